@@ -0,0 +1,484 @@
+// Package depwiretrace records the cross-package calls a Go program makes
+// while it runs and writes them in depwire's graph format, for the edges
+// static analysis can't see: calls through reflection, plugins, registries
+// and interfaces satisfied far from where they are used.
+//
+// The tracer samples the stacks of all goroutines at an interval. Each pair
+// of workspace functions on a stack, from different packages and with only
+// non-workspace frames (the standard library, third-party code) between
+// them, is a call edge; the outermost frame between them is recorded as
+// what the call went through, such as reflect.Value.Call. Record adds the
+// current stack at once, for paths too short-lived to be sampled.
+//
+// Start it in main, and run the program the way it runs in production or
+// under integration tests:
+//
+//	func main() {
+//		defer depwiretrace.FromEnv()()
+//		...
+//	}
+//
+// With DEPWIRE_TRACE=trace.json set the edges are written to trace.json,
+// every few seconds and when the returned function runs; without it nothing
+// is started. Then merge them into the project's graph:
+//
+//	depwire trace import trace.json
+//
+// File names in the trace are import paths (example.com/app/store/db.go),
+// since the binary doesn't know where its source tree is checked out;
+// depwire maps them onto the project.
+package depwiretrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options configure a Tracer. The zero value traces the main module.
+type Options struct {
+	// Output is the file the trace is written to (default depwire-trace.json).
+	Output string
+	// Packages are the import path prefixes of workspace code; calls between
+	// other packages aren't recorded. Defaults to the main module's path.
+	Packages []string
+	// Interval between stack samples (default 10ms).
+	Interval time.Duration
+	// FlushInterval between writes of the trace so far (default 5s), so a
+	// program ending in os.Exit or a signal loses at most that much.
+	FlushInterval time.Duration
+}
+
+// A Tracer samples goroutine stacks until Stop.
+type Tracer struct {
+	opts     Options
+	mainPath string
+	module   string
+	started  time.Time
+
+	mu      sync.Mutex
+	frames  map[uintptr][]frame
+	edges   map[edgeKey]*edge
+	nodes   map[string]node
+	samples int
+	records []runtime.StackRecord
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+	err  error
+}
+
+type frame struct {
+	pkg       string
+	function  string
+	node      string
+	file      string
+	line      int
+	workspace bool
+}
+
+type edgeKey struct{ source, target string }
+
+type edge struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Kind     string `json:"kind"`
+	FilePath string `json:"filePath"`
+	Line     int    `json:"line"`
+	// Samples the edge was seen in
+	Count int `json:"count"`
+	// The outermost non-workspace function between caller and callee
+	Via string `json:"via,omitempty"`
+}
+
+type node struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	FilePath  string `json:"filePath"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Exported  bool   `json:"exported"`
+	Scope     string `json:"scope,omitempty"`
+}
+
+// depwire's ProjectGraph, with where the trace came from in the metadata
+type graphFile struct {
+	ProjectRoot string   `json:"projectRoot"`
+	Files       []string `json:"files"`
+	Nodes       []node   `json:"nodes"`
+	Edges       []*edge  `json:"edges"`
+	Metadata    metadata `json:"metadata"`
+}
+
+type metadata struct {
+	ParsedAt  string    `json:"parsedAt"`
+	FileCount int       `json:"fileCount"`
+	NodeCount int       `json:"nodeCount"`
+	EdgeCount int       `json:"edgeCount"`
+	Trace     traceInfo `json:"trace"`
+}
+
+type traceInfo struct {
+	Module    string   `json:"module"`
+	Main      string   `json:"main"`
+	Packages  []string `json:"packages"`
+	GoVersion string   `json:"goVersion"`
+	StartedAt string   `json:"startedAt"`
+	Seconds   int      `json:"seconds"`
+	Samples   int      `json:"samples"`
+}
+
+// Start begins sampling. The trace is written every FlushInterval and by Stop.
+func Start(opts Options) (*Tracer, error) {
+	if opts.Output == "" {
+		opts.Output = "depwire-trace.json"
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Millisecond
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	t := &Tracer{
+		opts:    opts,
+		started: time.Now(),
+		frames:  make(map[uintptr][]frame),
+		edges:   make(map[edgeKey]*edge),
+		nodes:   make(map[string]node),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		t.mainPath = info.Path
+		t.module = info.Main.Path
+	}
+	if len(t.opts.Packages) == 0 {
+		if t.module == "" {
+			return nil, fmt.Errorf("depwiretrace: no module information in the binary; set Options.Packages")
+		}
+		t.opts.Packages = []string{t.module}
+	}
+	go t.run()
+	return t, nil
+}
+
+// FromEnv starts a tracer writing to the file in DEPWIRE_TRACE, tracing the
+// comma-separated import path prefixes in DEPWIRE_TRACE_PACKAGES (default
+// the main module). It returns the function that stops it, which does
+// nothing when DEPWIRE_TRACE isn't set or the tracer couldn't start.
+func FromEnv() func() {
+	output := os.Getenv("DEPWIRE_TRACE")
+	if output == "" {
+		return func() {}
+	}
+	var packages []string
+	for _, p := range strings.Split(os.Getenv("DEPWIRE_TRACE_PACKAGES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			packages = append(packages, p)
+		}
+	}
+	t, err := Start(Options{Output: output, Packages: packages})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return func() {}
+	}
+	return func() {
+		if err := t.Stop(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// Stop ends sampling and writes the trace.
+func (t *Tracer) Stop() error {
+	t.once.Do(func() {
+		close(t.stop)
+		<-t.done
+		t.err = t.Flush()
+	})
+	return t.err
+}
+
+// Record adds the calling goroutine's stack to the trace now.
+func (t *Tracer) Record() {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.add(pcs[:n])
+}
+
+// Flush writes the trace so far to the output file.
+func (t *Tracer) Flush() error {
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.graph(), "", "  ")
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	// Through a temporary file, so a reader never sees half a trace
+	tmp := t.opts.Output + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("depwiretrace: %w", err)
+	}
+	if err := os.Rename(tmp, t.opts.Output); err != nil {
+		return fmt.Errorf("depwiretrace: %w", err)
+	}
+	return nil
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+	sample := time.NewTicker(t.opts.Interval)
+	defer sample.Stop()
+	flush := time.NewTicker(t.opts.FlushInterval)
+	defer flush.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-sample.C:
+			t.sample()
+		case <-flush.C:
+			if err := t.Flush(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	}
+}
+
+func (t *Tracer) sample() {
+	n, ok := runtime.GoroutineProfile(t.records)
+	if !ok {
+		// More goroutines than records; grow and catch them next time
+		t.records = make([]runtime.StackRecord, n+n/4+16)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples++
+	for i := range t.records[:n] {
+		t.add(t.records[i].Stack())
+	}
+}
+
+// add records the edges of one stack, innermost frame first
+func (t *Tracer) add(stack []uintptr) {
+	var caller *frame
+	via := ""
+	for i := len(stack) - 1; i >= 0; i-- {
+		frames := t.resolve(stack[i])
+		// Inlined frames come innermost first too
+		for j := len(frames) - 1; j >= 0; j-- {
+			f := &frames[j]
+			if !f.workspace {
+				if caller != nil && via == "" {
+					via = f.function
+				}
+				continue
+			}
+			if caller != nil && caller.pkg != f.pkg {
+				key := edgeKey{caller.node, f.node}
+				e := t.edges[key]
+				if e == nil {
+					e = &edge{Source: caller.node, Target: f.node, Kind: "calls", FilePath: caller.file, Line: caller.line, Via: via}
+					t.edges[key] = e
+				}
+				e.Count++
+			}
+			caller, via = f, ""
+		}
+	}
+}
+
+func (t *Tracer) resolve(pc uintptr) []frame {
+	if frames, ok := t.frames[pc]; ok {
+		return frames
+	}
+	var frames []frame
+	iter := runtime.CallersFrames([]uintptr{pc})
+	for {
+		fr, more := iter.Next()
+		if fr.Function != "" {
+			frames = append(frames, t.frame(fr))
+		}
+		if !more {
+			break
+		}
+	}
+	t.frames[pc] = frames
+	return frames
+}
+
+func (t *Tracer) frame(fr runtime.Frame) frame {
+	pkg, receiver, name := splitFuncName(fr.Function)
+	if pkg == "main" && t.mainPath != "" {
+		pkg = t.mainPath
+	}
+	f := frame{pkg: pkg, function: fr.Function, line: fr.Line}
+	if name == "" || !t.inWorkspace(pkg) {
+		return f
+	}
+	f.workspace = true
+	f.file = path.Join(pkg, filepath.Base(fr.File))
+	symbol := name
+	if receiver != "" {
+		symbol = receiver + "." + name
+	}
+	f.node = f.file + "::" + symbol
+	if _, ok := t.nodes[f.node]; !ok {
+		// Where the function's code starts; inlined frames only know the line they are at
+		start := fr.Line
+		if fr.Func != nil {
+			_, start = fr.Func.FileLine(fr.Entry)
+		}
+		n := node{ID: f.node, Name: name, Kind: "function", FilePath: f.file, StartLine: start, EndLine: start, Exported: isExported(name)}
+		if receiver != "" {
+			n.Kind, n.Scope = "method", receiver
+		}
+		t.nodes[f.node] = n
+	}
+	return f
+}
+
+func (t *Tracer) inWorkspace(pkg string) bool {
+	for _, prefix := range t.opts.Packages {
+		if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// graph is the trace in depwire's graph format
+func (t *Tracer) graph() graphFile {
+	edges := make([]*edge, 0, len(t.edges))
+	used := make(map[string]bool)
+	for _, e := range t.edges {
+		edges = append(edges, e)
+		used[e.Source], used[e.Target] = true, true
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+	nodes := make([]node, 0, len(used))
+	files := make(map[string]bool)
+	for id := range used {
+		nodes = append(nodes, t.nodes[id])
+		files[t.nodes[id].FilePath] = true
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	fileList := make([]string, 0, len(files))
+	for f := range files {
+		fileList = append(fileList, f)
+	}
+	sort.Strings(fileList)
+	now := time.Now()
+	return graphFile{
+		ProjectRoot: t.module,
+		Files:       fileList,
+		Nodes:       nodes,
+		Edges:       edges,
+		Metadata: metadata{
+			ParsedAt:  now.UTC().Format(time.RFC3339),
+			FileCount: len(fileList),
+			NodeCount: len(nodes),
+			EdgeCount: len(edges),
+			Trace: traceInfo{
+				Module:    t.module,
+				Main:      t.mainPath,
+				Packages:  t.opts.Packages,
+				GoVersion: runtime.Version(),
+				StartedAt: t.started.UTC().Format(time.RFC3339),
+				Seconds:   int(now.Sub(t.started).Seconds()),
+				Samples:   t.samples,
+			},
+		},
+	}
+}
+
+// splitFuncName splits a runtime function name into its package and
+// depwire's name for the declaration it belongs to: closures count as the
+// function they are in, so "example.com/app/store.(*DB).Get.func1" is
+// package example.com/app/store, receiver DB, name Get. Package-level
+// closures and compiler wrappers have no declaration ("" name).
+func splitFuncName(function string) (pkg, receiver, name string) {
+	// Type arguments can hold import paths of their own
+	function = stripTypeArgs(function)
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return "", "", ""
+	}
+	// The linker escapes dots in the last path element (gopkg.in/yaml%2ev3)
+	pkg = strings.ReplaceAll(function[:slash+1+dot], "%2e", ".")
+	var parts []string
+	for _, part := range strings.Split(function[slash+1+dot+1:], ".") {
+		// Method values (-fm) and range-over-func bodies (-range1) belong to their function
+		if i := strings.IndexByte(part, '-'); i > 0 {
+			part = part[:i]
+		}
+		if part == "" || isClosure(part) {
+			break
+		}
+		parts = append(parts, part)
+	}
+	switch {
+	case len(parts) == 0 || parts[0] == "glob":
+		return pkg, "", ""
+	case strings.HasPrefix(parts[0], "("):
+		if len(parts) < 2 {
+			return pkg, "", ""
+		}
+		return pkg, strings.Trim(parts[0], "(*)"), parts[1]
+	case len(parts) >= 2:
+		return pkg, parts[0], parts[1]
+	}
+	return pkg, "", parts[0]
+}
+
+// Closures are funcN, N under another closure, and the gowrapN and
+// deferwrapN wrappers of go and defer statements
+func isClosure(part string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if strings.HasPrefix(part, prefix) {
+			part = part[len(prefix):]
+			break
+		}
+	}
+	return part != "" && strings.Trim(part, "0123456789") == ""
+}
+
+// stripTypeArgs drops the [...] of generic functions and receivers
+func stripTypeArgs(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
@@ -0,0 +1,3 @@
+module github.com/depwire/depwire/go/depwiretrace
+
+go 1.21
@@ -0,0 +1,34 @@
+import chalk from 'chalk';
+import type { ApiSurfaceReport } from './index.js';
+
+export function formatApiSurface(report: ApiSurfaceReport, limit: number): string {
+  const lines: string[] = [];
+
+  lines.push('');
+  lines.push(chalk.bold('Depwire API Surface'));
+  lines.push('');
+  lines.push(`${report.summary.packages} packages, ${report.summary.exports} exports, ` +
+    `${report.summary.unreferenced} not used outside their package`);
+  lines.push('');
+
+  for (const pkg of report.packages) {
+    if (pkg.exports.length === 0) continue;
+    lines.push(chalk.bold(`${pkg.importPath ?? pkg.dir}`) + chalk.dim(`  ${pkg.exports.length} exports, ${pkg.unreferenced} unreferenced`));
+    for (const symbol of pkg.exports.slice(0, limit)) {
+      const refs = symbol.externalRefs === 0
+        ? chalk.dim('unreferenced')
+        : `${symbol.externalRefs} ref${symbol.externalRefs === 1 ? '' : 's'} from ${symbol.referencedBy.length} package${symbol.referencedBy.length === 1 ? '' : 's'}`;
+      const tests = symbol.testRefs > 0 ? chalk.dim(` +${symbol.testRefs} in tests`) : '';
+      const approx = symbol.approximate && symbol.externalRefs > 0 ? chalk.dim(' ~') : '';
+      lines.push(`  ${symbol.kind.padEnd(9)} ${symbol.name.padEnd(32)} ${refs}${approx}${tests}`);
+    }
+    if (pkg.exports.length > limit) {
+      lines.push(chalk.dim(`  … ${pkg.exports.length - limit} more (use --limit or --format json)`));
+    }
+    lines.push('');
+  }
+
+  lines.push(chalk.dim('~ method counts are matched by name (no type information)'));
+  lines.push('');
+  return lines.join('\n');
+}
@@ -0,0 +1,151 @@
+import { goDeclarations, type GoDeclarationKind } from '../golang/source.js';
+import { loadGoProject, type GoPackage } from '../golang/packages.js';
+import { importQualifiers, qualifiedReferences, methodCalls } from '../golang/references.js';
+
+/**
+ * Inventory of each Go package's exported identifiers and how much the rest
+ * of the project leans on them. An export nobody outside its package uses
+ * can change freely; one used from twenty packages is load-bearing.
+ */
+
+export interface ExportedSymbol {
+  name: string;
+  kind: GoDeclarationKind;
+  /** Receiver type, for methods */
+  receiver?: string;
+  file: string;
+  line: number;
+  /** References from non-test files of other packages */
+  externalRefs: number;
+  /** References from _test.go files of other packages (including external _test packages) */
+  testRefs: number;
+  /** Import paths (or directories) of the packages that reference it, non-test */
+  referencedBy: string[];
+  /** Method counts are matched by name in files that reference the receiver type */
+  approximate?: boolean;
+}
+
+export interface PackageApiSurface {
+  dir: string;
+  importPath: string | null;
+  name: string;
+  exports: ExportedSymbol[];
+  /** Exports with no external (non-test) references */
+  unreferenced: number;
+}
+
+export interface ApiSurfaceReport {
+  projectRoot: string;
+  generatedAt: string;
+  packages: PackageApiSurface[];
+  summary: { packages: number; exports: number; unreferenced: number };
+}
+
+export interface ApiSurfaceOptions {
+  /** Only report packages whose directory or import path starts with this prefix */
+  package?: string;
+}
+
+export async function analyzeApiSurface(projectRoot: string, options: ApiSurfaceOptions = {}): Promise<ApiSurfaceReport> {
+  const project = await loadGoProject(projectRoot, { tests: true });
+  const packages: PackageApiSurface[] = [];
+  const dirs = [...project.packages.keys()].sort();
+
+  for (const dir of dirs) {
+    const pkg = project.packages.get(dir)!;
+    if (pkg.name === 'main' || !pkg.name) continue;
+    const label = pkg.importPath ?? dir;
+    if (options.package && !dir.startsWith(options.package) && !label.startsWith(options.package)) continue;
+
+    const symbols: ExportedSymbol[] = [];
+    const byName = new Map<string, ExportedSymbol>();
+    const methodsByReceiver = new Map<string, ExportedSymbol[]>();
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      for (const decl of goDeclarations(file)) {
+        if (!decl.exported) continue;
+        if (decl.kind === 'method' && (!decl.receiver || !/^\p{Lu}/u.test(decl.receiver))) continue;
+        const symbol: ExportedSymbol = {
+          name: decl.receiver ? `${decl.receiver}.${decl.name}` : decl.name,
+          kind: decl.kind,
+          receiver: decl.receiver,
+          file: file.file,
+          line: decl.line,
+          externalRefs: 0,
+          testRefs: 0,
+          referencedBy: [],
+        };
+        symbols.push(symbol);
+        if (decl.kind === 'method') {
+          symbol.approximate = true;
+          if (!methodsByReceiver.has(decl.receiver!)) methodsByReceiver.set(decl.receiver!, []);
+          methodsByReceiver.get(decl.receiver!)!.push(symbol);
+        } else {
+          byName.set(decl.name, symbol);
+        }
+      }
+    }
+    if (pkg.importPath) {
+      for (const importer of project.packages.values()) {
+        countReferences(importer, pkg, byName, methodsByReceiver);
+      }
+    }
+
+    symbols.sort((a, b) => b.externalRefs - a.externalRefs || a.name.localeCompare(b.name));
+    packages.push({
+      dir,
+      importPath: pkg.importPath,
+      name: pkg.name,
+      exports: symbols,
+      unreferenced: symbols.filter(s => s.externalRefs === 0).length,
+    });
+  }
+
+  const exports = packages.reduce((n, p) => n + p.exports.length, 0);
+  const unreferenced = packages.reduce((n, p) => n + p.unreferenced, 0);
+  return {
+    projectRoot,
+    generatedAt: new Date().toISOString(),
+    packages,
+    summary: { packages: packages.length, exports, unreferenced },
+  };
+}
+
+function countReferences(
+  importer: GoPackage,
+  pkg: GoPackage,
+  byName: Map<string, ExportedSymbol>,
+  methodsByReceiver: Map<string, ExportedSymbol[]>
+): void {
+  const qualifiers = importQualifiers(importer, pkg.importPath!, pkg.name, true);
+  const label = importer.importPath ?? importer.dir;
+
+  for (const file of importer.files) {
+    // In-package tests see the package's identifiers unqualified — not an external use
+    if (importer.dir === pkg.dir && file.packageName === pkg.name) continue;
+    const test = file.isTest;
+    const referencedTypes = new Set<string>();
+    for (const ref of qualifiedReferences(file, qualifiers)) {
+      const symbol = byName.get(ref.name);
+      if (!symbol) continue;
+      record(symbol, label, test);
+      if (symbol.kind === 'type' || symbol.kind === 'interface') referencedTypes.add(ref.name);
+    }
+    if (referencedTypes.size === 0) continue;
+    for (const call of methodCalls(file, qualifiers)) {
+      for (const type of referencedTypes) {
+        const method = methodsByReceiver.get(type)?.find(m => m.name === `${type}.${call.name}`);
+        if (method) record(method, label, test);
+      }
+    }
+  }
+}
+
+function record(symbol: ExportedSymbol, importer: string, test: boolean): void {
+  if (test) {
+    symbol.testRefs++;
+    return;
+  }
+  symbol.externalRefs++;
+  if (!symbol.referencedBy.includes(importer)) symbol.referencedBy.push(importer);
+}
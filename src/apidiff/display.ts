@@ -0,0 +1,51 @@
+import chalk from 'chalk';
+import type { ApiDiffResult } from './index.js';
+
+export function formatApiDiff(result: ApiDiffResult, showCompatible: boolean): string {
+  const lines: string[] = [];
+
+  lines.push('');
+  lines.push(chalk.bold(`Depwire API Diff — ${result.module}`));
+  lines.push(chalk.dim(`  ${result.from} → ${result.to}`));
+  lines.push('');
+
+  const incompatible = result.changes.filter(c => !c.compatible);
+  lines.push(chalk.bold(`Incompatible changes (${incompatible.length})`));
+  if (incompatible.length === 0) lines.push(chalk.green('  ✓ none'));
+  let lastPackage = '';
+  for (const c of incompatible) {
+    if (c.package !== lastPackage) {
+      lines.push(`  ${chalk.cyan(c.package)}`);
+      lastPackage = c.package;
+    }
+    lines.push(`    ${chalk.red('✗')} ${c.message}`);
+    if (c.before !== undefined && c.after !== undefined) {
+      lines.push(chalk.dim(`        was: ${c.before}`));
+      lines.push(chalk.dim(`        now: ${c.after}`));
+    }
+    const packages = [...new Set(c.consumers.map(u => u.package))];
+    if (packages.length > 0) {
+      lines.push(chalk.yellow(`        breaks ${packages.length} consumer${packages.length === 1 ? '' : 's'}: `) +
+        c.consumers.slice(0, 5).map(u => `${u.file}:${u.line}`).join(', ') +
+        (c.consumers.length > 5 ? chalk.dim(` … ${c.consumers.length - 5} more`) : ''));
+    }
+  }
+  lines.push('');
+
+  const compatible = result.changes.filter(c => c.compatible);
+  lines.push(chalk.bold(`Compatible changes (${compatible.length})`));
+  if (showCompatible) {
+    for (const c of compatible) {
+      lines.push(`  ${chalk.green('+')} ${c.package}${c.name ? `.${c.name}` : ''}  ${chalk.dim(c.message)}`);
+    }
+  } else if (compatible.length > 0) {
+    lines.push(chalk.dim('  (use --all to list)'));
+  }
+  lines.push('');
+
+  if (result.summary.brokenConsumers > 0) {
+    lines.push(chalk.yellow(`${result.summary.brokenConsumers} package${result.summary.brokenConsumers === 1 ? '' : 's'} in this tree would break`));
+    lines.push('');
+  }
+  return lines.join('\n');
+}
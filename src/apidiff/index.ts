@@ -0,0 +1,220 @@
+import { loadGoProject, type GoProject } from '../golang/packages.js';
+import { extractGoApi, type GoApiItem, type GoApiItemKind, type GoPackageApi } from '../golang/api.js';
+import { importQualifiers, qualifiedReferences, memberSelectors } from '../golang/references.js';
+import { withWorktree } from '../temporal/git.js';
+import { checkCancelled } from '../utils/progress.js';
+
+/**
+ * apidiff-style comparison of a Go module's exported API between two
+ * versions, classified the way Go's compatibility rules see it, plus the
+ * packages in the current tree that use what changed.
+ *
+ * The comparison is syntactic: types are compared as written, so a change
+ * that is only an alias rename or an equivalent spelling is reported too.
+ */
+
+export type ApiChangeType = 'removed' | 'changed' | 'added';
+
+export interface ApiConsumer {
+  package: string;
+  file: string;
+  line: number;
+}
+
+export interface ApiChange {
+  package: string;
+  name: string;
+  kind: GoApiItemKind;
+  change: ApiChangeType;
+  compatible: boolean;
+  message: string;
+  before?: string;
+  after?: string;
+  /** Uses in the current tree that this change breaks (incompatible changes only) */
+  consumers: ApiConsumer[];
+}
+
+export interface ApiDiffResult {
+  module: string;
+  from: string;
+  to: string;
+  changes: ApiChange[];
+  summary: { incompatible: number; compatible: number; brokenConsumers: number };
+}
+
+export interface ApiDiffOptions {
+  /** Module to compare (default: the module at the project root, or the only module) */
+  module?: string;
+  signal?: AbortSignal;
+}
+
+async function loadVersion(projectRoot: string, ref: string | undefined): Promise<GoProject> {
+  return ref ? withWorktree(projectRoot, ref, (dir) => loadGoProject(dir)) : loadGoProject(projectRoot);
+}
+
+/** Compare the module's API at ref `from` with ref `to` (or the working tree when `to` is omitted) */
+export async function diffModuleApi(projectRoot: string, from: string, to?: string, options: ApiDiffOptions = {}): Promise<ApiDiffResult> {
+  const current = await loadGoProject(projectRoot, { tests: true });
+  const modulePath = options.module ?? pickModule(current);
+
+  checkCancelled(options.signal);
+  const before = extractGoApi(await loadVersion(projectRoot, from), modulePath);
+  checkCancelled(options.signal);
+  const after = extractGoApi(to ? await loadVersion(projectRoot, to) : current, modulePath);
+  if (before.size === 0 && after.size === 0) {
+    throw new Error(`No packages of module ${modulePath} found at ${from}${to ? ` or ${to}` : ''}`);
+  }
+
+  const changes = compareApis(before, after);
+  attachConsumers(current, changes);
+
+  const incompatible = changes.filter(c => !c.compatible);
+  const broken = new Set(incompatible.flatMap(c => c.consumers.map(u => u.package)));
+  return {
+    module: modulePath,
+    from,
+    to: to ?? 'working tree',
+    changes,
+    summary: { incompatible: incompatible.length, compatible: changes.length - incompatible.length, brokenConsumers: broken.size },
+  };
+}
+
+/** Every change from one extracted API to another, incompatible changes first (consumers left empty) */
+export function compareApis(before: Map<string, GoPackageApi>, after: Map<string, GoPackageApi>): ApiChange[] {
+  const changes: ApiChange[] = [];
+  for (const [path, oldPkg] of before) {
+    const newPkg = after.get(path);
+    if (!newPkg) {
+      changes.push({ package: path, name: '', kind: 'type', change: 'removed', compatible: false, message: 'package removed', consumers: [] });
+      continue;
+    }
+    changes.push(...comparePackage(oldPkg, newPkg));
+  }
+  for (const path of after.keys()) {
+    if (!before.has(path)) {
+      changes.push({ package: path, name: '', kind: 'type', change: 'added', compatible: true, message: 'package added', consumers: [] });
+    }
+  }
+  return changes.sort((a, b) =>
+    Number(a.compatible) - Number(b.compatible) || a.package.localeCompare(b.package) || a.name.localeCompare(b.name)
+  );
+}
+
+function pickModule(project: GoProject): string {
+  const modules = project.modules.modules;
+  if (modules.length === 0) {
+    throw new Error('No go.mod found — apidiff compares Go modules');
+  }
+  const root = modules.find(m => m.dir === '.');
+  if (root) return root.path;
+  if (modules.length === 1) return modules[0].path;
+  throw new Error(`Several modules in this project — pick one with --module (${modules.map(m => m.path).join(', ')})`);
+}
+
+function comparePackage(oldPkg: GoPackageApi, newPkg: GoPackageApi): ApiChange[] {
+  const changes: ApiChange[] = [];
+  const path = oldPkg.importPath;
+  // Members of a removed or re-kinded type are covered by the type's own change
+  const skipMembers = new Set<string>();
+
+  for (const [name, oldItem] of oldPkg.items) {
+    if (oldItem.parent) continue;
+    const newItem = newPkg.items.get(name);
+    if (!newItem) {
+      changes.push(change(path, oldItem, 'removed', false, `${describe(oldItem)} removed`));
+      skipMembers.add(name);
+    } else if (oldItem.kind !== newItem.kind || oldItem.signature !== newItem.signature) {
+      changes.push(change(path, oldItem, 'changed', false, `${describe(oldItem)} changed`, newItem));
+      const sameShape = oldItem.kind === newItem.kind && newItem.signature.endsWith(oldItem.kind === 'interface' ? 'interface' : 'struct');
+      if (!sameShape) skipMembers.add(name);
+    }
+  }
+
+  for (const [name, oldItem] of oldPkg.items) {
+    if (!oldItem.parent || skipMembers.has(oldItem.parent)) continue;
+    const newItem = newPkg.items.get(name);
+    if (!newItem) {
+      const why = oldItem.kind === 'interface-method' ? '— callers of the interface break' : '';
+      changes.push(change(path, oldItem, 'removed', false, `${describe(oldItem)} removed ${why}`.trim()));
+    } else if (oldItem.signature !== newItem.signature) {
+      changes.push(change(path, oldItem, 'changed', false, `${describe(oldItem)} changed`, newItem));
+    }
+  }
+
+  for (const [name, newItem] of newPkg.items) {
+    if (oldPkg.items.has(name)) continue;
+    if (newItem.parent && !oldPkg.items.has(newItem.parent)) continue;
+    // A new interface method breaks every implementation outside the package
+    const compatible = newItem.kind !== 'interface-method';
+    const message = compatible ? `${describe(newItem)} added` : `${describe(newItem)} added — existing implementations no longer satisfy ${newItem.parent}`;
+    changes.push({ ...change(path, newItem, 'added', compatible, message), after: newItem.signature });
+  }
+
+  return changes;
+}
+
+function describe(item: GoApiItem): string {
+  const kind = item.kind === 'interface-method' ? 'interface method' : item.kind;
+  return `${kind} ${item.name}`;
+}
+
+function change(path: string, item: GoApiItem, type: ApiChangeType, compatible: boolean, message: string, newItem?: GoApiItem): ApiChange {
+  return {
+    package: path,
+    name: item.name,
+    kind: item.kind,
+    change: type,
+    compatible,
+    message,
+    before: type === 'added' ? undefined : item.signature,
+    after: newItem ? newItem.signature : undefined,
+    consumers: [],
+  };
+}
+
+/**
+ * Find uses of each incompatibly changed identifier in other packages of the
+ * current tree. Members (methods, fields) count in files that also reference
+ * their type, matched by name.
+ */
+function attachConsumers(project: GoProject, changes: ApiChange[]): void {
+  const byPackage = new Map<string, ApiChange[]>();
+  for (const c of changes) {
+    if (c.compatible) continue;
+    if (!byPackage.has(c.package)) byPackage.set(c.package, []);
+    byPackage.get(c.package)!.push(c);
+  }
+
+  for (const [path, pkgChanges] of byPackage) {
+    const dir = project.modules.dirForImport(path);
+    const target = dir ? project.packages.get(dir) : undefined;
+    const packageName = target?.name ?? path.split('/').pop()!;
+
+    for (const importer of project.packages.values()) {
+      if (importer.importPath === path) continue;
+      const qualifiers = importQualifiers(importer, path, packageName, true);
+      if (qualifiers.size === 0) continue;
+      const label = importer.importPath ?? importer.dir;
+
+      for (const file of importer.files) {
+        const refs = qualifiedReferences(file, qualifiers);
+        if (refs.length === 0) continue;
+        const referenced = new Set(refs.map(r => r.name));
+        const members = memberSelectors(file, qualifiers);
+
+        for (const c of pkgChanges) {
+          if (c.name === '') {
+            c.consumers.push({ package: label, file: file.file, line: refs[0].line });
+            continue;
+          }
+          const [head, member] = c.name.split('.');
+          if (!member) {
+            for (const ref of refs) if (ref.name === head) c.consumers.push({ package: label, file: file.file, line: ref.line });
+          } else if (referenced.has(head)) {
+            for (const use of members) if (use.name === member) c.consumers.push({ package: label, file: file.file, line: use.line });
+          }
+        }
+      }
+    }
+  }
+}
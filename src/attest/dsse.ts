@@ -0,0 +1,17 @@
+/** DSSE (Dead Simple Signing Envelope) — https://github.com/secure-systems-lab/dsse */
+
+export interface DsseEnvelope {
+  payloadType: string;
+  /** base64 */
+  payload: string;
+  signatures: Array<{ keyid: string; sig: string }>;
+}
+
+/** Pre-authentication encoding — what actually gets signed */
+export function pae(payloadType: string, payload: Buffer): Buffer {
+  const type = Buffer.from(payloadType, 'utf-8');
+  return Buffer.concat([
+    Buffer.from(`DSSEv1 ${type.length} `, 'utf-8'), type,
+    Buffer.from(` ${payload.length} `, 'utf-8'), payload,
+  ]);
+}
@@ -0,0 +1,248 @@
+import { createHash, createPrivateKey, createPublicKey, sign, verify, X509Certificate, type KeyObject } from 'crypto';
+import { readFileSync, writeFileSync } from 'fs';
+import { execFileSync } from 'child_process';
+import { pae, type DsseEnvelope } from './dsse.js';
+import { keylessSign, parseTrustedRoot, verifyBundle, checkCertificateIdentity, type SigstoreBundle } from './sigstore.js';
+
+export { pae, type DsseEnvelope };
+
+/**
+ * in-toto attestations of depwire reports. The report is the predicate of an
+ * in-toto Statement whose subjects are the report bytes and the git commit it
+ * was computed from; the Statement is signed as a DSSE envelope, either with
+ * a local key or keyless through Sigstore (Fulcio certificate + Rekor entry),
+ * so consumers can check which commit — and, keyless, which CI workflow —
+ * produced the analysis.
+ */
+
+export const STATEMENT_TYPE = 'https://in-toto.io/Statement/v1';
+export const PREDICATE_TYPE = 'https://depwire.dev/attestation/analysis/v1';
+export const PAYLOAD_TYPE = 'application/vnd.in-toto+json';
+
+export interface ResourceDescriptor {
+  name: string;
+  digest: Record<string, string>;
+}
+
+export interface AnalysisPredicate {
+  depwire: { version: string; command: string };
+  source: { repository: string | null; commit: string | null; dirty: boolean };
+  ci: { provider: string; runUrl: string | null; workflow: string | null; ref: string | null } | null;
+  analyzedAt: string;
+  report: unknown;
+}
+
+export interface Statement {
+  _type: string;
+  subject: ResourceDescriptor[];
+  predicateType: string;
+  predicate: AnalysisPredicate;
+}
+
+export interface AttestOptions {
+  /** depwire command that produced the report ("security", "lint") */
+  command: string;
+  version: string;
+  /** Where to write the attestation */
+  output: string;
+  /** PEM private key (Ed25519, ECDSA or RSA); DEPWIRE_ATTEST_KEY is used when unset */
+  key?: string;
+  /** Sign with a Sigstore certificate for the CI identity instead of a key */
+  keyless?: boolean;
+  fulcioUrl?: string;
+  rekorUrl?: string;
+  signal?: AbortSignal;
+}
+
+export interface AttestationVerifyOptions {
+  publicKey?: string;
+  trustedRoot?: string;
+  /** For keyless bundles: the certificate SAN to require, exactly or as a regular expression */
+  certificateIdentity?: string;
+  certificateIdentityRegexp?: string;
+  /** For keyless bundles: the OIDC issuer to require */
+  certificateOidcIssuer?: string;
+  commit?: string;
+}
+
+export interface VerifiedAttestation {
+  statement: Statement;
+  /** Keyid of the key, or the certificate identity (SAN) for keyless bundles */
+  signer: string;
+  keyless: boolean;
+  /**
+   * What the signature was checked against: the given public key, or the
+   * Sigstore trust root (certificate chain and Rekor entry). A keyless bundle
+   * checked against neither is unverified: its certificate signed it, but
+   * nothing says who issued the certificate.
+   */
+  verification: 'key' | 'sigstore' | 'unverified';
+  /** Rekor log index for keyless bundles */
+  logIndex: number | null;
+}
+
+function git(projectRoot: string, args: string[]): string | null {
+  try {
+    return execFileSync('git', args, { cwd: projectRoot, encoding: 'utf-8', stdio: ['ignore', 'pipe', 'ignore'] }).trim();
+  } catch {
+    return null;
+  }
+}
+
+function ciContext(): AnalysisPredicate['ci'] {
+  const env = process.env;
+  if (env.GITHUB_ACTIONS === 'true') {
+    return {
+      provider: 'github-actions',
+      runUrl: env.GITHUB_SERVER_URL && env.GITHUB_REPOSITORY && env.GITHUB_RUN_ID
+        ? `${env.GITHUB_SERVER_URL}/${env.GITHUB_REPOSITORY}/actions/runs/${env.GITHUB_RUN_ID}`
+        : null,
+      workflow: env.GITHUB_WORKFLOW_REF ?? env.GITHUB_WORKFLOW ?? null,
+      ref: env.GITHUB_REF ?? null,
+    };
+  }
+  if (env.GITLAB_CI === 'true') {
+    return { provider: 'gitlab-ci', runUrl: env.CI_JOB_URL ?? null, workflow: env.CI_CONFIG_PATH ?? null, ref: env.CI_COMMIT_REF_NAME ?? null };
+  }
+  if (env.CI) return { provider: 'ci', runUrl: null, workflow: null, ref: null };
+  return null;
+}
+
+/** An in-toto Statement with the report as predicate */
+export function createStatement(projectRoot: string, report: unknown, options: Pick<AttestOptions, 'command' | 'version'>): Statement {
+  const reportBytes = Buffer.from(JSON.stringify(report), 'utf-8');
+  const commit = git(projectRoot, ['rev-parse', 'HEAD']);
+  const repository = git(projectRoot, ['config', '--get', 'remote.origin.url']);
+  const status = git(projectRoot, ['status', '--porcelain']);
+
+  const subject: ResourceDescriptor[] = [
+    { name: `depwire-${options.command}.json`, digest: { sha256: createHash('sha256').update(reportBytes).digest('hex') } },
+  ];
+  if (commit) subject.push({ name: repository ?? projectRoot, digest: { gitCommit: commit } });
+
+  return {
+    _type: STATEMENT_TYPE,
+    subject,
+    predicateType: PREDICATE_TYPE,
+    predicate: {
+      depwire: { version: options.version, command: options.command },
+      source: { repository, commit, dirty: Boolean(status) },
+      ci: ciContext(),
+      analyzedAt: new Date().toISOString(),
+      report,
+    },
+  };
+}
+
+/** Hex SHA-256 of the DER public key — a stable keyid */
+export function keyId(key: KeyObject): string {
+  return createHash('sha256').update(createPublicKey(key).export({ type: 'spki', format: 'der' })).digest('hex');
+}
+
+// Ed25519 signs the message itself; EC and RSA keys sign its SHA-256
+export function algorithmFor(key: KeyObject): string | null {
+  return key.asymmetricKeyType === 'ed25519' || key.asymmetricKeyType === 'ed448' ? null : 'sha256';
+}
+
+export function signEnvelope(statement: Statement, privateKeyPem: string): DsseEnvelope {
+  const key = createPrivateKey(privateKeyPem);
+  const payload = Buffer.from(JSON.stringify(statement), 'utf-8');
+  const sig = sign(algorithmFor(key), pae(PAYLOAD_TYPE, payload), key);
+  return {
+    payloadType: PAYLOAD_TYPE,
+    payload: payload.toString('base64'),
+    signatures: [{ keyid: keyId(key), sig: sig.toString('base64') }],
+  };
+}
+
+/**
+ * Sign a report and write the attestation: a DSSE envelope for key signing,
+ * a Sigstore bundle for keyless.
+ */
+export async function attestReport(projectRoot: string, report: unknown, options: AttestOptions): Promise<string> {
+  const statement = createStatement(projectRoot, report, options);
+  if (options.keyless) {
+    const bundle = await keylessSign(PAYLOAD_TYPE, Buffer.from(JSON.stringify(statement), 'utf-8'), {
+      fulcioUrl: options.fulcioUrl,
+      rekorUrl: options.rekorUrl,
+      signal: options.signal,
+    });
+    writeFileSync(options.output, JSON.stringify(bundle, null, 2) + '\n');
+    return options.output;
+  }
+
+  const keyPath = options.key ?? process.env.DEPWIRE_ATTEST_KEY;
+  if (!keyPath) {
+    throw new Error('--attest needs a signing key (--attest-key <pem> or DEPWIRE_ATTEST_KEY) or --keyless');
+  }
+  const pem = keyPath.includes('-----BEGIN') ? keyPath : readFileSync(keyPath, 'utf-8');
+  writeFileSync(options.output, JSON.stringify(signEnvelope(statement, pem), null, 2) + '\n');
+  return options.output;
+}
+
+/**
+ * Check an attestation's signature and optionally that it was made for a
+ * commit. Key-signed envelopes are checked against the given public key.
+ * Keyless bundles are checked against the given key too, or, with a Sigstore
+ * trusted root, against the embedded certificate once it chains to Fulcio,
+ * its Rekor entry verifies and it names the expected identity and OIDC
+ * issuer; with neither they come back 'unverified'.
+ */
+export function verifyAttestation(file: string, options: AttestationVerifyOptions = {}): VerifiedAttestation {
+  const doc = JSON.parse(readFileSync(file, 'utf-8')) as DsseEnvelope | SigstoreBundle;
+  const bundle = 'dsseEnvelope' in doc ? doc : null;
+  const envelope: DsseEnvelope = bundle ? bundle.dsseEnvelope : doc as DsseEnvelope;
+  if (envelope.payloadType !== PAYLOAD_TYPE) throw new Error(`Unexpected payload type ${envelope.payloadType}`);
+
+  const payload = Buffer.from(envelope.payload, 'base64');
+  const message = pae(envelope.payloadType, payload);
+  let signer: string;
+  let publicKey: KeyObject;
+  let verification: VerifiedAttestation['verification'];
+  if (options.publicKey) {
+    publicKey = createPublicKey(options.publicKey.includes('-----BEGIN') ? options.publicKey : readFileSync(options.publicKey, 'utf-8'));
+    signer = keyId(publicKey);
+    verification = 'key';
+  } else if (bundle) {
+    const cert = new X509Certificate(Buffer.from(bundle.verificationMaterial.certificate.rawBytes, 'base64'));
+    publicKey = cert.publicKey;
+    signer = cert.subjectAltName ?? cert.subject;
+    verification = 'unverified';
+    if (options.trustedRoot) {
+      // Anyone can get a Fulcio certificate for their own account; the chain alone says nothing about who signed
+      if (!(options.certificateIdentity || options.certificateIdentityRegexp) || !options.certificateOidcIssuer) {
+        throw new Error('Keyless verification needs --certificate-identity (or --certificate-identity-regexp) and --certificate-oidc-issuer');
+      }
+      verifyBundle(bundle, parseTrustedRoot(readFileSync(options.trustedRoot, 'utf-8')));
+      checkCertificateIdentity(cert, {
+        identity: options.certificateIdentity,
+        identityRegexp: options.certificateIdentityRegexp,
+        oidcIssuer: options.certificateOidcIssuer,
+      });
+      verification = 'sigstore';
+    }
+  } else {
+    throw new Error('A public key (--key) is needed to verify a key-signed attestation');
+  }
+
+  const valid = envelope.signatures.some(s => verify(algorithmFor(publicKey), message, publicKey, Buffer.from(s.sig, 'base64')));
+  if (!valid) throw new Error('Signature does not verify');
+
+  const statement = JSON.parse(payload.toString('utf-8')) as Statement;
+  if (statement._type !== STATEMENT_TYPE || statement.predicateType !== PREDICATE_TYPE) {
+    throw new Error(`Not a depwire analysis attestation (${statement.predicateType})`);
+  }
+  // The report subject must match the predicate it carries
+  const reportDigest = createHash('sha256').update(Buffer.from(JSON.stringify(statement.predicate.report), 'utf-8')).digest('hex');
+  if (!statement.subject.some(s => s.digest.sha256 === reportDigest)) {
+    throw new Error('Report digest does not match the attested subject');
+  }
+  if (options.commit) {
+    const commit = statement.predicate.source.commit;
+    if (!commit || !commit.startsWith(options.commit)) {
+      throw new Error(`Attestation is for commit ${commit ?? '(none)'}, not ${options.commit}`);
+    }
+  }
+  const entry = bundle?.verificationMaterial.tlogEntries[0];
+  return { statement, signer, keyless: Boolean(bundle), verification, logIndex: entry ? Number(entry.logIndex) : null };
+}
@@ -0,0 +1,303 @@
+import { createHash, createPublicKey, generateKeyPairSync, sign, verify, X509Certificate } from 'crypto';
+import { pae, type DsseEnvelope } from './dsse.js';
+
+/**
+ * Keyless signing through the public Sigstore instance: an ephemeral P-256
+ * key is certified by Fulcio for the CI's OIDC identity, the DSSE envelope is
+ * signed with it and recorded in the Rekor transparency log. The result is a
+ * Sigstore bundle that cosign and `gh attestation verify` understand.
+ *
+ * Verifying a bundle needs the instance's trust material: the Fulcio
+ * certificate chain and the Rekor public key, as in the trusted root that
+ * `gh attestation trusted-root` prints. Without it, a bundle proves only
+ * that its own certificate signed it, which anyone can arrange.
+ */
+
+export const FULCIO_URL = 'https://fulcio.sigstore.dev';
+export const REKOR_URL = 'https://rekor.sigstore.dev';
+
+export interface SigstoreBundle {
+  mediaType: string;
+  verificationMaterial: {
+    /** base64 DER of the leaf certificate */
+    certificate: { rawBytes: string };
+    tlogEntries: Array<{
+      logIndex: string;
+      logId: { keyId: string };
+      kindVersion: { kind: string; version: string };
+      integratedTime: string;
+      inclusionPromise: { signedEntryTimestamp: string };
+      canonicalizedBody: string;
+    }>;
+  };
+  dsseEnvelope: DsseEnvelope;
+}
+
+export interface KeylessOptions {
+  /** OIDC token; defaults to SIGSTORE_ID_TOKEN or the GitHub Actions token */
+  identityToken?: string;
+  fulcioUrl?: string;
+  rekorUrl?: string;
+  signal?: AbortSignal;
+}
+
+/** The CI's OIDC identity token, with audience "sigstore" */
+export async function identityToken(signal?: AbortSignal): Promise<string> {
+  if (process.env.SIGSTORE_ID_TOKEN) return process.env.SIGSTORE_ID_TOKEN;
+  const url = process.env.ACTIONS_ID_TOKEN_REQUEST_URL;
+  const token = process.env.ACTIONS_ID_TOKEN_REQUEST_TOKEN;
+  if (url && token) {
+    const response = await fetch(`${url}&audience=sigstore`, { headers: { authorization: `Bearer ${token}` }, signal });
+    if (!response.ok) throw new Error(`GitHub OIDC token request failed (${response.status})`);
+    return ((await response.json()) as { value: string }).value;
+  }
+  throw new Error('No OIDC token for keyless signing — set SIGSTORE_ID_TOKEN, or run in GitHub Actions with `permissions: id-token: write`');
+}
+
+// The identity a Fulcio proof of possession signs is the token's subject (or email)
+function tokenSubject(token: string): string {
+  const claims = JSON.parse(Buffer.from(token.split('.')[1] ?? '', 'base64url').toString('utf-8')) as { sub?: string; email?: string };
+  const subject = claims.email ?? claims.sub;
+  if (!subject) throw new Error('OIDC token has no subject');
+  return subject;
+}
+
+async function postJson<T>(url: string, body: unknown, signal?: AbortSignal): Promise<T> {
+  const response = await fetch(url, {
+    method: 'POST',
+    headers: { 'content-type': 'application/json', accept: 'application/json' },
+    body: JSON.stringify(body),
+    signal,
+  });
+  if (!response.ok) {
+    throw new Error(`${new URL(url).host} returned ${response.status}: ${(await response.text()).slice(0, 200)}`);
+  }
+  return await response.json() as T;
+}
+
+export async function keylessSign(payloadType: string, payload: Buffer, options: KeylessOptions = {}): Promise<SigstoreBundle> {
+  const token = options.identityToken ?? await identityToken(options.signal);
+  const { privateKey, publicKey } = generateKeyPairSync('ec', { namedCurve: 'P-256' });
+
+  const fulcio = await postJson<{
+    signedCertificateEmbeddedSct?: { chain: { certificates: string[] } };
+    signedCertificateDetachedSct?: { chain: { certificates: string[] } };
+  }>(`${options.fulcioUrl ?? FULCIO_URL}/api/v2/signingCert`, {
+    credentials: { oidcIdentityToken: token },
+    publicKeyRequest: {
+      publicKey: { algorithm: 'ECDSA', content: publicKey.export({ type: 'spki', format: 'pem' }).toString() },
+      proofOfPossession: sign('sha256', Buffer.from(tokenSubject(token), 'utf-8'), privateKey).toString('base64'),
+    },
+  }, options.signal);
+  const chain = (fulcio.signedCertificateEmbeddedSct ?? fulcio.signedCertificateDetachedSct)?.chain.certificates ?? [];
+  if (chain.length === 0) throw new Error('Fulcio returned no certificate');
+  const leaf = new X509Certificate(chain[0]);
+
+  const envelope: DsseEnvelope = {
+    payloadType,
+    payload: payload.toString('base64'),
+    signatures: [{ keyid: '', sig: sign('sha256', pae(payloadType, payload), privateKey).toString('base64') }],
+  };
+
+  const entries = await postJson<Record<string, {
+    body: string;
+    integratedTime: number;
+    logID: string;
+    logIndex: number;
+    verification?: { signedEntryTimestamp?: string };
+  }>>(`${options.rekorUrl ?? REKOR_URL}/api/v1/log/entries`, {
+    apiVersion: '0.0.1',
+    kind: 'dsse',
+    spec: {
+      proposedContent: {
+        envelope: JSON.stringify(envelope),
+        verifiers: [Buffer.from(chain[0], 'utf-8').toString('base64')],
+      },
+    },
+  }, options.signal);
+  const entry = Object.values(entries)[0];
+  if (!entry) throw new Error('Rekor returned no log entry');
+
+  return {
+    mediaType: 'application/vnd.dev.sigstore.bundle.v0.3+json',
+    verificationMaterial: {
+      certificate: { rawBytes: leaf.raw.toString('base64') },
+      tlogEntries: [{
+        logIndex: String(entry.logIndex),
+        logId: { keyId: Buffer.from(entry.logID, 'hex').toString('base64') },
+        kindVersion: { kind: 'dsse', version: '0.0.1' },
+        integratedTime: String(entry.integratedTime),
+        inclusionPromise: { signedEntryTimestamp: entry.verification?.signedEntryTimestamp ?? '' },
+        canonicalizedBody: entry.body,
+      }],
+    },
+    dsseEnvelope: envelope,
+  };
+}
+
+
+/** The parts of a Sigstore trusted root (trusted_root.json) read here */
+export interface TrustedRoot {
+  tlogs: Array<{
+    baseUrl?: string;
+    /** base64 DER SubjectPublicKeyInfo */
+    publicKey: { rawBytes: string; validFor?: { start?: string; end?: string } };
+    logId: { keyId: string };
+  }>;
+  certificateAuthorities: Array<{
+    uri?: string;
+    /** base64 DER, the intermediate first and the root last */
+    certChain: { certificates: Array<{ rawBytes: string }> };
+    validFor?: { start?: string; end?: string };
+  }>;
+}
+
+/**
+ * Trusted roots from a file: one JSON document, or one per line as
+ * `gh attestation trusted-root` writes them. Their tlogs and CAs are merged.
+ */
+export function parseTrustedRoot(content: string): TrustedRoot {
+  let docs: Partial<TrustedRoot>[];
+  try {
+    docs = [JSON.parse(content)];
+  } catch {
+    docs = content.split('\n').filter(line => line.trim()).map(line => JSON.parse(line));
+  }
+  const root: TrustedRoot = { tlogs: docs.flatMap(d => d.tlogs ?? []), certificateAuthorities: docs.flatMap(d => d.certificateAuthorities ?? []) };
+  if (root.tlogs.length === 0 || root.certificateAuthorities.length === 0) {
+    throw new Error('The trusted root has no transparency logs or no certificate authorities');
+  }
+  return root;
+}
+
+const within = (time: number, range?: { start?: string; end?: string }) =>
+  (!range?.start || Date.parse(range.start) <= time) && (!range?.end || time <= Date.parse(range.end));
+
+/**
+ * Check that a bundle's certificate chains to a trusted Fulcio CA and was
+ * valid when Rekor logged the entry, that the entry's signed entry
+ * timestamp verifies with a trusted Rekor key, and that the logged entry
+ * is this envelope. Returns the log's integrated time (Unix seconds).
+ */
+export function verifyBundle(bundle: SigstoreBundle, root: TrustedRoot): number {
+  const entry = bundle.verificationMaterial.tlogEntries[0];
+  if (!entry) throw new Error('The bundle has no transparency log entry');
+  const integratedTime = Number(entry.integratedTime);
+
+  // The signed entry timestamp covers the entry body, its time and its place in the log
+  const logId = Buffer.from(entry.logId.keyId, 'base64').toString('hex');
+  const tlog = root.tlogs.find(t => Buffer.from(t.logId.keyId, 'base64').toString('hex') === logId);
+  if (!tlog) throw new Error(`The bundle was logged by an untrusted transparency log (${logId})`);
+  const rekorKey = createPublicKey({ key: Buffer.from(tlog.publicKey.rawBytes, 'base64'), format: 'der', type: 'spki' });
+  const promise = JSON.stringify({ body: entry.canonicalizedBody, integratedTime, logID: logId, logIndex: Number(entry.logIndex) });
+  const set = Buffer.from(entry.inclusionPromise?.signedEntryTimestamp ?? '', 'base64');
+  if (set.length === 0 || !verify('sha256', Buffer.from(promise, 'utf-8'), rekorKey, set)) {
+    throw new Error('The transparency log entry\'s signed entry timestamp does not verify');
+  }
+  if (!within(integratedTime * 1000, tlog.publicKey.validFor)) throw new Error('The transparency log key was not valid when the entry was logged');
+
+  // The logged entry records this payload and signature
+  const body = JSON.parse(Buffer.from(entry.canonicalizedBody, 'base64').toString('utf-8')) as {
+    kind?: string;
+    spec?: { payloadHash?: { value?: string }; signatures?: Array<{ signature?: string }> };
+  };
+  const envelope = bundle.dsseEnvelope;
+  const payloadHash = createHash('sha256').update(Buffer.from(envelope.payload, 'base64')).digest('hex');
+  if (body.kind !== 'dsse' || body.spec?.payloadHash?.value !== payloadHash) throw new Error('The transparency log entry is for another payload');
+  if (!envelope.signatures.some(s => body.spec?.signatures?.some(logged => logged.signature === s.sig))) {
+    throw new Error('The transparency log entry is for another signature');
+  }
+
+  // The short-lived leaf must chain to a trusted CA and have been valid when logged
+  const leaf = new X509Certificate(Buffer.from(bundle.verificationMaterial.certificate.rawBytes, 'base64'));
+  const time = integratedTime * 1000;
+  if (time < Date.parse(leaf.validFrom) || time > Date.parse(leaf.validTo)) {
+    throw new Error('The signing certificate was not valid when the entry was logged');
+  }
+  const chained = root.certificateAuthorities.some(ca => {
+    if (!within(time, ca.validFor)) return false;
+    const chain = ca.certChain.certificates.map(c => new X509Certificate(Buffer.from(c.rawBytes, 'base64')));
+    let cert = leaf;
+    for (const issuer of chain) {
+      if (!cert.checkIssued(issuer) || !cert.verify(issuer.publicKey)) return false;
+      cert = issuer;
+    }
+    return chain.length > 0;
+  });
+  if (!chained) throw new Error('The signing certificate does not chain to a trusted Fulcio certificate authority');
+  return integratedTime;
+}
+
+/** Who a keyless bundle must have been signed by, as cosign's --certificate-identity and --certificate-oidc-issuer */
+export interface CertificateIdentity {
+  /** The certificate's SAN: a workflow URI or an email */
+  identity?: string;
+  /** A regular expression the whole SAN must match, for identity */
+  identityRegexp?: string;
+  /** The OIDC issuer Fulcio recorded, e.g. https://token.actions.githubusercontent.com */
+  oidcIssuer: string;
+}
+
+// 1.3.6.1.4.1.57264.1.8 (issuer, a DER UTF8String) and 1.3.6.1.4.1.57264.1.1 (its deprecated raw form)
+const ISSUER_V2 = Buffer.from('060a2b0601040183bf300108', 'hex');
+const ISSUER_V1 = Buffer.from('060a2b0601040183bf300101', 'hex');
+
+// A DER element's content, from its tag at offset
+function derContent(der: Buffer, offset: number): { tag: number; start: number; end: number } {
+  const tag = der[offset];
+  let length = der[offset + 1];
+  let start = offset + 2;
+  if (length & 0x80) {
+    const bytes = length & 0x7f;
+    length = 0;
+    for (let i = 0; i < bytes; i++) length = length * 256 + der[start + i];
+    start += bytes;
+  }
+  return { tag, start, end: start + length };
+}
+
+// The extension value following an OID: Extension ::= SEQUENCE { extnID, critical BOOLEAN DEFAULT FALSE, extnValue OCTET STRING }
+function extensionValue(der: Buffer, oid: Buffer): Buffer | undefined {
+  const at = der.indexOf(oid);
+  if (at < 0) return undefined;
+  let next = derContent(der, at).end;
+  if (der[next] === 0x01) next = derContent(der, next).end;
+  const value = derContent(der, next);
+  return value.tag === 0x04 ? der.subarray(value.start, value.end) : undefined;
+}
+
+/** The OIDC issuer Fulcio recorded in a certificate */
+export function certificateIssuer(cert: X509Certificate): string | undefined {
+  const v2 = extensionValue(cert.raw, ISSUER_V2);
+  if (v2) {
+    const value = derContent(v2, 0);
+    return v2.subarray(value.start, value.end).toString('utf-8');
+  }
+  return extensionValue(cert.raw, ISSUER_V1)?.toString('utf-8');
+}
+
+/** The identities in a certificate's SAN (URIs and emails), without their type prefix */
+export function certificateIdentities(cert: X509Certificate): string[] {
+  return (cert.subjectAltName ?? '').split(/,\s*/)
+    .map(name => name.replace(/^"|"$/g, '').match(/^(?:URI|email):(.+)$/)?.[1])
+    .filter((name): name is string => Boolean(name));
+}
+
+/**
+ * Check that a keyless certificate was issued to the expected identity by
+ * the expected OIDC issuer. Every Fulcio certificate chains to the trusted
+ * root, whoever asked for it, so this is what ties a bundle to one CI.
+ */
+export function checkCertificateIdentity(cert: X509Certificate, expected: CertificateIdentity): void {
+  const issuer = certificateIssuer(cert);
+  if (issuer !== expected.oidcIssuer) {
+    throw new Error(`The signing certificate was issued for ${issuer ?? 'an unknown OIDC issuer'}, not ${expected.oidcIssuer}`);
+  }
+  const identities = certificateIdentities(cert);
+  const pattern = expected.identityRegexp ? new RegExp(`^(?:${expected.identityRegexp})$`) : null;
+  const matches = identities.some(id => pattern ? pattern.test(id) : id === expected.identity);
+  if (!matches) {
+    const wanted = pattern ? `an identity matching ${expected.identityRegexp}` : expected.identity;
+    throw new Error(`The signing certificate is for ${identities.join(', ') || 'no identity'}, not ${wanted}`);
+  }
+}
@@ -0,0 +1,117 @@
+import chalk from 'chalk';
+import { CAPABILITIES, type CapabilityReport } from './index.js';
+import { OBSERVABLE, type CapabilityObservation } from './observe.js';
+
+const SHORT: Record<string, string> = {
+  network: 'net', exec: 'exec', 'fs-write': 'fs-w', 'fs-read': 'fs-r', env: 'env', syscall: 'sys', cgo: 'cgo', unsafe: 'unsafe',
+};
+
+export function formatCapabilityMatrix(report: CapabilityReport, options: { all: boolean }): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Dependency Capabilities'));
+  lines.push(chalk.dim(`  ${report.modules.length} third-party modules (from ${report.via})`));
+  lines.push('');
+
+  const modules = report.modules.filter(m => options.all || m.direct.length > 0 || m.transitive.length > 0);
+  if (modules.length === 0) {
+    lines.push(chalk.green('  ✓ No third-party module uses any tracked capability'));
+    lines.push('');
+  } else {
+    const width = Math.min(60, Math.max(...modules.map(m => m.module.length)) + 2);
+    lines.push(chalk.dim(`  ${'module'.padEnd(width)}${CAPABILITIES.map(c => SHORT[c].padEnd(7)).join('')}`));
+    for (const m of modules) {
+      const cells = CAPABILITIES.map(c => {
+        if (m.direct.includes(c)) return chalk.red('●'.padEnd(7));
+        if (m.transitive.some(t => t.capability === c)) return chalk.yellow('◐'.padEnd(7));
+        return chalk.dim('·'.padEnd(7));
+      }).join('');
+      const name = m.directDependency ? m.module : chalk.dim(m.module);
+      lines.push(`  ${name}${' '.repeat(Math.max(1, width - m.module.length))}${cells}`);
+    }
+    lines.push('');
+    lines.push(chalk.dim('  ● uses it directly   ◐ through a module it imports   dimmed = indirect dependency'));
+    lines.push('');
+  }
+
+  for (const warning of report.warnings.slice(0, 5)) {
+    lines.push(chalk.yellow(`⚠ ${warning}`));
+  }
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export function formatCapabilityDetails(report: CapabilityReport, module: string): string {
+  const entry = report.modules.find(m => m.module === module);
+  if (!entry) throw new Error(`Module ${module} is not a dependency of this project`);
+
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold(`${entry.module} ${chalk.dim(entry.version)}`));
+  lines.push('');
+  for (const capability of entry.direct) {
+    lines.push(`  ${chalk.red('●')} ${capability}`);
+    for (const e of entry.evidence.filter(ev => ev.capability === capability)) {
+      lines.push(chalk.dim(`      ${e.api}  ${e.file}:${e.line}`));
+    }
+  }
+  for (const t of entry.transitive) {
+    lines.push(`  ${chalk.yellow('◐')} ${t.capability} ${chalk.dim(`via ${t.via}`)}`);
+  }
+  if (entry.direct.length === 0 && entry.transitive.length === 0) lines.push(chalk.green('  ✓ no tracked capabilities'));
+  lines.push('');
+  return lines.join('\n');
+}
+
+export function formatCapabilityObservation(observation: CapabilityObservation): string {
+  const lines: string[] = [];
+  const attributed = observation.syscalls - observation.unattributed;
+  lines.push('');
+  lines.push(chalk.bold('Depwire Observed Capabilities'));
+  lines.push(chalk.dim(`  ${observation.syscalls} file, network and process syscalls; ${attributed} charged to code outside the standard library (${observation.project} to the project's own)`));
+  lines.push('');
+
+  const modules = observation.modules;
+  if (modules.length > 0) {
+    const width = Math.min(60, Math.max(...modules.map(m => m.module.length)) + 2);
+    lines.push(chalk.dim(`  ${'module'.padEnd(width)}${OBSERVABLE.map(c => SHORT[c].padEnd(7)).join('')}`));
+    for (const m of modules) {
+      const cells = OBSERVABLE.map(c => {
+        const status = m.capabilities.find(s => s.capability === c)?.status;
+        if (status === 'confirmed') return chalk.green('✓'.padEnd(7));
+        if (status === 'unexpected') return chalk.red('!'.padEnd(7));
+        if (status === 'unexercised') return chalk.dim('○'.padEnd(7));
+        return chalk.dim('·'.padEnd(7));
+      }).join('');
+      lines.push(`  ${m.module}${' '.repeat(Math.max(1, width - m.module.length))}${cells}`);
+    }
+    lines.push('');
+    lines.push(chalk.dim('  ✓ observed, as the static report says   ! observed, missing from the static report   ○ in the static report, not exercised'));
+    lines.push('');
+  }
+
+  const unexpected = modules.filter(m => m.capabilities.some(c => c.status === 'unexpected'));
+  if (unexpected.length > 0) {
+    lines.push(chalk.bold('  Missed by the static report'));
+    for (const m of unexpected) {
+      for (const c of m.capabilities.filter(s => s.status === 'unexpected')) {
+        lines.push(`  ${chalk.red('!')} ${m.module} ${chalk.dim(m.version)} ${c.capability} ${chalk.dim(`(${c.count} syscalls)`)}`);
+        for (const use of m.uses.filter(u => u.capability === c.capability)) {
+          lines.push(chalk.dim(`      ${use.syscall}${use.target ? ` ${use.target}` : ''} from ${use.frame}${use.count > 1 ? ` ×${use.count}` : ''}`));
+        }
+      }
+    }
+    lines.push('');
+  }
+
+  const reaching = modules.filter(m => m.destinations.length > 0);
+  if (reaching.length > 0) {
+    lines.push(chalk.bold('  Network destinations'));
+    for (const m of reaching) lines.push(`  ${m.module}  ${chalk.dim(m.destinations.join(', '))}`);
+    lines.push('');
+  }
+
+  for (const warning of observation.warnings) lines.push(chalk.yellow(`⚠ ${warning}`));
+  if (observation.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
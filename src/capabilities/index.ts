@@ -0,0 +1,186 @@
+import { walk, goImports, type GoSourceFile } from '../golang/source.js';
+import { fileQualifiers } from '../golang/references.js';
+import { loadGoDependencies, dependencyModule, type GoDependencies } from '../golang/deps.js';
+import { readGoMod } from '../golang/modfile.js';
+import { findGoModuleRoot } from '../golang/toolchain.js';
+
+/**
+ * What each third-party module can do, judged by the standard library (and
+ * x/sys) functions its code calls: network, process execution, file system
+ * writes and reads, environment access, raw syscalls, cgo and unsafe.
+ * Capabilities are also inherited through the modules a module imports.
+ */
+
+export type Capability = 'network' | 'exec' | 'fs-write' | 'fs-read' | 'env' | 'syscall' | 'cgo' | 'unsafe';
+
+export const CAPABILITIES: Capability[] = ['network', 'exec', 'fs-write', 'fs-read', 'env', 'syscall', 'cgo', 'unsafe'];
+
+export interface CapabilityEvidence {
+  capability: Capability;
+  /** "os.WriteFile", `import "C"` */
+  api: string;
+  file: string;
+  line: number;
+}
+
+export interface ModuleCapabilities {
+  module: string;
+  version: string;
+  /** Capabilities the module's own code uses */
+  direct: Capability[];
+  /** Capabilities that come only from modules it imports, with the first such module */
+  transitive: Array<{ capability: Capability; via: string }>;
+  /** A few call sites per direct capability */
+  evidence: CapabilityEvidence[];
+  /** Whether the main module requires it directly (go.mod without // indirect) */
+  directDependency: boolean;
+  /** Modules it imports */
+  imports: string[];
+}
+
+export interface CapabilityReport {
+  projectRoot: string;
+  modules: ModuleCapabilities[];
+  via: GoDependencies['via'];
+  warnings: string[];
+}
+
+/** Package → function name → capability; '*' is any identifier of the package */
+const SIGNALS: Record<string, Record<string, Capability>> = {
+  net: Object.fromEntries([
+    'Dial', 'DialTCP', 'DialUDP', 'DialIP', 'DialUnix', 'DialTimeout', 'Dialer', 'Listen', 'ListenTCP', 'ListenUDP',
+    'ListenIP', 'ListenUnix', 'ListenUnixgram', 'ListenPacket', 'ListenMulticastUDP', 'ListenConfig', 'FileConn',
+    'FileListener', 'LookupHost', 'LookupIP', 'LookupAddr', 'LookupCNAME', 'LookupMX', 'LookupNS', 'LookupTXT',
+    'LookupSRV', 'LookupPort', 'Resolver', 'DefaultResolver', 'ResolveTCPAddr', 'ResolveUDPAddr', 'ResolveIPAddr',
+  ].map(n => [n, 'network' as Capability])),
+  'net/http': Object.fromEntries([
+    'Get', 'Head', 'Post', 'PostForm', 'Client', 'DefaultClient', 'Transport', 'DefaultTransport', 'Server',
+    'ListenAndServe', 'ListenAndServeTLS', 'Serve', 'ServeTLS',
+  ].map(n => [n, 'network' as Capability])),
+  'net/rpc': { Dial: 'network', DialHTTP: 'network', DialHTTPPath: 'network', Accept: 'network', ServeConn: 'network' },
+  'net/smtp': { Dial: 'network', SendMail: 'network' },
+  'crypto/tls': { Dial: 'network', DialWithDialer: 'network', Dialer: 'network', Listen: 'network' },
+  os: {
+    Create: 'fs-write', CreateTemp: 'fs-write', WriteFile: 'fs-write', OpenFile: 'fs-write', Mkdir: 'fs-write',
+    MkdirAll: 'fs-write', MkdirTemp: 'fs-write', Remove: 'fs-write', RemoveAll: 'fs-write', Rename: 'fs-write',
+    Chmod: 'fs-write', Chown: 'fs-write', Lchown: 'fs-write', Chtimes: 'fs-write', Link: 'fs-write',
+    Symlink: 'fs-write', Truncate: 'fs-write', CopyFS: 'fs-write',
+    Open: 'fs-read', ReadFile: 'fs-read', ReadDir: 'fs-read', Stat: 'fs-read', Lstat: 'fs-read', DirFS: 'fs-read',
+    Readlink: 'fs-read', Getwd: 'fs-read', UserHomeDir: 'env', UserConfigDir: 'env', UserCacheDir: 'env',
+    Getenv: 'env', LookupEnv: 'env', Environ: 'env', ExpandEnv: 'env', Setenv: 'env', Unsetenv: 'env', Clearenv: 'env',
+    StartProcess: 'exec', FindProcess: 'exec',
+  },
+  'io/ioutil': { WriteFile: 'fs-write', TempFile: 'fs-write', TempDir: 'fs-write', ReadFile: 'fs-read', ReadDir: 'fs-read' },
+  'path/filepath': { Walk: 'fs-read', WalkDir: 'fs-read', Glob: 'fs-read', EvalSymlinks: 'fs-read', Abs: 'fs-read' },
+  'os/exec': { '*': 'exec' },
+  plugin: { Open: 'exec' },
+  syscall: { Exec: 'exec', ForkExec: 'exec', StartProcess: 'exec', Getenv: 'env', Setenv: 'env', Environ: 'env', '*': 'syscall' },
+  'golang.org/x/sys/unix': { Exec: 'exec', ForkExec: 'exec', '*': 'syscall' },
+  'golang.org/x/sys/windows': { '*': 'syscall' },
+};
+
+// Identifiers that are types or constants only, never an action
+const NEUTRAL = new Set(['Errno', 'Signal', 'SIGINT', 'SIGTERM', 'SIGKILL', 'SIGHUP', 'ENOENT', 'EEXIST', 'EINTR', 'EAGAIN']);
+
+const MAX_EVIDENCE = 3;
+
+export async function analyzeCapabilities(projectRoot: string, options: { signal?: AbortSignal } = {}): Promise<CapabilityReport> {
+  const deps = await loadGoDependencies(projectRoot, { signal: options.signal });
+  const moduleRoot = findGoModuleRoot(projectRoot);
+  const required = new Map((moduleRoot ? readGoMod(moduleRoot)?.require ?? [] : []).map(r => [r.path, r]));
+
+  // Import path → module path, for inheriting capabilities through imports
+  const moduleOf = new Map<string, string>();
+  for (const pkg of deps.packages) {
+    const mod = dependencyModule(pkg.dir);
+    if (mod && pkg.importPath) moduleOf.set(pkg.importPath, mod.path);
+  }
+
+  const modules = new Map<string, ModuleCapabilities>();
+  for (const pkg of deps.packages) {
+    const mod = dependencyModule(pkg.dir);
+    if (!mod) continue;
+    if (!modules.has(mod.path)) {
+      modules.set(mod.path, {
+        module: mod.path,
+        version: mod.version,
+        direct: [],
+        transitive: [],
+        evidence: [],
+        directDependency: required.has(mod.path) && !required.get(mod.path)!.indirect,
+        imports: [],
+      });
+    }
+    const entry = modules.get(mod.path)!;
+    for (const file of pkg.files) {
+      for (const evidence of fileCapabilities(file)) {
+        if (!entry.direct.includes(evidence.capability)) entry.direct.push(evidence.capability);
+        if (entry.evidence.filter(e => e.capability === evidence.capability).length < MAX_EVIDENCE) entry.evidence.push(evidence);
+      }
+    }
+    for (const importPath of pkg.imports.keys()) {
+      const target = moduleOf.get(importPath);
+      if (target && target !== mod.path && !entry.imports.includes(target)) entry.imports.push(target);
+    }
+  }
+
+  // Inherit through imports, breadth first so `via` is the nearest module
+  for (const entry of modules.values()) {
+    const seen = new Set([entry.module]);
+    const queue = entry.imports.map(m => ({ module: m, via: m }));
+    while (queue.length > 0) {
+      const { module, via } = queue.shift()!;
+      if (seen.has(module)) continue;
+      seen.add(module);
+      const dep = modules.get(module);
+      if (!dep) continue;
+      for (const capability of dep.direct) {
+        if (!entry.direct.includes(capability) && !entry.transitive.some(t => t.capability === capability)) {
+          entry.transitive.push({ capability, via });
+        }
+      }
+      queue.push(...dep.imports.map(m => ({ module: m, via })));
+    }
+    entry.direct.sort((a, b) => CAPABILITIES.indexOf(a) - CAPABILITIES.indexOf(b));
+    entry.transitive.sort((a, b) => CAPABILITIES.indexOf(a.capability) - CAPABILITIES.indexOf(b.capability));
+  }
+
+  return {
+    projectRoot,
+    modules: [...modules.values()].sort((a, b) => a.module.localeCompare(b.module)),
+    via: deps.via,
+    warnings: deps.warnings,
+  };
+}
+
+/** Capability uses in one file */
+export function fileCapabilities(file: GoSourceFile): CapabilityEvidence[] {
+  const found: CapabilityEvidence[] = [];
+  for (const imp of goImports(file)) {
+    if (imp.path === 'C') found.push({ capability: 'cgo', api: 'import "C"', file: file.file, line: imp.line });
+    if (imp.path === 'unsafe') found.push({ capability: 'unsafe', api: 'import "unsafe"', file: file.file, line: imp.line });
+  }
+
+  const qualifiers = fileQualifiers(file);
+  const relevant = new Map([...qualifiers].filter(([, path]) => SIGNALS[path]));
+  if (relevant.size === 0) return found;
+
+  walk(file.root, (node) => {
+    if (node.type !== 'selector_expression' && node.type !== 'qualified_type') return;
+    const qualifier = node.childForFieldName(node.type === 'qualified_type' ? 'package' : 'operand');
+    const name = node.childForFieldName(node.type === 'qualified_type' ? 'name' : 'field')?.text;
+    const path = qualifier ? relevant.get(qualifier.text) : undefined;
+    if (!path || !name || NEUTRAL.has(name)) return;
+    const capability = SIGNALS[path][name] ?? SIGNALS[path]['*'];
+    if (capability) {
+      found.push({ capability, api: `${path}.${name}`, file: file.file, line: node.startPosition.row + 1 });
+      return false;
+    }
+  });
+  return found;
+}
+
+/** Every capability of a module, direct or inherited */
+export function allCapabilities(entry: ModuleCapabilities): Capability[] {
+  return [...entry.direct, ...entry.transitive.map(t => t.capability)];
+}
@@ -0,0 +1,278 @@
+import { execFile } from 'child_process';
+import { existsSync, mkdtempSync, readFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { symbolPackage } from '../golang/binary.js';
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+import { allCapabilities, type Capability, type CapabilityReport } from './index.js';
+
+/**
+ * Capabilities observed at runtime, to check the static report against.
+ * A run of the program (or a test binary) under `strace -f -k` records
+ * every file, network and process syscall with the stack that made it;
+ * each one is charged to the innermost frame outside the standard library,
+ * the code that asked for it, and so to a module. Observed capabilities
+ * the static report doesn't give that module mean the static analysis
+ * missed a path (reflection, code generated at build time, assembly);
+ * static ones never observed are just paths the run didn't exercise.
+ *
+ * Syscalls show network, exec and file system use only: environment
+ * access, cgo and unsafe happen without one, and a goroutine's stack
+ * stops where it was started, so work handed to another goroutine (the
+ * HTTP transport's dialer, say) is charged to whoever started that one,
+ * often nobody outside the standard library.
+ */
+
+export const OBSERVABLE: Capability[] = ['network', 'exec', 'fs-write', 'fs-read'];
+
+export interface SyscallEvent {
+  pid: number;
+  syscall: string;
+  args: string;
+  /** Return value, with the errno of failures ("-1 ENOENT (No such file or directory)") */
+  result: string;
+  /** Go symbols of the stack, innermost first; frames without one are left out */
+  frames: string[];
+}
+
+export interface ObservedUse {
+  capability: Capability;
+  syscall: string;
+  /** File path, network address or program */
+  target: string;
+  /** The frame charged: the innermost one outside the standard library */
+  frame: string;
+  count: number;
+}
+
+export type ObservationStatus = 'confirmed' | 'unexpected' | 'unexercised';
+
+export interface ObservedModule {
+  module: string;
+  version: string;
+  capabilities: Array<{ capability: Capability; status: ObservationStatus; count: number }>;
+  /** A few uses per observed capability, most frequent first */
+  uses: ObservedUse[];
+  /** Addresses connected or sent to */
+  destinations: string[];
+}
+
+export interface CapabilityObservation {
+  /** Syscalls the run made that map to a capability */
+  syscalls: number;
+  /** Of those, charged to the project's own code */
+  project: number;
+  /** Of those, with no frame outside the standard library and runtime */
+  unattributed: number;
+  /** Third-party modules with something observed or expected */
+  modules: ObservedModule[];
+  warnings: string[];
+}
+
+export interface ObserveOptions {
+  cwd?: string;
+  signal?: AbortSignal;
+}
+
+const SYSCALLS: Record<string, Capability> = {
+  socket: 'network', connect: 'network', bind: 'network', listen: 'network', accept: 'network', accept4: 'network',
+  sendto: 'network', sendmsg: 'network', sendmmsg: 'network',
+  execve: 'exec', execveat: 'exec',
+  unlink: 'fs-write', unlinkat: 'fs-write', rename: 'fs-write', renameat: 'fs-write', renameat2: 'fs-write',
+  mkdir: 'fs-write', mkdirat: 'fs-write', rmdir: 'fs-write', chmod: 'fs-write', fchmod: 'fs-write', fchmodat: 'fs-write',
+  chown: 'fs-write', fchown: 'fs-write', fchownat: 'fs-write', lchown: 'fs-write', truncate: 'fs-write', ftruncate: 'fs-write',
+  symlink: 'fs-write', symlinkat: 'fs-write', link: 'fs-write', linkat: 'fs-write', utimensat: 'fs-write', creat: 'fs-write',
+  stat: 'fs-read', lstat: 'fs-read', newfstatat: 'fs-read', fstatat64: 'fs-read', statx: 'fs-read', readlink: 'fs-read',
+  readlinkat: 'fs-read', getdents64: 'fs-read', access: 'fs-read', faccessat: 'fs-read', faccessat2: 'fs-read',
+};
+
+const OPEN = new Set(['open', 'openat', 'openat2']);
+
+/** The strace filter: the classes above, and the fd-based calls they leave out */
+const TRACE = '%file,%network,%process,?getdents64,?ftruncate,?fchmod,?fchown';
+
+const MAX_USES = 3;
+
+// pid syscall(args) = result; "<unfinished ...>" and "<... name resumed>" split one call across lines under -f
+const CALL = /^(?:(\d+)\s+)?(\w+)\((.*)\)\s+=\s+(.+)$/;
+const UNFINISHED = /^(?:(\d+)\s+)?(\w+)\((.*?)\s*<unfinished \.\.\.>$/;
+const RESUMED = /^(?:(\d+)\s+)?<\.\.\. (\w+) resumed>(.*)\)\s+=\s+(.+)$/;
+// " > /path/to/binary(symbol+0x1a) [0x4a1b2c]"
+const FRAME = /^(?:\d+\s+)?\s*> [^(]*\((.*)\+0x[0-9a-f]+\) \[0x[0-9a-f]+\]$/;
+
+/** Syscalls and their stacks from an strace -k log, with or without -f pid prefixes */
+export function parseStraceLog(content: string): SyscallEvent[] {
+  const events: SyscallEvent[] = [];
+  const pending = new Map<number, { syscall: string; args: string }>();
+  let current: SyscallEvent | null = null;
+
+  for (const line of content.split('\n')) {
+    const frame = FRAME.exec(line);
+    if (frame) {
+      current?.frames.push(frame[1]);
+      continue;
+    }
+    if (/^\s*>/.test(line.replace(/^\d+\s+/, ''))) continue;
+    current = null;
+
+    let match = UNFINISHED.exec(line);
+    if (match) {
+      pending.set(parseInt(match[1] ?? '0', 10), { syscall: match[2], args: match[3] });
+      continue;
+    }
+    match = RESUMED.exec(line);
+    if (match) {
+      const pid = parseInt(match[1] ?? '0', 10);
+      const start = pending.get(pid);
+      pending.delete(pid);
+      const args = `${start?.syscall === match[2] ? start.args : ''}${match[3]}`.replace(/^,\s*/, '');
+      events.push(current = { pid, syscall: match[2], args, result: match[4].trim(), frames: [] });
+      continue;
+    }
+    match = CALL.exec(line);
+    if (match) events.push(current = { pid: parseInt(match[1] ?? '0', 10), syscall: match[2], args: match[3], result: match[4].trim(), frames: [] });
+  }
+  return events;
+}
+
+export function syscallCapability(event: Pick<SyscallEvent, 'syscall' | 'args'>): Capability | null {
+  if (OPEN.has(event.syscall)) return /O_(WRONLY|RDWR|CREAT|TRUNC|APPEND)/.test(event.args) ? 'fs-write' : 'fs-read';
+  return SYSCALLS[event.syscall] ?? null;
+}
+
+const quoted = (args: string) => /"((?:[^"\\]|\\.)*)"/.exec(args)?.[1].replace(/\\(.)/g, '$1') ?? null;
+
+/** An address from strace's sockaddr rendering: 10.0.0.5:443, [::1]:8080, unix:/run/app.sock */
+export function socketAddress(args: string): string | null {
+  const port = /sin6?_port=htons\((\d+)\)/.exec(args)?.[1];
+  const v4 = /inet_addr\("([^"]+)"\)/.exec(args)?.[1];
+  if (v4) return `${v4}:${port ?? 0}`;
+  const v6 = /inet_pton\(AF_INET6, "([^"]+)"/.exec(args)?.[1];
+  if (v6) return `[${v6}]:${port ?? 0}`;
+  const unix = /sun_path=(@?"(?:[^"\\]|\\.)*")/.exec(args)?.[1];
+  if (unix) return `unix:${unix.startsWith('@') ? '@' : ''}${quoted(unix)}`;
+  return null;
+}
+
+function target(event: SyscallEvent, capability: Capability): string {
+  if (capability === 'network') {
+    const family = /\b(AF_\w+|PF_\w+)\b/.exec(event.args)?.[1];
+    return socketAddress(event.args) ?? (event.syscall === 'socket' && family ? family : '');
+  }
+  return quoted(event.args) ?? '';
+}
+
+const isStdPackage = (pkg: string) => !pkg.split('/')[0].includes('.');
+
+/**
+ * Charge observed syscalls to the modules of a static capability report
+ * and compare: confirmed (observed and in the report, directly or
+ * inherited), unexpected (observed only) and unexercised (reported for an
+ * observable capability, never observed).
+ */
+export function observeCapabilities(report: CapabilityReport, events: SyscallEvent[]): CapabilityObservation {
+  const project = new GoModuleIndex(findGoModules(report.projectRoot));
+  const byLength = [...report.modules].sort((a, b) => b.module.length - a.module.length);
+  const moduleOf = (pkg: string) => byLength.find(m => pkg === m.module || pkg.startsWith(`${m.module}/`)) ?? null;
+
+  const observed = new Map<string, Map<string, ObservedUse>>();
+  const destinations = new Map<string, Set<string>>();
+  const warnings: string[] = [];
+  let syscalls = 0;
+  let own = 0;
+  let unattributed = 0;
+  let framed = 0;
+
+  for (const event of events) {
+    const capability = syscallCapability(event);
+    if (!capability) continue;
+    syscalls++;
+    if (event.frames.length > 0) framed++;
+    let charged: { frame: string; pkg: string } | null = null;
+    for (const frame of event.frames) {
+      const pkg = frame.startsWith('runtime.') ? 'runtime' : symbolPackage(frame);
+      if (pkg && !isStdPackage(pkg)) {
+        charged = { frame, pkg };
+        break;
+      }
+    }
+    if (!charged) {
+      unattributed++;
+      continue;
+    }
+    if (project.moduleForImport(charged.pkg)) {
+      own++;
+      continue;
+    }
+    const mod = moduleOf(charged.pkg);
+    if (!mod) {
+      unattributed++;
+      continue;
+    }
+    const where = target(event, capability);
+    const uses = observed.get(mod.module) ?? new Map<string, ObservedUse>();
+    const key = `${capability}\0${event.syscall}\0${where}\0${charged.frame}`;
+    const use = uses.get(key);
+    if (use) use.count++;
+    else uses.set(key, { capability, syscall: event.syscall, target: where, frame: charged.frame, count: 1 });
+    observed.set(mod.module, uses);
+    if (capability === 'network' && where && event.syscall !== 'bind' && event.syscall !== 'socket') {
+      const set = destinations.get(mod.module) ?? new Set<string>();
+      set.add(where);
+      destinations.set(mod.module, set);
+    }
+  }
+  if (syscalls > 0 && framed === 0) warnings.push('No stack traces in the log: record it with strace -k (and a build of strace with stack tracing)');
+  else if (syscalls === 0) warnings.push('The log has no file, network or process syscalls');
+
+  const modules: ObservedModule[] = [];
+  for (const mod of report.modules) {
+    const uses = [...(observed.get(mod.module)?.values() ?? [])].sort((a, b) => b.count - a.count);
+    const expected = new Set(allCapabilities(mod));
+    const capabilities: ObservedModule['capabilities'] = [];
+    for (const capability of OBSERVABLE) {
+      const count = uses.filter(u => u.capability === capability).reduce((n, u) => n + u.count, 0);
+      if (count > 0) capabilities.push({ capability, status: expected.has(capability) ? 'confirmed' : 'unexpected', count });
+      else if (expected.has(capability)) capabilities.push({ capability, status: 'unexercised', count: 0 });
+    }
+    if (capabilities.length === 0) continue;
+    modules.push({
+      module: mod.module,
+      version: mod.version,
+      capabilities,
+      uses: OBSERVABLE.flatMap(c => uses.filter(u => u.capability === c).slice(0, MAX_USES)),
+      destinations: [...(destinations.get(mod.module) ?? [])].sort(),
+    });
+  }
+
+  return { syscalls, project: own, unattributed, modules, warnings };
+}
+
+/**
+ * Run a command under strace and return its log. The command goes through
+ * sh -c, so it can be a test binary with flags (./app.test -test.run
+ * TestSync); `go test` itself would trace the compiler too.
+ */
+export async function traceCommand(command: string, options: ObserveOptions = {}): Promise<string> {
+  const dir = mkdtempSync(join(tmpdir(), 'depwire-observe-'));
+  const log = join(dir, 'strace.log');
+  try {
+    const stderr = await new Promise<string>((resolve, reject) => {
+      const args = ['-f', '-k', '-qq', '-e', `trace=${TRACE}`, '-o', log, '--', 'sh', '-c', command];
+      execFile('strace', args, { cwd: options.cwd, signal: options.signal, maxBuffer: 256 * 1024 * 1024, encoding: 'utf-8' }, (error, _stdout, stderr) => {
+        if (error && (error as NodeJS.ErrnoException).code === 'ENOENT') {
+          reject(new Error('strace not found — install it (Linux only), or record a log elsewhere with strace -f -k and pass --observe'));
+        } else if (error && options.signal?.aborted) {
+          reject(error);
+        } else {
+          // A failing command still leaves a log worth reading
+          resolve(stderr);
+        }
+      });
+    });
+    if (!existsSync(log)) throw new Error(`strace failed: ${stderr.trim().split('\n')[0] || 'no log written'}`);
+    return readFileSync(log, 'utf-8');
+  } finally {
+    rmSync(dir, { recursive: true, force: true });
+  }
+}
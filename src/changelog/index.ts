@@ -0,0 +1,218 @@
+import { compareBaseline, type Baseline, type DriftReport, type MetricDrift } from '../drift/index.js';
+import { compareVersions } from '../golang/modgraph.js';
+import { fetchModule, type FetchModuleOptions } from '../remote/index.js';
+import { detectLicense, type DetectedLicense } from '../remote/license.js';
+import { mapLimit, type LicenseChange } from '../remote/compare.js';
+import { queryVulnerabilities, fetchAdvisory, type OsvAdvisory, type OsvOptions } from '../supply-chain/osv.js';
+import { checkCancelled } from '../utils/progress.js';
+
+/**
+ * The dependency side of a release: what changed in the Go requirements and
+ * the package graph between two tags — modules added, removed, upgraded and
+ * downgraded, their license changes, advisories the new versions bring in or
+ * fix, and how much the graph grew — as a section for the release notes.
+ * The graphs come from baselines captured at each ref (see drift).
+ */
+
+export interface ChangelogAdvisory extends OsvAdvisory {
+  module: string;
+  version: string;
+}
+
+export interface DependencyChangelog {
+  from: { ref: string; commit: string | null };
+  to: { ref: string; commit: string | null };
+  modules: {
+    added: Array<{ path: string; version: string; indirect: boolean }>;
+    removed: Array<{ path: string; version: string }>;
+    upgraded: Array<{ path: string; from: string; to: string }>;
+    downgraded: Array<{ path: string; from: string; to: string }>;
+  };
+  licenses: LicenseChange[];
+  vulnerabilities: {
+    /** Advisories affecting a version at `to` that didn't affect the module at `from` */
+    introduced: ChangelogAdvisory[];
+    /** Advisories affecting a version at `from` that no longer apply at `to` */
+    fixed: ChangelogAdvisory[];
+  };
+  graph: {
+    metrics: MetricDrift[];
+    packages: DriftReport['packages'];
+    edges: { added: number; removed: number };
+    cycles: DriftReport['cycles'];
+  };
+  /** Checks that could not be made: modules not fetched, OSV unreachable */
+  warnings: string[];
+}
+
+export interface ChangelogOptions extends FetchModuleOptions {
+  /** Fetch added and changed modules to compare their licenses (default true) */
+  licenses?: boolean;
+  /** Query OSV for advisories of added and changed modules (default true) */
+  vulnerabilities?: boolean;
+  osv?: OsvOptions;
+  /** Downloads in flight at once (default 8) */
+  concurrency?: number;
+}
+
+/** "v1.2.0..v1.3.0" → from and to; an empty side of the range is HEAD, as in git */
+export function parseReleaseRange(range: string): { from: string; to: string } {
+  const match = /^(.*?)\.\.(?!\.)(.*)$/.exec(range);
+  if (!match) throw new Error(`Expected a range like v1.2.0..v1.3.0, got "${range}"`);
+  const [from, to] = [match[1] || 'HEAD', match[2] || 'HEAD'];
+  if (from === to) throw new Error(`The range ${range} compares ${from} with itself`);
+  return { from, to };
+}
+
+export async function dependencyChangelog(
+  before: Baseline,
+  after: Baseline,
+  refs: { from: string; to: string },
+  options: ChangelogOptions = {}
+): Promise<DependencyChangelog> {
+  const drift = compareBaseline(before, after);
+  const warnings: string[] = [];
+  const changed = drift.modules.changed.map(({ path, before, after }) => ({ path, from: before, to: after }));
+  const modules: DependencyChangelog['modules'] = {
+    added: drift.modules.added,
+    removed: drift.modules.removed,
+    upgraded: changed.filter(m => compareVersions(m.to, m.from) > 0),
+    downgraded: changed.filter(m => compareVersions(m.to, m.from) < 0),
+  };
+
+  const licenses: LicenseChange[] = [];
+  if (options.licenses !== false) {
+    const licenseOf = async (path: string, version: string): Promise<DetectedLicense | undefined> => {
+      try {
+        return detectLicense((await fetchModule(`${path}@${version}`, options)).dir);
+      } catch (err) {
+        if (options.signal?.aborted) throw err;
+        warnings.push(`${path}@${version}: ${err instanceof Error ? err.message : err} (license not checked)`);
+        return undefined;
+      }
+    };
+    const checks = [
+      ...modules.added.map(m => async () => {
+        const to = await licenseOf(m.path, m.version);
+        if (to !== undefined) licenses.push({ path: m.path, to });
+      }),
+      ...changed.map(m => async () => {
+        const [from, to] = [await licenseOf(m.path, m.from), await licenseOf(m.path, m.to)];
+        if (from !== undefined && to !== undefined && from !== to) licenses.push({ path: m.path, from, to });
+      }),
+    ];
+    await mapLimit(checks, options.concurrency ?? 8, check => check());
+    licenses.sort((a, b) => a.path.localeCompare(b.path));
+  }
+
+  const vulnerabilities: DependencyChangelog['vulnerabilities'] = { introduced: [], fixed: [] };
+  if (options.vulnerabilities !== false) {
+    checkCancelled(options.signal);
+    // Unchanged modules have the same advisories on both sides
+    const old = [...modules.removed, ...changed.map(m => ({ path: m.path, version: m.from }))];
+    const current = [...modules.added.map(({ path, version }) => ({ path, version })), ...changed.map(m => ({ path: m.path, version: m.to }))];
+    const osv = { signal: options.signal, ...options.osv };
+    try {
+      const ids = await queryVulnerabilities([...old, ...current], {
+        ...osv,
+        onPrivate: (hidden) => {
+          const paths = [...new Set(hidden.map(m => m.path))];
+          warnings.push(`${paths.length} private modules (GOPRIVATE) were not checked for vulnerabilities: ${paths.join(', ')}`);
+        },
+      });
+      const idsOf = (list: Array<{ path: string; version: string }>) =>
+        new Map(list.map(m => [m.path, new Set(ids.get(`${m.path}@${m.version}`) ?? [])]));
+      const [oldIds, currentIds] = [idsOf(old), idsOf(current)];
+      const pending: Array<{ module: string; version: string; id: string; list: ChangelogAdvisory[] }> = [];
+      for (const m of current) {
+        for (const id of currentIds.get(m.path)!) {
+          if (!oldIds.get(m.path)?.has(id)) pending.push({ module: m.path, version: m.version, id, list: vulnerabilities.introduced });
+        }
+      }
+      for (const m of old) {
+        for (const id of oldIds.get(m.path)!) {
+          if (!currentIds.get(m.path)?.has(id)) pending.push({ module: m.path, version: m.version, id, list: vulnerabilities.fixed });
+        }
+      }
+      await mapLimit(pending, options.concurrency ?? 8, async ({ module, version, id, list }) => {
+        let advisory: OsvAdvisory;
+        try {
+          advisory = await fetchAdvisory(id, osv);
+        } catch (err) {
+          if (options.signal?.aborted) throw err;
+          advisory = { id, aliases: [], summary: null, url: `https://osv.dev/vulnerability/${id}` };
+        }
+        list.push({ module, version, ...advisory });
+      });
+      for (const list of [vulnerabilities.introduced, vulnerabilities.fixed]) {
+        list.sort((a, b) => a.module.localeCompare(b.module) || a.id.localeCompare(b.id));
+      }
+    } catch (err) {
+      if (options.signal?.aborted) throw err;
+      warnings.push(`OSV: ${err instanceof Error ? err.message : err} (vulnerabilities not checked)`);
+    }
+  }
+
+  return {
+    from: { ref: refs.from, commit: before.commit },
+    to: { ref: refs.to, commit: after.commit },
+    modules,
+    licenses,
+    vulnerabilities,
+    graph: {
+      metrics: drift.metrics,
+      packages: drift.packages,
+      edges: { added: drift.edges.added.length, removed: drift.edges.removed.length },
+      cycles: drift.cycles,
+    },
+    warnings: warnings.sort(),
+  };
+}
+
+/** The changelog as a "Dependencies" section to paste into release notes */
+export function formatChangelogMarkdown(changelog: DependencyChangelog): string {
+  const { modules, licenses, vulnerabilities, graph } = changelog;
+  const lines: string[] = [];
+  lines.push(`## Dependency changes in ${changelog.to.ref}`, '');
+  const counts = [
+    `${modules.added.length} added`,
+    `${modules.removed.length} removed`,
+    `${modules.upgraded.length} upgraded`,
+    ...(modules.downgraded.length > 0 ? [`${modules.downgraded.length} downgraded`] : []),
+  ];
+  lines.push(`Since ${changelog.from.ref}: ${counts.join(', ')} modules.`, '');
+
+  const section = (title: string, items: string[]) => {
+    if (items.length === 0) return;
+    lines.push(`### ${title}`, '', ...items.map(item => `- ${item}`), '');
+  };
+  const license = (l: DetectedLicense | undefined) => l ?? 'no license';
+  const licenseOf = new Map(licenses.filter(l => l.from === undefined).map(l => [l.path, l.to]));
+  const advisory = (a: ChangelogAdvisory) =>
+    `[${a.id}](${a.url})${a.aliases.length > 0 ? ` (${a.aliases.join(', ')})` : ''} in \`${a.module}\` ${a.version}${a.summary ? `: ${a.summary}` : ''}`;
+
+  section('⚠ New known vulnerabilities', vulnerabilities.introduced.map(advisory));
+  section('Fixed vulnerabilities', vulnerabilities.fixed.map(advisory));
+  section('Added', modules.added.map(m =>
+    `\`${m.path}\` ${m.version}${m.indirect ? ' (indirect)' : ''}${licenseOf.has(m.path) ? ` — ${license(licenseOf.get(m.path))}` : ''}`));
+  section('Upgraded', modules.upgraded.map(m => `\`${m.path}\` ${m.from} → ${m.to}`));
+  section('Downgraded', modules.downgraded.map(m => `\`${m.path}\` ${m.from} → ${m.to}`));
+  section('Removed', modules.removed.map(m => `\`${m.path}\` ${m.version}`));
+  section('License changes', licenses.filter(l => l.from !== undefined).map(l => `\`${l.path}\`: ${license(l.from)} → ${license(l.to)}`));
+
+  const sizes = graph.metrics.filter(m => ['files', 'packages', 'packageEdges', 'directModules', 'indirectModules', 'depth'].includes(m.metric));
+  if (sizes.length > 0) {
+    lines.push('### Dependency graph', '', `| | ${changelog.from.ref} | ${changelog.to.ref} | Change |`, '| --- | ---: | ---: | ---: |');
+    for (const m of sizes) {
+      const delta = m.after - m.before;
+      lines.push(`| ${m.metric} | ${m.before} | ${m.after} | ${delta > 0 ? `+${delta}` : delta} |`);
+    }
+    lines.push('');
+  }
+  section('New or grown package cycles', graph.cycles.map(c => c.cycle.map(p => `\`${p}\``).join(' → ')));
+
+  if (changelog.warnings.length > 0) {
+    lines.push('<details><summary>Not checked</summary>', '', ...changelog.warnings.map(w => `- ${w}`), '', '</details>', '');
+  }
+  return lines.join('\n');
+}
@@ -0,0 +1,93 @@
+import chalk from 'chalk';
+import { withInterrupt } from '../utils/progress.js';
+import { resolve } from 'path';
+import { fetchModule, openArchive, analyzeSource, type RemoteAnalysis } from '../remote/index.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('analyze');
+
+export interface AnalyzeCommandOptions {
+  proxy?: string;
+  cacheDir?: string;
+  format?: string;
+  limit?: string;
+  /** Expected h1: hash of a module zip (analyze archive) */
+  sum?: string;
+}
+
+const SEVERITY_COLOR: Record<string, (s: string) => string> = {
+  critical: chalk.red.bold,
+  high: chalk.red,
+  medium: chalk.yellow,
+  low: chalk.dim,
+  info: chalk.dim,
+};
+
+function formatRemoteAnalysis(report: RemoteAnalysis, limit: number): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold(`Depwire Analysis: ${report.module}${report.version ? `@${report.version}` : ''}`));
+  lines.push(chalk.dim(`  From ${report.origin}${report.time ? `, published ${report.time.slice(0, 10)}` : ''}`));
+  lines.push(chalk.dim(`  Source: ${report.dir}`));
+  lines.push('');
+  lines.push(`  ${report.files} files, ${report.packages} packages, ${report.symbols} symbols${report.go ? `, go ${report.go}` : ''}`);
+  lines.push(`  Health: ${report.health.score}/100 (${report.health.grade})`);
+  lines.push(`  Requires: ${report.requires.direct.length} direct, ${report.requires.indirect} indirect modules`);
+  for (const req of report.requires.direct.slice(0, limit)) lines.push(chalk.dim(`    ${req.path} ${req.version}`));
+  if (report.requires.direct.length > limit) lines.push(chalk.dim(`    … ${report.requires.direct.length - limit} more`));
+
+  lines.push('');
+  const capabilities = Object.entries(report.capabilities);
+  if (capabilities.length === 0) {
+    lines.push(chalk.green('  No network, exec, file, env, syscall, cgo or unsafe use in the module\'s own code'));
+  } else {
+    lines.push(chalk.bold('  Capabilities'));
+    for (const [capability, evidence] of capabilities) {
+      const first = evidence![0];
+      lines.push(`    ${capability.padEnd(9)} ${String(evidence!.length).padStart(4)} uses, e.g. ${first.api} ${chalk.dim(`${first.file}:${first.line}`)}`);
+    }
+  }
+
+  lines.push('');
+  const { summary, findings } = report.security;
+  lines.push(chalk.bold(`  Security findings: ${summary.total}`) + chalk.dim(` (${summary.critical} critical, ${summary.high} high, ${summary.medium} medium)`));
+  for (const finding of findings.slice(0, limit)) {
+    const color = SEVERITY_COLOR[finding.severity] ?? ((s: string) => s);
+    lines.push(`    ${color(finding.severity.padEnd(8))} ${finding.title} ${chalk.dim(`${finding.file}${finding.line ? `:${finding.line}` : ''}`)}`);
+  }
+  if (findings.length > limit) lines.push(chalk.dim(`    … ${findings.length - limit} more (--format json for all)`));
+  lines.push('');
+  lines.push(chalk.dim(`  Run any other depwire command against ${report.dir} for more detail.`));
+  lines.push('');
+  return lines.join('\n');
+}
+
+function print(report: RemoteAnalysis, options: AnalyzeCommandOptions): void {
+  if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatRemoteAnalysis(report, parseInt(options.limit || '10', 10)));
+  }
+}
+
+/** depwire analyze mod <module@version> — audit a module before depending on it */
+export async function analyzeModCommand(spec: string, options: AnalyzeCommandOptions): Promise<void> {
+  const report = await withInterrupt(async (signal) => {
+    log.info(`Fetching ${spec}...`);
+    const fetched = await fetchModule(spec, { proxy: options.proxy, cacheDir: options.cacheDir, signal });
+    const how = fetched.origin === 'direct' ? 'Downloaded from the repository' : fetched.cached ? 'Using cached' : 'Extracted';
+    log.info(`${how} ${fetched.path}@${fetched.version} (${fetched.dir})${fetched.verifiedSum ? `, ${fetched.verifiedSum} matches the checksum database` : ''}`);
+    return analyzeSource(fetched.dir, { module: fetched.path, version: fetched.version, time: fetched.time, origin: fetched.origin }, { signal });
+  });
+  print(report, options);
+}
+
+/** depwire analyze archive <file> — the same analysis for a module zip or tarball on disk, offline */
+export async function analyzeArchiveCommand(file: string, options: AnalyzeCommandOptions): Promise<void> {
+  const report = await withInterrupt(async (signal) => {
+    const opened = openArchive(resolve(file), { cacheDir: options.cacheDir, sum: options.sum });
+    log.info(`${opened.cached ? 'Using cached' : 'Extracted'} ${file} (${opened.dir})${opened.verifiedSum ? `, matches ${opened.verifiedSum}` : ''}`);
+    return analyzeSource(opened.dir, { module: opened.path, version: opened.version || null, origin: opened.origin }, { signal });
+  });
+  print(report, options);
+}
@@ -0,0 +1,34 @@
+import { resolve } from 'path';
+import { findProjectRoot } from '../utils/files.js';
+import { analyzeApiSurface } from '../api-surface/index.js';
+import { formatApiSurface } from '../api-surface/display.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ApiSurfaceCommandOptions extends OutputFlags {
+  package?: string;
+  unreferenced?: boolean;
+  format?: string;
+  limit?: string;
+}
+
+export async function apiSurfaceCommand(dir: string, options: ApiSurfaceCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await analyzeApiSurface(projectRoot, { package: options.package });
+
+  if (options.unreferenced) {
+    for (const pkg of report.packages) {
+      pkg.exports = pkg.exports.filter(s => s.externalRefs === 0);
+    }
+  }
+
+  answerQuietly(report.packages.some(p => p.exports.length > 0));
+
+  if (options.porcelain) {
+    // <package> <name> <kind> <receiver> <file> <line> <external refs> <test refs>
+    printPorcelain(report.packages.flatMap(p => p.exports.map(e => [p.importPath ?? p.dir, e.name, e.kind, e.receiver, e.file, e.line, e.externalRefs, e.testRefs])));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatApiSurface(report, parseInt(options.limit || '25', 10)));
+  }
+}
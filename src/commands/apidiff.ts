@@ -0,0 +1,39 @@
+import { resolve } from 'path';
+import { findProjectRoot } from '../utils/files.js';
+import { isGitRepo } from '../temporal/git.js';
+import { withInterrupt } from '../utils/progress.js';
+import { diffModuleApi } from '../apidiff/index.js';
+import { formatApiDiff } from '../apidiff/display.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ApiDiffCommandOptions extends OutputFlags {
+  dir?: string;
+  module?: string;
+  format?: string;
+  all?: boolean;
+}
+
+export async function apidiffCommand(from: string, to: string | undefined, options: ApiDiffCommandOptions): Promise<void> {
+  const projectRoot = !options.dir || options.dir === '.' ? findProjectRoot() : resolve(options.dir);
+  if (!isGitRepo(projectRoot)) {
+    throw new Error('Not a git repository — apidiff compares git refs');
+  }
+
+  const result = await withInterrupt((signal) => diffModuleApi(projectRoot, from, to, { module: options.module, signal }));
+
+  if (options.porcelain) {
+    // change <package> <name> <kind> <change> <compatible> <message>; consumer <package> <name> <consumer> <file> <line>
+    printPorcelain(result.changes.flatMap(c => [
+      ['change', c.package, c.name, c.kind, c.change, c.compatible, c.message],
+      ...c.consumers.map(u => ['consumer', c.package, c.name, u.package, u.file, u.line]),
+    ]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(result, null, 2));
+  } else {
+    console.log(formatApiDiff(result, Boolean(options.all)));
+  }
+
+  if (result.summary.incompatible > 0) {
+    process.exit(1);
+  }
+}
@@ -0,0 +1,89 @@
+import { resolve } from 'path';
+import { readFileSync } from 'fs';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { attestReport, verifyAttestation } from '../attest/index.js';
+import { getVersion } from './security.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('attest');
+
+export interface AttestFlags {
+  attest?: string;
+  attestKey?: string;
+  keyless?: boolean;
+}
+
+/** Write an attestation of a command's report when --attest was given */
+export async function attestIfRequested(projectRoot: string, command: string, report: unknown, options: AttestFlags): Promise<void> {
+  if (!options.attest) return;
+  const output = await attestReport(projectRoot, report, {
+    command,
+    version: getVersion(),
+    output: resolve(options.attest),
+    key: options.attestKey,
+    keyless: options.keyless,
+  });
+  log.info(`Attestation written to ${output}${options.keyless ? ' (Sigstore bundle)' : ''}`);
+}
+
+export interface AttestCreateOptions extends AttestFlags {
+  dir?: string;
+  command?: string;
+}
+
+/** depwire attest create <report.json> — attest a report saved from any --format json command */
+export async function attestCreateCommand(reportFile: string, options: AttestCreateOptions): Promise<void> {
+  const projectRoot = options.dir ? resolve(options.dir) : findProjectRoot();
+  const report = JSON.parse(readFileSync(resolve(reportFile), 'utf-8'));
+  await attestIfRequested(projectRoot, options.command ?? 'report', report, {
+    ...options,
+    attest: options.attest ?? `${reportFile.replace(/\.json$/, '')}.intoto.json`,
+  });
+}
+
+export interface AttestVerifyOptions {
+  key?: string;
+  trustedRoot?: string;
+  certificateIdentity?: string;
+  certificateIdentityRegexp?: string;
+  certificateOidcIssuer?: string;
+  commit?: string;
+  format?: string;
+}
+
+export async function attestVerifyCommand(file: string, options: AttestVerifyOptions): Promise<void> {
+  const result = verifyAttestation(resolve(file), {
+    publicKey: options.key ? resolve(options.key) : undefined,
+    trustedRoot: options.trustedRoot ? resolve(options.trustedRoot) : undefined,
+    certificateIdentity: options.certificateIdentity,
+    certificateIdentityRegexp: options.certificateIdentityRegexp,
+    certificateOidcIssuer: options.certificateOidcIssuer,
+    commit: options.commit,
+  });
+  const { predicate } = result.statement;
+  // A self-consistent keyless bundle is easy to forge; only a checked one passes
+  if (result.verification === 'unverified') process.exitCode = 1;
+
+  if (options.format === 'json') {
+    console.log(JSON.stringify(result, null, 2));
+    return;
+  }
+  console.log('');
+  if (result.verification === 'unverified') {
+    console.log(chalk.yellow(`⚠ Unverified depwire ${predicate.depwire.command} attestation`));
+    console.log(chalk.yellow('  The bundle is signed by its own certificate, but the certificate chain and Rekor entry were not checked.'));
+    console.log(chalk.yellow('  Pass --trusted-root (from `gh attestation trusted-root`) with --certificate-identity and --certificate-oidc-issuer, or --key, to verify it.'));
+  } else {
+    console.log(chalk.green(`✓ Valid depwire ${predicate.depwire.command} attestation`));
+  }
+  const how = result.verification === 'sigstore' ? ' (Sigstore certificate, chain, identity and Rekor entry verified)'
+    : result.keyless && result.verification === 'unverified' ? ' (Sigstore certificate, unverified)' : '';
+  console.log(`  Signed by:  ${result.signer}${chalk.dim(how)}`);
+  if (result.logIndex !== null) console.log(`  Rekor:      log index ${result.logIndex}`);
+  console.log(`  Commit:     ${predicate.source.commit ?? '(not a git checkout)'}${predicate.source.dirty ? chalk.yellow(' with uncommitted changes') : ''}`);
+  if (predicate.source.repository) console.log(`  Repository: ${predicate.source.repository}`);
+  if (predicate.ci) console.log(`  CI:         ${predicate.ci.provider}${predicate.ci.runUrl ? ` ${predicate.ci.runUrl}` : ''}`);
+  console.log(`  Analyzed:   ${predicate.analyzedAt} with depwire ${predicate.depwire.version}`);
+  console.log('');
+}
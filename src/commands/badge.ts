@@ -0,0 +1,70 @@
+import { resolve } from 'path';
+import { writeFileSync } from 'fs';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { findLintConfig } from '../rules/config.js';
+import { graphMetrics } from '../temporal/history.js';
+import { dependencyHealth, parseHealthWeights } from '../health/dependency.js';
+import { renderBadge, scoreColor, type Badge } from '../export/badge.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('badge');
+
+export interface BadgeCommandOptions extends FilterOptions {
+  metric?: string;
+  output?: string;
+  label?: string;
+  /** Health weights, e.g. cycles=0.5 modules=0.1 */
+  weight?: string[];
+  config?: string;
+}
+
+const METRICS = ['deps', 'cycles', 'health'];
+
+// "cycles=0.5" → { cycles: 0.5 }
+function parseWeightFlags(values: string[]): Record<string, number> {
+  const weights: Record<string, number> = {};
+  for (const value of values.flatMap(v => v.split(','))) {
+    const [name, weight] = value.split('=');
+    if (!name || weight === undefined || Number.isNaN(parseFloat(weight))) {
+      throw new Error(`Invalid --weight "${value}" (expected <component>=<weight>)`);
+    }
+    weights[name.trim()] = parseFloat(weight);
+  }
+  return weights;
+}
+
+export async function badgeCommand(dir: string, options: BadgeCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const metric = options.metric ?? 'health';
+  if (!METRICS.includes(metric)) throw new Error(`Unknown metric "${metric}" (expected ${METRICS.join(', ')})`);
+  const config = findLintConfig(projectRoot, options.config ? resolve(options.config) : undefined);
+  // Flags override the config's weights one component at a time
+  const weights = { ...config.healthWeights, ...parseHealthWeights(parseWeightFlags(options.weight ?? []), '--weight') };
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+
+  let badge: Badge;
+  if (metric === 'health') {
+    const health = dependencyHealth(graph, projectRoot, { weights, moduleAllowance: config.budgets?.maxDirectModules });
+    badge = { label: 'dependency health', message: `${health.score} ${health.grade}`, color: scoreColor(health.score) };
+  } else {
+    const metrics = graphMetrics(graph, projectRoot);
+    badge = metric === 'deps'
+      ? { label: 'dependencies', message: `${metrics.directModules} direct`, color: 'blue' }
+      : { label: 'import cycles', message: `${metrics.cycles}`, color: metrics.cycles === 0 ? 'brightgreen' : metrics.cycles < 3 ? 'orange' : 'red' };
+  }
+  if (options.label) badge.label = options.label;
+
+  const svg = renderBadge(badge);
+  if (options.output) {
+    writeFileSync(resolve(options.output), svg);
+    log.info(`Wrote ${badge.label}: ${badge.message} badge to ${options.output}`);
+  } else {
+    process.stdout.write(svg);
+  }
+}
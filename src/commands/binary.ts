@@ -0,0 +1,151 @@
+import { readFileSync } from 'fs';
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { withInterrupt } from '../utils/progress.js';
+import { findProjectRoot } from '../utils/files.js';
+import {
+  analyzeBinary, compareBinaryReports, readBinaryHistory, recordBinarySize, BINARY_HISTORY_FILE,
+  type BinaryComparison, type BinaryReport, type BinarySizeRecord, type SizeChange,
+} from '../golang/binary.js';
+import { formatSize } from '../export/treemap.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface BinaryCommandOptions extends OutputFlags {
+  packages?: boolean;
+  baseline?: string;
+  record?: boolean;
+  trend?: boolean;
+  dir?: string;
+  format?: string;
+  limit?: string;
+}
+
+const size = (bytes: number) => formatSize('binsize', bytes);
+const signed = (bytes: number) => `${bytes >= 0 ? '+' : '−'}${size(Math.abs(bytes))}`;
+
+function formatBinaryReport(report: BinaryReport, limit: number, packages: boolean): string {
+  const lines: string[] = [];
+  const [main, ...deps] = report.modules;
+  lines.push('');
+  lines.push(chalk.bold('Depwire Binary Dependencies'));
+  const totals = report.stripped ? '' : `, ${size(report.total)} of symbols`;
+  lines.push(chalk.dim(`  ${report.binary}: ${report.path || main.path} built with ${report.goVersion}, ${deps.length} modules${totals}`));
+  const revision = report.settings['vcs.revision'];
+  if (revision) lines.push(chalk.dim(`  from ${revision.slice(0, 12)}${report.settings['vcs.modified'] === 'true' ? ' (modified)' : ''}${report.settings['vcs.time'] ? ` at ${report.settings['vcs.time']}` : ''}`));
+  lines.push('');
+
+  const share = (bytes: number) => report.total > 0 ? chalk.dim(` ${(100 * bytes / report.total).toFixed(1).padStart(5)}%`) : '';
+  const row = (name: string, version: string, bytes: number, note = '') =>
+    `  ${report.stripped ? '' : `${size(bytes).padStart(10)}${share(bytes)}  `}${chalk.bold(name)} ${chalk.dim(version)}${note}`;
+
+  lines.push(row(main.path, main.version, main.size, chalk.dim(' (main module)')));
+  for (const mod of deps.slice(0, limit)) {
+    const replace = mod.replace ? chalk.yellow(` => ${mod.replace.path}${mod.replace.version ? ` ${mod.replace.version}` : ''}`) : '';
+    lines.push(row(mod.path, mod.version, mod.size, replace));
+  }
+  if (deps.length > limit) lines.push(chalk.dim(`  … ${deps.length - limit} more (use --limit or --format json)`));
+  if (!report.stripped) {
+    lines.push(row('standard library', `${report.std.packages} packages`, report.std.size));
+    if (report.unattributed > 0) lines.push(row('other', 'linker data, cgo', report.unattributed));
+  }
+  lines.push('');
+
+  if (packages && !report.stripped) {
+    lines.push(chalk.bold('  Largest packages'));
+    for (const pkg of report.packages.slice(0, limit)) {
+      lines.push(`  ${size(pkg.size).padStart(10)}${share(pkg.size)}  ${pkg.path}${pkg.module === null ? chalk.dim(' (std)') : ''}`);
+    }
+    if (report.packages.length > limit) lines.push(chalk.dim(`  … ${report.packages.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  for (const warning of report.warnings) lines.push(chalk.yellow(`  ⚠ ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+function formatComparison(comparison: BinaryComparison, limit: number, packages: boolean): string {
+  const lines: string[] = [];
+  const { before, after } = comparison;
+  const percent = before.total > 0 ? ` (${(100 * comparison.delta / before.total).toFixed(1)}%)` : '';
+  lines.push(chalk.bold('  Since the baseline'));
+  lines.push(chalk.dim(`  ${before.binary}${before.revision ? ` at ${before.revision.slice(0, 12)}` : ''}: ${size(before.total)} → ${size(after.total)}, ${signed(comparison.delta)}${percent}`));
+  const row = (c: SizeChange) => {
+    const text = `  ${signed(c.delta).padStart(11)}  ${c.path}${chalk.dim(`  ${size(c.before)} → ${size(c.after)}`)}`;
+    return c.delta > 0 ? chalk.red(text) : chalk.green(text);
+  };
+  for (const change of comparison.modules.slice(0, limit)) lines.push(row(change));
+  if (comparison.modules.length > limit) lines.push(chalk.dim(`  … ${comparison.modules.length - limit} more (use --limit or --format json)`));
+  if (comparison.added.length > 0) lines.push(chalk.yellow(`  New dependencies: ${comparison.added.join(', ')}`));
+  if (comparison.removed.length > 0) lines.push(chalk.dim(`  Dropped dependencies: ${comparison.removed.join(', ')}`));
+  lines.push('');
+  if (packages) {
+    lines.push(chalk.bold('  Packages that grew most'));
+    for (const change of comparison.packages.filter(c => c.delta > 0).slice(0, limit)) lines.push(row(change));
+    lines.push('');
+  }
+  return lines.join('\n');
+}
+
+function formatTrend(history: BinarySizeRecord[], limit: number): string {
+  const lines: string[] = [];
+  lines.push(chalk.bold(`  Recorded builds (${BINARY_HISTORY_FILE})`));
+  const shown = history.slice(-limit);
+  shown.forEach((record, i) => {
+    const previous = i > 0 ? shown[i - 1] : history[history.length - shown.length - 1];
+    const change = previous ? chalk.dim(`  ${signed(record.total - previous.total)}`) : '';
+    lines.push(`  ${record.recordedAt.slice(0, 10)}  ${(record.revision ?? '').slice(0, 12).padEnd(12)}  ${size(record.total).padStart(10)}${change}`);
+  });
+  if (history.length >= 2) {
+    const [first, last] = [history[0], history[history.length - 1]];
+    const growth = Object.keys({ ...first.modules, ...last.modules })
+      .map(path => ({ path, delta: (last.modules[path] ?? 0) - (first.modules[path] ?? 0) }))
+      .filter(g => g.delta > 0)
+      .sort((a, b) => b.delta - a.delta)
+      .slice(0, 5);
+    if (growth.length > 0) {
+      lines.push(chalk.dim(`  Grew most since ${first.recordedAt.slice(0, 10)}: ${growth.map(g => `${g.path} ${signed(g.delta)}`).join(', ')}`));
+    }
+  }
+  lines.push('');
+  return lines.join('\n');
+}
+
+// A baseline is an older binary, or the --format json report of one
+async function loadBaseline(file: string, signal: AbortSignal): Promise<BinaryReport> {
+  const head = readFileSync(file).subarray(0, 1).toString();
+  if (head !== '{') return analyzeBinary(file, { signal });
+  const report = JSON.parse(readFileSync(file, 'utf-8')) as BinaryReport;
+  if (!Array.isArray(report.modules) || typeof report.total !== 'number') throw new Error(`${file} is not a depwire binary --format json report`);
+  return report;
+}
+
+export async function binaryCommand(binary: string, options: BinaryCommandOptions): Promise<void> {
+  const { report, baseline } = await withInterrupt(async (signal) => ({
+    report: await analyzeBinary(binary, { signal }),
+    baseline: options.baseline ? await loadBaseline(options.baseline, signal) : null,
+  }));
+  const comparison = baseline ? compareBinaryReports(baseline, report) : null;
+  if (comparison && (baseline!.stripped || report.stripped)) {
+    report.warnings.push('A build without a symbol table has no sizes to compare; only the module lists are');
+  }
+  const projectRoot = options.dir ? resolve(options.dir) : findProjectRoot();
+  const history = options.record ? recordBinarySize(projectRoot, report)
+    : options.trend ? readBinaryHistory(projectRoot).filter(r => r.path === report.path) : null;
+
+  if (options.porcelain) {
+    // <module> <version> <size> <packages> <main> <replacement> <replacement version>; sizes in bytes, empty when stripped
+    printPorcelain(report.modules.map(m => [
+      m.path, m.version, report.stripped ? null : m.size, m.packages, m.main, m.replace?.path, m.replace?.version,
+    ]));
+  } else if (options.format === 'json') {
+    // The plain report unless there's more, so it can serve as a later --baseline
+    console.log(JSON.stringify(comparison || options.trend ? { ...report, comparison, history: options.trend ? history : undefined } : report, null, 2));
+  } else {
+    const limit = parseInt(options.limit ?? '30', 10);
+    console.log(formatBinaryReport(report, limit, Boolean(options.packages)));
+    if (comparison) console.log(formatComparison(comparison, limit, Boolean(options.packages)));
+    if (options.trend && history) console.log(formatTrend(history, limit));
+  }
+  answerQuietly(report.modules.length > 1);
+}
@@ -0,0 +1,72 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeBuildConfigurations, type BuildConfigReport } from '../golang/buildtags.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface BuildConfigsCommandOptions extends OutputFlags {
+  tags?: string[];
+  all?: boolean;
+  package?: string;
+  format?: string;
+  limit?: string;
+}
+
+function formatBuildConfigReport(report: BuildConfigReport, limit: number): string {
+  const lines: string[] = [];
+  const { tags } = report;
+  lines.push('');
+  lines.push(chalk.bold('Depwire Build Configurations'));
+  const mentioned = [...tags.goos, ...tags.goarch, ...tags.custom];
+  lines.push(chalk.dim(`  ${report.configurations.length} distinct configurations${mentioned.length > 0 ? ` from ${mentioned.join(', ')}` : ''}`));
+  lines.push('');
+
+  for (const c of report.configurations) {
+    lines.push(`  ${c.id.padEnd(32)} ${chalk.dim(`${c.files} conditional files`)}`);
+  }
+  lines.push('');
+
+  if (report.imports.length === 0) {
+    lines.push(chalk.green('  ✓ Every import is active in every configuration'));
+    lines.push('');
+  } else {
+    lines.push(chalk.bold('Conditional imports'));
+    for (const imp of report.imports.slice(0, limit)) {
+      const blank = imp.blank ? chalk.yellow(' _') : '';
+      const active = imp.always ? 'always' : `${imp.configurations.length}/${report.configurations.length}`;
+      lines.push(`  ${imp.from} → ${chalk.bold(imp.to)}${blank}  ${chalk.cyan(imp.condition ?? 'unconditional')}` + chalk.dim(`  ${active}`));
+    }
+    if (report.imports.length > limit) lines.push(chalk.dim(`  … ${report.imports.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+    if (report.imports.some(i => i.blank)) {
+      lines.push(chalk.dim('_ = blank import, for the package\'s init'));
+      lines.push('');
+    }
+  }
+
+  for (const warning of report.warnings) lines.push(chalk.yellow(`⚠ ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export async function buildConfigsCommand(dir: string, options: BuildConfigsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const tags = (options.tags ?? []).flatMap(v => v.split(',')).map(v => v.trim()).filter(Boolean);
+  const report = await withInterrupt(() => analyzeBuildConfigurations(projectRoot, { tags, all: options.all }));
+
+  if (options.package) {
+    // A suffix of the import path will do
+    report.imports = report.imports.filter(i => i.from === options.package || i.from.endsWith(`/${options.package}`));
+  }
+  answerQuietly(report.imports.some(i => !i.always));
+
+  if (options.porcelain) {
+    // <from> <to> <blank> <condition, empty when unconditional> <configurations, space-separated>
+    printPorcelain(report.imports.map(i => [i.from, i.to, i.blank, i.condition, i.configurations.join(' ')]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatBuildConfigReport(report, parseInt(options.limit ?? '40', 10)));
+  }
+}
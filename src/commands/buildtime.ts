@@ -0,0 +1,61 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { withInterrupt } from '../utils/progress.js';
+import { findProjectRoot } from '../utils/files.js';
+import { attributeBuildTime, measureBuild, readActionGraphs, type BuildTimeReport } from '../golang/buildtime.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface BuildTimeCommandOptions extends OutputFlags {
+  actiongraph?: string[];
+  all?: boolean;
+  format?: string;
+  limit?: string;
+}
+
+const seconds = (ms: number) => ms >= 1000 ? `${(ms / 1000).toFixed(1)}s` : `${Math.round(ms)}ms`;
+
+function formatBuildTime(report: BuildTimeReport, limit: number, all: boolean): string {
+  const lines: string[] = [];
+  const own = report.packages.filter(p => p.dir !== null);
+  const shown = all ? report.packages : own;
+  lines.push('');
+  lines.push(chalk.bold('Depwire Build Time'));
+  lines.push(chalk.dim(`  ${seconds(report.wall)} wall, ${seconds(report.compile)} compiling ${report.packages.length} packages (${seconds(report.workspace)} in ${own.length} of the project's), ${seconds(report.link)} linking ${report.binaries} binaries`));
+  if (report.correlation !== null) lines.push(chalk.dim(`  fan-in vs rebuild cost: ρ = ${report.correlation.toFixed(2)}`));
+  lines.push('');
+
+  if (shown.length > 0) {
+    lines.push(chalk.bold(`  ${'rebuild'.padStart(8)}  ${'compile'.padStart(8)}  ${'fan-in'.padStart(6)}  ${'deps'.padStart(5)}  package`));
+    const total = all ? report.compile : report.workspace;
+    for (const pkg of shown.slice(0, limit)) {
+      const share = total > 0 ? chalk.dim(` (${Math.round(100 * Math.min(pkg.rebuild, total) / total)}%)`) : '';
+      const name = pkg.dir === null ? chalk.dim(pkg.package) : pkg.dir;
+      lines.push(`  ${seconds(pkg.rebuild).padStart(8)}  ${(pkg.cached ? 'cached' : seconds(pkg.compile)).padStart(8)}  ${String(pkg.fanIn).padStart(6)}  ${String(pkg.dependents).padStart(5)}  ${name}${share}`);
+    }
+    if (shown.length > limit) lines.push(chalk.dim(`  … ${shown.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  for (const warning of report.warnings) lines.push(chalk.yellow(`  ⚠ ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+/** depwire buildtime [dir] — compile time per package and what a change to each one rebuilds */
+export async function buildtimeCommand(dir: string | undefined, options: BuildTimeCommandOptions): Promise<void> {
+  const projectRoot = dir ? resolve(dir) : findProjectRoot();
+  const report = options.actiongraph?.length
+    ? attributeBuildTime(projectRoot, readActionGraphs(options.actiongraph.map(f => resolve(f))))
+    : await withInterrupt((signal) => measureBuild(projectRoot, { signal }));
+  const packages = options.all ? report.packages : report.packages.filter(p => p.dir !== null);
+
+  if (options.porcelain) {
+    // <package> <compile ms> <rebuild ms> <fan-in> <dependents>; compile is empty for cached packages
+    printPorcelain(packages.map(p => [p.package, p.cached ? '' : Math.round(p.compile), Math.round(p.rebuild), p.fanIn, p.dependents]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify({ ...report, packages }, null, 2));
+  } else {
+    console.log(formatBuildTime(report, parseInt(options.limit ?? '20', 10), !!options.all));
+  }
+  answerQuietly(packages.length > 0);
+}
@@ -0,0 +1,56 @@
+import { readFileSync } from 'fs';
+import { resolve } from 'path';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeCapabilities } from '../capabilities/index.js';
+import { observeCapabilities, parseStraceLog, traceCommand } from '../capabilities/observe.js';
+import { formatCapabilityMatrix, formatCapabilityDetails, formatCapabilityObservation } from '../capabilities/display.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface CapabilitiesCommandOptions extends OutputFlags {
+  module?: string;
+  all?: boolean;
+  observe?: string;
+  run?: string;
+  format?: string;
+}
+
+export async function capabilitiesCommand(dir: string, options: CapabilitiesCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeCapabilities(projectRoot, { signal }));
+
+  const modules = options.module ? report.modules.filter(m => m.module === options.module) : report.modules;
+
+  if (options.observe || options.run) {
+    const log = options.observe
+      ? readFileSync(resolve(options.observe), 'utf-8')
+      : await withInterrupt((signal) => traceCommand(options.run!, { cwd: projectRoot, signal }));
+    const observation = observeCapabilities(report, parseStraceLog(log));
+    if (options.module) observation.modules = observation.modules.filter(m => m.module === options.module);
+    if (options.porcelain) {
+      // observed <module> <version> <capability> <confirmed|unexpected|unexercised> <syscalls>
+      printPorcelain(observation.modules.flatMap(m => m.capabilities.map(c => ['observed', m.module, m.version, c.capability, c.status, c.count])));
+    } else if (options.format === 'json') {
+      console.log(JSON.stringify({ ...report, modules, observation }, null, 2));
+    } else {
+      console.log(formatCapabilityObservation(observation));
+    }
+    answerQuietly(observation.modules.some(m => m.capabilities.some(c => c.status === 'unexpected')));
+    return;
+  }
+
+  if (options.porcelain) {
+    // capability <module> <version> <capability> <via>, via empty for the module's own use
+    printPorcelain(modules.flatMap(m => [
+      ...m.direct.map(c => ['capability', m.module, m.version, c, null]),
+      ...m.transitive.map(t => ['capability', m.module, m.version, t.capability, t.via]),
+    ]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify({ ...report, modules }, null, 2));
+  } else if (options.module) {
+    console.log(formatCapabilityDetails(report, options.module));
+  } else {
+    console.log(formatCapabilityMatrix(report, { all: Boolean(options.all) }));
+  }
+  answerQuietly(modules.some(m => m.direct.length > 0 || m.transitive.length > 0));
+}
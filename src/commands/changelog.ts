@@ -0,0 +1,60 @@
+import { resolve } from 'path';
+import { writeFileSync } from 'fs';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { captureBaseline } from '../drift/index.js';
+import { isGitRepo, withWorktree } from '../temporal/git.js';
+import { parseReleaseRange, dependencyChangelog, formatChangelogMarkdown } from '../changelog/index.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('changelog');
+
+export interface ChangelogCommandOptions extends FilterOptions {
+  proxy?: string;
+  cacheDir?: string;
+  /** --no-licenses: skip fetching added and changed modules for their licenses */
+  licenses?: boolean;
+  /** --no-vulns: skip the OSV query */
+  vulns?: boolean;
+  format?: string;
+  output?: string;
+}
+
+/** depwire changelog <from>..<to> — dependency changes between two releases */
+export async function changelogCommand(range: string, dir: string, options: ChangelogCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const format = options.format ?? 'markdown';
+  if (format !== 'markdown' && format !== 'json') throw new Error(`Unknown format "${format}" (expected markdown or json)`);
+  if (!isGitRepo(projectRoot)) throw new Error(`${projectRoot} is not a git repository`);
+  const refs = parseReleaseRange(range);
+
+  const capture = (ref: string) => withWorktree(projectRoot, ref, async (worktree) => {
+    log.info(`Analyzing ${ref}...`);
+    const filter = createFilter(worktree, options);
+    return captureBaseline(buildGraph(await parseWithProgress(worktree, { filter: filter.includesFile }), worktree, graphOptions()), worktree);
+  });
+  const before = await capture(refs.from);
+  const after = await capture(refs.to);
+
+  const changelog = await withInterrupt((signal) => {
+    if (options.licenses !== false || options.vulns !== false) log.info('Checking licenses and known vulnerabilities...');
+    return dependencyChangelog(before, after, refs, {
+      proxy: options.proxy,
+      cacheDir: options.cacheDir,
+      licenses: options.licenses,
+      vulnerabilities: options.vulns,
+      signal,
+    });
+  });
+
+  const output = format === 'json' ? JSON.stringify(changelog, null, 2) + '\n' : formatChangelogMarkdown(changelog);
+  if (options.output) {
+    writeFileSync(options.output, output);
+    log.info(`Wrote dependency changelog to ${options.output}`);
+  } else {
+    console.log(output);
+  }
+}
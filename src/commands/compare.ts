@@ -0,0 +1,134 @@
+import { existsSync, readFileSync, writeFileSync } from 'fs';
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { withInterrupt } from '../utils/progress.js';
+import { compareModuleVersions, formatModuleComparisonMarkdown } from '../remote/compare.js';
+import { compareWithTraces, parseTrace, type TraceComparison } from '../golang/trace.js';
+import { importFromJSON } from '../graph/serializer.js';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('compare');
+
+export interface CompareModCommandOptions {
+  proxy?: string;
+  cacheDir?: string;
+  format?: string;
+  output?: string;
+  /** --no-licenses: skip fetching new and upgraded modules for their licenses */
+  licenses?: boolean;
+  failOn?: string;
+}
+
+const RISK_ORDER = ['low', 'medium', 'high'];
+
+/** depwire compare mod <module@from> <module@to> — the risk summary of an upgrade */
+export async function compareModCommand(from: string, to: string, options: CompareModCommandOptions): Promise<void> {
+  const format = options.format ?? 'markdown';
+  if (format !== 'markdown' && format !== 'json') throw new Error(`Unknown format "${format}" (expected markdown or json)`);
+  if (options.failOn && !RISK_ORDER.includes(options.failOn)) throw new Error(`Unknown risk "${options.failOn}" (expected low, medium or high)`);
+
+  const comparison = await withInterrupt((signal) => {
+    log.info(`Fetching ${from} and ${to}...`);
+    return compareModuleVersions(from, to, { proxy: options.proxy, cacheDir: options.cacheDir, licenses: options.licenses, signal });
+  });
+
+  const output = format === 'json' ? JSON.stringify(comparison, null, 2) + '\n' : formatModuleComparisonMarkdown(comparison);
+  if (options.output) {
+    writeFileSync(options.output, output);
+    log.info(`Wrote upgrade summary to ${options.output}`);
+  } else {
+    console.log(output);
+  }
+  if (options.failOn && RISK_ORDER.indexOf(comparison.risk.level) >= RISK_ORDER.indexOf(options.failOn)) {
+    process.exit(1);
+  }
+}
+
+export interface CompareTracesCommandOptions extends OutputFlags {
+  /** Graph saved with depwire parse; the project is parsed without one */
+  static?: string;
+  dynamic?: string[];
+  dir?: string;
+  format?: string;
+  limit?: string;
+}
+
+function formatTraceComparison(comparison: TraceComparison, limit: number): string {
+  const lines: string[] = [];
+  const samples = comparison.traces.reduce((n, t) => n + t.samples, 0);
+  const share = comparison.staticCalls > 0 ? Math.round(100 * comparison.exercised / comparison.staticCalls) : 0;
+  lines.push('');
+  lines.push(chalk.bold('Depwire Static vs. Runtime Calls'));
+  lines.push(chalk.dim(`  ${comparison.traces.length} traces, ${samples} samples: ${comparison.exercised} of ${comparison.staticCalls} cross-package calls exercised (${share}%), ${comparison.missed.length} only seen at runtime`));
+  lines.push('');
+
+  lines.push(chalk.bold('  Missed by static analysis'));
+  if (comparison.missed.length === 0) lines.push(chalk.green('  ✓ None: every call the traces saw is in the static graph'));
+  for (const edge of comparison.missed.slice(0, limit)) {
+    lines.push(`  ${edge.source} → ${chalk.bold(edge.target)}${edge.via ? chalk.dim(` via ${edge.via}`) : ''}` + chalk.dim(`  ${edge.count}×`));
+  }
+  if (comparison.missed.length > limit) lines.push(chalk.dim(`  … ${comparison.missed.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  lines.push(chalk.bold('  Least exercised packages'));
+  const packages = comparison.packages.filter(p => p.exercised < p.calls);
+  for (const pkg of packages.slice(0, limit)) {
+    const note = pkg.seen ? '' : chalk.yellow('  never ran');
+    lines.push(`  ${`${pkg.exercised}/${pkg.calls}`.padStart(9)}  ${pkg.package}${note}`);
+  }
+  if (packages.length > limit) lines.push(chalk.dim(`  … ${packages.length - limit} more (use --limit or --format json)`));
+  if (packages.length === 0) lines.push(chalk.green('  ✓ Every static cross-package call ran'));
+  lines.push('');
+
+  if (comparison.unexercised.length > 0) {
+    lines.push(chalk.bold('  Never exercised (dead weight candidates)'));
+    for (const edge of comparison.unexercised.slice(0, limit)) {
+      lines.push(`  ${edge.source} → ${edge.target}` + chalk.dim(`  ${edge.filePath}:${edge.line}`));
+    }
+    if (comparison.unexercised.length > limit) lines.push(chalk.dim(`  … ${comparison.unexercised.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  if (comparison.unmatched.length > 0) {
+    lines.push(chalk.yellow(`  ⚠ ${comparison.unmatched.length} traced calls match no symbols of the graph (other modules, generic code, or source that changed since the trace)`));
+  }
+  lines.push(chalk.dim('  Traces sample stacks: a call that never showed up may be too quick to catch, not unused'));
+  lines.push('');
+  return lines.join('\n');
+}
+
+/** depwire compare --static graph.json --dynamic trace.json — the static graph against runtime traces */
+export async function compareTracesCommand(options: CompareTracesCommandOptions): Promise<void> {
+  if (!options.dynamic?.length) throw new Error('depwire compare needs --dynamic <trace.json> (or use depwire compare mod)');
+  const traces = options.dynamic.map(file => ({ file, trace: parseTrace(readFileSync(resolve(file), 'utf-8'), file) }));
+
+  let projectRoot: string;
+  let graph;
+  if (options.static) {
+    const json = JSON.parse(readFileSync(resolve(options.static), 'utf-8'));
+    // The go.mod files map the traces' import paths onto the graph's files
+    projectRoot = options.dir ? resolve(options.dir) : json.projectRoot && existsSync(json.projectRoot) ? json.projectRoot : findProjectRoot();
+    graph = importFromJSON(json);
+  } else {
+    projectRoot = options.dir ? resolve(options.dir) : findProjectRoot();
+    graph = buildGraph(await parseWithProgress(projectRoot), projectRoot, graphOptions());
+  }
+  const comparison = compareWithTraces(projectRoot, graph, traces);
+
+  if (options.porcelain) {
+    // unexercised <source> <target> <file> <line>; missed <source> <target> <count> <via>
+    printPorcelain([
+      ...comparison.unexercised.map(e => ['unexercised', e.source, e.target, e.filePath, e.line]),
+      ...comparison.missed.map(e => ['missed', e.source, e.target, e.count, e.via]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(comparison, null, 2));
+  } else {
+    console.log(formatTraceComparison(comparison, parseInt(options.limit ?? '20', 10)));
+  }
+  answerQuietly(comparison.unexercised.length > 0 || comparison.missed.length > 0);
+}
@@ -0,0 +1,19 @@
+import type { Command } from 'commander';
+import { findProjectRoot } from '../utils/files.js';
+import { complete } from '../completion/index.js';
+import { completionScript, COMPLETION_SHELLS, type CompletionShell } from '../completion/scripts.js';
+
+export function completionCommand(shell: string): void {
+  if (!COMPLETION_SHELLS.includes(shell as CompletionShell)) {
+    throw new Error(`Unknown shell "${shell}" (expected ${COMPLETION_SHELLS.join(', ')})`);
+  }
+  process.stdout.write(completionScript(shell as CompletionShell));
+}
+
+/** Print candidates for the completion scripts; never fails, since errors would land in the prompt */
+export function completeCommand(program: Command, words: string[]): void {
+  try {
+    const candidates = complete(program, words.length > 0 ? words : [''], findProjectRoot());
+    if (candidates.length > 0) process.stdout.write(`${candidates.join('\n')}\n`);
+  } catch { /* no candidates */ }
+}
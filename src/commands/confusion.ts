@@ -0,0 +1,71 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeConfusion, type ConfusionReport, type ConfusionRisk } from '../supply-chain/confusion.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ConfusionCommandOptions extends OutputFlags {
+  private?: string[];
+  proxy?: string;
+  offline?: boolean;
+  format?: string;
+}
+
+const RISK_COLOR: Record<ConfusionRisk, (s: string) => string> = {
+  critical: chalk.red.bold,
+  high: chalk.red,
+  medium: chalk.yellow,
+  low: chalk.dim,
+};
+
+function formatConfusionReport(report: ConfusionReport): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Dependency Confusion Check'));
+  lines.push(chalk.dim(`  Private prefixes: ${report.prefixes.join(', ')}`));
+  lines.push(chalk.dim(`  GOPROXY=${report.env.GOPROXY}  GONOPROXY=${report.env.GONOPROXY || '(empty)'}  GONOSUMDB=${report.env.GONOSUMDB || '(empty)'}`));
+  if (!report.checkedProxy) lines.push(chalk.dim('  Offline — public proxy not queried'));
+  lines.push('');
+
+  if (report.findings.length === 0) {
+    lines.push(chalk.green('  ✓ No internal modules at risk'));
+  }
+  for (const f of report.findings) {
+    const location = f.line ? `${f.goModFile}:${f.line}` : f.goModFile;
+    lines.push(`  ${RISK_COLOR[f.risk](f.risk.toUpperCase().padEnd(8))} ${chalk.bold(f.module)}  ${chalk.dim(location)}`);
+    lines.push(`           ${f.message}`);
+    if (f.publicVersions?.length) {
+      const shown = f.publicVersions.slice(-5).join(', ');
+      lines.push(chalk.dim(`           public versions: ${shown}${f.publicVersions.length > 5 ? ` (+${f.publicVersions.length - 5} more)` : ''}`));
+    }
+  }
+  for (const warning of report.warnings) {
+    lines.push(chalk.yellow(`  ⚠ ${warning}`));
+  }
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function confusionCommand(dir: string, options: ConfusionCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeConfusion(projectRoot, {
+    prefixes: options.private,
+    proxy: options.proxy,
+    offline: Boolean(options.offline),
+    signal,
+  }));
+
+  if (options.porcelain) {
+    // <risk> <module> <source> <goModFile> <line> <message>
+    printPorcelain(report.findings.map(f => [f.risk, f.module, f.source, f.goModFile, f.line, f.message]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatConfusionReport(report));
+  }
+
+  if (report.findings.some(f => f.risk === 'critical' || f.risk === 'high')) {
+    process.exit(1);
+  }
+}
@@ -0,0 +1,78 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeContextPropagation, type ContextReport } from '../golang/contexts.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ContextsCommandOptions extends OutputFlags {
+  package?: string;
+  algo?: string;
+  format?: string;
+  limit?: string;
+}
+
+function formatContextReport(report: ContextReport, limit: number): string {
+  const lines: string[] = [];
+  const { summary } = report;
+  lines.push('');
+  lines.push(chalk.bold('Depwire Context Propagation'));
+  lines.push(chalk.dim(`  ${summary.functions} functions, ${summary.withContext} with a context, ${summary.creators} creating one without`));
+  lines.push('');
+
+  if (report.findings.length === 0) {
+    lines.push(chalk.green('  ✓ No context is dropped across a package boundary'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  for (const f of report.findings.slice(0, limit)) {
+    const icon = f.approximate ? chalk.yellow('?') : chalk.red('✗');
+    lines.push(`${icon} ${f.caller} → ${chalk.bold(`${f.call}()`)}` + chalk.dim(`  ${f.file}:${f.line}`));
+    lines.push(chalk.dim(`    across ${f.packages.join(' → ')}`));
+    for (const [i, s] of f.chain.entries()) {
+      const note = i === 0 ? 'has a context, calls' : i === f.chain.length - 1 ? `calls ${f.call}()` : 'takes no context, calls';
+      lines.push(`    ${chalk.dim(`${s.file}:${s.line}`.padEnd(36))} ${s.function}` + chalk.dim(`  ${note}`));
+    }
+    lines.push('');
+  }
+  if (report.findings.length > limit) {
+    lines.push(chalk.dim(`  … ${report.findings.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  lines.push(`${report.findings.length} dropped context${report.findings.length === 1 ? '' : 's'}`);
+  if (report.findings.some(f => f.approximate)) {
+    lines.push(chalk.dim('? = relies on matching a method by name; the receiver type was not visible'));
+  }
+  for (const warning of report.warnings) lines.push(chalk.yellow(`⚠ ${warning}`));
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function contextsCommand(dir: string, options: ContextsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeContextPropagation(projectRoot, { algo: options.algo, signal }));
+
+  if (options.package) {
+    // Chains passing through the package; a suffix of the import path will do
+    const involves = (pkg: string) => pkg === options.package || pkg.endsWith(`/${options.package}`);
+    report.findings = report.findings.filter(f => f.packages.some(involves));
+  }
+
+  if (options.porcelain) {
+    // <file> <line> <call> <caller> <creator> <approximate> <chain, space-separated>
+    printPorcelain(report.findings.map(f => [f.file, f.line, f.call, f.caller, f.creator, f.approximate, f.chain.map(s => s.function).join(' ')]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatContextReport(report, parseInt(options.limit ?? '20', 10)));
+  }
+
+  answerQuietly(report.findings.length > 0);
+
+  // Name-matched chains are for review; only chains through resolved calls fail the run
+  if (report.findings.some(f => !f.approximate)) {
+    process.exit(1);
+  }
+}
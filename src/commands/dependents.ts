@@ -0,0 +1,81 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeDependents, type DependentsReport } from '../supply-chain/dependents.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface DependentsCommandOptions extends OutputFlags {
+  module?: string;
+  version?: string;
+  package?: string[];
+  countsOnly?: boolean;
+  offline?: boolean;
+  limit?: string;
+  format?: string;
+}
+
+function formatDependentsReport(report: DependentsReport, limit: number): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold(`Depwire Dependents: ${report.module}`));
+  if (report.counts) {
+    const { version, direct, indirect, total } = report.counts;
+    lines.push(`  ${chalk.bold(String(total))} public modules depend on ${version} (${direct} direct, ${indirect} indirect) ${chalk.dim('— deps.dev')}`);
+  } else {
+    lines.push(chalk.dim('  deps.dev has no dependent counts for this module'));
+  }
+
+  if (report.packages.length > 0) {
+    lines.push('');
+    lines.push(chalk.bold('  Importers by package') + chalk.dim(' — pkg.go.dev'));
+    const width = Math.min(60, Math.max(...report.packages.map(p => p.importPath.length)) + 2);
+    for (const pkg of report.packages) {
+      const known = pkg.knownImporters ?? pkg.importers.length;
+      const shown = pkg.knownImporters !== null && pkg.knownImporters > pkg.importers.length ? chalk.dim(` (${pkg.importers.length} listed)`) : '';
+      lines.push(`    ${pkg.importPath.padEnd(width)}${known === 0 ? chalk.dim('0') : String(known)}${shown}`);
+    }
+
+    lines.push('');
+    lines.push(chalk.bold(`  Dependent projects: ${report.dependents.length}`));
+    for (const dep of report.dependents.slice(0, limit)) {
+      lines.push(`    ${dep.project} ${chalk.dim(`uses ${dep.uses.join(', ')}`)}`);
+    }
+    if (report.dependents.length > limit) lines.push(chalk.dim(`    … ${report.dependents.length - limit} more (--format json for all)`));
+  }
+
+  for (const warning of report.warnings.slice(0, 5)) {
+    lines.push(chalk.yellow(`  ⚠ ${warning}`));
+  }
+  lines.push('');
+  lines.push(chalk.dim('  Only public code is visible to deps.dev and pkg.go.dev; private dependents are not counted.'));
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function dependentsCommand(dir: string, options: DependentsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeDependents(projectRoot, {
+    module: options.module,
+    version: options.version,
+    packages: options.package,
+    countsOnly: Boolean(options.countsOnly),
+    offline: Boolean(options.offline),
+    signal,
+  }));
+
+  answerQuietly((report.counts?.total ?? 0) > 0 || report.dependents.length > 0);
+
+  if (options.porcelain) {
+    // count <version> <direct> <indirect> <total>; package <import path> <known importers>; dependent <project> <our packages, space-separated>
+    printPorcelain([
+      ...(report.counts ? [['count', report.counts.version, report.counts.direct, report.counts.indirect, report.counts.total]] : []),
+      ...report.packages.map(p => ['package', p.importPath, p.knownImporters ?? p.importers.length]),
+      ...report.dependents.map(d => ['dependent', d.project, d.uses.join(' ')]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatDependentsReport(report, parseInt(options.limit || '20', 10)));
+  }
+}
@@ -0,0 +1,135 @@
+import { resolve } from 'path';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { detectTerminal } from '../utils/terminal.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { buildExportGraph, focusGraph, resolveFocus, type ExportGraph, type ExportLevel } from '../export/graph.js';
+import { renderTree, renderMatrix, matrixFits } from '../export/terminal.js';
+import { relabelGraph, resolveLabelOptions, type LabelFlags } from '../export/labels.js';
+import { findLintConfig } from '../rules/config.js';
+import { logger } from '../utils/log.js';
+import { answerQuietly, printPorcelain, readStdinQueries, type OutputFlags, type PorcelainField } from '../utils/porcelain.js';
+
+const log = logger('deps');
+
+export interface DepsCommandOptions extends FilterOptions, LabelFlags, OutputFlags {
+  /** A package, or - to read one per line from stdin and answer them all from one load */
+  package?: string;
+  reverse?: boolean;
+  depth?: string;
+  level?: string;
+  matrix?: boolean;
+  ascii?: boolean;
+  width?: string;
+}
+
+export async function depsCommand(dir: string, options: DepsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const labels = resolveLabelOptions(options, findLintConfig(projectRoot).labels);
+  const level = (options.level ?? 'package') as ExportLevel;
+  if (level !== 'package' && level !== 'file') throw new Error(`Unknown level "${options.level}" (expected package or file)`);
+  const depth = options.depth !== undefined ? parseInt(options.depth, 10) : undefined;
+  if (depth !== undefined && (!Number.isInteger(depth) || depth < 1)) throw new Error('--depth must be a positive integer');
+
+  const terminal = detectTerminal(process.stdout, {
+    ascii: options.ascii,
+    width: options.width ? parseInt(options.width, 10) : undefined,
+  });
+  // --package - answers a whole list of packages from one load
+  const queries = options.package === '-' ? readStdinQueries() : options.package !== undefined ? [options.package] : [];
+  if (options.package === '-' && queries.length === 0) throw new Error('No packages on stdin');
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const graph = buildExportGraph(buildGraph(parsedFiles, projectRoot, graphOptions()), projectRoot, { level, scope: filter.scope });
+  relabelGraph(graph, projectRoot, { ...labels, ellipsis: terminal.unicode ? '…' : '...' });
+  const direction = options.reverse ? 'in' : 'out';
+
+  if (options.porcelain || options.quiet) {
+    const { records, failed } = porcelainRecords(graph, queries, direction, depth);
+    printPorcelain(records);
+    answerQuietly(records.length > 0);
+    if (failed.length > 0) throw new Error(`${failed.length} of ${queries.length} packages could not be answered`);
+    return;
+  }
+
+  if (queries.length === 0) {
+    // Entry points: nothing depends on them (or, reversed, they depend on nothing)
+    const isRoot = (node: string) => (direction === 'out' ? graph.inNeighbors(node) : graph.outNeighbors(node)).every(n => n === node);
+    let roots = graph.filterNodes(isRoot);
+    // A graph that is one big cycle has no entry point; start everywhere
+    if (roots.length === 0) roots = graph.nodes();
+    roots.sort((a, b) => graph.getNodeAttribute(a, 'label').localeCompare(graph.getNodeAttribute(b, 'label')));
+    if (options.matrix) {
+      if (!matrixFits(graph, terminal)) {
+        throw new Error(`${graph.order} nodes don't fit a ${terminal.width}-column matrix — narrow it with --package and --depth`);
+      }
+      console.log(renderMatrix(graph, terminal));
+    } else {
+      console.log(renderTree(graph, terminal, { roots, direction, depth }));
+    }
+    return;
+  }
+
+  // Each query is answered in full; one that doesn't resolve doesn't stop the rest
+  const failed: string[] = [];
+  for (const query of queries) {
+    try {
+      if (options.matrix) {
+        const focused = focusGraph(graph, query, { hops: depth ?? 1, direction });
+        if (!matrixFits(focused, terminal)) {
+          throw new Error(`${focused.order} nodes don't fit a ${terminal.width}-column matrix — narrow it with --depth`);
+        }
+        if (queries.length > 1) console.log(terminal.color.bold(query));
+        console.log(renderMatrix(focused, terminal));
+      } else {
+        console.log(renderTree(graph, terminal, { roots: [resolveFocus(graph, query)], direction, depth }));
+      }
+    } catch (err) {
+      if (queries.length === 1) throw err;
+      log.error(query, { error: err });
+      failed.push(query);
+    }
+  }
+  if (failed.length > 0) throw new Error(`${failed.length} of ${queries.length} packages could not be answered`);
+}
+
+/**
+ * Without queries, every edge: edge <from> <to> <weight>. With them, what
+ * each one reaches breadth-first within --depth: dep <query> <package>
+ * <hops> (reversed: what depends on it).
+ */
+function porcelainRecords(graph: ExportGraph, queries: string[], direction: 'in' | 'out', depth: number | undefined): { records: PorcelainField[][]; failed: string[] } {
+  const label = (node: string) => graph.getNodeAttribute(node, 'label');
+  if (queries.length === 0) {
+    return { records: graph.mapEdges((_edge, attrs, source, target) => ['edge', label(source), label(target), attrs.weight]), failed: [] };
+  }
+  const records: PorcelainField[][] = [];
+  const failed: string[] = [];
+  for (const query of queries) {
+    let start: string;
+    try {
+      start = resolveFocus(graph, query);
+    } catch (err) {
+      if (queries.length === 1) throw err;
+      log.error(query, { error: err });
+      failed.push(query);
+      continue;
+    }
+    const hops = new Map([[start, 0]]);
+    const queue = [start];
+    while (queue.length > 0) {
+      const node = queue.shift()!;
+      const next = hops.get(node)! + 1;
+      if (depth !== undefined && next > depth) continue;
+      for (const neighbor of direction === 'out' ? graph.outNeighbors(node) : graph.inNeighbors(node)) {
+        if (hops.has(neighbor)) continue;
+        hops.set(neighbor, next);
+        queue.push(neighbor);
+        records.push(['dep', query, label(neighbor), next]);
+      }
+    }
+  }
+  return { records, failed };
+}
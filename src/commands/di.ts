@@ -0,0 +1,78 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot, scanDirectory } from '../utils/files.js';
+import { initParser } from '../parser/wasm-init.js';
+import { analyzeDiWiring, type DiAnalysis } from '../golang/di.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface DiCommandOptions extends OutputFlags {
+  format?: string;
+}
+
+function formatDiReport(analysis: DiAnalysis): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire DI Wiring'));
+  lines.push('');
+
+  if (analysis.containers.length === 0) {
+    lines.push(chalk.dim('No wire injectors, fx apps or dig containers found.'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  for (const container of analysis.containers) {
+    lines.push(chalk.bold(`${container.framework} ${container.name}`) + chalk.dim(`  ${container.file}:${container.line}`));
+    for (const provider of container.providers) {
+      const provides = provider.invoke ? chalk.dim('invoke') : provider.provides.join(', ') || chalk.dim('nothing');
+      lines.push(`  ${provider.name.padEnd(28)} → ${provides}`);
+      if (provider.consumes.length > 0) lines.push(chalk.dim(`  ${''.padEnd(28)}   needs ${provider.consumes.join(', ')}`));
+    }
+    if (container.incomplete) {
+      lines.push(chalk.dim('  (some registrations could not be typed — missing providers may be false positives)'));
+    }
+    lines.push('');
+  }
+
+  lines.push(chalk.bold(`Bindings (${analysis.bindings.length})`));
+  for (const b of analysis.bindings) {
+    lines.push(`  ${b.consumer} ${chalk.dim(`—${b.type}→`)} ${b.provider}`);
+  }
+  lines.push('');
+
+  if (analysis.issues.length > 0) {
+    lines.push(chalk.bold(`Issues (${analysis.issues.length})`));
+    for (const issue of analysis.issues) {
+      const icon = !issue.certain ? chalk.yellow('?') : chalk.red('✗');
+      lines.push(`  ${icon} ${issue.message}` + chalk.dim(`  ${issue.container.file}:${issue.container.line}`));
+    }
+    lines.push('');
+  }
+  return lines.join('\n');
+}
+
+export async function diCommand(dir: string, options: DiCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  await initParser();
+  const goFiles = scanDirectory(projectRoot).filter(f => f.endsWith('.go'));
+  const analysis = analyzeDiWiring(projectRoot, goFiles);
+
+  if (options.porcelain) {
+    // provider <framework> <container> <name> <file> <line> <provides> <consumes>; binding <consumer> <type> <provider>;
+    // issue <kind> <type> <certain> <container file> <line> <message>; type lists are ;-separated
+    printPorcelain([
+      ...analysis.containers.flatMap(c => c.providers.map(p => ['provider', c.framework, c.name, p.name, p.file, p.line, p.provides.join(';'), p.consumes.join(';')])),
+      ...analysis.bindings.map(b => ['binding', b.consumer, b.type, b.provider]),
+      ...analysis.issues.map(i => ['issue', i.kind, i.type, i.certain, i.container.file, i.container.line, i.message]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(analysis, null, 2));
+  } else {
+    console.log(formatDiReport(analysis));
+  }
+
+  // Only definite errors fail the run — uncertain ones are shown with '?'
+  if (analysis.issues.some(i => i.certain && i.kind === 'missing-provider')) {
+    process.exit(1);
+  }
+}
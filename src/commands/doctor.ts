@@ -0,0 +1,60 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { createSpinner, withInterrupt } from '../utils/progress.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { runDoctor, type CheckStatus, type DoctorReport } from '../doctor/index.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface DoctorCommandOptions extends FilterOptions, OutputFlags {
+  all?: boolean;
+  format?: string;
+}
+
+const MAX_DETAILS = 10;
+
+const MARKS: Record<CheckStatus, string> = {
+  ok: chalk.green('✓'),
+  warn: chalk.yellow('⚠'),
+  fail: chalk.red('✗'),
+  skip: chalk.dim('–'),
+};
+
+function formatDoctorReport(report: DoctorReport, all: boolean): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold(`Depwire Doctor: ${report.projectRoot}`));
+  lines.push('');
+  for (const check of report.checks) {
+    const summary = check.status === 'skip' ? chalk.dim(check.summary) : check.summary;
+    lines.push(`  ${MARKS[check.status]} ${chalk.bold(check.title.padEnd(16))}${summary}`);
+    const shown = all ? check.details : check.details.slice(0, MAX_DETAILS);
+    for (const detail of shown) lines.push(`      ${detail}`);
+    if (shown.length < check.details.length) lines.push(chalk.dim(`      … and ${check.details.length - shown.length} more (--all lists them)`));
+    if (check.hint && check.status !== 'ok') lines.push(chalk.dim(`      → ${check.hint}`));
+  }
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function doctorCommand(dir: string, options: DoctorCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const spinner = createSpinner('Checking');
+  let report: DoctorReport;
+  try {
+    report = await withInterrupt((signal) => runDoctor(projectRoot, { signal, filter: filter.includesFile, onProgress: spinner.update }));
+  } finally {
+    spinner.stop();
+  }
+
+  if (options.porcelain) {
+    // check <id> <status> <summary>, then detail <id> <detail> for each problem
+    printPorcelain(report.checks.flatMap(c => [['check', c.id, c.status, c.summary], ...c.details.map(d => ['detail', c.id, d])]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatDoctorReport(report, Boolean(options.all)));
+  }
+  if (report.status === 'fail') process.exit(1);
+}
@@ -0,0 +1,47 @@
+import { resolve } from 'path';
+import { writeFileSync } from 'fs';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { captureBaseline, compareBaseline, encodeBaseline, decodeBaseline, formatDriftMarkdown, baselineStore } from '../drift/index.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('drift');
+
+export interface DriftCommandOptions extends FilterOptions {
+  baseline: string;
+  capture?: boolean;
+  format?: string;
+  output?: string;
+  failOnRegression?: boolean;
+}
+
+export async function driftCommand(dir: string, options: DriftCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const format = options.format ?? 'markdown';
+  if (format !== 'markdown' && format !== 'json') throw new Error(`Unknown format "${format}" (expected markdown or json)`);
+  const store = baselineStore(options.baseline);
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const current = captureBaseline(buildGraph(parsedFiles, projectRoot, graphOptions()), projectRoot);
+
+  if (options.capture) {
+    await store.write(options.baseline, encodeBaseline(current));
+    log.info(`Captured baseline of ${Object.keys(current.packages).length} packages and ${current.modules.length} modules to ${options.baseline}`);
+    return;
+  }
+
+  const report = compareBaseline(decodeBaseline(await store.read(options.baseline)), current);
+  const output = format === 'json' ? JSON.stringify(report, null, 2) + '\n' : formatDriftMarkdown(report);
+  if (options.output) {
+    writeFileSync(options.output, output);
+    log.info(`Wrote drift report to ${options.output}`);
+  } else {
+    console.log(output);
+  }
+  if (options.failOnRegression && report.regressions > 0) {
+    process.exit(1);
+  }
+}
@@ -0,0 +1,93 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeErrorFlow, type ErrorFlowReport } from '../golang/errorflow.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ErrorsCommandOptions extends OutputFlags {
+  package?: string;
+  algo?: string;
+  format?: string;
+  limit?: string;
+}
+
+const list = (items: string[]) => (items.length > 3 ? [...items.slice(0, 3), `+${items.length - 3}`] : items).join(', ');
+
+function formatErrorFlowReport(report: ErrorFlowReport, limit: number): string {
+  const lines: string[] = [];
+  const leaking = report.errors.filter(e => report.uses.some(u => u.error === e.id && u.package !== e.package));
+  lines.push('');
+  lines.push(chalk.bold('Depwire Error Flow'));
+  lines.push(chalk.dim(`  ${report.errors.length} errors (${report.errors.filter(e => e.kind === 'sentinel').length} sentinels), ${leaking.length} used outside their package`));
+  lines.push('');
+
+  if (report.edges.length === 0) {
+    lines.push(chalk.dim('No errors cross a package boundary.'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  lines.push(chalk.bold('Package edges'));
+  for (const edge of report.edges.slice(0, limit)) {
+    lines.push(`  ${edge.from} → ${edge.to}  ${chalk.dim(`${edge.kinds.join('/')} ${list(edge.errors.map(e => e.slice(edge.from.length + 1)))} (${edge.uses})`)}`);
+  }
+  if (report.edges.length > limit) lines.push(chalk.dim(`  … ${report.edges.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  lines.push(chalk.bold('Errors'));
+  for (const error of leaking.slice(0, limit)) {
+    const uses = report.uses.filter(u => u.error === error.id && u.package !== error.package);
+    const count = (kind: string) => uses.filter(u => u.kind === kind).length;
+    const message = error.message ? chalk.cyan(`  "${error.message}"`) : chalk.cyan(`  ${error.kind}`);
+    lines.push(`  ${error.id}${message}` + chalk.dim(`  ${error.file}:${error.line}`));
+    lines.push(chalk.dim(`      returned ${count('returned')}, wrapped ${count('wrapped')}, checked ${count('checked')} times in ${new Set(uses.map(u => u.package)).size} other packages`));
+  }
+  if (leaking.length > limit) lines.push(chalk.dim(`  … ${leaking.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  for (const warning of report.warnings) lines.push(chalk.yellow(`⚠ ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+function formatErrorFlowDot(report: ErrorFlowReport): string {
+  const lines = ['digraph errors {', '  rankdir=LR;', '  node [shape=box, fontname="Helvetica"];'];
+  const quote = (s: string) => JSON.stringify(s);
+  for (const edge of report.edges) {
+    // Errors only checked are handled there; returned or wrapped ones keep leaking upward
+    const style = edge.kinds.every(k => k === 'checked') ? ', style=dashed' : '';
+    lines.push(`  ${quote(edge.from)} -> ${quote(edge.to)} [label=${quote(edge.errors.map(e => e.slice(edge.from.length + 1)).join('\n'))}${style}];`);
+  }
+  lines.push('}');
+  return lines.join('\n');
+}
+
+export async function errorsCommand(dir: string, options: ErrorsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeErrorFlow(projectRoot, { algo: options.algo, signal }));
+
+  if (options.package) {
+    // Errors the package declares or uses, and edges on either end; a suffix of the import path will do
+    const involves = (pkg: string) => pkg === options.package || pkg.endsWith(`/${options.package}`);
+    report.uses = report.uses.filter(u => involves(u.package) || involves(report.errors.find(e => e.id === u.error)!.package));
+    report.errors = report.errors.filter(e => involves(e.package) || report.uses.some(u => u.error === e.id));
+    report.edges = report.edges.filter(e => involves(e.from) || involves(e.to));
+  }
+  answerQuietly(report.edges.length > 0);
+
+  if (options.porcelain) {
+    // error <id> <kind> <file> <line> <message>; use <error> <kind> <package> <function> <file> <line> <propagated>; edge <from> <to> <uses> <kinds> <errors>
+    printPorcelain([
+      ...report.errors.map(e => ['error', e.id, e.kind, e.file, e.line, e.message ?? '']),
+      ...report.uses.map(u => ['use', u.error, u.kind, u.package, u.function ?? '', u.file, u.line, u.propagated]),
+      ...report.edges.map(e => ['edge', e.from, e.to, e.uses, e.kinds.join(' '), e.errors.join(' ')]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else if (options.format === 'dot') {
+    console.log(formatErrorFlowDot(report));
+  } else {
+    console.log(formatErrorFlowReport(report, parseInt(options.limit ?? '30', 10)));
+  }
+}
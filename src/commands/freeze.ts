@@ -0,0 +1,46 @@
+import { resolve, join } from 'path';
+import { existsSync } from 'fs';
+import chalk from 'chalk';
+import { buildGraph } from '../graph/index.js';
+import { toFileGraph } from '../graph/model.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { findLintConfig } from '../rules/config.js';
+import { LOCKFILE, snapshotLock, readLock, writeLock } from '../rules/lockfile.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('freeze');
+
+export interface FreezeCommandOptions {
+  config?: string;
+  output?: string;
+}
+
+function changes(label: string, before: string[], after: string[]): string[] {
+  const old = new Set(before);
+  const now = new Set(after);
+  return [
+    ...after.filter(v => !old.has(v)).map(v => chalk.green(`  + ${label} ${v}`)),
+    ...before.filter(v => !now.has(v)).map(v => chalk.red(`  - ${label} ${v}`)),
+  ];
+}
+
+export async function freezeCommand(dir: string, options: FreezeCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const config = findLintConfig(projectRoot, options.config ? resolve(options.config) : undefined);
+  const output = options.output ? resolve(options.output) : join(projectRoot, LOCKFILE);
+
+  // No exclude patterns: the lockfile must cover exactly what lint sees
+  const parsedFiles = await parseWithProgress(projectRoot);
+  const lock = snapshotLock(projectRoot, toFileGraph(buildGraph(parsedFiles, projectRoot, graphOptions())), config.layers);
+
+  // Show what this freeze approves, so the commit that updates the lockfile is easy to review
+  if (existsSync(output)) {
+    const previous = readLock(output);
+    const diff = [...changes('module', previous.modules, lock.modules), ...changes('edge', previous.edges, lock.edges)];
+    log.info(diff.length > 0 ? diff.join('\n') : chalk.dim('  No changes to the lockfile'));
+  }
+  writeLock(output, lock);
+  log.info(`Froze ${lock.modules.length} external modules and ${lock.edges.length} cross-layer edges to ${output}`);
+  if (!config.layers) log.info(chalk.dim('  Layers are top-level directories; declare "layers" in depwire.json to choose them'));
+}
@@ -0,0 +1,40 @@
+import { resolve } from 'path';
+import { generateFixture, writeFixture } from '../golang/fixture.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('gen-fixture');
+
+export interface GenFixtureCommandOptions {
+  packages?: string;
+  edges?: string;
+  cycles?: string;
+  files?: string;
+  functions?: string;
+  module?: string;
+  seed?: string;
+  output?: string;
+}
+
+/** depwire gen-fixture — a synthetic Go project of a given shape */
+export function genFixtureCommand(options: GenFixtureCommandOptions): void {
+  const packages = parseInt(options.packages ?? '50', 10);
+  const edges = parseInt(options.edges ?? String(packages * 3), 10);
+  const cycles = parseInt(options.cycles ?? '0', 10);
+  const { files, shape } = generateFixture({
+    packages,
+    edges,
+    cycles,
+    files: options.files !== undefined ? parseInt(options.files, 10) : undefined,
+    functions: options.functions !== undefined ? parseInt(options.functions, 10) : undefined,
+    module: options.module,
+    seed: options.seed !== undefined ? parseInt(options.seed, 10) : undefined,
+  });
+  const outDir = resolve(options.output ?? 'fixture');
+  writeFixture(outDir, files, shape);
+
+  if (shape.edges.length < edges) {
+    log.warn(`Only ${shape.edges.length} of ${edges} imports fit: ${packages} packages can't hold more without new cycles`);
+  }
+  const build = cycles > 0 ? 'import cycles keep it from building, by design' : 'it builds with go build ./...';
+  log.info(`Wrote ${shape.packages.length} packages with ${shape.edges.length} imports and ${shape.cycles.length} cycles to ${outDir}; ${build}`);
+}
@@ -0,0 +1,75 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeGlobalState, type GlobalStateReport } from '../golang/topology.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface GlobalsCommandOptions extends OutputFlags {
+  unexported?: boolean;
+  package?: string;
+  format?: string;
+  limit?: string;
+}
+
+const list = (packages: string[]) => (packages.length > 3 ? [...packages.slice(0, 3), `+${packages.length - 3}`] : packages).join(', ');
+
+function formatGlobalStateReport(report: GlobalStateReport, limit: number): string {
+  const lines: string[] = [];
+  const packages = new Set(report.variables.map(v => v.package));
+  lines.push('');
+  lines.push(chalk.bold('Depwire Global Mutable State'));
+  lines.push(chalk.dim(`  ${report.variables.length} package variables written after init, in ${packages.size} packages`));
+  lines.push('');
+
+  if (report.variables.length === 0) {
+    lines.push(chalk.dim('No package variables are written after init.'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  lines.push(chalk.bold('Variables'));
+  for (const variable of report.variables.slice(0, limit)) {
+    const name = variable.exported ? `${variable.package}.${variable.name}` : chalk.dim(`${variable.package}.${variable.name}`);
+    const type = variable.type ? `  ${chalk.cyan(variable.type)}` : '';
+    lines.push(`  ${name}${type}` + chalk.dim(`  ${variable.file}:${variable.line}`));
+    lines.push(chalk.dim(`      written by ${list(variable.writers)} (${variable.writes.length}), read by ${variable.readers.length > 0 ? list(variable.readers) : 'nobody'}`));
+  }
+  if (report.variables.length > limit) lines.push(chalk.dim(`  … ${report.variables.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  if (report.edges.length > 0) {
+    lines.push(chalk.bold('Package edges'));
+    for (const edge of report.edges.slice(0, limit)) {
+      lines.push(`  ${edge.from} → ${edge.to}  ${chalk.dim(`via ${list(edge.via)} (${edge.uses} reads)`)}`);
+    }
+    if (report.edges.length > limit) lines.push(chalk.dim(`  … ${report.edges.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+  return lines.join('\n');
+}
+
+export async function globalsCommand(dir: string, options: GlobalsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt(() => analyzeGlobalState(projectRoot, { unexported: options.unexported }));
+
+  if (options.package) {
+    // Variables the package declares, writes or reads; a suffix of the import path will do
+    const involves = (pkg: string) => pkg === options.package || pkg.endsWith(`/${options.package}`);
+    report.variables = report.variables.filter(v => involves(v.package) || v.uses.some(u => involves(u.package)));
+    report.edges = report.edges.filter(e => involves(e.from) || involves(e.to));
+  }
+  answerQuietly(report.variables.length > 0);
+
+  if (options.porcelain) {
+    // variable <package> <name> <exported> <type> <file> <line> <writers, space-separated> <readers, space-separated>; edge <from> <to> <uses> <via>
+    printPorcelain([
+      ...report.variables.map(v => ['variable', v.package, v.name, v.exported, v.type, v.file, v.line, v.writers.join(' '), v.readers.join(' ')]),
+      ...report.edges.map(e => ['edge', e.from, e.to, e.uses, e.via.join(' ')]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatGlobalStateReport(report, parseInt(options.limit ?? '30', 10)));
+  }
+}
@@ -0,0 +1,93 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeGoroutines, type GoroutineReport, type GoroutineSpawn } from '../golang/goroutines.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface GoroutinesCommandOptions extends OutputFlags {
+  package?: string;
+  algo?: string;
+  format?: string;
+  limit?: string;
+}
+
+function describeTargets(spawn: GoroutineSpawn): string {
+  if (spawn.targets.length === 0) return chalk.dim(spawn.literal ? 'func literal' : 'unresolved call');
+  const names = spawn.targets.map(t => (t.approximate ? chalk.dim(`${t.function} (by name)`) : t.function));
+  const shown = names.length > 3 ? [...names.slice(0, 3), chalk.dim(`+${names.length - 3}`)] : names;
+  return spawn.literal ? `func literal → ${shown.join(', ')}` : shown.join(', ');
+}
+
+function formatGoroutineReport(report: GoroutineReport, limit: number): string {
+  const lines: string[] = [];
+  const spawners = new Set(report.spawns.map(s => s.spawner));
+  const packages = new Set(report.spawns.map(s => s.package));
+  lines.push('');
+  lines.push(chalk.bold('Depwire Goroutine Spawns'));
+  lines.push(chalk.dim(`  ${report.spawns.length} go statements in ${spawners.size} functions of ${packages.size} packages`));
+  lines.push('');
+
+  if (report.spawns.length === 0) {
+    lines.push(chalk.dim('No go statements.'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  lines.push(chalk.bold('Package edges'));
+  for (const edge of report.edges.slice(0, limit)) {
+    const to = edge.from === edge.to ? chalk.dim('(same package)') : edge.external ? chalk.dim(edge.to) : edge.to;
+    lines.push(`  ${edge.from} → ${to}  ${chalk.dim(`${edge.spawns} go statement${edge.spawns === 1 ? '' : 's'}`)}`);
+  }
+  if (report.edges.length > limit) lines.push(chalk.dim(`  … ${report.edges.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  lines.push(chalk.bold('Spawns'));
+  for (const spawn of report.spawns.slice(0, limit)) {
+    lines.push(`  ${spawn.spawner}  go ${describeTargets(spawn)}` + chalk.dim(`  ${spawn.file}:${spawn.line}`));
+  }
+  if (report.spawns.length > limit) lines.push(chalk.dim(`  … ${report.spawns.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  for (const warning of report.warnings) lines.push(chalk.yellow(`⚠ ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+function formatGoroutineDot(report: GoroutineReport): string {
+  const lines = ['digraph goroutines {', '  rankdir=LR;', '  node [shape=box, fontname="Helvetica"];'];
+  const quote = (s: string) => JSON.stringify(s);
+  for (const edge of report.edges) {
+    const style = edge.external ? ', style=dashed' : '';
+    lines.push(`  ${quote(edge.from)} -> ${quote(edge.to)} [label=${quote(String(edge.spawns))}${style}];`);
+  }
+  lines.push('}');
+  return lines.join('\n');
+}
+
+export async function goroutinesCommand(dir: string, options: GoroutinesCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeGoroutines(projectRoot, { algo: options.algo, signal }));
+
+  if (options.package) {
+    // Goroutines the package starts, and goroutines elsewhere that run its code; a suffix of the import path will do
+    const involves = (pkg: string | null) => pkg !== null && (pkg === options.package || pkg.endsWith(`/${options.package}`));
+    report.spawns = report.spawns.filter(s => involves(s.package) || s.targets.some(t => involves(t.package)));
+    report.edges = report.edges.filter(e => involves(e.from) || involves(e.to));
+  }
+  answerQuietly(report.spawns.length > 0);
+
+  if (options.porcelain) {
+    // spawn <spawner> <package> <file> <line> <literal> <targets, space-separated>; edge <from> <to> <spawns> <external>
+    printPorcelain([
+      ...report.spawns.map(s => ['spawn', s.spawner, s.package, s.file, s.line, s.literal, s.targets.map(t => t.function).join(' ')]),
+      ...report.edges.map(e => ['edge', e.from, e.to, e.spawns, e.external]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else if (options.format === 'dot') {
+    console.log(formatGoroutineDot(report));
+  } else {
+    console.log(formatGoroutineReport(report, parseInt(options.limit ?? '30', 10)));
+  }
+}
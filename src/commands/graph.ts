@@ -0,0 +1,127 @@
+import { resolve, basename } from 'path';
+import { writeFileSync } from 'fs';
+import chalk from 'chalk';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import type { Direction } from '../graph/algorithms.js';
+import { buildExportGraph, focusGraph, clusterGraph, toGraphDocument, type ExportLevel } from '../export/graph.js';
+import { relabelGraph, resolveLabelOptions, type LabelFlags } from '../export/labels.js';
+import { findLintConfig } from '../rules/config.js';
+import { toDot } from '../export/dot.js';
+import { toSvg } from '../export/svg.js';
+import { toHtml } from '../export/html.js';
+import { toGexf } from '../export/gexf.js';
+import { toMermaid } from '../export/mermaid.js';
+import { toTreemapSvg, toTreemapHtml } from '../export/treemap.js';
+import { applyMetric, heatmap } from '../export/metrics.js';
+import { overlayDiff, diffSummary } from '../export/diff.js';
+import { diffGraphs } from '../graph/diff.js';
+import { isGitRepo, withWorktree } from '../temporal/git.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('graph');
+
+export interface GraphCommandOptions extends FilterOptions, LabelFlags {
+  level?: string;
+  format?: string;
+  focus?: string;
+  hops?: string;
+  direction?: string;
+  output?: string;
+  colorBy?: string;
+  churnSince?: string;
+  cluster?: boolean;
+  resolution?: string;
+  diff?: string;
+  treemap?: boolean;
+  size?: string;
+  binary?: string;
+  coverprofile?: string[];
+  pprof?: string[];
+  actiongraph?: string[];
+}
+
+const DIRECTIONS: Record<string, Direction> = { out: 'out', deps: 'out', in: 'in', rdeps: 'in', both: 'both' };
+
+export async function graphCommand(dir: string, options: GraphCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const labels = resolveLabelOptions(options, findLintConfig(projectRoot).labels);
+  const level = (options.level ?? 'package') as ExportLevel;
+  if (level !== 'package' && level !== 'file') throw new Error(`Unknown level "${options.level}" (expected package or file)`);
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const symbolGraph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  let graph = buildExportGraph(symbolGraph, projectRoot, { level, scope: filter.scope });
+  if (options.diff) {
+    if (!isGitRepo(projectRoot)) throw new Error('Not a git repository — --diff compares against a git ref');
+    log.info(chalk.dim(`Parsing ${options.diff} for the diff overlay`));
+    const base = await withWorktree(projectRoot, options.diff, async (baseDir) => {
+      const symbols = buildGraph(await parseWithProgress(baseDir, { filter: filter.includesFile }), baseDir, graphOptions());
+      return { symbols, graph: buildExportGraph(symbols, baseDir, { level, scope: filter.scope }) };
+    });
+    graph = overlayDiff(base.graph, graph, diffGraphs(base.symbols, symbolGraph), level);
+  }
+  // Before focusing, so instability reflects every coupling and not just the neighborhood's
+  const metricContext = { graph: symbolGraph, projectRoot, level, since: options.churnSince, binary: options.binary, coverprofile: options.coverprofile, pprof: options.pprof, actiongraph: options.actiongraph };
+  if (options.colorBy) {
+    await applyMetric(graph, options.colorBy, metricContext);
+  }
+  const size = options.size ?? 'loc';
+  if (options.treemap && size !== options.colorBy) {
+    await applyMetric(graph, size, metricContext);
+  }
+
+  relabelGraph(graph, projectRoot, labels);
+
+  if (options.focus) {
+    const direction = DIRECTIONS[options.direction ?? 'both'];
+    if (!direction) throw new Error(`Unknown direction "${options.direction}" (expected out, in or both)`);
+    const hops = parseInt(options.hops ?? '2', 10);
+    if (!Number.isInteger(hops) || hops < 0) throw new Error(`--hops must be a non-negative integer`);
+    graph = focusGraph(graph, options.focus, { hops, direction });
+  }
+  // After focusing, so the communities are those of what's drawn
+  const clusters = options.cluster
+    ? clusterGraph(graph, { resolution: options.resolution ? parseFloat(options.resolution) : undefined })
+    : undefined;
+  log.info(`${graph.order} ${level === 'file' ? 'files' : 'packages'}, ${graph.size} edges${clusters ? `, ${clusters.length} clusters` : ''}`, { level, nodes: graph.order, edges: graph.size });
+  if (options.diff) {
+    const summary = diffSummary(graph);
+    log.info(summary
+      ? `Since ${options.diff}: ${chalk.green(`+${summary.addedNodes}`)} ${chalk.red(`−${summary.removedNodes}`)} ${chalk.yellow(`~${summary.changedNodes}`)} nodes, ` +
+        `${chalk.green(`+${summary.addedEdges}`)} ${chalk.red(`−${summary.removedEdges}`)} edges`
+      : `No dependency changes since ${options.diff}`);
+  }
+
+  const format = options.format ?? (options.treemap ? 'html' : 'dot');
+  const render = { name: basename(projectRoot), heatmap: options.colorBy ? heatmap(graph, options.colorBy) : undefined, clusters };
+  let output: string;
+  if (options.treemap) {
+    if (format !== 'svg' && format !== 'html') throw new Error(`--treemap renders svg or html, not ${format}`);
+    output = format === 'svg' ? toTreemapSvg(graph, { ...render, size }) : toTreemapHtml(graph, { ...render, size });
+  } else if (format === 'json') {
+    output = JSON.stringify(toGraphDocument(graph, clusters), null, 2) + '\n';
+  } else if (format === 'dot') {
+    output = toDot(graph, render);
+  } else if (format === 'svg') {
+    output = toSvg(graph, render);
+  } else if (format === 'html') {
+    output = toHtml(graph, render);
+  } else if (format === 'gexf') {
+    output = toGexf(graph, render);
+  } else if (format === 'mermaid') {
+    output = toMermaid(graph, { clusters });
+  } else {
+    throw new Error(`Unknown format "${format}" (expected dot, svg, html, gexf, mermaid or json)`);
+  }
+
+  if (options.output) {
+    writeFileSync(options.output, output);
+    log.info(`Wrote ${options.output}`, { output: options.output });
+  } else {
+    process.stdout.write(output);
+  }
+}
@@ -0,0 +1,92 @@
+import { resolve, basename } from 'path';
+import { writeFileSync } from 'fs';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { createSpinner, withInterrupt } from '../utils/progress.js';
+import { analyzeHistory, historyCsv, parseStep, type HistoryPoint } from '../temporal/history.js';
+import { startTemporalServer } from '../viz/temporal-server.js';
+import { historyGexf } from '../export/gexf.js';
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('history');
+
+export interface HistoryCommandOptions {
+  since: string;
+  step?: string;
+  format?: string;
+  csv?: string;
+  gexf?: string;
+  viz?: boolean;
+  port?: string;
+  output?: string;
+}
+
+function delta(now: number, before: number | undefined): string {
+  if (before === undefined || now === before) return '';
+  return now > before ? chalk.red(` +${now - before}`) : chalk.green(` ${now - before}`);
+}
+
+function formatHistory(points: HistoryPoint[]): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Dependency History'));
+  lines.push(chalk.dim(`  ${points.length} revisions`));
+  lines.push('');
+  lines.push(chalk.dim(`  ${'date'.padEnd(12)}${'commit'.padEnd(10)}${'files'.padEnd(12)}${'packages'.padEnd(12)}${'pkg edges'.padEnd(14)}${'cycles'.padEnd(10)}modules`));
+  points.forEach((point, i) => {
+    const m = point.metrics;
+    const prev = points[i - 1]?.metrics;
+    // Pad before coloring so the columns line up
+    const cell = (value: number, before: number | undefined, width: number) => {
+      const plain = delta(value, before).replace(/\x1b\[[0-9;]*m/g, '');
+      return `${value}${delta(value, before)}${' '.repeat(Math.max(1, width - String(value).length - plain.length))}`;
+    };
+    lines.push(`  ${point.date.slice(0, 10).padEnd(12)}${point.commit.slice(0, 8).padEnd(10)}` +
+      `${cell(m.files, prev?.files, 12)}${cell(m.packages, prev?.packages, 12)}${cell(m.packageEdges, prev?.packageEdges, 14)}` +
+      `${cell(m.cycles, prev?.cycles, 10)}${m.directModules}${delta(m.directModules, prev?.directModules)}`);
+  });
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function historyCommand(dir: string, options: HistoryCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const step = parseStep(options.step ?? '1month');
+
+  const spinner = createSpinner('Snapshotting revisions');
+  const result = await withInterrupt((signal) => analyzeHistory(projectRoot, {
+    since: options.since,
+    step,
+    cacheDir: options.output,
+    signal,
+    onProgress: spinner.update,
+  })).finally(() => spinner.stop());
+
+  if (options.csv) {
+    writeFileSync(options.csv, historyCsv(result.points));
+    log.info(`Wrote ${options.csv}`);
+  }
+  if (options.gexf) {
+    // Labelled with today's import paths; packages that moved keep their directory
+    const index = new GoModuleIndex(findGoModules(projectRoot));
+    writeFileSync(options.gexf, historyGexf(result.snapshots, {
+      name: basename(projectRoot),
+      label: (dir) => index.importForDir(dir) ?? dir,
+    }));
+    log.info(`Wrote ${options.gexf}`);
+  }
+
+  if (options.format === 'json') {
+    console.log(JSON.stringify(result.points, null, 2));
+  } else if (options.format === 'csv') {
+    process.stdout.write(historyCsv(result.points));
+  } else {
+    console.log(formatHistory(result.points));
+  }
+
+  if (options.viz) {
+    // The temporal viewer steps and animates through the same snapshots
+    await startTemporalServer(result.snapshots, projectRoot, parseInt(options.port ?? '3334', 10));
+  }
+}
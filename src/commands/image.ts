@@ -0,0 +1,88 @@
+import { writeFileSync } from 'fs';
+import chalk from 'chalk';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeImage, imageSbom, type ImageReport } from '../golang/image.js';
+import { formatSize } from '../export/treemap.js';
+import { getVersion } from './security.js';
+import { logger } from '../utils/log.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+const log = logger('image');
+
+export interface ImageCommandOptions extends OutputFlags {
+  platform?: string;
+  format?: string;
+  output?: string;
+  limit?: string;
+}
+
+const size = (bytes: number) => formatSize('binsize', bytes);
+
+function formatImageReport(report: ImageReport, limit: number): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Image Audit'));
+  const digest = report.digest ? ` ${report.digest.slice(0, 19)}` : '';
+  lines.push(chalk.dim(`  ${report.image}${digest}${report.platform ? ` (${report.platform})` : ''}: ${report.layers} layers, ${report.binaries.length} Go binaries, ${report.modules.length} module versions`));
+  lines.push('');
+
+  for (const binary of report.binaries) {
+    const r = binary.report;
+    const main = r.modules[0];
+    const total = r.stripped ? '' : `${size(r.total)}, `;
+    lines.push(`  ${chalk.bold(binary.path)}${binary.entrypoint ? chalk.cyan(' (entrypoint)') : ''}`);
+    lines.push(chalk.dim(`    ${r.path || main.path} ${main.version}, ${r.goVersion}, ${total}${r.modules.length - 1} modules, layer ${binary.layer}`));
+  }
+  if (report.binaries.length > 0) lines.push('');
+
+  // Versions shipped more than once: the same module at two versions in one image
+  const versions = new Map<string, number>();
+  for (const mod of report.modules) versions.set(mod.path, (versions.get(mod.path) ?? 0) + 1);
+  if (report.modules.length > 0) {
+    lines.push(chalk.bold('  Modules'));
+    for (const mod of report.modules.slice(0, limit)) {
+      const replace = mod.replace ? chalk.yellow(` => ${mod.replace.path}${mod.replace.version ? ` ${mod.replace.version}` : ''}`) : '';
+      const skew = versions.get(mod.path)! > 1 ? chalk.yellow(' (several versions)') : '';
+      const where = report.binaries.length > 1 ? chalk.dim(`  ${mod.binaries.join(', ')}`) : '';
+      lines.push(`  ${mod.path} ${chalk.dim(mod.version)}${replace}${skew}${where}`);
+    }
+    if (report.modules.length > limit) lines.push(chalk.dim(`  … ${report.modules.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  const warnings = [...report.warnings, ...report.binaries.flatMap(b => b.report.warnings)];
+  for (const warning of warnings) lines.push(chalk.yellow(`  ⚠ ${warning}`));
+  if (warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+/** depwire image <ref> — the Go binaries an image ships and the modules in them */
+export async function imageCommand(ref: string, options: ImageCommandOptions): Promise<void> {
+  const report = await withInterrupt((signal) => analyzeImage(ref, { platform: options.platform, signal }));
+
+  if (options.porcelain) {
+    // <binary> <module> <version> <replacement> <replacement version>; one record per module of each binary
+    printPorcelain(report.binaries.flatMap(b => b.report.modules.map(m => [b.path, m.path, m.version, m.replace?.path, m.replace?.version])));
+    answerQuietly(report.binaries.length > 0);
+    return;
+  }
+
+  let output: string;
+  if (options.format === 'cyclonedx') {
+    output = JSON.stringify(imageSbom(report, getVersion()), null, 2) + '\n';
+  } else if (options.format === 'json') {
+    output = JSON.stringify(report, null, 2) + '\n';
+  } else if (!options.format || options.format === 'table') {
+    output = formatImageReport(report, parseInt(options.limit ?? '30', 10)) + '\n';
+  } else {
+    throw new Error(`Unknown format "${options.format}" (expected table, json or cyclonedx)`);
+  }
+
+  if (options.output) {
+    writeFileSync(options.output, output);
+    log.info(`Wrote ${options.output}`, { output: options.output });
+  } else {
+    process.stdout.write(output);
+  }
+  answerQuietly(report.binaries.length > 0);
+}
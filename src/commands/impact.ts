@@ -0,0 +1,76 @@
+import { resolve } from 'path';
+import { readFileSync } from 'fs';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { analyzeChangeImpact } from '../impact/index.js';
+import { parseUnifiedDiff } from '../impact/changes.js';
+import { formatImpactReport, formatTestImpact } from '../impact/display.js';
+import { analyzeTestImpact } from '../impact/tests.js';
+import { getChangedFiles, isGitRepo } from '../temporal/git.js';
+import { logger } from '../utils/log.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+const log = logger('impact');
+
+export interface ImpactCommandOptions extends OutputFlags {
+  base?: string;
+  files?: string[];
+  diff?: string;
+  format?: string;
+  limit?: string;
+  tests?: boolean;
+}
+
+async function readChangedFiles(projectRoot: string, options: ImpactCommandOptions): Promise<string[]> {
+  if (options.files && options.files.length > 0) {
+    return options.files;
+  }
+  if (options.diff) {
+    const diff = options.diff === '-'
+      ? readFileSync(0, 'utf-8')
+      : readFileSync(resolve(options.diff), 'utf-8');
+    return parseUnifiedDiff(diff);
+  }
+  if (!isGitRepo(projectRoot)) {
+    throw new Error('Not a git repository — pass --files or --diff');
+  }
+  return getChangedFiles(projectRoot, options.base);
+}
+
+export async function impactCommand(dir: string, options: ImpactCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const changedFiles = await readChangedFiles(projectRoot, options);
+
+  if (changedFiles.length === 0) {
+    log.info('No changed files.');
+  }
+
+  const parsedFiles = await parseWithProgress(projectRoot);
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  const result = analyzeChangeImpact(graph, projectRoot, changedFiles);
+  const wantTests = options.tests || options.format === 'go-test';
+  const tests = wantTests ? await analyzeTestImpact(projectRoot, result) : null;
+  const limit = parseInt(options.limit || '20', 10);
+  answerQuietly(result.affectedFiles.length > 0);
+
+  if (options.porcelain) {
+    // file|package <name> <distance> <changed dependencies>; binary|service add <path> <detected by>; with --tests, test <dir> <import path> and test-file <file>
+    printPorcelain([
+      ...result.affectedFiles.map(f => ['file', f.name, f.distance, f.changedDependencies]),
+      ...result.affectedPackages.map(p => ['package', p.name, p.distance, p.changedDependencies]),
+      ...result.binaries.map(b => ['binary', b.name, b.distance, b.changedDependencies, b.path, b.detectedBy]),
+      ...result.services.map(s => ['service', s.name, s.distance, s.changedDependencies, s.path, s.detectedBy]),
+      ...(tests?.goPackages ?? []).map(t => ['test', t.dir, t.importPath]),
+      ...(tests?.testFiles ?? []).map(file => ['test-file', file]),
+    ]);
+  } else if (options.format === 'go-test') {
+    // For `go test $(depwire impact --format go-test)`; prints nothing when no tests are affected
+    if (tests!.goTestArgs) console.log(tests!.goTestArgs.join('\n'));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(tests ? { ...result, tests } : result, null, 2));
+  } else {
+    console.log(formatImpactReport(result, limit));
+    if (tests) console.log(formatTestImpact(tests, limit));
+  }
+}
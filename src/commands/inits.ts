@@ -0,0 +1,77 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { analyzeInit, type InitEffect, type InitReport } from '../golang/init.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface InitsCommandOptions extends OutputFlags {
+  format?: string;
+}
+
+const EFFECT_COLORS: Record<InitEffect, (s: string) => string> = {
+  network: chalk.red,
+  exec: chalk.red,
+  file: chalk.yellow,
+  env: chalk.yellow,
+  goroutine: chalk.yellow,
+  panic: chalk.magenta,
+  registration: chalk.cyan,
+  'global-state': chalk.cyan,
+};
+
+function formatInitReport(report: InitReport): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Package Initialization'));
+  lines.push('');
+
+  if (report.packages.length === 0) {
+    lines.push(chalk.dim('No init functions or package-level initializers with side effects.'));
+  } else {
+    const byPath = new Map(report.packages.map(p => [p.importPath ?? p.dir, p]));
+    lines.push(chalk.bold(`Init order (${report.order.length} packages)`));
+    report.order.forEach((path, i) => {
+      const pkg = byPath.get(path)!;
+      const effects = pkg.effects.map(e => EFFECT_COLORS[e](e)).join(' ');
+      lines.push(`  ${String(i + 1).padStart(3)}. ${path}  ${effects || chalk.dim('no notable effects')}`);
+      for (const site of pkg.sites) {
+        const label = site.kind === 'init' ? 'init()' : `var ${site.name}`;
+        lines.push(chalk.dim(`       ${label}  ${site.file}:${site.line}`));
+        for (const e of site.evidence) {
+          lines.push(`         ${EFFECT_COLORS[e.effect](e.effect.padEnd(12))} ${e.call}` + chalk.dim(`  ${e.file}:${e.line}`));
+        }
+      }
+    });
+  }
+  lines.push('');
+
+  if (report.blankImports.length > 0) {
+    lines.push(chalk.bold(`Side-effect imports (${report.blankImports.length})`));
+    for (const imp of report.blankImports) {
+      const purpose = imp.purpose ? chalk.dim(`  ${imp.purpose}`) : chalk.yellow('  unknown side effect');
+      lines.push(`  _ "${imp.path}"${purpose}` + chalk.dim(`  ${imp.file}:${imp.line}`));
+    }
+    lines.push('');
+  }
+  return lines.join('\n');
+}
+
+export async function initsCommand(dir: string, options: InitsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await analyzeInit(projectRoot);
+
+  answerQuietly(report.packages.length + report.blankImports.length > 0);
+
+  if (options.porcelain) {
+    // init <order> <package> <init|var> <name> <file> <line> <effects, space-separated>; import <importer> <path> <file> <line> <purpose>
+    const byPath = new Map(report.packages.map(p => [p.importPath ?? p.dir, p]));
+    printPorcelain([
+      ...report.order.flatMap((path, i) => byPath.get(path)!.sites.map(site => ['init', i + 1, path, site.kind, site.name, site.file, site.line, site.effects.join(' ')])),
+      ...report.blankImports.map(imp => ['import', imp.importer, imp.path, imp.file, imp.line, imp.purpose]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatInitReport(report));
+  }
+}
@@ -0,0 +1,253 @@
+import { resolve, join } from 'path';
+import { existsSync } from 'fs';
+import { buildGraph } from '../graph/index.js';
+import { toFileGraph } from '../graph/model.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, filterLintResult, type FilterOptions } from '../parser/filter.js';
+import { RuleRegistry, builtinRules, loadRuleModule, goAnalysisRule, goInitRule, goLocalReplaceRule, goBlankImportRule, goDotImportRule, goCapabilityRule, goScorecardRule, goTripwireRule, goPanicRule, dependencyBudgetRule, noCircularDependencies, noNewCircularDependencies, regoPolicyRule } from '../rules/index.js';
+import { findLintConfig } from '../rules/config.js';
+import { LOCKFILE, readLock, frozenDependenciesRule, unapprovedEdges, type DependencyLock } from '../rules/lockfile.js';
+import { writeAdrStubs } from '../rules/adr.js';
+import { WAIVERS_FILE, readWaivers, applyWaivers, type WaiverOutcome } from '../rules/waivers.js';
+import { RATCHET_FILE, readRatchet, writeRatchet, applyRatchet, type RatchetOutcome, type RatchetState } from '../rules/ratchet.js';
+import { buildVerdict, writeVerdict } from '../rules/verdict.js';
+import { formatLintTable, formatLintJSON, formatLintSARIF, formatLintPorcelain } from '../rules/reporter.js';
+import { CAPABILITIES, type Capability } from '../capabilities/index.js';
+import { attestIfRequested, type AttestFlags } from './attest.js';
+import { getVersion } from './security.js';
+import { isGitRepo, resolveCommit } from '../temporal/git.js';
+import { logger } from '../utils/log.js';
+import type { OutputFlags } from '../utils/porcelain.js';
+
+const log = logger('lint');
+
+export interface LintCommandOptions extends AttestFlags, FilterOptions, OutputFlags {
+  rules?: string[];
+  /** Run only these rule IDs (or aliases such as cycles) */
+  rule?: string[];
+  /** Tolerate cycles that already exist at --base */
+  delta?: boolean;
+  base?: string;
+  builtin?: boolean;
+  goVet?: boolean;
+  vettool?: string;
+  goInit?: string[];
+  goImports?: boolean;
+  allowImport?: string[];
+  goReplace?: boolean;
+  /** true, or module path globs whose local replaces are allowed */
+  forbidLocalReplace?: boolean | string[];
+  forbidCapability?: string[];
+  minScorecard?: string;
+  scorecardCheck?: string[];
+  scorecardBase?: string;
+  initTripwire?: boolean | string;
+  forbidPanics?: string[];
+  /** Lint config file; depwire.json in the project root is used when present */
+  config?: string;
+  /** Lockfile; depwire.lock.json in the project root is used when present */
+  lock?: string;
+  /** Write ADR stubs here for cross-layer edges missing from the lockfile */
+  adr?: string;
+  /** Rego policy files or directories */
+  policy?: string[];
+  /** Rules whose finding count may only go down, in addition to the config's */
+  ratchet?: string[];
+  /** Waivers file; depwire.waivers.json in the project root is used when present */
+  waivers?: string;
+  /** Also write a pass/fail verdict document here, for merge queues */
+  verdict?: string;
+  format?: string;
+  maxWarnings?: string;
+}
+
+export async function lintCommand(dir: string, options: LintCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+
+  // Register rules before analysis — a bad rule module should fail fast
+  const registry = new RuleRegistry();
+  if (options.builtin !== false) {
+    const base = options.base ?? 'origin/main';
+    registry.register(options.delta
+      ? builtinRules.map(rule => (rule.id === noCircularDependencies.id ? noNewCircularDependencies({ base }) : rule))
+      : builtinRules);
+  }
+  for (const specifier of options.rules ?? []) {
+    registry.register(await loadRuleModule(specifier, projectRoot));
+  }
+  if (options.goVet || options.vettool) {
+    registry.register(goAnalysisRule({ vettool: options.vettool ? resolve(options.vettool) : undefined }));
+  }
+  if (options.goInit) {
+    registry.register(goInitRule({ packages: options.goInit }));
+  }
+  if (options.goImports || options.allowImport) {
+    registry.register(goBlankImportRule({ alsoAllow: options.allowImport }));
+    registry.register(goDotImportRule({ alsoAllow: options.allowImport }));
+  }
+  if (options.goReplace || options.forbidLocalReplace) {
+    const forbid = options.forbidLocalReplace;
+    registry.register(goLocalReplaceRule({ forbid: Boolean(forbid), allow: Array.isArray(forbid) ? forbid : undefined }));
+  }
+  if (options.forbidCapability) {
+    registry.register(goCapabilityRule({ forbid: parseCapabilities(options.forbidCapability) }));
+  }
+  if (options.minScorecard || options.scorecardCheck) {
+    registry.register(goScorecardRule({
+      minScore: options.minScorecard ? parseFloat(options.minScorecard) : undefined,
+      checks: parseScorecardChecks(options.scorecardCheck ?? []),
+      base: options.scorecardBase,
+    }));
+  }
+  if (options.initTripwire) {
+    registry.register(goTripwireRule({ base: typeof options.initTripwire === 'string' ? options.initTripwire : undefined }));
+  }
+  if (options.forbidPanics) {
+    registry.register(goPanicRule({ packages: options.forbidPanics }));
+  }
+  const config = findLintConfig(projectRoot, options.config ? resolve(options.config) : undefined);
+  if (config.budgets) {
+    registry.register(dependencyBudgetRule(config.budgets));
+  }
+  const lockFile = options.lock ? resolve(options.lock) : join(projectRoot, LOCKFILE);
+  let lock: DependencyLock | undefined;
+  if (options.lock || existsSync(lockFile)) {
+    lock = readLock(lockFile);
+    registry.register(frozenDependenciesRule(lock, { layers: config.layers }));
+  }
+  if (options.adr && !lock) {
+    throw new Error(`--adr records decisions on edges missing from ${LOCKFILE}; run depwire freeze first`);
+  }
+  if (options.policy) {
+    registry.register(regoPolicyRule({ policies: options.policy.map(p => resolve(p)) }));
+  }
+  if (options.rule) {
+    selectRules(registry, options.rule);
+  }
+  log.info(`Linting: ${projectRoot} (${registry.list().length} rules)`, { projectRoot, rules: registry.list().length });
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  // Rules reading go.mod or running go vet see the whole tree; their findings are filtered the same way
+  let result = filterLintResult(await registry.run(graph, projectRoot, { parsedFiles }), filter);
+  if (options.adr && lock) {
+    const fileGraph = toFileGraph(graph);
+    // Only edges still reported once filtered, so --include, --exclude and --only-* limit the stubs too
+    const reported = new Set(result.findings.filter(f => f.rule === 'frozen-dependencies' && f.source).map(f => `${f.source}\0${f.target}`));
+    const edges = unapprovedEdges(lock, fileGraph, config.layers).filter(edge => reported.has(`${edge.files[0][0]}\0${edge.files[0][1]}`));
+    for (const stub of writeAdrStubs(resolve(options.adr), edges, fileGraph)) {
+      log.info(`Wrote ADR stub ${stub.file} for ${stub.edge}`, { adr: stub.file, edge: stub.edge });
+    }
+  }
+
+  let waived: WaiverOutcome | undefined;
+  const waiversFile = options.waivers ? resolve(options.waivers) : join(projectRoot, WAIVERS_FILE);
+  if (options.waivers || existsSync(waiversFile)) {
+    waived = applyWaivers(result, readWaivers(waiversFile), { approvers: config.approvers, keys: config.approverKeys });
+    result = waived.result;
+    if (waived.applied.length > 0) log.info(`${waived.applied.length} findings waived`, { waived: waived.applied.length });
+  }
+
+  const ratchet = [...new Set([...(config.ratchet ?? []), ...(options.ratchet ?? [])].flatMap(v => v.split(',')).map(v => v.trim()).filter(Boolean).map(id => RULE_ALIASES[id] ?? id))];
+  let ratcheted: { rules: string[]; previous: RatchetState; outcome: RatchetOutcome } | undefined;
+  if (ratchet.length > 0) {
+    const stateFile = join(projectRoot, RATCHET_FILE);
+    const previous = readRatchet(stateFile);
+    const outcome = applyRatchet(result, previous, ratchet);
+    ratcheted = { rules: ratchet, previous, outcome };
+    result = outcome.result;
+    if (outcome.tightened.length > 0) {
+      writeRatchet(stateFile, outcome.state);
+      for (const change of outcome.tightened) {
+        log.info(change.allowed === undefined
+          ? `Ratchet: recorded ${change.count} ${change.rule} findings`
+          : `Ratchet: ${change.rule} down from ${change.allowed} to ${change.count}`);
+      }
+      log.info(`Commit ${RATCHET_FILE} to lock in the new counts`);
+    }
+  }
+
+  if (options.porcelain) {
+    const records = formatLintPorcelain(result);
+    if (records) console.log(records);
+  } else if (options.format === 'json') {
+    console.log(formatLintJSON(result));
+  } else if (options.format === 'sarif') {
+    console.log(formatLintSARIF(result, getVersion()));
+  } else {
+    console.log(formatLintTable(result));
+  }
+  await attestIfRequested(projectRoot, 'lint', result, options);
+  if (options.verdict) {
+    writeVerdict(resolve(options.verdict), buildVerdict(result, {
+      commit: headCommit(projectRoot),
+      depwireVersion: getVersion(),
+      base: options.delta ? options.base ?? 'origin/main' : undefined,
+      maxWarnings: options.maxWarnings !== undefined ? parseInt(options.maxWarnings, 10) : undefined,
+      waivers: waived,
+      ratchet: ratcheted,
+    }));
+    log.info(`Verdict written to ${options.verdict}`);
+  }
+
+  if (result.summary.error > 0) {
+    process.exit(1);
+  }
+  if (options.maxWarnings !== undefined && result.summary.warning > parseInt(options.maxWarnings, 10)) {
+    log.error(`${result.summary.warning} warnings exceed --max-warnings ${options.maxWarnings} — exiting with code 1`);
+    process.exit(1);
+  }
+}
+
+// Short names for rules whose IDs are long to type
+export const RULE_ALIASES: Record<string, string> = {
+  cycles: 'no-circular-dependencies',
+  budgets: 'dependency-budget',
+  lock: 'frozen-dependencies',
+  rego: 'rego-policy',
+};
+
+// Keep only the rules selected with --rule
+function selectRules(registry: RuleRegistry, selected: string[]): void {
+  const ids = selected.flatMap(v => v.split(',')).map(v => v.trim()).filter(Boolean).map(id => RULE_ALIASES[id] ?? id);
+  const unknown = ids.filter(id => !registry.get(id));
+  if (unknown.length > 0) {
+    throw new Error(`Unknown rule ${unknown.join(', ')} (registered: ${registry.list().map(r => r.id).join(', ')})`);
+  }
+  for (const rule of registry.list()) {
+    if (!ids.includes(rule.id)) registry.unregister(rule.id);
+  }
+}
+
+function headCommit(projectRoot: string): string | null {
+  if (!isGitRepo(projectRoot)) return null;
+  try {
+    return resolveCommit(projectRoot, 'HEAD');
+  } catch {
+    return null; // no commits yet
+  }
+}
+
+function parseCapabilities(values: string[]): Capability[] {
+  const caps = values.flatMap(v => v.split(',')).map(v => v.trim()).filter(Boolean);
+  const unknown = caps.filter(c => !CAPABILITIES.includes(c as Capability));
+  if (unknown.length > 0) {
+    throw new Error(`Unknown capability ${unknown.join(', ')} (expected ${CAPABILITIES.join(', ')})`);
+  }
+  return caps as Capability[];
+}
+
+// "Maintained=5" → { Maintained: 5 }
+function parseScorecardChecks(values: string[]): Record<string, number> {
+  const checks: Record<string, number> = {};
+  for (const value of values) {
+    const [name, min] = value.split('=');
+    if (!name || min === undefined || Number.isNaN(parseFloat(min))) {
+      throw new Error(`Invalid --scorecard-check "${value}" (expected <Check>=<min score>)`);
+    }
+    checks[name] = parseFloat(min);
+  }
+  return checks;
+}
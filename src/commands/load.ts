@@ -0,0 +1,79 @@
+import chalk from 'chalk';
+import { parseProject, type ParseOptions } from '../parser/index.js';
+import type { BuildGraphOptions } from '../graph/index.js';
+import type { ParsedFile } from '../parser/types.js';
+import { changeScope, type ChangeScope } from '../parser/scope.js';
+import { createSpinner, withInterrupt, isCancelled } from '../utils/progress.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('parser');
+
+let sinceRef: string | undefined;
+let scope: ChangeScope | null = null;
+let runtimeEdges = false;
+
+/**
+ * Limit every parseWithProgress() of this run to the packages changed since
+ * a git ref and their neighbors (the global --since). The scope is computed
+ * for the first project parsed and reused, so a second parse — a base
+ * worktree for a diff — covers the same files.
+ */
+export function scopeToChangesSince(ref: string): void {
+  sinceRef = ref;
+  scope = null;
+}
+
+/** Add imported runtime traces to the graphs the commands of this run build (the global --runtime-edges) */
+export function includeRuntimeEdges(): void {
+  runtimeEdges = true;
+}
+
+/** The buildGraph() options of this run's command */
+export function graphOptions(): BuildGraphOptions {
+  return { runtimeEdges };
+}
+
+/** The files the global --since limits parsing to, once the first parse computed them */
+export function changeScopeFiles(): Set<string> | undefined {
+  return scope?.files;
+}
+
+/**
+ * Parse a project for a CLI command: shows a spinner on stderr and
+ * cancels cleanly on Ctrl-C.
+ */
+export async function parseWithProgress(
+  projectRoot: string,
+  options: Pick<ParseOptions, 'exclude' | 'verbose' | 'filter'> = {}
+): Promise<ParsedFile[]> {
+  if (sinceRef && !scope) {
+    scope = changeScope(projectRoot, sinceRef);
+    log.info(chalk.dim(`Since ${sinceRef}: ${scope.packages.length} changed packages and ${scope.neighbors.length} neighbors (${scope.files.size} files)`), {
+      since: sinceRef,
+      packages: scope.packages.length,
+      neighbors: scope.neighbors.length,
+      files: scope.files.size,
+    });
+  }
+  const spinner = createSpinner('Parsing files');
+  try {
+    return await withInterrupt((signal) =>
+      parseProject(projectRoot, {
+        ...options,
+        only: scope?.files,
+        signal,
+        // Per-file logging and the spinner would fight over the same line
+        onProgress: options.verbose || log.enabled('trace') ? undefined : spinner.update,
+      })
+    );
+  } finally {
+    spinner.stop();
+  }
+}
+
+/** Exit with the conventional SIGINT status when an operation was cancelled */
+export function exitIfCancelled(err: unknown): void {
+  if (isCancelled(err)) {
+    process.exit(130);
+  }
+}
@@ -0,0 +1,89 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { explainModuleGraph, type ModuleGraphExplanation, type ExplainedModule } from '../golang/modreasons.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ModgraphCommandOptions extends OutputFlags {
+  module?: string;
+  unexplained?: boolean;
+  tests?: boolean;
+  format?: string;
+}
+
+// Requirements no import uses — kept for version selection, or stale
+const unexplained = (m: ExplainedModule) => m.requiredBy.filter(r => r.imports.length === 0 && !r.implicit);
+
+function formatModuleGraph(report: ModuleGraphExplanation, modules: ExplainedModule[], onlyUnexplained: boolean): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold(`Depwire Module Graph: ${report.main}`));
+  lines.push(chalk.dim(`  ${modules.length} modules via ${report.via}${report.tests ? ', including test imports' : ''}`));
+  lines.push('');
+
+  for (const m of modules) {
+    lines.push(`  ${chalk.bold(m.path)} ${m.version}${m.direct ? chalk.cyan(' direct') : ''}`);
+    if (m.why) lines.push(chalk.dim(`    why: ${m.why.join(' → ')}`));
+    else lines.push(chalk.yellow('    no package of this module is built'));
+    for (const req of onlyUnexplained ? unexplained(m) : m.requiredBy) {
+      const by = `${req.module}${req.version ? ` requires ${req.version}` : ''}`;
+      if (req.implicit) {
+        lines.push(`    ${chalk.yellow(`${req.module} imports it without requiring it`)}`);
+      } else if (req.imports.length === 0) {
+        lines.push(`    ${by} ${chalk.dim('— no import; kept for version selection')}`);
+      } else {
+        lines.push(`    ${by}`);
+      }
+      for (const imp of req.imports.slice(0, 5)) lines.push(chalk.dim(`      ${imp.from} imports ${imp.to}`));
+      if (req.imports.length > 5) lines.push(chalk.dim(`      … ${req.imports.length - 5} more`));
+    }
+    lines.push('');
+  }
+
+  for (const warning of report.warnings) lines.push(chalk.dim(`  ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+function formatModuleGraphDot(report: ModuleGraphExplanation, modules: ExplainedModule[]): string {
+  const lines = ['digraph modgraph {', '  rankdir=LR;', '  node [shape=box, fontname="Helvetica"];'];
+  const quote = (s: string) => JSON.stringify(s);
+  for (const m of modules) {
+    for (const req of m.requiredBy) {
+      const label = req.imports.length > 0 ? `${req.imports[0].from} → ${req.imports[0].to}${req.imports.length > 1 ? ` (+${req.imports.length - 1})` : ''}` : '';
+      const style = req.implicit ? ', color=orange' : req.imports.length === 0 ? ', style=dashed' : '';
+      lines.push(`  ${quote(req.module)} -> ${quote(m.path)} [label=${quote(label)}${style}];`);
+    }
+  }
+  lines.push(`  ${quote(report.main)} [style=bold];`);
+  lines.push('}');
+  return lines.join('\n');
+}
+
+export async function modgraphCommand(dir: string, options: ModgraphCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => explainModuleGraph(projectRoot, { tests: options.tests, signal }));
+
+  let modules = report.modules;
+  if (options.module) {
+    modules = modules.filter(m => m.path === options.module);
+    if (modules.length === 0) throw new Error(`${options.module} is not in the module graph`);
+  }
+  if (options.unexplained) modules = modules.filter(m => unexplained(m).length > 0);
+  answerQuietly(modules.length > 0);
+
+  if (options.porcelain) {
+    // module <path> <version> <direct> <import chain, space-separated>; required <module> <by> <version> <imports> <implicit>
+    printPorcelain(modules.flatMap(m => [
+      ['module', m.path, m.version, m.direct, m.why?.join(' ')],
+      ...(options.unexplained ? unexplained(m) : m.requiredBy).map(r => ['required', m.path, r.module, r.version, r.imports.length, Boolean(r.implicit)]),
+    ]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify({ ...report, modules }, null, 2));
+  } else if (options.format === 'dot') {
+    console.log(formatModuleGraphDot(report, modules));
+  } else {
+    console.log(formatModuleGraph(report, modules, Boolean(options.unexplained)));
+  }
+}
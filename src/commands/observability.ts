@@ -0,0 +1,83 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeObservability, OBSERVABILITY_CATEGORIES, type ObservabilityReport } from '../golang/observability.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ObservabilityCommandOptions extends OutputFlags {
+  category?: string;
+  package?: string;
+  format?: string;
+  limit?: string;
+}
+
+function formatObservabilityReport(report: ObservabilityReport, limit: number): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Observability Libraries'));
+  lines.push(chalk.dim(`  ${report.libraries.length} libraries used by ${report.packages.length} packages`));
+  lines.push('');
+
+  if (report.libraries.length === 0) {
+    lines.push(chalk.dim('No logging, metrics or tracing libraries in use.'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  for (const category of OBSERVABILITY_CATEGORIES) {
+    const libraries = report.libraries.filter(l => l.category === category);
+    if (libraries.length === 0) continue;
+    const mixed = libraries.length > 1 ? chalk.yellow(`  ${libraries.length} libraries`) : '';
+    lines.push(chalk.bold(category[0].toUpperCase() + category.slice(1)) + mixed);
+    for (const library of libraries) {
+      lines.push(`  ${library.library.padEnd(20)} ${chalk.dim(`${library.packages.length} packages, ${library.calls} calls, ${library.references} references`)}`);
+    }
+    lines.push('');
+  }
+
+  lines.push(chalk.bold('Packages'));
+  for (const pkg of report.packages.slice(0, limit)) {
+    const uses = pkg.libraries.map(l => `${l.library} ${chalk.dim(`(${l.calls})`)}`);
+    lines.push(`  ${pkg.package}  ${uses.join(', ')}`);
+  }
+  if (report.packages.length > limit) lines.push(chalk.dim(`  … ${report.packages.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  for (const m of report.mixed) {
+    lines.push(chalk.yellow(`⚠ ${m.category}: ${m.libraries.join(', ')} — candidates for consolidation`));
+  }
+  if (report.mixed.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export async function observabilityCommand(dir: string, options: ObservabilityCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt(() => analyzeObservability(projectRoot));
+
+  if (options.category) {
+    const category = options.category;
+    if (!OBSERVABILITY_CATEGORIES.includes(category as never)) {
+      throw new Error(`Unknown category "${category}" (expected ${OBSERVABILITY_CATEGORIES.join(', ')})`);
+    }
+    report.packages = report.packages
+      .map(p => ({ ...p, libraries: p.libraries.filter(l => l.category === category) }))
+      .filter(p => p.libraries.length > 0);
+    report.libraries = report.libraries.filter(l => l.category === category);
+    report.mixed = report.mixed.filter(m => m.category === category);
+  }
+  if (options.package) {
+    // A suffix of the import path will do
+    report.packages = report.packages.filter(p => p.package === options.package || p.package.endsWith(`/${options.package}`));
+  }
+  answerQuietly(report.packages.length > 0);
+
+  if (options.porcelain) {
+    // <package> <category> <library> <calls> <references> <imports, space-separated>
+    printPorcelain(report.packages.flatMap(p => p.libraries.map(l => [p.package, l.category, l.library, l.calls, l.references, l.imports.join(' ')])));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatObservabilityReport(report, parseInt(options.limit ?? '30', 10)));
+  }
+}
@@ -0,0 +1,28 @@
+import { relative } from 'path';
+import { loadGoProject } from '../golang/packages.js';
+import { packageDependencies, writePackageDependencyDocs } from '../docs/packages.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('docs');
+
+export interface PackageDocsCommandOptions {
+  sidecar?: boolean;
+}
+
+/** depwire docs --package-deps — per-package dependency sections in doc.go or DEPENDENCIES.md */
+export async function packageDocsCommand(projectRoot: string, options: PackageDocsCommandOptions): Promise<void> {
+  const project = await loadGoProject(projectRoot);
+  if (project.packages.size === 0) throw new Error(`No Go packages in ${projectRoot}`);
+
+  // go generate runs the directive in its package's directory, with GOPACKAGE set; update just that package
+  let packages: string[] | undefined;
+  if (process.env.GOPACKAGE) {
+    const dir = relative(projectRoot, process.cwd()).split('\\').join('/') || '.';
+    if (!project.packages.has(dir)) throw new Error(`go generate ran in ${dir}, which is not a Go package of ${projectRoot}`);
+    packages = [dir];
+  }
+
+  const changed = writePackageDependencyDocs(projectRoot, packageDependencies(project), { sidecar: options.sidecar, packages });
+  for (const file of changed) log.info(`Updated ${file}`, { file });
+  log.info(`${changed.length} of ${packages?.length ?? project.packages.size} packages updated`);
+}
@@ -0,0 +1,74 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzePanics, type PanicReport } from '../golang/panics.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface PanicsCommandOptions extends OutputFlags {
+  package?: string;
+  algo?: string;
+  format?: string;
+  limit?: string;
+}
+
+function formatPanicReport(report: PanicReport, limit: number): string {
+  const lines: string[] = [];
+  const { summary } = report;
+  lines.push('');
+  lines.push(chalk.bold('Depwire Panic Reachability'));
+  lines.push(chalk.dim(`  ${summary.exported} exported functions, ${summary.panicking} functions panic, ${summary.recovering} recover`));
+  lines.push('');
+
+  if (summary.exposed === 0) {
+    lines.push(chalk.green('  ✓ No panic escapes an exported function'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  let shown = 0;
+  for (const pkg of report.packages) {
+    if (pkg.exposed.length === 0 || shown >= limit) continue;
+    lines.push(`${chalk.bold(pkg.package)}  ${chalk.dim(`${pkg.exposed.length} of ${pkg.exported} exported functions`)}`);
+    for (const exposure of pkg.exposed.slice(0, limit - shown)) {
+      const icon = exposure.approximate ? chalk.yellow('?') : chalk.red('✗');
+      const last = exposure.chain[exposure.chain.length - 1];
+      const via = exposure.chain.length > 1 ? chalk.dim(` via ${exposure.chain.slice(1).map(s => s.function).join(' → ')}`) : '';
+      lines.push(`  ${icon} ${exposure.function.slice(pkg.package.length + 1)}  ${exposure.call}()${via}` + chalk.dim(`  ${last.file}:${last.line}`));
+      shown++;
+    }
+    lines.push('');
+  }
+  if (summary.exposed > shown) {
+    lines.push(chalk.dim(`  … ${summary.exposed - shown} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  if (report.packages.some(p => p.exposed.some(e => e.approximate))) {
+    lines.push(chalk.dim('? = relies on matching a method by name; the receiver type was not visible'));
+  }
+  for (const warning of report.warnings) lines.push(chalk.yellow(`⚠ ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export async function panicsCommand(dir: string, options: PanicsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzePanics(projectRoot, { algo: options.algo, signal }));
+
+  if (options.package) {
+    // A suffix of the import path will do
+    report.packages = report.packages.filter(p => p.package === options.package || p.package.endsWith(`/${options.package}`));
+  }
+  const exposures = report.packages.flatMap(p => p.exposed);
+  answerQuietly(exposures.length > 0);
+
+  if (options.porcelain) {
+    // <package> <function> <file> <line> <call> <approximate> <chain, space-separated>
+    printPorcelain(exposures.map(e => [e.package, e.function, e.file, e.line, e.call, e.approximate, e.chain.map(s => s.function).join(' ')]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatPanicReport(report, parseInt(options.limit ?? '30', 10)));
+  }
+}
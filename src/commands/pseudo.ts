@@ -0,0 +1,70 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzePseudoVersions, type PseudoVersionReport, type PinStatus } from '../supply-chain/pseudo.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface PseudoCommandOptions extends OutputFlags {
+  all?: boolean;
+  offline?: boolean;
+  proxy?: string;
+  cacheDir?: string;
+  format?: string;
+}
+
+const STATUS_COLOR: Record<PinStatus, (s: string) => string> = {
+  missing: chalk.red.bold,
+  orphaned: chalk.red,
+  branch: chalk.yellow,
+  unknown: chalk.dim,
+  merged: chalk.green,
+};
+
+function formatPseudoVersionReport(report: PseudoVersionReport): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Pseudo-Version Pins'));
+  lines.push(chalk.dim(`  ${report.pins.length} requirements pinned to untagged commits`));
+  lines.push('');
+
+  for (const pin of report.pins) {
+    lines.push(`  ${STATUS_COLOR[pin.status](pin.status.toUpperCase().padEnd(9))} ${chalk.bold(pin.module)} ${chalk.dim(pin.version)}${pin.direct ? '' : chalk.dim(' (indirect)')}`);
+    const after = pin.base ? `after ${pin.base}` : 'no earlier tag';
+    lines.push(`            commit ${pin.commit.slice(0, 12)} from ${pin.time.slice(0, 10)}, ${after}${pin.replaces ? `, replaces ${pin.replaces}` : ''}`);
+    if (pin.status === 'merged') lines.push(chalk.dim(`            on ${pin.refs[0]}`));
+    if (pin.status === 'branch') lines.push(chalk.dim(`            only on ${pin.refs.slice(0, 5).join(', ')}${pin.refs.length > 5 ? ` (+${pin.refs.length - 5} more)` : ''}`));
+    if (pin.status === 'orphaned') lines.push(`            on no branch or tag of ${pin.repository} — builds only while the proxy keeps it`);
+    if (pin.status === 'missing') lines.push(`            ${pin.repository ?? 'the repository'} no longer has this commit and the proxy does not serve it`);
+    if (pin.note) lines.push(chalk.dim(`            ${pin.note}`));
+  }
+  if (report.pins.length > 0) lines.push('');
+  const { merged, branch, orphaned, missing, unknown } = report.summary;
+  lines.push(`  ${merged} merged, ${branch} on other branches, ${orphaned} orphaned, ${missing} missing${unknown ? `, ${unknown} not checked` : ''}`);
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function pseudoCommand(dir: string, options: PseudoCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzePseudoVersions(projectRoot, {
+    all: Boolean(options.all),
+    offline: Boolean(options.offline),
+    proxy: options.proxy,
+    cacheDir: options.cacheDir,
+    signal,
+  }));
+
+  if (options.porcelain) {
+    // <status> <module> <version> <direct> <commit> <time> <base> <refs>, refs comma-separated
+    printPorcelain(report.pins.map(p => [p.status, p.module, p.version, p.direct, p.commit, p.time, p.base, p.refs.join(',')]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatPseudoVersionReport(report));
+  }
+
+  if (report.summary.orphaned > 0 || report.summary.missing > 0) {
+    process.exit(1);
+  }
+}
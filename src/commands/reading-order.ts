@@ -0,0 +1,58 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { loadGoProject } from '../golang/packages.js';
+import { readingOrder, formatReadingOrderMarkdown, type ReadingOrder, type ReadingRole } from '../docs/reading-order.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ReadingOrderCommandOptions extends OutputFlags {
+  format?: string;
+  limit?: string;
+}
+
+const ROLE_COLORS: Record<ReadingRole, (s: string) => string> = { foundation: chalk.green, core: chalk.cyan, entry: chalk.magenta };
+
+function formatReadingOrder(order: ReadingOrder, limit: number): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Reading Order'));
+  const count = (role: ReadingRole) => order.steps.filter(s => s.role === role).length;
+  lines.push(chalk.dim(`  ${order.steps.length} packages: ${count('foundation')} foundations, ${count('core')} core, ${count('entry')} entry points`));
+  lines.push('');
+
+  const width = String(order.steps.length).length;
+  for (const step of order.steps.slice(0, limit)) {
+    const name = step.importPath ?? step.dir;
+    lines.push(`  ${String(step.step).padStart(width)}. ${chalk.bold(name)}  ${ROLE_COLORS[step.role](step.role)}`);
+    if (step.synopsis) lines.push(`  ${' '.repeat(width + 2)}${step.synopsis}`);
+    lines.push(chalk.dim(`  ${' '.repeat(width + 2)}${step.reason}`));
+  }
+  if (order.steps.length > limit) lines.push(chalk.dim(`  … ${order.steps.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  if (order.undocumented > 0) {
+    lines.push(chalk.yellow(`⚠ ${order.undocumented} packages have no package comment to summarize them`));
+    lines.push('');
+  }
+  return lines.join('\n');
+}
+
+export async function readingOrderCommand(dir: string, options: ReadingOrderCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const project = await withInterrupt(() => loadGoProject(projectRoot));
+  if (project.packages.size === 0) throw new Error(`No Go packages in ${projectRoot}`);
+  const order = readingOrder(project);
+  answerQuietly(order.steps.length > 0);
+
+  if (options.porcelain) {
+    // <step> <package> <role> <level> <synopsis, empty when undocumented>
+    printPorcelain(order.steps.map(s => [s.step, s.importPath ?? s.dir, s.role, s.level, s.synopsis]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(order, null, 2));
+  } else if (options.format === 'markdown') {
+    console.log(formatReadingOrderMarkdown(order));
+  } else {
+    console.log(formatReadingOrder(order, parseInt(options.limit ?? '50', 10)));
+  }
+}
@@ -0,0 +1,70 @@
+import { resolve, join, dirname } from 'path';
+import { existsSync, mkdirSync, writeFileSync } from 'fs';
+import { findProjectRoot } from '../utils/files.js';
+import { previewPackageMove } from '../refactor/move.js';
+import { suggestCycleBreaks } from '../refactor/interfaces.js';
+import { formatMovePreview, formatCycleBreaks } from '../refactor/display.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('refactor');
+
+export interface RefactorPreviewOptions {
+  move?: string[];
+  format?: string;
+  limit?: string;
+  dir?: string;
+}
+
+export async function refactorPreviewCommand(options: RefactorPreviewOptions): Promise<void> {
+  if (!options.move || options.move.length !== 2) {
+    throw new Error('--move takes exactly two arguments: <from> <to>');
+  }
+  const projectRoot = !options.dir || options.dir === '.' ? findProjectRoot() : resolve(options.dir);
+  const [from, to] = options.move;
+  const preview = await previewPackageMove(projectRoot, from, to);
+
+  if (options.format === 'json') {
+    console.log(JSON.stringify(preview, null, 2));
+  } else {
+    console.log(formatMovePreview(preview, parseInt(options.limit || '50', 10)));
+  }
+
+  // Non-zero when the move as-is would not compile
+  if (preview.newCycles.length > 0 || preview.internalViolations.length > 0) {
+    process.exit(1);
+  }
+}
+
+export interface RefactorCyclesOptions {
+  dir?: string;
+  format?: string;
+  skeleton?: boolean;
+  out?: string;
+}
+
+export async function refactorCyclesCommand(options: RefactorCyclesOptions): Promise<void> {
+  const projectRoot = !options.dir || options.dir === '.' ? findProjectRoot() : resolve(options.dir);
+  const suggestions = await suggestCycleBreaks(projectRoot);
+
+  if (options.format === 'json') {
+    console.log(JSON.stringify(suggestions, null, 2));
+  } else {
+    console.log(formatCycleBreaks(suggestions, Boolean(options.skeleton) && !options.out));
+  }
+
+  // Skeletons are only written where asked — never into the project unless --out points there
+  if (options.out) {
+    const outDir = resolve(options.out);
+    for (const s of suggestions) {
+      if (s.interfaces.length === 0) continue;
+      const target = join(outDir, s.location.file);
+      if (existsSync(target)) {
+        log.warn(`Skipping ${target} — file exists`);
+        continue;
+      }
+      mkdirSync(dirname(target), { recursive: true });
+      writeFileSync(target, s.skeleton, 'utf-8');
+      log.info(`Wrote ${target}`);
+    }
+  }
+}
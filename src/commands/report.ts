@@ -0,0 +1,84 @@
+import { basename, dirname, extname, join, resolve } from 'path';
+import { existsSync, readFileSync, writeFileSync } from 'fs';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { resolveLabelOptions, type LabelFlags } from '../export/labels.js';
+import { findLintConfig } from '../rules/config.js';
+import { buildArchitectureReport, formatArchitectureMarkdown } from '../report/index.js';
+import { formatArchitectureWiki, WIKI_PROFILES, type WikiProfile } from '../report/wiki.js';
+import { moduleGlossary } from '../supply-chain/glossary.js';
+import { readCoverProfiles } from '../golang/coverage.js';
+import { withInterrupt } from '../utils/progress.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('report');
+
+export interface ReportCommandOptions extends FilterOptions, LabelFlags {
+  format?: string;
+  output?: string;
+  check?: boolean;
+  glossary?: boolean;
+  proxy?: string;
+  offline?: boolean;
+  /** confluence or notion: for publishing to a wiki, with the diagrams as SVG attachments */
+  profile?: string;
+  /** go test -coverprofile files, for the coverage section */
+  coverprofile?: string[];
+  coverageBelow?: string;
+  minDependents?: string;
+}
+
+export async function reportCommand(dir: string, options: ReportCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const labels = resolveLabelOptions(options, findLintConfig(projectRoot).labels);
+  const format = options.format ?? 'markdown';
+  if (format !== 'markdown' && format !== 'json') throw new Error(`Unknown format "${format}" (expected markdown or json)`);
+  if (options.check && !options.output) throw new Error('--check compares against a file; pass it with -o');
+  // Stars and latest versions change daily, so a checked file with them would never stay current
+  if (options.check && options.glossary) throw new Error('--glossary follows its upstreams, not the code, so it can\'t be checked; drop --glossary from the --check run and from the file it checks');
+  if (options.profile) {
+    if (!WIKI_PROFILES.includes(options.profile as WikiProfile)) throw new Error(`Unknown profile "${options.profile}" (expected ${WIKI_PROFILES.join(' or ')})`);
+    if (format === 'json') throw new Error('--profile publishes the document form of the report; it can\'t be combined with --format json');
+    if (!options.output) throw new Error('--profile writes the diagrams next to the document as SVG files to attach; pass the document\'s path with -o');
+  }
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const coverage = options.coverprofile?.length ? readCoverProfiles(projectRoot, options.coverprofile.map(f => resolve(f))) : undefined;
+  const coverageRisk = {
+    maxCoverage: options.coverageBelow !== undefined ? parseFloat(options.coverageBelow) : undefined,
+    minDependents: options.minDependents !== undefined ? parseInt(options.minDependents, 10) : undefined,
+  };
+  const report = await buildArchitectureReport(buildGraph(parsedFiles, projectRoot, graphOptions()), projectRoot, { labels, coverage, coverageRisk });
+  if (options.glossary) {
+    if (!options.offline) log.info(`Fetching upstream metadata for ${report.modules.length} modules...`);
+    const glossary = await withInterrupt((signal) => moduleGlossary(projectRoot, report.modules, { proxy: options.proxy, offline: options.offline, signal }));
+    report.glossary = glossary.entries;
+    for (const warning of glossary.warnings) log.warn(warning);
+  }
+  // Wiki attachments are named after the document: architecture.xhtml comes with architecture-components.svg
+  const wiki = options.profile ? formatArchitectureWiki(report, options.profile as WikiProfile, basename(options.output!, extname(options.output!))) : null;
+  const output = wiki ? wiki.document : format === 'json' ? JSON.stringify(report, null, 2) + '\n' : formatArchitectureMarkdown(report);
+  const files = options.output
+    ? [{ file: options.output, content: output }, ...(wiki?.attachments ?? []).map(a => ({ file: join(dirname(options.output!), a.name), content: a.content }))]
+    : [];
+
+  if (options.check) {
+    // For CI: the committed files must be what regenerating them would write
+    const stale = files.filter(f => (existsSync(f.file) ? readFileSync(f.file, 'utf-8') : null) !== f.content);
+    if (stale.length > 0) {
+      const flags = options.profile ? ` --profile ${options.profile}` : format === 'json' ? ' --format json' : '';
+      log.error(`${stale.map(f => f.file).join(', ')} ${stale.length === 1 ? 'is' : 'are'} out of date; regenerate with depwire report${flags} -o ${options.output}`);
+      process.exit(1);
+    }
+    log.info(`${options.output} is up to date`);
+  } else if (options.output) {
+    for (const f of files) writeFileSync(f.file, f.content);
+    const attached = wiki && wiki.attachments.length > 0 ? ` and ${wiki.attachments.length} diagrams to attach (${wiki.attachments.map(a => a.name).join(', ')})` : '';
+    log.info(`Wrote ${options.output}${attached}: ${report.packages.length} packages in ${report.components.length} components`);
+  } else {
+    process.stdout.write(output);
+  }
+}
@@ -0,0 +1,71 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeRoutes, type RouteReport } from '../golang/routes.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface RoutesCommandOptions extends OutputFlags {
+  path?: string;
+  package?: string;
+  algo?: string;
+  format?: string;
+  limit?: string;
+}
+
+function formatRouteReport(report: RouteReport, limit: number, detailed: boolean): string {
+  const lines: string[] = [];
+  const { summary } = report;
+  lines.push('');
+  lines.push(chalk.bold('Depwire HTTP Routes'));
+  const frameworks = Object.entries(summary.frameworks).map(([name, n]) => `${name} ${n}`).join(', ');
+  lines.push(chalk.dim(`  ${summary.routes} routes, ${summary.handlers} handlers${frameworks ? ` (${frameworks})` : ''}`));
+  lines.push('');
+
+  if (report.routes.length === 0) {
+    lines.push(chalk.yellow('  No route registrations found'));
+    lines.push('');
+  }
+  const width = Math.min(48, Math.max(0, ...report.routes.slice(0, limit).map(r => r.method.length + r.path.length + 1)));
+  for (const route of report.routes.slice(0, limit)) {
+    const method = route.method === '*' ? chalk.dim('*') : chalk.cyan(route.method);
+    const pad = ' '.repeat(Math.max(1, width - route.method.length - route.path.length));
+    const handler = route.handler ? (route.inline ? `func literal in ${route.handler}` : route.handler) : chalk.yellow(route.handlerExpr);
+    const icon = route.approximate ? chalk.yellow(' ?') : '';
+    lines.push(`  ${method} ${chalk.bold(route.path)}${pad} → ${handler}${icon}` + chalk.dim(`  ${route.file}:${route.line}`));
+    if (detailed) {
+      lines.push(chalk.dim(`      ${route.functions} functions in ${route.packages.length} packages: ${route.packages.join(', ') || 'none'}`));
+      if (route.external.length > 0) lines.push(chalk.dim(`      external: ${route.external.join(', ')}`));
+    }
+  }
+  if (report.routes.length > limit) lines.push(chalk.dim(`  … ${report.routes.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  if (report.routes.some(r => r.approximate)) {
+    lines.push(chalk.dim('? = handler matched by method name; the receiver type was not visible'));
+  }
+  for (const warning of report.warnings) lines.push(chalk.yellow(`⚠ ${warning}`));
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export async function routesCommand(dir: string, options: RoutesCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeRoutes(projectRoot, { algo: options.algo, path: options.path, signal }));
+
+  if (options.package) {
+    // A suffix of the import path will do
+    report.routes = report.routes.filter(r => r.package !== null && (r.package === options.package || r.package.endsWith(`/${options.package}`)));
+  }
+  answerQuietly(report.routes.length > 0);
+
+  if (options.porcelain) {
+    // <method> <path> <handler, empty when unresolved> <package> <file> <line> <packages reached, space-separated>
+    printPorcelain(report.routes.map(r => [r.method, r.path, r.handler, r.package, r.file, r.line, r.packages.join(' ')]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    // Asking about one path is asking what serves it: show the dependencies too
+    console.log(formatRouteReport(report, parseInt(options.limit ?? '50', 10), Boolean(options.path)));
+  }
+}
@@ -0,0 +1,68 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeScorecards, KEY_CHECKS, type ScorecardReport } from '../supply-chain/scorecard.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ScorecardCommandOptions extends OutputFlags {
+  offline?: boolean;
+  all?: boolean;
+  format?: string;
+}
+
+function scoreColor(score: number): (s: string) => string {
+  if (score < 0) return chalk.dim;
+  return score >= 7 ? chalk.green : score >= 4 ? chalk.yellow : chalk.red;
+}
+
+function formatScorecardReport(report: ScorecardReport, all: boolean): string {
+  const lines: string[] = [];
+  const modules = report.modules.filter(m => all || m.direct);
+  lines.push('');
+  lines.push(chalk.bold('Depwire OpenSSF Scorecards'));
+  lines.push(chalk.dim(`  ${modules.length} ${all ? '' : 'direct '}external modules`));
+  lines.push('');
+
+  if (modules.length > 0) {
+    const width = Math.min(50, Math.max(...modules.map(m => m.module.length)) + 2);
+    const short = KEY_CHECKS.map(c => c.replace(/-/g, ' ').split(' ').map(w => w[0]).join('').padEnd(5));
+    lines.push(chalk.dim(`  ${'module'.padEnd(width)}score  ${short.join('')}`));
+    for (const m of modules) {
+      const name = m.module.padEnd(width);
+      if (!m.scorecard) {
+        lines.push(`  ${name}${chalk.dim(m.repo ? 'not scanned' : 'unknown host')}`);
+        continue;
+      }
+      const card = m.scorecard;
+      const checks = KEY_CHECKS.map(c => {
+        const score = card.checks[c];
+        return score === undefined ? chalk.dim('·'.padEnd(5)) : scoreColor(score)((score < 0 ? '?' : String(score)).padEnd(5));
+      }).join('');
+      lines.push(`  ${name}${scoreColor(card.score)(card.score.toFixed(1).padEnd(7))}${checks}`);
+    }
+    lines.push('');
+    lines.push(chalk.dim(`  ${KEY_CHECKS.map((c, i) => `${short[i].trim()} ${c}`).join('   ')}`));
+    lines.push('');
+  }
+
+  for (const warning of report.warnings.slice(0, 5)) {
+    lines.push(chalk.yellow(`⚠ ${warning}`));
+  }
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export async function scorecardCommand(dir: string, options: ScorecardCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeScorecards(projectRoot, { offline: Boolean(options.offline), signal }));
+
+  if (options.porcelain) {
+    // <module> <version> <direct> <repo> <score>, direct modules unless --all; unscored modules have no score
+    printPorcelain(report.modules.filter(m => options.all || m.direct).map(m => [m.module, m.version, m.direct, m.repo, m.scorecard?.score]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatScorecardReport(report, Boolean(options.all)));
+  }
+}
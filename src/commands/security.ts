@@ -2,17 +2,22 @@ import { resolve, dirname, join } from 'path';
 import { readFileSync } from 'fs';
 import { fileURLToPath } from 'url';
 import { buildGraph } from '../graph/index.js';
-import { parseProject } from '../parser/index.js';
 import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
 import { scanSecurity } from '../security/scanner.js';
 import { formatTable, formatJSON, formatSARIF } from '../security/reporter.js';
 import type { Severity, VulnerabilityClass } from '../security/types.js';
+import { attestIfRequested, type AttestFlags } from './attest.js';
+import { logger } from '../utils/log.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+const log = logger('security');
 
 const __filename = fileURLToPath(import.meta.url);
 const __dirname = dirname(__filename);
 
 // Walk up to find package.json from bundled location
-function getVersion(): string {
+export function getVersion(): string {
   try {
     let dir = __dirname;
     for (let i = 0; i < 5; i++) {
@@ -27,11 +32,12 @@ function getVersion(): string {
   return '0.0.0';
 }
 
-export interface SecurityCommandOptions {
+export interface SecurityCommandOptions extends AttestFlags, OutputFlags {
   target?: string;
   class?: string[];
   format?: string;
   failOn?: string;
+  deps?: boolean;
 }
 
 const SEVERITY_ORDER: Severity[] = ['critical', 'high', 'medium', 'low', 'info'];
@@ -41,21 +47,23 @@ export async function securityCommand(
   options: SecurityCommandOptions
 ): Promise<void> {
   const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
-  console.error(`Scanning: ${projectRoot}`);
+  log.info(`Scanning: ${projectRoot}`);
 
   const startTime = Date.now();
 
-  const parsedFiles = await parseProject(projectRoot);
-  console.error(`Parsed ${parsedFiles.length} files`);
+  const parsedFiles = await parseWithProgress(projectRoot);
+  log.info(`Parsed ${parsedFiles.length} files`);
 
-  const graph = buildGraph(parsedFiles, projectRoot);
-  console.error(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  log.info(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
 
   const result = await scanSecurity(projectRoot, graph, {
     target: options.target,
     classes: options.class as VulnerabilityClass[] | undefined,
     format: (options.format as 'table' | 'json' | 'sarif') || 'table',
     graphAware: true,
+    dependencySecrets: Boolean(options.deps),
+    parsedFiles,
   });
 
   const elapsedMs = Date.now() - startTime;
@@ -63,13 +71,17 @@ export async function securityCommand(
   // Format and output
   const format = options.format || 'table';
 
-  if (format === 'json') {
+  if (options.porcelain) {
+    // <severity> <class> <file> <line> <id> <title> <module>
+    printPorcelain(result.findings.map(f => [f.severity, f.vulnerabilityClass, f.file, f.line, f.id, f.title, f.module]));
+  } else if (format === 'json') {
     console.log(formatJSON(result));
   } else if (format === 'sarif') {
     console.log(formatSARIF(result, getVersion()));
   } else {
     console.log(formatTable(result, elapsedMs));
   }
+  await attestIfRequested(projectRoot, 'security', result, options);
 
   // Fail on severity threshold
   if (options.failOn) {
@@ -80,7 +92,7 @@ export async function securityCommand(
         f => SEVERITY_ORDER.indexOf(f.severity) <= thresholdIdx
       );
       if (hasFindings) {
-        console.error(`Findings at or above ${threshold} severity detected — exiting with code 1`);
+        log.error(`Findings at or above ${threshold} severity detected — exiting with code 1`);
         process.exit(1);
       }
     }
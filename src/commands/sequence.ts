@@ -0,0 +1,47 @@
+import { resolve } from 'path';
+import { writeFileSync } from 'fs';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeSequence, toMermaidSequence, type CallSequence } from '../golang/sequence.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('sequence');
+
+export interface SequenceCommandOptions {
+  depth?: string;
+  external?: boolean;
+  algo?: string;
+  format?: string;
+  output?: string;
+}
+
+// A section to paste into design docs: the diagram and what was left out of it
+function formatSequenceMarkdown(sequence: CallSequence): string {
+  const lines: string[] = [`## ${sequence.route ?? sequence.entry}`, ''];
+  if (sequence.route) lines.push(`Handled by \`${sequence.entry}\`.`, '');
+  lines.push('```mermaid', toMermaidSequence(sequence).trimEnd(), '```', '');
+  lines.push(`Calls between packages, ${sequence.depth} levels deep; calls within a package are followed but not drawn, and a function is expanded the first time it is called.`, '');
+  return lines.join('\n');
+}
+
+export async function sequenceCommand(entry: string, dir: string, options: SequenceCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const format = options.format ?? 'mermaid';
+  if (!['mermaid', 'markdown', 'json'].includes(format)) throw new Error(`Unknown format "${format}" (expected mermaid, markdown or json)`);
+  const depth = parseInt(options.depth ?? '4', 10);
+  if (!(depth > 0)) throw new Error('--depth must be a positive number');
+
+  const sequence = await withInterrupt((signal) => analyzeSequence(projectRoot, entry, { depth, external: options.external, algo: options.algo, signal }));
+  for (const warning of sequence.warnings) log.warn(warning);
+  if (sequence.calls.length === 0) log.warn(`${sequence.entry} makes no calls into other workspace packages`);
+
+  const output = format === 'json' ? JSON.stringify(sequence, null, 2) + '\n'
+    : format === 'markdown' ? formatSequenceMarkdown(sequence)
+    : toMermaidSequence(sequence);
+  if (options.output) {
+    writeFileSync(options.output, output);
+    log.info(`Wrote ${options.output}: ${sequence.messages} messages between ${sequence.participants.length} packages`);
+  } else {
+    process.stdout.write(output);
+  }
+}
@@ -0,0 +1,61 @@
+import { resolve } from 'path';
+import { findProjectRoot } from '../utils/files.js';
+import { createSpinner, withInterrupt } from '../utils/progress.js';
+import { Workspace } from '../serve/workspace.js';
+import { startGrpcServer } from '../serve/grpc.js';
+import { startRestServer } from '../serve/rest.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('serve');
+
+export interface ServeCommandOptions {
+  rest?: boolean;
+  port?: string;
+  grpc?: boolean;
+  grpcPort?: string;
+  host?: string;
+  watch?: boolean;
+}
+
+export async function serveCommand(dir: string, options: ServeCommandOptions): Promise<void> {
+  if (options.rest === false && !options.grpc) {
+    throw new Error('Nothing to serve — --no-rest needs --grpc');
+  }
+
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const host = options.host || '127.0.0.1';
+  const workspace = new Workspace(projectRoot);
+
+  const spinner = createSpinner('Loading workspace');
+  try {
+    await withInterrupt((signal) => workspace.load(signal, spinner.update));
+  } finally {
+    spinner.stop();
+  }
+  log.info(`Loaded ${workspace.parsedFiles.length} files, ${workspace.graph.order} symbols, ${workspace.graph.size} edges`);
+
+  if (options.watch) {
+    workspace.watch();
+  }
+
+  const servers: Array<{ close(): unknown }> = [];
+
+  if (options.rest !== false) {
+    const port = parseInt(options.port || '3334', 10);
+    servers.push(await startRestServer(workspace, { host, port }));
+    log.info(`REST API listening on http://${host}:${port}/api/v1`, { host, port });
+  }
+
+  if (options.grpc) {
+    const grpcPort = parseInt(options.grpcPort || '50051', 10);
+    servers.push(await startGrpcServer(workspace, { host, port: grpcPort }));
+    log.info(`gRPC API listening on ${host}:${grpcPort} (service depwire.v1.Depwire)`, { host, port: grpcPort });
+  }
+
+  process.on('SIGINT', async () => {
+    log.info('Shutting down...');
+    servers.forEach(server => server.close());
+    await workspace.close();
+    process.exit(0);
+  });
+}
@@ -0,0 +1,89 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { SimulationEngine, type SimulationAction } from '../simulation/engine.js';
+import { RuleRegistry, builtinRules, loadRuleModule, type RuleFinding } from '../rules/index.js';
+import { printResult } from './whatif.js';
+
+export interface SimulateOptions {
+  removeEdge?: string[];
+  movePackage?: string[];
+  rules?: string[];
+  builtin?: boolean;
+  format?: string;
+}
+
+function parseArrow(value: string, flag: string): [string, string] {
+  const parts = value.split('->').map((p) => p.trim());
+  if (parts.length !== 2 || !parts[0] || !parts[1]) {
+    throw new Error(`${flag} expects A->B, got "${value}"`);
+  }
+  return [parts[0], parts[1]];
+}
+
+function findingKey(f: RuleFinding): string {
+  return `${f.rule}|${f.source ?? f.file ?? ''}|${f.target ?? ''}|${f.message}`;
+}
+
+/**
+ * Evaluate a refactor on a hypothetical graph: health metrics, cycles and
+ * rule (layer) violations before and after, without touching the code.
+ */
+export async function simulateCommand(dir: string, options: SimulateOptions): Promise<void> {
+  const actions: SimulationAction[] = [];
+  for (const value of options.removeEdge ?? []) {
+    const [target, dependency] = parseArrow(value, '--remove-edge');
+    actions.push({ type: 'remove-edge', target, dependency });
+  }
+  for (const value of options.movePackage ?? []) {
+    const [target, destination] = parseArrow(value, '--move-package');
+    actions.push({ type: 'move-package', target, destination });
+  }
+  if (actions.length === 0) {
+    throw new Error('Nothing to simulate — pass --remove-edge A->B or --move-package X->dir');
+  }
+
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const registry = new RuleRegistry();
+  if (options.builtin !== false) {
+    registry.register(builtinRules);
+  }
+  for (const specifier of options.rules ?? []) {
+    registry.register(await loadRuleModule(specifier, projectRoot));
+  }
+
+  const parsedFiles = await parseWithProgress(projectRoot);
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  const result = new SimulationEngine(graph).simulateAll(actions);
+
+  const before = await registry.run(graph, projectRoot);
+  const after = await registry.run(result.simulatedGraphInstance!, projectRoot);
+  const beforeKeys = new Set(before.findings.map(findingKey));
+  const afterKeys = new Set(after.findings.map(findingKey));
+  const violations = {
+    introduced: after.findings.filter((f) => !beforeKeys.has(findingKey(f))),
+    resolved: before.findings.filter((f) => !afterKeys.has(findingKey(f))),
+  };
+
+  if (options.format === 'json') {
+    const { simulatedGraphInstance, ...serializable } = result;
+    console.log(JSON.stringify({ actions, ...serializable, violations }, null, 2));
+    return;
+  }
+
+  if (actions.length > 1) {
+    console.log(chalk.dim(`Applied ${actions.length} actions in order; showing the combined result`));
+  }
+  printResult(result);
+  console.log(
+    `${chalk.bold('Rule Violations:')} ${violations.introduced.length} introduced, ${violations.resolved.length} resolved`
+  );
+  for (const f of violations.introduced) {
+    console.log(`  ${chalk.red('+')} ${f.rule}: ${f.message}`);
+  }
+  for (const f of violations.resolved) {
+    console.log(`  ${chalk.green('-')} ${f.rule}: ${f.message}`);
+  }
+}
@@ -0,0 +1,82 @@
+import { resolve, join } from 'path';
+import { existsSync } from 'fs';
+import { buildGraph } from '../graph/index.js';
+import { packageOf } from '../graph/model.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, filterLintResult, type FilterOptions } from '../parser/filter.js';
+import { resolveLabelOptions, type LabelFlags } from '../export/labels.js';
+import { buildArchitectureReport } from '../report/index.js';
+import { RuleRegistry, builtinRules, dependencyBudgetRule } from '../rules/index.js';
+import { findLintConfig } from '../rules/config.js';
+import { LOCKFILE, readLock, frozenDependenciesRule } from '../rules/lockfile.js';
+import { WAIVERS_FILE, readWaivers, applyWaivers } from '../rules/waivers.js';
+import { loadGoProject } from '../golang/packages.js';
+import { packageSynopsis } from '../docs/reading-order.js';
+import { prepareVizData } from '../viz/data.js';
+import { generateArcDiagramHTML } from '../viz/generate-html.js';
+import { siteFiles, writeSite, SITE_FORMATS, type SiteFormat } from '../site/index.js';
+import type { LintResult } from '../rules/types.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('site');
+
+export interface SiteCommandOptions extends FilterOptions, LabelFlags {
+  format?: string;
+  output?: string;
+  /** --no-findings: leave lint findings out */
+  findings?: boolean;
+}
+
+const NEXT_STEPS: Record<SiteFormat, string> = {
+  html: 'publish the directory as is (GitHub Pages: upload it with actions/upload-pages-artifact)',
+  mkdocs: 'build it with mkdocs build (pip install mkdocs-material)',
+  hugo: 'build it with hugo from that directory',
+};
+
+export async function siteCommand(dir: string, options: SiteCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const format = (options.format ?? 'html') as SiteFormat;
+  if (!SITE_FORMATS.includes(format)) throw new Error(`Unknown format "${format}" (expected ${SITE_FORMATS.join(', ')})`);
+  const outDir = resolve(options.output ?? 'docs-site');
+  const filter = createFilter(projectRoot, options);
+  const config = findLintConfig(projectRoot);
+  const labels = resolveLabelOptions(options, config.labels);
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  const report = await buildArchitectureReport(graph, projectRoot, { labels });
+
+  const files = new Map<string, string[]>();
+  graph.forEachNode((_node, attrs) => {
+    const pkg = packageOf(attrs.filePath);
+    if (!files.has(pkg)) files.set(pkg, []);
+    if (!files.get(pkg)!.includes(attrs.filePath)) files.get(pkg)!.push(attrs.filePath);
+  });
+  for (const list of files.values()) list.sort();
+
+  const synopses = new Map<string, string>();
+  if (report.languages.Go) {
+    for (const pkg of (await loadGoProject(projectRoot)).packages.values()) {
+      const synopsis = packageSynopsis(pkg);
+      if (synopsis) synopses.set(pkg.dir, synopsis);
+    }
+  }
+
+  let lint: LintResult | null = null;
+  if (options.findings !== false) {
+    // What depwire lint reports without flags: the built-in rules, the config's budgets, the lockfile and waivers
+    const registry = new RuleRegistry();
+    registry.register(builtinRules);
+    if (config.budgets) registry.register(dependencyBudgetRule(config.budgets));
+    const lockFile = join(projectRoot, LOCKFILE);
+    if (existsSync(lockFile)) registry.register(frozenDependenciesRule(readLock(lockFile), { layers: config.layers }));
+    lint = filterLintResult(await registry.run(graph, projectRoot, { parsedFiles }), filter);
+    const waiversFile = join(projectRoot, WAIVERS_FILE);
+    if (existsSync(waiversFile)) lint = applyWaivers(lint, readWaivers(waiversFile), { approvers: config.approvers, keys: config.approverKeys }).result;
+  }
+
+  const graphHtml = generateArcDiagramHTML(prepareVizData(graph, projectRoot));
+  const written = writeSite(outDir, siteFiles({ report, files, synopses, lint }, format, graphHtml));
+  log.info(`Wrote ${written.written} files to ${outDir}${written.removed > 0 ? `, removed ${written.removed} stale ones` : ''}; ${NEXT_STEPS[format]}`);
+}
@@ -0,0 +1,42 @@
+import { resolve } from 'path';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeTaint } from '../taint/index.js';
+import { loadTaintConfig } from '../taint/config.js';
+import { formatTaintReport } from '../taint/display.js';
+import { logger } from '../utils/log.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+const log = logger('taint');
+
+export interface TaintCommandOptions extends OutputFlags {
+  config?: string;
+  builtin?: boolean;
+  format?: string;
+  limit?: string;
+  algo?: string;
+}
+
+export async function taintCommand(dir: string, options: TaintCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const config = loadTaintConfig(options.config ? resolve(options.config) : undefined, options.builtin !== false);
+  log.info(`Tracking taint: ${config.sources.length} sources, ${config.sinks.length} sinks, ${config.sanitizers.length} sanitizers`);
+
+  const result = await withInterrupt((signal) => analyzeTaint(projectRoot, { config, algo: options.algo, signal }));
+
+  if (options.porcelain) {
+    // <category> <file> <line> <function> <source> <sink> <approximate>
+    printPorcelain(result.findings.map(f => [f.category, f.file, f.line, f.function, f.source, f.sink, f.approximate]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(result, null, 2));
+  } else {
+    console.log(formatTaintReport(result, parseInt(options.limit ?? '20', 10)));
+  }
+
+  answerQuietly(result.findings.length > 0);
+
+  // Name-matched flows are for review; only flows through resolved calls fail the run
+  if (result.findings.some(f => !f.approximate)) {
+    process.exit(1);
+  }
+}
@@ -0,0 +1,65 @@
+import { resolve } from 'path';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { changedTargets, type TargetKind } from '../impact/targets.js';
+import { getChangedFiles, isGitRepo } from '../temporal/git.js';
+import { logger } from '../utils/log.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+const log = logger('targets');
+
+export interface TargetsCommandOptions extends FilterOptions, OutputFlags {
+  changedSince?: string;
+  files?: string[];
+  kind?: string;
+  always?: string[];
+  format?: string;
+}
+
+const KINDS: Record<string, TargetKind[]> = {
+  binary: ['binary'],
+  service: ['service'],
+  all: ['binary', 'service'],
+};
+
+export async function targetsCommand(dir: string, options: TargetsCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const kinds = KINDS[options.kind ?? 'all'];
+  if (!kinds) throw new Error(`Unknown kind "${options.kind}" (expected binary, service or all)`);
+  const format = options.format ?? 'lines';
+  if (!['lines', 'json', 'github-matrix'].includes(format)) {
+    throw new Error(`Unknown format "${format}" (expected lines, json or github-matrix)`);
+  }
+
+  let changedFiles: string[];
+  if (options.files && options.files.length > 0) {
+    changedFiles = options.files;
+  } else if (!isGitRepo(projectRoot)) {
+    throw new Error('Not a git repository — pass --files');
+  } else {
+    changedFiles = getChangedFiles(projectRoot, options.changedSince);
+  }
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  const result = changedTargets(graph, projectRoot, changedFiles, { kinds, always: options.always });
+  answerQuietly(result.targets.length > 0);
+
+  if (options.porcelain) {
+    // <kind> <name> <path> <import path> <detected by>
+    printPorcelain(result.targets.map(t => [t.kind, t.name, t.path, t.importPath, t.detectedBy]));
+  } else if (format === 'json') {
+    console.log(JSON.stringify(result, null, 2));
+  } else if (format === 'github-matrix') {
+    // For `strategy.matrix: ${{ fromJSON(needs.targets.outputs.matrix) }}`
+    const include = result.targets.map(t => ({ kind: t.kind, name: t.name, path: t.path, ...(t.importPath ? { importPath: t.importPath } : {}) }));
+    console.log(JSON.stringify({ include }));
+  } else {
+    // One path per line, nothing when nothing is affected: `for dir in $(depwire targets ...)`
+    for (const path of new Set(result.targets.map(t => t.path))) console.log(path);
+  }
+  log.info(`${result.targets.length} target${result.targets.length === 1 ? '' : 's'} affected by ${changedFiles.length} changed file${changedFiles.length === 1 ? '' : 's'}`, { targets: result.targets.length, changed: changedFiles.length });
+}
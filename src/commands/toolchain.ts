@@ -0,0 +1,65 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeGoVersions, type GoVersionReport } from '../golang/goversion.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface ToolchainCommandOptions extends OutputFlags {
+  all?: boolean;
+  format?: string;
+}
+
+function formatGoVersionReport(report: GoVersionReport, all: boolean): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold(`Depwire Toolchain Audit: ${report.module}`));
+  lines.push(`  go ${report.go ?? '(none)'}${report.toolchain ? `, toolchain ${report.toolchain}` : ''}${chalk.dim(`  — installed: ${report.installed ?? 'no go on PATH'}`)}`);
+  lines.push(`  Oldest go directive this module could declare: ${chalk.bold(report.floor.go)}`);
+  for (const reason of report.floor.reasons.slice(0, 5)) lines.push(chalk.dim(`    set by ${reason}`));
+  lines.push('');
+
+  const modules = report.modules.filter(m => all || m.direct || m.newerThanOurs);
+  if (modules.length > 0) {
+    lines.push(chalk.bold(`  ${all ? 'Modules' : 'Direct modules (and any needing a newer go)'}`));
+    const width = Math.min(50, Math.max(...modules.map(m => m.path.length)) + 2);
+    for (const m of modules) {
+      const go = m.go ? `go ${m.go}` : chalk.dim('go ?');
+      lines.push(`    ${m.path.padEnd(width)}${(m.newerThanOurs ? chalk.red(go.padEnd(10)) : go.padEnd(10))}${m.toolchain ? chalk.dim(` toolchain ${m.toolchain}`) : ''}`);
+    }
+    lines.push('');
+  }
+
+  if (report.features.length > 0) {
+    lines.push(chalk.bold('  Version-dependent features in our code'));
+    for (const f of report.features) {
+      lines.push(`    go ${f.since.padEnd(6)} ${f.feature} ${chalk.dim(`${f.uses}× e.g. ${f.example.file}:${f.example.line}`)}`);
+    }
+    lines.push('');
+  }
+
+  for (const finding of report.findings) lines.push(chalk.yellow(`  ⚠ ${finding}`));
+  for (const warning of report.warnings) lines.push(chalk.dim(`  ${warning}`));
+  if (report.findings.length + report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export async function toolchainCommand(dir: string, options: ToolchainCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeGoVersions(projectRoot, { signal }));
+
+  if (options.porcelain) {
+    // go <ours> <toolchain> <installed> <floor>; module <path> <version> <direct> <go> <toolchain> <newer than ours>;
+    // feature <since> <feature> <uses> <file> <line>; finding <message>
+    printPorcelain([
+      ['go', report.go, report.toolchain, report.installed, report.floor.go],
+      ...report.modules.filter(m => options.all || m.direct || m.newerThanOurs).map(m => ['module', m.path, m.version, m.direct, m.go, m.toolchain, m.newerThanOurs]),
+      ...report.features.map(f => ['feature', f.since, f.feature, f.uses, f.example.file, f.example.line]),
+      ...report.findings.map(f => ['finding', f]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatGoVersionReport(report, Boolean(options.all)));
+  }
+}
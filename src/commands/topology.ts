@@ -0,0 +1,99 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeCommunication, type CommunicationPoint, type CommunicationReport } from '../golang/topology.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface TopologyCommandOptions extends OutputFlags {
+  package?: string;
+  format?: string;
+  limit?: string;
+}
+
+const FLOW = { out: '→ out', in: '← in', both: '↔ both' };
+
+function describeUses(point: CommunicationPoint): string {
+  const outside = new Set(point.uses.filter(u => u.package !== point.package).map(u => u.package));
+  const writes = point.uses.filter(u => u.access === 'write').length;
+  const reads = point.uses.filter(u => u.access === 'read').length;
+  const counts = point.declaration === 'var' ? `${writes} writes, ${reads} reads` : `${point.uses.length} calls`;
+  return `${counts} from ${outside.size} other package${outside.size === 1 ? '' : 's'}`;
+}
+
+function formatTopologyReport(report: CommunicationReport, limit: number): string {
+  const lines: string[] = [];
+  const channels = report.points.filter(p => p.kind === 'channel');
+  const shared = report.points.filter(p => p.kind === 'shared-state');
+  lines.push('');
+  lines.push(chalk.bold('Depwire Communication Topology'));
+  lines.push(chalk.dim(`  ${report.edges.length} package edges through ${channels.length} channels and ${shared.length} shared variables`));
+  lines.push('');
+
+  if (report.points.length === 0) {
+    lines.push(chalk.dim('No channels or shared package variables cross a package boundary.'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  lines.push(chalk.bold('Edges'));
+  for (const edge of report.edges.slice(0, limit)) {
+    const via = edge.via.length > 3 ? [...edge.via.slice(0, 3), `+${edge.via.length - 3}`] : edge.via;
+    lines.push(`  ${edge.from} → ${edge.to}  ${chalk.dim(`${edge.kind} via ${via.join(', ')} (${edge.uses})`)}`);
+  }
+  if (report.edges.length > limit) lines.push(chalk.dim(`  … ${report.edges.length - limit} more (use --limit or --format json)`));
+  lines.push('');
+
+  const section = (title: string, points: CommunicationPoint[]) => {
+    if (points.length === 0) return;
+    lines.push(chalk.bold(title));
+    for (const point of points.slice(0, limit)) {
+      const flow = point.flow ? `${FLOW[point.flow]}  ` : '';
+      const type = point.type ? `${point.type}  ` : '';
+      lines.push(`  ${point.package}.${point.name}  ${chalk.cyan(`${flow}${type}`)}${chalk.dim(`${describeUses(point)}  ${point.file}:${point.line}`)}`);
+    }
+    if (points.length > limit) lines.push(chalk.dim(`  … ${points.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  };
+  section('Channels', channels);
+  section('Shared state', shared);
+  return lines.join('\n');
+}
+
+function formatTopologyDot(report: CommunicationReport): string {
+  const lines = ['digraph topology {', '  rankdir=LR;', '  node [shape=box, fontname="Helvetica"];'];
+  const quote = (s: string) => JSON.stringify(s);
+  for (const edge of report.edges) {
+    const style = edge.kind === 'shared-state' ? ', style=dashed' : '';
+    lines.push(`  ${quote(edge.from)} -> ${quote(edge.to)} [label=${quote(edge.via.join('\n'))}${style}];`);
+  }
+  lines.push('}');
+  return lines.join('\n');
+}
+
+export async function topologyCommand(dir: string, options: TopologyCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt(() => analyzeCommunication(projectRoot));
+
+  if (options.package) {
+    // Points the package declares or uses, and edges on either end; a suffix of the import path will do
+    const involves = (pkg: string) => pkg === options.package || pkg.endsWith(`/${options.package}`);
+    report.points = report.points.filter(p => involves(p.package) || p.uses.some(u => involves(u.package)));
+    report.edges = report.edges.filter(e => involves(e.from) || involves(e.to));
+  }
+  answerQuietly(report.edges.length > 0);
+
+  if (options.porcelain) {
+    // point <kind> <package> <name> <declaration> <flow> <type> <file> <line> <uses>; edge <from> <to> <kind> <uses> <via, space-separated>
+    printPorcelain([
+      ...report.points.map(p => ['point', p.kind, p.package, p.name, p.declaration, p.flow ?? '', p.type, p.file, p.line, p.uses.length]),
+      ...report.edges.map(e => ['edge', e.from, e.to, e.kind, e.uses, e.via.join(' ')]),
+    ]);
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else if (options.format === 'dot') {
+    console.log(formatTopologyDot(report));
+  } else {
+    console.log(formatTopologyReport(report, parseInt(options.limit ?? '30', 10)));
+  }
+}
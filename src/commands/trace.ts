@@ -0,0 +1,60 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { clearRuntimeEdges, importTraces, RUNTIME_EDGES_FILE, type TraceImport } from '../golang/trace.js';
+
+export interface TraceImportCommandOptions {
+  dir?: string;
+  replace?: boolean;
+  format?: string;
+  limit?: string;
+}
+
+function formatTraceImport(result: TraceImport, limit: number): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Runtime Trace Import'));
+  const samples = result.traces.reduce((n, t) => n + t.samples, 0);
+  lines.push(chalk.dim(`  ${result.traces.length} traces, ${samples} samples: ${result.recorded} cross-package calls recorded`));
+  lines.push(chalk.dim(`  ${result.confirmed} also found statically, ${result.dynamic.length} only seen at runtime, ${result.unmatched.length} not matched`));
+  lines.push('');
+
+  if (result.dynamic.length > 0) {
+    lines.push(chalk.bold('  Runtime-only calls'));
+    for (const edge of result.dynamic.slice(0, limit)) {
+      lines.push(`  ${edge.source} → ${chalk.bold(edge.target)}${edge.via ? chalk.dim(` via ${edge.via}`) : ''}` + chalk.dim(`  ${edge.count}×`));
+    }
+    if (result.dynamic.length > limit) lines.push(chalk.dim(`  … ${result.dynamic.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  const outside = result.unmatched.filter(u => u.reason === 'outside the project').length;
+  const missing = result.unmatched.length - outside;
+  if (outside > 0) lines.push(chalk.yellow(`  ⚠ ${outside} calls are in packages outside this project's modules`));
+  if (missing > 0) lines.push(chalk.yellow(`  ⚠ ${missing} calls are between functions the graph doesn't have (generic code, or source that changed since the trace)`));
+  if (result.skipped.length > 0) lines.push(chalk.yellow(`  ⚠ ${result.skipped.length} traces were imported before and not counted again: ${result.skipped.join(', ')}`));
+  lines.push(chalk.dim(`  ${result.stored} runtime edges in ${RUNTIME_EDGES_FILE}; depwire --runtime-edges <command> includes them in its graph`));
+  lines.push('');
+  return lines.join('\n');
+}
+
+/** depwire trace import <trace.json...> — merge runtime call edges recorded by depwiretrace */
+export async function traceImportCommand(files: string[], options: TraceImportCommandOptions): Promise<void> {
+  const projectRoot = options.dir ? resolve(options.dir) : findProjectRoot();
+  const graph = buildGraph(await parseWithProgress(projectRoot), projectRoot, graphOptions());
+  const result = importTraces(projectRoot, files.map(f => resolve(f)), graph, { replace: Boolean(options.replace) });
+
+  if (options.format === 'json') {
+    console.log(JSON.stringify(result, null, 2));
+  } else {
+    console.log(formatTraceImport(result, parseInt(options.limit ?? '30', 10)));
+  }
+}
+
+/** depwire trace clear — drop every imported trace */
+export async function traceClearCommand(options: { dir?: string }): Promise<void> {
+  const projectRoot = options.dir ? resolve(options.dir) : findProjectRoot();
+  console.log(clearRuntimeEdges(projectRoot) ? `Removed ${RUNTIME_EDGES_FILE}` : 'No runtime edges imported');
+}
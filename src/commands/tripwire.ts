@@ -0,0 +1,64 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { isGitRepo } from '../temporal/git.js';
+import { analyzeTripwire, type TripwireReport } from '../supply-chain/tripwire.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface TripwireCommandOptions extends OutputFlags {
+  base?: string;
+  algo?: string;
+  format?: string;
+}
+
+function formatTripwireReport(report: TripwireReport): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Init-Time Tripwire'));
+  lines.push(chalk.dim(report.base
+    ? `  ${report.modules.length} modules added since ${report.base}`
+    : `  ${report.modules.length} dependency modules`));
+  lines.push('');
+
+  if (report.findings.length === 0) {
+    lines.push(chalk.green('  ✓ No exec, network or payload decoding during package initialization'));
+    lines.push('');
+  }
+  for (const f of report.findings) {
+    const icon = f.severity === 'high' ? chalk.red('✗') : chalk.yellow('?');
+    lines.push(`  ${icon} ${chalk.bold(f.package)} ${chalk.dim(f.version)}  ${f.kind}: ${chalk.bold(f.call)}`);
+    f.chain.forEach((step, i) => {
+      lines.push(chalk.dim(`      ${'  '.repeat(i)}${i === 0 ? '' : '→ '}${step.function}  ${step.file}:${step.line}`));
+    });
+    lines.push(`      ${'  '.repeat(f.chain.length)}→ ${f.call}  ${chalk.dim(`${f.file}:${f.line}`)}`);
+  }
+  if (report.findings.length > 0) lines.push('');
+
+  for (const warning of report.warnings.slice(0, 5)) {
+    lines.push(chalk.yellow(`⚠ ${warning}`));
+  }
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+export async function tripwireCommand(dir: string, options: TripwireCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  if (options.base && !isGitRepo(projectRoot)) {
+    throw new Error('Not a git repository — --base needs git history');
+  }
+  const report = await withInterrupt((signal) => analyzeTripwire(projectRoot, { base: options.base, algo: options.algo, signal }));
+
+  if (options.porcelain) {
+    // <severity> <kind> <package> <version> <call> <file> <line>
+    printPorcelain(report.findings.map(f => [f.severity, f.kind, f.package, f.version, f.call, f.file, f.line]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatTripwireReport(report));
+  }
+
+  if (report.findings.some(f => f.severity === 'high')) {
+    process.exit(1);
+  }
+}
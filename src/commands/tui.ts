@@ -0,0 +1,104 @@
+import { resolve, basename, join } from 'path';
+import { spawnSync } from 'child_process';
+import { emitKeypressEvents } from 'readline';
+import { buildGraph } from '../graph/index.js';
+import { findProjectRoot } from '../utils/files.js';
+import { detectTerminal } from '../utils/terminal.js';
+import { parseWithProgress, graphOptions } from './load.js';
+import { createFilter, type FilterOptions } from '../parser/filter.js';
+import { buildExportGraph, type ExportLevel } from '../export/graph.js';
+import { applyMetric } from '../export/metrics.js';
+import { relabelGraph, resolveLabelOptions, type LabelFlags } from '../export/labels.js';
+import { findLintConfig } from '../rules/config.js';
+import { resolveCollapsePrefixes } from '../viz/collapse.js';
+import { Explorer, buildExplorerData, type Key } from '../tui/explorer.js';
+import type { SourcePosition } from '../graph/model.js';
+
+export interface TuiCommandOptions extends FilterOptions, LabelFlags {
+  level?: string;
+  metrics?: string[];
+  churnSince?: string;
+  ascii?: boolean;
+  /** Path or import-path prefixes to start collapsed */
+  collapse?: string[];
+}
+
+const ENTER_SCREEN = '\x1b[?1049h\x1b[?25l';
+const LEAVE_SCREEN = '\x1b[?25h\x1b[?1049l';
+
+// $VISUAL or $EDITOR at a line: +N for vi, emacs and nano; file:line:col for GUI editors
+function openInEditor(projectRoot: string, location: SourcePosition): string | null {
+  const editor = process.env.VISUAL || process.env.EDITOR || 'vi';
+  const [command, ...args] = editor.split(/\s+/);
+  const file = join(projectRoot, location.file);
+  const name = basename(command);
+  const at = `${file}:${location.line}${location.column ? `:${location.column}` : ''}`;
+  if (/^(code|codium|cursor|windsurf)/.test(name)) args.push('-g', at);
+  else if (/^(subl|zed|hx)/.test(name)) args.push(at);
+  else args.push(`+${location.line}`, file);
+  const result = spawnSync(command, args, { stdio: 'inherit', cwd: projectRoot });
+  return result.error ? `Could not run ${editor}: ${result.error.message}` : null;
+}
+
+export async function tuiCommand(dir: string, options: TuiCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const filter = createFilter(projectRoot, options);
+  const labels = resolveLabelOptions(options, findLintConfig(projectRoot).labels);
+  if (!process.stdin.isTTY || !process.stdout.isTTY) throw new Error('depwire tui needs an interactive terminal');
+  const level = (options.level ?? 'package') as ExportLevel;
+  if (level !== 'package' && level !== 'file') throw new Error(`Unknown level "${options.level}" (expected package or file)`);
+
+  const parsedFiles = await parseWithProgress(projectRoot, { filter: filter.includesFile });
+  const symbolGraph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  const graph = buildExportGraph(symbolGraph, projectRoot, { level, scope: filter.scope });
+  const metrics = options.metrics ?? [];
+  for (const metric of metrics) {
+    await applyMetric(graph, metric, { graph: symbolGraph, projectRoot, level, since: options.churnSince });
+  }
+  const terminal = detectTerminal(process.stdout, { ascii: options.ascii });
+  relabelGraph(graph, projectRoot, { ...labels, ellipsis: terminal.unicode ? '…' : '...' });
+  const collapsed = resolveCollapsePrefixes(projectRoot, options.collapse ?? []);
+  const explorer = new Explorer(buildExplorerData(symbolGraph, graph, { name: basename(projectRoot), level, metrics }), terminal, collapsed);
+
+  const { stdin, stdout } = process;
+  const draw = () => {
+    stdout.write(`\x1b[H${explorer.render(stdout.columns, stdout.rows).join('\x1b[K\n')}\x1b[K\x1b[J`);
+  };
+
+  await new Promise<void>((done) => {
+    const enter = () => {
+      stdout.write(ENTER_SCREEN);
+      stdin.setRawMode(true);
+      stdin.resume();
+      draw();
+    };
+    const leave = () => {
+      stdin.setRawMode(false);
+      stdin.pause();
+      stdout.write(LEAVE_SCREEN);
+    };
+    const onKey = (str: string | undefined, key: Key = {}) => {
+      const action = explorer.handleKey(str, key);
+      if (action?.type === 'quit') {
+        stdin.off('keypress', onKey);
+        stdout.off('resize', draw);
+        leave();
+        done();
+        return;
+      }
+      if (action?.type === 'open') {
+        leave();
+        const error = openInEditor(projectRoot, action.location);
+        if (error) explorer.notify(error);
+        enter();
+        return;
+      }
+      draw();
+    };
+
+    emitKeypressEvents(stdin);
+    stdin.on('keypress', onKey);
+    stdout.on('resize', draw);
+    enter();
+  });
+}
@@ -0,0 +1,63 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { isGitRepo } from '../temporal/git.js';
+import { checkTyposquats, type TyposquatReport } from '../supply-chain/typosquat.js';
+import { printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface TyposquatCommandOptions extends OutputFlags {
+  base?: string;
+  allow?: string[];
+  internal?: string[];
+  popular?: string[];
+  format?: string;
+}
+
+function formatTyposquatReport(report: TyposquatReport): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Typosquat Check'));
+  lines.push(chalk.dim(report.base
+    ? `  ${report.checked.length} modules new since ${report.base}`
+    : `  ${report.checked.length} required modules`));
+  lines.push('');
+
+  if (report.matches.length === 0) {
+    lines.push(chalk.green('  ✓ No suspicious module paths'));
+    lines.push('');
+    return lines.join('\n');
+  }
+  for (const m of report.matches) {
+    const icon = m.confidence === 'likely' ? chalk.red('✗') : chalk.yellow('?');
+    lines.push(`  ${icon} ${chalk.bold(m.module)} ${chalk.dim(m.version)}  ${chalk.dim(`${m.goModFile}:${m.line}`)}`);
+    lines.push(`      ${m.message}`);
+  }
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function typosquatCommand(dir: string, options: TyposquatCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  if (options.base && !isGitRepo(projectRoot)) {
+    throw new Error('Not a git repository — --base needs git history');
+  }
+
+  const report = checkTyposquats(projectRoot, options.base, {
+    allow: options.allow,
+    internalPrefixes: options.internal,
+    popular: options.popular,
+  });
+
+  if (options.porcelain) {
+    // <confidence> <module> <version> <resembles> <reason> <goModFile> <line>
+    printPorcelain(report.matches.map(m => [m.confidence, m.module, m.version, m.resembles, m.reason, m.goModFile, m.line]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatTyposquatReport(report));
+  }
+
+  if (report.matches.some(m => m.confidence === 'likely')) {
+    process.exit(1);
+  }
+}
@@ -0,0 +1,31 @@
+import { resolve } from 'path';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { analyzeUnsafe } from '../unsafe/index.js';
+import { formatUnsafeReport } from '../unsafe/display.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface UnsafeCommandOptions extends OutputFlags {
+  deps?: boolean;
+  unreachable?: boolean;
+  format?: string;
+  limit?: string;
+  algo?: string;
+  dynamicRoots?: boolean;
+}
+
+export async function unsafeCommand(dir: string, options: UnsafeCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => analyzeUnsafe(projectRoot, { dependencies: options.deps !== false, algo: options.algo, dynamicRoots: Boolean(options.dynamicRoots), signal }));
+  const shown = report.uses.filter(u => options.unreachable || u.reachable);
+
+  if (options.porcelain) {
+    // <risk> <package> <api> <file> <line> <function> <importPath> <module> <reachable>, reachable uses unless --unreachable
+    printPorcelain(shown.map(u => [u.risk, u.package, u.api, u.file, u.line, u.function, u.importPath, u.module, u.reachable]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatUnsafeReport(report, { limit: parseInt(options.limit ?? '30', 10), unreachable: Boolean(options.unreachable), dynamicRoots: Boolean(options.dynamicRoots) }));
+  }
+  answerQuietly(shown.length > 0);
+}
@@ -0,0 +1,49 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { findProjectRoot } from '../utils/files.js';
+import { withInterrupt } from '../utils/progress.js';
+import { verifyVendor, type VendorReport } from '../supply-chain/vendor.js';
+
+export interface VendorVerifyCommandOptions {
+  offline?: boolean;
+  proxy?: string;
+  cacheDir?: string;
+  format?: string;
+}
+
+function formatVendorReport(report: VendorReport): string {
+  const lines: string[] = [];
+  lines.push('');
+  lines.push(chalk.bold('Depwire Vendor Verification'));
+  lines.push(chalk.dim(`  ${report.modules} modules, ${report.packages} packages, ${report.filesChecked} files compared with module sources`));
+  lines.push('');
+  if (report.findings.length === 0) {
+    lines.push(chalk.green('  ✓ vendor/ matches go.mod, vendor/modules.txt and the module sources'));
+  }
+  for (const f of report.findings) {
+    const color = f.kind === 'unverified' ? chalk.yellow : chalk.red;
+    lines.push(`  ${color(f.kind.padEnd(17))} ${f.module ? chalk.bold(f.module) + ' ' : ''}${f.message}`);
+  }
+  lines.push('');
+  return lines.join('\n');
+}
+
+export async function vendorVerifyCommand(dir: string, options: VendorVerifyCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const report = await withInterrupt((signal) => verifyVendor(projectRoot, {
+    offline: Boolean(options.offline),
+    proxy: options.proxy,
+    cacheDir: options.cacheDir,
+    signal,
+  }));
+
+  if (options.format === 'json') {
+    console.log(JSON.stringify(report, null, 2));
+  } else {
+    console.log(formatVendorReport(report));
+  }
+
+  if (report.findings.some(f => f.kind !== 'unverified')) {
+    process.exit(1);
+  }
+}
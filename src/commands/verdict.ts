@@ -0,0 +1,26 @@
+import { resolve } from 'path';
+import { readVerdict, assertVerdict } from '../rules/verdict.js';
+import { RULE_ALIASES } from './lint.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('verdict');
+
+export interface VerdictAssertOptions {
+  rule?: string[];
+  commit?: string;
+  forbidWaivers?: boolean;
+}
+
+/** depwire verdict assert <verdict.json> — exit 1 unless the verdict holds */
+export async function verdictAssertCommand(file: string, options: VerdictAssertOptions): Promise<void> {
+  const verdict = readVerdict(resolve(file));
+  const rules = (options.rule ?? []).flatMap(v => v.split(',')).map(v => v.trim()).filter(Boolean).map(id => RULE_ALIASES[id] ?? id);
+  const failures = assertVerdict(verdict, { rules, commit: options.commit, noWaivers: options.forbidWaivers });
+
+  if (failures.length > 0) {
+    for (const failure of failures) log.error(`✗ ${failure}`);
+    process.exit(1);
+  }
+  const scope = rules.length > 0 ? rules.join(', ') : `${verdict.rules.length} rules`;
+  log.info(`✓ Verdict holds: ${scope} passed${verdict.commit ? ` at ${verdict.commit.slice(0, 12)}` : ''}`);
+}
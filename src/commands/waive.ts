@@ -0,0 +1,37 @@
+import { resolve, join } from 'path';
+import { existsSync, readFileSync, writeFileSync } from 'fs';
+import { findProjectRoot } from '../utils/files.js';
+import { WAIVERS_FILE, readWaivers, signWaiver, type Waiver } from '../rules/waivers.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('waive');
+
+export interface WaiveCommandOptions {
+  approver: string;
+  reason: string;
+  expires: string;
+  /** Approver's private key; the waiver is signed when given */
+  key?: string;
+  waivers?: string;
+}
+
+export async function waiveCommand(rule: string, fingerprint: string, dir: string, options: WaiveCommandOptions): Promise<void> {
+  const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
+  const file = options.waivers ? resolve(options.waivers) : join(projectRoot, WAIVERS_FILE);
+  if (!/^\d{4}-\d{2}-\d{2}$/.test(options.expires) || Number.isNaN(Date.parse(options.expires))) {
+    throw new Error(`Invalid --expires "${options.expires}" (expected YYYY-MM-DD)`);
+  }
+
+  let waiver: Waiver = { rule, fingerprint, approver: options.approver, reason: options.reason, expires: options.expires };
+  const keyPem = options.key ?? process.env.DEPWIRE_WAIVER_KEY;
+  if (keyPem) waiver = signWaiver(waiver, keyPem.includes('-----BEGIN') ? keyPem : readFileSync(keyPem, 'utf-8'));
+
+  // Replace an earlier waiver for the same finding, so renewing doesn't pile up entries
+  const waivers = (existsSync(file) ? readWaivers(file) : []).filter(w => {
+    const existing = w as Partial<Waiver>;
+    return existing.rule !== rule || existing.fingerprint !== fingerprint;
+  });
+  waivers.push(waiver);
+  writeFileSync(file, JSON.stringify({ waivers }, null, 2) + '\n');
+  log.info(`Waived ${rule} ${fingerprint} until ${options.expires}${waiver.signature ? `, signed by ${options.approver}` : ''} in ${file}`);
+}
@@ -1,11 +1,14 @@
 import { resolve } from 'path';
 import chalk from 'chalk';
-import { parseProject } from '../parser/index.js';
 import { buildGraph } from '../graph/index.js';
 import { findProjectRoot } from '../utils/files.js';
+import { parseWithProgress, graphOptions } from './load.js';
 import { SimulationEngine, SimulationAction, SimulationResult } from '../simulation/engine.js';
 import { prepareVizData } from '../viz/data.js';
 import { serveWhatIfViz } from '../viz/whatif-server.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('whatif');
 
 export interface WhatIfOptions {
   simulate?: string;
@@ -21,11 +24,11 @@ export async function whatif(dir: string, options: WhatIfOptions): Promise<void>
   if (!options.simulate) {
     // Phase B: open browser UI
     const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
-    console.error(`Parsing project: ${projectRoot}`);
+    log.info(`Parsing project: ${projectRoot}`);
 
-    const parsedFiles = await parseProject(projectRoot);
-    const graph = buildGraph(parsedFiles, projectRoot);
-    console.error(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
+    const parsedFiles = await parseWithProgress(projectRoot);
+    const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+    log.info(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
 
     const vizData = prepareVizData(graph, projectRoot);
 
@@ -45,12 +48,12 @@ export async function whatif(dir: string, options: WhatIfOptions): Promise<void>
   // Validate action type
   const validActions = ['move', 'delete', 'rename', 'split', 'merge'];
   if (!validActions.includes(options.simulate)) {
-    console.error(chalk.red(`Invalid action: ${options.simulate}. Must be one of: ${validActions.join(', ')}`));
+    log.error(`Invalid action: ${options.simulate}. Must be one of: ${validActions.join(', ')}`);
     process.exit(1);
   }
 
   if (!options.target) {
-    console.error(chalk.red('--target is required for all simulation actions'));
+    log.error('--target is required for all simulation actions');
     process.exit(1);
   }
 
@@ -59,14 +62,13 @@ export async function whatif(dir: string, options: WhatIfOptions): Promise<void>
 
   // Parse codebase
   const projectRoot = dir === '.' ? findProjectRoot() : resolve(dir);
-  console.error(`Parsing project: ${projectRoot}`);
+  log.info(`Parsing project: ${projectRoot}`);
 
-  const parsedFiles = await parseProject(projectRoot);
-  const graph = buildGraph(parsedFiles, projectRoot);
-  console.error(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
+  const parsedFiles = await parseWithProgress(projectRoot);
+  const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+  log.info(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
 
   // Run simulation
-  console.error('');
   const engine = new SimulationEngine(graph);
 
   try {
@@ -90,7 +92,7 @@ export async function whatif(dir: string, options: WhatIfOptions): Promise<void>
       action.target
     );
   } catch (err: any) {
-    console.error(chalk.red(`Simulation failed: ${err.message}`));
+    log.error('Simulation failed', { error: err });
     process.exit(1);
   }
 }
@@ -102,7 +104,7 @@ function buildAction(options: WhatIfOptions): SimulationAction {
   switch (type) {
     case 'move':
       if (!options.destination) {
-        console.error(chalk.red('--destination is required for move action'));
+        log.error('--destination is required for move action');
         process.exit(1);
       }
       return { type: 'move', target, destination: options.destination };
@@ -112,18 +114,18 @@ function buildAction(options: WhatIfOptions): SimulationAction {
 
     case 'rename':
       if (!options.newName) {
-        console.error(chalk.red('--new-name is required for rename action'));
+        log.error('--new-name is required for rename action');
         process.exit(1);
       }
       return { type: 'rename', target, newName: options.newName };
 
     case 'split':
       if (!options.newFile) {
-        console.error(chalk.red('--new-file is required for split action'));
+        log.error('--new-file is required for split action');
         process.exit(1);
       }
       if (!options.symbols) {
-        console.error(chalk.red('--symbols is required for split action (comma-separated)'));
+        log.error('--symbols is required for split action (comma-separated)');
         process.exit(1);
       }
       return {
@@ -135,18 +137,18 @@ function buildAction(options: WhatIfOptions): SimulationAction {
 
     case 'merge':
       if (!options.source) {
-        console.error(chalk.red('--source is required for merge action'));
+        log.error('--source is required for merge action');
         process.exit(1);
       }
       return { type: 'merge', target, source: options.source };
 
     default:
-      console.error(chalk.red(`Unknown action: ${type}`));
+      log.error(`Unknown action: ${type}`);
       process.exit(1);
   }
 }
 
-function printResult(result: SimulationResult): void {
+export function printResult(result: SimulationResult): void {
   const { action, healthDelta, diff } = result;
   const line = '\u2500'.repeat(45);
 
@@ -205,5 +207,9 @@ function formatAction(action: SimulationAction): string {
       return `SPLIT ${action.target} \u2192 ${action.newFile} (${action.symbols.join(', ')})`;
     case 'merge':
       return `MERGE ${action.source} \u2192 ${action.target}`;
+    case 'remove-edge':
+      return `REMOVE EDGE ${action.target} \u2192 ${action.dependency}`;
+    case 'move-package':
+      return `MOVE PACKAGE ${action.target} \u2192 ${action.destination}`;
   }
 }
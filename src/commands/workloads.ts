@@ -0,0 +1,101 @@
+import { resolve } from 'path';
+import chalk from 'chalk';
+import { withInterrupt } from '../utils/progress.js';
+import { clusterWorkloads, readManifests, workloadId, type Workload } from '../remote/kubernetes.js';
+import { auditWorkloads, type WorkloadAudit } from '../supply-chain/workloads.js';
+import { answerQuietly, printPorcelain, type OutputFlags } from '../utils/porcelain.js';
+
+export interface WorkloadsCommandOptions extends OutputFlags {
+  cluster?: boolean;
+  context?: string;
+  namespace?: string;
+  module?: string;
+  vuln?: string;
+  vulns?: boolean;
+  platform?: string;
+  format?: string;
+  limit?: string;
+}
+
+function formatWorkloadAudit(audit: WorkloadAudit, options: WorkloadsCommandOptions, limit: number): string {
+  const lines: string[] = [];
+  const failed = audit.images.filter(i => i.error).length;
+  const binaries = audit.images.reduce((n, i) => n + (i.report?.binaries.length ?? 0), 0);
+  lines.push('');
+  lines.push(chalk.bold('Depwire Workload Modules'));
+  lines.push(chalk.dim(`  ${audit.workloads.length} workloads, ${audit.images.length} images${failed ? ` (${failed} not read)` : ''}, ${binaries} Go binaries`));
+  lines.push('');
+
+  const query = options.vuln ?? options.module ?? (options.vulns ? null : undefined);
+  if (query === undefined) {
+    // No question asked: what each workload runs
+    const byImage = new Map(audit.images.map(i => [i.image, i]));
+    let shown = 0;
+    for (const workload of audit.workloads) {
+      if (shown++ >= limit) break;
+      lines.push(`  ${chalk.bold(workloadId(workload))}${chalk.dim(`  ${workload.source}`)}`);
+      for (const container of workload.containers) {
+        const report = byImage.get(container.image)?.report;
+        const contents = !report ? chalk.yellow('not read')
+          : report.binaries.length === 0 ? chalk.dim('no Go binaries')
+          : report.binaries.map(b => `${b.path} ${chalk.dim(`(${b.report.modules.length - 1} modules)`)}`).join(', ');
+        lines.push(`    ${container.name}${container.init ? chalk.dim(' (init)') : ''}  ${chalk.dim(container.image)} → ${contents}`);
+      }
+    }
+    if (audit.workloads.length > limit) lines.push(chalk.dim(`  … ${audit.workloads.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  } else {
+    const affected = new Set(audit.shipped.map(s => s.workload));
+    lines.push(query === null
+      ? chalk.bold(`  ${affected.size} workloads ship modules with known vulnerabilities`)
+      : chalk.bold(`  ${affected.size} workloads ship ${query}`));
+    let workload = '';
+    for (const s of audit.shipped.slice(0, limit)) {
+      if (s.workload !== workload) lines.push(`  ${chalk.bold(workload = s.workload)}`);
+      const ids = s.advisories.length ? chalk.red(`  ${s.advisories.join(', ')}`) : '';
+      lines.push(`    ${s.container}  ${chalk.dim(s.image)}  ${s.binary}  ${s.module} ${chalk.dim(s.version)}${ids}`);
+    }
+    if (audit.shipped.length > limit) lines.push(chalk.dim(`  … ${audit.shipped.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+
+    if (audit.advisories.length > 0) {
+      lines.push(chalk.bold('  Advisories'));
+      for (const a of audit.advisories) {
+        const aliases = a.aliases.length ? chalk.dim(` (${a.aliases.join(', ')})`) : '';
+        lines.push(`  ${a.id}${aliases}${a.summary ? `: ${a.summary}` : ''}`);
+      }
+      lines.push('');
+    }
+  }
+
+  for (const warning of audit.warnings) lines.push(chalk.yellow(`  ⚠ ${warning}`));
+  if (audit.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
+
+/** depwire workloads [manifests] — Kubernetes workloads → images → Go binaries → modules */
+export async function workloadsCommand(path: string | undefined, options: WorkloadsCommandOptions): Promise<void> {
+  const audit = await withInterrupt(async (signal) => {
+    const warnings: string[] = [];
+    let workloads: Workload[];
+    if (options.cluster) {
+      workloads = await clusterWorkloads({ context: options.context, namespace: options.namespace, signal });
+    } else {
+      workloads = readManifests(resolve(path ?? '.'), warnings);
+      if (options.namespace) workloads = workloads.filter(w => (w.namespace ?? 'default') === options.namespace);
+    }
+    const result = await auditWorkloads(workloads, { module: options.module, vuln: options.vuln, vulns: options.vulns, platform: options.platform, signal });
+    result.warnings.unshift(...warnings);
+    return result;
+  });
+
+  if (options.porcelain) {
+    // <workload> <container> <image> <binary> <module> <version> <advisories, comma-separated>
+    printPorcelain(audit.shipped.map(s => [s.workload, s.container, s.image, s.binary, s.module, s.version, s.advisories.join(',')]));
+  } else if (options.format === 'json') {
+    console.log(JSON.stringify(audit, null, 2));
+  } else {
+    console.log(formatWorkloadAudit(audit, options, parseInt(options.limit ?? '30', 10)));
+  }
+  answerQuietly(audit.shipped.length > 0);
+}
@@ -0,0 +1,171 @@
+import { existsSync, mkdirSync, readFileSync, readdirSync, statSync } from 'fs';
+import { join, relative } from 'path';
+import type { Command, Option } from 'commander';
+import { findGoModules } from '../golang/modules.js';
+import { writeFileAtomic } from '../utils/files.js';
+
+/**
+ * Shell completion. The bash, zsh and fish scripts (see scripts.ts) pass the
+ * words typed so far to the hidden `depwire __complete` command, which walks
+ * the commander tree and prints one candidate per line, optionally followed
+ * by a tab and a description. `:files` or `:dirs` alone tells the shell to
+ * fall back to its own path completion.
+ *
+ * Options that take packages or modules complete against the workspace,
+ * read from .depwire/completion.json. The cache is rebuilt — a directory
+ * walk, no parsing — when a go.mod changes or it is a few minutes old, so
+ * a TAB never waits for a full parse.
+ */
+
+export const FILES = ':files';
+export const DIRS = ':dirs';
+
+const CACHE_TTL = 5 * 60 * 1000;
+const SKIP_DIRS = new Set(['node_modules', 'vendor', 'dist', 'build', 'testdata']);
+
+export interface WorkspaceCompletions {
+  generated: number;
+  /** Newest go.mod mtime when generated */
+  stamp: number;
+  /** Import paths and project-relative directories of Go packages */
+  packages: string[];
+  /** Workspace modules and everything their go.mod files require */
+  modules: string[];
+}
+
+// Option values completed against the workspace, by long flag
+const PACKAGE_OPTIONS = new Set(['--package', '--focus']);
+const MODULE_OPTIONS = new Set(['--module']);
+// Options whose description lists their values ("Output format: table (default), json")
+const ENUM_OPTIONS = new Set(['--format', '--level', '--direction', '--kind']);
+
+function scanWorkspace(projectRoot: string, stamp: number): WorkspaceCompletions {
+  const goModules = findGoModules(projectRoot);
+  const packages = new Set<string>();
+  const modules = new Set<string>();
+  for (const mod of goModules) {
+    modules.add(mod.path);
+    for (const req of mod.mod.require) modules.add(req.path);
+  }
+  const nested = new Set(goModules.map(m => m.dir));
+
+  for (const mod of goModules) {
+    const visit = (dir: string) => {
+      let entries: string[];
+      try {
+        entries = readdirSync(dir);
+      } catch {
+        return;
+      }
+      const rel = relative(projectRoot, dir).split('\\').join('/') || '.';
+      if (rel !== mod.dir && nested.has(rel)) return;
+      if (entries.some(e => e.endsWith('.go'))) {
+        const sub = relative(join(projectRoot, mod.dir), dir).split('\\').join('/');
+        packages.add(sub ? `${mod.path}/${sub}` : mod.path);
+        if (rel !== '.') packages.add(rel);
+      }
+      for (const entry of entries) {
+        if (entry.startsWith('.') || entry.startsWith('_') || SKIP_DIRS.has(entry)) continue;
+        const full = join(dir, entry);
+        try {
+          if (statSync(full).isDirectory()) visit(full);
+        } catch { /* unreadable */ }
+      }
+    };
+    visit(join(projectRoot, mod.dir));
+  }
+  return { generated: Date.now(), stamp, packages: [...packages].sort(), modules: [...modules].sort() };
+}
+
+/** Packages and modules of the workspace, from .depwire/completion.json when it's current */
+export function workspaceCompletions(projectRoot: string): WorkspaceCompletions {
+  const goMods = findGoModules(projectRoot).map(m => join(projectRoot, m.goModFile));
+  const stamp = Math.max(0, ...goMods.map(f => statSync(f).mtimeMs));
+  const cacheFile = join(projectRoot, '.depwire', 'completion.json');
+  if (existsSync(cacheFile)) {
+    try {
+      const cached = JSON.parse(readFileSync(cacheFile, 'utf-8')) as WorkspaceCompletions;
+      if (cached.stamp === stamp && Date.now() - cached.generated < CACHE_TTL) return cached;
+    } catch { /* rebuild */ }
+  }
+  const fresh = scanWorkspace(projectRoot, stamp);
+  try {
+    mkdirSync(join(projectRoot, '.depwire'), { recursive: true });
+    writeFileAtomic(cacheFile, JSON.stringify(fresh));
+  } catch { /* read-only checkout: complete without caching */ }
+  return fresh;
+}
+
+const visibleCommands = (cmd: Command) => cmd.commands.filter(c => !c.name().startsWith('__'));
+
+function findOption(cmd: Command, flag: string): Option | undefined {
+  return cmd.options.find(o => o.long === flag || o.short === flag);
+}
+
+/** Values listed in an option's description, after the last colon */
+function enumValues(option: Option): string[] {
+  if (option.argChoices) return option.argChoices;
+  const list = /:\s*([^:]+)$/.exec(option.description)?.[1];
+  if (!list) return [];
+  return list.split(/,|\bor\b/).map(v => /^\s*([\w-]+)/.exec(v)?.[1]).filter((v): v is string => !!v);
+}
+
+function optionValues(option: Option, workspace: () => WorkspaceCompletions): string[] {
+  const flag = option.long ?? '';
+  if (PACKAGE_OPTIONS.has(flag)) return workspace().packages;
+  if (MODULE_OPTIONS.has(flag)) return workspace().modules;
+  if (ENUM_OPTIONS.has(flag) || option.argChoices) {
+    const values = enumValues(option);
+    if (values.length > 0) return values;
+  }
+  return [/dir\b/.test(flag) ? DIRS : FILES];
+}
+
+/**
+ * Completion candidates for `words` — the words after `depwire`, the last
+ * being the (possibly empty) word under the cursor.
+ */
+export function complete(program: Command, words: string[], projectRoot: string): string[] {
+  const current = words[words.length - 1] ?? '';
+  let workspaceCache: WorkspaceCompletions | null = null;
+  const workspace = () => (workspaceCache ??= workspaceCompletions(projectRoot));
+
+  let cmd = program;
+  let positional = 0;
+  let expecting: Option | null = null;
+  for (const word of words.slice(0, -1)) {
+    if (word.startsWith('-')) {
+      const option = findOption(cmd, word.split('=')[0]);
+      expecting = option && (option.required || option.variadic) && !word.includes('=') ? option : null;
+      continue;
+    }
+    if (expecting) {
+      if (!expecting.variadic) expecting = null;
+      continue;
+    }
+    const sub = positional === 0 ? cmd.commands.find(c => c.name() === word || c.aliases().includes(word)) : undefined;
+    if (sub) cmd = sub;
+    else positional++;
+  }
+
+  let candidates: string[];
+  if (expecting && (!current.startsWith('-') || !expecting.variadic)) {
+    candidates = optionValues(expecting, workspace);
+  } else if (current.startsWith('-')) {
+    candidates = cmd.options.filter(o => !o.hidden && o.long).map(o => `${o.long}\t${o.description}`);
+    candidates.push('--help\tDisplay help for command');
+  } else if (positional === 0 && visibleCommands(cmd).length > 0) {
+    candidates = visibleCommands(cmd).map(c => `${c.name()}\t${c.description()}`);
+  } else {
+    const args = cmd.registeredArguments;
+    const arg = args[Math.min(positional, args.length - 1)];
+    if (!arg || (positional >= args.length && !arg.variadic)) return [];
+    const name = arg.name();
+    if (/package/.test(name)) candidates = workspace().packages;
+    else if (name === 'module') candidates = workspace().modules;
+    else candidates = [/dir/.test(name) ? DIRS : FILES];
+  }
+
+  if (candidates[0] === FILES || candidates[0] === DIRS) return candidates.slice(0, 1);
+  return candidates.filter(c => c.startsWith(current));
+}
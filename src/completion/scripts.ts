@@ -0,0 +1,77 @@
+/**
+ * Completion scripts. Each asks `depwire __complete -- <words>` for
+ * candidates ("value" or "value<TAB>description" lines) and handles the
+ * :files and :dirs fallbacks with the shell's own path completion.
+ */
+
+export type CompletionShell = 'bash' | 'zsh' | 'fish';
+
+export const COMPLETION_SHELLS: CompletionShell[] = ['bash', 'zsh', 'fish'];
+
+const BASH = `# depwire bash completion — add to ~/.bashrc:
+#   source <(depwire completion bash)
+_depwire() {
+  local cur=\${COMP_WORDS[COMP_CWORD]} IFS=$'\\n'
+  local -a candidates
+  candidates=($(depwire __complete -- "\${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+  case "\${candidates[0]}" in
+    :files) COMPREPLY=($(compgen -f -- "$cur")); compopt -o filenames ;;
+    :dirs) COMPREPLY=($(compgen -d -- "$cur")); compopt -o filenames ;;
+    *) COMPREPLY=("\${candidates[@]%%$'\\t'*}") ;;
+  esac
+}
+complete -F _depwire depwire
+`;
+
+const ZSH = `#compdef depwire
+# depwire zsh completion — add to ~/.zshrc:
+#   source <(depwire completion zsh)
+# or save as _depwire in a directory on $fpath
+_depwire() {
+  local -a candidates values display
+  local c
+  candidates=("\${(@f)$(depwire __complete -- "\${(@)words[2,CURRENT]}" 2>/dev/null)}")
+  case "$candidates[1]" in
+    :files) _files ;;
+    :dirs) _files -/ ;;
+    *)
+      for c in "\${candidates[@]}"; do
+        [[ -z $c ]] && continue
+        values+=("\${c%%$'\\t'*}")
+        if [[ $c == *$'\\t'* ]]; then display+=("\${c%%$'\\t'*}  -- \${c#*$'\\t'}"); else display+=("$c"); fi
+      done
+      compadd -l -d display -a values
+      ;;
+  esac
+}
+if [[ "$funcstack[1]" == _depwire ]]; then
+  _depwire "$@"
+else
+  compdef _depwire depwire
+fi
+`;
+
+const FISH = `# depwire fish completion — save to ~/.config/fish/completions/depwire.fish:
+#   depwire completion fish > ~/.config/fish/completions/depwire.fish
+function __depwire_complete
+    set -l words (commandline -opc) (commandline -ct)
+    set -l candidates (depwire __complete -- $words[2..-1] 2>/dev/null)
+    switch "$candidates[1]"
+        case :files
+            __fish_complete_path (commandline -ct)
+        case :dirs
+            __fish_complete_directories (commandline -ct)
+        case '*'
+            printf '%s\\n' $candidates
+    end
+end
+complete -c depwire -f -a '(__depwire_complete)'
+`;
+
+export function completionScript(shell: CompletionShell): string {
+  switch (shell) {
+    case 'bash': return BASH;
+    case 'zsh': return ZSH;
+    case 'fish': return FISH;
+  }
+}
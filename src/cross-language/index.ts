@@ -3,6 +3,7 @@ import type { ParsedFile } from '../parser/types.js';
 import type { CrossLanguageEdge, CrossLanguageDetectionResult } from './types.js';
 import { detectRestApiEdges } from './detectors/rest-api.js';
 import { detectSubprocessEdges } from './detectors/subprocess.js';
+import { stableNodeId } from '../graph/stable-id.js';
 
 export function detectCrossLanguageEdges(
   files: ParsedFile[],
@@ -36,6 +37,7 @@ export function detectCrossLanguageEdges(
         startLine: 1,
         endLine: 1,
         exported: false,
+        stableId: stableNodeId({ id: sourceNodeId, kind: 'import', filePath: edge.sourceFile }),
       });
     }
 
@@ -53,6 +55,7 @@ export function detectCrossLanguageEdges(
         startLine: 1,
         endLine: 1,
         exported: false,
+        stableId: stableNodeId({ id: targetNodeId, kind: 'import', filePath: edge.targetFile }),
       });
     }
 
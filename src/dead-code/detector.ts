@@ -7,10 +7,11 @@ export function findDeadSymbols(
   graph: Graph,
   projectRoot: string,
   includeTests = false,
-  debug = false
+  debug = false,
+  dynamicRoots: Set<string> = new Set()
 ): { symbols: DeadSymbol[]; stats: ExclusionStats } {
   const deadSymbols: DeadSymbol[] = [];
-  const context: ExclusionContext = { graph, projectRoot };
+  const context: ExclusionContext = { graph, projectRoot, dynamicRoots };
   
   const stats: ExclusionStats = {
     total: 0,
@@ -20,6 +21,7 @@ export function findDeadSymbols(
     excludedByTypeDeclaration: 0,
     excludedByDefaultExport: 0,
     excludedByFrameworkDir: 0,
+    excludedByDynamicRoot: 0,
   };
 
   const packageEntryPoints = getPackageEntryPoints(projectRoot);
@@ -87,6 +89,7 @@ export function findDeadSymbols(
           case "types": stats.excludedByTypeDeclaration++; break;
           case "default": stats.excludedByDefaultExport++; break;
           case "framework": stats.excludedByFrameworkDir++; break;
+          case "dynamic": stats.excludedByDynamicRoot++; break;
         }
         continue;
       }
@@ -113,6 +116,7 @@ export function findDeadSymbols(
     console.log(`Excluded by type declaration: ${stats.excludedByTypeDeclaration}`);
     console.log(`Excluded by default export: ${stats.excludedByDefaultExport}`);
     console.log(`Excluded by framework dir: ${stats.excludedByFrameworkDir}`);
+    console.log(`Excluded by dynamic root: ${stats.excludedByDynamicRoot}`);
     console.log(`Remaining dead symbols: ${deadSymbols.length}\n`);
   }
 
@@ -228,6 +232,11 @@ function shouldExclude(
     return "framework";
   }
 
+  // Go functions reached through plugin.Open, reflect.New or a registry
+  if (context.dynamicRoots.has(`${relativePath.split(path.sep).join("/")}:${attrs.startLine}`)) {
+    return "dynamic";
+  }
+
   // C++ specific exclusions
   if (isCppExcluded(attrs)) {
     return "framework";
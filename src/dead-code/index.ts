@@ -16,13 +16,15 @@ export function analyzeDeadCode(
     stats: options.stats || false,
     json: options.json || false,
     debug: options.debug || false,
+    dynamicRoots: options.dynamicRoots,
   };
 
   const { symbols: rawDeadSymbols } = findDeadSymbols(
     graph, 
     projectRoot, 
     opts.includeTests,
-    opts.debug
+    opts.debug,
+    opts.dynamicRoots
   );
 
   const classifiedSymbols = classifyDeadSymbols(rawDeadSymbols, graph);
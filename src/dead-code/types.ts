@@ -32,11 +32,14 @@ export interface DeadCodeOptions {
   stats: boolean;
   json: boolean;
   debug: boolean;
+  /** "<file>:<line>" of Go functions reached through plugins, reflect.New or registries, kept as roots */
+  dynamicRoots?: Set<string>;
 }
 
 export interface ExclusionContext {
   graph: DirectedGraph;
   projectRoot: string;
+  dynamicRoots: Set<string>;
 }
 
 export interface ExclusionStats {
@@ -47,4 +50,5 @@ export interface ExclusionStats {
   excludedByTypeDeclaration: number;
   excludedByDefaultExport: number;
   excludedByFrameworkDir: number;
+  excludedByDynamicRoot: number;
 }
@@ -0,0 +1,76 @@
+import { describe, it } from 'node:test';
+import assert from 'node:assert';
+import { mkdtempSync, readFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { dirname, join } from 'path';
+import { fileURLToPath } from 'url';
+import { DirectedGraph } from 'graphology';
+import { assertGolden, normalizeOutput, EXPORTERS, type Fixture } from './depwiretest.js';
+import type { ExportGraph } from './export/graph.js';
+
+const GOLDEN = join(dirname(fileURLToPath(import.meta.url)), '..', 'test', 'golden');
+
+function createExportGraph(): ExportGraph {
+  const graph: ExportGraph = new DirectedGraph();
+  graph.addNode('cmd/app', { label: 'example.com/app/cmd/app', package: 'cmd/app', files: 1, symbols: 2 });
+  graph.addNode('store', { label: 'example.com/app/store', package: 'store', files: 2, symbols: 5 });
+  graph.addNode('github.com/lib/pq', { label: 'github.com/lib/pq', package: 'github.com/lib/pq', files: 0, symbols: 0, external: true });
+  graph.addEdge('cmd/app', 'store', { weight: 3 });
+  graph.addEdge('store', 'github.com/lib/pq', { weight: 1 });
+  return graph;
+}
+
+function render(format: keyof typeof EXPORTERS): string {
+  const exportGraph = createExportGraph();
+  const fixture: Fixture = { projectRoot: '/project', graph: new DirectedGraph(), exportGraph, generated: false };
+  return EXPORTERS[format](exportGraph, fixture);
+}
+
+describe('depwiretest', () => {
+  it('normalizeOutput should replace the project root, timestamps and uuids', () => {
+    const output = 'built /tmp/fixture/go.mod at 2026-10-14T09:30:00.123Z\r\nid urn:uuid:123e4567-e89b-12d3-a456-426614174000   \n\n';
+    assert.strictEqual(
+      normalizeOutput(output, { projectRoot: '/tmp/fixture' }),
+      'built <root>/go.mod at <time>\nid urn:uuid:<uuid>\n',
+    );
+  });
+
+  it('normalizeOutput should sort JSON keys, and arrays only when asked', () => {
+    const a = '{"nodes":[{"id":"b","label":"B"},{"id":"a","label":"A"}],"edges":[]}';
+    const b = '{"edges":[],"nodes":[{"label":"B","id":"b"},{"label":"A","id":"a"}]}';
+    assert.strictEqual(normalizeOutput(a), normalizeOutput(b));
+    assert.notStrictEqual(normalizeOutput(a), normalizeOutput('{"edges":[],"nodes":[{"id":"a","label":"A"},{"id":"b","label":"B"}]}'));
+    assert.strictEqual(
+      normalizeOutput(a, { sortArrays: true }),
+      normalizeOutput('{"edges":[],"nodes":[{"id":"a","label":"A"},{"id":"b","label":"B"}]}', { sortArrays: true }),
+    );
+  });
+
+  it('normalizeOutput should sort runs of lines at the same indentation', () => {
+    const output = 'graph {\n  b;\n  a;\n    nested;\n  d;\n  c;\n}\n';
+    assert.strictEqual(normalizeOutput(output, { sortLines: true }), 'graph {\n  a;\n  b;\n    nested;\n  c;\n  d;\n}\n');
+  });
+
+  it('assertGolden should write with update and fail on a change', () => {
+    const dir = mkdtempSync(join(tmpdir(), 'depwiretest-'));
+    try {
+      const golden = join(dir, 'out.txt');
+      assert.throws(() => assertGolden('a\n', golden, { update: false }), /No golden file/);
+
+      assertGolden('a\nb\n', golden, { update: true });
+      assert.strictEqual(readFileSync(golden, 'utf-8'), 'a\nb\n');
+      assertGolden('a\r\nb  \n', golden, { update: false });
+      assert.throws(() => assertGolden('a\nc\n', golden, { update: false }), /first difference at line 2:\n {2}a\n- b\n\+ c/);
+    } finally {
+      rmSync(dir, { recursive: true, force: true });
+    }
+  });
+
+  it('mermaid output should match its golden file', () => {
+    assertGolden(render('mermaid'), join(GOLDEN, 'export.mermaid'));
+  });
+
+  it('dot output should match its golden file', () => {
+    assertGolden(render('dot'), join(GOLDEN, 'export.dot'));
+  });
+});
@@ -0,0 +1,219 @@
+import { existsSync, mkdirSync, mkdtempSync, readFileSync, realpathSync, rmSync, writeFileSync } from 'fs';
+import { tmpdir } from 'os';
+import { dirname, join, resolve } from 'path';
+import { AssertionError } from 'assert';
+import type { DirectedGraph } from 'graphology';
+import { parseProject } from './parser/index.js';
+import { buildGraph } from './graph/index.js';
+import type { DependencyScope } from './parser/filter.js';
+import { buildExportGraph, toGraphDocument, type ExportGraph, type ExportLevel } from './export/graph.js';
+import { toDot } from './export/dot.js';
+import { toSvg } from './export/svg.js';
+import { toGexf } from './export/gexf.js';
+import { toMermaid } from './export/mermaid.js';
+import { generateFixture, writeFixture, type FixtureOptions } from './golang/fixture.js';
+
+/**
+ * depwire-cli/depwiretest — golden-file tests for output formats.
+ *
+ * Load a fixture project, render it with an exporter (one of depwire's
+ * formats or your own function of the export graph) and compare the output
+ * with a file checked in next to the test. Before the comparison the
+ * output is normalized: line endings, trailing whitespace, the fixture's
+ * absolute path, timestamps and UUIDs, and for JSON the order of object
+ * keys. Only a regression in the output itself fails the test.
+ *
+ *   import { it } from 'node:test';
+ *   import { goldenExport } from 'depwire-cli/depwiretest';
+ *
+ *   it('renders the sample project', async () => {
+ *     await goldenExport('test/fixtures/go-project', myExporter, 'test/golden/go-project.txt');
+ *   });
+ *
+ * Run with DEPWIRE_UPDATE_GOLDEN=1 to write the golden files instead, then
+ * review the diff like any other change.
+ */
+
+export interface Fixture {
+  projectRoot: string;
+  /** The symbol graph, as from buildGraph() */
+  graph: DirectedGraph;
+  /** What the exporters render */
+  exportGraph: ExportGraph;
+  /** Generated from fixture options into a temporary directory, which the caller removes */
+  generated: boolean;
+}
+
+export interface LoadFixtureOptions {
+  level?: ExportLevel;
+  scope?: DependencyScope;
+}
+
+/** Renders an export graph; the built-in formats are in EXPORTERS */
+export type Exporter = (graph: ExportGraph, fixture: Fixture) => string;
+
+export const EXPORTERS: Record<'dot' | 'json' | 'gexf' | 'mermaid' | 'svg', Exporter> = {
+  dot: (graph) => toDot(graph),
+  json: (graph) => JSON.stringify(toGraphDocument(graph), null, 2) + '\n',
+  gexf: (graph) => toGexf(graph),
+  mermaid: (graph) => toMermaid(graph),
+  svg: (graph) => toSvg(graph),
+};
+
+export interface NormalizeOptions {
+  /** Replaced by <root>, with its real path */
+  projectRoot?: string;
+  /** Sort each run of consecutive lines at the same indentation, for formats that don't fix their order */
+  sortLines?: boolean;
+  /** JSON: sort arrays as well as object keys */
+  sortArrays?: boolean;
+  /** Further replacements, applied last */
+  replace?: Array<[RegExp | string, string]>;
+}
+
+export interface GoldenOptions extends NormalizeOptions {
+  /** Write the golden file instead of comparing (default: DEPWIRE_UPDATE_GOLDEN=1) */
+  update?: boolean;
+}
+
+/**
+ * Parse a fixture directory and build its export graph. Given fixture
+ * options instead, a project of that shape is generated (see generateFixture)
+ * into a temporary directory first.
+ */
+export async function loadFixture(fixture: string | FixtureOptions, options: LoadFixtureOptions = {}): Promise<Fixture> {
+  let projectRoot: string;
+  const generated = typeof fixture !== 'string';
+  if (typeof fixture === 'string') {
+    projectRoot = resolve(fixture);
+    if (!existsSync(projectRoot)) throw new Error(`No fixture at ${projectRoot}`);
+  } else {
+    projectRoot = mkdtempSync(join(tmpdir(), 'depwiretest-'));
+    const { files, shape } = generateFixture(fixture);
+    writeFixture(projectRoot, files, shape);
+  }
+  const graph = buildGraph(await parseProject(projectRoot), projectRoot);
+  return { projectRoot, graph, exportGraph: buildExportGraph(graph, projectRoot, options), generated };
+}
+
+const TIMESTAMP = /\b\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?/g;
+const UUID = /\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b/gi;
+
+function sortJson(value: unknown, arrays: boolean): unknown {
+  if (Array.isArray(value)) {
+    const items = value.map(v => sortJson(v, arrays));
+    if (!arrays) return items;
+    return items.map(v => [JSON.stringify(v), v] as const).sort((a, b) => a[0] < b[0] ? -1 : a[0] > b[0] ? 1 : 0).map(([, v]) => v);
+  }
+  if (value && typeof value === 'object') {
+    return Object.fromEntries(Object.keys(value).sort().map(key => [key, sortJson((value as Record<string, unknown>)[key], arrays)]));
+  }
+  return value;
+}
+
+function sortLineRuns(lines: string[]): string[] {
+  const indent = (line: string) => line.length - line.trimStart().length;
+  const out: string[] = [];
+  for (let i = 0; i < lines.length;) {
+    let j = i + 1;
+    // Blank lines end a run
+    if (lines[i] !== '') while (j < lines.length && lines[j] !== '' && indent(lines[j]) === indent(lines[i])) j++;
+    out.push(...lines.slice(i, j).sort());
+    i = j;
+  }
+  return out;
+}
+
+/** The output with everything that changes between runs and machines replaced */
+export function normalizeOutput(output: string, options: NormalizeOptions = {}): string {
+  let text = output.replace(/\r\n/g, '\n');
+  if (options.projectRoot) {
+    const roots = new Set([resolve(options.projectRoot)]);
+    try {
+      roots.add(realpathSync(options.projectRoot));
+    } catch {
+      // A root that is gone has no other name
+    }
+    // Longest first, so a root inside another doesn't leave a suffix behind
+    for (const root of [...roots].sort((a, b) => b.length - a.length)) text = text.split(root).join('<root>');
+  }
+  text = text.replace(TIMESTAMP, '<time>').replace(UUID, '<uuid>');
+
+  const trimmed = text.trim();
+  if (trimmed.startsWith('{') || trimmed.startsWith('[')) {
+    try {
+      text = JSON.stringify(sortJson(JSON.parse(trimmed), !!options.sortArrays), null, 2) + '\n';
+    } catch {
+      // Not JSON after all; compared as text
+    }
+  }
+
+  let lines = text.split('\n').map(line => line.trimEnd());
+  if (options.sortLines) lines = sortLineRuns(lines);
+  text = lines.join('\n').replace(/\n*$/, '\n');
+
+  for (const [pattern, replacement] of options.replace ?? []) {
+    text = typeof pattern === 'string' ? text.split(pattern).join(replacement) : text.replace(pattern, replacement);
+  }
+  return text;
+}
+
+// The first differing line with a few lines of context either side
+function describeMismatch(expected: string, actual: string): string {
+  const want = expected.replace(/\n$/, '').split('\n');
+  const got = actual.replace(/\n$/, '').split('\n');
+  let line = 0;
+  while (line < want.length && line < got.length && want[line] === got[line]) line++;
+  const from = Math.max(0, line - 2);
+  const out = [`first difference at line ${line + 1}:`];
+  for (let i = from; i < line; i++) out.push(`  ${want[i]}`);
+  for (let i = line; i < Math.min(want.length, line + 3); i++) out.push(`- ${want[i]}`);
+  for (let i = line; i < Math.min(got.length, line + 3); i++) out.push(`+ ${got[i]}`);
+  return out.join('\n');
+}
+
+/**
+ * Compare output with a golden file after normalizing both. Fails with the
+ * first differing line; with update set (or DEPWIRE_UPDATE_GOLDEN=1) the
+ * golden file is written instead.
+ */
+export function assertGolden(actual: string, goldenFile: string, options: GoldenOptions = {}): void {
+  const update = options.update ?? process.env.DEPWIRE_UPDATE_GOLDEN === '1';
+  const normalized = normalizeOutput(actual, options);
+  if (update) {
+    mkdirSync(dirname(goldenFile), { recursive: true });
+    writeFileSync(goldenFile, normalized);
+    return;
+  }
+  if (!existsSync(goldenFile)) {
+    throw new AssertionError({ message: `No golden file ${goldenFile}; run with DEPWIRE_UPDATE_GOLDEN=1 to create it` });
+  }
+  const expected = normalizeOutput(readFileSync(goldenFile, 'utf-8'), options);
+  if (normalized !== expected) {
+    throw new AssertionError({
+      message: `Output differs from ${goldenFile} (run with DEPWIRE_UPDATE_GOLDEN=1 to accept it)\n${describeMismatch(expected, normalized)}`,
+      expected,
+      actual: normalized,
+      operator: 'strictEqual',
+    });
+  }
+}
+
+/** Load a fixture, render it and compare the output with a golden file */
+export async function goldenExport(
+  fixture: string | FixtureOptions,
+  exporter: keyof typeof EXPORTERS | Exporter,
+  goldenFile: string,
+  options: GoldenOptions & LoadFixtureOptions = {},
+): Promise<string> {
+  const render = typeof exporter === 'string' ? EXPORTERS[exporter] : exporter;
+  if (!render) throw new Error(`Unknown format "${exporter}" (expected ${Object.keys(EXPORTERS).join(', ')} or an exporter function)`);
+  const loaded = await loadFixture(fixture, options);
+  try {
+    const output = render(loaded.exportGraph, loaded);
+    assertGolden(output, goldenFile, { ...options, projectRoot: options.projectRoot ?? loaded.projectRoot });
+    return output;
+  } finally {
+    if (loaded.generated) rmSync(loaded.projectRoot, { recursive: true, force: true });
+  }
+}
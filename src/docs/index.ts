@@ -17,4 +17,5 @@ export { generateHistory } from './history.js';
 export { generateCurrent } from './current.js';
 export { generateStatus } from './status.js';
 export { generateHealth } from './health.js';
+export { packageDependencies, writePackageDependencyDocs, type PackageDependencies, type PackageDocOptions } from './packages.js';
 export { createMetadata, updateMetadata, loadMetadata, saveMetadata, type ProjectMetadata, type DocMetadata } from './metadata.js';
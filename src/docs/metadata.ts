@@ -1,5 +1,8 @@
 import { existsSync, readFileSync, writeFileSync } from 'fs';
 import { join, resolve } from 'path';
+import { logger } from '../utils/log.js';
+
+const log = logger('docs');
 
 export interface DocMetadata {
   generated_at: string;
@@ -31,7 +34,7 @@ export function loadMetadata(outputDir: string): ProjectMetadata | null {
     const content = readFileSync(metadataPath, 'utf-8');
     return JSON.parse(content);
   } catch (err) {
-    console.error('Failed to load metadata:', err);
+    log.warn('Failed to load metadata', { error: err });
     return null;
   }
 }
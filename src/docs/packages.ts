@@ -0,0 +1,137 @@
+import { existsSync, readFileSync, writeFileSync } from 'fs';
+import { join } from 'path';
+import type { GoProject } from '../golang/packages.js';
+
+/**
+ * Per-package dependency docs: a generated section in each Go package's
+ * doc.go (or a DEPENDENCIES.md next to it) listing the workspace packages
+ * and external modules it imports and the packages importing it. Meant to
+ * be kept current with `//go:generate depwire docs --package-deps`.
+ *
+ * The doc.go section sits between //depwire:begin and //depwire:end lines;
+ * those are directive comments, which godoc leaves out, so only the
+ * "Dependencies" heading and lists show in the package documentation.
+ * Everything outside the markers is left as written.
+ */
+
+const BEGIN = '//depwire:begin dependencies';
+const END = '//depwire:end dependencies';
+const SIDECAR = 'DEPENDENCIES.md';
+
+export interface PackageDependencies {
+  /** Project-relative directory */
+  dir: string;
+  name: string;
+  importPath: string | null;
+  /** Workspace packages it imports, by import path */
+  imports: string[];
+  /** Third-party import paths */
+  external: string[];
+  /** Workspace packages importing it */
+  importedBy: string[];
+}
+
+export interface PackageDocOptions {
+  /** Write DEPENDENCIES.md instead of a section in doc.go */
+  sidecar?: boolean;
+  /** Only these package directories */
+  packages?: string[];
+}
+
+export function packageDependencies(project: GoProject): PackageDependencies[] {
+  const result = new Map<string, PackageDependencies>();
+  for (const pkg of project.packages.values()) {
+    result.set(pkg.dir, { dir: pkg.dir, name: pkg.name, importPath: pkg.importPath, imports: [], external: [], importedBy: [] });
+  }
+  for (const pkg of project.packages.values()) {
+    const deps = result.get(pkg.dir)!;
+    for (const path of pkg.imports.keys()) {
+      const dir = project.modules.dirForImport(path);
+      if (dir !== null && result.has(dir)) {
+        deps.imports.push(path);
+        result.get(dir)!.importedBy.push(pkg.importPath ?? pkg.dir);
+      } else if (path.split('/')[0].includes('.')) {
+        // The standard library has no dot in its first path element
+        deps.external.push(path);
+      }
+    }
+  }
+  for (const deps of result.values()) {
+    deps.imports.sort();
+    deps.external.sort();
+    deps.importedBy.sort();
+  }
+  return [...result.values()].sort((a, b) => a.dir.localeCompare(b.dir));
+}
+
+/** Write or update each package's section; returns the project-relative files that changed */
+export function writePackageDependencyDocs(projectRoot: string, packages: PackageDependencies[], options: PackageDocOptions = {}): string[] {
+  const changed: string[] = [];
+  for (const deps of packages) {
+    if (options.packages && !options.packages.includes(deps.dir)) continue;
+    const file = deps.dir === '.' ? (options.sidecar ? SIDECAR : 'doc.go') : `${deps.dir}/${options.sidecar ? SIDECAR : 'doc.go'}`;
+    const path = join(projectRoot, file);
+    const before = existsSync(path) ? readFileSync(path, 'utf-8') : null;
+    const after = options.sidecar ? dependencyMarkdown(deps) : injectDocSection(before, deps);
+    if (after === before) continue;
+    writeFileSync(path, after);
+    changed.push(file);
+  }
+  return changed;
+}
+
+/** The section as Go comment lines, markers included */
+export function dependencyDocSection(deps: PackageDependencies): string[] {
+  const lines = [BEGIN, '//', '// # Dependencies', '//'];
+  const list = (title: string, items: string[]) => {
+    if (items.length === 0) return;
+    lines.push(`// ${title}:`, '//');
+    for (const item of items) lines.push(`//   - ${item}`);
+    lines.push('//');
+  };
+  list('Imports', deps.imports);
+  list('External imports', deps.external);
+  list('Imported by', deps.importedBy);
+  if (deps.imports.length + deps.external.length + deps.importedBy.length === 0) lines.push('// No workspace or external dependencies, and no workspace package imports it.', '//');
+  lines.pop();
+  lines.push(END);
+  return lines;
+}
+
+/**
+ * doc.go with the section replaced, or added at the end of the package
+ * comment. A doc.go without a package comment gets the section as one; a
+ * missing doc.go is created with just the section and the package clause.
+ */
+export function injectDocSection(source: string | null, deps: PackageDependencies): string {
+  const section = dependencyDocSection(deps);
+  if (source === null) return [...section, `package ${deps.name}`, ''].join('\n');
+
+  const lines = source.split('\n');
+  const begin = lines.findIndex(l => l.trim() === BEGIN);
+  const end = begin === -1 ? -1 : lines.findIndex((l, i) => i > begin && l.trim() === END);
+  if (begin !== -1 && end !== -1) {
+    lines.splice(begin, end - begin + 1, ...section);
+    return lines.join('\n');
+  }
+
+  const clause = lines.findIndex(l => /^package\s+\w+/.test(l));
+  if (clause === -1) throw new Error(`doc.go of ${deps.dir} has no package clause`);
+  // Directly above the clause, the last line of the package comment if there is one
+  const commented = clause > 0 && lines[clause - 1].startsWith('//');
+  lines.splice(clause, 0, ...(commented ? ['//', ...section] : section));
+  return lines.join('\n');
+}
+
+export function dependencyMarkdown(deps: PackageDependencies): string {
+  const lines: string[] = ['<!-- Code generated by depwire docs --package-deps; DO NOT EDIT. -->', ''];
+  lines.push(`# ${deps.importPath ?? deps.dir} dependencies`, '');
+  const list = (title: string, items: string[], none: string) => {
+    lines.push(`## ${title}`, '');
+    lines.push(...(items.length > 0 ? items.map(item => `- \`${item}\``) : [none]), '');
+  };
+  list('Imports', deps.imports, 'No workspace packages.');
+  list('External imports', deps.external, 'No third-party packages.');
+  list('Imported by', deps.importedBy, 'No workspace package imports it.');
+  return lines.join('\n');
+}
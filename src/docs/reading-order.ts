@@ -0,0 +1,162 @@
+import { basename } from 'path';
+import { DirectedGraph } from 'graphology';
+import { stronglyConnectedComponents } from '../graph/algorithms.js';
+import { toGoImportGraph, type GoPackage, type GoProject } from '../golang/packages.js';
+import { packageComment, docSynopsis } from '../golang/source.js';
+
+/**
+ * A reading order for new engineers: Go packages from the leaves (models,
+ * config, helpers that import nothing else in the workspace) toward the
+ * entry points (package main), so each package is read after what it
+ * builds on. Packages in an import cycle are read together. Each step says
+ * why it comes where it does, with the package's synopsis from its doc
+ * comment.
+ */
+
+export type ReadingRole = 'foundation' | 'core' | 'entry';
+
+export interface ReadingStep {
+  /** 1-based position in the order */
+  step: number;
+  dir: string;
+  importPath: string | null;
+  name: string;
+  role: ReadingRole;
+  /** Longest chain of workspace imports below the package; 0 for leaves */
+  level: number;
+  /** First sentence of the package comment */
+  synopsis: string | null;
+  /** Workspace packages it imports, all earlier in the order (or in the same cycle) */
+  buildsOn: string[];
+  /** Workspace packages importing it */
+  importedBy: number;
+  /** The other packages of its import cycle */
+  cycleWith: string[];
+  /** Why the package is at this point of the order */
+  reason: string;
+}
+
+export interface ReadingOrder {
+  projectRoot: string;
+  steps: ReadingStep[];
+  /** Packages without a package comment */
+  undocumented: number;
+}
+
+/** Listed in a reason before "and N more" */
+const MAX_NAMED = 3;
+
+export function packageSynopsis(pkg: GoPackage): string | null {
+  // go doc prefers doc.go; otherwise the first file with a package comment
+  const isDoc = (file: string) => basename(file) === 'doc.go';
+  const files = pkg.files.filter(f => !f.isTest).sort((a, b) => Number(isDoc(b.file)) - Number(isDoc(a.file)) || a.file.localeCompare(b.file));
+  for (const file of files) {
+    const doc = packageComment(file);
+    if (doc) return docSynopsis(doc);
+  }
+  return null;
+}
+
+export function readingOrder(project: GoProject): ReadingOrder {
+  const graph = toGoImportGraph(project);
+  for (const pkg of project.packages.values()) {
+    // Test-only directories have nothing to read before their package
+    if (!pkg.name) graph.dropNode(pkg.dir);
+  }
+  const label = (dir: string) => project.packages.get(dir)?.importPath ?? dir;
+
+  // Condense cycles so the order is over a DAG
+  const components = stronglyConnectedComponents(graph);
+  const componentOf = new Map<string, number>();
+  components.forEach((members, i) => members.forEach(dir => componentOf.set(dir, i)));
+  const dag = new DirectedGraph();
+  components.forEach((_members, i) => dag.addNode(String(i)));
+  graph.forEachEdge((_edge, _attrs, source, target) => {
+    const [from, to] = [componentOf.get(source)!, componentOf.get(target)!];
+    if (from !== to) dag.mergeEdge(String(from), String(to));
+  });
+
+  // Level: the longest import chain below a component, memoized depth-first
+  const levels = new Map<string, number>();
+  const levelOf = (node: string): number => {
+    const known = levels.get(node);
+    if (known !== undefined) return known;
+    const level = Math.max(-1, ...dag.outNeighbors(node).map(levelOf)) + 1;
+    levels.set(node, level);
+    return level;
+  };
+
+  const roleOf = (dir: string): ReadingRole =>
+    project.packages.get(dir)!.name === 'main' ? 'entry' : graph.outDegree(dir) === 0 ? 'foundation' : 'core';
+  const ROLES: ReadingRole[] = ['foundation', 'core', 'entry'];
+  const order = graph.nodes().sort((a, b) =>
+    // Foundations first and entry points (which nothing can import) last; leaves before what imports them, then the most widely used
+    ROLES.indexOf(roleOf(a)) - ROLES.indexOf(roleOf(b)) ||
+    levelOf(String(componentOf.get(a))) - levelOf(String(componentOf.get(b))) ||
+    componentOf.get(a)! - componentOf.get(b)! ||
+    graph.inDegree(b) - graph.inDegree(a) ||
+    label(a).localeCompare(label(b))
+  );
+
+  let undocumented = 0;
+  const steps = order.map((dir, i): ReadingStep => {
+    const pkg = project.packages.get(dir)!;
+    const level = levelOf(String(componentOf.get(dir)));
+    const buildsOn = graph.outNeighbors(dir).map(label).sort();
+    const cycleWith = components[componentOf.get(dir)!].filter(d => d !== dir).map(label).sort();
+    const role = roleOf(dir);
+    const synopsis = packageSynopsis(pkg);
+    if (!synopsis) undocumented++;
+    return {
+      step: i + 1,
+      dir,
+      importPath: pkg.importPath,
+      name: pkg.name,
+      role,
+      level,
+      synopsis,
+      buildsOn,
+      importedBy: graph.inDegree(dir),
+      cycleWith,
+      reason: readingReason(role, buildsOn, graph.inDegree(dir), cycleWith),
+    };
+  });
+  return { projectRoot: project.projectRoot, steps, undocumented };
+}
+
+function named(items: string[]): string {
+  const shown = items.slice(0, MAX_NAMED).join(', ');
+  return items.length > MAX_NAMED ? `${shown} and ${items.length - MAX_NAMED} more` : shown;
+}
+
+function readingReason(role: ReadingRole, buildsOn: string[], importedBy: number, cycleWith: string[]): string {
+  const users = `imported by ${importedBy} workspace package${importedBy === 1 ? '' : 's'}`;
+  const reasons: string[] = [];
+  if (role === 'entry') reasons.push(buildsOn.length > 0 ? `Entry point: wires together ${named(buildsOn)}` : 'Entry point that imports no workspace packages');
+  else if (role === 'foundation') reasons.push(`Imports no workspace packages; ${users}`);
+  else reasons.push(`Builds on ${named(buildsOn)}; ${users}`);
+  if (cycleWith.length > 0) reasons.push(`read together with ${named(cycleWith)}, which it shares an import cycle with`);
+  return reasons.join('; ');
+}
+
+/** The order as a Markdown section for ONBOARDING.md or a wiki */
+export function formatReadingOrderMarkdown(order: ReadingOrder): string {
+  const lines: string[] = ['## Reading order', ''];
+  lines.push('Read the packages in this order: each one only builds on packages above it, from the foundations to the entry points.', '');
+  const titles: Record<ReadingRole, string> = { foundation: 'Foundations', core: 'Core', entry: 'Entry points' };
+  let role: ReadingRole | null = null;
+  for (const step of order.steps) {
+    if (step.role !== role) {
+      if (role !== null) lines.push('');
+      role = step.role;
+      lines.push(`### ${titles[role]}`, '');
+    }
+    const summary = step.synopsis ? ` — ${step.synopsis}` : '';
+    lines.push(`${step.step}. \`${step.importPath ?? step.dir}\`${summary}`, `   - ${step.reason}.`);
+  }
+  lines.push('');
+  if (order.undocumented > 0) {
+    lines.push(`${order.undocumented} package${order.undocumented === 1 ? ' has' : 's have'} no package comment; a \`// Package x ...\` comment in doc.go gives the summary shown here.`, '');
+  }
+  return lines.join('\n');
+}
@@ -0,0 +1,300 @@
+import { accessSync, constants, existsSync, readdirSync, readFileSync } from 'fs';
+import { join } from 'path';
+import { parseProject, type SkipReason } from '../parser/index.js';
+import { findGoModules, type GoModule } from '../golang/modules.js';
+import { runGo, goModCacheDir } from '../golang/toolchain.js';
+import { goListDeps } from '../golang/deps.js';
+import { compareGoVersions } from '../golang/goversion.js';
+import { parseGoSource, walk } from '../golang/source.js';
+import { escapeModulePath } from '../supply-chain/confusion.js';
+import { defaultCacheDir } from '../remote/index.js';
+import { checkCancelled, type ProgressCallback } from '../utils/progress.js';
+
+/**
+ * What makes an analysis slow or quietly incomplete: the Go toolchain and
+ * module cache that go list, go vet and the module graph need, packages go
+ * cannot load, files the parser skipped or only partly understood, and
+ * depwire's own caches. Every other command carries on past these — doctor
+ * is where they are listed.
+ */
+
+export type CheckStatus = 'ok' | 'warn' | 'fail' | 'skip';
+
+export interface DoctorCheck {
+  id: 'go-toolchain' | 'module-cache' | 'go-packages' | 'parse' | 'depwire-cache';
+  title: string;
+  status: CheckStatus;
+  summary: string;
+  /** The individual problems: files, packages, modules */
+  details: string[];
+  /** What to do about them */
+  hint?: string;
+}
+
+export interface DoctorReport {
+  projectRoot: string;
+  checks: DoctorCheck[];
+  /** The worst status of any check */
+  status: CheckStatus;
+}
+
+export interface DoctorOptions {
+  signal?: AbortSignal;
+  /** The file filter the other commands would parse with (see createFilter) */
+  filter?: (file: string, source: string) => boolean;
+  onProgress?: ProgressCallback;
+}
+
+const SEVERITY: CheckStatus[] = ['skip', 'ok', 'warn', 'fail'];
+
+const worst = (statuses: CheckStatus[]): CheckStatus =>
+  statuses.reduce((a, b) => (SEVERITY.indexOf(b) > SEVERITY.indexOf(a) ? b : a), 'ok');
+
+const firstLine = (text: string) => text.trim().split('\n')[0];
+
+async function checkToolchain(projectRoot: string, modules: GoModule[], signal?: AbortSignal): Promise<{ check: DoctorCheck; available: boolean }> {
+  const check = (status: CheckStatus, summary: string, details: string[] = [], hint?: string): DoctorCheck =>
+    ({ id: 'go-toolchain', title: 'Go toolchain', status, summary, details, hint });
+  if (modules.length === 0) return { check: check('skip', 'No go.mod in the project'), available: false };
+
+  let installed: string | null;
+  try {
+    const result = await runGo(['env', 'GOVERSION'], { cwd: projectRoot, signal });
+    if (result.exitCode !== 0) {
+      return { check: check('fail', `go env failed: ${firstLine(result.stderr)}`, [], 'Check GOROOT and the go installation'), available: false };
+    }
+    installed = result.stdout.trim() || null;
+  } catch (err) {
+    if (signal?.aborted) throw err;
+    return {
+      check: check('warn', 'go is not on PATH', [
+        'Third-party packages come from vendor/ or are not analyzed',
+        'go vet, the module graph and type-checked analyses are skipped',
+      ], 'Install Go and make sure `go` is on PATH'),
+      available: false,
+    };
+  }
+
+  const needed = modules
+    .flatMap(m => [m.mod.go, m.mod.toolchain].filter((v): v is string => !!v).map(v => ({ version: v, file: m.goModFile })))
+    .sort((a, b) => compareGoVersions(b.version, a.version))[0];
+  if (installed && needed && compareGoVersions(installed, needed.version) < 0) {
+    const local = process.env.GOTOOLCHAIN === 'local';
+    return {
+      check: check(local ? 'fail' : 'warn', `${installed} is older than go ${needed.version} in ${needed.file}`, [
+        local ? 'GOTOOLCHAIN=local: go refuses to load the module' : 'go downloads a newer toolchain on first use, which is slow and fails offline',
+      ], `Install Go ${needed.version} or newer`),
+      available: true,
+    };
+  }
+  return { check: check('ok', installed ?? 'go is installed'), available: true };
+}
+
+// Where the go command extracts a module version, and whether it finished doing so
+function cacheState(cache: string, path: string, version: string): 'cached' | 'missing' | 'partial' {
+  const download = join(cache, 'cache', 'download', escapeModulePath(path), '@v', escapeModulePath(version));
+  if (existsSync(`${download}.partial`)) return 'partial';
+  if (!existsSync(join(cache, `${escapeModulePath(path)}@${escapeModulePath(version)}`))) return 'missing';
+  // The .ziphash is written last; an extracted directory without it is an interrupted download
+  return existsSync(`${download}.ziphash`) ? 'cached' : 'partial';
+}
+
+async function checkModuleCache(projectRoot: string, modules: GoModule[], goAvailable: boolean, signal?: AbortSignal): Promise<DoctorCheck> {
+  const check = (status: CheckStatus, summary: string, details: string[] = [], hint?: string): DoctorCheck =>
+    ({ id: 'module-cache', title: 'Module cache', status, summary, details, hint });
+  if (modules.length === 0) return check('skip', 'No go.mod in the project');
+
+  const cache = await goModCacheDir(projectRoot, signal);
+  const missing: string[] = [];
+  const partial: string[] = [];
+  const downloaded: GoModule[] = [];
+  let required = 0;
+  for (const module of modules) {
+    // Vendored modules build from vendor/, not the cache
+    if (existsSync(join(projectRoot, module.dir, 'vendor', 'modules.txt'))) continue;
+    downloaded.push(module);
+    for (const req of module.mod.require) {
+      const replace = module.mod.replace.find(r => r.oldPath === req.path && (!r.oldVersion || r.oldVersion === req.version));
+      if (replace?.local) continue;
+      const path = replace?.newPath ?? req.path;
+      const version = replace?.newVersion ?? req.version;
+      required++;
+      const state = cacheState(cache, path, version);
+      if (state === 'missing') missing.push(`${path}@${version}`);
+      if (state === 'partial') partial.push(`${path}@${version} (interrupted download)`);
+    }
+  }
+  if (downloaded.length === 0) return check('ok', 'Every module is vendored');
+
+  const writable = (() => {
+    try {
+      accessSync(existsSync(cache) ? cache : join(cache, '..'), constants.W_OK);
+      return true;
+    } catch {
+      return false;
+    }
+  })();
+
+  // go mod verify only reads what is cached; with misses it would start downloading
+  const modified: string[] = [];
+  if (goAvailable && missing.length === 0 && partial.length === 0) {
+    for (const module of downloaded) {
+      checkCancelled(signal);
+      const result = await runGo(['mod', 'verify'], { cwd: join(projectRoot, module.dir), signal });
+      if (result.exitCode === 0) continue;
+      modified.push(...`${result.stdout}\n${result.stderr}`.split('\n').map(l => l.trim()).filter(l => l && l !== 'all modules verified'));
+    }
+  }
+
+  if (partial.length + modified.length > 0) {
+    return check('fail', `${partial.length + modified.length} modules in ${cache} are broken`, [...partial, ...modified],
+      'Run go clean -modcache (or delete the listed versions) and go mod download');
+  }
+  if (missing.length > 0) {
+    return check('warn', `${missing.length} of ${required} required modules are not in ${cache}`, missing,
+      `Run go mod download${modules.length > 1 ? ' in each module' : ''}; until then go list downloads them, or fails offline`);
+  }
+  if (!writable) return check('warn', `${cache} is not writable`, [], 'The go command cannot download new versions; check GOMODCACHE and its permissions');
+  return check('ok', `${required} required modules cached in ${cache}${goAvailable ? ', verified' : ''}`);
+}
+
+async function checkGoPackages(projectRoot: string, modules: GoModule[], goAvailable: boolean, signal?: AbortSignal): Promise<DoctorCheck> {
+  const check = (status: CheckStatus, summary: string, details: string[] = [], hint?: string): DoctorCheck =>
+    ({ id: 'go-packages', title: 'Go packages', status, summary, details, hint });
+  if (modules.length === 0) return check('skip', 'No go.mod in the project');
+  if (!goAvailable) return check('skip', 'Needs the go toolchain');
+
+  const failed = new Map<string, string>();
+  let loaded = 0;
+  for (const module of modules) {
+    try {
+      for (const pkg of await goListDeps(join(projectRoot, module.dir), ['./...'], signal)) {
+        if (pkg.Error) failed.set(pkg.ImportPath, firstLine(pkg.Error.Err));
+        else loaded++;
+      }
+    } catch (err) {
+      if (signal?.aborted) throw err;
+      failed.set(module.path, err instanceof Error ? err.message : String(err));
+    }
+  }
+  if (failed.size > 0) {
+    return check('fail', `${failed.size} packages failed to load and are left out of dependency analyses`,
+      [...failed].sort(([a], [b]) => a.localeCompare(b)).map(([pkg, err]) => `${pkg}: ${err}`),
+      'Fix the errors go list reports (go list -e -deps ./...)');
+  }
+  return check('ok', `${loaded} packages load, dependencies included`);
+}
+
+const PROBLEMS: Partial<Record<SkipReason, string>> = {
+  'too-large': 'over 1MB',
+  unreadable: 'unreadable',
+  'no-parser': 'no parser for its contents',
+  'outside-project': 'outside the project',
+};
+
+// The first syntax error tree-sitter recovered from, as a line number
+function syntaxErrorLine(file: string, source: string): number | null {
+  const root = parseGoSource(file, source).root;
+  if (!root.hasError) return null;
+  // Follow the subtrees containing errors down to the first ERROR or MISSING node
+  let line = root.startPosition.row + 1;
+  let found = false;
+  walk(root, (node) => {
+    if (found || !node.hasError && !node.isMissing) return false;
+    line = node.startPosition.row + 1;
+    if (node.type === 'ERROR' || node.isMissing) found = true;
+  });
+  return line;
+}
+
+async function checkParse(projectRoot: string, options: DoctorOptions): Promise<DoctorCheck> {
+  const errors: string[] = [];
+  const skipped: string[] = [];
+  let filtered = 0;
+  const parsed = await parseProject(projectRoot, {
+    signal: options.signal,
+    filter: options.filter,
+    onProgress: options.onProgress,
+    onSkip: (file, reason, error) => {
+      if (reason === 'excluded' || reason === 'filtered') filtered++;
+      else if (reason === 'error') errors.push(`${file}: ${error instanceof Error ? error.message : error}`);
+      else skipped.push(`${file}: ${PROBLEMS[reason]}`);
+    },
+  });
+
+  // tree-sitter recovers from syntax errors, so a broken Go file parses with pieces missing
+  const partial: string[] = [];
+  for (const file of parsed.filter(f => f.filePath.endsWith('.go'))) {
+    try {
+      const line = syntaxErrorLine(file.filePath, readFileSync(join(projectRoot, file.filePath), 'utf-8'));
+      if (line !== null) partial.push(`${file.filePath}:${line}: syntax error, partly analyzed`);
+    } catch { /* reported by the parse itself */ }
+  }
+
+  const counts = `${parsed.length} files parsed${filtered > 0 ? `, ${filtered} filtered out` : ''}`;
+  const details = [...errors, ...skipped, ...partial];
+  if (details.length === 0) return { id: 'parse', title: 'Parsing', status: 'ok', summary: counts, details };
+  return {
+    id: 'parse',
+    title: 'Parsing',
+    status: errors.length > 0 ? 'fail' : 'warn',
+    summary: `${counts}; ${[
+      errors.length > 0 ? `${errors.length} failed` : '',
+      skipped.length > 0 ? `${skipped.length} skipped` : '',
+      partial.length > 0 ? `${partial.length} with syntax errors` : '',
+    ].filter(Boolean).join(', ')} — their symbols and edges are missing from the graph`,
+    details,
+    hint: skipped.length > 0 && errors.length + partial.length === 0
+      ? 'Exclude them with --exclude if they are not meant to be analyzed'
+      : 'Fix the syntax errors, or exclude the files with --exclude',
+  };
+}
+
+function checkDepwireCache(projectRoot: string): DoctorCheck {
+  const details: string[] = [];
+  const snapshots = join(projectRoot, '.depwire', 'temporal');
+  let cached = 0;
+  if (existsSync(snapshots)) {
+    for (const name of readdirSync(snapshots).filter(n => n.endsWith('.json'))) {
+      try {
+        JSON.parse(readFileSync(join(snapshots, name), 'utf-8'));
+        cached++;
+      } catch {
+        details.push(`.depwire/temporal/${name}: unreadable snapshot, recomputed on every run`);
+      }
+    }
+  }
+  const completion = join(projectRoot, '.depwire', 'completion.json');
+  if (existsSync(completion)) {
+    try {
+      JSON.parse(readFileSync(completion, 'utf-8'));
+    } catch {
+      details.push('.depwire/completion.json: unreadable, completion rescans the workspace every time');
+    }
+  }
+  const downloads = defaultCacheDir();
+  if (existsSync(downloads)) {
+    try {
+      accessSync(downloads, constants.W_OK);
+    } catch {
+      details.push(`${downloads}: not writable, analyze and compare download modules again every run`);
+    }
+  }
+  if (details.length > 0) {
+    return { id: 'depwire-cache', title: 'Depwire caches', status: 'warn', summary: `${details.length} cache problems`, details, hint: 'Delete the listed files; they are rebuilt on the next run' };
+  }
+  return { id: 'depwire-cache', title: 'Depwire caches', status: 'ok', summary: cached > 0 ? `${cached} history snapshots cached` : 'Nothing cached yet', details };
+}
+
+export async function runDoctor(projectRoot: string, options: DoctorOptions = {}): Promise<DoctorReport> {
+  const modules = findGoModules(projectRoot);
+  const { check: toolchain, available: goAvailable } = await checkToolchain(projectRoot, modules, options.signal);
+  const checks = [
+    toolchain,
+    await checkModuleCache(projectRoot, modules, goAvailable, options.signal),
+    await checkGoPackages(projectRoot, modules, goAvailable, options.signal),
+    await checkParse(projectRoot, options),
+    checkDepwireCache(projectRoot),
+  ];
+  return { projectRoot, checks, status: worst(checks.map(c => c.status)) };
+}
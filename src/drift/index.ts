@@ -0,0 +1,184 @@
+import { gzipSync, gunzipSync } from 'zlib';
+import { execFileSync } from 'child_process';
+import { DirectedGraph } from 'graphology';
+import { toPackageGraph } from '../graph/model.js';
+import { findCycles, cycleChanges } from '../graph/algorithms.js';
+import { graphMetrics, type HistoryMetrics } from '../temporal/history.js';
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+import { longestChain } from '../rules/budgets.js';
+
+export { registerBaselineStore, baselineStore, type BaselineStore } from './storage.js';
+
+/**
+ * Drift against a stored baseline: a compact record of the package graph,
+ * its Go requirements and its metrics at one point in time, and the report
+ * of what changed since — for scheduled architecture reviews.
+ */
+
+export interface DriftMetrics extends HistoryMetrics {
+  /** Longest package import chain, cycles counted as one step */
+  depth: number;
+}
+
+export interface Baseline {
+  version: 1;
+  capturedAt: string;
+  commit: string | null;
+  /** Package directory → label (Go import path where known) */
+  packages: Record<string, string>;
+  edges: Array<[string, string]>;
+  modules: Array<{ path: string; version: string; indirect: boolean }>;
+  metrics: DriftMetrics;
+}
+
+export interface MetricDrift {
+  metric: keyof DriftMetrics;
+  before: number;
+  after: number;
+  /** The metric moved in the direction that makes the architecture worse */
+  regression: boolean;
+}
+
+export interface DriftReport {
+  baseline: { capturedAt: string; commit: string | null };
+  modules: {
+    added: Array<{ path: string; version: string; indirect: boolean }>;
+    removed: Array<{ path: string; version: string }>;
+    changed: Array<{ path: string; before: string; after: string }>;
+  };
+  packages: { added: string[]; removed: string[] };
+  edges: { added: Array<[string, string]>; removed: Array<[string, string]> };
+  /** Package cycles that are new or larger than at the baseline */
+  cycles: Array<{ kind: 'new' | 'grown'; cycle: string[] }>;
+  metrics: MetricDrift[];
+  regressions: number;
+}
+
+// Growth in these is worse; the rest are sizes, reported without judgement
+const WORSE_WHEN_HIGHER: Array<keyof DriftMetrics> = ['packageEdges', 'cycles', 'largestCycle', 'directModules', 'indirectModules', 'depth'];
+
+function gitHead(dir: string): string | null {
+  try {
+    return execFileSync('git', ['rev-parse', 'HEAD'], { cwd: dir, encoding: 'utf-8', stdio: ['ignore', 'pipe', 'ignore'] }).trim();
+  } catch {
+    return null;
+  }
+}
+
+export function captureBaseline(graph: DirectedGraph, projectRoot: string): Baseline {
+  const packageGraph = toPackageGraph(graph);
+  const modules = findGoModules(projectRoot);
+  const index = new GoModuleIndex(modules);
+  const local = new Set(modules.map(m => m.path));
+  const requires = new Map<string, { path: string; version: string; indirect: boolean }>();
+  for (const mod of modules) {
+    for (const req of mod.mod.require) {
+      if (!local.has(req.path) && !requires.has(req.path)) requires.set(req.path, { path: req.path, version: req.version, indirect: req.indirect });
+    }
+  }
+  return {
+    version: 1,
+    capturedAt: new Date().toISOString(),
+    commit: gitHead(projectRoot),
+    packages: Object.fromEntries(packageGraph.mapNodes(pkg => [pkg, index.importForDir(pkg) ?? pkg])),
+    edges: packageGraph.mapEdges((_edge, _attrs, source, target) => [source, target] as [string, string]),
+    modules: [...requires.values()].sort((a, b) => a.path.localeCompare(b.path)),
+    metrics: { ...graphMetrics(graph, projectRoot), depth: longestChain(packageGraph).depth },
+  };
+}
+
+/** Baselines are gzipped JSON */
+export function encodeBaseline(baseline: Baseline): Buffer {
+  return gzipSync(JSON.stringify(baseline));
+}
+
+export function decodeBaseline(data: Buffer): Baseline {
+  let baseline: Baseline;
+  try {
+    baseline = JSON.parse(gunzipSync(data).toString('utf-8'));
+  } catch {
+    throw new Error('Not a depwire baseline (expected gzipped JSON written by depwire drift --capture)');
+  }
+  if (baseline.version !== 1) throw new Error(`Unsupported baseline version ${baseline.version}`);
+  return baseline;
+}
+
+function packageCycles(baseline: Pick<Baseline, 'packages' | 'edges'>): string[][] {
+  const graph = new DirectedGraph();
+  for (const pkg of Object.keys(baseline.packages)) graph.addNode(pkg);
+  for (const [source, target] of baseline.edges) graph.mergeEdge(source, target);
+  return findCycles(graph).filter(c => c.length > 1);
+}
+
+export function compareBaseline(before: Baseline, after: Baseline): DriftReport {
+  const beforeModules = new Map(before.modules.map(m => [m.path, m]));
+  const afterModules = new Map(after.modules.map(m => [m.path, m]));
+  const edgeKey = ([source, target]: [string, string]) => `${source}\0${target}`;
+  const beforeEdges = new Set(before.edges.map(edgeKey));
+  const afterEdges = new Set(after.edges.map(edgeKey));
+  const label = (pkg: string) => after.packages[pkg] ?? before.packages[pkg] ?? pkg;
+
+  const metrics = (Object.keys(after.metrics) as Array<keyof DriftMetrics>)
+    .filter(metric => before.metrics[metric] !== undefined)
+    .map(metric => ({
+      metric,
+      before: before.metrics[metric],
+      after: after.metrics[metric],
+      regression: WORSE_WHEN_HIGHER.includes(metric) && after.metrics[metric] > before.metrics[metric],
+    }));
+  const cycles = cycleChanges(packageCycles(before), packageCycles(after))
+    .map(change => ({ kind: change.kind, cycle: change.cycle.map(label) }));
+
+  const report: DriftReport = {
+    baseline: { capturedAt: before.capturedAt, commit: before.commit },
+    modules: {
+      added: after.modules.filter(m => !beforeModules.has(m.path)),
+      removed: before.modules.filter(m => !afterModules.has(m.path)).map(({ path, version }) => ({ path, version })),
+      changed: after.modules
+        .filter(m => beforeModules.has(m.path) && beforeModules.get(m.path)!.version !== m.version)
+        .map(m => ({ path: m.path, before: beforeModules.get(m.path)!.version, after: m.version })),
+    },
+    packages: {
+      added: Object.keys(after.packages).filter(p => !(p in before.packages)).map(label).sort(),
+      removed: Object.keys(before.packages).filter(p => !(p in after.packages)).map(label).sort(),
+    },
+    edges: {
+      added: after.edges.filter(e => !beforeEdges.has(edgeKey(e))).map(([s, t]) => [label(s), label(t)] as [string, string]),
+      removed: before.edges.filter(e => !afterEdges.has(edgeKey(e))).map(([s, t]) => [label(s), label(t)] as [string, string]),
+    },
+    cycles,
+    metrics,
+    regressions: 0,
+  };
+  report.regressions = metrics.filter(m => m.regression).length + report.modules.added.filter(m => !m.indirect).length + cycles.length;
+  return report;
+}
+
+/** Markdown for a review issue or a chat post */
+export function formatDriftMarkdown(report: DriftReport): string {
+  const lines: string[] = [];
+  const since = `${report.baseline.capturedAt.slice(0, 10)}${report.baseline.commit ? ` (${report.baseline.commit.slice(0, 8)})` : ''}`;
+  lines.push(`# Dependency drift since ${since}`, '');
+  lines.push(report.regressions > 0 ? `**${report.regressions} regressions.**` : 'No regressions.', '');
+
+  lines.push('## Metrics', '', '| Metric | Baseline | Now | Change |', '| --- | ---: | ---: | ---: |');
+  for (const m of report.metrics) {
+    const delta = m.after - m.before;
+    lines.push(`| ${m.metric}${m.regression ? ' ⚠' : ''} | ${m.before} | ${m.after} | ${delta > 0 ? `+${delta}` : delta} |`);
+  }
+  lines.push('');
+
+  const section = (title: string, items: string[]) => {
+    if (items.length === 0) return;
+    lines.push(`## ${title}`, '', ...items.map(item => `- ${item}`), '');
+  };
+  section('New external modules', report.modules.added.map(m => `\`${m.path}\` ${m.version}${m.indirect ? ' (indirect)' : ''}`));
+  section('Removed external modules', report.modules.removed.map(m => `\`${m.path}\``));
+  section('Module version changes', report.modules.changed.map(m => `\`${m.path}\` ${m.before} → ${m.after}`));
+  section('New or grown cycles', report.cycles.map(c => `${c.kind === 'new' ? 'New' : 'Grown'}: ${c.cycle.map(p => `\`${p}\``).join(' → ')}`));
+  section('New packages', report.packages.added.map(p => `\`${p}\``));
+  section('Removed packages', report.packages.removed.map(p => `\`${p}\``));
+  section('New package dependencies', report.edges.added.map(([s, t]) => `\`${s}\` → \`${t}\``));
+  section('Removed package dependencies', report.edges.removed.map(([s, t]) => `\`${s}\` → \`${t}\``));
+  return lines.join('\n');
+}
@@ -0,0 +1,94 @@
+import { spawn } from 'child_process';
+import { readFileSync, writeFileSync, renameSync, mkdirSync } from 'fs';
+import { dirname, resolve } from 'path';
+import { fileURLToPath } from 'url';
+
+/**
+ * Where drift baselines are kept, chosen by URL scheme. Local paths and
+ * file:// URLs, http(s) (GET to read, PUT to write), s3:// through the AWS
+ * CLI and gs:// through gsutil are built in; registerBaselineStore() adds
+ * others.
+ */
+
+export interface BaselineStore {
+  read(location: string): Promise<Buffer>;
+  write(location: string, data: Buffer): Promise<void>;
+}
+
+function run(command: string, args: string[], input?: Buffer): Promise<Buffer> {
+  return new Promise((resolvePromise, reject) => {
+    const child = spawn(command, args, { stdio: ['pipe', 'pipe', 'pipe'] });
+    const stdout: Buffer[] = [];
+    const stderr: Buffer[] = [];
+    child.stdout.on('data', (chunk: Buffer) => stdout.push(chunk));
+    child.stderr.on('data', (chunk: Buffer) => stderr.push(chunk));
+    child.on('error', (err: NodeJS.ErrnoException) => {
+      reject(err.code === 'ENOENT' ? new Error(`${command} not found — it is needed for this baseline location`) : err);
+    });
+    child.on('close', (code) => {
+      if (code === 0) resolvePromise(Buffer.concat(stdout));
+      else reject(new Error(`${command} ${args.join(' ')} failed: ${Buffer.concat(stderr).toString('utf-8').trim() || `exit code ${code}`}`));
+    });
+    child.stdin.end(input);
+  });
+}
+
+// Object stores whose CLI copies between a URL and stdin/stdout ("-")
+function cliStore(command: string, copy: string[]): BaselineStore {
+  return {
+    read: (location) => run(command, [...copy, location, '-']),
+    write: async (location, data) => { await run(command, [...copy, '-', location], data); },
+  };
+}
+
+const fileStore: BaselineStore = {
+  read: async (location) => readFileSync(localPath(location)),
+  write: async (location, data) => {
+    const path = localPath(location);
+    mkdirSync(dirname(path), { recursive: true });
+    const tmpPath = `${path}.${process.pid}.tmp`;
+    writeFileSync(tmpPath, data);
+    renameSync(tmpPath, path);
+  },
+};
+
+const httpStore: BaselineStore = {
+  read: async (location) => {
+    const response = await fetch(location);
+    if (!response.ok) throw new Error(`GET ${location}: ${response.status} ${response.statusText}`);
+    return Buffer.from(await response.arrayBuffer());
+  },
+  write: async (location, data) => {
+    const response = await fetch(location, { method: 'PUT', body: data, headers: { 'content-type': 'application/octet-stream' } });
+    if (!response.ok) throw new Error(`PUT ${location}: ${response.status} ${response.statusText}`);
+  },
+};
+
+const stores = new Map<string, BaselineStore>([
+  ['file', fileStore],
+  ['http', httpStore],
+  ['https', httpStore],
+  ['s3', cliStore('aws', ['s3', 'cp'])],
+  ['gs', cliStore('gsutil', ['cp'])],
+]);
+
+function localPath(location: string): string {
+  return location.startsWith('file:') ? fileURLToPath(location) : resolve(location);
+}
+
+// "s3://bucket/key" → s3; plain paths (including Windows drive letters) → file
+function schemeOf(location: string): string {
+  const match = /^([a-z][a-z0-9+.-]+):\/\//i.exec(location);
+  return match ? match[1].toLowerCase() : 'file';
+}
+
+export function registerBaselineStore(scheme: string, store: BaselineStore): void {
+  stores.set(scheme.toLowerCase(), store);
+}
+
+export function baselineStore(location: string): BaselineStore {
+  const scheme = schemeOf(location);
+  const store = stores.get(scheme);
+  if (!store) throw new Error(`No baseline store for ${scheme}:// (built in: ${[...stores.keys()].join(', ')})`);
+  return store;
+}
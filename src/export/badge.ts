@@ -0,0 +1,77 @@
+import { escapeXml } from './svg.js';
+
+/**
+ * Shields-style "flat" badges: a grey label on the left, a colored message
+ * on the right. Text is measured with approximate Verdana 11px widths, so
+ * no font has to be installed where badges are rendered.
+ */
+
+export const BADGE_COLORS = {
+  brightgreen: '#4c1',
+  green: '#97ca00',
+  yellowgreen: '#a4a61d',
+  yellow: '#dfb317',
+  orange: '#fe7d37',
+  red: '#e05d44',
+  blue: '#007ec6',
+  grey: '#555',
+} as const;
+
+export type BadgeColor = keyof typeof BADGE_COLORS;
+
+export interface Badge {
+  label: string;
+  message: string;
+  color: BadgeColor | string;
+}
+
+const NARROW = new Set([...'iljI.,:;|!\'` ']);
+const SEMI = new Set([...'frt()[]{}-/']);
+const WIDE = new Set([...'mwMW%@']);
+
+function textWidth(text: string): number {
+  let width = 0;
+  for (const c of text) {
+    if (NARROW.has(c)) width += 3.5;
+    else if (SEMI.has(c)) width += 4.8;
+    else if (WIDE.has(c)) width += 10.5;
+    else if (c >= 'A' && c <= 'Z') width += 7.5;
+    else width += 6.8;
+  }
+  return Math.ceil(width);
+}
+
+/** Color for a 0-100 score, on the usual shields scale */
+export function scoreColor(score: number): BadgeColor {
+  if (score >= 90) return 'brightgreen';
+  if (score >= 80) return 'green';
+  if (score >= 70) return 'yellowgreen';
+  if (score >= 60) return 'yellow';
+  if (score >= 50) return 'orange';
+  return 'red';
+}
+
+export function renderBadge(badge: Badge): string {
+  const color = BADGE_COLORS[badge.color as BadgeColor] ?? badge.color;
+  const labelWidth = textWidth(badge.label) + 10;
+  const messageWidth = textWidth(badge.message) + 10;
+  const width = labelWidth + messageWidth;
+  const label = escapeXml(badge.label);
+  const message = escapeXml(badge.message);
+  const text = (x: number, value: string) =>
+    `<text x="${x}" y="15" fill="#010101" fill-opacity=".3">${value}</text><text x="${x}" y="14">${value}</text>`;
+
+  return [
+    `<svg xmlns="http://www.w3.org/2000/svg" width="${width}" height="20" role="img" aria-label="${label}: ${message}">`,
+    `<title>${label}: ${message}</title>`,
+    '<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>',
+    `<clipPath id="r"><rect width="${width}" height="20" rx="3" fill="#fff"/></clipPath>`,
+    `<g clip-path="url(#r)"><rect width="${labelWidth}" height="20" fill="#555"/><rect x="${labelWidth}" width="${messageWidth}" height="20" fill="${escapeXml(color)}"/><rect width="${width}" height="20" fill="url(#s)"/></g>`,
+    '<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">',
+    text(labelWidth / 2, label),
+    text(labelWidth + messageWidth / 2, message),
+    '</g>',
+    '</svg>',
+    '',
+  ].join('\n');
+}
@@ -0,0 +1,78 @@
+import { DirectedGraph } from 'graphology';
+import { packageOf } from '../graph/model.js';
+import type { GraphDelta } from '../graph/diff.js';
+import type { ExportGraph, ExportLevel } from './graph.js';
+
+/**
+ * One graph showing a change: the union of the before and after export
+ * graphs, with `change` set on what was added, removed or (for nodes whose
+ * symbols changed) modified. The exporters draw added elements green,
+ * removed ones dashed red and changed nodes highlighted.
+ */
+
+export type ChangeKind = 'added' | 'removed' | 'changed';
+
+export interface DiffSummary {
+  addedNodes: number;
+  removedNodes: number;
+  changedNodes: number;
+  addedEdges: number;
+  removedEdges: number;
+}
+
+export function overlayDiff(before: ExportGraph, after: ExportGraph, delta: GraphDelta, level: ExportLevel = 'package'): ExportGraph {
+  const overlay: ExportGraph = new DirectedGraph();
+  const keyOf = (filePath: string) => (level === 'file' ? filePath : packageOf(filePath));
+
+  const symbolsAdded = new Map<string, number>();
+  const symbolsRemoved = new Map<string, number>();
+  for (const node of delta.addedNodes) symbolsAdded.set(keyOf(node.filePath), (symbolsAdded.get(keyOf(node.filePath)) ?? 0) + 1);
+  for (const node of delta.removedNodes) symbolsRemoved.set(keyOf(node.filePath), (symbolsRemoved.get(keyOf(node.filePath)) ?? 0) + 1);
+
+  after.forEachNode((node, attrs) => {
+    if (!before.hasNode(node)) {
+      overlay.addNode(node, { ...attrs, change: 'added' });
+      return;
+    }
+    const added = symbolsAdded.get(node) ?? 0;
+    const removed = symbolsRemoved.get(node) ?? 0;
+    overlay.addNode(node, added || removed
+      ? { ...attrs, change: 'changed', symbolsAdded: added, symbolsRemoved: removed }
+      : { ...attrs });
+  });
+  before.forEachNode((node, attrs) => {
+    if (!overlay.hasNode(node)) overlay.addNode(node, { ...attrs, change: 'removed' });
+  });
+
+  after.forEachEdge((_edge, attrs, source, target) => {
+    overlay.addEdge(source, target, before.hasEdge(source, target) ? { ...attrs } : { ...attrs, change: 'added' });
+  });
+  before.forEachEdge((_edge, attrs, source, target) => {
+    if (!overlay.hasEdge(source, target)) overlay.addEdge(source, target, { ...attrs, change: 'removed' });
+  });
+  return overlay;
+}
+
+/** Counts of what overlayDiff() marked, or null for a graph without a diff */
+export function diffSummary(graph: ExportGraph): DiffSummary | null {
+  const summary: DiffSummary = { addedNodes: 0, removedNodes: 0, changedNodes: 0, addedEdges: 0, removedEdges: 0 };
+  let marked = false;
+  graph.forEachNode((_node, attrs) => {
+    if (attrs.change === 'added') summary.addedNodes++;
+    else if (attrs.change === 'removed') summary.removedNodes++;
+    else if (attrs.change === 'changed') summary.changedNodes++;
+    marked ||= attrs.change !== undefined;
+  });
+  graph.forEachEdge((_edge, attrs) => {
+    if (attrs.change === 'added') summary.addedEdges++;
+    else if (attrs.change === 'removed') summary.removedEdges++;
+    marked ||= attrs.change !== undefined;
+  });
+  return marked ? summary : null;
+}
+
+export const CHANGE_COLORS: Record<ChangeKind, { stroke: string; fill: string }> = {
+  added: { stroke: '#22a55b', fill: '#e3f6ea' },
+  removed: { stroke: '#d64545', fill: '#fbe5e5' },
+  changed: { stroke: '#f59e0b', fill: '#fdf1dc' },
+};
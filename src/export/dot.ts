@@ -0,0 +1,110 @@
+import type { ExportGraph, Cluster } from './graph.js';
+import { legendStops, formatMetricValue, type Heatmap } from './metrics.js';
+import { CHANGE_COLORS } from './diff.js';
+
+/**
+ * Graphviz DOT for an export graph. Node labels are the import path or file
+ * path; edge width grows with the number of references behind the edge.
+ * A focused node (distance 0) is drawn filled, its neighbors fade with hops.
+ * With a heatmap, nodes are filled by metric instead and a legend is added;
+ * edges the metric is set on (coverage, cpu) are drawn in its colors too;
+ * with clusters, each community is drawn as a labelled Graphviz cluster.
+ * A diff overlay outlines added nodes and edges green, removed ones dashed red.
+ */
+
+export interface DotOptions {
+  name?: string;
+  /** Graphviz rankdir; LR reads best for long import paths */
+  rankdir?: 'LR' | 'TB' | 'RL' | 'BT';
+  heatmap?: Heatmap;
+  clusters?: Cluster[];
+}
+
+export function quoteDot(value: string): string {
+  return `"${value.replace(/\\/g, '\\\\').replace(/"/g, '\\"').replace(/\n/g, '\\n')}"`;
+}
+
+const DISTANCE_FILL = ['#4a9eff', '#a7cdfa', '#dceafc', '#f2f6fb'];
+
+export function toDot(graph: ExportGraph, options: DotOptions = {}): string {
+  const lines: string[] = [];
+  const heat = options.heatmap;
+  lines.push(`digraph ${quoteDot(options.name ?? 'depwire')} {`);
+  lines.push(`  rankdir=${options.rankdir ?? 'LR'};`);
+  lines.push('  node [shape=box, style="rounded,filled", fillcolor="#ffffff", fontname="Helvetica", fontsize=10];');
+  lines.push('  edge [color="#7a7a8c", arrowsize=0.6];');
+
+  const statements = new Map<string, string>();
+  for (const node of graph.nodes().sort()) {
+    const attrs = graph.getNodeAttributes(node);
+    let tooltip = `${attrs.files} files, ${attrs.symbols} symbols`;
+    const parts = [`label=${quoteDot(attrs.label)}`];
+    if (heat) {
+      const value = attrs[heat.attribute];
+      if (typeof value === 'number') {
+        parts.push(`fillcolor=${quoteDot(heat.color(value))}`);
+        tooltip += `, ${heat.attribute} ${formatMetricValue(value)}`;
+      }
+      if (attrs.distance === 0) parts.push('penwidth=3');
+    } else if (attrs.distance !== undefined) {
+      parts.push(`fillcolor=${quoteDot(DISTANCE_FILL[Math.min(attrs.distance, DISTANCE_FILL.length - 1)])}`);
+      if (attrs.distance === 0) parts.push('penwidth=2', 'fontcolor="#ffffff"');
+    }
+    if (attrs.change) {
+      const colors = CHANGE_COLORS[attrs.change];
+      parts.push(`color=${quoteDot(colors.stroke)}`);
+      if (attrs.distance !== 0) parts.push('penwidth=2');
+      if (!heat && attrs.distance === undefined) parts.push(`fillcolor=${quoteDot(colors.fill)}`);
+      if (attrs.change === 'removed') parts.push('style="rounded,filled,dashed"');
+      tooltip += attrs.change === 'changed' ? `, +${attrs.symbolsAdded} −${attrs.symbolsRemoved} symbols` : `, ${attrs.change}`;
+    }
+    parts.push(`tooltip=${quoteDot(tooltip)}`);
+    statements.set(node, `${quoteDot(node)} [${parts.join(', ')}];`);
+  }
+
+  if (options.clusters) {
+    for (const cluster of options.clusters) {
+      // Single nodes gain nothing from a box around them
+      if (cluster.members.length < 2) {
+        cluster.members.filter(m => statements.has(m)).forEach(m => lines.push(`  ${statements.get(m)}`));
+        continue;
+      }
+      lines.push(`  subgraph cluster_${cluster.id} {`);
+      lines.push(`    label=${quoteDot(cluster.label)}; fontname="Helvetica"; fontsize=11; style="rounded,dashed"; color="#8a8aa0";`);
+      cluster.members.filter(m => statements.has(m)).sort().forEach(m => lines.push(`    ${statements.get(m)}`));
+      lines.push('  }');
+    }
+  } else {
+    for (const statement of statements.values()) lines.push(`  ${statement}`);
+  }
+
+  const edges = graph.mapEdges((_edge, attrs, source, target) => ({ source, target, ...attrs }))
+    .sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target));
+  for (const edge of edges) {
+    const width = Math.min(5, 1 + Math.log10(edge.weight)).toFixed(2);
+    const value = heat ? edge[heat.attribute] : undefined;
+    const metric = typeof value === 'number' ? `, ${heat!.attribute} ${formatMetricValue(value)}` : '';
+    const parts = [`penwidth=${width}`, `tooltip=${quoteDot(`${edge.weight} references${metric}${edge.change ? `, ${edge.change}` : ''}`)}`];
+    if (typeof value === 'number' && !edge.change) parts.push(`color=${quoteDot(heat!.color(value))}`);
+    if (edge.change) parts.push(`color=${quoteDot(CHANGE_COLORS[edge.change].stroke)}`);
+    if (edge.change === 'removed') parts.push('style=dashed');
+    lines.push(`  ${quoteDot(edge.source)} -> ${quoteDot(edge.target)} [${parts.join(', ')}];`);
+  }
+
+  if (heat) {
+    // A chain of swatches, low to high, kept out of the main layout's ranks
+    const stops = legendStops(heat);
+    lines.push('  subgraph cluster_legend {');
+    lines.push(`    label=${quoteDot(heat.attribute)}; fontname="Helvetica"; fontsize=10; style="rounded"; color="#c0c0cc";`);
+    stops.forEach((stop, i) => {
+      lines.push(`    "__legend_${i}" [label=${quoteDot(formatMetricValue(stop.value))}, fillcolor=${quoteDot(stop.color)}, shape=box, style="filled", width=0.4, height=0.25];`);
+    });
+    if (stops.length > 1) {
+      lines.push(`    ${stops.map((_, i) => `"__legend_${i}"`).join(' -> ')} [style=invis];`);
+    }
+    lines.push('  }');
+  }
+
+  lines.push('}');
+  return lines.join('\n') + '\n';
+}
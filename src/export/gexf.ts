@@ -0,0 +1,191 @@
+import { packageOf } from '../graph/model.js';
+import type { TemporalSnapshot } from '../temporal/types.js';
+import type { ExportGraph, ExportLevel } from './graph.js';
+import { escapeXml } from './svg.js';
+
+/**
+ * GEXF 1.3 for Gephi. toGexf() writes one export graph with every node
+ * attribute the exporters know (metrics, clusters, diff changes);
+ * historyGexf() writes a dynamic graph from history snapshots, where nodes
+ * and edges live for the revisions they exist in and sizes and weights
+ * change per revision, so Gephi's timeline can replay the evolution.
+ */
+
+const HEADER = '<?xml version="1.0" encoding="UTF-8"?>\n<gexf xmlns="http://gexf.net/1.3" version="1.3">';
+
+type AttributeType = 'integer' | 'double' | 'string';
+
+// Attributes written as GEXF attributes rather than the node's label
+const SKIPPED = new Set(['label']);
+
+function attributeTypes(graph: ExportGraph): Map<string, AttributeType> {
+  const types = new Map<string, AttributeType>();
+  graph.forEachNode((_node, attrs) => {
+    for (const [key, value] of Object.entries(attrs)) {
+      if (SKIPPED.has(key) || value === undefined) continue;
+      const type: AttributeType | null = typeof value === 'number'
+        ? (Number.isInteger(value) ? 'integer' : 'double')
+        : typeof value === 'string' ? 'string' : null;
+      if (!type) continue;
+      const known = types.get(key);
+      // A column with any fractional value is a double; mixed kinds become strings
+      if (!known) types.set(key, type);
+      else if (known !== type) types.set(key, known !== 'string' && type !== 'string' ? 'double' : 'string');
+    }
+  });
+  return types;
+}
+
+export function toGexf(graph: ExportGraph, options: { name?: string } = {}): string {
+  const types = attributeTypes(graph);
+  const ids = new Map([...types.keys()].map((key, i) => [key, String(i)]));
+  const out: string[] = [HEADER];
+  out.push(`  <meta><creator>depwire</creator><description>${escapeXml(options.name ?? 'depwire')}</description></meta>`);
+  out.push('  <graph defaultedgetype="directed" mode="static">');
+  out.push('    <attributes class="node" mode="static">');
+  for (const [key, type] of types) out.push(`      <attribute id="${ids.get(key)}" title="${escapeXml(key)}" type="${type}"/>`);
+  out.push('    </attributes>');
+
+  out.push('    <nodes>');
+  for (const node of graph.nodes().sort()) {
+    const attrs = graph.getNodeAttributes(node);
+    out.push(`      <node id="${escapeXml(node)}" label="${escapeXml(attrs.label)}">`);
+    out.push('        <attvalues>');
+    for (const key of types.keys()) {
+      const value = attrs[key];
+      if (typeof value === 'number' || typeof value === 'string') {
+        out.push(`          <attvalue for="${ids.get(key)}" value="${escapeXml(String(value))}"/>`);
+      }
+    }
+    out.push('        </attvalues>');
+    out.push('      </node>');
+  }
+  out.push('    </nodes>');
+
+  out.push('    <edges>');
+  const edges = graph.mapEdges((_edge, attrs, source, target) => ({ source, target, ...attrs }))
+    .sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target));
+  edges.forEach((edge, i) => {
+    const label = edge.change ? ` label="${edge.change}"` : '';
+    out.push(`      <edge id="${i}" source="${escapeXml(edge.source)}" target="${escapeXml(edge.target)}" weight="${edge.weight}"${label}/>`);
+  });
+  out.push('    </edges>');
+  out.push('  </graph>');
+  out.push('</gexf>');
+  return out.join('\n') + '\n';
+}
+
+interface Revision {
+  nodes: Map<string, { files: number; symbols: number }>;
+  edges: Map<string, { source: string; target: string; weight: number }>;
+}
+
+function revisionOf(snapshot: TemporalSnapshot, level: ExportLevel): Revision {
+  const keyOf = (path: string) => (level === 'file' ? path : packageOf(path));
+  const nodes = new Map<string, { files: number; symbols: number }>();
+  for (const file of snapshot.files) {
+    const node = nodes.get(keyOf(file.path)) ?? { files: 0, symbols: 0 };
+    node.files++;
+    node.symbols += file.symbols;
+    nodes.set(keyOf(file.path), node);
+  }
+  const edges = new Map<string, { source: string; target: string; weight: number }>();
+  for (const edge of snapshot.edges) {
+    const source = keyOf(edge.source);
+    const target = keyOf(edge.target);
+    if (source === target) continue;
+    const key = `${source}\0${target}`;
+    const existing = edges.get(key);
+    if (existing) existing.weight += edge.weight;
+    else edges.set(key, { source, target, weight: edge.weight });
+  }
+  return { nodes, edges };
+}
+
+/**
+ * Runs of equal values over revisions as GEXF intervals. Revision i covers
+ * [start of i, start of i+1); the last one stays open.
+ */
+function intervals<T>(values: Array<T | undefined>, starts: string[]): Array<{ value: T; start: string; end?: string }> {
+  const runs: Array<{ value: T; start: string; end?: string }> = [];
+  for (let i = 0; i < values.length; i++) {
+    const value = values[i];
+    if (value === undefined) continue;
+    const previous = runs[runs.length - 1];
+    if (previous && previous.end === starts[i] && previous.value === value) {
+      previous.end = starts[i + 1];
+    } else {
+      runs.push({ value, start: starts[i], end: starts[i + 1] });
+    }
+  }
+  return runs;
+}
+
+function bounds(run: { start: string; end?: string }): string {
+  return `start="${run.start}"${run.end ? ` end="${run.end}"` : ''}`;
+}
+
+export function historyGexf(
+  snapshots: TemporalSnapshot[],
+  options: { level?: ExportLevel; name?: string; label?: (node: string) => string } = {}
+): string {
+  const level = options.level ?? 'package';
+  const label = options.label ?? ((node: string) => node);
+  const revisions = snapshots.map(snapshot => revisionOf(snapshot, level));
+  const starts = snapshots.map(snapshot => new Date(snapshot.commitDate).toISOString());
+
+  const out: string[] = [HEADER];
+  out.push(`  <meta><creator>depwire</creator><description>${escapeXml(`${options.name ?? 'depwire'}: ${snapshots.length} revisions`)}</description></meta>`);
+  out.push('  <graph defaultedgetype="directed" mode="dynamic" timeformat="datetime" timerepresentation="interval">');
+  out.push('    <attributes class="node" mode="dynamic">');
+  out.push('      <attribute id="files" title="files" type="integer"/>');
+  out.push('      <attribute id="symbols" title="symbols" type="integer"/>');
+  out.push('    </attributes>');
+  out.push('    <attributes class="node" mode="static">');
+  out.push('      <attribute id="commit" title="first commit" type="string"/>');
+  out.push('    </attributes>');
+  // Gephi reads an edge attribute titled "weight" as the dynamic edge weight
+  out.push('    <attributes class="edge" mode="dynamic">');
+  out.push('      <attribute id="weight" title="weight" type="float"/>');
+  out.push('    </attributes>');
+
+  const nodeKeys = [...new Set(revisions.flatMap(r => [...r.nodes.keys()]))].sort();
+  out.push('    <nodes>');
+  for (const node of nodeKeys) {
+    const present = revisions.map(r => (r.nodes.has(node) ? true : undefined));
+    const first = revisions.findIndex(r => r.nodes.has(node));
+    out.push(`      <node id="${escapeXml(node)}" label="${escapeXml(label(node))}">`);
+    out.push('        <attvalues>');
+    out.push(`          <attvalue for="commit" value="${snapshots[first].commitHash}"/>`);
+    for (const attribute of ['files', 'symbols'] as const) {
+      for (const run of intervals(revisions.map(r => r.nodes.get(node)?.[attribute]), starts)) {
+        out.push(`          <attvalue for="${attribute}" value="${run.value}" ${bounds(run)}/>`);
+      }
+    }
+    out.push('        </attvalues>');
+    out.push('        <spells>');
+    for (const run of intervals(present, starts)) out.push(`          <spell ${bounds(run)}/>`);
+    out.push('        </spells>');
+    out.push('      </node>');
+  }
+  out.push('    </nodes>');
+
+  const edgeKeys = [...new Set(revisions.flatMap(r => [...r.edges.keys()]))].sort();
+  out.push('    <edges>');
+  edgeKeys.forEach((key, i) => {
+    const [source, target] = key.split('\0');
+    const weights = revisions.map(r => r.edges.get(key)?.weight);
+    out.push(`      <edge id="${i}" source="${escapeXml(source)}" target="${escapeXml(target)}">`);
+    out.push('        <attvalues>');
+    for (const run of intervals(weights, starts)) out.push(`          <attvalue for="weight" value="${run.value}" ${bounds(run)}/>`);
+    out.push('        </attvalues>');
+    out.push('        <spells>');
+    for (const run of intervals(weights.map(w => (w === undefined ? undefined : true)), starts)) out.push(`          <spell ${bounds(run)}/>`);
+    out.push('        </spells>');
+    out.push('      </edge>');
+  });
+  out.push('    </edges>');
+  out.push('  </graph>');
+  out.push('</gexf>');
+  return out.join('\n') + '\n';
+}
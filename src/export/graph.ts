@@ -0,0 +1,224 @@
+import { readFileSync } from 'fs';
+import { join } from 'path';
+import { DirectedGraph } from 'graphology';
+import { toFileGraph, toPackageGraph, packageOf, type SourcePosition } from '../graph/model.js';
+import { neighborhood, communities, type Direction } from '../graph/algorithms.js';
+import { findGoModules, GoModuleIndex, type GoModule } from '../golang/modules.js';
+import { scanGoImportSpecs } from '../parser/imports.js';
+import type { DependencyScope } from '../parser/filter.js';
+import type { ChangeKind } from './diff.js';
+
+/**
+ * The graph the exporters render: packages (directories) or files, with
+ * weighted edges counting the symbol references between them, each with
+ * the position of its first reference. Go packages are labelled with their
+ * import path. With the external scope (--only-external) it is the
+ * third-party Go modules instead, one node per module, with edges from the
+ * packages importing them weighted by the number of importing files.
+ */
+
+export type ExportLevel = 'package' | 'file';
+
+export interface ExportNodeAttributes {
+  label: string;
+  /** The package directory; for files, the directory they live in */
+  package: string;
+  files: number;
+  symbols: number;
+  /** Hops from the focused node, when the graph was narrowed with focusGraph() */
+  distance?: number;
+  /** Community index set by clusterGraph() */
+  cluster?: number;
+  /** Set by overlayDiff(); changed nodes also count their added and removed symbols */
+  change?: ChangeKind;
+  symbolsAdded?: number;
+  symbolsRemoved?: number;
+  /** A third-party module rather than a workspace package or file */
+  external?: boolean;
+  /** The label before relabelGraph() gave it a display name */
+  fullLabel?: string;
+  [attribute: string]: unknown;
+}
+
+export interface ExportEdgeAttributes {
+  weight: number;
+  /** Where the dependency shows up first: an import statement or call site */
+  witness?: SourcePosition;
+  change?: Exclude<ChangeKind, 'changed'>;
+  /** Metrics set on edges by applyMetric(), such as coverage and cpu */
+  [attribute: string]: unknown;
+}
+
+export type ExportGraph = DirectedGraph<ExportNodeAttributes, ExportEdgeAttributes>;
+
+export function buildExportGraph(graph: DirectedGraph, projectRoot: string, options: { level?: ExportLevel; scope?: DependencyScope } = {}): ExportGraph {
+  const exportGraph = buildWorkspaceGraph(graph, projectRoot, options.level);
+  if (options.scope === 'external') {
+    addExternalModules(exportGraph, graph, projectRoot, options.level ?? 'package');
+    // Only the edges into third-party modules, and the packages that have them
+    for (const edge of exportGraph.filterEdges((_edge, _attrs, _source, target) => !exportGraph.getNodeAttribute(target, 'external'))) {
+      exportGraph.dropEdge(edge);
+    }
+    for (const node of exportGraph.filterNodes((node, attrs) => !attrs.external && exportGraph.degree(node) === 0)) {
+      exportGraph.dropNode(node);
+    }
+  }
+  return exportGraph;
+}
+
+/** The module a third-party import path belongs to: the longest required module path that prefixes it */
+function requiredModuleOf(importPath: string, required: string[]): string | null {
+  let best: string | null = null;
+  for (const path of required) {
+    if ((importPath === path || importPath.startsWith(`${path}/`)) && (!best || path.length > best.length)) best = path;
+  }
+  return best;
+}
+
+function addExternalModules(exportGraph: ExportGraph, graph: DirectedGraph, projectRoot: string, level: ExportLevel): void {
+  const modules: GoModule[] = findGoModules(projectRoot);
+  const index = new GoModuleIndex(modules);
+  const required = [...new Set(modules.flatMap(m => m.mod.require.map(r => r.path)))];
+  const files = new Set<string>();
+  graph.forEachNode((_node, attrs) => {
+    if (attrs.filePath.endsWith('.go')) files.add(attrs.filePath);
+  });
+
+  for (const file of files) {
+    let source: string;
+    try {
+      source = readFileSync(join(projectRoot, file), 'utf-8');
+    } catch {
+      continue;
+    }
+    const from = level === 'file' ? file : packageOf(file);
+    if (!exportGraph.hasNode(from)) continue;
+    const seen = new Set<string>();
+    for (const spec of scanGoImportSpecs(source)) {
+      // The standard library has no dot in its first path element
+      if (!spec.path.split('/')[0].includes('.') || index.moduleForImport(spec.path)) continue;
+      const module = requiredModuleOf(spec.path, required) ?? spec.path;
+      if (seen.has(module)) continue;
+      seen.add(module);
+      const node = `mod:${module}`;
+      if (!exportGraph.hasNode(node)) exportGraph.addNode(node, { label: module, package: '', files: 0, symbols: 0, external: true });
+      if (exportGraph.hasEdge(from, node)) exportGraph.updateEdgeAttribute(from, node, 'weight', (w) => (w ?? 0) + 1);
+      else exportGraph.addEdge(from, node, { weight: 1, witness: { file, line: spec.line, column: spec.column } });
+    }
+  }
+}
+
+function buildWorkspaceGraph(graph: DirectedGraph, projectRoot: string, level?: ExportLevel): ExportGraph {
+  const exportGraph: ExportGraph = new DirectedGraph();
+
+  if (level === 'file') {
+    const symbols = new Map<string, number>();
+    graph.forEachNode((_node, attrs) => {
+      if (attrs.name !== '__file__') symbols.set(attrs.filePath, (symbols.get(attrs.filePath) ?? 0) + 1);
+    });
+    const fileGraph = toFileGraph(graph);
+    fileGraph.forEachNode((file) => {
+      exportGraph.addNode(file, { label: file, package: packageOf(file), files: 1, symbols: symbols.get(file) ?? 0 });
+    });
+    fileGraph.forEachEdge((_edge, attrs, source, target) => exportGraph.addEdge(source, target, { weight: attrs.weight, witness: attrs.witness }));
+    return exportGraph;
+  }
+
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const packageGraph = toPackageGraph(graph);
+  packageGraph.forEachNode((pkg, attrs) => {
+    exportGraph.addNode(pkg, { label: index.importForDir(pkg) ?? pkg, package: pkg, files: attrs.files, symbols: attrs.symbols });
+  });
+  packageGraph.forEachEdge((_edge, attrs, source, target) => exportGraph.addEdge(source, target, { weight: attrs.weight, witness: attrs.witness }));
+  return exportGraph;
+}
+
+/**
+ * Find the node a --focus argument names: its key, its label (import path,
+ * or display name after relabelGraph()), or a unique path suffix ("store"
+ * for internal/store).
+ */
+export function resolveFocus(graph: ExportGraph, focus: string): string {
+  const wanted = focus.replace(/\/+$/, '');
+  if (graph.hasNode(wanted)) return wanted;
+  const byLabel = graph.findNode((_node, attrs) => attrs.label === wanted || attrs.fullLabel === wanted);
+  if (byLabel !== undefined) return byLabel;
+
+  const matches = graph.filterNodes((node, attrs) => node.endsWith(`/${wanted}`) || (attrs.fullLabel ?? attrs.label).endsWith(`/${wanted}`));
+  if (matches.length === 1) return matches[0];
+  if (matches.length > 1) {
+    throw new Error(`"${focus}" is ambiguous: ${matches.slice(0, 5).join(', ')}${matches.length > 5 ? ', …' : ''}`);
+  }
+  throw new Error(`No package or file matches "${focus}"`);
+}
+
+/**
+ * The neighborhood of one node: everything within `hops` edges in the given
+ * direction, with the edges between those nodes. Each node gets `distance`.
+ */
+export function focusGraph(graph: ExportGraph, focus: string, options: { hops?: number; direction?: Direction } = {}): ExportGraph {
+  const center = resolveFocus(graph, focus);
+  const distances = neighborhood(graph, center, options.hops ?? 2, options.direction ?? 'both');
+
+  const focused: ExportGraph = new DirectedGraph();
+  for (const [node, distance] of distances) {
+    focused.addNode(node, { ...graph.getNodeAttributes(node), distance });
+  }
+  graph.forEachEdge((_edge, attrs, source, target) => {
+    if (focused.hasNode(source) && focused.hasNode(target)) focused.addEdge(source, target, { ...attrs });
+  });
+  return focused;
+}
+
+export interface Cluster {
+  id: number;
+  label: string;
+  members: string[];
+}
+
+function commonPrefix(labels: string[]): string[] {
+  const parts = labels.map(label => label.split('/'));
+  const common: string[] = [];
+  for (let i = 0; parts.length > 0 && parts.every(p => i < p.length - 1 && p[i] === parts[0][i]); i++) common.push(parts[0][i]);
+  return common;
+}
+
+// The members' common path below what every node shares, or the best-connected member
+function clusterLabel(graph: ExportGraph, members: string[], shared: number): string {
+  const common = commonPrefix(members.map(m => graph.getNodeAttribute(m, 'label')));
+  if (members.length > 1 && common.length > shared) return `${common.join('/')}/…`;
+  const hub = [...members].sort((a, b) => graph.degree(b) - graph.degree(a) || a.localeCompare(b))[0];
+  const label = graph.getNodeAttribute(hub, 'label');
+  return members.length > 1 ? `${label} +${members.length - 1}` : label;
+}
+
+/**
+ * Group nodes into de facto modules by community detection on the
+ * reference-weighted graph; sets `cluster` on every node.
+ */
+export function clusterGraph(graph: ExportGraph, options: { resolution?: number } = {}): Cluster[] {
+  const groups = communities(graph, { weight: 'weight', resolution: options.resolution });
+  const clusters: Cluster[] = [];
+  for (const [node, id] of groups) {
+    graph.setNodeAttribute(node, 'cluster', id);
+    (clusters[id] ??= { id, label: '', members: [] }).members.push(node);
+  }
+  const shared = commonPrefix(graph.mapNodes((_node, attrs) => attrs.label)).length;
+  for (const cluster of clusters) cluster.label = clusterLabel(graph, cluster.members, shared);
+  return clusters;
+}
+
+export interface GraphDocument {
+  nodes: Array<ExportNodeAttributes & { id: string }>;
+  edges: Array<ExportEdgeAttributes & { source: string; target: string }>;
+  clusters?: Cluster[];
+}
+
+/** The JSON document of `depwire graph --format json` */
+export function toGraphDocument(graph: ExportGraph, clusters?: Cluster[]): GraphDocument {
+  return {
+    nodes: graph.mapNodes((id, attrs) => ({ id, ...attrs })),
+    edges: graph.mapEdges((_edge, attrs, source, target) => ({ source, target, ...attrs })),
+    ...(clusters ? { clusters } : {}),
+  };
+}
@@ -0,0 +1,74 @@
+import type { ExportGraph } from './graph.js';
+import { toSvg, escapeXml, type SvgOptions } from './svg.js';
+import { diffSummary } from './diff.js';
+
+/**
+ * A self-contained HTML page around the SVG export: scrollable, with the
+ * hovered node's edges and neighbors highlighted. No external scripts, so
+ * it can be attached to a review or published as a CI artifact. A diff
+ * overlay gets a summary line and a toggle to hide unchanged nodes.
+ */
+
+export function toHtml(graph: ExportGraph, options: SvgOptions = {}): string {
+  const title = options.name ? `${options.name} — Depwire graph` : 'Depwire graph';
+  const stats = `${graph.order} nodes, ${graph.size} edges${options.heatmap ? `, colored by ${options.heatmap.attribute}` : ''}`;
+  const diff = diffSummary(graph);
+  const changes = diff
+    ? `${diff.addedNodes} added, ${diff.removedNodes} removed, ${diff.changedNodes} changed; ` +
+      `edges +${diff.addedEdges} −${diff.removedEdges}`
+    : '';
+  return `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>${escapeXml(title)}</title>
+  <style>
+    body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif; color: #1a1a2e; }
+    header { padding: 12px 20px; border-bottom: 1px solid #e0e0ea; }
+    header h1 { font-size: 16px; margin: 0 0 4px; }
+    header p { font-size: 12px; color: #666; margin: 0; }
+    main { overflow: auto; height: calc(100vh - ${diff ? 80 : 60}px); }
+    .node { cursor: default; }
+    svg.hovering .edge { stroke-opacity: 0.1; }
+    svg.hovering .node { opacity: 0.3; }
+    svg.hovering .edge.active { stroke-opacity: 1; stroke: #4a9eff; }
+    svg.hovering .node.active { opacity: 1; }
+    label { font-size: 12px; color: #444; }
+    svg.changes-only .node:not(.added):not(.removed):not(.changed) { opacity: 0.15; }
+    svg.changes-only .edge:not(.added):not(.removed) { stroke-opacity: 0.08; }
+  </style>
+</head>
+<body>
+  <header>
+    <h1>${escapeXml(title)}</h1>
+    <p>${escapeXml(stats)}</p>${diff ? `
+    <p>${escapeXml(changes)} <label><input type="checkbox" id="changesOnly"> fade unchanged</label></p>` : ''}
+  </header>
+  <main>
+${toSvg(graph, options)}
+  </main>
+  <script>
+    const svg = document.querySelector('main svg');
+    const edges = [...svg.querySelectorAll('.edge')];
+    svg.querySelectorAll('.node').forEach((node) => {
+      const id = node.dataset.id;
+      node.addEventListener('mouseenter', () => {
+        const neighbors = new Set([id]);
+        edges.forEach((edge) => {
+          const active = edge.dataset.source === id || edge.dataset.target === id;
+          edge.classList.toggle('active', active);
+          if (active) { neighbors.add(edge.dataset.source); neighbors.add(edge.dataset.target); }
+        });
+        svg.querySelectorAll('.node').forEach(n => n.classList.toggle('active', neighbors.has(n.dataset.id)));
+        svg.classList.add('hovering');
+      });
+      node.addEventListener('mouseleave', () => svg.classList.remove('hovering'));
+    });
+    const changesOnly = document.getElementById('changesOnly');
+    if (changesOnly) changesOnly.addEventListener('change', () => svg.classList.toggle('changes-only', changesOnly.checked));
+  </script>
+</body>
+</html>
+`;
+}
@@ -0,0 +1,170 @@
+import { GoModuleIndex, findGoModules } from '../golang/modules.js';
+import type { ExportGraph } from './graph.js';
+
+/**
+ * Display names for the exported graph, so a diagram of
+ * github.com/acme/platform/internal/... stays readable: strip the module
+ * path, rename by regex, and shorten deep paths to their last segments.
+ * Aliases win outright; otherwise the module is stripped first and the
+ * result shortened. Only `label` changes — node keys, and the full label
+ * kept in `fullLabel`, still resolve --focus and --package.
+ */
+
+export interface LabelAlias {
+  pattern: RegExp;
+  /** Replacement for the whole label; $1… refer to the pattern's groups */
+  label: string;
+}
+
+export interface LabelOptions {
+  /** github.com/acme/platform/internal/store → internal/store */
+  stripModule?: boolean;
+  /** Tried in order against the full label; the first match names the node */
+  aliases?: LabelAlias[];
+  /** Keep this many trailing path segments, more where that leaves two nodes with one name */
+  maxSegments?: number;
+  /** Marks elided segments (default …) */
+  ellipsis?: string;
+}
+
+/** An alias from its CLI form, pattern=label (split at the last =, so the pattern may contain one) */
+export function parseAlias(rule: string): LabelAlias {
+  const at = rule.lastIndexOf('=');
+  if (at <= 0) throw new Error(`Alias "${rule}" must look like pattern=label`);
+  return compileAlias(rule.slice(0, at), rule.slice(at + 1));
+}
+
+export function compileAlias(pattern: string, label: string): LabelAlias {
+  try {
+    return { pattern: new RegExp(pattern), label };
+  } catch (err) {
+    throw new Error(`Alias pattern "${pattern}" is not a valid regular expression: ${err instanceof Error ? err.message : err}`);
+  }
+}
+
+/** The "labels" section of depwire.json; aliases map patterns to labels, in order */
+export function parseLabelOptions(value: unknown, source: string): LabelOptions {
+  if (typeof value !== 'object' || value === null || Array.isArray(value)) {
+    throw new Error(`${source}: "labels" must be an object with stripModule, aliases and maxSegments`);
+  }
+  const raw = value as Record<string, unknown>;
+  const options: LabelOptions = {};
+  if (raw.stripModule !== undefined) {
+    if (typeof raw.stripModule !== 'boolean') throw new Error(`${source}: labels.stripModule must be true or false`);
+    options.stripModule = raw.stripModule;
+  }
+  if (raw.aliases !== undefined) {
+    if (typeof raw.aliases !== 'object' || raw.aliases === null || Array.isArray(raw.aliases) || !Object.values(raw.aliases).every(l => typeof l === 'string')) {
+      throw new Error(`${source}: labels.aliases must map patterns to labels`);
+    }
+    options.aliases = Object.entries(raw.aliases as Record<string, string>).map(([pattern, label]) => compileAlias(pattern, label));
+  }
+  if (raw.maxSegments !== undefined) options.maxSegments = parseSegments(raw.maxSegments, `${source}: labels.maxSegments`);
+  return options;
+}
+
+function parseSegments(value: unknown, what: string): number {
+  const n = typeof value === 'string' ? Number(value) : value;
+  if (typeof n !== 'number' || !Number.isInteger(n) || n < 1) throw new Error(`${what} must be a positive integer`);
+  return n;
+}
+
+export interface LabelFlags {
+  stripModule?: boolean;
+  /** pattern=label rules */
+  alias?: string[];
+  /** Trailing segments to keep */
+  shorten?: string;
+}
+
+/** Command-line flags over the config's labels: flags win, and their aliases are tried first */
+export function resolveLabelOptions(flags: LabelFlags, configured: LabelOptions = {}): LabelOptions {
+  return {
+    stripModule: flags.stripModule || configured.stripModule,
+    aliases: [...(flags.alias ?? []).map(parseAlias), ...(configured.aliases ?? [])],
+    maxSegments: flags.shorten !== undefined ? parseSegments(flags.shorten, '--shorten') : configured.maxSegments,
+  };
+}
+
+// The module's last element, skipping a major version: example.com/app/v2 and gopkg.in/app.v2 are app
+function moduleName(modulePath: string): string {
+  const parts = modulePath.split('/');
+  let last = parts.pop()!;
+  if (/^v\d+$/.test(last) && parts.length > 0) last = parts.pop()!;
+  return last.replace(/\.v\d+$/, '');
+}
+
+// The package path below its module; the module's root package goes by the module's name
+function moduleRelative(label: string, index: GoModuleIndex, qualified = false): string {
+  const module = index.moduleForImport(label);
+  if (!module) return label;
+  const name = moduleName(module.path);
+  if (label === module.path) return name;
+  const sub = label.slice(module.path.length + 1);
+  return qualified ? `${name}/${sub}` : sub;
+}
+
+// Trailing segments of each label, adding segments only where names would collide
+function shorten(labels: Map<string, string>, maxSegments: number, ellipsis: string): Map<string, string> {
+  const segments = new Map([...labels].map(([node, label]) => [node, label.split('/')]));
+  const keep = new Map([...labels.keys()].map(node => [node, maxSegments]));
+  const tail = (node: string) => {
+    const parts = segments.get(node)!;
+    const n = keep.get(node)!;
+    return parts.length > n ? `${ellipsis}/${parts.slice(-n).join('/')}` : parts.join('/');
+  };
+  for (;;) {
+    const byName = new Map<string, string[]>();
+    for (const node of labels.keys()) {
+      const name = tail(node);
+      byName.set(name, [...(byName.get(name) ?? []), node]);
+    }
+    let grew = false;
+    for (const nodes of byName.values()) {
+      if (nodes.length < 2) continue;
+      for (const node of nodes) {
+        if (keep.get(node)! < segments.get(node)!.length) {
+          keep.set(node, keep.get(node)! + 1);
+          grew = true;
+        }
+      }
+    }
+    if (!grew) return new Map([...labels.keys()].map(node => [node, tail(node)]));
+  }
+}
+
+/** Rename the graph's nodes in place; `fullLabel` keeps what they were called */
+export function relabelGraph(graph: ExportGraph, projectRoot: string, options: LabelOptions): void {
+  const aliases = options.aliases ?? [];
+  if (!options.stripModule && aliases.length === 0 && !options.maxSegments) return;
+  const index = options.stripModule ? new GoModuleIndex(findGoModules(projectRoot)) : null;
+
+  const aliased = new Map<string, string>();
+  const derived = new Map<string, string>();
+  graph.forEachNode((node, attrs) => {
+    const full = attrs.fullLabel ?? attrs.label;
+    for (const alias of aliases) {
+      const match = alias.pattern.exec(full);
+      if (!match) continue;
+      aliased.set(node, alias.label.replace(/\$(\d+)/g, (_, group) => match[Number(group)] ?? ''));
+      return;
+    }
+    derived.set(node, index && !attrs.external ? moduleRelative(full, index) : full);
+  });
+  if (index) {
+    // internal/store in two modules of a workspace: keep the module's name on both
+    const counts = new Map<string, number>();
+    for (const label of derived.values()) counts.set(label, (counts.get(label) ?? 0) + 1);
+    for (const [node, label] of derived) {
+      if (counts.get(label)! > 1) derived.set(node, moduleRelative(graph.getNodeAttribute(node, 'fullLabel') ?? graph.getNodeAttribute(node, 'label'), index, true));
+    }
+  }
+  const shortened = options.maxSegments ? shorten(derived, options.maxSegments, options.ellipsis ?? '…') : derived;
+
+  for (const labels of [aliased, shortened]) {
+    for (const [node, label] of labels) {
+      graph.setNodeAttribute(node, 'fullLabel', graph.getNodeAttribute(node, 'fullLabel') ?? graph.getNodeAttribute(node, 'label'));
+      graph.setNodeAttribute(node, 'label', label);
+    }
+  }
+}
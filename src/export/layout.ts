@@ -0,0 +1,138 @@
+import type { AbstractGraph } from 'graphology-types';
+import { stronglyConnectedComponents } from '../graph/algorithms.js';
+
+/**
+ * A left-to-right layered layout for the SVG and HTML exporters, so they
+ * don't need Graphviz installed. Dependents sit left of their dependencies;
+ * members of an import cycle share a column. Rows within a column are
+ * ordered by the barycenter heuristic to keep edges short.
+ */
+
+type Graph = AbstractGraph<any, any, any>;
+
+export interface NodeBox {
+  x: number;
+  y: number;
+  width: number;
+  height: number;
+  layer: number;
+}
+
+export interface Layout {
+  nodes: Map<string, NodeBox>;
+  width: number;
+  height: number;
+}
+
+export interface LayoutOptions {
+  label?: (node: string) => string;
+  /** Nodes in the same group are kept together within each column */
+  group?: (node: string) => number;
+  /** Approximate glyph width at the exporters' 12px font */
+  charWidth?: number;
+}
+
+const NODE_HEIGHT = 24;
+const ROW_GAP = 12;
+const COLUMN_GAP = 80;
+const MARGIN = 20;
+
+function assignLayers(graph: Graph): Map<string, number> {
+  const componentOf = new Map<string, number>();
+  const components = stronglyConnectedComponents(graph);
+  components.forEach((members, i) => members.forEach(m => componentOf.set(m, i)));
+
+  // Longest path from the roots of the condensation (nodes nothing depends on)
+  const successors = components.map(() => new Set<number>());
+  const inDegree = components.map(() => 0);
+  graph.forEachEdge((_edge, _attrs, source, target) => {
+    const from = componentOf.get(source)!;
+    const to = componentOf.get(target)!;
+    if (from === to || successors[from].has(to)) return;
+    successors[from].add(to);
+    inDegree[to]++;
+  });
+
+  const layerOf = components.map(() => 0);
+  const queue = components.map((_, i) => i).filter(i => inDegree[i] === 0);
+  while (queue.length > 0) {
+    const current = queue.shift()!;
+    for (const next of successors[current]) {
+      layerOf[next] = Math.max(layerOf[next], layerOf[current] + 1);
+      if (--inDegree[next] === 0) queue.push(next);
+    }
+  }
+
+  const layers = new Map<string, number>();
+  graph.forEachNode((node) => layers.set(node, layerOf[componentOf.get(node)!]));
+  return layers;
+}
+
+function orderLayers(
+  graph: Graph,
+  layers: Map<string, number>,
+  label: (node: string) => string,
+  group: (node: string) => number
+): string[][] {
+  const columns: string[][] = [];
+  for (const [node, layer] of layers) {
+    (columns[layer] ??= []).push(node);
+  }
+  for (let i = 0; i < columns.length; i++) {
+    columns[i] = (columns[i] ?? []).sort((a, b) => group(a) - group(b) || label(a).localeCompare(label(b)));
+  }
+
+  const position = new Map<string, number>();
+  const record = () => columns.forEach(column => column.forEach((node, i) => position.set(node, i / Math.max(1, column.length - 1))));
+  record();
+
+  const barycenter = (node: string, neighbors: string[]): number => {
+    const placed = neighbors.filter(n => position.has(n) && n !== node);
+    if (placed.length === 0) return position.get(node)!;
+    return placed.reduce((sum, n) => sum + position.get(n)!, 0) / placed.length;
+  };
+
+  for (let sweep = 0; sweep < 4; sweep++) {
+    const forward = sweep % 2 === 0;
+    const indices = columns.map((_, i) => i);
+    if (!forward) indices.reverse();
+    for (const i of indices.slice(1)) {
+      const weights = new Map(columns[i].map(node => [
+        node,
+        barycenter(node, forward ? graph.inNeighbors(node) : graph.outNeighbors(node)),
+      ]));
+      columns[i].sort((a, b) => group(a) - group(b) || weights.get(a)! - weights.get(b)! || label(a).localeCompare(label(b)));
+      record();
+    }
+  }
+  return columns;
+}
+
+export function layeredLayout(graph: Graph, options: LayoutOptions = {}): Layout {
+  const label = options.label ?? ((node: string) => node);
+  const charWidth = options.charWidth ?? 7;
+  const columns = orderLayers(graph, assignLayers(graph), label, options.group ?? (() => 0));
+
+  const nodes = new Map<string, NodeBox>();
+  const tallest = Math.max(0, ...columns.map(column => column.length));
+  const columnHeight = tallest * (NODE_HEIGHT + ROW_GAP) - ROW_GAP;
+  let x = MARGIN;
+
+  columns.forEach((column, layer) => {
+    const widths = column.map(node => Math.max(40, label(node).length * charWidth + 16));
+    const columnWidth = Math.max(0, ...widths);
+    // Shorter columns are centered against the tallest one
+    let y = MARGIN + (columnHeight - (column.length * (NODE_HEIGHT + ROW_GAP) - ROW_GAP)) / 2;
+    column.forEach((node, i) => {
+      nodes.set(node, { x, y, width: widths[i], height: NODE_HEIGHT, layer });
+      y += NODE_HEIGHT + ROW_GAP;
+    });
+    x += columnWidth + COLUMN_GAP;
+  });
+
+  return {
+    nodes,
+    width: Math.max(2 * MARGIN, x - COLUMN_GAP + MARGIN),
+    height: Math.max(2 * MARGIN, columnHeight + 2 * MARGIN),
+  };
+}
@@ -0,0 +1,56 @@
+import type { ExportGraph, Cluster } from './graph.js';
+
+/**
+ * Mermaid flowchart for an export graph, for Markdown that GitHub and
+ * GitLab render in place. Node ids are positional (mermaid ids can't hold
+ * slashes or dots); labels are the import path or file path. Clusters
+ * become subgraphs and removed diff edges are dotted. Only the flowchart
+ * text is returned; wrap it in a ```mermaid fence to embed it.
+ */
+
+export interface MermaidOptions {
+  /** Flowchart direction; LR reads best for long import paths */
+  direction?: 'LR' | 'TB' | 'RL' | 'BT';
+  clusters?: Cluster[];
+  /** Label edges with the number of references behind them */
+  weights?: boolean;
+}
+
+// Quotes are the only character a quoted label can't hold; mermaid decodes entity codes
+export function quoteMermaid(value: string): string {
+  return `"${value.replace(/"/g, '#quot;')}"`;
+}
+
+export function toMermaid(graph: ExportGraph, options: MermaidOptions = {}): string {
+  const lines: string[] = [`flowchart ${options.direction ?? 'LR'}`];
+  const ids = new Map(graph.nodes().sort().map((node, i) => [node, `n${i}`]));
+  const statement = (node: string) => {
+    const attrs = graph.getNodeAttributes(node);
+    // Third-party modules are drawn as stadiums, workspace packages as boxes
+    return attrs.external ? `${ids.get(node)}([${quoteMermaid(attrs.label)}])` : `${ids.get(node)}[${quoteMermaid(attrs.label)}]`;
+  };
+
+  if (options.clusters) {
+    for (const cluster of options.clusters) {
+      const members = cluster.members.filter(m => ids.has(m)).sort();
+      if (members.length < 2) {
+        members.forEach(m => lines.push(`  ${statement(m)}`));
+        continue;
+      }
+      lines.push(`  subgraph c${cluster.id}[${quoteMermaid(cluster.label)}]`);
+      members.forEach(m => lines.push(`    ${statement(m)}`));
+      lines.push('  end');
+    }
+  } else {
+    for (const node of ids.keys()) lines.push(`  ${statement(node)}`);
+  }
+
+  const edges = graph.mapEdges((_edge, attrs, source, target) => ({ source, target, ...attrs }))
+    .sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target));
+  for (const edge of edges) {
+    const arrow = edge.change === 'removed' ? '-.->' : '-->';
+    const label = options.weights ? `|${edge.weight}|` : '';
+    lines.push(`  ${ids.get(edge.source)} ${arrow}${label} ${ids.get(edge.target)}`);
+  }
+  return lines.join('\n') + '\n';
+}
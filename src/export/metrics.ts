@@ -0,0 +1,277 @@
+import { execFileSync } from 'child_process';
+import { readFileSync } from 'fs';
+import { join } from 'path';
+import type { DirectedGraph } from 'graphology';
+import { packageOf } from '../graph/model.js';
+import { scanSecurity } from '../security/scanner.js';
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+import { symbolPackage } from '../golang/binary.js';
+import { packageCoverage, percentCovered, readCoverProfiles } from '../golang/coverage.js';
+import { hotPaths, readCpuProfiles } from '../golang/pprof.js';
+import { attributeBuildTime, readActionGraphs } from '../golang/buildtime.js';
+import type { ExportGraph, ExportLevel } from './graph.js';
+
+export { symbolPackage };
+
+/**
+ * Numeric node metrics for heatmap coloring. The built-in metrics are
+ * computed per file and summed per package (instability is computed on the
+ * exported graph itself). binsize is the bytes each package contributes
+ * to a Go binary. coverage is the percentage of statements covered by
+ * go test -coverprofile profiles, set on edges too (the coverage of the
+ * package depended on); the heatmap runs the other way for it, hot where
+ * coverage is low. cpu is the share of a pprof CPU profile's time spent in
+ * each node's own code (cpuCum, with what it calls), set on edges too for
+ * the time of the calls along them. buildtime is each package's compile
+ * time in milliseconds from go build -debug-actiongraph output, with
+ * `rebuild` set beside it: the compile time a change to the package
+ * triggers. Any other name colors by an existing numeric node attribute
+ * such as `symbols`.
+ */
+
+export const BUILTIN_METRICS = ['loc', 'churn', 'vulns', 'instability', 'binsize', 'coverage', 'cpu', 'buildtime'] as const;
+export type BuiltinMetric = typeof BUILTIN_METRICS[number];
+
+export interface MetricContext {
+  /** The symbol graph the export graph was built from */
+  graph: DirectedGraph;
+  projectRoot: string;
+  level: ExportLevel;
+  /** git --since for churn; defaults to 90 days */
+  since?: string;
+  /** Go binary for binsize */
+  binary?: string;
+  /** go test -coverprofile files for coverage */
+  coverprofile?: string[];
+  /** pprof CPU profiles for cpu */
+  pprof?: string[];
+  /** go build -debug-actiongraph files for buildtime */
+  actiongraph?: string[];
+  signal?: AbortSignal;
+}
+
+export interface Heatmap {
+  attribute: string;
+  min: number;
+  max: number;
+  /** Fill color for a value; nodes without one are left uncolored */
+  color: (value: number) => string;
+}
+
+function filesOf(graph: DirectedGraph): string[] {
+  const files = new Set<string>();
+  graph.forEachNode((_node, attrs) => files.add(attrs.filePath));
+  return [...files];
+}
+
+function linesOfCode(projectRoot: string, files: string[]): Map<string, number> {
+  const loc = new Map<string, number>();
+  for (const file of files) {
+    try {
+      const text = readFileSync(join(projectRoot, file), 'utf-8');
+      loc.set(file, text.split('\n').filter(line => line.trim() !== '').length);
+    } catch {
+      // Deleted since parsing
+    }
+  }
+  return loc;
+}
+
+// Commits touching each file
+function churn(projectRoot: string, since: string): Map<string, number> {
+  const counts = new Map<string, number>();
+  let log = '';
+  try {
+    // --relative: paths relative to (and limited to) the project directory
+    log = execFileSync('git', ['log', `--since=${since}`, '--no-merges', '--relative', '--name-only', '--format='], {
+      cwd: projectRoot,
+      encoding: 'utf-8',
+      maxBuffer: 64 * 1024 * 1024,
+      stdio: ['ignore', 'pipe', 'ignore'],
+    });
+  } catch {
+    throw new Error('churn needs a git repository');
+  }
+  for (const line of log.split('\n')) {
+    const file = line.trim();
+    if (file) counts.set(file, (counts.get(file) ?? 0) + 1);
+  }
+  return counts;
+}
+
+async function vulnerabilities(context: MetricContext): Promise<Map<string, number>> {
+  const result = await scanSecurity(context.projectRoot, context.graph, { graphAware: true, signal: context.signal });
+  const counts = new Map<string, number>();
+  for (const finding of result.findings) {
+    counts.set(finding.file, (counts.get(finding.file) ?? 0) + 1);
+  }
+  return counts;
+}
+
+// Martin's instability: efferent / (afferent + efferent) couplings
+function instability(graph: ExportGraph): Map<string, number> {
+  const values = new Map<string, number>();
+  graph.forEachNode((node) => {
+    const ce = graph.outNeighbors(node).filter(n => n !== node).length;
+    const ca = graph.inNeighbors(node).filter(n => n !== node).length;
+    if (ca + ce > 0) values.set(node, ce / (ca + ce));
+  });
+  return values;
+}
+
+// Bytes of the symbols each package directory contributes to a Go binary
+function binarySize(context: MetricContext): Map<string, number> {
+  if (!context.binary) throw new Error('binsize needs a Go binary (--binary <file>)');
+  if (context.level === 'file') throw new Error('binsize is measured per package, not per file');
+  let output: string;
+  try {
+    output = execFileSync('go', ['tool', 'nm', '-size', context.binary], {
+      cwd: context.projectRoot,
+      encoding: 'utf-8',
+      maxBuffer: 256 * 1024 * 1024,
+      stdio: ['ignore', 'pipe', 'pipe'],
+    });
+  } catch (err) {
+    throw new Error(`go tool nm failed on ${context.binary}: ${err instanceof Error ? err.message : err}`);
+  }
+
+  const index = new GoModuleIndex(findGoModules(context.projectRoot));
+  const sizes = new Map<string, number>();
+  for (const line of output.split('\n')) {
+    // address size type name
+    const match = line.trim().match(/^\S+\s+(\d+)\s+\S\s+(.+)$/);
+    if (!match) continue;
+    const pkg = symbolPackage(match[2]);
+    // main packages are all named "main" in the symbol table, so only libraries are attributed
+    const dir = pkg ? index.dirForImport(pkg) : null;
+    if (dir !== null) sizes.set(dir, (sizes.get(dir) ?? 0) + parseInt(match[1], 10));
+  }
+  return sizes;
+}
+
+/** Set `attribute` on every node of the export graph that has a value for it */
+export async function applyMetric(exportGraph: ExportGraph, attribute: string, context: MetricContext): Promise<void> {
+  if (!(BUILTIN_METRICS as readonly string[]).includes(attribute)) {
+    const numeric = exportGraph.someNode((_node, attrs) => typeof attrs[attribute] === 'number');
+    if (!numeric) {
+      throw new Error(`Unknown metric "${attribute}" (built in: ${BUILTIN_METRICS.join(', ')}, or a numeric node attribute)`);
+    }
+    return;
+  }
+
+  if (attribute === 'instability') {
+    for (const [node, value] of instability(exportGraph)) exportGraph.setNodeAttribute(node, attribute, value);
+    return;
+  }
+
+  if (attribute === 'binsize') {
+    const sizes = binarySize(context);
+    exportGraph.forEachNode((node) => exportGraph.setNodeAttribute(node, attribute, sizes.get(node) ?? 0));
+    return;
+  }
+
+  if (attribute === 'coverage') {
+    if (!context.coverprofile?.length) throw new Error('coverage needs go test -coverprofile output (--coverprofile <file>)');
+    const coverage = readCoverProfiles(context.projectRoot, context.coverprofile);
+    const counts = context.level === 'file' ? coverage.files : packageCoverage(coverage);
+    // Nodes the profiles don't cover stay uncolored: no data is not 0%
+    exportGraph.forEachNode((node) => {
+      const count = counts.get(node);
+      if (count && count.statements > 0) exportGraph.setNodeAttribute(node, attribute, percentCovered(count));
+    });
+    exportGraph.forEachEdge((edge, _attrs, _source, target) => {
+      const value = exportGraph.getNodeAttribute(target, attribute);
+      if (typeof value === 'number') exportGraph.setEdgeAttribute(edge, attribute, value);
+    });
+    return;
+  }
+
+  if (attribute === 'cpu') {
+    if (!context.pprof?.length) throw new Error('cpu needs a pprof CPU profile (--pprof <file>)');
+    const hot = hotPaths(context.projectRoot, readCpuProfiles(context.pprof), context.level);
+    const share = (value: number) => hot.total > 0 ? 100 * value / hot.total : 0;
+    exportGraph.forEachNode((node) => {
+      const spot = hot.nodes.get(node);
+      exportGraph.setNodeAttribute(node, attribute, share(spot?.flat ?? 0));
+      exportGraph.setNodeAttribute(node, 'cpuCum', share(spot?.cum ?? 0));
+    });
+    // Calls without an import behind them (through an interface, say) have no edge to carry them
+    exportGraph.forEachEdge((edge, _attrs, source, target) => {
+      const time = hot.calls.get(`${source}\0${target}`);
+      if (time) exportGraph.setEdgeAttribute(edge, attribute, share(time));
+    });
+    return;
+  }
+
+  if (attribute === 'buildtime') {
+    if (!context.actiongraph?.length) throw new Error('buildtime needs go build -debug-actiongraph output (--actiongraph <file>)');
+    if (context.level !== 'package') throw new Error('buildtime is measured per package (use --level package)');
+    const report = attributeBuildTime(context.projectRoot, readActionGraphs(context.actiongraph));
+    const byDir = new Map(report.packages.filter(p => p.dir !== null && !p.cached).map(p => [p.dir!, p]));
+    exportGraph.forEachNode((node) => {
+      const pkg = byDir.get(node);
+      if (!pkg) return;
+      exportGraph.setNodeAttribute(node, attribute, pkg.compile);
+      exportGraph.setNodeAttribute(node, 'rebuild', pkg.rebuild);
+    });
+    return;
+  }
+
+  let perFile: Map<string, number>;
+  if (attribute === 'loc') perFile = linesOfCode(context.projectRoot, filesOf(context.graph));
+  else if (attribute === 'churn') perFile = churn(context.projectRoot, context.since ?? '90 days ago');
+  else perFile = await vulnerabilities(context);
+
+  const totals = new Map<string, number>();
+  for (const [file, value] of perFile) {
+    const node = context.level === 'file' ? file : packageOf(file);
+    if (exportGraph.hasNode(node)) totals.set(node, (totals.get(node) ?? 0) + value);
+  }
+  exportGraph.forEachNode((node) => {
+    // Zero is a meaningful reading for counts — a package with no findings
+    exportGraph.setNodeAttribute(node, attribute, totals.get(node) ?? 0);
+  });
+}
+
+// Cool-to-hot ramp, readable on both white and dark backgrounds
+const RAMP = ['#2c7bb6', '#abd9e9', '#ffffbf', '#fdae61', '#d7191c'];
+
+// Metrics where more is better, so the low end is the hot one
+const HIGHER_IS_BETTER = new Set(['coverage']);
+
+function interpolate(a: string, b: string, t: number): string {
+  const channel = (hex: string, i: number) => parseInt(hex.slice(1 + 2 * i, 3 + 2 * i), 16);
+  const mixed = [0, 1, 2].map(i => Math.round(channel(a, i) + (channel(b, i) - channel(a, i)) * t));
+  return `#${mixed.map(v => v.toString(16).padStart(2, '0')).join('')}`;
+}
+
+export function heatmap(graph: ExportGraph, attribute: string): Heatmap {
+  const values = graph.mapNodes((_node, attrs) => attrs[attribute]).filter((v): v is number => typeof v === 'number');
+  const min = values.length ? Math.min(...values) : 0;
+  const max = values.length ? Math.max(...values) : 0;
+  return {
+    attribute,
+    min,
+    max,
+    color: (value: number) => {
+      const position = max === min ? 0.5 : (value - min) / (max - min);
+      const t = HIGHER_IS_BETTER.has(attribute) ? 1 - position : position;
+      const scaled = Math.min(1, Math.max(0, t)) * (RAMP.length - 1);
+      const i = Math.min(RAMP.length - 2, Math.floor(scaled));
+      return interpolate(RAMP[i], RAMP[i + 1], scaled - i);
+    },
+  };
+}
+
+/** Evenly spaced legend stops, low to high */
+export function legendStops(map: Heatmap, count = 5): Array<{ value: number; color: string }> {
+  if (map.max === map.min) return [{ value: map.min, color: map.color(map.min) }];
+  return Array.from({ length: count }, (_, i) => {
+    const value = map.min + (map.max - map.min) * (i / (count - 1));
+    return { value, color: map.color(value) };
+  });
+}
+
+export function formatMetricValue(value: number): string {
+  return Number.isInteger(value) ? String(value) : value.toFixed(2);
+}
@@ -0,0 +1,192 @@
+import type { ExportGraph, Cluster } from './graph.js';
+import { layeredLayout, type Layout } from './layout.js';
+import { legendStops, formatMetricValue, type Heatmap } from './metrics.js';
+import { CHANGE_COLORS, diffSummary } from './diff.js';
+
+/**
+ * Standalone SVG for an export graph, laid out by layeredLayout(). Nodes and
+ * edges carry data-id / data-source / data-target so the HTML export can
+ * wire up hover highlighting without re-deriving the graph. Clustered nodes
+ * are kept together in each column and outlined in their cluster's color.
+ * A diff overlay draws added nodes and edges green, removed ones dashed red
+ * and changed nodes orange, with a key below the graph.
+ */
+
+export interface SvgOptions {
+  name?: string;
+  heatmap?: Heatmap;
+  clusters?: Cluster[];
+}
+
+export function escapeXml(value: string): string {
+  return value.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;');
+}
+
+// Dark text on light fills, light text on dark ones
+export function textColor(fill: string): string {
+  const [r, g, b] = [1, 3, 5].map(i => parseInt(fill.slice(i, i + 2), 16));
+  return 0.299 * r + 0.587 * g + 0.114 * b > 150 ? '#1a1a2e' : '#ffffff';
+}
+
+const DISTANCE_FILL = ['#4a9eff', '#a7cdfa', '#dceafc', '#f2f6fb'];
+const CLUSTER_COLORS = ['#4a9eff', '#7c3aed', '#ec4899', '#f59e0b', '#10b981', '#06b6d4', '#ef4444', '#84cc16', '#a855f7', '#64748b'];
+export const LEGEND_HEIGHT = 44;
+const CLUSTER_ROW = 18;
+const MAX_CLUSTER_ROWS = 12;
+const DIFF_LEGEND_HEIGHT = 28;
+
+export function clusterColor(cluster: number): string {
+  return CLUSTER_COLORS[cluster % CLUSTER_COLORS.length];
+}
+
+// A pale version of a color, for fills behind dark text
+export function tint(color: string): string {
+  const mixed = [1, 3, 5].map(i => Math.round(parseInt(color.slice(i, i + 2), 16) * 0.25 + 255 * 0.75));
+  return `#${mixed.map(v => v.toString(16).padStart(2, '0')).join('')}`;
+}
+
+function nodeFill(graph: ExportGraph, node: string, heat?: Heatmap): string {
+  const attrs = graph.getNodeAttributes(node);
+  if (heat) {
+    const value = attrs[heat.attribute];
+    return typeof value === 'number' ? heat.color(value) : '#ffffff';
+  }
+  if (attrs.distance !== undefined) return DISTANCE_FILL[Math.min(attrs.distance, DISTANCE_FILL.length - 1)];
+  if (attrs.change) return CHANGE_COLORS[attrs.change].fill;
+  if (attrs.cluster !== undefined) return tint(clusterColor(attrs.cluster));
+  return '#ffffff';
+}
+
+function edgePath(layout: Layout, source: string, target: string): string {
+  const s = layout.nodes.get(source)!;
+  const t = layout.nodes.get(target)!;
+  if (t.layer > s.layer) {
+    const x1 = s.x + s.width, y1 = s.y + s.height / 2;
+    const x2 = t.x, y2 = t.y + t.height / 2;
+    const bend = (x2 - x1) / 2;
+    return `M ${x1} ${y1} C ${x1 + bend} ${y1}, ${x2 - bend} ${y2}, ${x2} ${y2}`;
+  }
+  // Same column (a cycle) or backwards: loop out to the right of both boxes
+  const x1 = s.x + s.width, y1 = s.y + s.height / 2;
+  const x2 = t.x + t.width, y2 = t.y + t.height / 2;
+  const reach = Math.max(x1, x2) + 30 + Math.abs(y2 - y1) * 0.15;
+  return `M ${x1} ${y1} C ${reach} ${y1}, ${reach} ${y2}, ${x2} ${y2}`;
+}
+
+export function legend(heat: Heatmap, y: number): string {
+  const stops = legendStops(heat);
+  const width = 200;
+  const lines = [`<g class="legend" transform="translate(20, ${y})">`];
+  lines.push(`<text x="0" y="10" font-size="11" fill="#555">${escapeXml(heat.attribute)}</text>`);
+  lines.push('<defs><linearGradient id="legend-gradient" x1="0" x2="1" y1="0" y2="0">');
+  stops.forEach((stop, i) => {
+    lines.push(`<stop offset="${stops.length > 1 ? (i / (stops.length - 1)) * 100 : 0}%" stop-color="${stop.color}"/>`);
+  });
+  lines.push('</linearGradient></defs>');
+  lines.push(`<rect x="0" y="16" width="${width}" height="10" fill="url(#legend-gradient)" stroke="#c0c0cc"/>`);
+  lines.push(`<text x="0" y="40" font-size="10" fill="#555">${formatMetricValue(heat.min)}</text>`);
+  lines.push(`<text x="${width}" y="40" font-size="10" fill="#555" text-anchor="end">${formatMetricValue(heat.max)}</text>`);
+  lines.push('</g>');
+  return lines.join('\n');
+}
+
+function clusterLegend(clusters: Cluster[], y: number): string {
+  const shown = clusters.filter(c => c.members.length > 1).slice(0, MAX_CLUSTER_ROWS);
+  const lines = [`<g class="cluster-legend" transform="translate(20, ${y})">`];
+  shown.forEach((cluster, i) => {
+    lines.push(`<rect x="0" y="${i * CLUSTER_ROW}" width="12" height="12" rx="2" fill="${tint(clusterColor(cluster.id))}" stroke="${clusterColor(cluster.id)}" stroke-width="2"/>`);
+    lines.push(`<text x="18" y="${i * CLUSTER_ROW + 10}" font-size="11" fill="#333">${escapeXml(`${cluster.label} (${cluster.members.length})`)}</text>`);
+  });
+  lines.push('</g>');
+  return lines.join('\n');
+}
+
+function diffLegend(y: number): string {
+  const lines = [`<g class="diff-legend" transform="translate(20, ${y})">`];
+  let x = 0;
+  for (const change of ['added', 'removed', 'changed'] as const) {
+    const colors = CHANGE_COLORS[change];
+    lines.push(`<rect x="${x}" y="0" width="12" height="12" rx="2" fill="${colors.fill}" stroke="${colors.stroke}" stroke-width="2"${change === 'removed' ? ' stroke-dasharray="3 2"' : ''}/>`);
+    lines.push(`<text x="${x + 18}" y="10" font-size="11" fill="#333">${change}</text>`);
+    x += 80;
+  }
+  for (const change of ['added', 'removed'] as const) {
+    lines.push(`<line x1="${x}" y1="6" x2="${x + 24}" y2="6" stroke="${CHANGE_COLORS[change].stroke}" stroke-width="2"${change === 'removed' ? ' stroke-dasharray="6 4"' : ''}/>`);
+    lines.push(`<text x="${x + 30}" y="10" font-size="11" fill="#333">${change} edge</text>`);
+    x += 120;
+  }
+  lines.push('</g>');
+  return lines.join('\n');
+}
+
+function clusterLegendHeight(clusters?: Cluster[]): number {
+  if (!clusters) return 0;
+  const rows = Math.min(MAX_CLUSTER_ROWS, clusters.filter(c => c.members.length > 1).length);
+  return rows > 0 ? rows * CLUSTER_ROW + 12 : 0;
+}
+
+export function toSvg(graph: ExportGraph, options: SvgOptions = {}): string {
+  const layout = layeredLayout(graph, {
+    label: (node) => graph.getNodeAttribute(node, 'label') as string,
+    group: options.clusters ? (node) => graph.getNodeAttribute(node, 'cluster') ?? 0 : undefined,
+  });
+  const heat = options.heatmap;
+  const legendHeight = clusterLegendHeight(options.clusters);
+  const diff = diffSummary(graph) !== null;
+  const height = layout.height + legendHeight + (heat ? LEGEND_HEIGHT : 0) + (diff ? DIFF_LEGEND_HEIGHT : 0);
+  const out: string[] = [];
+
+  out.push(`<svg xmlns="http://www.w3.org/2000/svg" width="${layout.width}" height="${height}" viewBox="0 0 ${layout.width} ${height}" font-family="Helvetica, Arial, sans-serif">`);
+  if (options.name) out.push(`<title>${escapeXml(options.name)}</title>`);
+  const marker = (id: string, color: string) =>
+    `<marker id="${id}" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="${color}"/></marker>`;
+  out.push(`<defs>${marker('arrow', '#7a7a8c')}${diff ? marker('arrow-added', CHANGE_COLORS.added.stroke) + marker('arrow-removed', CHANGE_COLORS.removed.stroke) : ''}</defs>`);
+  out.push('<rect width="100%" height="100%" fill="#ffffff"/>');
+
+  out.push('<g class="edges">');
+  const edges = graph.mapEdges((_edge, attrs, source, target) => ({ source, target, ...attrs }))
+    .sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target));
+  for (const edge of edges) {
+    if (edge.source === edge.target) continue;
+    const width = Math.min(5, 1 + Math.log10(edge.weight)).toFixed(2);
+    const value = heat ? edge[heat.attribute] : undefined;
+    const metric = typeof value === 'number' ? `, ${heat!.attribute} ${formatMetricValue(value)}` : '';
+    const stroke = edge.change ? CHANGE_COLORS[edge.change].stroke : typeof value === 'number' ? heat!.color(value) : '#7a7a8c';
+    const dash = edge.change === 'removed' ? ' stroke-dasharray="6 4"' : '';
+    const title = `${edge.source} → ${edge.target}: ${edge.weight} references${metric}${edge.change ? ` (${edge.change})` : ''}`;
+    out.push(`<path class="edge${edge.change ? ` ${edge.change}` : ''}" data-source="${escapeXml(edge.source)}" data-target="${escapeXml(edge.target)}" d="${edgePath(layout, edge.source, edge.target)}" fill="none" stroke="${stroke}" stroke-opacity="${edge.change ? 0.9 : 0.7}" stroke-width="${width}"${dash} marker-end="url(#${edge.change ? `arrow-${edge.change}` : 'arrow'})"><title>${escapeXml(title)}</title></path>`);
+  }
+  out.push('</g>');
+
+  out.push('<g class="nodes">');
+  for (const node of graph.nodes().sort()) {
+    const box = layout.nodes.get(node)!;
+    const attrs = graph.getNodeAttributes(node);
+    const fill = nodeFill(graph, node, heat);
+    let tooltip = `${attrs.label}\n${attrs.files} files, ${attrs.symbols} symbols`;
+    if (heat && typeof attrs[heat.attribute] === 'number') {
+      tooltip += `\n${heat.attribute}: ${formatMetricValue(attrs[heat.attribute] as number)}`;
+    }
+    const cluster = attrs.cluster !== undefined ? options.clusters?.[attrs.cluster] : undefined;
+    if (cluster) tooltip += `\ncluster: ${cluster.label}`;
+    if (attrs.change === 'changed') tooltip += `\nchanged: +${attrs.symbolsAdded} −${attrs.symbolsRemoved} symbols`;
+    else if (attrs.change) tooltip += `\n${attrs.change}`;
+    out.push(`<g class="node${attrs.change ? ` ${attrs.change}` : ''}" data-id="${escapeXml(node)}">`);
+    out.push(`<title>${escapeXml(tooltip)}</title>`);
+    // A change outranks the cluster outline
+    const stroke = attrs.change ? CHANGE_COLORS[attrs.change].stroke
+      : options.clusters && attrs.cluster !== undefined ? clusterColor(attrs.cluster) : '#4a4a5a';
+    const strokeWidth = attrs.distance === 0 ? 3 : attrs.change || options.clusters ? 2 : 1;
+    const dash = attrs.change === 'removed' ? ' stroke-dasharray="4 3"' : '';
+    out.push(`<rect x="${box.x}" y="${box.y}" width="${box.width}" height="${box.height}" rx="5" fill="${fill}" stroke="${stroke}" stroke-width="${strokeWidth}"${dash}/>`);
+    out.push(`<text x="${box.x + box.width / 2}" y="${box.y + box.height / 2 + 4}" font-size="12" text-anchor="middle" fill="${textColor(fill)}">${escapeXml(attrs.label)}</text>`);
+    out.push('</g>');
+  }
+  out.push('</g>');
+
+  if (options.clusters && legendHeight > 0) out.push(clusterLegend(options.clusters, layout.height));
+  if (heat) out.push(legend(heat, layout.height + legendHeight));
+  if (diff) out.push(diffLegend(layout.height + legendHeight + (heat ? LEGEND_HEIGHT : 0)));
+  out.push('</svg>');
+  return out.join('\n') + '\n';
+}
@@ -0,0 +1,113 @@
+import type { ExportGraph } from './graph.js';
+import { fitLine, type Terminal } from '../utils/terminal.js';
+
+/**
+ * Terminal renderings of an export graph: an indented dependency tree,
+ * and a compact adjacency matrix for graphs small enough to fit across the
+ * screen. Both fit lines to the terminal width and fall back to ASCII.
+ */
+
+export interface TreeOptions {
+  roots: string[];
+  /** out: what each node depends on; in: what depends on it */
+  direction?: 'out' | 'in';
+  /** Levels below each root; unlimited by default */
+  depth?: number;
+}
+
+const UNICODE_TREE = { branch: '├── ', last: '└── ', pipe: '│   ', space: '    ', cycle: '↻' };
+const ASCII_TREE = { branch: '|-- ', last: '`-- ', pipe: '|   ', space: '    ', cycle: '<-' };
+
+function neighbors(graph: ExportGraph, node: string, direction: 'out' | 'in'): Array<{ node: string; weight: number }> {
+  const entries = direction === 'out'
+    ? graph.mapOutEdges(node, (_edge, attrs, _source, target) => ({ node: target, weight: attrs.weight }))
+    : graph.mapInEdges(node, (_edge, attrs, source) => ({ node: source, weight: attrs.weight }));
+  return entries
+    .filter(entry => entry.node !== node)
+    .sort((a, b) => graph.getNodeAttribute(a.node, 'label').localeCompare(graph.getNodeAttribute(b.node, 'label')));
+}
+
+/**
+ * Each dependency is expanded once; later occurrences are marked instead of
+ * repeated, so the output stays proportional to the number of edges.
+ */
+export function renderTree(graph: ExportGraph, terminal: Terminal, options: TreeOptions): string {
+  const { color } = terminal;
+  const chars = terminal.unicode ? UNICODE_TREE : ASCII_TREE;
+  const direction = options.direction ?? 'out';
+  const maxDepth = options.depth ?? Infinity;
+  const expanded = new Set<string>();
+  const lines: string[] = [];
+  const label = (node: string) => graph.getNodeAttribute(node, 'label');
+
+  const visit = (node: string, prefix: string, ancestors: Set<string>, depth: number) => {
+    const children = neighbors(graph, node, direction);
+    if (depth >= maxDepth) {
+      if (children.length > 0) lines.push(fitLine(`${prefix}${chars.last}${color.dim(`… ${children.length} more`)}`, terminal.width, terminal.unicode));
+      return;
+    }
+    children.forEach((child, i) => {
+      const last = i === children.length - 1;
+      let line = `${prefix}${last ? chars.last : chars.branch}${label(child.node)} ${color.dim(String(child.weight))}`;
+      const cycle = ancestors.has(child.node);
+      const seen = !cycle && expanded.has(child.node) && neighbors(graph, child.node, direction).length > 0;
+      if (cycle) line += ` ${color.yellow(`${chars.cycle} cycle`)}`;
+      else if (seen) line += ` ${color.dim('(see above)')}`;
+      lines.push(fitLine(line, terminal.width, terminal.unicode));
+      if (cycle || seen) return;
+
+      expanded.add(child.node);
+      ancestors.add(child.node);
+      visit(child.node, prefix + (last ? chars.space : chars.pipe), ancestors, depth + 1);
+      ancestors.delete(child.node);
+    });
+  };
+
+  for (const root of options.roots) {
+    lines.push(fitLine(color.bold(label(root)), terminal.width, terminal.unicode));
+    expanded.add(root);
+    visit(root, '', new Set([root]), 0);
+  }
+  return lines.join('\n');
+}
+
+/** The widest graph renderMatrix() can draw in a terminal this wide */
+export function matrixFits(graph: ExportGraph, terminal: Terminal): boolean {
+  const labelWidth = Math.min(40, Math.max(...graph.mapNodes((_node, attrs) => attrs.label.length)));
+  return graph.order > 0 && String(graph.order).length + labelWidth + 3 + graph.order <= terminal.width;
+}
+
+/**
+ * Rows depend on columns. Cells where the two nodes depend on each other
+ * (a two-node cycle) are highlighted.
+ */
+export function renderMatrix(graph: ExportGraph, terminal: Terminal): string {
+  const { color } = terminal;
+  const nodes = graph.nodes().sort((a, b) => graph.getNodeAttribute(a, 'label').localeCompare(graph.getNodeAttribute(b, 'label')));
+  const digits = String(nodes.length).length;
+  const labels = nodes.map(node => graph.getNodeAttribute(node, 'label'));
+  const labelWidth = Math.min(40, Math.max(...labels.map(l => l.length)));
+  const fitted = labels.map(l => (l.length <= labelWidth ? l.padEnd(labelWidth) : `${terminal.unicode ? '…' : '~'}${l.slice(l.length - labelWidth + 1)}`));
+  const gutter = ' '.repeat(digits + labelWidth + 3);
+  const [yes, no, self] = terminal.unicode ? ['■', '·', '╲'] : ['#', '.', '\\'];
+  const lines: string[] = [];
+
+  // Column numbers, one digit per row, most significant first
+  for (let place = digits - 1; place >= 0; place--) {
+    const row = nodes.map((_, i) => {
+      const number = String(i + 1).padStart(digits, ' ');
+      return number[digits - 1 - place];
+    }).join('');
+    lines.push(color.dim(`${gutter}${row}`));
+  }
+
+  nodes.forEach((source, i) => {
+    const cells = nodes.map((target, j) => {
+      if (i === j) return color.dim(self);
+      if (!graph.hasEdge(source, target)) return color.dim(no);
+      return graph.hasEdge(target, source) ? color.red(yes) : color.cyan(yes);
+    }).join('');
+    lines.push(`${color.dim(String(i + 1).padStart(digits))} ${fitted[i]}  ${cells}`);
+  });
+  return lines.join('\n');
+}
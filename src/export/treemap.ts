@@ -0,0 +1,340 @@
+import type { ExportGraph } from './graph.js';
+import { formatMetricValue, type Heatmap } from './metrics.js';
+import { escapeXml, textColor, clusterColor, tint, legend, LEGEND_HEIGHT } from './svg.js';
+
+/**
+ * A squarified treemap of the export graph: one rectangle per package (or
+ * file), nested by directory, with area from a size metric (loc, binsize,
+ * symbols…) and fill from a heatmap or the top-level directory. Tooltips
+ * list each package's heaviest dependencies and dependents; the HTML version
+ * highlights them on hover. Made for hunting bloat.
+ */
+
+export interface TreemapOptions {
+  name?: string;
+  /** Numeric node attribute for area */
+  size: string;
+  heatmap?: Heatmap;
+  width?: number;
+  height?: number;
+}
+
+interface Rect {
+  x: number;
+  y: number;
+  width: number;
+  height: number;
+}
+
+interface TreeNode {
+  name: string;
+  /** The graph node this rectangle stands for, on leaves */
+  node?: string;
+  value: number;
+  children: TreeNode[];
+}
+
+interface Cell {
+  tree: TreeNode;
+  rect: Rect;
+  depth: number;
+  /** Index of the top-level directory, for default coloring */
+  top: number;
+}
+
+const HEADER = 16;
+const PADDING = 3;
+const TOP_NEIGHBORS = 5;
+
+function buildTree(graph: ExportGraph, size: string): TreeNode {
+  const root: TreeNode = { name: '', value: 0, children: [] };
+  const dirs = new Map<string, TreeNode>([['', root]]);
+  const own = new Map<string, number>();
+
+  graph.forEachNode((node, attrs) => {
+    const value = attrs[size];
+    if (typeof value !== 'number' || value <= 0) return;
+    own.set(node, value);
+    let parent = root;
+    let path = '';
+    for (const segment of node.split('/')) {
+      path = path ? `${path}/${segment}` : segment;
+      let child = dirs.get(path);
+      if (!child) {
+        child = { name: segment, value: 0, children: [] };
+        dirs.set(path, child);
+        parent.children.push(child);
+      }
+      parent = child;
+    }
+  });
+
+  // A package with subpackages gets a leaf of its own beside them
+  for (const [node, value] of own) {
+    const dir = dirs.get(node)!;
+    if (dir.children.length === 0) {
+      dir.node = node;
+      dir.value = value;
+    } else {
+      dir.children.push({ name: '(package)', node, value, children: [] });
+    }
+  }
+
+  const total = (tree: TreeNode): number => {
+    if (tree.children.length > 0) tree.value = tree.children.reduce((sum, child) => sum + total(child), 0);
+    return tree.value;
+  };
+  total(root);
+
+  // "internal" → "store" with nothing else under internal reads better as one "internal/store"
+  const compress = (tree: TreeNode): TreeNode => {
+    while (tree.children.length === 1 && tree.children[0].children.length > 0) {
+      const only = tree.children[0];
+      tree = { ...only, name: tree.name ? `${tree.name}/${only.name}` : only.name };
+    }
+    if (tree.children.length === 1 && !tree.node) {
+      const only = tree.children[0];
+      return { ...only, name: tree.name ? `${tree.name}/${only.name}` : only.name };
+    }
+    tree.children = tree.children.map(compress);
+    return tree;
+  };
+  root.children = root.children.map(compress);
+  return root;
+}
+
+function worst(areas: number[], side: number): number {
+  const sum = areas.reduce((a, b) => a + b, 0);
+  const max = Math.max(...areas);
+  const min = Math.min(...areas);
+  return Math.max((side * side * max) / (sum * sum), (sum * sum) / (side * side * min));
+}
+
+// Lay one row along the shorter side of rect; returns what's left of it
+function layoutRow(row: Array<{ item: TreeNode; area: number }>, rect: Rect, out: Array<{ item: TreeNode; rect: Rect }>): Rect {
+  const sum = row.reduce((total, entry) => total + entry.area, 0);
+  if (rect.width >= rect.height) {
+    const width = sum / rect.height;
+    let y = rect.y;
+    for (const entry of row) {
+      const height = entry.area / width;
+      out.push({ item: entry.item, rect: { x: rect.x, y, width, height } });
+      y += height;
+    }
+    return { x: rect.x + width, y: rect.y, width: rect.width - width, height: rect.height };
+  }
+  const height = sum / rect.width;
+  let x = rect.x;
+  for (const entry of row) {
+    const width = entry.area / height;
+    out.push({ item: entry.item, rect: { x, y: rect.y, width, height } });
+    x += width;
+  }
+  return { x: rect.x, y: rect.y + height, width: rect.width, height: rect.height - height };
+}
+
+/** Bruls et al.'s squarified layout: rows of rectangles kept close to square */
+function squarify(items: TreeNode[], rect: Rect): Array<{ item: TreeNode; rect: Rect }> {
+  const total = items.reduce((sum, item) => sum + item.value, 0);
+  const out: Array<{ item: TreeNode; rect: Rect }> = [];
+  if (total <= 0 || rect.width <= 0 || rect.height <= 0) return out;
+
+  const scale = (rect.width * rect.height) / total;
+  const entries = [...items].sort((a, b) => b.value - a.value).map(item => ({ item, area: item.value * scale }));
+  let remaining = rect;
+  let row: typeof entries = [];
+  for (let i = 0; i < entries.length;) {
+    const side = Math.min(remaining.width, remaining.height);
+    const candidate = [...row, entries[i]];
+    if (row.length === 0 || worst(candidate.map(e => e.area), side) <= worst(row.map(e => e.area), side)) {
+      row = candidate;
+      i++;
+    } else {
+      remaining = layoutRow(row, remaining, out);
+      row = [];
+    }
+  }
+  if (row.length > 0) layoutRow(row, remaining, out);
+  return out;
+}
+
+function place(tree: TreeNode, rect: Rect, depth: number, top: number, cells: Cell[]): void {
+  cells.push({ tree, rect, depth, top });
+  if (tree.children.length === 0) return;
+  const header = rect.height > 2 * HEADER && rect.width > 60 ? HEADER : PADDING;
+  const inner = {
+    x: rect.x + PADDING,
+    y: rect.y + header,
+    width: rect.width - 2 * PADDING,
+    height: rect.height - header - PADDING,
+  };
+  for (const placed of squarify(tree.children, inner)) {
+    place(placed.item, placed.rect, depth + 1, top, cells);
+  }
+}
+
+function layoutTreemap(graph: ExportGraph, size: string, width: number, height: number): Cell[] {
+  const root = buildTree(graph, size);
+  const cells: Cell[] = [];
+  squarify(root.children, { x: 0, y: 0, width, height }).forEach((placed, i) => place(placed.item, placed.rect, 0, i, cells));
+  return cells;
+}
+
+/** Heaviest neighbors first, as "label (weight)" */
+export function topNeighbors(graph: ExportGraph, node: string, direction: 'out' | 'in', limit = TOP_NEIGHBORS): string[] {
+  const entries = direction === 'out'
+    ? graph.mapOutEdges(node, (_edge, attrs, _source, target) => ({ node: target, weight: attrs.weight }))
+    : graph.mapInEdges(node, (_edge, attrs, source) => ({ node: source, weight: attrs.weight }));
+  return entries
+    .filter(entry => entry.node !== node)
+    .sort((a, b) => b.weight - a.weight || a.node.localeCompare(b.node))
+    .slice(0, limit)
+    .map(entry => `${graph.getNodeAttribute(entry.node, 'label')} (${entry.weight})`);
+}
+
+export function formatSize(attribute: string, value: number): string {
+  if (attribute === 'binsize') {
+    return value >= 1024 * 1024 ? `${(value / 1024 / 1024).toFixed(1)} MiB` : `${(value / 1024).toFixed(1)} KiB`;
+  }
+  return `${formatMetricValue(value)} ${attribute}`;
+}
+
+// Roughly how many 11px characters fit across a width
+function fit(text: string, width: number): string | null {
+  const chars = Math.floor((width - 8) / 6.5);
+  if (chars < 3) return null;
+  return text.length <= chars ? text : `${text.slice(0, chars - 1)}…`;
+}
+
+export function toTreemapSvg(graph: ExportGraph, options: TreemapOptions): string {
+  const width = options.width ?? 1200;
+  const height = options.height ?? 800;
+  const heat = options.heatmap;
+  const cells = layoutTreemap(graph, options.size, width, height);
+  const total = heat ? height + LEGEND_HEIGHT : height;
+  const out: string[] = [];
+
+  out.push(`<svg xmlns="http://www.w3.org/2000/svg" width="${width}" height="${total}" viewBox="0 0 ${width} ${total}" font-family="Helvetica, Arial, sans-serif">`);
+  if (options.name) out.push(`<title>${escapeXml(options.name)}</title>`);
+  out.push('<rect width="100%" height="100%" fill="#ffffff"/>');
+
+  for (const cell of cells) {
+    const { x, y, width: w, height: h } = cell.rect;
+    const box = `x="${x.toFixed(1)}" y="${y.toFixed(1)}" width="${Math.max(0, w).toFixed(1)}" height="${Math.max(0, h).toFixed(1)}"`;
+    const node = cell.tree.node;
+    if (!node) {
+      out.push(`<g class="group"><title>${escapeXml(`${cell.tree.name}\n${formatSize(options.size, cell.tree.value)}`)}</title>`);
+      out.push(`<rect ${box} fill="#f4f4f8" stroke="#b0b0c0" stroke-width="1"/>`);
+      const label = h > 2 * HEADER && w > 60 ? fit(cell.tree.name, w) : null;
+      if (label) out.push(`<text x="${(x + 4).toFixed(1)}" y="${(y + 12).toFixed(1)}" font-size="11" font-weight="bold" fill="#333">${escapeXml(label)}</text>`);
+      out.push('</g>');
+      continue;
+    }
+
+    const attrs = graph.getNodeAttributes(node);
+    const value = heat ? attrs[heat.attribute] : undefined;
+    const fill = heat ? (typeof value === 'number' ? heat.color(value) : '#ffffff') : tint(clusterColor(cell.top));
+    let tooltip = `${attrs.label}\n${formatSize(options.size, cell.tree.value)}`;
+    if (heat && typeof value === 'number') tooltip += `\n${heat.attribute}: ${formatMetricValue(value)}`;
+    const deps = topNeighbors(graph, node, 'out');
+    const dependents = topNeighbors(graph, node, 'in');
+    if (deps.length) tooltip += `\ndepends on: ${deps.join(', ')}`;
+    if (dependents.length) tooltip += `\nused by: ${dependents.join(', ')}`;
+
+    out.push(`<g class="leaf" data-id="${escapeXml(node)}"><title>${escapeXml(tooltip)}</title>`);
+    out.push(`<rect ${box} fill="${fill}" stroke="#ffffff" stroke-width="1.5"/>`);
+    const label = h > 16 ? fit(cell.tree.name === '(package)' ? attrs.label.split('/').pop()! : cell.tree.name, w) : null;
+    if (label) out.push(`<text x="${(x + 4).toFixed(1)}" y="${(y + 13).toFixed(1)}" font-size="11" fill="${textColor(fill)}">${escapeXml(label)}</text>`);
+    out.push('</g>');
+  }
+
+  if (heat) out.push(legend(heat, height));
+  out.push('</svg>');
+  return out.join('\n') + '\n';
+}
+
+// Embedded in a <script>, so "</script>" inside a label can't end it early
+function scriptJson(value: unknown): string {
+  return JSON.stringify(value).replace(/</g, '\\u003c');
+}
+
+export function toTreemapHtml(graph: ExportGraph, options: TreemapOptions): string {
+  const title = options.name ? `${options.name} — Depwire treemap` : 'Depwire treemap';
+  const stats = `${graph.order} nodes, area by ${options.size}${options.heatmap ? `, colored by ${options.heatmap.attribute}` : ''}`;
+  const details: Record<string, { label: string; size: string; out: string[]; in: string[]; deps: string[]; dependents: string[] }> = {};
+  graph.forEachNode((node, attrs) => {
+    const value = attrs[options.size];
+    details[node] = {
+      label: attrs.label,
+      size: typeof value === 'number' ? formatSize(options.size, value) : '',
+      out: graph.outNeighbors(node).filter(n => n !== node),
+      in: graph.inNeighbors(node).filter(n => n !== node),
+      deps: topNeighbors(graph, node, 'out'),
+      dependents: topNeighbors(graph, node, 'in'),
+    };
+  });
+
+  return `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>${escapeXml(title)}</title>
+  <style>
+    body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif; color: #1a1a2e; display: flex; flex-direction: column; height: 100vh; }
+    header { padding: 12px 20px; border-bottom: 1px solid #e0e0ea; }
+    header h1 { font-size: 16px; margin: 0 0 4px; }
+    header p { font-size: 12px; color: #666; margin: 0; }
+    .body { flex: 1; display: flex; overflow: hidden; }
+    main { flex: 1; overflow: auto; }
+    aside { width: 300px; border-left: 1px solid #e0e0ea; padding: 12px 16px; font-size: 12px; overflow-y: auto; }
+    aside h2 { font-size: 13px; margin: 0 0 4px; word-break: break-all; }
+    aside h3 { font-size: 12px; margin: 12px 0 4px; color: #666; }
+    aside ol { margin: 0; padding-left: 18px; }
+    .leaf rect { transition: opacity 0.15s; }
+    svg.hovering .leaf rect { opacity: 0.35; }
+    svg.hovering .leaf.active rect { opacity: 1; stroke: #1a1a2e; stroke-width: 2; }
+    svg.hovering .leaf.dep rect { opacity: 1; stroke: #2563eb; stroke-width: 2; }
+    svg.hovering .leaf.dependent rect { opacity: 1; stroke: #ea580c; stroke-width: 2; }
+  </style>
+</head>
+<body>
+  <header>
+    <h1>${escapeXml(title)}</h1>
+    <p>${escapeXml(stats)}. Hover a package: dependencies are outlined blue, dependents orange.</p>
+  </header>
+  <div class="body">
+    <main>
+${toTreemapSvg(graph, options)}
+    </main>
+    <aside id="details"><p>Hover a package to see its heaviest dependencies and dependents.</p></aside>
+  </div>
+  <script>
+    const details = ${scriptJson(details)};
+    const svg = document.querySelector('main svg');
+    const panel = document.getElementById('details');
+    const leaves = [...svg.querySelectorAll('.leaf')];
+    const escape = (text) => text.replace(/[&<>"]/g, (c) => ({ '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;' })[c]);
+    const list = (items) => items.length ? '<ol>' + items.map(item => '<li>' + escape(item) + '</li>').join('') + '</ol>' : '<p>none</p>';
+    leaves.forEach((leaf) => {
+      leaf.addEventListener('mouseenter', () => {
+        const info = details[leaf.dataset.id];
+        const out = new Set(info.out);
+        const incoming = new Set(info.in);
+        leaves.forEach((other) => {
+          other.classList.toggle('active', other === leaf);
+          other.classList.toggle('dep', out.has(other.dataset.id));
+          other.classList.toggle('dependent', incoming.has(other.dataset.id));
+        });
+        svg.classList.add('hovering');
+        panel.innerHTML = '<h2>' + escape(info.label) + '</h2><p>' + escape(info.size) + '</p>' +
+          '<h3>Depends on (' + info.out.length + ')</h3>' + list(info.deps) +
+          '<h3>Used by (' + info.in.length + ')</h3>' + list(info.dependents);
+      });
+      leaf.addEventListener('mouseleave', () => svg.classList.remove('hovering'));
+    });
+  </script>
+</body>
+</html>
+`;
+}
@@ -0,0 +1,142 @@
+import type { Node } from 'web-tree-sitter';
+import { goDeclarations } from './source.js';
+import type { GoProject } from './packages.js';
+
+/**
+ * The exported API of Go packages as comparable items: one item per exported
+ * func, type, method, const and var, plus exported struct fields and
+ * interface methods. Signatures keep only what callers depend on — parameter
+ * names and formatting are dropped so they don't register as changes.
+ */
+
+export type GoApiItemKind = 'func' | 'method' | 'type' | 'interface' | 'const' | 'var' | 'field' | 'interface-method';
+
+export interface GoApiItem {
+  /** Func, Type, Type.Method, Type.Field */
+  name: string;
+  kind: GoApiItemKind;
+  signature: string;
+  /** Enclosing type for methods, fields and interface methods */
+  parent?: string;
+  file: string;
+  line: number;
+}
+
+export interface GoPackageApi {
+  importPath: string;
+  dir: string;
+  name: string;
+  items: Map<string, GoApiItem>;
+}
+
+const isExported = (name: string) => /^\p{Lu}/u.test(name);
+const squash = (text: string) => text.replace(/\s+/g, ' ').trim();
+
+/** API of every non-main package in the given module (or all modules) */
+export function extractGoApi(project: GoProject, modulePath?: string): Map<string, GoPackageApi> {
+  const apis = new Map<string, GoPackageApi>();
+
+  for (const pkg of project.packages.values()) {
+    if (!pkg.importPath || !pkg.name || pkg.name === 'main') continue;
+    if (modulePath && project.modules.moduleForImport(pkg.importPath)?.path !== modulePath) continue;
+    if (pkg.importPath.split('/').includes('internal')) continue;
+
+    const items = new Map<string, GoApiItem>();
+    const add = (item: GoApiItem) => items.set(item.name, item);
+
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      for (const decl of goDeclarations(file)) {
+        if (!decl.exported) continue;
+        const at = { file: file.file, line: decl.line };
+        switch (decl.kind) {
+          case 'func':
+            add({ name: decl.name, kind: 'func', signature: funcSignature(decl.node), ...at });
+            break;
+          case 'method':
+            if (!decl.receiver || !isExported(decl.receiver)) break;
+            add({ name: `${decl.receiver}.${decl.name}`, kind: 'method', signature: funcSignature(decl.node), parent: decl.receiver, ...at });
+            break;
+          case 'type':
+          case 'interface':
+            for (const item of typeItems(decl.name, decl.node, at)) add(item);
+            break;
+          case 'const':
+          case 'var': {
+            const type = decl.node.childForFieldName('type');
+            add({ name: decl.name, kind: decl.kind, signature: type ? squash(type.text) : '', ...at });
+            break;
+          }
+        }
+      }
+    }
+
+    apis.set(pkg.importPath, { importPath: pkg.importPath, dir: pkg.dir, name: pkg.name, items });
+  }
+
+  return apis;
+}
+
+/** Type parameters, parameter types and result types of a func or method */
+export function funcSignature(node: Node): string {
+  const typeParams = node.childForFieldName('type_parameters');
+  const params = parameterTypes(node.childForFieldName('parameters'));
+  const result = node.childForFieldName('result');
+  const results = result?.type === 'parameter_list' ? parameterTypes(result) : result ? squash(result.text) : '';
+  const generic = typeParams ? squash(typeParams.text) : '';
+  return `${generic}(${params})${results ? ` ${result?.type === 'parameter_list' ? `(${results})` : results}` : ''}`;
+}
+
+function parameterTypes(list: Node | null): string {
+  if (!list) return '';
+  const types: string[] = [];
+  for (const param of list.namedChildren) {
+    if (!param) continue;
+    const type = param.childForFieldName('type');
+    if (!type) continue;
+    const text = (param.type === 'variadic_parameter_declaration' ? '...' : '') + squash(type.text);
+    const names = param.childrenForFieldName('name').filter(Boolean).length;
+    for (let i = 0; i < Math.max(1, names); i++) types.push(text);
+  }
+  return types.join(', ');
+}
+
+function typeItems(name: string, spec: Node, at: { file: string; line: number }): GoApiItem[] {
+  const type = spec.childForFieldName('type');
+  const typeParams = spec.childForFieldName('type_parameters');
+  const generic = typeParams ? squash(typeParams.text) : '';
+  const alias = spec.type === 'type_alias' ? '= ' : '';
+  const items: GoApiItem[] = [];
+
+  if (type?.type === 'struct_type') {
+    items.push({ name, kind: 'type', signature: `${alias}${generic}struct`, ...at });
+    const fields = type.namedChildren.find(n => n?.type === 'field_declaration_list');
+    for (const field of fields?.namedChildren ?? []) {
+      if (field?.type !== 'field_declaration') continue;
+      const fieldType = field.childForFieldName('type');
+      const names = field.childrenForFieldName('name').filter((n): n is Node => Boolean(n)).map(n => n.text);
+      // Embedded fields are named after their type
+      if (names.length === 0 && fieldType) names.push(fieldType.text.replace(/^\*/, '').split('.').pop()!.replace(/\[.*$/, ''));
+      for (const fieldName of names) {
+        if (!isExported(fieldName)) continue;
+        items.push({ name: `${name}.${fieldName}`, kind: 'field', signature: squash(fieldType?.text ?? ''), parent: name, ...at, line: field.startPosition.row + 1 });
+      }
+    }
+  } else if (type?.type === 'interface_type') {
+    items.push({ name, kind: 'interface', signature: `${alias}${generic}interface`, ...at });
+    for (const elem of type.namedChildren) {
+      if (!elem) continue;
+      if (elem.type === 'method_elem' || elem.type === 'method_spec') {
+        const method = elem.childForFieldName('name')?.text;
+        if (!method) continue;
+        // Unexported methods matter too: they make the interface unimplementable outside the package
+        items.push({ name: `${name}.${method}`, kind: 'interface-method', signature: funcSignature(elem), parent: name, ...at, line: elem.startPosition.row + 1 });
+      } else {
+        items.push({ name: `${name}.{${squash(elem.text)}}`, kind: 'interface-method', signature: 'embedded', parent: name, ...at, line: elem.startPosition.row + 1 });
+      }
+    }
+  } else {
+    items.push({ name, kind: 'type', signature: `${alias}${generic}${squash(type?.text ?? '')}`, ...at });
+  }
+  return items;
+}
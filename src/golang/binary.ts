@@ -0,0 +1,313 @@
+import { existsSync, mkdirSync, readFileSync } from 'fs';
+import { dirname, join, resolve } from 'path';
+import { writeFileAtomic } from '../utils/files.js';
+import { runGo } from './toolchain.js';
+
+/**
+ * What a built Go binary was made of, read from the binary alone: the
+ * module list the linker embeds (what `go version -m` and debug/buildinfo
+ * read) gives the dependency set with versions, sums and replacements, and
+ * the symbol table gives the bytes each package, and so each module, adds.
+ * Sizes count code and data in the file (text, rodata, data), not bss.
+ * Binaries built with -ldflags=-s have no symbol table; their modules are
+ * still listed, without sizes (-w, which drops only DWARF, keeps them).
+ * Two builds compare module by module, and recorded builds make a trend.
+ */
+
+export interface BinaryModuleVersion {
+  path: string;
+  version: string;
+  /** go.sum hash; none for the main module and local replacements */
+  sum: string | null;
+}
+
+export interface BinaryModule extends BinaryModuleVersion {
+  /** The module it was replaced with, when go.mod had a replace directive */
+  replace: BinaryModuleVersion | null;
+  main: boolean;
+  /** Bytes of the module's symbols (0 without a symbol table) */
+  size: number;
+  /** Packages of the module with symbols in the binary */
+  packages: number;
+}
+
+export interface BinaryPackage {
+  path: string;
+  /** The module providing it; null for the standard library */
+  module: string | null;
+  size: number;
+}
+
+export interface BuildInfo {
+  goVersion: string;
+  /** Import path of the main package */
+  path: string;
+  main: BinaryModule;
+  deps: BinaryModule[];
+  /** build lines: -compiler, GOOS, CGO_ENABLED, vcs.revision, ... */
+  settings: Record<string, string>;
+}
+
+export interface BinaryReport {
+  binary: string;
+  goVersion: string;
+  path: string;
+  settings: Record<string, string>;
+  /** The main module first, then dependencies by size */
+  modules: BinaryModule[];
+  std: { size: number; packages: number };
+  /** Bytes of symbols no package owns: linker generated data, cgo and assembly without a package prefix */
+  unattributed: number;
+  /** Bytes of all sized symbols */
+  total: number;
+  /** Packages by size */
+  packages: BinaryPackage[];
+  stripped: boolean;
+  warnings: string[];
+}
+
+export interface BinaryOptions {
+  signal?: AbortSignal;
+}
+
+/** Parse `go version -m` output */
+export function parseBuildInfo(output: string): BuildInfo {
+  const lines = output.split('\n');
+  const header = /:\s+(go\S+)\s*$/.exec(lines[0] ?? '');
+  if (!header) throw new Error('Not a Go binary, or one built without module information');
+  const info: BuildInfo = {
+    goVersion: header[1],
+    path: '',
+    main: { path: '', version: '', sum: null, replace: null, main: true, size: 0, packages: 0 },
+    deps: [],
+    settings: {},
+  };
+  const module = (fields: string[]): BinaryModuleVersion => ({ path: fields[1], version: fields[2] ?? '', sum: fields[3] || null });
+  for (const line of lines.slice(1)) {
+    const fields = line.trim().split('\t');
+    switch (fields[0]) {
+      case 'path': info.path = fields[1]; break;
+      case 'mod': info.main = { ...info.main, ...module(fields) }; break;
+      case 'dep': info.deps.push({ ...module(fields), replace: null, main: false, size: 0, packages: 0 }); break;
+      // A replacement follows the line of the module it replaces
+      case '=>': {
+        const last = info.deps[info.deps.length - 1] ?? info.main;
+        last.replace = module(fields);
+        break;
+      }
+      case 'build': {
+        const eq = fields[1]?.indexOf('=') ?? -1;
+        if (eq > 0) info.settings[fields[1].slice(0, eq)] = fields[1].slice(eq + 1);
+        break;
+      }
+    }
+  }
+  return info;
+}
+
+/**
+ * The package of a symbol in `go tool nm` output:
+ * "github.com/org/x/store.(*DB).Get" and "type:*github.com/org/x/store.DB"
+ * both belong to github.com/org/x/store.
+ */
+export function symbolPackage(name: string): string | null {
+  const symbol = name.replace(/^type:\*?/, '');
+  if (symbol.startsWith('go:') || symbol.startsWith('runtime.')) return null;
+  // Receivers and type arguments can contain paths of their own
+  const cut = symbol.search(/[([]/);
+  const head = cut === -1 ? symbol : symbol.slice(0, cut);
+  const dot = head.indexOf('.', head.lastIndexOf('/') + 1);
+  // The linker escapes dots in the last path element (gopkg.in/yaml%2ev3)
+  return dot > 0 ? head.slice(0, dot).replace(/%2e/gi, '.') : null;
+}
+
+/**
+ * Bytes per package from `go tool nm -size` output; symbols of no package
+ * are counted under the empty string. bss and undefined symbols take no
+ * space in the file and are skipped.
+ */
+export function parseSymbolSizes(output: string): Map<string, number> {
+  const sizes = new Map<string, number>();
+  for (const line of output.split('\n')) {
+    // address size type name
+    const match = line.trim().match(/^\S+\s+(\d+)\s+([A-Za-z])\s+(.+)$/);
+    if (!match || /^[BbU]$/.test(match[2])) continue;
+    const name = match[3];
+    // symbolPackage leaves the runtime out, as the binsize metric only colors workspace packages
+    const found = name.startsWith('runtime.') ? 'runtime' : symbolPackage(name);
+    // Constants the compiler pools ($f64.3ff0000000000000) and generic shapes aren't packages
+    const pkg = found && /^[A-Za-z][\w.~\-/]*$/.test(found) && found !== 'go' ? found : '';
+    sizes.set(pkg, (sizes.get(pkg) ?? 0) + parseInt(match[1], 10));
+  }
+  return sizes;
+}
+
+export async function analyzeBinary(binary: string, options: BinaryOptions = {}): Promise<BinaryReport> {
+  const file = resolve(binary);
+  if (!existsSync(file)) throw new Error(`${binary} does not exist`);
+  // Neither command needs a module; running beside the binary keeps a go.mod toolchain line from applying
+  const cwd = dirname(file);
+  const version = await runGo(['version', '-m', file], { cwd, signal: options.signal });
+  if (version.exitCode !== 0) {
+    throw new Error(`${binary} is not a Go binary with build information: ${version.stderr.trim() || version.stdout.trim()}`);
+  }
+  const info = parseBuildInfo(version.stdout);
+
+  const warnings: string[] = [];
+  const nm = await runGo(['tool', 'nm', '-size', file], { cwd, signal: options.signal });
+  const sizes = nm.exitCode === 0 ? parseSymbolSizes(nm.stdout) : new Map<string, number>();
+  const stripped = sizes.size === 0;
+  if (stripped) warnings.push(`${binary} has no symbol table (built with -ldflags=-s?); modules are listed without sizes`);
+  if (info.main.path === 'command-line-arguments' || !info.main.path) {
+    warnings.push('Built from files rather than a module package; its own code is counted as package main');
+  }
+
+  // Longest module path first, so nested modules claim their own packages
+  const modules = [info.main, ...info.deps];
+  const byLength = [...modules].sort((a, b) => b.path.length - a.path.length);
+  const owner = (pkg: string): BinaryModule | null => {
+    // Every main package is named main in the symbol table
+    if (pkg === 'main') return info.main;
+    return byLength.find(m => m.path && (pkg === m.path || pkg.startsWith(`${m.path}/`))) ?? null;
+  };
+
+  const packages: BinaryPackage[] = [];
+  const std = { size: 0, packages: 0 };
+  let unattributed = 0;
+  let total = 0;
+  for (const [pkg, size] of sizes) {
+    total += size;
+    const module = pkg ? owner(pkg) : null;
+    if (module) {
+      module.size += size;
+      module.packages++;
+      packages.push({ path: pkg, module: module.path, size });
+    } else if (pkg && !pkg.split('/')[0].includes('.')) {
+      // The standard library's first path element has no dot
+      std.size += size;
+      std.packages++;
+      packages.push({ path: pkg, module: null, size });
+    } else {
+      unattributed += size;
+    }
+  }
+
+  const deps = info.deps.sort((a, b) => b.size - a.size || a.path.localeCompare(b.path));
+  return {
+    binary,
+    goVersion: info.goVersion,
+    path: info.path,
+    settings: info.settings,
+    modules: [info.main, ...deps],
+    std,
+    unattributed,
+    total,
+    packages: packages.sort((a, b) => b.size - a.size || a.path.localeCompare(b.path)),
+    stripped,
+    warnings,
+  };
+}
+
+export interface SizeChange {
+  /** Module or package path */
+  path: string;
+  before: number;
+  after: number;
+  delta: number;
+}
+
+export interface BuildSummary {
+  binary: string;
+  path: string;
+  revision: string | null;
+  total: number;
+}
+
+export interface BinaryComparison {
+  before: BuildSummary;
+  after: BuildSummary;
+  delta: number;
+  /** Modules (the standard library as "std") whose size changed, most growth first */
+  modules: SizeChange[];
+  packages: SizeChange[];
+  /** Dependencies only one of the builds links */
+  added: string[];
+  removed: string[];
+}
+
+const summary = (report: BinaryReport): BuildSummary =>
+  ({ binary: report.binary, path: report.path, revision: report.settings['vcs.revision'] ?? null, total: report.total });
+
+function sizeChanges(before: Map<string, number>, after: Map<string, number>): SizeChange[] {
+  const changes: SizeChange[] = [];
+  for (const path of new Set([...before.keys(), ...after.keys()])) {
+    const b = before.get(path) ?? 0;
+    const a = after.get(path) ?? 0;
+    if (a !== b) changes.push({ path, before: b, after: a, delta: a - b });
+  }
+  return changes.sort((x, y) => y.delta - x.delta || x.path.localeCompare(y.path));
+}
+
+// The main module is keyed by its role, so a renamed or forked module still lines up
+const moduleSizes = (report: BinaryReport) => new Map<string, number>([
+  ...report.modules.map(m => [m.main ? `${m.path} (main)` : m.path, m.size] as [string, number]),
+  ['std', report.std.size],
+]);
+
+/** Where the bytes went between two builds of a program, say the last release and this branch */
+export function compareBinaryReports(before: BinaryReport, after: BinaryReport): BinaryComparison {
+  const deps = (report: BinaryReport) => new Set(report.modules.filter(m => !m.main).map(m => m.path));
+  const [old, current] = [deps(before), deps(after)];
+  return {
+    before: summary(before),
+    after: summary(after),
+    delta: after.total - before.total,
+    modules: sizeChanges(moduleSizes(before), moduleSizes(after)),
+    packages: sizeChanges(new Map(before.packages.map(p => [p.path, p.size])), new Map(after.packages.map(p => [p.path, p.size]))),
+    added: [...current].filter(m => !old.has(m)).sort(),
+    removed: [...old].filter(m => !current.has(m)).sort(),
+  };
+}
+
+/**
+ * Recorded build sizes, for trends across builds: one record per
+ * `depwire binary --record`, the last HISTORY_LIMIT kept.
+ */
+export const BINARY_HISTORY_FILE = join('.depwire', 'binary-history.json');
+
+const HISTORY_LIMIT = 100;
+
+export interface BinarySizeRecord extends BuildSummary {
+  recordedAt: string;
+  goVersion: string;
+  std: number;
+  /** Module path → bytes */
+  modules: Record<string, number>;
+}
+
+export function readBinaryHistory(projectRoot: string): BinarySizeRecord[] {
+  const file = join(projectRoot, BINARY_HISTORY_FILE);
+  if (!existsSync(file)) return [];
+  try {
+    const history = JSON.parse(readFileSync(file, 'utf-8'));
+    return Array.isArray(history) ? history : [];
+  } catch {
+    return [];
+  }
+}
+
+/** Append a build to the history; returns the records of the same main package, oldest first */
+export function recordBinarySize(projectRoot: string, report: BinaryReport): BinarySizeRecord[] {
+  const record: BinarySizeRecord = {
+    ...summary(report),
+    recordedAt: new Date().toISOString(),
+    goVersion: report.goVersion,
+    std: report.std.size,
+    modules: Object.fromEntries(report.modules.map(m => [m.path, m.size])),
+  };
+  const history = [...readBinaryHistory(projectRoot), record].slice(-HISTORY_LIMIT);
+  const file = join(projectRoot, BINARY_HISTORY_FILE);
+  mkdirSync(dirname(file), { recursive: true });
+  writeFileAtomic(file, JSON.stringify(history, null, 2) + '\n');
+  return history.filter(r => r.path === report.path);
+}
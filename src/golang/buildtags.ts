@@ -0,0 +1,292 @@
+import { basename } from 'path';
+import { goImports, type GoSourceFile } from './source.js';
+import { loadGoProject, type GoProject } from './packages.js';
+import { packageKey } from './callgraph.js';
+
+/**
+ * Imports that only exist in some builds. Drivers picked by build tags
+ * (db_sqlite.go with //go:build sqlite), platform files (poll_windows.go)
+ * and registry packages blank-imported for their init behind a tag all
+ * change the import graph depending on GOOS, GOARCH and -tags.
+ *
+ * Every constraint in the workspace — //go:build lines, legacy // +build
+ * lines and _GOOS/_GOARCH file name suffixes — is parsed, and the
+ * configurations that combine the GOOS, GOARCH and custom tags they
+ * mention are enumerated. Configurations that select the same files are
+ * merged. Each import edge is labelled with its condition and the
+ * configurations it is active in.
+ */
+
+export type BuildConstraint =
+  | { op: 'tag'; tag: string }
+  | { op: 'not'; x: BuildConstraint }
+  | { op: 'and' | 'or'; x: BuildConstraint; y: BuildConstraint };
+
+// go/build's syslist
+export const KNOWN_GOOS = ['aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'hurd', 'illumos', 'ios', 'js', 'linux', 'nacl', 'netbsd', 'openbsd', 'plan9', 'solaris', 'wasip1', 'windows', 'zos'];
+export const KNOWN_GOARCH = ['386', 'amd64', 'amd64p32', 'arm', 'armbe', 'arm64', 'arm64be', 'loong64', 'mips', 'mipsle', 'mips64', 'mips64le', 'mips64p32', 'mips64p32le', 'ppc', 'ppc64', 'ppc64le', 'riscv', 'riscv64', 's390', 's390x', 'sparc', 'sparc64', 'wasm'];
+const UNIX_GOOS = new Set(['aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'hurd', 'illumos', 'ios', 'linux', 'netbsd', 'openbsd', 'solaris']);
+// GOOS values that also satisfy another one
+const IMPLIED_GOOS: Record<string, string> = { android: 'linux', ios: 'darwin', illumos: 'solaris' };
+/** Tags the toolchain sets or never sets, which aren't configuration choices */
+const FIXED_TAGS: Record<string, boolean> = { gc: true, gccgo: false, ignore: false };
+const RELEASE_TAG = /^go1\.\d+$/;
+
+/** Custom tags beyond which only none, each one alone and all of them are tried */
+const MAX_EXHAUSTIVE_TAGS = 6;
+
+export interface BuildConfiguration {
+  /** "linux/amd64", "windows/amd64+sqlite,cgo" */
+  id: string;
+  goos: string;
+  goarch: string;
+  tags: string[];
+  /** Conditional files it selects */
+  files: number;
+}
+
+export interface ConditionalImportFile {
+  file: string;
+  /** The file's constraint; null when it has none */
+  condition: string | null;
+}
+
+export interface ConditionalImport {
+  /** Key of the importing package */
+  from: string;
+  /** Import path */
+  to: string;
+  local: boolean;
+  /** Blank import, for the package's init (drivers, codecs, registries) */
+  blank: boolean;
+  /** Under which the edge exists (any importing file's constraint); null when unconditional */
+  condition: string | null;
+  files: ConditionalImportFile[];
+  /** Ids of the configurations the edge is active in */
+  configurations: string[];
+  always: boolean;
+}
+
+export interface BuildConfigReport {
+  projectRoot: string;
+  configurations: BuildConfiguration[];
+  imports: ConditionalImport[];
+  /** Tags found in constraints, by kind */
+  tags: { goos: string[]; goarch: string[]; custom: string[] };
+  warnings: string[];
+}
+
+export interface BuildConfigOptions {
+  /** Extra custom tags to try, beyond those in constraints */
+  tags?: string[];
+  /** Include edges that are active in every configuration */
+  all?: boolean;
+}
+
+export async function analyzeBuildConfigurations(projectRoot: string, options: BuildConfigOptions = {}): Promise<BuildConfigReport> {
+  return buildConfigurations(await loadGoProject(projectRoot), options);
+}
+
+export function buildConfigurations(project: GoProject, options: BuildConfigOptions = {}): BuildConfigReport {
+  const warnings: string[] = [];
+  const constraints = new Map<string, BuildConstraint | null>();
+  for (const pkg of project.packages.values()) {
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      try {
+        constraints.set(file.file, fileConstraint(file));
+      } catch (err) {
+        warnings.push(`${file.file}: ${err instanceof Error ? err.message : err}; treated as unconstrained`);
+        constraints.set(file.file, null);
+      }
+    }
+  }
+
+  const atoms = new Set<string>(options.tags ?? []);
+  for (const c of constraints.values()) if (c) for (const tag of constraintTags(c)) atoms.add(tag);
+  const goos = [...new Set(['linux', ...[...atoms].filter(t => KNOWN_GOOS.includes(t)), ...(atoms.has('unix') ? ['windows'] : [])])].sort();
+  const goarch = [...new Set(['amd64', ...[...atoms].filter(t => KNOWN_GOARCH.includes(t))])].sort();
+  const custom = [...atoms].filter(t => !KNOWN_GOOS.includes(t) && !KNOWN_GOARCH.includes(t) && t !== 'unix' && !(t in FIXED_TAGS) && !RELEASE_TAG.test(t)).sort();
+
+  let tagSets: string[][];
+  if (custom.length <= MAX_EXHAUSTIVE_TAGS) {
+    tagSets = [[]];
+    for (const tag of custom) tagSets = [...tagSets, ...tagSets.map(set => [...set, tag])];
+  } else {
+    tagSets = [[], ...custom.map(t => [t]), custom];
+    warnings.push(`${custom.length} custom build tags; only none, each alone and all together are tried`);
+  }
+
+  // Configurations selecting the same files are one; the first (fewest tags, first GOOS/GOARCH) names it
+  const conditional = [...constraints].filter(([, c]) => c !== null) as Array<[string, BuildConstraint]>;
+  const configurations: BuildConfiguration[] = [];
+  const active = new Map<string, Set<string>>();
+  const seen = new Set<string>();
+  for (const tags of tagSets.sort((a, b) => a.length - b.length)) {
+    for (const os of goos) {
+      for (const arch of goarch) {
+        const set = configTags(os, arch, tags);
+        const files = conditional.filter(([, c]) => evalConstraint(c, set)).map(([file]) => file);
+        const signature = files.join('\0');
+        if (seen.has(signature)) continue;
+        seen.add(signature);
+        const id = `${os}/${arch}${tags.length > 0 ? `+${tags.join(',')}` : ''}`;
+        configurations.push({ id, goos: os, goarch: arch, tags, files: files.length });
+        active.set(id, new Set(files));
+      }
+    }
+  }
+
+  const imports: ConditionalImport[] = [];
+  for (const pkg of project.packages.values()) {
+    const from = packageKey(pkg);
+    const edges = new Map<string, { blank: boolean; files: ConditionalImportFile[] }>();
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      const constraint = constraints.get(file.file) ?? null;
+      for (const imp of goImports(file)) {
+        const edge = edges.get(imp.path) ?? { blank: false, files: [] };
+        edge.blank ||= imp.kind === 'blank';
+        edge.files.push({ file: file.file, condition: constraint ? formatConstraint(constraint) : null });
+        edges.set(imp.path, edge);
+      }
+    }
+    for (const [to, edge] of edges) {
+      const unconditional = edge.files.some(f => f.condition === null);
+      const ids = configurations
+        .filter(c => edge.files.some(f => f.condition === null || active.get(c.id)!.has(f.file)))
+        .map(c => c.id);
+      const always = ids.length === configurations.length;
+      if (always && !options.all) continue;
+      const conditions = [...new Set(edge.files.map(f => f.condition))];
+      imports.push({
+        from,
+        to,
+        local: project.modules.dirForImport(to) !== null,
+        blank: edge.blank,
+        condition: unconditional ? null : conditions.length === 1 ? conditions[0] : conditions.map(c => `(${c})`).join(' || '),
+        files: edge.files,
+        configurations: ids,
+        always,
+      });
+    }
+  }
+  imports.sort((a, b) => a.from.localeCompare(b.from) || a.to.localeCompare(b.to));
+
+  return {
+    projectRoot: project.projectRoot,
+    configurations,
+    imports,
+    tags: { goos: goos.filter(t => atoms.has(t)), goarch: goarch.filter(t => atoms.has(t)), custom },
+    warnings,
+  };
+}
+
+/** A file's build constraint: its //go:build line (or // +build lines) and its _GOOS/_GOARCH suffix, combined */
+export function fileConstraint(file: GoSourceFile): BuildConstraint | null {
+  const parts: BuildConstraint[] = [];
+  // Constraints only count above the package clause
+  const header = file.source.slice(0, file.root.namedChildren.find(n => n?.type === 'package_clause')?.startIndex ?? 0);
+  const goBuild = /^\/\/go:build\s+(.+)$/m.exec(header);
+  if (goBuild) {
+    parts.push(parseConstraint(goBuild[1]));
+  } else {
+    for (const match of header.matchAll(/^\/\/ ?\+build\s+(.+)$/gm)) parts.push(parsePlusBuild(match[1]));
+  }
+  const suffix = fileNameConstraint(file.file);
+  if (suffix) parts.push(suffix);
+  return parts.length === 0 ? null : parts.reduce((x, y) => ({ op: 'and', x, y }));
+}
+
+/** The //go:build expression grammar: ||, &&, !, parentheses and tags */
+export function parseConstraint(expr: string): BuildConstraint {
+  const tokens = expr.match(/\|\||&&|!|\(|\)|[\w.]+|\S/g) ?? [];
+  let i = 0;
+  const fail = (what: string): never => { throw new Error(`invalid //go:build expression "${expr.trim()}": ${what}`); };
+  const or = (): BuildConstraint => {
+    let x = and();
+    while (tokens[i] === '||') {
+      i++;
+      x = { op: 'or', x, y: and() };
+    }
+    return x;
+  };
+  const and = (): BuildConstraint => {
+    let x = not();
+    while (tokens[i] === '&&') {
+      i++;
+      x = { op: 'and', x, y: not() };
+    }
+    return x;
+  };
+  const not = (): BuildConstraint => {
+    const token = tokens[i++];
+    if (token === '!') return { op: 'not', x: not() };
+    if (token === '(') {
+      const x = or();
+      if (tokens[i++] !== ')') fail('missing )');
+      return x;
+    }
+    if (!token || !/^[\w.]+$/.test(token)) return fail(token ? `unexpected ${token}` : 'unexpected end');
+    return { op: 'tag', tag: token };
+  };
+  const result = or();
+  if (i < tokens.length) fail(`unexpected ${tokens[i]}`);
+  return result;
+}
+
+// // +build linux,amd64 darwin — spaces are ||, commas &&, ! negates a tag
+function parsePlusBuild(line: string): BuildConstraint {
+  const options = line.trim().split(/\s+/).map(option => option.split(',').map((term): BuildConstraint => {
+    const tag = term.replace(/^!/, '');
+    if (!/^[\w.]+$/.test(tag)) throw new Error(`invalid // +build term "${term}"`);
+    return term.startsWith('!') ? { op: 'not', x: { op: 'tag', tag } } : { op: 'tag', tag };
+  }).reduce((x, y) => ({ op: 'and', x, y })));
+  return options.reduce((x, y) => ({ op: 'or', x, y }));
+}
+
+// name_GOOS.go, name_GOARCH.go, name_GOOS_GOARCH.go (a _test suffix comes after)
+function fileNameConstraint(path: string): BuildConstraint | null {
+  const parts = basename(path).replace(/\.go$/, '').replace(/_test$/, '').split('_');
+  if (parts.length < 2) return null;
+  const last = parts[parts.length - 1];
+  const previous = parts.length >= 3 ? parts[parts.length - 2] : null;
+  if (KNOWN_GOARCH.includes(last)) {
+    const arch: BuildConstraint = { op: 'tag', tag: last };
+    return previous && KNOWN_GOOS.includes(previous) ? { op: 'and', x: { op: 'tag', tag: previous }, y: arch } : arch;
+  }
+  return KNOWN_GOOS.includes(last) ? { op: 'tag', tag: last } : null;
+}
+
+export function evalConstraint(c: BuildConstraint, tags: Set<string>): boolean {
+  switch (c.op) {
+    case 'tag': return c.tag in FIXED_TAGS ? FIXED_TAGS[c.tag] : RELEASE_TAG.test(c.tag) || tags.has(c.tag);
+    case 'not': return !evalConstraint(c.x, tags);
+    case 'and': return evalConstraint(c.x, tags) && evalConstraint(c.y, tags);
+    case 'or': return evalConstraint(c.x, tags) || evalConstraint(c.y, tags);
+  }
+}
+
+export function formatConstraint(c: BuildConstraint, parent?: BuildConstraint['op']): string {
+  switch (c.op) {
+    case 'tag': return c.tag;
+    case 'not': return `!${formatConstraint(c.x, 'not')}`;
+    default: {
+      const text = `${formatConstraint(c.x, c.op)} ${c.op === 'and' ? '&&' : '||'} ${formatConstraint(c.y, c.op)}`;
+      return parent && parent !== c.op ? `(${text})` : text;
+    }
+  }
+}
+
+function constraintTags(c: BuildConstraint): string[] {
+  if (c.op === 'tag') return [c.tag];
+  if (c.op === 'not') return constraintTags(c.x);
+  return [...constraintTags(c.x), ...constraintTags(c.y)];
+}
+
+function configTags(goos: string, goarch: string, tags: string[]): Set<string> {
+  const set = new Set([goos, goarch, ...tags]);
+  if (IMPLIED_GOOS[goos]) set.add(IMPLIED_GOOS[goos]);
+  if (UNIX_GOOS.has(goos)) set.add('unix');
+  return set;
+}
@@ -0,0 +1,254 @@
+import { existsSync, mkdtempSync, readFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { findGoModules, GoModuleIndex } from './modules.js';
+import { runGo } from './toolchain.js';
+
+/**
+ * Compile time per package from the action graph the go command writes
+ * with -debug-actiongraph, and what a change to each package costs: the
+ * package recompiles, and so does every package that imports it, directly
+ * or through others. The rebuild cost is the sum of those compile times;
+ * ranked against fan-in, it shows which heavily imported packages are also
+ * expensive to touch. Linking, paid once per binary whatever changed, is
+ * reported on its own.
+ *
+ * Packages the build took from the cache have no compile time, so builds
+ * are measured with -a, which compiles everything.
+ */
+
+/** One action of `go build -debug-actiongraph` output (the fields read here) */
+export interface BuildAction {
+  ID: number;
+  /** build, link, link-install, build check cache, nop, … */
+  Mode: string;
+  Package: string;
+  Deps: number[] | null;
+  NeedBuild?: boolean;
+  TimeStart?: string;
+  TimeDone?: string;
+  /** Wall time of the action's commands, in nanoseconds */
+  CmdReal?: number;
+}
+
+export interface PackageBuildTime {
+  /** Import path */
+  package: string;
+  /** Project directory; null for dependencies and the standard library */
+  dir: string | null;
+  /** Compile time in milliseconds, cgo and assembly included */
+  compile: number;
+  /** Taken from the build cache, so its compile time is unknown */
+  cached: boolean;
+  /** Packages in the build that import it */
+  fanIn: number;
+  /** Packages in the build that depend on it directly or transitively */
+  dependents: number;
+  /** Compile time of the package and all its dependents: what a change to it recompiles */
+  rebuild: number;
+}
+
+export interface BuildTimeReport {
+  /** First action started to last action done, milliseconds */
+  wall: number;
+  /** Compile time over every package */
+  compile: number;
+  /** Compile time of the project's own packages */
+  workspace: number;
+  /** Link time over every binary */
+  link: number;
+  binaries: number;
+  /** Most expensive to change first */
+  packages: PackageBuildTime[];
+  /** Spearman rank correlation of fan-in and rebuild cost over the project's packages; null below 3 */
+  correlation: number | null;
+  warnings: string[];
+}
+
+export interface MeasureBuildOptions {
+  /** Package patterns (default ./...) */
+  patterns?: string[];
+  signal?: AbortSignal;
+}
+
+// Test variants are named "pkg [pkg.test]"
+const packageName = (action: BuildAction) => action.Package.replace(/ \[.*\]$/, '');
+
+function duration(action: BuildAction): number {
+  if (typeof action.CmdReal === 'number') return action.CmdReal / 1e6;
+  const start = action.TimeStart ? Date.parse(action.TimeStart) : NaN;
+  const done = action.TimeDone ? Date.parse(action.TimeDone) : NaN;
+  return Number.isFinite(start) && Number.isFinite(done) ? Math.max(0, done - start) : 0;
+}
+
+export function parseActionGraph(content: string, name: string): BuildAction[] {
+  let parsed: unknown;
+  try {
+    parsed = JSON.parse(content);
+  } catch {
+    parsed = null;
+  }
+  if (!Array.isArray(parsed) || parsed.some(a => typeof a?.ID !== 'number' || typeof a?.Mode !== 'string')) {
+    throw new Error(`${name} is not a go build -debug-actiongraph file`);
+  }
+  return parsed as BuildAction[];
+}
+
+export function readActionGraphs(files: string[]): BuildAction[][] {
+  return files.map(file => parseActionGraph(readFileSync(file, 'utf-8'), file));
+}
+
+// Spearman's rho with average ranks for ties
+function rankCorrelation(xs: number[], ys: number[]): number | null {
+  if (xs.length < 3) return null;
+  const ranks = (values: number[]) => {
+    const order = values.map((v, i) => [v, i] as const).sort((a, b) => a[0] - b[0]);
+    const result = new Array<number>(values.length);
+    for (let i = 0; i < order.length;) {
+      let j = i;
+      while (j + 1 < order.length && order[j + 1][0] === order[i][0]) j++;
+      for (let k = i; k <= j; k++) result[order[k][1]] = (i + j) / 2;
+      i = j + 1;
+    }
+    return result;
+  };
+  const rx = ranks(xs);
+  const ry = ranks(ys);
+  const mean = (xs.length - 1) / 2;
+  let cov = 0, vx = 0, vy = 0;
+  for (let i = 0; i < xs.length; i++) {
+    cov += (rx[i] - mean) * (ry[i] - mean);
+    vx += (rx[i] - mean) ** 2;
+    vy += (ry[i] - mean) ** 2;
+  }
+  return vx === 0 || vy === 0 ? null : cov / Math.sqrt(vx * vy);
+}
+
+/**
+ * Attribute the builds of one or more action graphs (one per module built)
+ * to packages. A package compiled by several builds keeps its longest
+ * compile; imports are merged.
+ */
+export function attributeBuildTime(projectRoot: string, graphs: BuildAction[][]): BuildTimeReport {
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const compile = new Map<string, { time: number; cached: boolean }>();
+  const imports = new Map<string, Set<string>>();
+  let link = 0;
+  let binaries = 0;
+  let first = Infinity;
+  let last = -Infinity;
+
+  for (const actions of graphs) {
+    const byId = new Map(actions.map(a => [a.ID, a]));
+    for (const action of actions) {
+      const start = action.TimeStart ? Date.parse(action.TimeStart) : NaN;
+      const done = action.TimeDone ? Date.parse(action.TimeDone) : NaN;
+      if (Number.isFinite(start)) first = Math.min(first, start);
+      if (Number.isFinite(done)) last = Math.max(last, done);
+
+      if (action.Mode === 'link') {
+        binaries++;
+        link += duration(action);
+        continue;
+      }
+      if (action.Mode !== 'build') continue;
+      const pkg = packageName(action);
+      const cached = action.NeedBuild === false;
+      const time = cached ? 0 : duration(action);
+      const seen = compile.get(pkg);
+      if (!seen || (seen.cached && !cached) || time > seen.time) compile.set(pkg, { time, cached });
+      const deps = imports.get(pkg) ?? new Set<string>();
+      for (const id of action.Deps ?? []) {
+        const dep = byId.get(id);
+        if (dep?.Mode === 'build' && packageName(dep) !== pkg) deps.add(packageName(dep));
+      }
+      imports.set(pkg, deps);
+    }
+  }
+
+  const importers = new Map<string, string[]>();
+  for (const [pkg, deps] of imports) {
+    for (const dep of deps) {
+      const list = importers.get(dep) ?? [];
+      list.push(pkg);
+      importers.set(dep, list);
+    }
+  }
+
+  const packages: PackageBuildTime[] = [];
+  for (const [pkg, { time, cached }] of compile) {
+    const seen = new Set<string>([pkg]);
+    const queue = [pkg];
+    let rebuild = time;
+    while (queue.length > 0) {
+      for (const dependent of importers.get(queue.shift()!) ?? []) {
+        if (seen.has(dependent)) continue;
+        seen.add(dependent);
+        queue.push(dependent);
+        rebuild += compile.get(dependent)?.time ?? 0;
+      }
+    }
+    packages.push({
+      package: pkg,
+      dir: index.dirForImport(pkg),
+      compile: time,
+      cached,
+      fanIn: importers.get(pkg)?.length ?? 0,
+      dependents: seen.size - 1,
+      rebuild,
+    });
+  }
+  packages.sort((a, b) => b.rebuild - a.rebuild || a.package.localeCompare(b.package));
+
+  const own = packages.filter(p => p.dir !== null);
+  const warnings: string[] = [];
+  if (compile.size === 0) warnings.push('The action graph has no compile actions');
+  const cached = packages.filter(p => p.cached).length;
+  if (cached > 0) warnings.push(`${cached} packages came from the build cache, so their compile time is unknown (build with -a to time everything)`);
+
+  return {
+    wall: last > first ? last - first : 0,
+    compile: packages.reduce((n, p) => n + p.compile, 0),
+    workspace: own.reduce((n, p) => n + p.compile, 0),
+    link,
+    binaries,
+    packages,
+    correlation: rankCorrelation(own.map(p => p.fanIn), own.map(p => p.rebuild)),
+    warnings,
+  };
+}
+
+/**
+ * Build the project with `go build -a -debug-actiongraph` and attribute
+ * the time. A go.work at the root, or a single root module, is built in
+ * one run; otherwise each module is built on its own. Binaries go to a
+ * temporary directory.
+ */
+export async function measureBuild(projectRoot: string, options: MeasureBuildOptions = {}): Promise<BuildTimeReport> {
+  const modules = findGoModules(projectRoot);
+  if (modules.length === 0) throw new Error(`No go.mod under ${projectRoot}`);
+  const roots = existsSync(join(projectRoot, 'go.work')) || modules.every(m => m.dir === '.')
+    ? [projectRoot]
+    : modules.map(m => join(projectRoot, m.dir));
+
+  const dir = mkdtempSync(join(tmpdir(), 'depwire-buildtime-'));
+  const graphs: BuildAction[][] = [];
+  const warnings: string[] = [];
+  try {
+    for (const [i, cwd] of roots.entries()) {
+      const file = join(dir, `actiongraph-${i}.json`);
+      const result = await runGo(
+        ['build', '-a', `-debug-actiongraph=${file}`, '-o', join(dir, `bin-${i}`) + '/', ...(options.patterns ?? ['./...'])],
+        { cwd, signal: options.signal },
+      );
+      if (result.exitCode !== 0) warnings.push(`go build in ${cwd} failed: ${result.stderr.trim().split('\n')[0]}`);
+      if (existsSync(file)) graphs.push(parseActionGraph(readFileSync(file, 'utf-8'), file));
+    }
+  } finally {
+    rmSync(dir, { recursive: true, force: true });
+  }
+
+  const report = attributeBuildTime(projectRoot, graphs);
+  report.warnings.unshift(...warnings);
+  return report;
+}
@@ -0,0 +1,440 @@
+import type { Node } from 'web-tree-sitter';
+import { walk, type GoSourceFile } from './source.js';
+import { fileQualifiers } from './references.js';
+import type { GoPackage, GoProject } from './packages.js';
+import { pointerCallEdges, type PointerEdge } from './pta.js';
+
+/**
+ * A syntactic call graph for Go: functions and methods of every project
+ * package, and the calls between them. Without type checking, method calls
+ * are resolved from the receiver's declared type when it's visible
+ * (receivers, parameters, composite literals) and otherwise to every project
+ * method with that name — a class-hierarchy-style over-approximation marked
+ * `approximate`.
+ *
+ * With --algo pta the calls come from SSA pointer analysis instead (see
+ * pta.ts): slower, but exact through interfaces and function values, and
+ * only for code reachable from a main package.
+ */
+
+export type CallGraphAlgorithm = 'syntactic' | 'pta';
+
+export const CALL_GRAPH_ALGORITHMS: CallGraphAlgorithm[] = ['syntactic', 'pta'];
+
+export interface GoFunction {
+  /** "<import path>.Name" or "<import path>.Type.Name" for methods */
+  id: string;
+  /** Import path of the package (directory outside any module) */
+  package: string;
+  dir: string;
+  name: string;
+  receiver?: string;
+  file: string;
+  line: number;
+  /** function_declaration or method_declaration */
+  node: Node;
+  /** Parameter names in order (receiver excluded); "_" for unnamed ones */
+  params: string[];
+  /** Qualified parameter types ("net/http.Request" for *http.Request) */
+  paramTypes: string[];
+  /** Receiver name for methods */
+  receiverName?: string;
+  exported: boolean;
+}
+
+export interface GoCall {
+  caller: string;
+  /** Function id for project functions, "<import path>.Name" for external ones, "?.Name" for unknown methods */
+  callee: string;
+  external: boolean;
+  /** Matched by method name only */
+  approximate: boolean;
+  /** The function was referenced as a value (handler, callback), not called */
+  reference: boolean;
+  file: string;
+  line: number;
+  node: Node;
+}
+
+export interface GoCallGraph {
+  project: GoProject;
+  functions: Map<string, GoFunction>;
+  calls: GoCall[];
+  callsFrom: Map<string, GoCall[]>;
+  /** main.main of each command and every init function */
+  entryPoints: string[];
+  /** Resolved callees of a call_expression */
+  calleesOf(file: string, call: Node): GoCall[];
+  /** The function or method declaration a node is inside, if any */
+  functionAt(file: string, node: Node): GoFunction | null;
+}
+
+/** Key a package's functions are qualified with */
+export function packageKey(pkg: GoPackage): string {
+  return pkg.importPath ?? pkg.dir;
+}
+
+export interface LoadCallGraphOptions {
+  algo?: string;
+  signal?: AbortSignal;
+  /** Collects why a precise graph fell back to the syntactic one */
+  warnings?: string[];
+}
+
+/** The call graph of an analysis, built with the chosen algorithm */
+export async function loadGoCallGraph(project: GoProject, options: LoadCallGraphOptions = {}): Promise<GoCallGraph> {
+  const algo = options.algo ?? 'syntactic';
+  if (!CALL_GRAPH_ALGORITHMS.includes(algo as CallGraphAlgorithm)) {
+    throw new Error(`Unknown call graph algorithm "${algo}" (expected ${CALL_GRAPH_ALGORITHMS.join(' or ')})`);
+  }
+  if (algo === 'syntactic') return buildGoCallGraph(project);
+  const analysis = await pointerCallEdges(project, { signal: options.signal });
+  if (analysis.modules.length === 0) {
+    options.warnings?.push('No main package for pointer analysis to start from; using the syntactic call graph');
+    return buildGoCallGraph(project);
+  }
+  return buildGoCallGraph(project, analysis.edges);
+}
+
+/** The syntactic call graph, or one from pointer analysis edges when given */
+export function buildGoCallGraph(project: GoProject, edges?: PointerEdge[]): GoCallGraph {
+  const functions = new Map<string, GoFunction>();
+  const methodsByName = new Map<string, string[]>();
+  const inits = new Map<string, number>();
+  const entryPoints: string[] = [];
+
+  for (const pkg of project.packages.values()) {
+    const key = packageKey(pkg);
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      const qualifiers = fileQualifiers(file, project);
+      for (const node of file.root.namedChildren) {
+        if (node?.type !== 'function_declaration' && node?.type !== 'method_declaration') continue;
+        const name = node.childForFieldName('name')?.text;
+        if (!name) continue;
+        const receiver = node.type === 'method_declaration' ? receiverInfo(node, key, qualifiers) : null;
+        if (node.type === 'method_declaration' && !receiver) continue;
+
+        let id = receiver ? `${key}.${receiver.type}.${name}` : `${key}.${name}`;
+        if (!receiver && name === 'init') {
+          // A package can have any number of init functions
+          const n = (inits.get(key) ?? 0) + 1;
+          inits.set(key, n);
+          id = `${key}.init#${n}`;
+          entryPoints.push(id);
+        }
+        if (!receiver && name === 'main' && pkg.name === 'main') entryPoints.push(id);
+
+        const { names, types } = parameters(node.childForFieldName('parameters'), key, qualifiers);
+        functions.set(id, {
+          id,
+          package: key,
+          dir: pkg.dir,
+          name,
+          receiver: receiver?.type,
+          file: file.file,
+          line: node.startPosition.row + 1,
+          node,
+          params: names,
+          paramTypes: types,
+          receiverName: receiver?.name,
+          exported: /^\p{Lu}/u.test(name),
+        });
+        if (receiver) {
+          if (!methodsByName.has(name)) methodsByName.set(name, []);
+          methodsByName.get(name)!.push(id);
+        }
+      }
+    }
+  }
+
+  const calls: GoCall[] = [];
+  const callsFrom = new Map<string, GoCall[]>();
+  const callsAt = new Map<string, GoCall[]>();
+  const declarations = new Map<string, GoFunction>();
+  for (const fn of functions.values()) declarations.set(`${fn.file}:${fn.node.startIndex}`, fn);
+  const edgesFrom = new Map<string, PointerEdge[]>();
+  for (const edge of edges ?? []) {
+    if (!edgesFrom.has(edge.caller)) edgesFrom.set(edge.caller, []);
+    edgesFrom.get(edge.caller)!.push(edge);
+  }
+
+  for (const fn of functions.values()) {
+    const pkg = project.packages.get(fn.dir)!;
+    const file = pkg.files.find(f => f.file === fn.file)!;
+    const qualifiers = fileQualifiers(file, project);
+    const types = localTypes(fn, qualifiers);
+    const out: GoCall[] = [];
+
+    const add = (call: Omit<GoCall, 'caller' | 'file'>) => {
+      const entry = { ...call, caller: fn.id, file: fn.file };
+      out.push(entry);
+      const at = `${fn.file}:${call.node.startIndex}`;
+      if (!callsAt.has(at)) callsAt.set(at, []);
+      callsAt.get(at)!.push(entry);
+    };
+
+    if (edges) {
+      const sites = callSites(fn.node);
+      for (const edge of edgesFrom.get(fn.id) ?? []) {
+        const atLine = sites.get(edge.line) ?? [];
+        // go/token counts bytes and tree-sitter UTF-16 units, so the column only has to pick between calls on a line
+        const node = atLine.length === 1 ? atLine[0].node : atLine.find(s => s.column === edge.column - 1)?.node;
+        if (!node) continue;
+        add({ callee: edge.callee, external: !functions.has(edge.callee), approximate: false, reference: false, line: edge.line, node });
+      }
+      calls.push(...out);
+      callsFrom.set(fn.id, out);
+      continue;
+    }
+
+    walk(fn.node, (node) => {
+      if (node.type === 'call_expression') {
+        const callee = node.childForFieldName('function');
+        const line = node.startPosition.row + 1;
+        for (const target of resolveCallee(callee, fn, qualifiers, types, functions, methodsByName)) {
+          add({ ...target, reference: false, line, node });
+        }
+        return;
+      }
+      // Functions passed as values (http.HandleFunc("/", handle)) are reachable too
+      if (node.type === 'argument_list' || node.type === 'keyed_element' || node.type === 'literal_element') {
+        for (const arg of node.namedChildren) {
+          if (arg?.type !== 'identifier' && arg?.type !== 'selector_expression') continue;
+          const refs = resolveCallee(arg, fn, qualifiers, types, functions, methodsByName)
+            .filter(t => !t.external && !t.approximate);
+          for (const target of refs) add({ ...target, reference: true, line: arg.startPosition.row + 1, node: arg });
+        }
+      }
+    });
+
+    calls.push(...out);
+    callsFrom.set(fn.id, out);
+  }
+
+  return {
+    project,
+    functions,
+    calls,
+    callsFrom,
+    entryPoints,
+    calleesOf: (file, call) => (callsAt.get(`${file}:${call.startIndex}`) ?? []).filter(c => !c.reference),
+    functionAt: (file, node) => {
+      for (let n: Node | null = node; n; n = n.parent) {
+        if (n.type === 'function_declaration' || n.type === 'method_declaration') {
+          return declarations.get(`${file}:${n.startIndex}`) ?? null;
+        }
+      }
+      return null;
+    },
+  };
+}
+
+/** Call expressions of a function by line, with the column of their opening parenthesis */
+function callSites(fn: Node): Map<number, Array<{ column: number; node: Node }>> {
+  const sites = new Map<number, Array<{ column: number; node: Node }>>();
+  walk(fn, (node) => {
+    if (node.type !== 'call_expression') return;
+    const args = node.childForFieldName('arguments');
+    if (!args) return;
+    const line = args.startPosition.row + 1;
+    if (!sites.has(line)) sites.set(line, []);
+    sites.get(line)!.push({ column: args.startPosition.column, node });
+  });
+  return sites;
+}
+
+/** Function ids reachable from the given roots (defaults to the entry points) */
+export function reachableFunctions(graph: GoCallGraph, roots: string[] = graph.entryPoints): Map<string, string | null> {
+  // Each reached function maps to the caller it was first reached from, for reconstructing a path
+  const parent = new Map<string, string | null>();
+  const queue: string[] = [];
+  for (const root of roots) {
+    if (!parent.has(root)) {
+      parent.set(root, null);
+      queue.push(root);
+    }
+  }
+  while (queue.length > 0) {
+    const id = queue.shift()!;
+    for (const call of graph.callsFrom.get(id) ?? []) {
+      if (call.external || parent.has(call.callee)) continue;
+      parent.set(call.callee, id);
+      queue.push(call.callee);
+    }
+  }
+  return parent;
+}
+
+/** Path from an entry point to id, using the parents from reachableFunctions() */
+export function callPath(parents: Map<string, string | null>, id: string): string[] {
+  const path: string[] = [];
+  let current: string | null | undefined = id;
+  while (current) {
+    path.unshift(current);
+    current = parents.get(current);
+  }
+  return path;
+}
+
+type ResolvedCallee = Pick<GoCall, 'callee' | 'external' | 'approximate'>;
+
+function resolveCallee(
+  callee: Node | null,
+  fn: GoFunction,
+  qualifiers: Map<string, string>,
+  types: Map<string, string>,
+  functions: Map<string, GoFunction>,
+  methodsByName: Map<string, string[]>
+): ResolvedCallee[] {
+  if (!callee) return [];
+  while (callee.type === 'parenthesized_expression' || callee.type === 'index_expression' || callee.type === 'generic_type') {
+    const inner: Node | null = callee.type === 'parenthesized_expression'
+      ? callee.namedChildren[0] ?? null
+      : callee.childForFieldName('operand') ?? callee.childForFieldName('type');
+    if (!inner) return [];
+    callee = inner;
+  }
+
+  if (callee.type === 'identifier') {
+    if (types.has(callee.text)) return [];
+    const id = `${fn.package}.${callee.text}`;
+    return functions.has(id) ? [{ callee: id, external: false, approximate: false }] : [];
+  }
+  if (callee.type !== 'selector_expression') return [];
+
+  const operand = callee.childForFieldName('operand');
+  const name = callee.childForFieldName('field')?.text;
+  if (!operand || !name) return [];
+
+  // pkg.Func
+  if (operand.type === 'identifier' && qualifiers.has(operand.text) && !types.has(operand.text)) {
+    const id = `${qualifiers.get(operand.text)}.${name}`;
+    return [{ callee: id, external: !functions.has(id), approximate: false }];
+  }
+
+  // x.Method with a known receiver type
+  const typeKey = operand.type === 'identifier' ? types.get(operand.text) : undefined;
+  if (typeKey) {
+    const id = `${typeKey}.${name}`;
+    if (functions.has(id)) return [{ callee: id, external: false, approximate: false }];
+    if (!isProjectType(typeKey, functions)) return [{ callee: id, external: true, approximate: false }];
+  }
+
+  const candidates = methodsByName.get(name) ?? [];
+  if (candidates.length === 0) return [{ callee: typeKey ? `${typeKey}.${name}` : `?.${name}`, external: true, approximate: !typeKey }];
+  return candidates.map(id => ({ callee: id, external: false, approximate: true }));
+}
+
+function isProjectType(typeKey: string, functions: Map<string, GoFunction>): boolean {
+  const prefix = `${typeKey}.`;
+  for (const id of functions.keys()) {
+    if (id.startsWith(prefix)) return true;
+  }
+  return false;
+}
+
+/** Qualified name of a type expression: "<import path>.Name", stripping pointers and type arguments */
+export function qualifiedTypeName(type: Node | null, pkgKey: string, qualifiers: Map<string, string>): string | null {
+  while (type && (type.type === 'pointer_type' || type.type === 'generic_type' || type.type === 'parenthesized_type')) {
+    type = type.type === 'generic_type' ? type.childForFieldName('type') : type.namedChildren[0] ?? null;
+  }
+  if (!type) return null;
+  if (type.type === 'type_identifier') return `${pkgKey}.${type.text}`;
+  if (type.type === 'qualified_type') {
+    const pkg = type.childForFieldName('package')?.text;
+    const name = type.childForFieldName('name')?.text;
+    if (pkg && name) return `${qualifiers.get(pkg) ?? pkg}.${name}`;
+  }
+  return null;
+}
+
+function receiverInfo(method: Node, pkgKey: string, qualifiers: Map<string, string>): { name?: string; type: string } | null {
+  const param = method.childForFieldName('receiver')?.namedChildren.find(n => n?.type === 'parameter_declaration');
+  const type = qualifiedTypeName(param?.childForFieldName('type') ?? null, pkgKey, qualifiers);
+  if (!type) return null;
+  return { name: param?.childForFieldName('name')?.text, type: type.slice(pkgKey.length + 1) };
+}
+
+function parameters(list: Node | null, pkgKey: string, qualifiers: Map<string, string>): { names: string[]; types: string[] } {
+  const names: string[] = [];
+  const types: string[] = [];
+  for (const param of list?.namedChildren ?? []) {
+    if (param?.type !== 'parameter_declaration' && param?.type !== 'variadic_parameter_declaration') continue;
+    const type = qualifiedTypeName(param.childForFieldName('type'), pkgKey, qualifiers) ?? param.childForFieldName('type')?.text ?? '';
+    const idents = param.childrenForFieldName('name').filter((n): n is Node => n !== null);
+    if (idents.length === 0) {
+      names.push('_');
+      types.push(type);
+    }
+    for (const ident of idents) {
+      names.push(ident.text);
+      types.push(type);
+    }
+  }
+  return { names, types };
+}
+
+/** Variables of a function whose type is evident: receiver, parameters, x := T{} / &T{} / new(T) */
+function localTypes(fn: GoFunction, qualifiers: Map<string, string>): Map<string, string> {
+  const types = new Map<string, string>();
+  if (fn.receiverName && fn.receiver) types.set(fn.receiverName, `${fn.package}.${fn.receiver}`);
+  fn.params.forEach((name, i) => {
+    if (name !== '_' && /^[^\s[\]*()]+\.\w+$/.test(fn.paramTypes[i])) types.set(name, fn.paramTypes[i]);
+  });
+
+  walk(fn.node, (node) => {
+    if (node.type === 'parameter_declaration' && node.parent?.parent?.type === 'func_literal') {
+      const type = qualifiedTypeName(node.childForFieldName('type'), fn.package, qualifiers);
+      for (const ident of node.childrenForFieldName('name')) {
+        if (ident && type) types.set(ident.text, type);
+      }
+    }
+    if (node.type === 'var_spec') {
+      const type = qualifiedTypeName(node.childForFieldName('type'), fn.package, qualifiers);
+      for (const ident of node.childrenForFieldName('name')) {
+        if (ident && type) types.set(ident.text, type);
+      }
+    }
+    if (node.type !== 'short_var_declaration') return;
+    const left = node.childForFieldName('left')?.namedChildren ?? [];
+    const right = node.childForFieldName('right')?.namedChildren ?? [];
+    left.forEach((ident, i) => {
+      let value = right[i] ?? null;
+      if (!ident || ident.type !== 'identifier' || !value) return;
+      if (value.type === 'unary_expression' && value.childForFieldName('operator')?.text === '&') {
+        value = value.childForFieldName('operand');
+      }
+      let type: string | null = null;
+      if (value?.type === 'composite_literal') {
+        type = qualifiedTypeName(value.childForFieldName('type'), fn.package, qualifiers);
+      } else if (value?.type === 'call_expression' && value.childForFieldName('function')?.text === 'new') {
+        type = qualifiedTypeName(value.childForFieldName('arguments')?.namedChildren[0] ?? null, fn.package, qualifiers);
+      }
+      if (type) types.set(ident.text, type);
+    });
+  });
+  return types;
+}
+
+/** The file a function is declared in */
+export function functionFile(graph: GoCallGraph, fn: GoFunction): GoSourceFile {
+  return graph.project.packages.get(fn.dir)!.files.find(f => f.file === fn.file)!;
+}
+
+/**
+ * Functions a name given on the command line refers to: a function id, or
+ * its tail — "handlers.Server.Get", "cmd/api.main", "main.main" — with the
+ * package named by its Go name or the end of its import path. (*T).M is
+ * taken as T.M.
+ */
+export function findFunctions(graph: GoCallGraph, spec: string): GoFunction[] {
+  const name = spec.replace(/\(\*?([^)]+)\)\./, '$1.');
+  const exact = graph.functions.get(name);
+  if (exact) return [exact];
+  return [...graph.functions.values()].filter(fn => {
+    const local = fn.receiver ? `${fn.receiver}.${fn.name}` : fn.name;
+    const pkgName = graph.project.packages.get(fn.dir)?.name;
+    return fn.id.endsWith(`/${name}`) || name === local || name === `${pkgName}.${local}`;
+  }).sort((a, b) => a.id.localeCompare(b.id));
+}
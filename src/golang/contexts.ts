@@ -0,0 +1,141 @@
+import { loadGoProject } from './packages.js';
+import { loadGoCallGraph, type GoCall, type GoCallGraph, type GoFunction } from './callgraph.js';
+
+/**
+ * Dropped contexts: call chains that start in a function that has a
+ * context.Context — or an *http.Request, whose Context() it could pass on —
+ * and end in a function that calls context.Background() or context.TODO()
+ * instead. Every function in between takes no context, so cancellation,
+ * deadlines and request-scoped values stop at the first of them.
+ *
+ * Only chains crossing a package boundary are reported: inside one package
+ * the fix is local, across packages it is an API the caller can't thread
+ * a context through.
+ */
+
+const CONTEXT_CREATORS = new Set(['context.Background', 'context.TODO']);
+const CONTEXT_TYPES = new Set(['context.Context', 'net/http.Request']);
+
+export interface ContextStep {
+  function: string;
+  package: string;
+  /** The call to the next function in the chain, or to context.Background/TODO for the last */
+  file: string;
+  line: number;
+}
+
+export interface ContextFinding {
+  /** Function that has a context */
+  caller: string;
+  /** Function that creates a fresh one */
+  creator: string;
+  /** "context.Background" or "context.TODO" */
+  call: string;
+  file: string;
+  line: number;
+  /** caller → … → creator */
+  chain: ContextStep[];
+  /** Packages the chain passes through, in order */
+  packages: string[];
+  /** Some call in the chain was matched by method name only */
+  approximate: boolean;
+}
+
+export interface ContextReport {
+  projectRoot: string;
+  findings: ContextFinding[];
+  summary: { functions: number; withContext: number; creators: number; findings: number };
+  warnings: string[];
+}
+
+export interface ContextOptions {
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+  signal?: AbortSignal;
+}
+
+export async function analyzeContextPropagation(projectRoot: string, options: ContextOptions = {}): Promise<ContextReport> {
+  const project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  const findings = droppedContexts(graph);
+  const functions = [...graph.functions.values()];
+  return {
+    projectRoot,
+    findings,
+    summary: {
+      functions: functions.length,
+      withContext: functions.filter(hasContext).length,
+      creators: functions.filter(f => creations(graph, f).length > 0).length,
+      findings: findings.length,
+    },
+    warnings,
+  };
+}
+
+export function droppedContexts(graph: GoCallGraph): ContextFinding[] {
+  const callers = new Map<string, GoCall[]>();
+  for (const call of graph.calls) {
+    if (call.external || call.reference) continue;
+    if (!callers.has(call.callee)) callers.set(call.callee, []);
+    callers.get(call.callee)!.push(call);
+  }
+
+  const findings: ContextFinding[] = [];
+  for (const creator of graph.functions.values()) {
+    // A function with a context that creates one anyway is a local fix, not a dropped chain
+    if (hasContext(creator)) continue;
+    const created = creations(graph, creator)[0];
+    if (!created) continue;
+
+    // Breadth-first up the callers through functions without a context; the first call into
+    // each one is its shortest way down to the creator
+    const next = new Map<string, GoCall | null>([[creator.id, null]]);
+    const queue = [creator.id];
+    while (queue.length > 0) {
+      const id = queue.shift()!;
+      for (const call of callers.get(id) ?? []) {
+        if (next.has(call.caller)) continue;
+        next.set(call.caller, call);
+        const caller = graph.functions.get(call.caller)!;
+        if (!hasContext(caller)) {
+          queue.push(caller.id);
+          continue;
+        }
+        const finding = chainFinding(graph, caller, creator, created, next);
+        if (new Set(finding.packages).size > 1) findings.push(finding);
+      }
+    }
+  }
+  return findings.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line || a.caller.localeCompare(b.caller));
+}
+
+function chainFinding(graph: GoCallGraph, caller: GoFunction, creator: GoFunction, created: GoCall, next: Map<string, GoCall | null>): ContextFinding {
+  const chain: ContextStep[] = [];
+  let approximate = false;
+  for (let call = next.get(caller.id) ?? null; call; call = next.get(call.callee) ?? null) {
+    const fn = graph.functions.get(call.caller)!;
+    chain.push({ function: fn.id, package: fn.package, file: call.file, line: call.line });
+    approximate ||= call.approximate;
+  }
+  chain.push({ function: creator.id, package: creator.package, file: created.file, line: created.line });
+  const packages = chain.map(s => s.package).filter((pkg, i, all) => i === 0 || all[i - 1] !== pkg);
+  return {
+    caller: caller.id,
+    creator: creator.id,
+    call: created.callee,
+    file: created.file,
+    line: created.line,
+    chain,
+    packages,
+    approximate,
+  };
+}
+
+function hasContext(fn: GoFunction): boolean {
+  return fn.paramTypes.some(t => CONTEXT_TYPES.has(t));
+}
+
+function creations(graph: GoCallGraph, fn: GoFunction): GoCall[] {
+  return (graph.callsFrom.get(fn.id) ?? []).filter(c => c.external && CONTEXT_CREATORS.has(c.callee));
+}
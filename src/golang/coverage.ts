@@ -0,0 +1,172 @@
+import { readFileSync } from 'fs';
+import { posix } from 'path';
+import type { DirectedGraph } from 'graphology';
+import type { ExportGraph } from '../export/graph.js';
+import { packageOf } from '../graph/model.js';
+import { findGoModules, GoModuleIndex } from './modules.js';
+
+/**
+ * Statement coverage from `go test -coverprofile` files, per project file
+ * and package, and the concentration risks it shows: packages much of the
+ * workspace depends on, directly or through others, whose tests cover
+ * little of them. A bug there reaches every dependent, and little catches
+ * it first.
+ *
+ * Profiles name files by import path (example.com/app/store/db.go); blocks
+ * repeated across profiles, or within one from -coverpkg, count once,
+ * covered when any run covered them.
+ */
+
+export interface CoverageCount {
+  statements: number;
+  covered: number;
+}
+
+export interface CoverageData {
+  /** set, count or atomic */
+  mode: string;
+  /** Project-relative file → its statements */
+  files: Map<string, CoverageCount>;
+  /** Blocks of files outside the project's modules */
+  outside: number;
+}
+
+export interface ConcentrationRisk {
+  /** Package directory */
+  package: string;
+  /** Covered statements, 0–100 */
+  coverage: number;
+  statements: number;
+  /** Workspace packages that import it */
+  fanIn: number;
+  /** Workspace packages that depend on it directly or transitively */
+  dependents: number;
+  /** dependents × the uncovered share: how much of the workspace rests on untested code */
+  risk: number;
+}
+
+export interface CoverageRiskOptions {
+  /** Packages covered below this percentage are risks (default 50) */
+  maxCoverage?: number;
+  /** Only packages at least this many packages depend on are risks (default 3) */
+  minDependents?: number;
+}
+
+// file:startLine.startCol,endLine.endCol statements count
+const BLOCK = /^(.+):(\d+\.\d+,\d+\.\d+) (\d+) (\d+)$/;
+
+/** Parse coverage profiles, each a name (for errors) and its content */
+export function parseCoverProfiles(projectRoot: string, profiles: Array<{ name: string; content: string }>): CoverageData {
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const blocks = new Map<string, { file: string; statements: number; covered: boolean }>();
+  const dirs = new Map<string, string | null>();
+  let mode = '';
+  let outside = 0;
+
+  for (const { name, content } of profiles) {
+    const lines = content.split('\n');
+    const header = /^mode: (\w+)/.exec(lines[0] ?? '');
+    if (!header) throw new Error(`${name} is not a Go coverage profile (no "mode:" line)`);
+    mode ||= header[1];
+    for (const line of lines.slice(1)) {
+      const match = BLOCK.exec(line.trim());
+      if (!match) continue;
+      const [, file, range, statements, count] = match;
+      const pkg = posix.dirname(file);
+      if (!dirs.has(pkg)) dirs.set(pkg, index.dirForImport(pkg));
+      const dir = dirs.get(pkg)!;
+      if (dir === null) {
+        outside++;
+        continue;
+      }
+      const path = dir === '.' ? posix.basename(file) : `${dir}/${posix.basename(file)}`;
+      const key = `${path}:${range}`;
+      const block = blocks.get(key);
+      if (block) block.covered ||= count !== '0';
+      else blocks.set(key, { file: path, statements: parseInt(statements, 10), covered: count !== '0' });
+    }
+  }
+
+  const files = new Map<string, CoverageCount>();
+  for (const block of blocks.values()) {
+    const entry = files.get(block.file) ?? { statements: 0, covered: 0 };
+    entry.statements += block.statements;
+    if (block.covered) entry.covered += block.statements;
+    files.set(block.file, entry);
+  }
+  return { mode, files, outside };
+}
+
+export function readCoverProfiles(projectRoot: string, files: string[]): CoverageData {
+  return parseCoverProfiles(projectRoot, files.map(name => ({ name, content: readFileSync(name, 'utf-8') })));
+}
+
+/** Statements per package directory */
+export function packageCoverage(coverage: CoverageData): Map<string, CoverageCount> {
+  const packages = new Map<string, CoverageCount>();
+  for (const [file, count] of coverage.files) {
+    const pkg = posix.dirname(file);
+    const entry = packages.get(pkg) ?? { statements: 0, covered: 0 };
+    entry.statements += count.statements;
+    entry.covered += count.covered;
+    packages.set(pkg, entry);
+  }
+  return packages;
+}
+
+export const percentCovered = (count: CoverageCount) => count.statements === 0 ? 100 : 100 * count.covered / count.statements;
+
+/**
+ * Workspace Go packages with functions that the profiles have no entry for:
+ * no tests ran, or -coverpkg left them out. Their coverage is unknown
+ * rather than 0%, so concentrationRisks doesn't judge them and reports
+ * list them on their own. Given the package graph, only its packages count.
+ */
+export function unprofiledPackages(graph: DirectedGraph, coverage: CoverageData, packageGraph?: ExportGraph): string[] {
+  const profiled = packageCoverage(coverage);
+  const packages = new Set<string>();
+  graph.forEachNode((_node, attrs) => {
+    const file = String(attrs.filePath);
+    if (!file.endsWith('.go') || file.endsWith('_test.go') || (attrs.kind !== 'function' && attrs.kind !== 'method')) return;
+    const pkg = packageOf(file);
+    if (!profiled.has(pkg) && (!packageGraph || packageGraph.hasNode(pkg))) packages.add(pkg);
+  });
+  return [...packages].sort();
+}
+
+/**
+ * Packages of a package-level export graph that are poorly covered and
+ * heavily depended on, riskiest first. Packages the profiles don't cover
+ * at all (no tests run, or left out of -coverpkg) aren't judged; see
+ * unprofiledPackages.
+ */
+export function concentrationRisks(graph: ExportGraph, coverage: CoverageData, options: CoverageRiskOptions = {}): ConcentrationRisk[] {
+  const maxCoverage = options.maxCoverage ?? 50;
+  const minDependents = options.minDependents ?? 3;
+  const packages = packageCoverage(coverage);
+  const risks: ConcentrationRisk[] = [];
+  graph.forEachNode((pkg, attrs) => {
+    const count = packages.get(pkg);
+    if (attrs.external || !count || count.statements === 0) return;
+    const percent = percentCovered(count);
+    if (percent >= maxCoverage) return;
+    const dependents = transitiveDependents(graph, pkg);
+    if (dependents < minDependents) return;
+    const fanIn = graph.inNeighbors(pkg).filter(n => n !== pkg).length;
+    risks.push({ package: pkg, coverage: percent, statements: count.statements, fanIn, dependents, risk: dependents * (1 - percent / 100) });
+  });
+  return risks.sort((a, b) => b.risk - a.risk || a.package.localeCompare(b.package));
+}
+
+function transitiveDependents(graph: ExportGraph, pkg: string): number {
+  const seen = new Set<string>([pkg]);
+  const queue = [pkg];
+  while (queue.length > 0) {
+    for (const dependent of graph.inNeighbors(queue.shift()!)) {
+      if (seen.has(dependent)) continue;
+      seen.add(dependent);
+      queue.push(dependent);
+    }
+  }
+  return seen.size - 1;
+}
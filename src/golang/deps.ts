@@ -0,0 +1,180 @@
+import { existsSync, readFileSync, readdirSync } from 'fs';
+import { join, relative } from 'path';
+import { initParser } from '../parser/wasm-init.js';
+import { runGo, findGoModuleRoot } from './toolchain.js';
+import { parseGoSource, goImports, type GoImport, type GoSourceFile } from './source.js';
+import type { GoPackage, GoProject } from './packages.js';
+
+/**
+ * Sources of third-party packages a project builds with. `go list -deps`
+ * gives the exact set (and the module cache directory of each); without a
+ * toolchain, vendor/ is used when present.
+ */
+
+/** The fields of `go list -json` output Depwire uses */
+export interface GoListPackage {
+  ImportPath: string;
+  Dir?: string;
+  Name?: string;
+  GoFiles?: string[];
+  CgoFiles?: string[];
+  Imports?: string[];
+  Standard?: boolean;
+  DepOnly?: boolean;
+  Module?: { Path: string; Version?: string; Dir?: string; Main?: boolean };
+  Error?: { Err: string };
+}
+
+export interface GoDependencyModule {
+  path: string;
+  version: string;
+}
+
+export interface GoDependencies {
+  /** Third-party packages, keyed like project packages ("<module>@<version>/<subdir>") */
+  packages: GoPackage[];
+  modules: GoDependencyModule[];
+  /** Every package in the build, standard library included, as reported by go list */
+  listed: GoListPackage[];
+  via: 'go list' | 'vendor' | 'none';
+  warnings: string[];
+}
+
+/** Run `go list -deps -json` for the given patterns (with `flags` such as -test) and parse the stream of JSON objects */
+export async function goListDeps(moduleRoot: string, patterns: string[] = ['./...'], signal?: AbortSignal, flags: string[] = []): Promise<GoListPackage[]> {
+  const result = await runGo(['list', '-deps', '-e', '-json', ...flags, ...patterns], { cwd: moduleRoot, signal });
+  if (result.exitCode !== 0 && !result.stdout.trim()) {
+    throw new Error(`go list failed: ${result.stderr.trim().split('\n')[0]}`);
+  }
+  return parseJsonStream(result.stdout) as GoListPackage[];
+}
+
+/** `go list -json` and `go mod download -json` print objects back to back, not an array */
+export function parseJsonStream(output: string): unknown[] {
+  const objects: unknown[] = [];
+  let depth = 0;
+  let start = -1;
+  let inString = false;
+  for (let i = 0; i < output.length; i++) {
+    const ch = output[i];
+    if (inString) {
+      if (ch === '\\') i++;
+      else if (ch === '"') inString = false;
+      continue;
+    }
+    if (ch === '"') inString = true;
+    else if (ch === '{') {
+      if (depth++ === 0) start = i;
+    } else if (ch === '}' && --depth === 0) {
+      objects.push(JSON.parse(output.slice(start, i + 1)));
+    }
+  }
+  return objects;
+}
+
+export async function loadGoDependencies(projectRoot: string, options: { signal?: AbortSignal } = {}): Promise<GoDependencies> {
+  await initParser();
+  const warnings: string[] = [];
+  const moduleRoot = findGoModuleRoot(projectRoot);
+  if (!moduleRoot) {
+    return { packages: [], modules: [], listed: [], via: 'none', warnings: ['No go.mod found — third-party packages not analyzed'] };
+  }
+
+  let listed: GoListPackage[] | null = null;
+  try {
+    listed = await goListDeps(moduleRoot, ['./...'], options.signal);
+  } catch (err) {
+    if (options.signal?.aborted) throw err;
+    warnings.push(`${err instanceof Error ? err.message : err}`);
+  }
+
+  if (listed) {
+    const packages: GoPackage[] = [];
+    const modules = new Map<string, GoDependencyModule>();
+    for (const pkg of listed) {
+      if (pkg.Standard || !pkg.Module || pkg.Module.Main || !pkg.Dir) continue;
+      if (pkg.Error) warnings.push(`${pkg.ImportPath}: ${pkg.Error.Err}`);
+      const version = pkg.Module.Version ?? '';
+      modules.set(`${pkg.Module.Path}@${version}`, { path: pkg.Module.Path, version });
+      const sub = pkg.Module.Dir ? relative(pkg.Module.Dir, pkg.Dir).split('\\').join('/') : '';
+      const dir = `${pkg.Module.Path}@${version}${sub ? `/${sub}` : ''}`;
+      packages.push(readPackage(dir, pkg.ImportPath, pkg.Dir, [...(pkg.GoFiles ?? []), ...(pkg.CgoFiles ?? [])], warnings));
+    }
+    return { packages, modules: [...modules.values()], listed, via: 'go list', warnings };
+  }
+
+  const vendored = loadVendored(moduleRoot, warnings);
+  if (vendored) return { ...vendored, listed: [], via: 'vendor', warnings };
+  warnings.push('Third-party packages not analyzed (no Go toolchain and no vendor directory)');
+  return { packages: [], modules: [], listed: [], via: 'none', warnings };
+}
+
+/** A project with third-party packages added, for analyses that follow calls into dependencies */
+export function withDependencies(project: GoProject, deps: GoDependencies): GoProject {
+  const packages = new Map(project.packages);
+  for (const pkg of deps.packages) packages.set(pkg.dir, pkg);
+  return { ...project, packages };
+}
+
+/** Module path and version of a dependency package dir ("<module>@<version>/<subdir>"), or null for project packages */
+export function dependencyModule(dir: string): GoDependencyModule | null {
+  const match = /^(.+?)@([^/]+)/.exec(dir);
+  return match ? { path: match[1], version: match[2] } : null;
+}
+
+function readPackage(dir: string, importPath: string, absDir: string, fileNames: string[], warnings: string[]): GoPackage {
+  const files: GoSourceFile[] = [];
+  for (const name of fileNames.sort()) {
+    try {
+      files.push(parseGoSource(`${dir}/${name}`, readFileSync(join(absDir, name), 'utf-8')));
+    } catch (err) {
+      warnings.push(`${dir}/${name}: ${err instanceof Error ? err.message : err}`);
+    }
+  }
+  const imports = new Map<string, Array<GoImport & { file: string }>>();
+  for (const file of files) {
+    for (const imp of goImports(file)) {
+      if (!imports.has(imp.path)) imports.set(imp.path, []);
+      imports.get(imp.path)!.push({ ...imp, file: file.file });
+    }
+  }
+  return {
+    dir,
+    importPath,
+    name: files[0]?.packageName ?? '',
+    files,
+    imports,
+    testImports: new Map(),
+  };
+}
+
+// vendor/modules.txt lists "# module version" followed by the packages vendored from it
+function loadVendored(moduleRoot: string, warnings: string[]): Pick<GoDependencies, 'packages' | 'modules'> | null {
+  const manifest = join(moduleRoot, 'vendor', 'modules.txt');
+  if (!existsSync(manifest)) return null;
+
+  const packages: GoPackage[] = [];
+  const modules: GoDependencyModule[] = [];
+  let current: GoDependencyModule | null = null;
+  for (const line of readFileSync(manifest, 'utf-8').split('\n')) {
+    const header = /^# (\S+) (\S+)/.exec(line);
+    if (header) {
+      current = { path: header[1], version: header[2] };
+      modules.push(current);
+      continue;
+    }
+    const importPath = line.trim();
+    if (!current || !importPath || importPath.startsWith('#')) continue;
+    const absDir = join(moduleRoot, 'vendor', importPath);
+    const goFiles = readdirGo(absDir);
+    if (goFiles.length === 0) continue;
+    const sub = importPath.slice(current.path.length).replace(/^\//, '');
+    packages.push(readPackage(`${current.path}@${current.version}${sub ? `/${sub}` : ''}`, importPath, absDir, goFiles, warnings));
+  }
+  return { packages, modules };
+}
+
+function readdirGo(dir: string): string[] {
+  // Build constraints aren't evaluated — every non-test file is included
+  return existsSync(dir) ? readdirSync(dir).filter(f => f.endsWith('.go') && !f.endsWith('_test.go')) : [];
+}
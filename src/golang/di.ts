@@ -0,0 +1,686 @@
+import { readFileSync, readdirSync } from 'fs';
+import { join } from 'path';
+import type { DirectedGraph } from 'graphology';
+import type { Node } from 'web-tree-sitter';
+import { parseGoSource, goDeclarations, walk, type GoSourceFile } from './source.js';
+import { findGoModules, GoModuleIndex } from './modules.js';
+import { fileQualifiers } from './references.js';
+
+/**
+ * Dependency-injection wiring for google/wire, uber/fx and uber/dig.
+ *
+ * With DI, a consumer never imports the implementation it receives — the
+ * container matches constructor parameter types to constructor result types.
+ * This recovers those matches from the source: every provider a container can
+ * see (following wire.NewSet / fx.Options / fx.Module values across packages),
+ * the types each provider consumes and produces, and the consumer → provider
+ * bindings. Types nobody provides are reported as missing providers, which
+ * wire fails on at generate time and fx/dig only at startup.
+ *
+ * Types are matched as written (canonicalized to import paths), so a provider
+ * returning an alias of the consumed type is not recognized.
+ */
+
+export type DiFramework = 'wire' | 'fx' | 'dig';
+
+const FRAMEWORKS: Record<string, DiFramework> = {
+  'github.com/google/wire': 'wire',
+  'go.uber.org/fx': 'fx',
+  'go.uber.org/dig': 'dig',
+};
+
+/** Types fx itself puts in every container */
+const FX_BUILTINS = new Set(['go.uber.org/fx.Lifecycle', 'go.uber.org/fx.Shutdowner', 'go.uber.org/fx.DotGraph']);
+
+export interface DiProvider {
+  framework: DiFramework;
+  /** Graph node ID of the provider function ("file::Name"), or a description for wire.Bind etc. */
+  id: string;
+  name: string;
+  file: string;
+  line: number;
+  provides: string[];
+  consumes: string[];
+  /** Invoked functions consume but provide nothing */
+  invoke?: boolean;
+}
+
+export interface DiContainer {
+  framework: DiFramework;
+  /** Injector function (wire), or the function calling fx.New / dig.New */
+  name: string;
+  file: string;
+  line: number;
+  providers: DiProvider[];
+  /** Wire injector parameters and outputs, as types */
+  inputs: string[];
+  outputs: string[];
+  /** Some registrations couldn't be typed (fx.Supply of a variable, value groups, ...) — missing providers are uncertain */
+  incomplete: boolean;
+}
+
+export interface DiBinding {
+  framework: DiFramework;
+  /** Consumer node ID */
+  consumer: string;
+  /** Provider node ID */
+  provider: string;
+  type: string;
+  file: string;
+  line: number;
+}
+
+export interface DiIssue {
+  framework: DiFramework;
+  kind: 'missing-provider' | 'duplicate-provider';
+  type: string;
+  container: { name: string; file: string; line: number };
+  /** Consumers of the type (missing) or its providers (duplicate) */
+  symbols: string[];
+  message: string;
+  /** False when the container has registrations whose types couldn't be read */
+  certain: boolean;
+}
+
+export interface DiAnalysis {
+  containers: DiContainer[];
+  bindings: DiBinding[];
+  issues: DiIssue[];
+}
+
+interface FileContext {
+  file: GoSourceFile;
+  pkgPath: string;
+  /** Qualifier → import path */
+  imports: Map<string, string>;
+  /** Qualifier → framework */
+  frameworks: Map<string, DiFramework>;
+}
+
+/** Lazily parsed Go packages, by project-relative directory */
+class GoSourceIndex {
+  private readonly dirs = new Map<string, GoSourceFile[]>();
+  /** Files parsed before, by project-relative path */
+  readonly parsed: Map<string, GoSourceFile>;
+  private readonly contexts = new Map<string, FileContext>();
+  private readonly types = new Map<string, Set<string>>();
+  private readonly projectRoot: string;
+  readonly modules: GoModuleIndex;
+
+  constructor(projectRoot: string, modules: GoModuleIndex, parsed: GoSourceFile[] = []) {
+    this.projectRoot = projectRoot;
+    this.modules = modules;
+    this.parsed = new Map(parsed.map(f => [f.file, f]));
+  }
+
+  filesIn(dir: string): GoSourceFile[] {
+    let files = this.dirs.get(dir);
+    if (files) return files;
+    files = [];
+    try {
+      for (const entry of readdirSync(join(this.projectRoot, dir)).sort()) {
+        if (!entry.endsWith('.go') || entry.endsWith('_test.go')) continue;
+        const rel = dir === '.' ? entry : `${dir}/${entry}`;
+        try {
+          files.push(this.parsed.get(rel) ?? parseGoSource(rel, readFileSync(join(this.projectRoot, rel), 'utf-8')));
+        } catch { /* unreadable or unparsable — skip */ }
+      }
+    } catch { /* not a directory in this project */ }
+    this.dirs.set(dir, files);
+    return files;
+  }
+
+  context(file: GoSourceFile): FileContext {
+    let ctx = this.contexts.get(file.file);
+    if (ctx) return ctx;
+    const imports = new Map<string, string>();
+    const frameworks = new Map<string, DiFramework>();
+    // Named as the Go package is, so example.com/store/v2 is store, not v2
+    for (const [qualifier, path] of fileQualifiers(file)) {
+      imports.set(qualifier, path);
+      if (FRAMEWORKS[path]) frameworks.set(qualifier, FRAMEWORKS[path]);
+    }
+    ctx = { file, pkgPath: this.modules.importForDir(file.dir) ?? file.dir, imports, frameworks };
+    this.contexts.set(file.file, ctx);
+    return ctx;
+  }
+
+  declaredTypes(dir: string): Set<string> {
+    let types = this.types.get(dir);
+    if (types) return types;
+    types = new Set();
+    for (const file of this.filesIn(dir)) {
+      for (const decl of goDeclarations(file)) {
+        if (decl.kind === 'type' || decl.kind === 'interface') types.add(decl.name);
+      }
+    }
+    this.types.set(dir, types);
+    return types;
+  }
+
+  /** Find a top-level func, var or type by name in the package at dir */
+  find(dir: string, name: string, kind: 'func' | 'var' | 'type'): { file: GoSourceFile; node: Node } | null {
+    for (const file of this.filesIn(dir)) {
+      for (const decl of goDeclarations(file)) {
+        if (decl.name !== name) continue;
+        if (decl.kind === kind || (kind === 'type' && decl.kind === 'interface')) return { file, node: decl.node };
+      }
+    }
+    return null;
+  }
+
+  /** Directory of the package an identifier reference points to ("Foo" or "pkg.Foo") */
+  resolve(ctx: FileContext, expr: Node): { dir: string; name: string } | null {
+    if (expr.type === 'identifier') return { dir: ctx.file.dir, name: expr.text };
+    if (expr.type === 'selector_expression') {
+      const operand = expr.childForFieldName('operand');
+      const field = expr.childForFieldName('field')?.text;
+      const path = operand?.type === 'identifier' ? ctx.imports.get(operand.text) : undefined;
+      const dir = path ? this.modules.dirForImport(path) : null;
+      if (dir && field) return { dir, name: field };
+    }
+    return null;
+  }
+}
+
+/**
+ * Analyze DI wiring in the given Go files (files that don't import a DI
+ * framework are skipped cheaply). Files already parsed are passed as
+ * parsed, so they aren't parsed again. The tree-sitter parser must already
+ * be initialized.
+ */
+export function analyzeDiWiring(projectRoot: string, goFiles: string[], parsed: GoSourceFile[] = []): DiAnalysis {
+  const index = new GoSourceIndex(projectRoot, new GoModuleIndex(findGoModules(projectRoot)), parsed);
+  const containers: DiContainer[] = [];
+
+  for (const path of goFiles) {
+    if (path.endsWith('_test.go')) continue;
+    let source: string;
+    try {
+      source = index.parsed.get(path)?.source ?? readFileSync(join(projectRoot, path), 'utf-8');
+    } catch {
+      continue;
+    }
+    if (!Object.keys(FRAMEWORKS).some(f => source.includes(`"${f}"`))) continue;
+
+    const dir = path.includes('/') ? path.slice(0, path.lastIndexOf('/')) : '.';
+    const file = index.filesIn(dir).find(f => f.file === path) ?? index.parsed.get(path) ?? parseGoSource(path, source);
+    containers.push(...findContainers(index, index.context(file)));
+  }
+
+  const bindings: DiBinding[] = [];
+  const issues: DiIssue[] = [];
+  for (const container of containers) {
+    resolveContainer(container, bindings, issues);
+  }
+  return { containers, bindings, issues };
+}
+
+/**
+ * Add consumer → provider edges (kind 'consumes') for DI bindings to a
+ * built graph, given its Go files parsed (parseGoSources). Runs only for
+ * projects with Go files.
+ */
+export function detectDiEdges(sources: GoSourceFile[], projectRoot: string, graph: DirectedGraph): DiAnalysis | null {
+  if (sources.length === 0) return null;
+
+  const analysis = analyzeDiWiring(projectRoot, sources.map(s => s.file), sources);
+  for (const binding of analysis.bindings) {
+    if (!graph.hasNode(binding.consumer) || !graph.hasNode(binding.provider)) continue;
+    if (graph.hasEdge(binding.consumer, binding.provider)) continue;
+    graph.addEdge(binding.consumer, binding.provider, {
+      kind: 'consumes',
+      filePath: binding.file,
+      line: binding.line,
+      framework: binding.framework,
+      injectedType: binding.type,
+    });
+  }
+  return analysis;
+}
+
+// ── Container discovery ────────────────────────────────────────────
+
+function callee(ctx: FileContext, call: Node): { framework: DiFramework; name: string } | null {
+  const fn = call.childForFieldName('function');
+  if (fn?.type !== 'selector_expression') return null;
+  const operand = fn.childForFieldName('operand');
+  const framework = operand?.type === 'identifier' ? ctx.frameworks.get(operand.text) : undefined;
+  const name = fn.childForFieldName('field')?.text;
+  return framework && name ? { framework, name } : null;
+}
+
+function callArgs(call: Node): Node[] {
+  return (call.childForFieldName('arguments')?.namedChildren ?? []).filter((n): n is Node => Boolean(n));
+}
+
+function enclosingFunction(node: Node): Node | null {
+  let current: Node | null = node.parent;
+  while (current && current.type !== 'function_declaration' && current.type !== 'method_declaration') {
+    current = current.parent;
+  }
+  return current;
+}
+
+function findContainers(index: GoSourceIndex, ctx: FileContext): DiContainer[] {
+  const containers: DiContainer[] = [];
+
+  for (const decl of ctx.file.root.namedChildren) {
+    if (decl?.type !== 'function_declaration' && decl?.type !== 'method_declaration') continue;
+    const fnName = decl.childForFieldName('name')?.text ?? '';
+    // dig containers: variables assigned from dig.New() in this function
+    const digVars = new Map<string, DiContainer>();
+
+    walk(decl, (node) => {
+      if (node.type === 'short_var_declaration' || node.type === 'assignment_statement') {
+        const right = node.childForFieldName('right')?.namedChildren[0];
+        const left = node.childForFieldName('left')?.namedChildren[0];
+        if (right?.type === 'call_expression' && left?.type === 'identifier') {
+          const c = callee(ctx, right);
+          if (c?.framework === 'dig' && c.name === 'New') {
+            const container = newContainer('dig', fnName, ctx, node);
+            digVars.set(left.text, container);
+            containers.push(container);
+          }
+        }
+      }
+      if (node.type !== 'call_expression') return;
+
+      const c = callee(ctx, node);
+      if (c?.framework === 'wire' && c.name === 'Build') {
+        const container = newContainer('wire', fnName, ctx, decl);
+        const { params, results } = signatureTypes(index, ctx, decl);
+        container.inputs = params;
+        // Injectors return (T), (T, error) or (T, func(), error)
+        container.outputs = results.filter(t => t !== 'func()');
+        expand(index, ctx, callArgs(node), container, 'provide', new Set());
+        containers.push(container);
+        return false;
+      }
+      if (c?.framework === 'fx' && c.name === 'New') {
+        const container = newContainer('fx', fnName, ctx, node);
+        expand(index, ctx, callArgs(node), container, 'options', new Set());
+        containers.push(container);
+        return false;
+      }
+
+      // c.Provide(...) / c.Invoke(...) on a dig container
+      const fn = node.childForFieldName('function');
+      const operand = fn?.type === 'selector_expression' ? fn.childForFieldName('operand') : null;
+      const method = fn?.childForFieldName('field')?.text;
+      const container = operand?.type === 'identifier' ? digVars.get(operand.text) : undefined;
+      if (container && (method === 'Provide' || method === 'Invoke')) {
+        const [arg] = callArgs(node);
+        if (arg) expand(index, ctx, [arg], container, method === 'Provide' ? 'provide' : 'invoke', new Set());
+      }
+    });
+  }
+
+  return containers;
+}
+
+function newContainer(framework: DiFramework, name: string, ctx: FileContext, node: Node): DiContainer {
+  return {
+    framework,
+    name: name || '(anonymous)',
+    file: ctx.file.file,
+    line: node.startPosition.row + 1,
+    providers: [],
+    inputs: [],
+    outputs: [],
+    incomplete: false,
+  };
+}
+
+type Mode = 'options' | 'provide' | 'invoke';
+
+/**
+ * Add the registrations in a list of expressions to a container.
+ * 'options' is fx.New / fx.Options context, 'provide' a provider list
+ * (wire.NewSet, fx.Provide, dig Provide), 'invoke' an fx.Invoke list.
+ */
+function expand(index: GoSourceIndex, ctx: FileContext, args: Node[], container: DiContainer, mode: Mode, seen: Set<string>): void {
+  for (const arg of args) {
+    if (arg.type === 'call_expression') {
+      const c = callee(ctx, arg);
+      const inner = callArgs(arg);
+      switch (c?.name) {
+        case 'NewSet':
+        case 'Options':
+          expand(index, ctx, inner, container, c.name === 'Options' ? 'options' : 'provide', seen);
+          continue;
+        case 'Module':
+          expand(index, ctx, inner.slice(1), container, 'options', seen);
+          continue;
+        case 'Provide':
+          expand(index, ctx, inner, container, 'provide', seen);
+          continue;
+        case 'Invoke':
+          expand(index, ctx, inner, container, 'invoke', seen);
+          continue;
+        case 'Bind':
+        case 'InterfaceValue': {
+          // wire.Bind(new(Iface), new(*Impl)): Iface is satisfied by whatever provides *Impl
+          const iface = newType(index, ctx, inner[0]);
+          const impl = c.name === 'Bind' ? newType(index, ctx, inner[1]) : null;
+          if (iface) {
+            container.providers.push(pseudoProvider(container, ctx, arg, `wire.${c.name}`, [iface], impl ? [impl] : []));
+          }
+          continue;
+        }
+        case 'Struct': {
+          const type = newType(index, ctx, inner[0]);
+          if (type) container.providers.push(pseudoProvider(container, ctx, arg, 'wire.Struct', [type], []));
+          container.incomplete = true; // field dependencies aren't read
+          continue;
+        }
+        case 'Value':
+        case 'Supply':
+          for (const value of inner) {
+            const type = literalType(index, ctx, value);
+            if (type) container.providers.push(pseudoProvider(container, ctx, value, `${container.framework}.${c.name}`, [type], []));
+            else container.incomplete = true;
+          }
+          continue;
+        case 'Annotate':
+          annotated(index, ctx, arg, container, mode);
+          continue;
+        case 'FieldsOf':
+        case 'Decorate':
+        case 'Replace':
+          container.incomplete = true;
+          continue;
+      }
+      if (c) continue; // other fx options (fx.WithLogger, fx.StartTimeout, ...)
+      container.incomplete = true;
+      continue;
+    }
+
+    if (arg.type === 'func_literal') {
+      addFunction(index, ctx, arg, arg, container, mode === 'invoke', '(func literal)');
+      continue;
+    }
+
+    const ref = index.resolve(ctx, arg);
+    if (!ref) {
+      container.incomplete = true;
+      continue;
+    }
+    const key = `${ref.dir}::${ref.name}`;
+    const fn = index.find(ref.dir, ref.name, 'func');
+    if (fn && mode !== 'options') {
+      addFunction(index, index.context(fn.file), fn.node, arg, container, mode === 'invoke', ref.name, ctx);
+      continue;
+    }
+    // A package-level set: var Set = wire.NewSet(...) / var Module = fx.Options(...)
+    const v = index.find(ref.dir, ref.name, 'var');
+    const value = v?.node.childForFieldName('value')?.namedChildren[0];
+    if (v && value && !seen.has(key)) {
+      seen.add(key);
+      expand(index, index.context(v.file), [value], container, mode, seen);
+      continue;
+    }
+    if (!seen.has(key)) container.incomplete = true;
+  }
+}
+
+function pseudoProvider(container: DiContainer, ctx: FileContext, node: Node, name: string, provides: string[], consumes: string[]): DiProvider {
+  return {
+    framework: container.framework,
+    id: `${ctx.file.file}::${enclosingFunction(node)?.childForFieldName('name')?.text ?? '__file__'}`,
+    name,
+    file: ctx.file.file,
+    line: node.startPosition.row + 1,
+    provides,
+    consumes,
+  };
+}
+
+function addFunction(
+  index: GoSourceIndex,
+  declCtx: FileContext,
+  fn: Node,
+  site: Node,
+  container: DiContainer,
+  invoke: boolean,
+  name: string,
+  siteCtx: FileContext = declCtx
+): void {
+  const { params, results, groups } = signatureTypes(index, declCtx, fn);
+  if (groups) container.incomplete = true;
+  const isLiteral = fn.type === 'func_literal';
+  const id = isLiteral
+    ? `${siteCtx.file.file}::${enclosingFunction(site)?.childForFieldName('name')?.text ?? '__file__'}`
+    : `${declCtx.file.file}::${name}`;
+  container.providers.push({
+    framework: container.framework,
+    id,
+    name,
+    file: isLiteral ? siteCtx.file.file : declCtx.file.file,
+    line: (isLiteral ? site : fn).startPosition.row + 1,
+    provides: invoke ? [] : results.filter(t => t !== 'func()' || container.framework !== 'wire'),
+    consumes: params,
+    invoke: invoke || undefined,
+  });
+}
+
+/** fx.Annotate(fn, fx.As(new(I)), ...): the function, providing I instead of its own result */
+function annotated(index: GoSourceIndex, ctx: FileContext, call: Node, container: DiContainer, mode: Mode): void {
+  const [target, ...annotations] = callArgs(call);
+  if (!target) return;
+  const before = container.providers.length;
+  expand(index, ctx, [target], container, mode === 'invoke' ? 'invoke' : 'provide', new Set());
+  const provider = container.providers.length > before ? container.providers[container.providers.length - 1] : null;
+  if (!provider) return;
+
+  const as: string[] = [];
+  let self = false;
+  for (const annotation of annotations) {
+    const c = annotation.type === 'call_expression' ? callee(ctx, annotation) : null;
+    if (c?.name === 'As') {
+      for (const arg of callArgs(annotation)) {
+        const c2 = arg.type === 'call_expression' ? callee(ctx, arg) : null;
+        if (c2?.name === 'Self') self = true;
+        const type = newType(index, ctx, arg);
+        if (type) as.push(type);
+      }
+    } else {
+      // ParamTags / ResultTags name values — types alone no longer identify them
+      container.incomplete = true;
+    }
+  }
+  if (as.length > 0) {
+    provider.provides = self ? [...provider.provides, ...as] : as;
+  }
+}
+
+// ── Types ──────────────────────────────────────────────────────────
+
+/** Canonical text of a type: package-qualified names become import paths */
+function canonicalType(index: GoSourceIndex, ctx: FileContext, node: Node): string {
+  const local = index.declaredTypes(ctx.file.dir);
+  const base = node.startIndex;
+  const text = node.text;
+  let out = '';
+  let last = base;
+  walk(node, (child) => {
+    if (child.type === 'qualified_type') {
+      const pkg = child.childForFieldName('package')?.text ?? '';
+      const name = child.childForFieldName('name')?.text ?? '';
+      out += text.slice(last - base, child.startIndex - base) + `${ctx.imports.get(pkg) ?? pkg}.${name}`;
+      last = child.endIndex;
+      return false;
+    }
+    if (child.type === 'type_identifier' && local.has(child.text)) {
+      out += text.slice(last - base, child.startIndex - base) + `${ctx.pkgPath}.${child.text}`;
+      last = child.endIndex;
+    }
+  });
+  return (out + text.slice(last - base)).replace(/\s+/g, ' ').trim();
+}
+
+/** T from new(T) */
+function newType(index: GoSourceIndex, ctx: FileContext, expr: Node | undefined): string | null {
+  if (expr?.type !== 'call_expression' || expr.childForFieldName('function')?.text !== 'new') return null;
+  const arg = expr.childForFieldName('arguments')?.namedChildren[0];
+  return arg ? canonicalType(index, ctx, arg) : null;
+}
+
+/** Type of a literal value: &T{...} or T{...} */
+function literalType(index: GoSourceIndex, ctx: FileContext, expr: Node): string | null {
+  const pointer = expr.type === 'unary_expression' && expr.text.startsWith('&');
+  const literal = pointer ? expr.childForFieldName('operand') : expr;
+  if (literal?.type !== 'composite_literal') return null;
+  const type = literal.childForFieldName('type');
+  return type ? `${pointer ? '*' : ''}${canonicalType(index, ctx, type)}` : null;
+}
+
+/**
+ * Parameter and result types of a func declaration or literal, expanding
+ * fx.In / fx.Out (and dig.In / dig.Out) parameter structs into their fields.
+ * Optional fields are not required; value groups make the result uncertain.
+ */
+function signatureTypes(index: GoSourceIndex, ctx: FileContext, fn: Node): { params: string[]; results: string[]; groups: boolean } {
+  const params: string[] = [];
+  const results: string[] = [];
+  let groups = false;
+
+  const collect = (nodes: Node[], into: string[], isResult: boolean) => {
+    for (const param of nodes) {
+      // A bare result type (func() T) has no parameter_declaration around it
+      const typeNode = param.type === 'parameter_declaration' || param.type === 'variadic_parameter_declaration'
+        ? param.childForFieldName('type')
+        : isResult ? param : null;
+      if (!typeNode) continue;
+      const fields = paramObjectFields(index, ctx, typeNode, isResult ? 'Out' : 'In');
+      if (fields) {
+        groups ||= fields.groups;
+        into.push(...fields.types);
+        continue;
+      }
+      const type = (param.type === 'variadic_parameter_declaration' ? '...' : '') + canonicalType(index, ctx, typeNode);
+      const names = typeNode === param ? 0 : param.childrenForFieldName('name').filter(Boolean).length;
+      for (let i = 0; i < Math.max(1, names); i++) into.push(type);
+    }
+  };
+  const children = (node: Node | null) => (node?.namedChildren ?? []).filter((n): n is Node => Boolean(n));
+
+  collect(children(fn.childForFieldName('parameters')), params, false);
+  const result = fn.childForFieldName('result');
+  if (result) collect(result.type === 'parameter_list' ? children(result) : [result], results, true);
+
+  return { params, results: results.filter(t => t !== 'error'), groups };
+}
+
+/** Fields of a struct that embeds fx.In/fx.Out or dig.In/dig.Out */
+function paramObjectFields(index: GoSourceIndex, ctx: FileContext, typeNode: Node, marker: 'In' | 'Out'): { types: string[]; groups: boolean } | null {
+  let dir = ctx.file.dir;
+  let name: string | null = null;
+  if (typeNode.type === 'type_identifier') {
+    name = typeNode.text;
+  } else if (typeNode.type === 'qualified_type') {
+    const path = ctx.imports.get(typeNode.childForFieldName('package')?.text ?? '');
+    const target = path ? index.modules.dirForImport(path) : null;
+    if (!target) return null;
+    dir = target;
+    name = typeNode.childForFieldName('name')?.text ?? null;
+  }
+  if (!name) return null;
+  const decl = index.find(dir, name, 'type');
+  const struct = decl?.node.childForFieldName('type');
+  if (!decl || struct?.type !== 'struct_type') return null;
+  const declCtx = index.context(decl.file);
+
+  const fieldList = struct.namedChildren.find(n => n?.type === 'field_declaration_list');
+  const fields = (fieldList?.namedChildren ?? []).filter((n): n is Node => n?.type === 'field_declaration');
+  const embedsMarker = fields.some(f => {
+    const type = f.childForFieldName('type');
+    if (f.childrenForFieldName('name').filter(Boolean).length > 0 || type?.type !== 'qualified_type') return false;
+    const framework = declCtx.frameworks.get(type.childForFieldName('package')?.text ?? '');
+    return (framework === 'fx' || framework === 'dig') && type.childForFieldName('name')?.text === marker;
+  });
+  if (!embedsMarker) return null;
+
+  const types: string[] = [];
+  let groups = false;
+  for (const field of fields) {
+    const names = field.childrenForFieldName('name').filter(Boolean).length;
+    const type = field.childForFieldName('type');
+    if (names === 0 || !type) continue;
+    const tag = field.childForFieldName('tag')?.text ?? '';
+    if (/optional:"true"/.test(tag)) continue;
+    if (/group:"/.test(tag)) {
+      groups = true;
+      continue;
+    }
+    const named = tag.match(/name:"([^"]+)"/);
+    const canonical = canonicalType(index, declCtx, type) + (named ? ` [name=${named[1]}]` : '');
+    for (let i = 0; i < names; i++) types.push(canonical);
+  }
+  return { types, groups };
+}
+
+// ── Resolution ─────────────────────────────────────────────────────
+
+function resolveContainer(container: DiContainer, bindings: DiBinding[], issues: DiIssue[]): void {
+  const providersOf = new Map<string, DiProvider[]>();
+  for (const provider of container.providers) {
+    for (const type of provider.provides) {
+      if (!providersOf.has(type)) providersOf.set(type, []);
+      providersOf.get(type)!.push(provider);
+    }
+  }
+  const at = { name: container.name, file: container.file, line: container.line };
+  const inputs = new Set(container.inputs);
+
+  const missing = new Map<string, string[]>();
+  const need = (type: string, consumer: string) => {
+    if (inputs.has(type) || (container.framework === 'fx' && FX_BUILTINS.has(type))) return;
+    const providers = providersOf.get(type);
+    if (!providers) {
+      if (!missing.has(type)) missing.set(type, []);
+      missing.get(type)!.push(consumer);
+      return;
+    }
+    for (const provider of providers) {
+      if (provider.id === consumer) continue;
+      bindings.push({ framework: container.framework, consumer, provider: provider.id, type, file: container.file, line: container.line });
+    }
+  };
+
+  for (const provider of container.providers) {
+    for (const type of provider.consumes) need(type, provider.id);
+  }
+  for (const type of container.outputs) need(type, `${container.file}::${container.name}`);
+
+  for (const [type, consumers] of missing) {
+    issues.push({
+      framework: container.framework,
+      kind: 'missing-provider',
+      type,
+      container: at,
+      symbols: [...new Set(consumers)],
+      message: `No provider for ${type} in ${container.framework} container ${container.name} (needed by ${[...new Set(consumers)].map(shortName).join(', ')})`,
+      certain: !container.incomplete,
+    });
+  }
+
+  // fx and dig reject a type provided twice; wire does too
+  for (const [type, providers] of providersOf) {
+    const distinct = [...new Set(providers.map(p => `${p.id}|${p.name}`))];
+    if (distinct.length < 2) continue;
+    issues.push({
+      framework: container.framework,
+      kind: 'duplicate-provider',
+      type,
+      container: at,
+      symbols: providers.map(p => p.id),
+      message: `${type} is provided ${distinct.length} times in ${container.framework} container ${container.name} (${providers.map(p => p.name).join(', ')})`,
+      certain: true,
+    });
+  }
+}
+
+function shortName(id: string): string {
+  return id.includes('::') ? id.split('::').pop()! : id;
+}
@@ -0,0 +1,186 @@
+import type { Node } from 'web-tree-sitter';
+import { walk } from './source.js';
+import { fileQualifiers, qualifiedName } from './references.js';
+import { loadGoProject } from './packages.js';
+import { buildGoCallGraph, packageKey, qualifiedTypeName, type GoCallGraph, type GoFunction } from './callgraph.js';
+
+/**
+ * Code that is only reached dynamically, which a call graph can't follow:
+ * plugins opened with plugin.Open, types built with reflect.New from a
+ * registered reflect.Type, and registries mapping names to constructors
+ * (map literals, registry[name] = New, Register("name", New)).
+ *
+ * Each site lists the functions it makes reachable. Reachability analyses
+ * can treat them as roots (--dynamic-roots) instead of reporting them as
+ * dead or unreachable.
+ */
+
+export type DynamicKind = 'plugin' | 'reflect' | 'registry';
+
+export interface DynamicSite {
+  kind: DynamicKind;
+  /** "plugin.Open", "reflect.New", "map literal", "index assignment" or the Register function */
+  via: string;
+  /** Key of the package the site is in */
+  package: string;
+  file: string;
+  line: number;
+  /** Function containing the site; null in package-level declarations */
+  function: string | null;
+  /** Function ids reached through the site */
+  targets: string[];
+}
+
+export interface DynamicReport {
+  sites: DynamicSite[];
+  /** Every target, once */
+  roots: string[];
+  /** Keys of packages with dynamic sites or targets */
+  packages: string[];
+}
+
+const REGISTER = /^[Rr]egister\w*$/;
+
+export function findDynamicSites(graph: GoCallGraph): DynamicReport {
+  const { project, functions } = graph;
+  const sites: DynamicSite[] = [];
+  const opened: Array<Omit<DynamicSite, 'targets'>> = [];
+  const reflectedTypes = new Set<string>();
+  const lookups = new Set<string>();
+
+  const methodsOf = (typeKey: string) => [...functions.values()]
+    .filter(f => f.receiver && `${f.package}.${f.receiver}` === typeKey)
+    .map(f => f.id);
+
+  for (const pkg of project.packages.values()) {
+    const key = packageKey(pkg);
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      const qualifiers = fileQualifiers(file, project);
+      const site = (node: Node, kind: DynamicKind, via: string): Omit<DynamicSite, 'targets'> => ({
+        kind,
+        via,
+        package: key,
+        file: file.file,
+        line: node.startPosition.row + 1,
+        function: graph.functionAt(file.file, node)?.id ?? null,
+      });
+      // A function value (New, codec.New) or a composite literal (&Driver{}) whose methods get called
+      const targetsOf = (value: Node | null): string[] => {
+        const fn = functionValue(value, key, qualifiers, functions);
+        if (fn) return [fn.id];
+        const type = literalType(value, key, qualifiers);
+        return type ? methodsOf(type) : [];
+      };
+      const add = (node: Node, via: string, targets: string[]) => {
+        if (targets.length > 0) sites.push({ ...site(node, 'registry', via), targets });
+      };
+
+      walk(file.root, (node) => {
+        if (node.type === 'call_expression') {
+          const callee = node.childForFieldName('function');
+          const args = node.childForFieldName('arguments')?.namedChildren.filter((n): n is Node => n !== null) ?? [];
+          const name = qualifiedName(callee, qualifiers);
+          if (name === 'plugin.Open') opened.push(site(node, 'plugin', name));
+          if (name === 'reflect.New') opened.push(site(node, 'reflect', name));
+          if (name === 'reflect.TypeOf') {
+            const type = reflectedType(args[0] ?? null, key, qualifiers);
+            if (type) reflectedTypes.add(type);
+          }
+          // p.Lookup("Symbol") on an opened plugin
+          if (callee?.type === 'selector_expression' && callee.childForFieldName('field')?.text === 'Lookup' && args[0]?.type === 'interpreted_string_literal') {
+            lookups.add(args[0].text.slice(1, -1));
+          }
+          const registrar = callee?.type === 'selector_expression' ? callee.childForFieldName('field')?.text : callee?.text;
+          if (registrar && REGISTER.test(registrar) && args.some(a => a.type === 'interpreted_string_literal' || a.type === 'raw_string_literal')) {
+            add(node, callee!.text, args.flatMap(targetsOf));
+          }
+          return;
+        }
+        if (node.type === 'composite_literal' && node.childForFieldName('type')?.type === 'map_type') {
+          const values = node.childForFieldName('body')?.namedChildren
+            .filter((n): n is Node => n?.type === 'keyed_element')
+            .map(elementValue) ?? [];
+          add(node, 'map literal', values.flatMap(targetsOf));
+          return;
+        }
+        if (node.type === 'assignment_statement') {
+          const left = node.childForFieldName('left')?.namedChildren ?? [];
+          const right = node.childForFieldName('right')?.namedChildren ?? [];
+          left.forEach((target, i) => {
+            if (target?.type === 'index_expression') add(node, 'index assignment', targetsOf(right[i] ?? null));
+          });
+        }
+      });
+    }
+  }
+
+  // Plugins are main packages without a main function; their exports are what Lookup finds
+  const pluginExports: string[] = [];
+  for (const pkg of project.packages.values()) {
+    const key = packageKey(pkg);
+    const file = pkg.files.find(f => !f.isTest);
+    if (pkg.name !== 'main' || !file || functions.has(`${key}.main`)) continue;
+    const exports = [...functions.values()]
+      .filter(f => f.dir === pkg.dir && f.exported && !f.receiver && (lookups.size === 0 || lookups.has(f.name)))
+      .map(f => f.id);
+    if (exports.length === 0) continue;
+    pluginExports.push(...exports);
+    sites.push({ kind: 'plugin', via: 'plugin package', package: key, file: file.file, line: 1, function: null, targets: exports });
+  }
+  const reflectTargets = [...reflectedTypes].flatMap(methodsOf);
+  for (const s of opened) {
+    const targets = s.kind === 'plugin' ? pluginExports : reflectTargets;
+    if (targets.length > 0) sites.push({ ...s, targets });
+  }
+
+  sites.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+  const roots = [...new Set(sites.flatMap(s => s.targets))];
+  const packages = new Set(sites.map(s => s.package));
+  for (const id of roots) packages.add(functions.get(id)!.package);
+  return { sites, roots, packages: [...packages].sort() };
+}
+
+/** "<file>:<line>" of the declaration of every dynamic root in a project, for dead-code */
+export async function dynamicRootLocations(projectRoot: string): Promise<Set<string>> {
+  const graph = buildGoCallGraph(await loadGoProject(projectRoot));
+  return new Set(findDynamicSites(graph).roots.map((id) => {
+    const fn = graph.functions.get(id)!;
+    return `${fn.file}:${fn.line}`;
+  }));
+}
+
+/** The project function an identifier or pkg.Name selector names */
+function functionValue(node: Node | null, pkgKey: string, qualifiers: Map<string, string>, functions: Map<string, GoFunction>): GoFunction | null {
+  if (node?.type === 'identifier') return functions.get(`${pkgKey}.${node.text}`) ?? null;
+  const name = qualifiedName(node, qualifiers);
+  return name ? functions.get(name) ?? null : null;
+}
+
+/** Type of T{} or &T{} */
+function literalType(node: Node | null, pkgKey: string, qualifiers: Map<string, string>): string | null {
+  if (node?.type === 'unary_expression' && node.childForFieldName('operator')?.text === '&') node = node.childForFieldName('operand');
+  if (node?.type !== 'composite_literal') return null;
+  return qualifiedTypeName(node.childForFieldName('type'), pkgKey, qualifiers);
+}
+
+/** The type behind reflect.TypeOf's argument: T{}, &T{}, new(T) or (*T)(nil) */
+function reflectedType(arg: Node | null, pkgKey: string, qualifiers: Map<string, string>): string | null {
+  const literal = literalType(arg, pkgKey, qualifiers);
+  if (literal || arg?.type !== 'call_expression') return literal;
+  const callee = arg.childForFieldName('function');
+  if (callee?.text === 'new') return qualifiedTypeName(arg.childForFieldName('arguments')?.namedChildren[0] ?? null, pkgKey, qualifiers);
+  // (*T)(nil) parses as a call of a parenthesized dereference
+  const inner = callee?.type === 'parenthesized_expression' ? callee.namedChildren[0] : null;
+  const pointee = inner?.type === 'unary_expression' ? inner.childForFieldName('operand') : inner?.type === 'pointer_type' ? inner.namedChildren[0] : null;
+  if (pointee?.type === 'identifier' || pointee?.type === 'type_identifier') return `${pkgKey}.${pointee.text}`;
+  const name = qualifiedName(pointee ?? null, qualifiers);
+  if (name) return name;
+  return pointee?.type === 'qualified_type' ? qualifiedTypeName(pointee, pkgKey, qualifiers) : null;
+}
+
+// keyed_element children are the key and the value, wrapped in literal_element by newer grammars
+function elementValue(element: Node): Node | null {
+  const value = element.namedChildren[element.namedChildren.length - 1] ?? null;
+  return value?.type === 'literal_element' ? value.namedChildren[0] ?? null : value;
+}
@@ -0,0 +1,356 @@
+import type { Node } from 'web-tree-sitter';
+import { walk, goDeclarations } from './source.js';
+import { loadGoProject } from './packages.js';
+import { fileQualifiers, qualifiedName } from './references.js';
+import { loadGoCallGraph, functionFile, packageKey, qualifiedTypeName, type GoCallGraph, type GoFunction } from './callgraph.js';
+
+/**
+ * How errors travel between packages. The errors are sentinel errors
+ * (var ErrNotFound = errors.New(...)) and error types (anything with an
+ * Error() string method). Each one is followed to where it is:
+ *
+ *   returned  — return store.ErrNotFound, return &QueryError{...}, or
+ *               propagated: return err where err came from a call that
+ *               may return it, through any number of functions
+ *   wrapped   — fmt.Errorf("...: %w", ...) or errors.Join
+ *   checked   — errors.Is, errors.As, ==, type assertions and type switches
+ *
+ * The error-flow graph has an edge from the package declaring an error to
+ * every other package that returns, wraps or checks it, which is where a
+ * storage layer's errors leak into the HTTP handlers. Propagation through
+ * variables is by name within a function, not flow-sensitive.
+ */
+
+export type GoErrorKind = 'sentinel' | 'type';
+export type ErrorUseKind = 'returned' | 'wrapped' | 'checked';
+
+export interface GoError {
+  /** "<package key>.Name" */
+  id: string;
+  kind: GoErrorKind;
+  package: string;
+  name: string;
+  /** Text of errors.New/fmt.Errorf for sentinels */
+  message?: string;
+  file: string;
+  line: number;
+  /** Functions that may return it, directly or propagated */
+  returnedBy: string[];
+}
+
+export interface ErrorUse {
+  error: string;
+  kind: ErrorUseKind;
+  package: string;
+  /** Enclosing function id; null in package-level declarations */
+  function: string | null;
+  file: string;
+  line: number;
+  /** Returned as the result of a call rather than by name */
+  propagated: boolean;
+}
+
+export interface ErrorFlowEdge {
+  /** Package declaring the errors */
+  from: string;
+  /** Package returning, wrapping or checking them */
+  to: string;
+  errors: string[];
+  kinds: ErrorUseKind[];
+  uses: number;
+}
+
+export interface ErrorFlowReport {
+  projectRoot: string;
+  errors: GoError[];
+  uses: ErrorUse[];
+  edges: ErrorFlowEdge[];
+  warnings: string[];
+}
+
+export interface ErrorFlowOptions {
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+  signal?: AbortSignal;
+}
+
+export async function analyzeErrorFlow(projectRoot: string, options: ErrorFlowOptions = {}): Promise<ErrorFlowReport> {
+  const project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  const { errors, uses } = traceErrors(graph);
+  return { projectRoot, errors, uses, edges: errorFlowEdges(errors, uses), warnings };
+}
+
+/** What a returned expression may evaluate to: errors named directly, and calls whose errors it passes on */
+interface ErrorSources {
+  errors: Set<string>;
+  calls: Node[];
+}
+
+interface ReturnSite {
+  line: number;
+  sources: ErrorSources;
+}
+
+export function traceErrors(graph: GoCallGraph): { errors: GoError[]; uses: ErrorUse[] } {
+  const { project, functions } = graph;
+  const errors = new Map<string, GoError>();
+  const types = new Map<string, { file: string; line: number }>();
+
+  for (const pkg of project.packages.values()) {
+    const key = packageKey(pkg);
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      const qualifiers = fileQualifiers(file, project);
+      for (const decl of goDeclarations(file)) {
+        if (decl.kind === 'type') types.set(`${key}.${decl.name}`, { file: file.file, line: decl.line });
+        if (decl.kind !== 'var') continue;
+        const message = sentinelMessage(decl.node, qualifiers);
+        if (message === null) continue;
+        errors.set(`${key}.${decl.name}`, { id: `${key}.${decl.name}`, kind: 'sentinel', package: key, name: decl.name, message, file: file.file, line: decl.line, returnedBy: [] });
+      }
+    }
+  }
+  for (const fn of functions.values()) {
+    if (fn.name !== 'Error' || !fn.receiver || fn.params.length > 0) continue;
+    const id = `${fn.package}.${fn.receiver}`;
+    const at = types.get(id) ?? { file: fn.file, line: fn.line };
+    errors.set(id, { id, kind: 'type', package: fn.package, name: fn.receiver, ...at, returnedBy: [] });
+  }
+
+  const uses: ErrorUse[] = [];
+  const returns = new Map<string, ReturnSite[]>();
+  for (const fn of functions.values()) {
+    const file = functionFile(graph, fn);
+    const qualifiers = fileQualifiers(file, project);
+    const ctx: FunctionContext = { fn, qualifiers, errors, assigned: assignments(fn.node) };
+    const use = (error: string, kind: ErrorUseKind, node: Node) => {
+      uses.push({ error, kind, package: fn.package, function: fn.id, file: file.file, line: node.startPosition.row + 1, propagated: false });
+    };
+    const sites: ReturnSite[] = [];
+
+    walk(fn.node, (node) => {
+      // Closures return from themselves, not from fn
+      if (node.type === 'return_statement' && !insideClosure(node, fn.node)) {
+        const sources: ErrorSources = { errors: new Set(), calls: [] };
+        for (const expr of node.namedChildren[0]?.namedChildren ?? []) collectSources(expr, ctx, sources, new Set());
+        sites.push({ line: node.startPosition.row + 1, sources });
+        for (const error of sources.errors) use(error, 'returned', node);
+        return;
+      }
+      if (node.type === 'call_expression') {
+        const name = qualifiedName(node.childForFieldName('function'), qualifiers);
+        const args = callArgs(node);
+        if (isWrap(name, args)) {
+          for (const arg of name === 'errors.Join' ? args : args.slice(1)) {
+            const error = errorRef(arg, ctx);
+            if (error) use(error, 'wrapped', arg);
+          }
+        } else if (name === 'errors.Is') {
+          const error = errorRef(args[1] ?? null, ctx);
+          if (error) use(error, 'checked', node);
+        } else if (name === 'errors.As') {
+          const error = asTarget(args[1] ?? null, ctx);
+          if (error) use(error, 'checked', node);
+        }
+        return;
+      }
+      if (node.type === 'binary_expression' && ['==', '!='].includes(node.childForFieldName('operator')?.text ?? '')) {
+        for (const side of [node.childForFieldName('left'), node.childForFieldName('right')]) {
+          const error = errorRef(side, ctx);
+          if (error) use(error, 'checked', node);
+        }
+        return;
+      }
+      // err.(*QueryError), and case *QueryError: in a type switch
+      if (node.type === 'type_assertion_expression' || node.type === 'type_case') {
+        const types = node.type === 'type_case' ? node.childrenForFieldName('type') : [node.childForFieldName('type')];
+        for (const type of types) {
+          const error = qualifiedTypeName(type, fn.package, qualifiers);
+          if (error && errors.get(error)?.kind === 'type') use(error, 'checked', node);
+        }
+      }
+    });
+    returns.set(fn.id, sites);
+  }
+
+  // What each function may return, propagated through calls to a fixed point
+  const mayReturn = new Map<string, Set<string>>();
+  const calleesOf = (fn: GoFunction, call: Node) => graph.calleesOf(functionFile(graph, fn).file, call).filter(c => !c.external);
+  for (const [id, sites] of returns) mayReturn.set(id, new Set(sites.flatMap(s => [...s.sources.errors])));
+  for (let changed = true; changed;) {
+    changed = false;
+    for (const [id, sites] of returns) {
+      const fn = functions.get(id)!;
+      const own = mayReturn.get(id)!;
+      for (const site of sites) {
+        for (const call of site.sources.calls) {
+          for (const callee of calleesOf(fn, call)) {
+            for (const error of mayReturn.get(callee.callee) ?? []) {
+              if (own.has(error)) continue;
+              own.add(error);
+              changed = true;
+            }
+          }
+        }
+      }
+    }
+  }
+
+  for (const [id, sites] of returns) {
+    const fn = functions.get(id)!;
+    const file = functionFile(graph, fn).file;
+    for (const site of sites) {
+      const propagated = new Set(site.sources.calls.flatMap(call => calleesOf(fn, call).flatMap(c => [...mayReturn.get(c.callee) ?? []])));
+      for (const error of propagated) {
+        if (site.sources.errors.has(error)) continue;
+        uses.push({ error, kind: 'returned', package: fn.package, function: id, file, line: site.line, propagated: true });
+      }
+    }
+    for (const error of mayReturn.get(id)!) errors.get(error)!.returnedBy.push(id);
+  }
+
+  for (const error of errors.values()) error.returnedBy.sort();
+  return {
+    errors: [...errors.values()].sort((a, b) => a.package.localeCompare(b.package) || a.name.localeCompare(b.name)),
+    uses: dedupe(uses).sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line || a.error.localeCompare(b.error)),
+  };
+}
+
+/** Declaring package → every other package returning, wrapping or checking its errors */
+export function errorFlowEdges(errors: GoError[], uses: ErrorUse[]): ErrorFlowEdge[] {
+  const declaredIn = new Map(errors.map(e => [e.id, e.package]));
+  const edges = new Map<string, ErrorFlowEdge>();
+  for (const use of uses) {
+    const from = declaredIn.get(use.error)!;
+    if (from === use.package) continue;
+    const key = `${from}\0${use.package}`;
+    const edge = edges.get(key) ?? { from, to: use.package, errors: [], kinds: [], uses: 0 };
+    if (!edge.errors.includes(use.error)) edge.errors.push(use.error);
+    if (!edge.kinds.includes(use.kind)) edge.kinds.push(use.kind);
+    edge.uses++;
+    edges.set(key, edge);
+  }
+  return [...edges.values()].sort((a, b) => b.uses - a.uses || a.from.localeCompare(b.from) || a.to.localeCompare(b.to));
+}
+
+interface FunctionContext {
+  fn: GoFunction;
+  qualifiers: Map<string, string>;
+  errors: Map<string, GoError>;
+  /** Local name → expressions assigned to it */
+  assigned: Map<string, Node[]>;
+}
+
+function collectSources(expr: Node | null, ctx: FunctionContext, sources: ErrorSources, seen: Set<string>): void {
+  if (!expr) return;
+  const error = errorRef(expr, ctx);
+  if (error) {
+    sources.errors.add(error);
+    return;
+  }
+  if (expr.type === 'parenthesized_expression') {
+    collectSources(expr.namedChildren[0] ?? null, ctx, sources, seen);
+  } else if (expr.type === 'identifier') {
+    if (seen.has(expr.text)) return;
+    seen.add(expr.text);
+    for (const value of ctx.assigned.get(expr.text) ?? []) collectSources(value, ctx, sources, seen);
+  } else if (expr.type === 'call_expression') {
+    const name = qualifiedName(expr.childForFieldName('function'), ctx.qualifiers);
+    const args = callArgs(expr);
+    // %w and errors.Join keep the chain; other fmt.Errorf verbs and errors.New start a new one
+    if (isWrap(name, args)) {
+      for (const arg of name === 'errors.Join' ? args : args.slice(1)) collectSources(arg, ctx, sources, seen);
+    } else if (name !== 'fmt.Errorf' && name !== 'errors.New') {
+      sources.calls.push(expr);
+    }
+  }
+}
+
+/** The error an expression names: a sentinel (ErrX, pkg.ErrX) or a literal of an error type (&QueryError{}, pkg.QueryError{}) */
+function errorRef(expr: Node | null, ctx: FunctionContext): string | null {
+  if (!expr) return null;
+  let id: string | null = null;
+  if (expr.type === 'identifier') id = `${ctx.fn.package}.${expr.text}`;
+  else if (expr.type === 'selector_expression') id = qualifiedName(expr, ctx.qualifiers);
+  else {
+    let literal: Node | null = expr;
+    if (literal.type === 'unary_expression' && literal.childForFieldName('operator')?.text === '&') literal = literal.childForFieldName('operand');
+    if (literal?.type === 'composite_literal') id = qualifiedTypeName(literal.childForFieldName('type'), ctx.fn.package, ctx.qualifiers);
+    return id && ctx.errors.get(id)?.kind === 'type' ? id : null;
+  }
+  return id && ctx.errors.get(id)?.kind === 'sentinel' ? id : null;
+}
+
+// errors.As(err, &target): the error type target was declared with (var target *QueryError, or target := &QueryError{})
+function asTarget(arg: Node | null, ctx: FunctionContext): string | null {
+  const target = arg?.type === 'unary_expression' ? arg.childForFieldName('operand') : arg;
+  if (target?.type !== 'identifier') return null;
+  const declared: Array<string | null> = [];
+  walk(ctx.fn.node, (node) => {
+    if (node.type !== 'var_spec' || !node.childrenForFieldName('name').some(n => n?.text === target.text)) return;
+    declared.push(qualifiedTypeName(node.childForFieldName('type'), ctx.fn.package, ctx.qualifiers));
+  });
+  declared.push(...(ctx.assigned.get(target.text) ?? []).map(value => errorRef(value, ctx)));
+  return declared.find(type => type !== null && ctx.errors.get(type)?.kind === 'type') ?? null;
+}
+
+function insideClosure(node: Node, fn: Node): boolean {
+  for (let current = node.parent; current && current.startIndex >= fn.startIndex; current = current.parent) {
+    if (current.type === 'func_literal') return true;
+    if (current.startIndex === fn.startIndex) break;
+  }
+  return false;
+}
+
+/** Local names of a function and what is assigned to them (=, :=, var), closures included */
+function assignments(fn: Node): Map<string, Node[]> {
+  const assigned = new Map<string, Node[]>();
+  const add = (names: Array<Node | null>, values: Array<Node | null>) => {
+    names.forEach((name, i) => {
+      // a, err := f() assigns the one call to every name
+      const value = values.length === names.length ? values[i] : values[0];
+      if (name?.type !== 'identifier' || name.text === '_' || !value) return;
+      if (!assigned.has(name.text)) assigned.set(name.text, []);
+      assigned.get(name.text)!.push(value);
+    });
+  };
+  walk(fn, (node) => {
+    if (node.type === 'assignment_statement' || node.type === 'short_var_declaration') {
+      add(node.childForFieldName('left')?.namedChildren ?? [], node.childForFieldName('right')?.namedChildren ?? []);
+    } else if (node.type === 'var_spec') {
+      add(node.childrenForFieldName('name'), node.childForFieldName('value')?.namedChildren ?? []);
+    }
+  });
+  return assigned;
+}
+
+// The message of var ErrX = errors.New("...") or fmt.Errorf("..."); null for anything else
+function sentinelMessage(spec: Node, qualifiers: Map<string, string>): string | null {
+  const value = spec.childForFieldName('value')?.namedChildren[0];
+  if (value?.type !== 'call_expression') return null;
+  const name = qualifiedName(value.childForFieldName('function'), qualifiers);
+  if (name !== 'errors.New' && name !== 'fmt.Errorf') return null;
+  const message = callArgs(value)[0];
+  return message?.type === 'interpreted_string_literal' || message?.type === 'raw_string_literal' ? message.text.slice(1, -1) : '';
+}
+
+function isWrap(name: string | null, args: Node[]): boolean {
+  if (name === 'errors.Join') return true;
+  return name === 'fmt.Errorf' && /%w/.test(args[0]?.text ?? '');
+}
+
+function callArgs(call: Node): Node[] {
+  return (call.childForFieldName('arguments')?.namedChildren ?? []).filter((n): n is Node => n !== null);
+}
+
+function dedupe(uses: ErrorUse[]): ErrorUse[] {
+  const seen = new Set<string>();
+  return uses.filter((use) => {
+    const key = `${use.error}\0${use.kind}\0${use.file}:${use.line}`;
+    if (seen.has(key)) return false;
+    seen.add(key);
+    return true;
+  });
+}
@@ -0,0 +1,215 @@
+import { existsSync, mkdirSync, readdirSync, readFileSync, rmSync, writeFileSync } from 'fs';
+import { dirname, join, posix } from 'path';
+
+/**
+ * Synthetic Go projects of a chosen shape, for benchmarks and for testing
+ * rules against structures known in advance: a number of packages, of
+ * package imports and of import cycles, each cycle its own strongly
+ * connected component. Every import is used, so without cycles the
+ * project builds with `go build ./...` and passes `go vet`; Go rejects
+ * import cycles, so a fixture with any is for static analysis only. The
+ * same seed gives the same project, and the manifest written with it lists
+ * every package, edge and cycle.
+ */
+
+export interface FixtureOptions {
+  packages: number;
+  /** Package imports, cycle edges included */
+  edges: number;
+  cycles: number;
+  /** Go files per package (default 2) */
+  files?: number;
+  /** Functions per file (default 3) */
+  functions?: number;
+  /** Module path (default example.com/fixture) */
+  module?: string;
+  /** Packages per directory group (default 25) */
+  groupSize?: number;
+  seed?: number;
+}
+
+export interface FixtureShape {
+  module: string;
+  seed: number;
+  /** Package directories, project-relative */
+  packages: string[];
+  /** [importer, imported] package directories */
+  edges: Array<[string, string]>;
+  /** The packages of each import cycle */
+  cycles: string[][];
+}
+
+export interface FixtureFile {
+  path: string;
+  content: string;
+}
+
+/** Lists the files of the last run, so the next one can replace them */
+export const FIXTURE_MANIFEST = 'depwire-fixture.json';
+
+// mulberry32: small, fast and the same everywhere
+function random(seed: number): () => number {
+  let state = seed >>> 0;
+  return () => {
+    state = (state + 0x6d2b79f5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
+
+/**
+ * Which package imports which. Packages are numbered and ordinary imports
+ * go from a higher number to a lower one, which can't close a loop; each
+ * cycle is a descending chain of 2–4 packages of its own closed by one
+ * import back up, and an ordinary import is only added when its target
+ * can't already reach its source, so cycles never merge or multiply.
+ */
+function fixtureEdges(options: FixtureOptions, next: () => number): { edges: Array<[number, number]>; cycles: number[][] } {
+  const n = options.packages;
+  const imports = Array.from({ length: n }, () => new Set<number>());
+  const edges: Array<[number, number]> = [];
+  const add = (from: number, to: number) => {
+    imports[from].add(to);
+    edges.push([from, to]);
+  };
+  const pick = (below: number) => Math.floor(next() * below);
+
+  const cycles: number[][] = [];
+  const free = Array.from({ length: n }, (_, i) => i);
+  for (let c = 0; c < options.cycles; c++) {
+    // Leave two packages for each cycle still to place
+    const length = Math.min(2 + pick(3), free.length - 2 * (options.cycles - c - 1));
+    const members: number[] = [];
+    for (let i = 0; i < length; i++) members.push(free.splice(pick(free.length), 1)[0]);
+    members.sort((a, b) => b - a);
+    for (let i = 0; i + 1 < members.length; i++) add(members[i], members[i + 1]);
+    add(members[members.length - 1], members[0]);
+    cycles.push(members);
+  }
+
+  const reaches = (from: number, to: number) => {
+    const seen = new Set([from]);
+    const queue = [from];
+    while (queue.length > 0) {
+      for (const dep of imports[queue.shift()!]) {
+        if (dep === to) return true;
+        if (!seen.has(dep)) {
+          seen.add(dep);
+          queue.push(dep);
+        }
+      }
+    }
+    return false;
+  };
+  const tryAdd = (from: number, to: number) => {
+    if (from === to || imports[from].has(to) || reaches(to, from)) return false;
+    add(from, to);
+    return true;
+  };
+
+  // Random draws while they mostly land, then a sweep for dense shapes; a single package has nothing to import
+  let misses = 0;
+  while (n > 1 && edges.length < options.edges && misses < 50 * n) {
+    const from = 1 + pick(n - 1);
+    if (tryAdd(from, pick(from))) misses = 0;
+    else misses++;
+  }
+  for (let from = n - 1; from > 0 && edges.length < options.edges; from--) {
+    for (let to = from - 1; to >= 0 && edges.length < options.edges; to--) tryAdd(from, to);
+  }
+  return { edges, cycles };
+}
+
+const pad = (i: number, width: number) => String(i).padStart(width, '0');
+
+/** The files of a fixture and its shape; nothing is written */
+export function generateFixture(options: FixtureOptions): { files: FixtureFile[]; shape: FixtureShape } {
+  const n = options.packages;
+  if (!Number.isInteger(n) || n < 1) throw new Error('--packages must be at least 1');
+  if (!Number.isInteger(options.edges) || options.edges < 0) throw new Error('--edges must be a non-negative number');
+  if (!Number.isInteger(options.cycles) || options.cycles < 0 || options.cycles * 2 > n) {
+    throw new Error(`--cycles must be between 0 and ${Math.floor(n / 2)} for ${n} packages (each cycle takes 2 to 4 packages of its own)`);
+  }
+  const module = options.module ?? 'example.com/fixture';
+  const seed = options.seed ?? 1;
+  const fileCount = Math.max(1, options.files ?? 2);
+  const functionCount = Math.max(1, options.functions ?? 3);
+  const groupSize = Math.max(1, options.groupSize ?? 25);
+  const next = random(seed);
+
+  const width = String(n - 1).length;
+  const groupWidth = String(Math.floor((n - 1) / groupSize)).length;
+  const names = Array.from({ length: n }, (_, i) => `p${pad(i, width)}`);
+  const dirs = names.map((name, i) => `pkg/g${pad(Math.floor(i / groupSize), groupWidth)}/${name}`);
+  const { edges, cycles } = fixtureEdges(options, next);
+
+  const files: FixtureFile[] = [{ path: 'go.mod', content: `module ${module}\n\ngo 1.21\n` }];
+  const imports = Array.from({ length: n }, () => [] as number[]);
+  for (const [from, to] of edges) imports[from].push(to);
+
+  for (let p = 0; p < n; p++) {
+    const name = names[p];
+    // Spread the imports over the files; each one is called from its file's first function
+    const byFile = Array.from({ length: fileCount }, () => [] as number[]);
+    imports[p].sort((a, b) => dirs[a].localeCompare(dirs[b])).forEach((dep, i) => byFile[i % fileCount].push(dep));
+
+    for (let f = 0; f < fileCount; f++) {
+      const lines: string[] = [];
+      if (f === 0) lines.push(`// Package ${name} is generated by depwire gen-fixture.`);
+      lines.push(`package ${name}`, '');
+      const deps = byFile[f];
+      if (deps.length === 1) lines.push(`import "${module}/${dirs[deps[0]]}"`, '');
+      else if (deps.length > 1) lines.push('import (', ...deps.map(d => `\t"${module}/${dirs[d]}"`), ')', '');
+
+      lines.push(`// T${f} holds state for the functions of ${name}_${f}.go.`, `type T${f} struct {`, '\tn int', '}', '');
+      lines.push(`// Get returns the value of t.`, `func (t *T${f}) Get() int {`, '\treturn t.n', '}', '');
+      for (let fn = 0; fn < functionCount; fn++) {
+        const id = `F${f}_${fn}`;
+        lines.push(`// ${id} is a generated function.`, `func ${id}(n int) int {`);
+        if (fn === 0) for (const dep of deps) lines.push(`\tn += ${names[dep]}.F0_0(n)`);
+        // Calls within the package: to the previous function of this file, and across files
+        if (fn > 0) lines.push(`\tn += F${f}_${fn - 1}(n)`);
+        else if (f > 0) lines.push(`\tn += F${f - 1}_0(n)`);
+        lines.push(`\tt := &T${f}{n: n + ${fn}}`, '\treturn t.Get()', '}', '');
+      }
+      files.push({ path: `${dirs[p]}/${name}_${f}.go`, content: lines.join('\n').replace(/\n+$/, '\n') });
+    }
+  }
+
+  return {
+    files,
+    shape: {
+      module,
+      seed,
+      packages: dirs,
+      edges: edges.map(([from, to]) => [dirs[from], dirs[to]] as [string, string]).sort((a, b) => a[0].localeCompare(b[0]) || a[1].localeCompare(b[1])),
+      cycles: cycles.map(c => c.map(i => dirs[i]).sort()),
+    },
+  };
+}
+
+/**
+ * Write a fixture to outDir, replacing the files of an earlier fixture
+ * there. A non-empty directory without the manifest is refused.
+ */
+export function writeFixture(outDir: string, files: FixtureFile[], shape: FixtureShape): void {
+  const manifestFile = join(outDir, FIXTURE_MANIFEST);
+  if (existsSync(manifestFile)) {
+    const previous = (JSON.parse(readFileSync(manifestFile, 'utf-8')) as { files?: string[] }).files ?? [];
+    for (const path of previous) {
+      // The manifest only lists paths inside the fixture
+      if (posix.isAbsolute(path) || path.split('/').includes('..')) continue;
+      rmSync(join(outDir, path), { force: true });
+    }
+  } else if (existsSync(outDir) && readdirSync(outDir).length > 0) {
+    throw new Error(`${outDir} is not empty and wasn't generated by depwire gen-fixture; pick an empty or new directory`);
+  }
+  for (const file of files) {
+    const target = join(outDir, file.path);
+    mkdirSync(dirname(target), { recursive: true });
+    writeFileSync(target, file.content);
+  }
+  writeFileSync(manifestFile, JSON.stringify({ ...shape, files: files.map(f => f.path).sort() }, null, 2) + '\n');
+}
@@ -0,0 +1,130 @@
+import type { Node } from 'web-tree-sitter';
+import { walk } from './source.js';
+import { loadGoProject } from './packages.js';
+import { loadGoCallGraph, functionFile, type GoCallGraph } from './callgraph.js';
+
+/**
+ * Where goroutines come from: every `go` statement of the workspace, the
+ * function it's in and what the goroutine runs, rolled up into a graph of
+ * packages spawning goroutines that run code of other packages. For
+ * `go func() { ... }()` the goroutine runs whatever the literal calls.
+ */
+
+export interface GoroutineTarget {
+  /** Function id, "<import path>.Name" for external functions */
+  function: string;
+  /** Key of the function's package; null for methods that could not be resolved */
+  package: string | null;
+  external: boolean;
+  /** Matched by method name only */
+  approximate: boolean;
+}
+
+export interface GoroutineSpawn {
+  /** Function containing the go statement */
+  spawner: string;
+  package: string;
+  file: string;
+  line: number;
+  /** The go statement starts a function literal */
+  literal: boolean;
+  /** What the goroutine runs: the called function, or every function the literal calls */
+  targets: GoroutineTarget[];
+}
+
+export interface GoroutineEdge {
+  from: string;
+  to: string;
+  /** go statements behind the edge */
+  spawns: number;
+  external: boolean;
+}
+
+export interface GoroutineReport {
+  projectRoot: string;
+  spawns: GoroutineSpawn[];
+  /** Spawning package → package whose code the goroutine runs; from === to for goroutines within a package */
+  edges: GoroutineEdge[];
+  warnings: string[];
+}
+
+export interface GoroutineOptions {
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+  signal?: AbortSignal;
+}
+
+export async function analyzeGoroutines(projectRoot: string, options: GoroutineOptions = {}): Promise<GoroutineReport> {
+  const project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  const spawns = goroutineSpawns(graph);
+  return { projectRoot, spawns, edges: spawnEdges(spawns), warnings };
+}
+
+export function goroutineSpawns(graph: GoCallGraph): GoroutineSpawn[] {
+  const spawns: GoroutineSpawn[] = [];
+  for (const fn of graph.functions.values()) {
+    const file = functionFile(graph, fn).file;
+    const imports = [...graph.project.packages.get(fn.dir)!.imports.keys()];
+    const targetsOf = (call: Node): GoroutineTarget[] => graph.calleesOf(file, call).map(c => ({
+      function: c.callee,
+      package: c.external ? calleePackage(c.callee, imports) : graph.functions.get(c.callee)!.package,
+      external: c.external,
+      approximate: c.approximate,
+    }));
+
+    walk(fn.node, (node) => {
+      if (node.type !== 'go_statement') return;
+      const call = node.namedChildren.find(n => n?.type === 'call_expression');
+      if (!call) return;
+      const callee = call.childForFieldName('function');
+      const literal = callee?.type === 'func_literal';
+      const targets: GoroutineTarget[] = [];
+      if (callee && literal) {
+        // Nested go statements are spawns of their own
+        walk(callee, (inner) => {
+          if (inner.type === 'go_statement') return false;
+          if (inner.type === 'call_expression') targets.push(...targetsOf(inner));
+        });
+      } else {
+        targets.push(...targetsOf(call));
+      }
+      spawns.push({
+        spawner: fn.id,
+        package: fn.package,
+        file,
+        line: node.startPosition.row + 1,
+        literal,
+        targets: [...new Map(targets.map(t => [t.function, t])).values()],
+      });
+    });
+  }
+  return spawns.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+}
+
+/** Package edges of the spawns; a literal that calls nothing runs in its own package */
+export function spawnEdges(spawns: GoroutineSpawn[]): GoroutineEdge[] {
+  const edges = new Map<string, GoroutineEdge>();
+  for (const spawn of spawns) {
+    const targets = spawn.targets.filter(t => t.package !== null);
+    const packages = targets.length > 0 ? new Map(targets.map(t => [t.package!, t.external])) : new Map([[spawn.package, false]]);
+    for (const [to, external] of packages) {
+      const key = `${spawn.package}\0${to}`;
+      const edge = edges.get(key) ?? { from: spawn.package, to, spawns: 0, external };
+      edge.spawns++;
+      edges.set(key, edge);
+    }
+  }
+  return [...edges.values()].sort((a, b) => b.spawns - a.spawns || a.from.localeCompare(b.from) || a.to.localeCompare(b.to));
+}
+
+// "net/http.Client.Do" → "net/http": the longest import it starts with, or up to the first dot after the last slash
+export function calleePackage(callee: string, imports: string[]): string | null {
+  if (callee.startsWith('?.')) return null;
+  const imported = imports.filter(path => callee.startsWith(`${path}.`)).sort((a, b) => b.length - a.length)[0];
+  if (imported) return imported;
+  const slash = callee.lastIndexOf('/');
+  const dot = callee.indexOf('.', slash + 1);
+  return dot < 0 ? null : callee.slice(0, dot);
+}
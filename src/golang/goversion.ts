@@ -0,0 +1,236 @@
+import { existsSync, readFileSync } from 'fs';
+import { join } from 'path';
+import { loadGoFiles, goImports, walk, type GoSourceFile } from './source.js';
+import { buildModuleGraph } from './modgraph.js';
+import { parseGoMod, readGoMod } from './modfile.js';
+import { runGo, findGoModuleRoot, goModCacheDir } from './toolchain.js';
+import { escapeModulePath } from '../supply-chain/confusion.js';
+
+/**
+ * Go language and toolchain versions across the module graph: the go and
+ * toolchain directives of every module, the dependencies that need a newer
+ * Go than ours declares, and the language features and standard library
+ * packages in our own code that set a floor under the go directive — what
+ * would have to change to support an older Go, and what forces a newer one.
+ *
+ * Since Go 1.21 the go directive is a hard minimum: a module declaring a
+ * newer go than the running toolchain makes the go command switch
+ * toolchains (GOTOOLCHAIN=auto) or fail (GOTOOLCHAIN=local).
+ */
+
+export interface ModuleGoVersion {
+  path: string;
+  version: string;
+  direct: boolean;
+  go: string | null;
+  toolchain: string | null;
+  /** Its go directive is newer than ours */
+  newerThanOurs: boolean;
+}
+
+export interface LanguageFeatureUse {
+  feature: string;
+  /** Go release that introduced it */
+  since: string;
+  uses: number;
+  example: { file: string; line: number };
+}
+
+export interface GoVersionReport {
+  module: string;
+  /** Our go and toolchain directives */
+  go: string | null;
+  toolchain: string | null;
+  /** `go version` of the toolchain on PATH, or null without one */
+  installed: string | null;
+  modules: ModuleGoVersion[];
+  /** Our language and library use, newest first */
+  features: LanguageFeatureUse[];
+  /** The oldest go directive our module could declare, and what sets it */
+  floor: { go: string; reasons: string[] };
+  findings: string[];
+  warnings: string[];
+}
+
+/** Compare Go versions ("1.21", "1.21.3", "1.22rc1", "go1.23.0"): releases after their prereleases */
+export function compareGoVersions(a: string, b: string): number {
+  const parse = (v: string) => {
+    const m = /^(?:go)?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:(rc|beta)(\d+))?/.exec(v);
+    if (!m) return [0, 0, 0, 0, 0];
+    // A prerelease sorts before the .0 release; "1.21" (the language version) equals "1.21.0"
+    const stage = m[4] === 'beta' ? 0 : m[4] === 'rc' ? 1 : 2;
+    return [Number(m[1]), Number(m[2] ?? 0), Number(m[3] ?? 0), stage, Number(m[5] ?? 0)];
+  };
+  const [pa, pb] = [parse(a), parse(b)];
+  for (let i = 0; i < pa.length; i++) {
+    if (pa[i] !== pb[i]) return pa[i] - pb[i];
+  }
+  return 0;
+}
+
+function maxVersion(versions: string[]): string | null {
+  return versions.reduce<string | null>((max, v) => (max === null || compareGoVersions(v, max) > 0 ? v : max), null);
+}
+
+// Standard library packages by the release that added them
+const STDLIB_SINCE: Record<string, string> = {
+  'io/fs': '1.16',
+  embed: '1.16',
+  'net/netip': '1.18',
+  'crypto/ecdh': '1.20',
+  slices: '1.21',
+  maps: '1.21',
+  cmp: '1.21',
+  'log/slog': '1.21',
+  'testing/slogtest': '1.21',
+  'math/rand/v2': '1.22',
+  'go/version': '1.22',
+  iter: '1.23',
+  unique: '1.23',
+  structs: '1.23',
+  weak: '1.24',
+  'crypto/mlkem': '1.24',
+  'crypto/hkdf': '1.24',
+  'crypto/pbkdf2': '1.24',
+  'crypto/sha3': '1.24',
+  'testing/synctest': '1.25',
+};
+
+const BUILTIN_SINCE: Record<string, string> = { min: '1.21', max: '1.21', clear: '1.21' };
+const UNSAFE_SINCE: Record<string, string> = { Add: '1.17', Slice: '1.17', String: '1.20', StringData: '1.20', SliceData: '1.20' };
+
+/** Language features and standard library packages our code uses that not every Go release has */
+export function detectLanguageFeatures(files: GoSourceFile[]): LanguageFeatureUse[] {
+  const uses = new Map<string, LanguageFeatureUse>();
+  const record = (feature: string, since: string, file: GoSourceFile, line: number) => {
+    const use = uses.get(feature);
+    if (use) use.uses++;
+    else uses.set(feature, { feature, since, uses: 1, example: { file: file.file, line } });
+  };
+
+  // Functions a package declares itself shadow the min/max/clear builtins
+  const declared = new Map<string, Set<string>>();
+  for (const file of files) {
+    if (!declared.has(file.dir)) declared.set(file.dir, new Set());
+    for (const node of file.root.namedChildren) {
+      const name = node?.type === 'function_declaration' ? node.childForFieldName('name')?.text : undefined;
+      if (name) declared.get(file.dir)!.add(name);
+    }
+  }
+
+  for (const file of files) {
+    for (const imp of goImports(file)) {
+      const since = STDLIB_SINCE[imp.path];
+      if (since) record(`package ${imp.path}`, since, file, imp.line);
+    }
+    walk(file.root, (node) => {
+      const line = node.startPosition.row + 1;
+      switch (node.type) {
+        case 'type_parameter_list':
+          record('generics (type parameters)', '1.18', file, line);
+          if (node.parent?.type === 'type_alias') record('generic type aliases', '1.24', file, line);
+          break;
+        case 'range_clause': {
+          const right = node.childForFieldName('right');
+          if (right?.type === 'int_literal') record('range over int', '1.22', file, line);
+          break;
+        }
+        case 'int_literal':
+        case 'float_literal':
+        case 'imaginary_literal':
+          if (/^0[bBoO]|_/.test(node.text)) record('binary, octal 0o and _-separated number literals', '1.13', file, line);
+          break;
+        case 'call_expression': {
+          const fn = node.childForFieldName('function');
+          if (fn?.type === 'identifier' && BUILTIN_SINCE[fn.text] && !declared.get(file.dir)?.has(fn.text)) {
+            record(`builtin ${fn.text}`, BUILTIN_SINCE[fn.text], file, line);
+          } else if (fn?.type === 'selector_expression' && fn.childForFieldName('operand')?.text === 'unsafe') {
+            const name = fn.childForFieldName('field')?.text ?? '';
+            if (UNSAFE_SINCE[name]) record(`unsafe.${name}`, UNSAFE_SINCE[name], file, line);
+          }
+          break;
+        }
+      }
+    });
+  }
+  return [...uses.values()].sort((a, b) => compareGoVersions(b.since, a.since) || a.feature.localeCompare(b.feature));
+}
+
+export async function analyzeGoVersions(projectRoot: string, options: { signal?: AbortSignal } = {}): Promise<GoVersionReport> {
+  const moduleRoot = findGoModuleRoot(projectRoot);
+  const mod = moduleRoot ? readGoMod(moduleRoot) : null;
+  if (!moduleRoot || !mod?.module) throw new Error('No go.mod found — the toolchain audit reads go directives');
+
+  const { graph, warnings } = await buildModuleGraph(moduleRoot, { signal: options.signal });
+  let installed: string | null = null;
+  try {
+    const result = await runGo(['env', 'GOVERSION'], { cwd: moduleRoot, signal: options.signal });
+    if (result.exitCode === 0) installed = result.stdout.trim() || null;
+  } catch (err) {
+    if (options.signal?.aborted) throw err;
+  }
+
+  // go mod graph reports directives since Go 1.21; older toolchains leave them to the module cache
+  const cache = await goModCacheDir(moduleRoot, options.signal);
+  const ours = mod.go;
+  const modules: ModuleGoVersion[] = [];
+  graph.forEachNode((_node, attrs) => {
+    if (attrs.main) return;
+    let go = attrs.go ?? null;
+    let toolchain = attrs.toolchain ?? null;
+    if (!go) {
+      const file = join(cache, 'cache', 'download', escapeModulePath(attrs.path), '@v', `${escapeModulePath(attrs.version)}.mod`);
+      if (existsSync(file)) {
+        const parsed = parseGoMod(readFileSync(file, 'utf-8'));
+        go = parsed.go;
+        toolchain = parsed.toolchain;
+      }
+    }
+    modules.push({
+      path: attrs.path,
+      version: attrs.version,
+      direct: attrs.direct,
+      go,
+      toolchain,
+      newerThanOurs: Boolean(go && ours && compareGoVersions(go, ours) > 0),
+    });
+  });
+  modules.sort((a, b) => compareGoVersions(b.go ?? '0', a.go ?? '0') || a.path.localeCompare(b.path));
+  const unknown = modules.filter(m => !m.go).length;
+  if (unknown > 0) warnings.push(`${unknown} modules' go directives are unknown — run go mod download, or use Go 1.21+ so go mod graph reports them`);
+
+  const features = detectLanguageFeatures(await loadGoFiles(moduleRoot));
+
+  // The floor: the newest go any dependency declares, or any feature we use needs
+  const depFloor = maxVersion(modules.map(m => m.go).filter((v): v is string => !!v));
+  const featureFloor = maxVersion(features.map(f => f.since));
+  const floorGo = maxVersion([depFloor, featureFloor].filter((v): v is string => !!v)) ?? '1.0';
+  const reasons = [
+    ...modules.filter(m => m.go && compareGoVersions(m.go, floorGo) === 0).map(m => `${m.path} ${m.version} declares go ${m.go}`),
+    ...features.filter(f => compareGoVersions(f.since, floorGo) === 0).map(f => `${f.feature} (${f.example.file}:${f.example.line})`),
+  ];
+
+  const findings: string[] = [];
+  for (const m of modules.filter(m => m.newerThanOurs)) {
+    findings.push(`${m.path} ${m.version} requires go ${m.go}, newer than our go ${ours} — go mod tidy raises ours to match`);
+  }
+  if (ours && featureFloor && compareGoVersions(featureFloor, ours) > 0) {
+    findings.push(`Our code uses Go ${featureFloor} features but go.mod declares go ${ours}: ${features.filter(f => compareGoVersions(f.since, ours) > 0).map(f => f.feature).join(', ')}`);
+  }
+  const needed = maxVersion([ours, mod.toolchain, depFloor].filter((v): v is string => !!v));
+  if (installed && needed && compareGoVersions(installed, needed) < 0) {
+    findings.push(`The installed ${installed} is older than the go ${needed} this build needs — the go command will download a newer toolchain or, with GOTOOLCHAIN=local, fail`);
+  }
+
+  return {
+    module: mod.module,
+    go: ours,
+    toolchain: mod.toolchain,
+    installed,
+    modules,
+    features,
+    floor: { go: floorGo, reasons },
+    findings,
+    warnings,
+  };
+}
@@ -0,0 +1,279 @@
+import { existsSync, mkdtempSync, readFileSync, rmSync, statSync, writeFileSync } from 'fs';
+import { gunzipSync } from 'zlib';
+import { randomUUID } from 'crypto';
+import { tmpdir } from 'os';
+import { join, posix } from 'path';
+import { readTar, type ArchiveEntry } from '../remote/archive.js';
+import { formatImageReference, formatPlatform, pullImage } from '../remote/registry.js';
+import { analyzeBinary, type BinaryModuleVersion, type BinaryReport } from './binary.js';
+
+/**
+ * The Go binaries a container image ships and the modules built into
+ * them. The image is pulled from its registry, or read from a `docker
+ * save` archive; its layers are applied in order, whiteouts included, so
+ * only binaries in the final filesystem count. Each one is inspected like
+ * `depwire binary` inspects a file, and the results are combined into one
+ * module list and a CycloneDX SBOM whose dependency graph runs image →
+ * binary → module.
+ */
+
+export interface ImageBinary {
+  /** Absolute path in the image */
+  path: string;
+  /** Index of the layer it comes from, base layer 0 */
+  layer: number;
+  /** The image's entrypoint (or first command) */
+  entrypoint: boolean;
+  report: BinaryReport;
+}
+
+export interface ImageModule extends BinaryModuleVersion {
+  replace: BinaryModuleVersion | null;
+  /** Paths of the binaries built with this module version */
+  binaries: string[];
+}
+
+export interface ImageReport {
+  image: string;
+  /** Manifest digest; empty for archives without one */
+  digest: string;
+  platform: string | null;
+  layers: number;
+  binaries: ImageBinary[];
+  /** Dependencies over every binary, one entry per module version */
+  modules: ImageModule[];
+  warnings: string[];
+}
+
+export interface ImageOptions {
+  /** os/arch[/variant] to pull from a multi-platform image */
+  platform?: string;
+  signal?: AbortSignal;
+}
+
+/** Layers are image-sized, well past what a module zip may unpack to */
+const MAX_LAYER_SIZE = 2 ** 31 - 1;
+
+const BUILDINFO_MAGIC = Buffer.from('\xff Go buildinf:', 'latin1');
+
+// ELF, Mach-O (32, 64, universal) and PE executables with Go build information
+export function isGoBinary(data: Buffer): boolean {
+  if (data.length < 64) return false;
+  const magic = data.readUInt32BE(0);
+  const executable = magic === 0x7f454c46 || magic === 0xfeedface || magic === 0xfeedfacf || magic === 0xcefaedfe
+    || magic === 0xcffaedfe || magic === 0xcafebabe || data.readUInt16BE(0) === 0x4d5a;
+  return executable && data.includes(BUILDINFO_MAGIC);
+}
+
+function layerEntries(data: Buffer, mediaType = ''): ArchiveEntry[] {
+  if (mediaType.includes('zstd') || data.readUInt32LE(0) === 0xfd2fb528) throw new Error('zstd-compressed layers are not supported');
+  const tar = data[0] === 0x1f && data[1] === 0x8b ? gunzipSync(data, { maxOutputLength: MAX_LAYER_SIZE }) : data;
+  return readTar(tar, MAX_LAYER_SIZE);
+}
+
+/**
+ * The Go binaries of the image's final filesystem. A ".wh.name" entry
+ * deletes name from the layers below, ".wh..wh..opq" everything below in
+ * its directory.
+ */
+export function flattenGoBinaries(layers: ArchiveEntry[][]): Map<string, { layer: number; data: Buffer }> {
+  const binaries = new Map<string, { layer: number; data: Buffer }>();
+  layers.forEach((entries, layer) => {
+    for (const entry of entries) {
+      const base = posix.basename(entry.name);
+      if (!base.startsWith('.wh.')) continue;
+      const dir = posix.dirname(`/${entry.name}`);
+      const opaque = base === '.wh..wh..opq';
+      const target = opaque ? dir : posix.join(dir, base.slice(4));
+      const under = target === '/' ? '/' : `${target}/`;
+      for (const path of [...binaries.keys()]) {
+        if ((!opaque && path === target) || path.startsWith(under)) binaries.delete(path);
+      }
+    }
+    for (const entry of entries) {
+      if (posix.basename(entry.name).startsWith('.wh.')) continue;
+      const path = `/${entry.name.replace(/^\/+/, '')}`;
+      // A file of a higher layer replaces the one below, binary or not
+      if (isGoBinary(entry.data)) binaries.set(path, { layer, data: entry.data });
+      else binaries.delete(path);
+    }
+  });
+  return binaries;
+}
+
+interface ImageLayers {
+  image: string;
+  digest: string;
+  platform: string | null;
+  config: Record<string, any>;
+  layers: ArchiveEntry[][];
+}
+
+// A `docker save` archive: manifest.json names the config and the layer tars, in both its legacy and OCI layouts
+function readImageArchive(file: string): ImageLayers {
+  const entries = new Map(readTar(readFileSync(file), MAX_LAYER_SIZE).map(e => [e.name, e.data]));
+  const manifest = entries.get('manifest.json');
+  if (!manifest) throw new Error(`${file} is not a docker save archive (no manifest.json)`);
+  const [image] = JSON.parse(manifest.toString('utf-8')) as Array<{ Config: string; RepoTags?: string[]; Layers: string[] }>;
+  if (!image) throw new Error(`${file} has no images`);
+  const config = entries.get(image.Config);
+  const layers = image.Layers.map((name) => {
+    const data = entries.get(name);
+    if (!data) throw new Error(`${file}: layer ${name} is missing`);
+    return layerEntries(data);
+  });
+  const parsed = config ? JSON.parse(config.toString('utf-8')) as Record<string, any> : {};
+  return {
+    image: image.RepoTags?.[0] ?? posix.basename(file),
+    digest: '',
+    platform: parsed.os && parsed.architecture ? formatPlatform(parsed) : null,
+    config: parsed,
+    layers,
+  };
+}
+
+async function loadImage(ref: string, options: ImageOptions): Promise<ImageLayers> {
+  if (existsSync(ref) && statSync(ref).isFile()) return readImageArchive(ref);
+  const pulled = await pullImage(ref, options);
+  return {
+    image: formatImageReference(pulled.reference),
+    digest: pulled.digest,
+    platform: pulled.platform ? formatPlatform(pulled.platform) : null,
+    config: pulled.config,
+    layers: pulled.layers.map(l => layerEntries(l.data, l.descriptor.mediaType)),
+  };
+}
+
+// The program the image runs: the entrypoint, or the command without one
+function entrypointOf(config: Record<string, any>): string | null {
+  const run = config.config ?? {};
+  const argv: unknown[] = [...(Array.isArray(run.Entrypoint) ? run.Entrypoint : []), ...(Array.isArray(run.Cmd) ? run.Cmd : [])];
+  const program = typeof argv[0] === 'string' ? argv[0] : null;
+  if (!program || ['/bin/sh', 'sh', '/bin/bash'].includes(program)) return null;
+  return program.startsWith('/') ? program : posix.join(run.WorkingDir || '/', program);
+}
+
+/** Pull or read an image and inspect every Go binary in it */
+export async function analyzeImage(ref: string, options: ImageOptions = {}): Promise<ImageReport> {
+  const image = await loadImage(ref, options);
+  const found = flattenGoBinaries(image.layers);
+  const entrypoint = entrypointOf(image.config);
+  const warnings: string[] = [];
+  const binaries: ImageBinary[] = [];
+
+  const dir = mkdtempSync(join(tmpdir(), 'depwire-image-'));
+  try {
+    let n = 0;
+    for (const [path, { layer, data }] of [...found].sort(([a], [b]) => a.localeCompare(b))) {
+      const file = join(dir, `${n++}-${posix.basename(path)}`);
+      writeFileSync(file, data);
+      try {
+        const report = await analyzeBinary(file, { signal: options.signal });
+        report.binary = path;
+        report.warnings = report.warnings.map(w => w.split(file).join(path));
+        binaries.push({ path, layer, entrypoint: path === entrypoint, report });
+      } catch (err) {
+        if (options.signal?.aborted) throw err;
+        warnings.push(`${path}: ${err instanceof Error ? err.message.split(file).join(path) : err}`);
+      }
+    }
+  } finally {
+    rmSync(dir, { recursive: true, force: true });
+  }
+  if (found.size === 0) warnings.push('No Go binaries with build information in the image');
+
+  const modules = new Map<string, ImageModule>();
+  for (const binary of binaries) {
+    for (const mod of binary.report.modules) {
+      if (mod.main) continue;
+      const key = `${mod.path}@${mod.version}`;
+      let entry = modules.get(key);
+      if (!entry) modules.set(key, entry = { path: mod.path, version: mod.version, sum: mod.sum, replace: mod.replace, binaries: [] });
+      entry.binaries.push(binary.path);
+    }
+  }
+
+  return {
+    image: image.image,
+    digest: image.digest,
+    platform: image.platform,
+    layers: image.layers.length,
+    binaries,
+    modules: [...modules.values()].sort((a, b) => a.path.localeCompare(b.path) || a.version.localeCompare(b.version)),
+    warnings,
+  };
+}
+
+const golangPurl = (path: string, version: string) =>
+  `pkg:golang/${path}${version && version !== '(devel)' ? `@${version}` : ''}`;
+
+/**
+ * The report as a CycloneDX 1.5 SBOM: the image as the described component,
+ * its Go binaries as applications, their modules and Go standard libraries
+ * as libraries, and the dependency graph between them.
+ */
+export function imageSbom(report: ImageReport, toolVersion: string): Record<string, unknown> {
+  const imageRef = report.digest ? `${report.image.replace(/@.*$/, '')}@${report.digest}` : report.image;
+  const name = report.image.replace(/[@:][^/]*$/, '');
+  const components: Array<Record<string, unknown>> = [];
+  const dependencies: Array<{ ref: string; dependsOn: string[] }> = [];
+  const libraries = new Map<string, Record<string, unknown>>();
+
+  const binaryRefs: string[] = [];
+  for (const binary of report.binaries) {
+    const { report: r } = binary;
+    const main = r.modules[0];
+    const ref = `file:${binary.path}`;
+    binaryRefs.push(ref);
+    const properties = [
+      { name: 'depwire:go.version', value: r.goVersion },
+      { name: 'depwire:go.package', value: r.path },
+      ...Object.entries(r.settings).map(([key, value]) => ({ name: `depwire:go.build.${key}`, value })),
+    ];
+    components.push({
+      type: 'application',
+      'bom-ref': ref,
+      name: binary.path,
+      ...(main.version ? { version: main.version } : {}),
+      purl: golangPurl(main.path, main.version),
+      properties,
+    });
+
+    const stdlib = golangPurl('stdlib', r.goVersion.replace(/^go/, '').replace(/ .*$/, ''));
+    libraries.set(stdlib, { type: 'library', 'bom-ref': stdlib, name: 'stdlib', version: r.goVersion, purl: stdlib });
+    const dependsOn = [stdlib];
+    for (const mod of r.modules.slice(1)) {
+      const purl = golangPurl(mod.path, mod.version);
+      dependsOn.push(purl);
+      if (libraries.has(purl)) continue;
+      const props = [
+        ...(mod.sum ? [{ name: 'depwire:go.sum', value: mod.sum }] : []),
+        ...(mod.replace ? [{ name: 'depwire:go.replace', value: `${mod.replace.path}${mod.replace.version ? ` ${mod.replace.version}` : ''}` }] : []),
+      ];
+      libraries.set(purl, { type: 'library', 'bom-ref': purl, name: mod.path, version: mod.version, purl, ...(props.length ? { properties: props } : {}) });
+    }
+    dependencies.push({ ref, dependsOn: [...new Set(dependsOn)] });
+  }
+  components.push(...[...libraries.values()].sort((a, b) => String(a['bom-ref']).localeCompare(String(b['bom-ref']))));
+  for (const ref of [...libraries.keys()].sort()) dependencies.push({ ref, dependsOn: [] });
+
+  return {
+    bomFormat: 'CycloneDX',
+    specVersion: '1.5',
+    serialNumber: `urn:uuid:${randomUUID()}`,
+    version: 1,
+    metadata: {
+      timestamp: new Date().toISOString(),
+      tools: { components: [{ type: 'application', name: 'depwire', version: toolVersion }] },
+      component: {
+        type: 'container',
+        'bom-ref': imageRef,
+        name,
+        ...(report.digest ? { version: report.digest } : {}),
+        ...(report.platform ? { properties: [{ name: 'depwire:image.platform', value: report.platform }] } : {}),
+      },
+    },
+    components,
+    dependencies: [{ ref: imageRef, dependsOn: binaryRefs }, ...dependencies],
+  };
+}
@@ -0,0 +1,277 @@
+import type { Node } from 'web-tree-sitter';
+import { walk, goImports, functionsNamed, goDeclarations, type GoSourceFile } from './source.js';
+import { loadGoProject, type GoPackage, type GoProject } from './packages.js';
+import { fileQualifiers } from './references.js';
+
+/**
+ * Package initialization: which packages run init() (or initialize
+ * package-level vars with function calls), what that code touches at a
+ * coarse level, the order Go runs it in, and blank imports that exist only
+ * to trigger another package's init.
+ */
+
+export type InitEffect = 'network' | 'file' | 'env' | 'exec' | 'goroutine' | 'panic' | 'registration' | 'global-state';
+
+export interface InitSite {
+  file: string;
+  line: number;
+  /** "init" or the package-level variable whose initializer calls functions */
+  kind: 'init' | 'var';
+  name: string;
+  effects: InitEffect[];
+  /** Calls that caused each effect ("os.Getenv"), for the report */
+  evidence: Array<{ effect: InitEffect; call: string; file: string; line: number }>;
+}
+
+export interface PackageInit {
+  dir: string;
+  importPath: string | null;
+  sites: InitSite[];
+  effects: InitEffect[];
+}
+
+export interface SideEffectImport {
+  file: string;
+  line: number;
+  importer: string;
+  path: string;
+  /** What the imported package is known to register, when it's a well-known one */
+  purpose?: string;
+}
+
+export interface InitReport {
+  projectRoot: string;
+  /** Packages with init work, in the order Go initializes them */
+  order: string[];
+  packages: PackageInit[];
+  blankImports: SideEffectImport[];
+}
+
+/** (import path, function) → effect; '*' matches any function of the package */
+const EFFECTS: Array<[string, string, InitEffect]> = [
+  ['net', '*', 'network'],
+  ['net/http', 'Get', 'network'],
+  ['net/http', 'Post', 'network'],
+  ['net/http', 'Head', 'network'],
+  ['net/http', 'ListenAndServe', 'network'],
+  ['net/http', 'Handle', 'registration'],
+  ['net/http', 'HandleFunc', 'registration'],
+  ['net/rpc', '*', 'network'],
+  ['google.golang.org/grpc', 'Dial', 'network'],
+  ['google.golang.org/grpc', 'DialContext', 'network'],
+  ['google.golang.org/grpc', 'NewClient', 'network'],
+  ['database/sql', 'Open', 'network'],
+  ['database/sql', 'Register', 'registration'],
+  ['os', 'Open', 'file'],
+  ['os', 'OpenFile', 'file'],
+  ['os', 'Create', 'file'],
+  ['os', 'ReadFile', 'file'],
+  ['os', 'WriteFile', 'file'],
+  ['os', 'ReadDir', 'file'],
+  ['os', 'Mkdir', 'file'],
+  ['os', 'MkdirAll', 'file'],
+  ['os', 'MkdirTemp', 'file'],
+  ['os', 'CreateTemp', 'file'],
+  ['os', 'Remove', 'file'],
+  ['os', 'RemoveAll', 'file'],
+  ['os', 'Stat', 'file'],
+  ['os', 'Getenv', 'env'],
+  ['os', 'LookupEnv', 'env'],
+  ['os', 'Setenv', 'env'],
+  ['os', 'Environ', 'env'],
+  ['os', 'Exit', 'panic'],
+  ['io/ioutil', '*', 'file'],
+  ['path/filepath', 'Walk', 'file'],
+  ['path/filepath', 'WalkDir', 'file'],
+  ['path/filepath', 'Glob', 'file'],
+  ['os/exec', '*', 'exec'],
+  ['syscall', 'Exec', 'exec'],
+  ['flag', 'Parse', 'env'],
+  ['log', 'Fatal', 'panic'],
+  ['log', 'Fatalf', 'panic'],
+  ['log', 'Panic', 'panic'],
+  ['log', 'Panicf', 'panic'],
+  ['expvar', 'Publish', 'registration'],
+  ['image', 'RegisterFormat', 'registration'],
+  ['encoding/gob', 'Register', 'registration'],
+  ['github.com/prometheus/client_golang/prometheus', 'MustRegister', 'registration'],
+  ['github.com/prometheus/client_golang/prometheus', 'Register', 'registration'],
+  ['github.com/spf13/viper', 'ReadInConfig', 'file'],
+  ['github.com/spf13/viper', 'AutomaticEnv', 'env'],
+  ['github.com/joho/godotenv', 'Load', 'env'],
+];
+
+/** Well-known packages imported for their side effects */
+const KNOWN_SIDE_EFFECTS: Array<[RegExp, string]> = [
+  [/^github\.com\/(go-sql-driver\/mysql|lib\/pq|jackc\/pgx(\/v\d+)?\/stdlib|mattn\/go-sqlite3|denisenkom\/go-mssqldb|microsoft\/go-mssqldb)$/, 'database/sql driver'],
+  [/^modernc\.org\/sqlite$/, 'database/sql driver'],
+  [/^image\/(png|jpeg|gif)$|^golang\.org\/x\/image\//, 'image decoder'],
+  [/^net\/http\/pprof$/, 'registers /debug/pprof handlers on http.DefaultServeMux'],
+  [/^expvar$/, 'registers /debug/vars on http.DefaultServeMux'],
+  [/^embed$/, 'enables //go:embed'],
+  [/^time\/tzdata$/, 'embeds the time zone database'],
+  [/^go\.uber\.org\/automaxprocs$/, 'sets GOMAXPROCS from the container CPU quota'],
+  [/^github\.com\/joho\/godotenv\/autoload$/, 'loads .env into the environment'],
+  [/\/statik$|\/docs$/, 'registers generated assets or API docs'],
+];
+
+export async function analyzeInit(projectRoot: string): Promise<InitReport> {
+  const project = await loadGoProject(projectRoot);
+  const packages: PackageInit[] = [];
+  const blankImports: SideEffectImport[] = [];
+
+  for (const pkg of [...project.packages.values()].sort((a, b) => a.dir.localeCompare(b.dir))) {
+    const sites: InitSite[] = [];
+    const local = localFunctions(pkg);
+    for (const file of pkg.files) {
+      const qualifiers = fileQualifiers(file, project);
+      for (const fn of functionsNamed(file, 'init')) {
+        sites.push(site(file, fn, 'init', 'init', qualifiers, local));
+      }
+      for (const decl of goDeclarations(file)) {
+        if (decl.kind !== 'var') continue;
+        const value = decl.node.childForFieldName('value');
+        if (!value || !hasCall(value)) continue;
+        const found = site(file, value, 'var', decl.name, qualifiers, local);
+        // A var initialized from a plain constructor is ordinary — report it only when it does something notable
+        if (found.effects.length > 0) sites.push({ ...found, line: decl.line });
+      }
+      for (const imp of goImports(file)) {
+        if (imp.kind !== 'blank') continue;
+        const purpose = KNOWN_SIDE_EFFECTS.find(([pattern]) => pattern.test(imp.path))?.[1];
+        blankImports.push({ file: file.file, line: imp.line, importer: pkg.importPath ?? pkg.dir, path: imp.path, purpose });
+      }
+    }
+    if (sites.length === 0) continue;
+    packages.push({
+      dir: pkg.dir,
+      importPath: pkg.importPath,
+      sites,
+      effects: [...new Set(sites.flatMap(s => s.effects))].sort(),
+    });
+  }
+
+  const withInit = new Set(packages.map(p => p.dir));
+  return {
+    projectRoot,
+    order: initOrder(project).filter(dir => withInit.has(dir)).map(dir => project.packages.get(dir)?.importPath ?? dir),
+    packages,
+    blankImports,
+  };
+}
+
+function localFunctions(pkg: GoPackage): Map<string, { file: GoSourceFile; node: Node }> {
+  const map = new Map<string, { file: GoSourceFile; node: Node }>();
+  for (const file of pkg.files) {
+    for (const decl of goDeclarations(file)) {
+      if (decl.kind === 'func' && decl.name !== 'init') map.set(decl.name, { file, node: decl.node });
+    }
+  }
+  return map;
+}
+
+function hasCall(node: Node): boolean {
+  let found = false;
+  walk(node, (child) => {
+    if (found) return false;
+    if (child.type === 'func_literal') return false;
+    if (child.type === 'call_expression') found = true;
+  });
+  return found;
+}
+
+/**
+ * Effects of the code under root, following calls into functions of the
+ * same package (Go runs them during init too).
+ */
+function site(
+  file: GoSourceFile,
+  root: Node,
+  kind: InitSite['kind'],
+  name: string,
+  qualifiers: Map<string, string>,
+  local: Map<string, { file: GoSourceFile; node: Node }>
+): InitSite {
+  const evidence: InitSite['evidence'] = [];
+  const visited = new Set<string>();
+
+  const scan = (node: Node, source: GoSourceFile, depth: number) => {
+    const imports = source === file ? qualifiers : fileQualifiers(source);
+    walk(node, (child) => {
+      if (child.type === 'go_statement') {
+        evidence.push({ effect: 'goroutine', call: 'go', file: source.file, line: child.startPosition.row + 1 });
+      }
+      if (child.type === 'assignment_statement' && kind === 'init') {
+        // Assigning to another package's variable from init mutates global state
+        const left = child.childForFieldName('left')?.namedChildren[0];
+        const operand = left?.type === 'selector_expression' ? left.childForFieldName('operand') : null;
+        if (operand?.type === 'identifier' && imports.has(operand.text)) {
+          evidence.push({ effect: 'global-state', call: left!.text, file: source.file, line: child.startPosition.row + 1 });
+        }
+      }
+      if (child.type !== 'call_expression') return;
+      const fn = child.childForFieldName('function');
+      const line = child.startPosition.row + 1;
+      if (fn?.type === 'identifier') {
+        if (fn.text === 'panic') evidence.push({ effect: 'panic', call: 'panic', file: source.file, line });
+        const target = local.get(fn.text);
+        if (target && !visited.has(fn.text) && depth < 5) {
+          visited.add(fn.text);
+          scan(target.node, target.file, depth + 1);
+        }
+        return;
+      }
+      if (fn?.type !== 'selector_expression') return;
+      const operand = fn.childForFieldName('operand');
+      const field = fn.childForFieldName('field')?.text ?? '';
+      const path = operand?.type === 'identifier' ? imports.get(operand.text) : undefined;
+      if (!path) return;
+      for (const [effectPath, effectFn, effect] of EFFECTS) {
+        if (path === effectPath && (effectFn === '*' || effectFn === field)) {
+          evidence.push({ effect, call: `${path}.${field}`, file: source.file, line });
+          break;
+        }
+      }
+    });
+  };
+
+  scan(root, file, 0);
+  return {
+    file: file.file,
+    line: root.startPosition.row + 1,
+    kind,
+    name,
+    effects: [...new Set(evidence.map(e => e.effect))].sort(),
+    evidence,
+  };
+}
+
+/**
+ * Go's package initialization order (Go 1.21+): repeatedly initialize the
+ * first package, by import path, whose imports are all initialized.
+ */
+export function initOrder(project: GoProject): string[] {
+  const deps = new Map<string, Set<string>>();
+  for (const pkg of project.packages.values()) {
+    const set = new Set<string>();
+    for (const path of pkg.imports.keys()) {
+      const dir = project.modules.dirForImport(path);
+      if (dir && dir !== pkg.dir && project.packages.has(dir)) set.add(dir);
+    }
+    deps.set(pkg.dir, set);
+  }
+  const key = (dir: string) => project.packages.get(dir)?.importPath ?? dir;
+  const order: string[] = [];
+  const done = new Set<string>();
+  const pending = new Set(deps.keys());
+
+  while (pending.size > 0) {
+    const ready = [...pending].filter(dir => [...deps.get(dir)!].every(d => done.has(d)));
+    // An import cycle won't compile anyway — fall back to import path order for what's left
+    const next = (ready.length > 0 ? ready : [...pending]).sort((a, b) => key(a).localeCompare(key(b)))[0];
+    order.push(next);
+    done.add(next);
+    pending.delete(next);
+  }
+  return order;
+}
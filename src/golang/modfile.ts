@@ -0,0 +1,130 @@
+import { existsSync, readFileSync } from 'fs';
+import { join } from 'path';
+
+/**
+ * go.mod parser — module, go/toolchain directives, require, replace,
+ * exclude and retract, in both single-line and block form.
+ */
+
+export interface GoRequire {
+  path: string;
+  version: string;
+  indirect: boolean;
+  line: number;
+}
+
+export interface GoReplace {
+  oldPath: string;
+  oldVersion?: string;
+  /** Module path, or a filesystem path for local replacements */
+  newPath: string;
+  newVersion?: string;
+  /** True when newPath is a local directory (starts with ./ ../ or /) */
+  local: boolean;
+  line: number;
+}
+
+export interface GoModFile {
+  module: string | null;
+  go: string | null;
+  toolchain: string | null;
+  require: GoRequire[];
+  replace: GoReplace[];
+  exclude: Array<{ path: string; version: string; line: number }>;
+  retract: Array<{ version: string; line: number }>;
+}
+
+function stripComment(line: string): { text: string; comment: string } {
+  const index = line.indexOf('//');
+  if (index < 0) return { text: line.trim(), comment: '' };
+  return { text: line.slice(0, index).trim(), comment: line.slice(index + 2).trim() };
+}
+
+function unquote(token: string): string {
+  return token.length >= 2 && (token.startsWith('"') || token.startsWith('`')) ? token.slice(1, -1) : token;
+}
+
+function isLocalPath(path: string): boolean {
+  return path.startsWith('./') || path.startsWith('../') || path.startsWith('/') || /^[A-Za-z]:[\\/]/.test(path);
+}
+
+export function parseGoMod(content: string): GoModFile {
+  const mod: GoModFile = { module: null, go: null, toolchain: null, require: [], replace: [], exclude: [], retract: [] };
+  let block: string | null = null;
+
+  const apply = (verb: string, args: string[], comment: string, line: number) => {
+    switch (verb) {
+      case 'module':
+        mod.module = unquote(args[0] ?? '') || null;
+        break;
+      case 'go':
+        mod.go = args[0] ?? null;
+        break;
+      case 'toolchain':
+        mod.toolchain = args[0] ?? null;
+        break;
+      case 'require':
+        if (args.length >= 2) {
+          mod.require.push({ path: unquote(args[0]), version: args[1], indirect: /\bindirect\b/.test(comment), line });
+        }
+        break;
+      case 'exclude':
+        if (args.length >= 2) mod.exclude.push({ path: unquote(args[0]), version: args[1], line });
+        break;
+      case 'retract':
+        if (args.length >= 1) mod.retract.push({ version: args.join(' '), line });
+        break;
+      case 'replace': {
+        const arrow = args.indexOf('=>');
+        if (arrow < 1 || arrow === args.length - 1) break;
+        const [oldPath, oldVersion] = args.slice(0, arrow);
+        const [newPath, newVersion] = args.slice(arrow + 1);
+        mod.replace.push({
+          oldPath: unquote(oldPath),
+          oldVersion,
+          newPath: unquote(newPath),
+          newVersion,
+          local: isLocalPath(unquote(newPath)),
+          line,
+        });
+        break;
+      }
+    }
+  };
+
+  content.split('\n').forEach((raw, index) => {
+    const lineNo = index + 1;
+    const { text, comment } = stripComment(raw);
+    if (!text) return;
+
+    if (block) {
+      if (text === ')') {
+        block = null;
+        return;
+      }
+      apply(block, text.split(/\s+/), comment, lineNo);
+      return;
+    }
+
+    const tokens = text.split(/\s+/);
+    if (tokens[1] === '(' && tokens.length === 2) {
+      block = tokens[0];
+      return;
+    }
+    apply(tokens[0], tokens.slice(1), comment, lineNo);
+  });
+
+  return mod;
+}
+
+/** Read and parse dir/go.mod, or null if there is none */
+export function readGoMod(dir: string): GoModFile | null {
+  const path = join(dir, 'go.mod');
+  if (!existsSync(path)) return null;
+  return parseGoMod(readFileSync(path, 'utf-8'));
+}
+
+/** Go import path of a package directory (relative to the module root) */
+export function importPathOf(modulePath: string, dir: string): string {
+  return dir === '.' || dir === '' ? modulePath : `${modulePath}/${dir}`;
+}
@@ -0,0 +1,111 @@
+import { DirectedGraph } from 'graphology';
+import { runGo, findGoModuleRoot } from './toolchain.js';
+import { readGoMod } from './modfile.js';
+
+/**
+ * The module requirement graph — one node per module path, an edge for each
+ * requirement — from `go mod graph`, or the main go.mod alone without a
+ * toolchain. Supply-chain enrichments (scorecards, licenses) are stored as
+ * node attributes.
+ */
+
+export interface ModuleNodeAttributes {
+  path: string;
+  /** Selected version; '' for the main module */
+  version: string;
+  main: boolean;
+  /** Required by the main module without // indirect */
+  direct: boolean;
+  /** go and toolchain directives of the selected version's go.mod, when go mod graph reports them (Go 1.21+) */
+  go?: string;
+  toolchain?: string;
+  [enrichment: string]: unknown;
+}
+
+export interface ModuleEdgeAttributes {
+  /** The version the requiring module asks for */
+  version: string;
+}
+
+export type ModuleGraph = DirectedGraph<ModuleNodeAttributes, ModuleEdgeAttributes>;
+
+export async function buildModuleGraph(projectRoot: string, options: { signal?: AbortSignal } = {}): Promise<{ graph: ModuleGraph; via: 'go mod graph' | 'go.mod'; warnings: string[] }> {
+  const graph: ModuleGraph = new DirectedGraph();
+  const warnings: string[] = [];
+  const moduleRoot = findGoModuleRoot(projectRoot);
+  const mod = moduleRoot ? readGoMod(moduleRoot) : null;
+  if (!moduleRoot || !mod?.module) {
+    return { graph, via: 'go.mod', warnings: ['No go.mod found'] };
+  }
+
+  const main = mod.module;
+  const selected = new Map(mod.require.map(r => [r.path, r.version]));
+  graph.addNode(main, { path: main, version: '', main: true, direct: false, go: mod.go ?? undefined, toolchain: mod.toolchain ?? undefined });
+  for (const r of mod.require) {
+    graph.mergeNode(r.path, { path: r.path, version: r.version, main: false, direct: !r.indirect });
+    graph.mergeEdge(main, r.path, { version: r.version });
+  }
+
+  let output: string | null = null;
+  try {
+    const result = await runGo(['mod', 'graph'], { cwd: moduleRoot, signal: options.signal });
+    if (result.exitCode === 0) output = result.stdout;
+    else warnings.push(`go mod graph failed: ${result.stderr.trim().split('\n')[0]}`);
+  } catch (err) {
+    if (options.signal?.aborted) throw err;
+    warnings.push(`${err instanceof Error ? err.message : err}`);
+  }
+  if (output === null) return { graph, via: 'go.mod', warnings };
+
+  // Lines are "from@version to@version"; the main module has no version
+  const directives = new Map<string, { go?: string; toolchain?: string }>();
+  for (const line of output.split('\n')) {
+    const [from, to] = line.trim().split(/\s+/);
+    if (!from || !to) continue;
+    const [fromPath] = splitVersion(from);
+    const [toPath, toVersion] = splitVersion(to);
+    if (fromPath === 'go' || fromPath === 'toolchain') continue;
+    // "m@v go@1.21" and "m@v toolchain@go1.22.1" carry the go.mod directives
+    if (toPath === 'go' || toPath === 'toolchain') {
+      if (!directives.has(from)) directives.set(from, {});
+      directives.get(from)![toPath] = toVersion;
+      continue;
+    }
+    for (const path of [fromPath, toPath]) {
+      if (!graph.hasNode(path)) graph.addNode(path, { path, version: '', main: false, direct: false });
+    }
+    // go.mod lists every selected version since Go 1.17; otherwise the highest requested wins
+    if (!selected.has(toPath) && compareVersions(toVersion, graph.getNodeAttribute(toPath, 'version')) > 0) {
+      graph.setNodeAttribute(toPath, 'version', toVersion);
+    }
+    if (fromPath !== toPath) graph.mergeEdge(fromPath, toPath, { version: toVersion });
+  }
+  graph.forEachNode((node, attrs) => {
+    if (attrs.main) return;
+    const found = directives.get(`${attrs.path}@${attrs.version}`);
+    if (found?.go) graph.setNodeAttribute(node, 'go', found.go);
+    if (found?.toolchain) graph.setNodeAttribute(node, 'toolchain', found.toolchain);
+  });
+  return { graph, via: 'go mod graph', warnings };
+}
+
+function splitVersion(entry: string): [string, string] {
+  const at = entry.lastIndexOf('@');
+  return at < 0 ? [entry, ''] : [entry.slice(0, at), entry.slice(at + 1)];
+}
+
+/** Semver-ish comparison of Go module versions (v1.2.3, pseudo-versions compare by prefix) */
+export function compareVersions(a: string, b: string): number {
+  const parse = (v: string) => {
+    const m = /^v(\d+)\.(\d+)\.(\d+)/.exec(v);
+    return m ? [Number(m[1]), Number(m[2]), Number(m[3])] : [0, 0, 0];
+  };
+  const [pa, pb] = [parse(a), parse(b)];
+  for (let i = 0; i < 3; i++) {
+    if (pa[i] !== pb[i]) return pa[i] - pb[i];
+  }
+  // A prerelease or pseudo-version sorts before the release
+  const pre = (v: string) => /^v\d+\.\d+\.\d+-/.test(v);
+  if (pre(a) !== pre(b)) return pre(a) ? -1 : 1;
+  return a.localeCompare(b);
+}
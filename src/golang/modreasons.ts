@@ -0,0 +1,140 @@
+import { buildModuleGraph } from './modgraph.js';
+import { goListDeps, type GoListPackage } from './deps.js';
+import { findGoModuleRoot } from './toolchain.js';
+
+/**
+ * The module graph with the package-level reasons for each edge: module
+ * A requires B because package a/x imports b/y. `go mod graph` lists the
+ * requirements; `go list -deps` says which imports exercise them. Edges
+ * without any import are requirements kept only for version selection
+ * (or by tests, when tests are excluded) — the ones worth questioning.
+ */
+
+export interface PackageImport {
+  /** Importing package, in the requiring module */
+  from: string;
+  /** Imported package, in the required module */
+  to: string;
+}
+
+export interface ModuleRequirement {
+  /** Requiring module */
+  module: string;
+  /** The version it asks for */
+  version: string;
+  /** Imports that use the requirement; empty when nothing imports it */
+  imports: PackageImport[];
+  /** Imported without a requirement edge (pre-1.17 go.mod, or a pruned graph) */
+  implicit?: boolean;
+}
+
+export interface ExplainedModule {
+  path: string;
+  /** Selected version */
+  version: string;
+  direct: boolean;
+  requiredBy: ModuleRequirement[];
+  /** Shortest import chain from a main-module package into the module, or null when no package of it is built */
+  why: string[] | null;
+}
+
+export interface ModuleGraphExplanation {
+  main: string;
+  via: 'go mod graph' | 'go.mod';
+  tests: boolean;
+  modules: ExplainedModule[];
+  warnings: string[];
+}
+
+export interface ExplainModuleGraphOptions {
+  /** Count imports from _test.go files (go list -test) */
+  tests?: boolean;
+  signal?: AbortSignal;
+}
+
+// go list -test reports test variants as "p [p.test]" and test mains as "p.test"
+const basePath = (importPath: string) => importPath.replace(/ \[.*\]$/, '');
+
+export async function explainModuleGraph(projectRoot: string, options: ExplainModuleGraphOptions = {}): Promise<ModuleGraphExplanation> {
+  const moduleRoot = findGoModuleRoot(projectRoot);
+  if (!moduleRoot) throw new Error('No go.mod found — modgraph explains Go module requirements');
+  const { graph, via, warnings } = await buildModuleGraph(moduleRoot, { signal: options.signal });
+  const main = graph.findNode((_node, attrs) => attrs.main);
+  if (!main) throw new Error('No main module in the module graph');
+
+  let listed: GoListPackage[] = [];
+  try {
+    listed = await goListDeps(moduleRoot, ['./...'], options.signal, options.tests ? ['-test'] : []);
+  } catch (err) {
+    if (options.signal?.aborted) throw err;
+    warnings.push(`Package-level reasons unavailable: ${err instanceof Error ? err.message : err}`);
+  }
+
+  // Package → module, then every import that crosses a module boundary
+  const moduleOf = new Map<string, string>();
+  const importsOf = new Map<string, string[]>();
+  for (const pkg of listed) {
+    if (pkg.Standard || !pkg.Module) continue;
+    const path = basePath(pkg.ImportPath);
+    if (path.endsWith('.test')) continue;
+    moduleOf.set(path, pkg.Module.Path);
+    const imports = importsOf.get(path) ?? [];
+    for (const imp of pkg.Imports ?? []) {
+      const target = basePath(imp);
+      if (!imports.includes(target)) imports.push(target);
+    }
+    importsOf.set(path, imports);
+  }
+  const crossing = new Map<string, PackageImport[]>();
+  for (const [from, imports] of importsOf) {
+    const fromModule = moduleOf.get(from)!;
+    for (const to of imports) {
+      const toModule = moduleOf.get(to);
+      if (!toModule || toModule === fromModule) continue;
+      const key = `${fromModule}\0${toModule}`;
+      if (!crossing.has(key)) crossing.set(key, []);
+      crossing.get(key)!.push({ from, to });
+    }
+  }
+
+  // Shortest chains from main-module packages, breadth first
+  const chain = new Map<string, string[]>();
+  const queue: string[] = [];
+  for (const [path, module] of moduleOf) {
+    if (module === main) {
+      chain.set(path, [path]);
+      queue.push(path);
+    }
+  }
+  for (let i = 0; i < queue.length; i++) {
+    for (const next of importsOf.get(queue[i]) ?? []) {
+      if (chain.has(next) || !moduleOf.has(next)) continue;
+      chain.set(next, [...chain.get(queue[i])!, next]);
+      queue.push(next);
+    }
+  }
+  const whyModule = new Map<string, string[]>();
+  for (const path of queue) {
+    const module = moduleOf.get(path)!;
+    if (!whyModule.has(module)) whyModule.set(module, chain.get(path)!);
+  }
+
+  const modules: ExplainedModule[] = [];
+  graph.forEachNode((node, attrs) => {
+    if (attrs.main) return;
+    const requiredBy: ModuleRequirement[] = graph.inEdges(node).map(edge => {
+      const from = graph.source(edge);
+      const imports = (crossing.get(`${from}\0${node}`) ?? []).sort((a, b) => a.from.localeCompare(b.from) || a.to.localeCompare(b.to));
+      return { module: from, version: graph.getEdgeAttribute(edge, 'version'), imports };
+    });
+    for (const [key, imports] of crossing) {
+      const [from, to] = key.split('\0');
+      if (to === node && !graph.hasEdge(from, node)) requiredBy.push({ module: from, version: '', imports, implicit: true });
+    }
+    requiredBy.sort((a, b) => Number(b.module === main) - Number(a.module === main) || b.imports.length - a.imports.length || a.module.localeCompare(b.module));
+    modules.push({ path: attrs.path, version: attrs.version, direct: attrs.direct, requiredBy, why: whyModule.get(node) ?? null });
+  });
+  modules.sort((a, b) => Number(b.direct) - Number(a.direct) || a.path.localeCompare(b.path));
+
+  return { main, via, tests: Boolean(options.tests), modules, warnings };
+}
@@ -0,0 +1,87 @@
+import { readdirSync, lstatSync } from 'fs';
+import { join, relative } from 'path';
+import { readGoMod, type GoModFile } from './modfile.js';
+
+/**
+ * The Go modules in a project tree (multi-module repos have several go.mod
+ * files) and mapping between import paths and project-relative directories.
+ */
+
+export interface GoModule {
+  /** Module path from the module directive */
+  path: string;
+  /** Module root, project-relative ('.' for the project root) */
+  dir: string;
+  /** Project-relative path of the go.mod file */
+  goModFile: string;
+  mod: GoModFile;
+}
+
+const SKIP_DIRS = new Set(['node_modules', 'vendor', 'dist', 'build', 'testdata']);
+
+export function findGoModules(projectRoot: string): GoModule[] {
+  const modules: GoModule[] = [];
+
+  const visit = (dir: string) => {
+    let entries: string[];
+    try {
+      entries = readdirSync(dir);
+    } catch {
+      return;
+    }
+    if (entries.includes('go.mod')) {
+      const mod = readGoMod(dir);
+      if (mod?.module) {
+        const rel = relative(projectRoot, dir).split('\\').join('/') || '.';
+        modules.push({ path: mod.module, dir: rel, goModFile: rel === '.' ? 'go.mod' : `${rel}/go.mod`, mod });
+      }
+    }
+    for (const entry of entries) {
+      if (entry.startsWith('.') || entry.startsWith('_') || SKIP_DIRS.has(entry)) continue;
+      const full = join(dir, entry);
+      try {
+        if (lstatSync(full).isDirectory()) visit(full);
+      } catch { /* unreadable */ }
+    }
+  };
+
+  visit(projectRoot);
+  return modules.sort((a, b) => a.dir.localeCompare(b.dir));
+}
+
+export class GoModuleIndex {
+  readonly modules: GoModule[];
+
+  constructor(modules: GoModule[]) {
+    // Longest paths first so nested modules win
+    this.modules = [...modules].sort((a, b) => b.path.length - a.path.length);
+  }
+
+  /** The module that owns an import path, if it's in this project */
+  moduleForImport(importPath: string): GoModule | null {
+    return this.modules.find(m => importPath === m.path || importPath.startsWith(`${m.path}/`)) ?? null;
+  }
+
+  /** The module a project-relative directory belongs to */
+  moduleForDir(dir: string): GoModule | null {
+    const byDir = [...this.modules].sort((a, b) => b.dir.length - a.dir.length);
+    return byDir.find(m => m.dir === '.' || dir === m.dir || dir.startsWith(`${m.dir}/`)) ?? null;
+  }
+
+  /** Project-relative directory for a local import path, or null for external imports */
+  dirForImport(importPath: string): string | null {
+    const mod = this.moduleForImport(importPath);
+    if (!mod) return null;
+    const sub = importPath.slice(mod.path.length).replace(/^\//, '');
+    if (mod.dir === '.') return sub || '.';
+    return sub ? `${mod.dir}/${sub}` : mod.dir;
+  }
+
+  /** Import path for a project-relative package directory, or null outside any module */
+  importForDir(dir: string): string | null {
+    const mod = this.moduleForDir(dir);
+    if (!mod) return null;
+    const sub = mod.dir === '.' ? (dir === '.' ? '' : dir) : dir.slice(mod.dir.length).replace(/^\//, '');
+    return sub ? `${mod.path}/${sub}` : mod.path;
+  }
+}
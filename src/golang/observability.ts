@@ -0,0 +1,238 @@
+import type { DirectedGraph } from 'graphology';
+import { walk, goImports, type GoSourceFile } from './source.js';
+import { loadGoProject, type GoProject } from './packages.js';
+import { fileQualifiers } from './references.js';
+import { packageKey } from './callgraph.js';
+
+/**
+ * Which logging, metrics and tracing libraries each package uses — zap next
+ * to logrus next to slog, prometheus next to OpenTelemetry — with the call
+ * sites, to plan consolidating on one per concern. Libraries are recognized
+ * by import path; uses are the qualified identifiers of their packages
+ * (zap.String, slog.Info, prometheus.NewCounterVec), so method calls on a
+ * *zap.Logger held in a field count once, where the logger is built.
+ */
+
+export type ObservabilityCategory = 'logging' | 'metrics' | 'tracing';
+
+export const OBSERVABILITY_CATEGORIES: ObservabilityCategory[] = ['logging', 'metrics', 'tracing'];
+
+interface LibrarySignature {
+  library: string;
+  category: ObservabilityCategory;
+}
+
+/** Import path (or prefix, at a path boundary) → library; the longest match wins */
+const LIBRARIES: Record<string, LibrarySignature> = {
+  log: { library: 'log', category: 'logging' },
+  'log/slog': { library: 'slog', category: 'logging' },
+  'go.uber.org/zap': { library: 'zap', category: 'logging' },
+  'github.com/sirupsen/logrus': { library: 'logrus', category: 'logging' },
+  'github.com/rs/zerolog': { library: 'zerolog', category: 'logging' },
+  'github.com/go-kit/log': { library: 'go-kit/log', category: 'logging' },
+  'github.com/go-kit/kit/log': { library: 'go-kit/log', category: 'logging' },
+  'github.com/golang/glog': { library: 'glog', category: 'logging' },
+  'k8s.io/klog': { library: 'klog', category: 'logging' },
+  'github.com/hashicorp/go-hclog': { library: 'hclog', category: 'logging' },
+  'github.com/go-logr/logr': { library: 'logr', category: 'logging' },
+  'github.com/inconshreveable/log15': { library: 'log15', category: 'logging' },
+  'github.com/apex/log': { library: 'apex/log', category: 'logging' },
+  'github.com/charmbracelet/log': { library: 'charmbracelet/log', category: 'logging' },
+  'go.opentelemetry.io/otel/log': { library: 'otel', category: 'logging' },
+  'go.opentelemetry.io/contrib/bridges': { library: 'otel', category: 'logging' },
+
+  expvar: { library: 'expvar', category: 'metrics' },
+  'github.com/prometheus/client_golang': { library: 'prometheus', category: 'metrics' },
+  'go.opentelemetry.io/otel/metric': { library: 'otel', category: 'metrics' },
+  'go.opentelemetry.io/otel/sdk/metric': { library: 'otel', category: 'metrics' },
+  'go.opentelemetry.io/otel/exporters/prometheus': { library: 'otel', category: 'metrics' },
+  'go.opentelemetry.io/otel/exporters/otlp/otlpmetric': { library: 'otel', category: 'metrics' },
+  'go.opencensus.io/stats': { library: 'opencensus', category: 'metrics' },
+  'github.com/DataDog/datadog-go': { library: 'datadog-statsd', category: 'metrics' },
+  'github.com/rcrowley/go-metrics': { library: 'go-metrics', category: 'metrics' },
+  'github.com/armon/go-metrics': { library: 'go-metrics', category: 'metrics' },
+  'github.com/hashicorp/go-metrics': { library: 'go-metrics', category: 'metrics' },
+  'github.com/VictoriaMetrics/metrics': { library: 'victoriametrics', category: 'metrics' },
+  'github.com/cactus/go-statsd-client': { library: 'statsd', category: 'metrics' },
+
+  'go.opentelemetry.io/otel': { library: 'otel', category: 'tracing' },
+  'go.opentelemetry.io/contrib': { library: 'otel', category: 'tracing' },
+  'go.opencensus.io': { library: 'opencensus', category: 'tracing' },
+  'github.com/opentracing/opentracing-go': { library: 'opentracing', category: 'tracing' },
+  'gopkg.in/DataDog/dd-trace-go.v1': { library: 'dd-trace', category: 'tracing' },
+  'github.com/DataDog/dd-trace-go': { library: 'dd-trace', category: 'tracing' },
+  'github.com/uber/jaeger-client-go': { library: 'jaeger', category: 'tracing' },
+  'github.com/openzipkin/zipkin-go': { library: 'zipkin', category: 'tracing' },
+};
+
+const PREFIXES = Object.keys(LIBRARIES).sort((a, b) => b.length - a.length);
+
+export interface ObservabilitySite {
+  library: string;
+  category: ObservabilityCategory;
+  /** "zap.NewProduction", "slog.Info", "prometheus.CounterOpts" */
+  api: string;
+  file: string;
+  line: number;
+  /** Called, rather than used as a type or value */
+  call: boolean;
+}
+
+export interface PackageLibraryUse {
+  library: string;
+  category: ObservabilityCategory;
+  /** Import paths of the library's packages the package imports */
+  imports: string[];
+  calls: number;
+  references: number;
+  sites: ObservabilitySite[];
+}
+
+export interface PackageObservability {
+  package: string;
+  dir: string;
+  libraries: PackageLibraryUse[];
+}
+
+export interface LibraryUsage {
+  library: string;
+  category: ObservabilityCategory;
+  /** Keys of the packages using it */
+  packages: string[];
+  calls: number;
+  references: number;
+}
+
+export interface ObservabilityReport {
+  projectRoot: string;
+  packages: PackageObservability[];
+  libraries: LibraryUsage[];
+  /** Concerns served by more than one library, the consolidation candidates */
+  mixed: Array<{ category: ObservabilityCategory; libraries: string[] }>;
+}
+
+/** The observability library an import path belongs to, if any */
+export function observabilityLibrary(importPath: string): LibrarySignature | null {
+  const prefix = PREFIXES.find(p => importPath === p || importPath.startsWith(`${p}/`));
+  return prefix ? LIBRARIES[prefix] : null;
+}
+
+export async function analyzeObservability(projectRoot: string): Promise<ObservabilityReport> {
+  return observabilityInventory(await loadGoProject(projectRoot));
+}
+
+export function observabilityInventory(project: GoProject): ObservabilityReport {
+  const packages: PackageObservability[] = [];
+  for (const pkg of project.packages.values()) {
+    const uses = new Map<string, PackageLibraryUse>();
+    const useOf = (signature: LibrarySignature) => {
+      const key = `${signature.category}\0${signature.library}`;
+      let use = uses.get(key);
+      if (!use) {
+        use = { ...signature, imports: [], calls: 0, references: 0, sites: [] };
+        uses.set(key, use);
+      }
+      return use;
+    };
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      for (const imp of goImports(file)) {
+        const signature = observabilityLibrary(imp.path);
+        if (!signature) continue;
+        const use = useOf(signature);
+        if (!use.imports.includes(imp.path)) use.imports.push(imp.path);
+      }
+      for (const site of observabilitySites(file, project)) {
+        const use = useOf(site);
+        use.sites.push(site);
+        use.references++;
+        if (site.call) use.calls++;
+      }
+    }
+    if (uses.size === 0) continue;
+    packages.push({
+      package: packageKey(pkg),
+      dir: pkg.dir,
+      libraries: [...uses.values()].sort((a, b) => a.category.localeCompare(b.category) || b.references - a.references),
+    });
+  }
+  packages.sort((a, b) => a.package.localeCompare(b.package));
+
+  const libraries = new Map<string, LibraryUsage>();
+  for (const pkg of packages) {
+    for (const use of pkg.libraries) {
+      const key = `${use.category}\0${use.library}`;
+      const usage = libraries.get(key) ?? { library: use.library, category: use.category, packages: [], calls: 0, references: 0 };
+      usage.packages.push(pkg.package);
+      usage.calls += use.calls;
+      usage.references += use.references;
+      libraries.set(key, usage);
+    }
+  }
+  const sorted = [...libraries.values()].sort((a, b) =>
+    OBSERVABILITY_CATEGORIES.indexOf(a.category) - OBSERVABILITY_CATEGORIES.indexOf(b.category) || b.packages.length - a.packages.length || a.library.localeCompare(b.library));
+  const mixed = OBSERVABILITY_CATEGORIES
+    .map(category => ({ category, libraries: sorted.filter(l => l.category === category).map(l => l.library) }))
+    .filter(m => m.libraries.length > 1);
+  return { projectRoot: project.projectRoot, packages, libraries: sorted, mixed };
+}
+
+/** Qualified uses of observability packages in a file */
+export function observabilitySites(file: GoSourceFile, project?: GoProject): ObservabilitySite[] {
+  const libraries = new Map<string, LibrarySignature>();
+  for (const [qualifier, path] of fileQualifiers(file, project)) {
+    const signature = observabilityLibrary(path);
+    if (signature) libraries.set(qualifier, signature);
+  }
+  if (libraries.size === 0) return [];
+
+  const sites: ObservabilitySite[] = [];
+  walk(file.root, (node) => {
+    if (node.type !== 'selector_expression' && node.type !== 'qualified_type') return;
+    const qualifier = node.type === 'qualified_type' ? node.childForFieldName('package') : node.childForFieldName('operand');
+    const name = node.type === 'qualified_type' ? node.childForFieldName('name') : node.childForFieldName('field');
+    const signature = qualifier?.type === 'identifier' || qualifier?.type === 'package_identifier' ? libraries.get(qualifier.text) : undefined;
+    if (!signature || !name) return;
+    const parent = node.parent;
+    sites.push({
+      ...signature,
+      api: `${qualifier!.text}.${name.text}`,
+      file: file.file,
+      line: node.startPosition.row + 1,
+      call: parent?.type === 'call_expression' && parent.childForFieldName('function')?.startIndex === node.startIndex,
+    });
+    return false;
+  });
+  return sites;
+}
+
+/**
+ * Set an `observability` attribute (library names, sorted) on the nodes of
+ * Go functions, methods and other declarations that use observability
+ * libraries in a built graph, given its non-test Go files parsed
+ * (parseGoSources).
+ */
+export function annotateObservability(sources: GoSourceFile[], graph: DirectedGraph): number {
+  const byFile = new Map<string, string[]>();
+  graph.forEachNode((id, attrs) => {
+    if (!String(attrs.filePath).endsWith('.go')) return;
+    if (!byFile.has(attrs.filePath)) byFile.set(attrs.filePath, []);
+    byFile.get(attrs.filePath)!.push(id);
+  });
+
+  let annotated = 0;
+  for (const file of sources) {
+    // Most files import none of these; skip walking them
+    if (!PREFIXES.some(p => file.source.includes(`"${p}`))) continue;
+    const sites = observabilitySites(file);
+    for (const id of byFile.get(file.file) ?? []) {
+      const attrs = graph.getNodeAttributes(id);
+      if (attrs.name === '__file__') continue;
+      const used = new Set(sites.filter(s => s.line >= attrs.startLine && s.line <= attrs.endLine).map(s => s.library));
+      if (used.size === 0) continue;
+      graph.setNodeAttribute(id, 'observability', [...used].sort());
+      annotated++;
+    }
+  }
+  return annotated;
+}
@@ -0,0 +1,94 @@
+import { DirectedGraph } from 'graphology';
+import { loadGoFiles, goImports, type GoSourceFile, type GoImport } from './source.js';
+import { findGoModules, GoModuleIndex } from './modules.js';
+
+/**
+ * Go packages of a project: files grouped by directory, with their imports
+ * resolved to local package directories where possible.
+ */
+
+export interface GoPackage {
+  /** Project-relative directory */
+  dir: string;
+  importPath: string | null;
+  /** Package name of the non-test files */
+  name: string;
+  files: GoSourceFile[];
+  /** Imports of non-test files, by import path */
+  imports: Map<string, Array<GoImport & { file: string }>>;
+  /** Imports of _test.go files, by import path */
+  testImports: Map<string, Array<GoImport & { file: string }>>;
+}
+
+export interface GoProject {
+  projectRoot: string;
+  modules: GoModuleIndex;
+  packages: Map<string, GoPackage>;
+}
+
+export async function loadGoProject(projectRoot: string, options: { tests?: boolean } = {}): Promise<GoProject> {
+  return groupGoPackages(projectRoot, await loadGoFiles(projectRoot, { tests: options.tests }));
+}
+
+/** A project from files that are already parsed, for callers that can't wait on loadGoProject */
+export function groupGoPackages(projectRoot: string, files: GoSourceFile[]): GoProject {
+  const modules = new GoModuleIndex(findGoModules(projectRoot));
+  const packages = new Map<string, GoPackage>();
+
+  for (const file of files) {
+    let pkg = packages.get(file.dir);
+    if (!pkg) {
+      pkg = {
+        dir: file.dir,
+        importPath: modules.importForDir(file.dir),
+        name: '',
+        files: [],
+        imports: new Map(),
+        testImports: new Map(),
+      };
+      packages.set(file.dir, pkg);
+    }
+    pkg.files.push(file);
+    if (!file.isTest && !pkg.name) pkg.name = file.packageName;
+
+    const target = file.isTest ? pkg.testImports : pkg.imports;
+    for (const imp of goImports(file)) {
+      if (!target.has(imp.path)) target.set(imp.path, []);
+      target.get(imp.path)!.push({ ...imp, file: file.file });
+    }
+  }
+
+  return { projectRoot, modules, packages };
+}
+
+/**
+ * Package import graph: nodes are package directories, edges are local
+ * (in-project) imports from non-test files. This is the graph Go's
+ * import-cycle rule applies to.
+ */
+export function toGoImportGraph(project: GoProject): DirectedGraph<{ importPath: string | null }, { count: number }> {
+  const graph = new DirectedGraph<{ importPath: string | null }, { count: number }>();
+  for (const pkg of project.packages.values()) {
+    if (!graph.hasNode(pkg.dir)) graph.addNode(pkg.dir, { importPath: pkg.importPath });
+  }
+  for (const pkg of project.packages.values()) {
+    for (const [path, uses] of pkg.imports) {
+      const dir = project.modules.dirForImport(path);
+      if (!dir || dir === pkg.dir || !graph.hasNode(dir)) continue;
+      graph.mergeEdge(pkg.dir, dir, { count: uses.length });
+    }
+  }
+  return graph;
+}
+
+/**
+ * Go's internal-package rule: a package at .../a/internal/b may only be
+ * imported by packages rooted at .../a.
+ */
+export function internalImportAllowed(importerPath: string, importedPath: string): boolean {
+  const parts = importedPath.split('/');
+  const index = parts.lastIndexOf('internal');
+  if (index < 0) return true;
+  const parent = parts.slice(0, index).join('/');
+  return parent === '' || importerPath === parent || importerPath.startsWith(`${parent}/`);
+}
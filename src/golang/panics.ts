@@ -0,0 +1,219 @@
+import type { Node } from 'web-tree-sitter';
+import { walk } from './source.js';
+import { loadGoProject } from './packages.js';
+import { loadGoCallGraph, functionFile, type GoCall, type GoCallGraph, type GoFunction } from './callgraph.js';
+
+/**
+ * Exported functions a panic can escape from: the function, or something
+ * it calls, panics, and no function on the way has a deferred recover().
+ * For library layers that promise errors instead of panics, each exposed
+ * function comes with the shortest chain down to the panic.
+ *
+ * A function recovers when it defers a func literal calling recover(), or a
+ * function that does. Panics in the deferred function itself (re-panics)
+ * still escape. Calls in go statements are not followed: a panic in another
+ * goroutine crashes the program instead of unwinding the caller — depwire
+ * goroutines lists those.
+ */
+
+const PANIC_CALLS = new Set(['log.Panic', 'log.Panicf', 'log.Panicln']);
+
+export interface PanicStep {
+  function: string;
+  package: string;
+  /** The call to the next function, or the panic for the last */
+  file: string;
+  line: number;
+}
+
+export interface PanicExposure {
+  /** Exported function a panic escapes from */
+  function: string;
+  package: string;
+  file: string;
+  line: number;
+  /** "panic" or "log.Panicf" */
+  call: string;
+  /** function → … → the function that panics */
+  chain: PanicStep[];
+  /** Some call in the chain was matched by method name only */
+  approximate: boolean;
+}
+
+export interface PanicPackage {
+  package: string;
+  dir: string;
+  /** Exported functions and methods of exported types */
+  exported: number;
+  exposed: PanicExposure[];
+}
+
+export interface PanicReport {
+  projectRoot: string;
+  packages: PanicPackage[];
+  summary: { exported: number; exposed: number; panicking: number; recovering: number };
+  warnings: string[];
+}
+
+export interface PanicOptions {
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+  signal?: AbortSignal;
+}
+
+export async function analyzePanics(projectRoot: string, options: PanicOptions = {}): Promise<PanicReport> {
+  const project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  const { packages, panicking, recovering } = panicExposure(graph);
+  return {
+    projectRoot,
+    packages,
+    summary: {
+      exported: packages.reduce((n, p) => n + p.exported, 0),
+      exposed: packages.reduce((n, p) => n + p.exposed.length, 0),
+      panicking,
+      recovering,
+    },
+    warnings,
+  };
+}
+
+interface PanicSite {
+  call: string;
+  file: string;
+  line: number;
+}
+
+export function panicExposure(graph: GoCallGraph): { packages: PanicPackage[]; panicking: number; recovering: number } {
+  const recovers = new Set<string>();
+  const deferred = new Map<string, Node[]>();
+  for (const fn of graph.functions.values()) {
+    const literals = deferredLiterals(fn.node);
+    deferred.set(fn.id, literals);
+    if (literals.some(callsRecover)) recovers.add(fn.id);
+  }
+  // defer handlePanic(): handlePanic calls recover() itself
+  for (const call of graph.calls) {
+    if (call.external || call.node.parent?.type !== 'defer_statement') continue;
+    const callee = graph.functions.get(call.callee);
+    if (callee && callsRecover(callee.node)) recovers.add(call.caller);
+  }
+
+  // Panics that leave the function they are in
+  const sites = new Map<string, PanicSite>();
+  for (const fn of graph.functions.values()) {
+    const file = functionFile(graph, fn).file;
+    const inDefer = (node: Node) => deferred.get(fn.id)!.some(l => l.startIndex <= node.startIndex && node.endIndex <= l.endIndex);
+    const site = panicSites(fn, graph, file).find(s => !inGoStatement(s.node) && (!recovers.has(fn.id) || inDefer(s.node)));
+    if (site) sites.set(fn.id, { call: site.call, file, line: site.node.startPosition.row + 1 });
+  }
+
+  const callers = new Map<string, GoCall[]>();
+  for (const call of graph.calls) {
+    if (call.external || call.reference || inGoStatement(call.node)) continue;
+    if (!callers.has(call.callee)) callers.set(call.callee, []);
+    callers.get(call.callee)!.push(call);
+  }
+
+  // Breadth-first from every panicking function up through callers without a recover;
+  // each function keeps the call it was first reached through
+  const next = new Map<string, GoCall | null>();
+  const origin = new Map<string, string>();
+  const queue: string[] = [];
+  for (const id of sites.keys()) {
+    next.set(id, null);
+    origin.set(id, id);
+    queue.push(id);
+  }
+  while (queue.length > 0) {
+    const id = queue.shift()!;
+    for (const call of callers.get(id) ?? []) {
+      if (next.has(call.caller) || recovers.has(call.caller)) continue;
+      next.set(call.caller, call);
+      origin.set(call.caller, origin.get(id)!);
+      queue.push(call.caller);
+    }
+  }
+
+  const packages = new Map<string, PanicPackage>();
+  for (const fn of graph.functions.values()) {
+    if (!isApi(fn, graph)) continue;
+    let pkg = packages.get(fn.package);
+    if (!pkg) {
+      pkg = { package: fn.package, dir: fn.dir, exported: 0, exposed: [] };
+      packages.set(fn.package, pkg);
+    }
+    pkg.exported++;
+    if (!next.has(fn.id)) continue;
+
+    const chain: PanicStep[] = [];
+    let approximate = false;
+    for (let call = next.get(fn.id) ?? null; call; call = next.get(call.callee) ?? null) {
+      const caller = graph.functions.get(call.caller)!;
+      chain.push({ function: caller.id, package: caller.package, file: call.file, line: call.line });
+      approximate ||= call.approximate;
+    }
+    const panicker = graph.functions.get(origin.get(fn.id)!)!;
+    const site = sites.get(panicker.id)!;
+    chain.push({ function: panicker.id, package: panicker.package, file: site.file, line: site.line });
+    pkg.exposed.push({ function: fn.id, package: fn.package, file: fn.file, line: fn.line, call: site.call, chain, approximate });
+  }
+
+  for (const pkg of packages.values()) pkg.exposed.sort((a, b) => a.function.localeCompare(b.function));
+  return {
+    packages: [...packages.values()].filter(p => p.exported > 0).sort((a, b) => b.exposed.length - a.exposed.length || a.package.localeCompare(b.package)),
+    panicking: sites.size,
+    recovering: recovers.size,
+  };
+}
+
+// Exported functions, and exported methods of exported types, outside package main
+function isApi(fn: GoFunction, graph: GoCallGraph): boolean {
+  if (!fn.exported || graph.project.packages.get(fn.dir)!.name === 'main') return false;
+  return !fn.receiver || /^\p{Lu}/u.test(fn.receiver);
+}
+
+// panic(...) where panic is the builtin, and log.Panic*
+function panicSites(fn: GoFunction, graph: GoCallGraph, file: string): Array<{ call: string; node: Node }> {
+  const found: Array<{ call: string; node: Node }> = [];
+  walk(fn.node, (node) => {
+    if (node.type !== 'call_expression') return;
+    const callee = node.childForFieldName('function');
+    if (callee?.type === 'identifier' && callee.text === 'panic') {
+      found.push({ call: 'panic', node });
+      return;
+    }
+    const external = graph.calleesOf(file, node).find(c => c.external && PANIC_CALLS.has(c.callee));
+    if (external) found.push({ call: external.callee, node });
+  });
+  return found;
+}
+
+// The func literals of defer func() { ... }() statements
+function deferredLiterals(fn: Node): Node[] {
+  const literals: Node[] = [];
+  walk(fn, (node) => {
+    if (node.type !== 'defer_statement') return;
+    const callee = node.namedChildren[0]?.childForFieldName('function');
+    if (callee?.type === 'func_literal') literals.push(callee);
+  });
+  return literals;
+}
+
+function callsRecover(node: Node): boolean {
+  let found = false;
+  walk(node, (child) => {
+    if (found) return false;
+    if (child.type === 'call_expression' && child.childForFieldName('function')?.text === 'recover') found = true;
+  });
+  return found;
+}
+
+function inGoStatement(call: Node): boolean {
+  for (let current = call.parent; current; current = current.parent) {
+    if (current.type === 'go_statement') return true;
+    if (current.type === 'function_declaration' || current.type === 'method_declaration') return false;
+  }
+  return false;
+}
@@ -0,0 +1,273 @@
+import { readFileSync } from 'fs';
+import { gunzipSync } from 'zlib';
+import { isAbsolute, posix, relative, sep } from 'path';
+import { symbolPackage } from './binary.js';
+import { findGoModules, GoModuleIndex } from './modules.js';
+
+/**
+ * CPU profiles (runtime/pprof, go test -cpuprofile, /debug/pprof/profile)
+ * mapped onto the project. A profile is a gzipped protobuf of stack
+ * samples; each frame names its function and source file, by absolute path
+ * on the machine that built the binary, or by import path with -trimpath.
+ *
+ * Time is attributed to the innermost project frame of each sample, time
+ * spent in the standard library or a dependency included, so a package is
+ * hot when its own code is where the program spends its time; cum counts
+ * every sample a node is on the stack of. Consecutive project frames in
+ * different nodes are the calls hot paths run through.
+ */
+
+export interface ProfileFrame {
+  function: string;
+  file: string;
+  line: number;
+}
+
+export interface ProfileSample {
+  /** Of the CPU sample type, in its unit */
+  value: number;
+  /** Innermost frame first, inlined calls expanded */
+  stack: ProfileFrame[];
+}
+
+export interface CpuProfile {
+  /** Unit of the sample values, nanoseconds for runtime/pprof */
+  unit: string;
+  durationNanos: number;
+  samples: ProfileSample[];
+}
+
+export interface HotSpot {
+  /** Samples whose innermost project frame is in the node */
+  flat: number;
+  /** Samples with the node anywhere on the stack */
+  cum: number;
+}
+
+export interface HotPaths {
+  unit: string;
+  /** Every sample's value, the 100% the others are shares of */
+  total: number;
+  /** Project file or package directory → its time */
+  nodes: Map<string, HotSpot>;
+  /** caller\0callee → time of the samples that made the call */
+  calls: Map<string, number>;
+  /** Time of samples with no project frame: the runtime, GC, another module */
+  outside: number;
+}
+
+// A protobuf message as [field number, value] pairs: numbers for varints, bytes for length-delimited fields
+function decodeMessage(buf: Uint8Array): Array<[number, number | Uint8Array]> {
+  const fields: Array<[number, number | Uint8Array]> = [];
+  let pos = 0;
+  const varint = () => {
+    let value = 0;
+    let scale = 1;
+    for (;;) {
+      if (pos >= buf.length) throw new Error('truncated varint');
+      const byte = buf[pos++];
+      value += (byte & 0x7f) * scale;
+      if (byte < 0x80) return value;
+      scale *= 128;
+    }
+  };
+  while (pos < buf.length) {
+    const key = varint();
+    const field = Math.floor(key / 8);
+    switch (key & 7) {
+      case 0: fields.push([field, varint()]); break;
+      case 1: pos += 8; break;
+      case 2: {
+        const length = varint();
+        if (pos + length > buf.length) throw new Error('truncated field');
+        fields.push([field, buf.subarray(pos, pos + length)]);
+        pos += length;
+        break;
+      }
+      case 5: pos += 4; break;
+      default: throw new Error(`unsupported wire type ${key & 7}`);
+    }
+  }
+  return fields;
+}
+
+// A repeated integer field, packed or not
+function integers(value: number | Uint8Array): number[] {
+  return typeof value === 'number' ? [value] : packed(value);
+}
+
+function packed(buf: Uint8Array): number[] {
+  const values: number[] = [];
+  let value = 0;
+  let scale = 1;
+  for (const byte of buf) {
+    value += (byte & 0x7f) * scale;
+    scale *= 128;
+    if (byte < 0x80) {
+      values.push(value);
+      value = 0;
+      scale = 1;
+    }
+  }
+  return values;
+}
+
+/** Decode a CPU profile, gzipped or not; name is for errors */
+export function parseCpuProfile(data: Uint8Array, name: string): CpuProfile {
+  let fields: Array<[number, number | Uint8Array]>;
+  try {
+    const raw = data[0] === 0x1f && data[1] === 0x8b ? gunzipSync(data) : data;
+    fields = decodeMessage(raw);
+  } catch (err) {
+    throw new Error(`${name} is not a pprof profile: ${err instanceof Error ? err.message : err}`);
+  }
+
+  const strings: string[] = [];
+  const types: Array<{ type: number; unit: number }> = [];
+  const rawSamples: Array<{ locations: number[]; values: number[] }> = [];
+  const locations = new Map<number, Array<{ fn: number; line: number }>>();
+  const functions = new Map<number, { name: number; file: number }>();
+  let durationNanos = 0;
+
+  for (const [field, value] of fields) {
+    if (field === 6 && typeof value !== 'number') {
+      strings.push(Buffer.from(value).toString('utf-8'));
+    } else if (field === 10 && typeof value === 'number') {
+      durationNanos = value;
+    } else if (typeof value === 'number') {
+      continue;
+    } else if (field === 1) {
+      const type = { type: 0, unit: 0 };
+      for (const [f, v] of decodeMessage(value)) {
+        if (f === 1 && typeof v === 'number') type.type = v;
+        if (f === 2 && typeof v === 'number') type.unit = v;
+      }
+      types.push(type);
+    } else if (field === 2) {
+      const sample = { locations: [] as number[], values: [] as number[] };
+      for (const [f, v] of decodeMessage(value)) {
+        if (f === 1) sample.locations.push(...integers(v));
+        if (f === 2) sample.values.push(...integers(v));
+      }
+      rawSamples.push(sample);
+    } else if (field === 4) {
+      let id = 0;
+      const lines: Array<{ fn: number; line: number }> = [];
+      for (const [f, v] of decodeMessage(value)) {
+        if (f === 1 && typeof v === 'number') id = v;
+        if (f === 4 && typeof v !== 'number') {
+          const line = { fn: 0, line: 0 };
+          for (const [lf, lv] of decodeMessage(v)) {
+            if (lf === 1 && typeof lv === 'number') line.fn = lv;
+            if (lf === 2 && typeof lv === 'number') line.line = lv;
+          }
+          lines.push(line);
+        }
+      }
+      locations.set(id, lines);
+    } else if (field === 5) {
+      let id = 0;
+      const fn = { name: 0, file: 0 };
+      for (const [f, v] of decodeMessage(value)) {
+        if (f === 1 && typeof v === 'number') id = v;
+        if (f === 2 && typeof v === 'number') fn.name = v;
+        if (f === 4 && typeof v === 'number') fn.file = v;
+      }
+      functions.set(id, fn);
+    }
+  }
+
+  const typeNames = types.map(t => `${strings[t.type]}/${strings[t.unit]}`);
+  const cpu = types.findIndex(t => strings[t.type] === 'cpu');
+  if (cpu === -1) {
+    throw new Error(`${name} is not a CPU profile (sample types: ${typeNames.join(', ') || 'none'})`);
+  }
+
+  const frames = new Map<number, ProfileFrame[]>();
+  const framesOf = (location: number) => {
+    let stack = frames.get(location);
+    if (!stack) {
+      // Inlined functions come before the function they were inlined into
+      stack = (locations.get(location) ?? []).map(({ fn, line }) => {
+        const f = functions.get(fn);
+        return { function: f ? strings[f.name] ?? '' : '', file: f ? strings[f.file] ?? '' : '', line };
+      });
+      frames.set(location, stack);
+    }
+    return stack;
+  };
+
+  return {
+    unit: strings[types[cpu].unit] ?? '',
+    durationNanos,
+    samples: rawSamples.map(s => ({ value: s.values[cpu] ?? 0, stack: s.locations.flatMap(framesOf) })),
+  };
+}
+
+export function readCpuProfiles(files: string[]): CpuProfile[] {
+  return files.map(file => parseCpuProfile(readFileSync(file), file));
+}
+
+/**
+ * Time per project file or package and per call between them, over one or
+ * more profiles of the same program.
+ */
+export function hotPaths(projectRoot: string, profiles: CpuProfile[], level: 'file' | 'package'): HotPaths {
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const files = new Map<string, string | null>();
+  const projectFile = (frame: ProfileFrame): string | null => {
+    const key = `${frame.function}\0${frame.file}`;
+    if (!files.has(key)) files.set(key, locate(frame, projectRoot, index));
+    return files.get(key)!;
+  };
+
+  const nodes = new Map<string, HotSpot>();
+  const calls = new Map<string, number>();
+  let total = 0;
+  let outside = 0;
+  for (const profile of profiles) {
+    for (const sample of profile.samples) {
+      total += sample.value;
+      const path: string[] = [];
+      for (const frame of sample.stack) {
+        const file = projectFile(frame);
+        if (file === null) continue;
+        const node = level === 'file' ? file : posix.dirname(file);
+        // Recursion and calls within the node run through it once
+        if (path[path.length - 1] !== node) path.push(node);
+      }
+      if (path.length === 0) {
+        outside += sample.value;
+        continue;
+      }
+      const spot = (node: string) => {
+        let entry = nodes.get(node);
+        if (!entry) nodes.set(node, entry = { flat: 0, cum: 0 });
+        return entry;
+      };
+      spot(path[0]).flat += sample.value;
+      for (const node of new Set(path)) spot(node).cum += sample.value;
+      const made = new Set<string>();
+      for (let i = path.length - 1; i > 0; i--) made.add(`${path[i]}\0${path[i - 1]}`);
+      for (const call of made) calls.set(call, (calls.get(call) ?? 0) + sample.value);
+    }
+  }
+  return { unit: profiles[0]?.unit ?? '', total, nodes, calls, outside };
+}
+
+// Project-relative file of a frame: by its path under the project, its trimmed import path, or its function's package
+function locate(frame: ProfileFrame, projectRoot: string, index: GoModuleIndex): string | null {
+  if (!frame.file.endsWith('.go')) return null;
+  if (isAbsolute(frame.file)) {
+    const rel = relative(projectRoot, frame.file);
+    if (!rel.startsWith('..') && !isAbsolute(rel)) return rel.split(sep).join('/');
+  } else {
+    const dir = index.dirForImport(posix.dirname(frame.file));
+    if (dir !== null) return dir === '.' ? posix.basename(frame.file) : `${dir}/${posix.basename(frame.file)}`;
+  }
+  // Built elsewhere: main packages are only found above, by path
+  const pkg = symbolPackage(frame.function);
+  const dir = pkg && pkg !== 'main' ? index.dirForImport(pkg) : null;
+  if (dir === null) return null;
+  return dir === '.' ? posix.basename(frame.file) : `${dir}/${posix.basename(frame.file)}`;
+}
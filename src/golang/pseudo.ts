@@ -0,0 +1,45 @@
+/**
+ * Go pseudo-versions (https://go.dev/ref/mod#pseudo-versions): versions the
+ * go command synthesizes for an untagged commit, in one of three forms —
+ *
+ *   vX.0.0-yyyymmddhhmmss-abcdefabcdef            no earlier tag
+ *   vX.Y.Z-pre.0.yyyymmddhhmmss-abcdefabcdef      after a prerelease tag
+ *   vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdefabcdef      after a release tag
+ *
+ * The timestamp is the commit time in UTC; the revision is the first 12
+ * hex digits of the commit hash.
+ */
+
+export interface PseudoVersion {
+  version: string;
+  /** The tag the pseudo-version sorts after ("v1.2.3"), or null with no earlier tag */
+  base: string | null;
+  /** Commit time, ISO 8601 UTC */
+  time: string;
+  /** 12-character commit hash prefix */
+  revision: string;
+}
+
+const PSEUDO = /^(v\d+\.\d+\.\d+)-(?:(\d{14})|0\.(\d{14})|(.+)\.0\.(\d{14}))-([0-9a-f]{12})(\+incompatible)?$/;
+
+export function isPseudoVersion(version: string): boolean {
+  return PSEUDO.test(version);
+}
+
+export function parsePseudoVersion(version: string): PseudoVersion | null {
+  const match = PSEUDO.exec(version);
+  if (!match) return null;
+  const [, core, untagged, afterRelease, pre, afterPrerelease, revision] = match;
+  const t = untagged ?? afterRelease ?? afterPrerelease;
+  const time = `${t.slice(0, 4)}-${t.slice(4, 6)}-${t.slice(6, 8)}T${t.slice(8, 10)}:${t.slice(10, 12)}:${t.slice(12, 14)}Z`;
+
+  let base: string | null = null;
+  if (pre !== undefined) {
+    base = `${core}-${pre}`;
+  } else if (afterRelease !== undefined) {
+    // vX.Y.(Z+1)-0.<time> sorts after the release vX.Y.Z
+    const [major, minor, patch] = core.slice(1).split('.').map(Number);
+    base = `v${major}.${minor}.${patch - 1}`;
+  }
+  return { version, base, time, revision };
+}
@@ -0,0 +1,147 @@
+import { createHash } from 'crypto';
+import { existsSync, mkdirSync, readFileSync, writeFileSync } from 'fs';
+import { dirname, join } from 'path';
+import { runGo } from './toolchain.js';
+import type { GoProject } from './packages.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('golang');
+
+/**
+ * Call edges from SSA and inclusion-based pointer analysis, for the
+ * --algo pta call graph. The go command builds the program in SSA form and
+ * solves points-to sets from every main package, so calls through
+ * interfaces and function values go exactly where values can flow — at the
+ * cost of type-checking the whole program, dependencies and standard
+ * library included. Pointer analysis was removed from x/tools after v0.12,
+ * so that is the version run.
+ *
+ * Edges are cached in .depwire/callgraph-pta.json, keyed by the Go
+ * version, go.mod/go.sum and every Go file of the workspace.
+ */
+
+export const POINTER_ANALYSIS_TOOL = 'golang.org/x/tools/cmd/callgraph@v0.12.0';
+
+export interface PointerEdge {
+  /** Calling function in depwire's ids ("<import path>.Name", "<import path>.Type.Name") */
+  caller: string;
+  callee: string;
+  /** Call site, 1-based; the column is go/token's (the opening parenthesis) */
+  line: number;
+  column: number;
+}
+
+export interface PointerAnalysis {
+  edges: PointerEdge[];
+  /** Modules analyzed; modules without a main package have no roots and are left out */
+  modules: string[];
+  cached: boolean;
+}
+
+interface PointerCache {
+  key: string;
+  edges: PointerEdge[];
+  modules: string[];
+}
+
+const FORMAT = ['{{.Caller}}', '{{.Callee}}', '{{.Line}}', '{{.Column}}'].join('\t');
+
+export async function pointerCallEdges(project: GoProject, options: { signal?: AbortSignal } = {}): Promise<PointerAnalysis> {
+  const { projectRoot } = project;
+  let goVersion = '';
+  const version = await runGo(['env', 'GOVERSION'], { cwd: projectRoot, signal: options.signal });
+  if (version.exitCode === 0) goVersion = version.stdout.trim();
+
+  const key = cacheKey(project, goVersion);
+  const cacheFile = join(projectRoot, '.depwire', 'callgraph-pta.json');
+  const cache = readCache(cacheFile);
+  if (cache?.key === key) return { edges: cache.edges, modules: cache.modules, cached: true };
+
+  const edges: PointerEdge[] = [];
+  const modules: string[] = [];
+  for (const module of project.modules.modules) {
+    log.info(`Running pointer analysis: ${module.path}`, { module: module.path });
+    const result = await runGo(['run', POINTER_ANALYSIS_TOOL, '-algo=pta', `-format=${FORMAT}`, './...'], {
+      cwd: join(projectRoot, module.dir),
+      signal: options.signal,
+    });
+    if (result.exitCode !== 0) {
+      // Pointer analysis starts from main.main; a library module has nothing to start from
+      if (/no main packages/.test(result.stderr)) continue;
+      throw new Error(`Pointer analysis of ${module.path} failed: ${result.stderr.trim() || `exit code ${result.exitCode}`}`);
+    }
+    modules.push(module.path);
+    edges.push(...parseCallgraphOutput(result.stdout));
+  }
+
+  const entry: PointerCache = { key, edges, modules };
+  try {
+    mkdirSync(dirname(cacheFile), { recursive: true });
+    writeFileSync(cacheFile, JSON.stringify(entry));
+  } catch { /* read-only checkout — the cache is an optimization */ }
+  return { edges, modules, cached: false };
+}
+
+/** Parse callgraph -format output, dropping edges without a call site or between synthetic functions */
+export function parseCallgraphOutput(output: string): PointerEdge[] {
+  const edges: PointerEdge[] = [];
+  const seen = new Set<string>();
+  for (const line of output.split('\n')) {
+    const [callerName, calleeName, row, col] = line.split('\t');
+    if (!calleeName || !row || row === '0') continue;
+    const caller = ssaFunctionId(callerName);
+    const callee = ssaFunctionId(calleeName);
+    if (!caller || !callee) continue;
+    const edge = { caller, callee, line: parseInt(row, 10), column: parseInt(col, 10) };
+    // Closures fold into their enclosing function, so their calls can repeat
+    const id = `${edge.caller}\0${edge.callee}\0${edge.line}:${edge.column}`;
+    if (seen.has(id)) continue;
+    seen.add(id);
+    edges.push(edge);
+  }
+  return edges;
+}
+
+/**
+ * An SSA function name in depwire's ids: "(*pkg.T).M" is "pkg.T.M", type
+ * arguments are dropped, and closures ("pkg.F$1"), bound methods and thunks
+ * ("$bound", "$thunk") stand for the function they come from. Null for
+ * synthetic functions: the root and package initializers.
+ */
+export function ssaFunctionId(name: string): string | null {
+  let plain = '';
+  let depth = 0;
+  for (const ch of name) {
+    if (ch === '[') depth++;
+    else if (ch === ']') depth--;
+    else if (depth === 0) plain += ch;
+  }
+  plain = plain.replace(/\$.*$/, '');
+  const method = /^\(\*?(.+)\)\.([^.]+)$/.exec(plain);
+  if (method) return `${method[1]}.${method[2]}`;
+  // <root>, and pkg.init: the synthetic initializer that runs var declarations and init#N
+  if (!/^[^()<>\s]+\.[^./()\s]+$/.test(plain) || plain.endsWith('.init')) return null;
+  return plain;
+}
+
+function cacheKey(project: GoProject, goVersion: string): string {
+  const hash = createHash('sha256');
+  hash.update(`${POINTER_ANALYSIS_TOOL}\0${goVersion}\0`);
+  for (const module of project.modules.modules) {
+    for (const name of ['go.mod', 'go.sum']) {
+      const file = join(project.projectRoot, module.dir, name);
+      hash.update(`${module.dir}/${name}\0${existsSync(file) ? readFileSync(file, 'utf-8') : ''}\0`);
+    }
+  }
+  const files = [...project.packages.values()].flatMap(p => p.files).sort((a, b) => a.file.localeCompare(b.file));
+  for (const file of files) hash.update(`${file.file}\0${file.source}\0`);
+  return hash.digest('hex');
+}
+
+function readCache(file: string): PointerCache | null {
+  try {
+    return existsSync(file) ? JSON.parse(readFileSync(file, 'utf-8')) as PointerCache : null;
+  } catch {
+    return null;
+  }
+}
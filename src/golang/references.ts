@@ -0,0 +1,123 @@
+import type { Node } from 'web-tree-sitter';
+import { walk, goImports, type GoSourceFile } from './source.js';
+import type { GoPackage, GoProject } from './packages.js';
+
+/**
+ * Syntactic cross-package references: pkg.Name selectors and qualified
+ * types. The Go parser doesn't resolve identifiers across packages, so this
+ * is how package-to-package symbol usage is measured.
+ */
+
+export interface GoReference {
+  name: string;
+  file: string;
+  line: number;
+}
+
+/** Names an importing package uses to qualify identifiers from importPath */
+export function importQualifiers(importer: GoPackage, importPath: string, packageName: string, tests = false): Set<string> {
+  const names = new Set<string>();
+  const uses = [...(importer.imports.get(importPath) ?? []), ...(tests ? importer.testImports.get(importPath) ?? [] : [])];
+  for (const use of uses) {
+    if (use.kind === 'alias' && use.alias) {
+      names.add(use.alias);
+    } else if (use.kind === 'normal') {
+      names.add(importPath.split('/').pop()!);
+      // The package clause name is what files use when it differs from the last path segment
+      if (packageName) names.add(packageName);
+    }
+  }
+  return names;
+}
+
+/** Identifiers used through one of the qualifiers (pkg.Func, pkg.Type{}, *pkg.Type) */
+export function qualifiedReferences(file: GoSourceFile, qualifiers: Set<string>): GoReference[] {
+  const refs: GoReference[] = [];
+  if (qualifiers.size === 0) return refs;
+  walk(file.root, (node: Node) => {
+    if (node.type === 'qualified_type') {
+      const pkg = node.childForFieldName('package')?.text;
+      const name = node.childForFieldName('name')?.text;
+      if (pkg && name && qualifiers.has(pkg)) refs.push({ name, file: file.file, line: node.startPosition.row + 1 });
+      return false;
+    }
+    if (node.type === 'selector_expression') {
+      const operand = node.childForFieldName('operand');
+      const field = node.childForFieldName('field')?.text;
+      if (operand?.type === 'identifier' && qualifiers.has(operand.text) && field) {
+        refs.push({ name: field, file: file.file, line: node.startPosition.row + 1 });
+        return false;
+      }
+    }
+  });
+  return refs;
+}
+
+/**
+ * Method calls x.M() whose operand is not a package qualifier, by method name.
+ * Without type information these can only be matched to a type by name.
+ */
+export function methodCalls(file: GoSourceFile, qualifiers: Set<string>): GoReference[] {
+  const calls: GoReference[] = [];
+  walk(file.root, (node: Node) => {
+    if (node.type !== 'call_expression') return;
+    const fn = node.childForFieldName('function');
+    if (fn?.type !== 'selector_expression') return;
+    const operand = fn.childForFieldName('operand');
+    const method = fn.childForFieldName('field')?.text;
+    if (method && operand && !(operand.type === 'identifier' && qualifiers.has(operand.text))) {
+      calls.push({ name: method, file: file.file, line: node.startPosition.row + 1 });
+    }
+  });
+  return calls;
+}
+
+/** Every x.Name selector (field access or method call) not qualified by a package */
+export function memberSelectors(file: GoSourceFile, qualifiers: Set<string>): GoReference[] {
+  const uses: GoReference[] = [];
+  walk(file.root, (node: Node) => {
+    if (node.type !== 'selector_expression') return;
+    const operand = node.childForFieldName('operand');
+    const field = node.childForFieldName('field')?.text;
+    if (field && operand && !(operand.type === 'identifier' && qualifiers.has(operand.text))) {
+      uses.push({ name: field, file: file.file, line: node.startPosition.row + 1 });
+    }
+  });
+  return uses;
+}
+
+/**
+ * Every name a file can qualify identifiers with, mapped to the import path.
+ * Local packages use their package clause name; external ones the usual
+ * convention (last path element without a /vN suffix, go- prefix or .vN).
+ */
+export function fileQualifiers(file: GoSourceFile, project?: GoProject): Map<string, string> {
+  const names = new Map<string, string>();
+  for (const imp of goImports(file)) {
+    if (imp.kind === 'blank' || imp.kind === 'dot') continue;
+    if (imp.alias) {
+      names.set(imp.alias, imp.path);
+      continue;
+    }
+    const dir = project?.modules.dirForImport(imp.path);
+    const localName = dir ? project?.packages.get(dir)?.name : undefined;
+    names.set(localName || guessPackageName(imp.path), imp.path);
+  }
+  return names;
+}
+
+/** "<import path>.Name" of a pkg.Name selector, given the file's qualifiers, or null */
+export function qualifiedName(node: Node | null, qualifiers: Map<string, string>): string | null {
+  if (node?.type !== 'selector_expression') return null;
+  const operand = node.childForFieldName('operand');
+  const field = node.childForFieldName('field')?.text;
+  if (operand?.type !== 'identifier' || !field || !qualifiers.has(operand.text)) return null;
+  return `${qualifiers.get(operand.text)}.${field}`;
+}
+
+export function guessPackageName(importPath: string): string {
+  const parts = importPath.split('/');
+  let last = parts.pop()!;
+  if (/^v\d+$/.test(last) && parts.length > 0) last = parts.pop()!;
+  return last.replace(/\.v\d+$/, '').replace(/^go-/, '').replace(/[.-]go$/, '').replace(/[^A-Za-z0-9_]/g, '');
+}
@@ -0,0 +1,558 @@
+import type { Node } from 'web-tree-sitter';
+import { walk, type GoSourceFile } from './source.js';
+import { loadGoProject } from './packages.js';
+import { fileQualifiers } from './references.js';
+import { loadGoCallGraph, functionFile, qualifiedTypeName, reachableFunctions, type GoCallGraph, type GoFunction } from './callgraph.js';
+import { calleePackage } from './goroutines.js';
+
+/**
+ * HTTP routes of a Go service and the code behind each: route registrations
+ * on net/http muxes, gorilla/mux, chi, gin and echo routers, the handler
+ * function each registers, its package, and every workspace and external
+ * package reachable from the handler in the call graph — "what code serves
+ * /api/users".
+ *
+ * Prefixes are followed through route groups (gin and echo Group, chi Route
+ * and Group, gorilla PathPrefix().Subrouter(), net/http StripPrefix), into
+ * functions a router is passed to, and into router constructors mounted
+ * with chi Mount. Paths must be string literals; registrations with computed
+ * paths are counted in the warnings.
+ */
+
+export type RouterFramework = 'net/http' | 'gorilla/mux' | 'chi' | 'gin' | 'echo';
+
+const FRAMEWORKS: Array<[RegExp, RouterFramework]> = [
+  [/^net\/http$/, 'net/http'],
+  [/^github\.com\/gorilla\/mux$/, 'gorilla/mux'],
+  [/^github\.com\/go-chi\/chi(\/v\d+)?$/, 'chi'],
+  [/^github\.com\/gin-gonic\/gin$/, 'gin'],
+  [/^github\.com\/labstack\/echo(\/v\d+)?$/, 'echo'],
+];
+
+const METHODS = ['GET', 'POST', 'PUT', 'PATCH', 'DELETE', 'HEAD', 'OPTIONS', 'CONNECT', 'TRACE'];
+// chi names its registrations Get, Post, ...
+const CHI_METHODS = new Map(METHODS.map(m => [m[0] + m.slice(1).toLowerCase(), m]));
+// gorilla/mux route matchers that return the route, for walking a chain down to the router
+const GORILLA_MATCHERS = new Set(['Methods', 'Schemes', 'Host', 'Headers', 'Queries', 'Name', 'MatcherFunc']);
+// Router methods that register a route or a group
+const REGISTRATIONS = new Set([
+  ...METHODS, ...CHI_METHODS.keys(), 'Any', 'Add', 'Handle', 'HandleFunc', 'Method', 'MethodFunc', 'Mount', 'Route', 'Group', 'Path', 'PathPrefix',
+]);
+/** Cap on the prefixes one router variable can carry, for routers passed around recursively */
+const MAX_PREFIXES = 8;
+
+export interface HttpRoute {
+  /** "GET", or "*" for any method */
+  method: string;
+  path: string;
+  framework: RouterFramework;
+  file: string;
+  line: number;
+  /** Function id of the handler; for an inline func literal, the function registering it */
+  handler: string | null;
+  /** The handler argument as written */
+  handlerExpr: string;
+  inline: boolean;
+  /** The handler was matched by method name only */
+  approximate: boolean;
+  /** Package of the handler */
+  package: string | null;
+  /** Workspace packages reachable from the handler, its own first */
+  packages: string[];
+  /** Standard-library and third-party packages the reachable code calls into */
+  external: string[];
+  /** Workspace functions reachable from the handler */
+  functions: number;
+}
+
+export interface RouteReport {
+  projectRoot: string;
+  routes: HttpRoute[];
+  summary: { routes: number; handlers: number; frameworks: Partial<Record<RouterFramework, number>> };
+  warnings: string[];
+}
+
+export interface RouteOptions {
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+  /** Only routes serving this request path (or written as this pattern) */
+  path?: string;
+  signal?: AbortSignal;
+}
+
+export async function analyzeRoutes(projectRoot: string, options: RouteOptions = {}): Promise<RouteReport> {
+  const project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  const { routes, skipped } = httpRoutes(graph);
+  if (skipped > 0) warnings.push(`${skipped} route registrations with a path that is not a string literal were skipped`);
+  const matching = options.path ? routes.filter(r => routeMatches(r.path, options.path!, r.framework === 'net/http')) : routes;
+  const frameworks: Partial<Record<RouterFramework, number>> = {};
+  for (const route of matching) frameworks[route.framework] = (frameworks[route.framework] ?? 0) + 1;
+  return {
+    projectRoot,
+    routes: matching,
+    summary: { routes: matching.length, handlers: new Set(matching.map(r => r.handler ?? `${r.file}:${r.line}`)).size, frameworks },
+    warnings,
+  };
+}
+
+/**
+ * Whether a route pattern serves a request path: {id}, {id:[0-9]+} and :id
+ * match one segment, {rest...}, *rest and a trailing * match the rest, and
+ * with subtree (net/http) a pattern ending in / matches everything below
+ * it. A path equal to the pattern as written matches too.
+ */
+export function routeMatches(pattern: string, path: string, subtree = false): boolean {
+  if (pattern === path) return true;
+  const source = pattern.split('/').map((segment, i, all) => {
+    if (/^\{[^}]*\.\.\.\}$/.test(segment) || /^\*/.test(segment)) return '.*';
+    if (subtree && i === all.length - 1 && segment === '' && i > 0) return '.*';
+    return segment
+      .split(/(\{[^}]*\}|:[A-Za-z_]\w*)/)
+      .map(part => part === '{$}' ? '' : /^\{.*\}$|^:/.test(part) ? '[^/]+' : part.replace(/[.*+?^${}()|[\]\\]/g, '\\$&'))
+      .join('');
+  }).join('/');
+  return new RegExp(`^${source.replace(/\/\.\*$/, '(/.*)?')}$`).test(path);
+}
+
+function joinPath(prefix: string, path: string): string {
+  if (!prefix) return path;
+  if (!path) return prefix;
+  return `${prefix.replace(/\/$/, '')}${path.startsWith('/') ? path : `/${path}`}`;
+}
+
+function stringLiteral(node: Node | null | undefined): string | null {
+  return node?.type === 'interpreted_string_literal' || node?.type === 'raw_string_literal' ? node.text.slice(1, -1) : null;
+}
+
+function argumentsOf(call: Node): Node[] {
+  return (call.childForFieldName('arguments')?.namedChildren ?? []).filter((n): n is Node => n !== null && n.type !== 'comment');
+}
+
+interface Registration {
+  framework: RouterFramework;
+  methods: string[];
+  /** Pattern as registered, before the router's prefix */
+  path: string;
+  /** The router the route is registered on */
+  router: Node | null;
+  handler: Node;
+}
+
+interface ScanResult {
+  routes: HttpRoute[];
+  /** Path arguments that weren't literals */
+  skipped: number;
+  /** Router prefixes passed to other functions: callee id → parameter → prefixes */
+  passed: Array<[string, string, string[]]>;
+  /** Prefixes of functions returning a router that gets mounted */
+  mounted: Array<[string, string[]]>;
+}
+
+/** Routes of every function, with prefixes propagated until no function learns a new one */
+export function httpRoutes(graph: GoCallGraph): { routes: HttpRoute[]; skipped: number } {
+  const paramPrefixes = new Map<string, Map<string, Set<string>>>();
+  const mountPrefixes = new Map<string, Set<string>>();
+  const routes = new Map<string, HttpRoute[]>();
+  const skipped = new Map<string, number>();
+  const queue = [...graph.functions.keys()];
+  const queued = new Set(queue);
+
+  const learn = (target: Set<string>, prefixes: string[]): boolean => {
+    let grew = false;
+    for (const prefix of prefixes) {
+      if (target.size >= MAX_PREFIXES || target.has(prefix)) continue;
+      target.add(prefix);
+      grew = true;
+    }
+    return grew;
+  };
+  const requeue = (id: string) => {
+    if (!queued.has(id)) {
+      queued.add(id);
+      queue.push(id);
+    }
+  };
+
+  while (queue.length > 0) {
+    const id = queue.shift()!;
+    queued.delete(id);
+    const fn = graph.functions.get(id)!;
+    const result = scanFunction(graph, fn, paramPrefixes.get(id), mountPrefixes.get(id));
+    if (!result) continue;
+    routes.set(id, result.routes);
+    skipped.set(id, result.skipped);
+    for (const [callee, param, prefixes] of result.passed) {
+      if (!paramPrefixes.has(callee)) paramPrefixes.set(callee, new Map());
+      const params = paramPrefixes.get(callee)!;
+      if (!params.has(param)) params.set(param, new Set());
+      if (learn(params.get(param)!, prefixes)) requeue(callee);
+    }
+    for (const [callee, prefixes] of result.mounted) {
+      if (!mountPrefixes.has(callee)) mountPrefixes.set(callee, new Set());
+      if (learn(mountPrefixes.get(callee)!, prefixes)) requeue(callee);
+    }
+  }
+
+  const all = [...routes.values()].flat();
+  const unique = new Map(all.map(r => [`${r.method} ${r.path} ${r.file}:${r.line}`, r]));
+  return {
+    routes: [...unique.values()].sort((a, b) => a.path.localeCompare(b.path) || a.method.localeCompare(b.method) || a.file.localeCompare(b.file) || a.line - b.line),
+    skipped: [...skipped.values()].reduce((n, k) => n + k, 0),
+  };
+}
+
+function scanFunction(
+  graph: GoCallGraph,
+  fn: GoFunction,
+  paramPrefixes: Map<string, Set<string>> | undefined,
+  mountPrefixes: Set<string> | undefined
+): ScanResult | null {
+  const file = functionFile(graph, fn);
+  const qualifiers = fileQualifiers(file, graph.project);
+  const frameworks = new Set<RouterFramework>();
+  let httpQualifier: string | null = null;
+  for (const [name, path] of qualifiers) {
+    const framework = FRAMEWORKS.find(([pattern]) => pattern.test(path))?.[1];
+    if (framework) frameworks.add(framework);
+    if (path === 'net/http') httpQualifier = name;
+  }
+  if (frameworks.size === 0) return null;
+
+  const result: ScanResult = { routes: [], skipped: 0, passed: [], mounted: [] };
+  const defaults = mountPrefixes && mountPrefixes.size > 0 ? [...mountPrefixes] : [''];
+  const env = new Map<string, string[]>();
+  for (const [param, prefixes] of paramPrefixes ?? []) env.set(param, [...prefixes]);
+  const calls = graph.callsFrom.get(fn.id) ?? [];
+  const isHttp = (node: Node | null | undefined, name: string) =>
+    node?.type === 'selector_expression' && node.childForFieldName('operand')?.text === httpQualifier && node.childForFieldName('field')?.text === name;
+
+  // Prefix of a router expression in scope
+  const prefixesOf = (node: Node | null, scope: Map<string, string[]>): string[] => {
+    if (!node) return defaults;
+    if (node.type === 'identifier') return scope.get(node.text) ?? defaults;
+    return groupPrefixes(node, scope) ?? defaults;
+  };
+  // Prefix of an expression deriving a router or route group from another, or null when it isn't one
+  const groupPrefixes = (node: Node, scope: Map<string, string[]>): string[] | null => {
+    if (node.type === 'identifier') return scope.get(node.text) ?? null;
+    if (node.type === 'parenthesized_expression') return node.namedChildren[0] ? groupPrefixes(node.namedChildren[0], scope) : null;
+    if (node.type !== 'call_expression') return null;
+    const callee = node.childForFieldName('function');
+    if (callee?.type !== 'selector_expression') return null;
+    const operand = callee.childForFieldName('operand');
+    const field = callee.childForFieldName('field')?.text;
+    const path = stringLiteral(argumentsOf(node)[0]);
+    const join = (prefixes: string[]) => prefixes.map(p => joinPath(p, path ?? '')).slice(0, MAX_PREFIXES);
+    switch (field) {
+      case 'Group':
+        // gin and echo take a prefix; chi's takes a func and keeps the router's
+        return path !== null ? join(prefixesOf(operand, scope)) : prefixesOf(operand, scope);
+      case 'Route':
+      case 'PathPrefix':
+        return path !== null ? join(prefixesOf(operand, scope)) : null;
+      case 'Subrouter':
+      case 'With':
+        return prefixesOf(operand, scope);
+      default:
+        return field && operand && GORILLA_MATCHERS.has(field) ? groupPrefixes(operand, scope) : null;
+    }
+  };
+
+  // Without types, a variable counts as a router when routes are registered on it in this function
+  const routers = new Set<string>();
+  walk(fn.node, (node) => {
+    if (node.type !== 'call_expression') return;
+    const callee = node.childForFieldName('function');
+    const operand = callee?.type === 'selector_expression' ? callee.childForFieldName('operand') : null;
+    const field = callee?.childForFieldName('field')?.text ?? '';
+    if (operand?.type === 'identifier' && operand.text !== httpQualifier && REGISTRATIONS.has(field)) {
+      routers.add(operand.text);
+    }
+  });
+  // Routers mounted under a prefix: mux.Handle("/api/", http.StripPrefix("/api", api)), r.Mount("/admin", admin)
+  const mountedRouters = new Set<string>();
+  walk(fn.node, (node) => {
+    if (node.type !== 'call_expression') return;
+    const callee = node.childForFieldName('function');
+    const field = callee?.type === 'selector_expression' ? callee.childForFieldName('field')?.text : undefined;
+    if (field !== 'Handle' && field !== 'Mount') return;
+    const args = argumentsOf(node);
+    const path = stringLiteral(args[0]);
+    let handler = args[1];
+    if (path === null || !handler) return;
+    // chi strips the mount path; a net/http mux only does with StripPrefix, and otherwise sees full paths
+    let prefix = field === 'Mount' ? path : '';
+    if (handler.type === 'call_expression' && isHttp(handler.childForFieldName('function'), 'StripPrefix')) {
+      prefix = stringLiteral(argumentsOf(handler)[0]) ?? '';
+      handler = argumentsOf(handler)[1];
+    }
+    if (handler?.type !== 'identifier' || !routers.has(handler.text)) return;
+    mountedRouters.add(handler.text);
+    env.set(handler.text, prefixesOf(callee!.childForFieldName('operand'), env).map(p => joinPath(p, prefix.replace(/\/$/, ''))));
+  });
+
+  const handlerOf = (node: Node): Pick<HttpRoute, 'handler' | 'inline' | 'approximate'> & { roots: string[]; external: string[] } => {
+    if (node.type === 'parenthesized_expression' && node.namedChildren[0]) return handlerOf(node.namedChildren[0]);
+    if (node.type === 'func_literal') {
+      const inside = calls.filter(c => c.node.startIndex >= node.startIndex && c.node.endIndex <= node.endIndex);
+      return {
+        handler: fn.id,
+        inline: true,
+        approximate: inside.some(c => c.approximate),
+        roots: inside.filter(c => !c.external).map(c => c.callee),
+        external: inside.filter(c => c.external).map(c => c.callee),
+      };
+    }
+    if (node.type === 'identifier' || node.type === 'selector_expression') {
+      const refs = calls.filter(c => c.reference && c.node.startIndex === node.startIndex && !c.external);
+      if (refs.length > 0) return { handler: refs[0].callee, inline: false, approximate: refs[0].approximate, roots: [refs[0].callee], external: [] };
+      const method = node.type === 'selector_expression' ? node.childForFieldName('field')?.text : undefined;
+      const candidates = [...graph.functions.values()].filter(f => f.receiver && f.name === method).map(f => f.id).sort();
+      return { handler: candidates.length === 1 ? candidates[0] : null, inline: false, approximate: candidates.length > 0, roots: candidates, external: [] };
+    }
+    if (node.type === 'unary_expression' || node.type === 'composite_literal') {
+      // &apiHandler{...}: the type's ServeHTTP
+      const literal = node.type === 'unary_expression' ? node.childForFieldName('operand') : node;
+      const type = literal?.type === 'composite_literal' ? qualifiedTypeName(literal.childForFieldName('type'), fn.package, qualifiers) : null;
+      const serve = type ? `${type}.ServeHTTP` : null;
+      const found = serve !== null && graph.functions.has(serve);
+      return { handler: found ? serve : null, inline: false, approximate: false, roots: found ? [serve!] : [], external: [] };
+    }
+    if (node.type === 'call_expression') {
+      // A wrapper — http.HandlerFunc(h), auth(h), chain.Then(h) — around the function it is given
+      for (const arg of argumentsOf(node).reverse()) {
+        const inner = handlerOf(arg);
+        if (inner.handler || inner.roots.length > 0) return inner;
+      }
+      // A constructor: NewServer(db) returning a type with ServeHTTP
+      const constructors = graph.calleesOf(fn.file, node).filter(c => !c.external).map(c => c.callee);
+      const roots = [...constructors];
+      for (const id of constructors) {
+        const constructor = graph.functions.get(id)!;
+        const ctorFile = functionFile(graph, constructor);
+        const type = qualifiedTypeName(constructor.node.childForFieldName('result'), constructor.package, fileQualifiers(ctorFile, graph.project));
+        if (type && graph.functions.has(`${type}.ServeHTTP`)) roots.push(`${type}.ServeHTTP`);
+      }
+      return { handler: roots[roots.length - 1] ?? null, inline: false, approximate: false, roots, external: [] };
+    }
+    return { handler: null, inline: false, approximate: false, roots: [], external: [] };
+  };
+
+  const registration = (node: Node, scope: Map<string, string[]>): Registration | null | 'skipped' => {
+    const callee = node.childForFieldName('function');
+    if (callee?.type !== 'selector_expression') return null;
+    const router = callee.childForFieldName('operand');
+    const field = callee.childForFieldName('field')?.text ?? '';
+    const args = argumentsOf(node);
+    const literal = args.map(a => stringLiteral(a));
+    const pathArg = (i: number): string | 'skipped' | null =>
+      literal[i] ?? (args[i] && args.length > i + 1 && args[i].type !== 'func_literal' ? 'skipped' : null);
+
+    if (router?.text === httpQualifier && field !== 'Handle' && field !== 'HandleFunc') return null;
+    if ((frameworks.has('gin') || frameworks.has('echo')) && (METHODS.includes(field) || field === 'Any') && args.length >= 2) {
+      const path = pathArg(0);
+      if (path === null || path === 'skipped') return path;
+      const gin = frameworks.has('gin');
+      // gin takes middleware before the handler, echo after it
+      return { framework: gin ? 'gin' : 'echo', methods: [field === 'Any' ? '*' : field], path, router, handler: gin ? args[args.length - 1] : args[1] };
+    }
+    if (frameworks.has('gin') && field === 'Handle' && literal[0] && /^[A-Z]+$/.test(literal[0]) && args.length >= 3) {
+      return literal[1] !== null ? { framework: 'gin', methods: [literal[0]], path: literal[1], router, handler: args[args.length - 1] } : 'skipped';
+    }
+    if (frameworks.has('echo') && field === 'Add' && literal[0] && args.length >= 3) {
+      return literal[1] !== null ? { framework: 'echo', methods: [literal[0]], path: literal[1], router, handler: args[2] } : 'skipped';
+    }
+    if (frameworks.has('chi') && CHI_METHODS.has(field) && args.length === 2) {
+      const path = pathArg(0);
+      if (path === null || path === 'skipped') return path;
+      return path.startsWith('/') ? { framework: 'chi', methods: [CHI_METHODS.get(field)!], path, router, handler: args[1] } : null;
+    }
+    if (frameworks.has('chi') && (field === 'Method' || field === 'MethodFunc') && literal[0] && args.length >= 3) {
+      return literal[1] !== null ? { framework: 'chi', methods: [literal[0].toUpperCase()], path: literal[1], router, handler: args[2] } : 'skipped';
+    }
+    if (frameworks.has('chi') && field === 'Mount' && args.length === 2) {
+      const path = pathArg(0);
+      if (path === null || path === 'skipped') return path;
+      if (args[1].type === 'identifier' && mountedRouters.has(args[1].text)) return null;
+      if (args[1].type === 'call_expression') {
+        // A router constructor: its routes get the mount prefix
+        const constructors = graph.calleesOf(fn.file, args[1]).filter(c => !c.external);
+        const prefixes = prefixesOf(router, scope).map(p => joinPath(p, path.replace(/\/$/, '')));
+        for (const c of constructors) result.mounted.push([c.callee, prefixes]);
+        if (constructors.length > 0) return null;
+      }
+      return { framework: 'chi', methods: ['*'], path: joinPath(path, '*'), router, handler: args[1] };
+    }
+    if ((field === 'Handle' || field === 'HandleFunc') && args.length === 2) {
+      const path = pathArg(0);
+      if (path === null || path === 'skipped') return path;
+      if (args[1].type === 'identifier' && mountedRouters.has(args[1].text)) return null;
+      if (args[1].type === 'call_expression' && isHttp(args[1].childForFieldName('function'), 'StripPrefix')) {
+        const inner = argumentsOf(args[1])[1];
+        if (inner?.type === 'identifier' && mountedRouters.has(inner.text)) return null;
+      }
+      const onHttp = router?.text === httpQualifier;
+      const framework: RouterFramework = !onHttp && frameworks.has('gorilla/mux') ? 'gorilla/mux' : !onHttp && frameworks.has('chi') ? 'chi' : 'net/http';
+      if (framework === 'net/http') {
+        // Go 1.22 patterns: "GET /users/{id}"
+        const pattern = /^([A-Z]+)\s+(.*)$/.exec(path);
+        return { framework, methods: [pattern?.[1] ?? '*'], path: pattern?.[2] ?? path, router: onHttp ? null : router, handler: args[1] };
+      }
+      return { framework, methods: framework === 'gorilla/mux' ? gorillaMethods(node) : ['*'], path, router, handler: args[1] };
+    }
+    if (frameworks.has('gorilla/mux') && (field === 'Handler' || field === 'HandlerFunc') && args.length === 1) {
+      // r.Path("/users").Methods("GET").HandlerFunc(h), r.PathPrefix("/static/").Handler(files)
+      let path: string | null = null;
+      let base: Node | null = router;
+      while (base?.type === 'call_expression') {
+        const fnNode = base.childForFieldName('function');
+        if (fnNode?.type !== 'selector_expression') break;
+        const name = fnNode.childForFieldName('field')?.text ?? '';
+        if (name === 'Path' || name === 'PathPrefix') {
+          path = stringLiteral(argumentsOf(base)[0]);
+          if (path === null) return 'skipped';
+          if (name === 'PathPrefix') path = joinPath(path, '*');
+        } else if (!GORILLA_MATCHERS.has(name)) {
+          break;
+        }
+        base = fnNode.childForFieldName('operand');
+      }
+      return path === null ? null : { framework: 'gorilla/mux', methods: gorillaMethods(node), path, router: base, handler: args[0] };
+    }
+    return null;
+  };
+
+  const visit = (node: Node, scope: Map<string, string[]>) => {
+    if (node.type === 'short_var_declaration' || node.type === 'assignment_statement') {
+      const left = node.childForFieldName('left')?.namedChildren ?? [];
+      const right = node.childForFieldName('right')?.namedChildren ?? [];
+      left.forEach((ident, i) => {
+        const value = right[i];
+        if (ident?.type !== 'identifier' || !value) return;
+        const prefixes = groupPrefixes(value, scope);
+        if (prefixes) scope.set(ident.text, prefixes);
+      });
+    }
+
+    if (node.type === 'call_expression') {
+      const found = registration(node, scope);
+      if (found === 'skipped') {
+        result.skipped++;
+      } else if (found) {
+        const handler = handlerOf(found.handler);
+        const owner = handler.handler ? graph.functions.get(handler.handler)?.package ?? null : null;
+        const deps = dependencies(graph, handler.roots, handler.external, file, owner);
+        const prefixes = found.router ? prefixesOf(found.router, scope) : defaults;
+        for (const prefix of prefixes) {
+          for (const method of found.methods) {
+            result.routes.push({
+              method,
+              path: joinPath(prefix, found.path),
+              framework: found.framework,
+              file: fn.file,
+              line: node.startPosition.row + 1,
+              handler: handler.handler,
+              handlerExpr: found.handler.text.length > 80 ? `${found.handler.text.slice(0, 77)}...` : found.handler.text,
+              inline: handler.inline,
+              approximate: handler.approximate,
+              package: owner,
+              ...deps,
+            });
+          }
+        }
+      }
+
+      // chi r.Route("/api", func(r chi.Router) {...}) and r.Group(func(r chi.Router) {...}): the closure's router is a group
+      const callee = node.childForFieldName('function');
+      const field = callee?.type === 'selector_expression' ? callee.childForFieldName('field')?.text : undefined;
+      const args = argumentsOf(node);
+      const closure = args[args.length - 1];
+      if (frameworks.has('chi') && (field === 'Route' || field === 'Group') && closure?.type === 'func_literal') {
+        const param = closure.childForFieldName('parameters')?.namedChildren.find(n => n?.type === 'parameter_declaration')?.childForFieldName('name')?.text;
+        const inner = new Map(scope);
+        if (param) inner.set(param, groupPrefixes(node, scope) ?? defaults);
+        for (const arg of args.slice(0, -1)) visit(arg, scope);
+        visit(closure, inner);
+        return;
+      }
+
+      // Routers passed to workspace functions carry their prefix along
+      for (const c of graph.calleesOf(fn.file, node)) {
+        if (c.external) continue;
+        const target = graph.functions.get(c.callee)!;
+        args.forEach((arg, i) => {
+          const param = target.params[i];
+          if (arg.type === 'identifier' && param && param !== '_' && scope.has(arg.text)) result.passed.push([c.callee, param, scope.get(arg.text)!]);
+        });
+      }
+    }
+
+    for (const child of node.namedChildren) {
+      if (!child) continue;
+      visit(child, child.type === 'func_literal' ? new Map(scope) : scope);
+    }
+  };
+  const body = fn.node.childForFieldName('body');
+  if (body) visit(body, env);
+  return result;
+}
+
+/** HTTP methods from .Methods("GET", "POST") calls chained onto a gorilla/mux route */
+function gorillaMethods(registration: Node): string[] {
+  const methods: string[] = [];
+  const collect = (call: Node) => {
+    const callee = call.childForFieldName('function');
+    if (callee?.type === 'selector_expression' && callee.childForFieldName('field')?.text === 'Methods') {
+      for (const arg of argumentsOf(call)) {
+        const method = stringLiteral(arg);
+        if (method) methods.push(method.toUpperCase());
+      }
+    }
+  };
+  // Chained after: r.HandleFunc(...).Methods("GET")
+  for (let n: Node | null = registration; n?.parent?.type === 'selector_expression' && n.parent.parent?.type === 'call_expression'; n = n.parent.parent) {
+    collect(n.parent.parent);
+  }
+  // Chained before: r.Methods("GET").Path(...).HandlerFunc(...)
+  let operand = registration.childForFieldName('function')?.childForFieldName('operand') ?? null;
+  while (operand?.type === 'call_expression') {
+    collect(operand);
+    operand = operand.childForFieldName('function')?.childForFieldName('operand') ?? null;
+  }
+  return methods.length > 0 ? methods : ['*'];
+}
+
+/** Workspace and external packages reachable from a handler's roots */
+function dependencies(
+  graph: GoCallGraph,
+  roots: string[],
+  direct: string[],
+  file: GoSourceFile,
+  own: string | null
+): Pick<HttpRoute, 'packages' | 'external' | 'functions'> {
+  const reached = reachableFunctions(graph, roots.filter(id => graph.functions.has(id)));
+  const packages = new Set<string>(own ? [own] : []);
+  const external = new Set<string>();
+  const imports = [...graph.project.packages.get(file.dir)!.imports.keys()];
+  for (const callee of direct) {
+    const pkg = calleePackage(callee, imports);
+    if (pkg) external.add(pkg);
+  }
+  for (const id of reached.keys()) {
+    const fn = graph.functions.get(id)!;
+    packages.add(fn.package);
+    const fnImports = [...graph.project.packages.get(fn.dir)!.imports.keys()];
+    for (const call of graph.callsFrom.get(id) ?? []) {
+      if (!call.external) continue;
+      const pkg = calleePackage(call.callee, fnImports);
+      if (pkg) external.add(pkg);
+    }
+  }
+  return {
+    packages: [...packages].sort((a, b) => Number(b === own) - Number(a === own) || a.localeCompare(b)),
+    external: [...external].sort(),
+    functions: reached.size,
+  };
+}
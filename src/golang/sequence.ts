@@ -0,0 +1,201 @@
+import { loadGoProject } from './packages.js';
+import { loadGoCallGraph, findFunctions, type GoCall, type GoCallGraph, type GoFunction } from './callgraph.js';
+import { calleePackage } from './goroutines.js';
+import { httpRoutes, routeMatches } from './routes.js';
+
+/**
+ * A sequence diagram of what an entry point does across packages, for
+ * documenting key request paths: starting at a function (main.main, a
+ * handler) or at the handler of an HTTP route, every call into another
+ * workspace package becomes a message between package lifelines, followed
+ * down to a depth of cross-package hops. Calls within a package aren't
+ * drawn but are followed, so a package's helpers calling elsewhere still
+ * show up as that package's messages. A function expanded once is drawn
+ * without its calls after that.
+ */
+
+export interface SequenceCall {
+  /** Lifeline of the caller: the package directory, or the import path for external packages */
+  from: string;
+  to: string;
+  /** Function id of the callee ("<import path>.Name" for external ones) */
+  callee: string;
+  /** The message text: Name() or Type.Name() */
+  label: string;
+  file: string;
+  line: number;
+  /** Matched by method name only */
+  approximate: boolean;
+  external: boolean;
+  /** Why the callee's own calls aren't shown */
+  stop?: 'depth' | 'recursion' | 'repeated';
+  calls: SequenceCall[];
+}
+
+export interface CallSequence {
+  projectRoot: string;
+  /** Function id of the entry point */
+  entry: string;
+  /** The route the entry point serves, when one was given */
+  route: string | null;
+  depth: number;
+  /** Lifelines in order of appearance, the entry point's package first */
+  participants: string[];
+  calls: SequenceCall[];
+  /** Messages drawn */
+  messages: number;
+  truncated: boolean;
+  warnings: string[];
+}
+
+export interface SequenceOptions {
+  /** Cross-package hops to follow (default 4) */
+  depth?: number;
+  /** Also draw calls into third-party packages (the standard library is never drawn) */
+  external?: boolean;
+  algo?: string;
+  signal?: AbortSignal;
+}
+
+/** Beyond this many messages a diagram stops being readable */
+const MAX_MESSAGES = 150;
+
+export async function analyzeSequence(projectRoot: string, entry: string, options: SequenceOptions = {}): Promise<CallSequence> {
+  const project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  const { fn, route } = resolveEntry(graph, entry);
+  const sequence = callSequence(graph, fn, options);
+  if (sequence.truncated) warnings.push(`Stopped at ${MAX_MESSAGES} messages; a lower --depth gives a readable diagram`);
+  return { projectRoot, route, ...sequence, warnings };
+}
+
+// An HTTP route ("GET /users/1", "/health") or a function name
+function resolveEntry(graph: GoCallGraph, entry: string): { fn: GoFunction; route: string | null } {
+  const request = /^(?:([A-Z]+)\s+)?(\/\S*)$/.exec(entry.trim());
+  if (request) {
+    const [, method, path] = request;
+    const matches = httpRoutes(graph).routes.filter(r =>
+      (!method || r.method === '*' || r.method === method) && routeMatches(r.path, path, r.framework === 'net/http'));
+    if (matches.length === 0) throw new Error(`No registered route serves ${entry}`);
+    // The most specific pattern wins, as with the routers themselves
+    const route = matches.sort((a, b) => b.path.length - a.path.length || Number(a.method === '*') - Number(b.method === '*'))[0];
+    const where = `${route.method} ${route.path} (${route.file}:${route.line})`;
+    if (!route.handler) throw new Error(`The handler of ${where} could not be resolved: ${route.handlerExpr}`);
+    if (route.inline) throw new Error(`${where} is served by a func literal in ${route.handler}; name the function to draw it`);
+    return { fn: graph.functions.get(route.handler)!, route: `${route.method} ${route.path}` };
+  }
+
+  const found = findFunctions(graph, entry);
+  if (found.length === 0) throw new Error(`No function matches "${entry}"`);
+  if (found.length > 1) {
+    const shown = found.slice(0, 5).map(f => f.id).join(', ');
+    throw new Error(`"${entry}" matches ${found.length} functions: ${shown}${found.length > 5 ? ', …' : ''}; qualify it with more of the import path`);
+  }
+  return { fn: found[0], route: null };
+}
+
+/** The sequence of cross-package calls from fn */
+export function callSequence(graph: GoCallGraph, fn: GoFunction, options: SequenceOptions = {}): Omit<CallSequence, 'projectRoot' | 'route' | 'warnings'> {
+  const depth = options.depth ?? 4;
+  const lifeline = (f: GoFunction) => f.dir === '.' ? graph.project.packages.get(f.dir)?.name || '.' : f.dir;
+  const participants = [lifeline(fn)];
+  const expanded = new Set<string>([fn.id]);
+  let messages = 0;
+  let truncated = false;
+
+  const imports = (f: GoFunction) => [...graph.project.packages.get(f.dir)!.imports.keys()];
+
+  // Cross-package calls made by caller, following calls within its package; stack guards recursion
+  const visit = (caller: GoFunction, level: number, stack: Set<string>): SequenceCall[] => {
+    const result: SequenceCall[] = [];
+    const seen = new Set<string>();
+    for (const call of sortedCalls(graph, caller.id)) {
+      if (seen.has(call.callee)) continue;
+      seen.add(call.callee);
+      if (messages >= MAX_MESSAGES) {
+        truncated = true;
+        break;
+      }
+
+      if (call.external) {
+        const pkg = calleePackage(call.callee, imports(caller));
+        // Only third-party packages: the standard library's first path element has no dot
+        if (!options.external || !pkg || !pkg.split('/')[0].includes('.')) continue;
+        if (!participants.includes(pkg)) participants.push(pkg);
+        messages++;
+        result.push({
+          from: lifeline(caller), to: pkg, callee: call.callee, label: `${call.callee.slice(pkg.length + 1)}()`,
+          file: call.file, line: call.line, approximate: false, external: true, calls: [],
+        });
+        continue;
+      }
+
+      const callee = graph.functions.get(call.callee);
+      if (!callee) continue;
+      if (callee.dir === caller.dir) {
+        // Same package: not a message, but what it calls may cross a boundary
+        if (!expanded.has(callee.id)) {
+          expanded.add(callee.id);
+          result.push(...visit(callee, level, new Set([...stack, callee.id])));
+        }
+        continue;
+      }
+
+      const to = lifeline(callee);
+      if (!participants.includes(to)) participants.push(to);
+      messages++;
+      const message: SequenceCall = {
+        from: lifeline(caller), to, callee: callee.id, label: `${callee.receiver ? `${callee.receiver}.` : ''}${callee.name}()`,
+        file: call.file, line: call.line, approximate: call.approximate, external: false, calls: [],
+      };
+      if (stack.has(callee.id)) message.stop = 'recursion';
+      else if (expanded.has(callee.id)) message.stop = 'repeated';
+      else if (level + 1 >= depth) message.stop = 'depth';
+      else {
+        expanded.add(callee.id);
+        message.calls = visit(callee, level + 1, new Set([...stack, callee.id]));
+      }
+      result.push(message);
+    }
+    return result;
+  };
+
+  const calls = visit(fn, 0, new Set([fn.id]));
+  return { entry: fn.id, depth, participants, calls, messages, truncated };
+}
+
+// Calls in source order; an approximate call site resolving to several methods is left out, since it can't say which one runs
+function sortedCalls(graph: GoCallGraph, id: string): GoCall[] {
+  const calls = (graph.callsFrom.get(id) ?? []).filter(c => !c.reference);
+  const candidates = new Map<number, number>();
+  for (const call of calls) candidates.set(call.node.startIndex, (candidates.get(call.node.startIndex) ?? 0) + 1);
+  return calls
+    .filter(c => !c.approximate || candidates.get(c.node.startIndex) === 1)
+    .sort((a, b) => a.node.startIndex - b.node.startIndex);
+}
+
+/** Mermaid sequenceDiagram text; wrap it in a ```mermaid fence to embed it */
+export function toMermaidSequence(sequence: CallSequence): string {
+  const lines: string[] = ['sequenceDiagram'];
+  const ids = new Map(sequence.participants.map((p, i) => [p, `p${i}`]));
+  // Aliased ids, as mermaid ids can't hold slashes
+  for (const [label, id] of ids) lines.push(`  participant ${id} as ${label}`);
+
+  const draw = (calls: SequenceCall[]) => {
+    for (const call of calls) {
+      const [from, to] = [ids.get(call.from)!, ids.get(call.to)!];
+      const text = `${call.label}${call.approximate ? ' ?' : ''}`;
+      if (call.calls.length === 0) {
+        lines.push(`  ${from}->>${to}: ${text}`);
+        continue;
+      }
+      lines.push(`  ${from}->>+${to}: ${text}`);
+      draw(call.calls);
+      lines.push(`  deactivate ${to}`);
+    }
+  };
+  draw(sequence.calls);
+  if (sequence.truncated) lines.push(`  Note over ${ids.get(sequence.participants[0])}: truncated at ${MAX_MESSAGES} messages`);
+  return lines.join('\n') + '\n';
+}
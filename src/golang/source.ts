@@ -0,0 +1,243 @@
+import { readFileSync } from 'fs';
+import { join } from 'path';
+import type { Node } from 'web-tree-sitter';
+import type { ParsedFile } from '../parser/types.js';
+import { getParser, initParser, isInitialized } from '../parser/wasm-init.js';
+import { scanDirectory, scanGoTestFiles } from '../utils/files.js';
+import { packageOf } from '../graph/model.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('golang');
+
+/**
+ * Syntax-level access to Go sources for the Go-specific analyses
+ * (imports, init functions, goroutines, ...). The symbol graph stays the
+ * source of truth for dependencies; this is for facts it doesn't record.
+ */
+
+export interface GoSourceFile {
+  /** Project-relative path */
+  file: string;
+  /** Package directory, project-relative */
+  dir: string;
+  /** Name from the package clause ("main", "foo", "foo_test") */
+  packageName: string;
+  isTest: boolean;
+  source: string;
+  root: Node;
+}
+
+export type GoImportKind = 'normal' | 'alias' | 'blank' | 'dot';
+
+export interface GoImport {
+  path: string;
+  /** Explicit name, for alias imports */
+  alias?: string;
+  kind: GoImportKind;
+  line: number;
+  column: number;
+  /** Offsets of the quoted path literal in the source string, for rewriting */
+  pathStart: number;
+  pathEnd: number;
+}
+
+export interface LoadGoFilesOptions {
+  /** Include _test.go files (default false) */
+  tests?: boolean;
+}
+
+/** Parse every Go file in the project (vendor and hidden directories excluded) */
+export async function loadGoFiles(projectRoot: string, options: LoadGoFilesOptions = {}): Promise<GoSourceFile[]> {
+  await initParser();
+  const files = scanDirectory(projectRoot).filter(f => f.endsWith('.go'));
+  if (options.tests) files.push(...scanGoTestFiles(projectRoot));
+
+  const result: GoSourceFile[] = [];
+  for (const file of files.sort()) {
+    try {
+      const source = readFileSync(join(projectRoot, file), 'utf-8');
+      result.push(parseGoSource(file, source));
+    } catch (err) {
+      log.warn(`Error parsing file ${file}`, { file, error: err });
+    }
+  }
+  return result;
+}
+
+/** Parse one Go file. The parser must already be initialized. */
+export function parseGoSource(file: string, source: string): GoSourceFile {
+  const tree = getParser('go').parse(source, null, { bufferSize: 1024 * 1024 });
+  if (!tree) throw new Error(`Failed to parse ${file}`);
+  const root = tree.rootNode;
+  const clause = root.namedChildren.find(n => n?.type === 'package_clause');
+  const packageName = clause?.namedChildren.find(n => n?.type === 'package_identifier')?.text ?? '';
+  return {
+    file,
+    dir: packageOf(file),
+    packageName,
+    isTest: file.endsWith('_test.go'),
+    source,
+    root,
+  };
+}
+
+/**
+ * The non-test Go files of a parsed project, each parsed once for the Go
+ * hooks of buildGraph to share. Empty until the parser is initialized;
+ * unreadable or unparsable files are left out.
+ */
+export function parseGoSources(projectRoot: string, files: ParsedFile[]): GoSourceFile[] {
+  if (!isInitialized()) return [];
+  const sources: GoSourceFile[] = [];
+  for (const { filePath } of files) {
+    if (!filePath.endsWith('.go') || filePath.endsWith('_test.go')) continue;
+    try {
+      sources.push(parseGoSource(filePath, readFileSync(join(projectRoot, filePath), 'utf-8')));
+    } catch { /* unreadable or unparsable — skip */ }
+  }
+  return sources;
+}
+
+/** Depth-first walk; return false from visit to skip a node's children */
+export function walk(node: Node, visit: (node: Node) => boolean | void): void {
+  if (visit(node) === false) return;
+  for (const child of node.namedChildren) {
+    if (child) walk(child, visit);
+  }
+}
+
+export function goImports(file: GoSourceFile): GoImport[] {
+  const imports: GoImport[] = [];
+  for (const decl of file.root.namedChildren) {
+    if (decl?.type !== 'import_declaration') continue;
+    walk(decl, (node) => {
+      if (node.type !== 'import_spec') return;
+      const pathNode = node.childForFieldName('path');
+      if (!pathNode) return false;
+      const nameNode = node.childForFieldName('name');
+      const name = nameNode?.text;
+      imports.push({
+        path: pathNode.text.slice(1, -1),
+        alias: name && name !== '_' && name !== '.' ? name : undefined,
+        kind: name === '_' ? 'blank' : name === '.' ? 'dot' : name ? 'alias' : 'normal',
+        line: node.startPosition.row + 1,
+        column: node.startPosition.column + 1,
+        pathStart: pathNode.startIndex,
+        pathEnd: pathNode.endIndex,
+      });
+      return false;
+    });
+  }
+  return imports;
+}
+
+/** Top-level function declarations with the given name (e.g. "init", "main") */
+export function functionsNamed(file: GoSourceFile, name: string): Node[] {
+  return file.root.namedChildren.filter((n): n is Node =>
+    n?.type === 'function_declaration' && n.childForFieldName('name')?.text === name
+  );
+}
+
+export type GoDeclarationKind = 'func' | 'method' | 'type' | 'interface' | 'const' | 'var';
+
+export interface GoDeclaration {
+  name: string;
+  kind: GoDeclarationKind;
+  /** Receiver type name for methods, without pointer ("Client" for func (c *Client) Get) */
+  receiver?: string;
+  exported: boolean;
+  line: number;
+  node: Node;
+}
+
+/** Top-level declarations of a file: funcs, methods, types, consts and vars */
+export function goDeclarations(file: GoSourceFile): GoDeclaration[] {
+  const decls: GoDeclaration[] = [];
+  const add = (name: string, kind: GoDeclarationKind, node: Node, receiver?: string) => {
+    decls.push({ name, kind, receiver, exported: /^\p{Lu}/u.test(name), line: node.startPosition.row + 1, node });
+  };
+
+  for (const node of file.root.namedChildren) {
+    if (!node) continue;
+    switch (node.type) {
+      case 'function_declaration': {
+        const name = node.childForFieldName('name')?.text;
+        if (name) add(name, 'func', node);
+        break;
+      }
+      case 'method_declaration': {
+        const name = node.childForFieldName('name')?.text;
+        const receiver = receiverTypeName(node);
+        if (name && receiver) add(name, 'method', node, receiver);
+        break;
+      }
+      case 'type_declaration':
+        for (const spec of node.namedChildren) {
+          if (spec?.type !== 'type_spec' && spec?.type !== 'type_alias') continue;
+          const name = spec.childForFieldName('name')?.text;
+          const type = spec.childForFieldName('type');
+          if (name) add(name, type?.type === 'interface_type' ? 'interface' : 'type', spec);
+        }
+        break;
+      case 'const_declaration':
+      case 'var_declaration':
+        walk(node, (child) => {
+          if (child.type !== 'const_spec' && child.type !== 'var_spec') return;
+          for (const ident of child.childrenForFieldName('name')) {
+            if (ident) add(ident.text, node.type === 'const_declaration' ? 'const' : 'var', child);
+          }
+          return false;
+        });
+        break;
+    }
+  }
+  return decls;
+}
+
+/** Receiver type of a method_declaration, without pointer or type parameters */
+export function receiverTypeName(method: Node): string | null {
+  const receiver = method.childForFieldName('receiver');
+  const param = receiver?.namedChildren.find(n => n?.type === 'parameter_declaration');
+  let type = param?.childForFieldName('type') ?? null;
+  while (type && (type.type === 'pointer_type' || type.type === 'generic_type')) {
+    type = type.type === 'pointer_type' ? type.namedChildren[0] ?? null : type.childForFieldName('type');
+  }
+  return type?.type === 'type_identifier' ? type.text : null;
+}
+
+/**
+ * The package comment: the comment directly above the package clause, as
+ * text with the comment markers removed. Directives (//go:build,
+ * //go:generate, ...) are dropped, as godoc drops them.
+ */
+export function packageComment(file: GoSourceFile): string | null {
+  const clause = file.root.namedChildren.find(n => n?.type === 'package_clause');
+  if (!clause) return null;
+  // Sections generated by depwire docs --package-deps aren't the author's description
+  const header = file.source.slice(0, clause.startIndex)
+    .replace(/^\/\/depwire:begin dependencies$[\s\S]*?^\/\/depwire:end dependencies$/m, '')
+    .replace(/\s+$/, '');
+
+  const text: string[] = [];
+  if (header.endsWith('*/')) {
+    const start = header.lastIndexOf('/*');
+    if (start === -1) return null;
+    text.push(...header.slice(start + 2, -2).split('\n').map(l => l.replace(/^\s*\* ?/, '')));
+  } else {
+    const lines = header.split('\n');
+    for (let i = lines.length - 1; i >= 0 && lines[i].trim().startsWith('//'); i--) {
+      const line = lines[i].trim();
+      if (/^\/\/[a-z0-9]+:\S/.test(line)) continue;
+      text.unshift(line.replace(/^\/\/ ?/, ''));
+    }
+  }
+  const doc = text.join('\n').trim();
+  return doc || null;
+}
+
+/** The first sentence of a doc comment, on one line — what go doc shows in package lists */
+export function docSynopsis(doc: string): string {
+  const paragraph = doc.split(/\n\s*\n/)[0].replace(/\s+/g, ' ').trim();
+  const end = /[.!?](?=\s|$)/.exec(paragraph);
+  return end ? paragraph.slice(0, end.index + 1) : paragraph;
+}
@@ -0,0 +1,71 @@
+import { execFile } from 'child_process';
+import { existsSync } from 'fs';
+import { dirname, join } from 'path';
+import { homedir } from 'os';
+
+/**
+ * Helpers for shelling out to the Go toolchain.
+ * Depwire's own Go analysis is tree-sitter based; these are used where a
+ * feature needs type information only `go` itself can provide.
+ */
+
+export interface GoCommandResult {
+  stdout: string;
+  stderr: string;
+  exitCode: number;
+}
+
+export interface GoCommandOptions {
+  cwd: string;
+  signal?: AbortSignal;
+  env?: Record<string, string>;
+}
+
+/**
+ * Run `go <args>`. Resolves with the output even on a non-zero exit (many go
+ * subcommands use exit codes to report findings); rejects only if `go` could
+ * not be started or the signal aborted.
+ */
+export function runGo(args: string[], options: GoCommandOptions): Promise<GoCommandResult> {
+  return new Promise((resolve, reject) => {
+    execFile('go', args, {
+      cwd: options.cwd,
+      signal: options.signal,
+      env: { ...process.env, ...options.env },
+      maxBuffer: 256 * 1024 * 1024,
+      encoding: 'utf-8',
+    }, (error, stdout, stderr) => {
+      if (error && typeof (error as NodeJS.ErrnoException).code === 'string') {
+        // ENOENT and friends — the toolchain isn't available
+        const code = (error as NodeJS.ErrnoException).code;
+        reject(code === 'ENOENT'
+          ? new Error('Go toolchain not found — install Go and make sure `go` is on PATH')
+          : error);
+        return;
+      }
+      resolve({ stdout, stderr, exitCode: error ? (error as { code?: number }).code ?? 1 : 0 });
+    });
+  });
+}
+
+/** Directory of the nearest go.mod at or above dir, or null */
+export function findGoModuleRoot(dir: string): string | null {
+  let current = dir;
+  for (;;) {
+    if (existsSync(join(current, 'go.mod'))) return current;
+    const parent = dirname(current);
+    if (parent === current) return null;
+    current = parent;
+  }
+}
+
+/** The module cache directory (go env GOMODCACHE, or its default without a toolchain) */
+export async function goModCacheDir(cwd: string, signal?: AbortSignal): Promise<string> {
+  try {
+    const result = await runGo(['env', 'GOMODCACHE'], { cwd, signal });
+    if (result.exitCode === 0 && result.stdout.trim()) return result.stdout.trim();
+  } catch (err) {
+    if (signal?.aborted) throw err;
+  }
+  return process.env.GOMODCACHE || join(process.env.GOPATH?.split(':')[0] || join(homedir(), 'go'), 'pkg', 'mod');
+}
@@ -0,0 +1,418 @@
+import type { DirectedGraph } from 'graphology';
+import type { Node } from 'web-tree-sitter';
+import { walk, goDeclarations, receiverTypeName, type GoSourceFile } from './source.js';
+import { loadGoProject, groupGoPackages, type GoProject } from './packages.js';
+import { fileQualifiers } from './references.js';
+import { buildGoCallGraph, packageKey, type GoCallGraph } from './callgraph.js';
+
+/**
+ * The communication topology of a Go workspace: which packages exchange
+ * data at run time through channels and shared package-level variables.
+ * The import graph says who may call whom; this says whose goroutines talk
+ * to whose, which an import of a "config" or "events" package hides.
+ *
+ * Channels count where they cross a package boundary in an exported
+ * signature (func A() <-chan Event, func (b *Bus) Publish(ch chan<- Event))
+ * or as an exported package-level channel. Shared state is every other
+ * exported package-level var, except sentinel errors. Edges follow the
+ * data: from the package that sends or writes to the one that receives or
+ * reads.
+ *
+ * The global state inventory is the stricter view of shared state: every
+ * package-level var, unexported ones included on request, that something
+ * other than its initializer or an init function writes.
+ */
+
+export type CommunicationKind = 'channel' | 'shared-state';
+
+/** Data direction seen from the declaring package: out (it sends), in (it receives), both */
+export type ChannelFlow = 'in' | 'out' | 'both';
+
+export interface CommunicationUse {
+  package: string;
+  file: string;
+  line: number;
+  /** Id of the enclosing func or method ("<package>.init" for every init); null in package-level declarations */
+  function: string | null;
+  /** call of a func with channels; write/send or read/receive of a var */
+  access: 'call' | 'write' | 'read';
+}
+
+export interface CommunicationPoint {
+  kind: CommunicationKind;
+  /** Key of the declaring package */
+  package: string;
+  /** "Events", "Bus.Subscribe" */
+  name: string;
+  declaration: 'func' | 'method' | 'var';
+  /** The channel types of the signature, or the var's declared type when there is one */
+  type: string;
+  /** Channels only */
+  flow?: ChannelFlow;
+  file: string;
+  line: number;
+  /** Uses in the declaring package and elsewhere */
+  uses: CommunicationUse[];
+}
+
+export interface CommunicationEdge {
+  from: string;
+  to: string;
+  kind: CommunicationKind;
+  /** Names of the points behind the edge */
+  via: string[];
+  uses: number;
+}
+
+export interface CommunicationReport {
+  projectRoot: string;
+  /** Points used outside their package, or written and read by different packages */
+  points: CommunicationPoint[];
+  edges: CommunicationEdge[];
+}
+
+export interface GlobalVariable {
+  /** Key of the declaring package */
+  package: string;
+  name: string;
+  exported: boolean;
+  /** Declared type, when there is one */
+  type: string;
+  file: string;
+  line: number;
+  /** Writes outside the initializer and init functions */
+  writes: CommunicationUse[];
+  /** Keys of packages writing it after init, and of packages reading it */
+  writers: string[];
+  readers: string[];
+  uses: CommunicationUse[];
+}
+
+export interface GlobalStateReport {
+  projectRoot: string;
+  variables: GlobalVariable[];
+  /** Writing package → reading package, kind 'shared-state' */
+  edges: CommunicationEdge[];
+}
+
+export interface GlobalStateOptions {
+  /** Include unexported variables */
+  unexported?: boolean;
+}
+
+export async function analyzeCommunication(projectRoot: string): Promise<CommunicationReport> {
+  const project = await loadGoProject(projectRoot);
+  const points = communicationPoints(project, buildGoCallGraph(project));
+  return { projectRoot, points, edges: communicationEdges(points) };
+}
+
+export function communicationPoints(project: GoProject, graph: GoCallGraph): CommunicationPoint[] {
+  const points: CommunicationPoint[] = [];
+  const vars = new Map<string, CommunicationPoint>();
+
+  for (const pkg of project.packages.values()) {
+    const key = packageKey(pkg);
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      for (const decl of goDeclarations(file)) {
+        if (!decl.exported) continue;
+        const at = { package: key, file: file.file, line: decl.line, uses: [] as CommunicationUse[] };
+        if (decl.kind === 'func' || (decl.kind === 'method' && decl.receiver && /^\p{Lu}/u.test(decl.receiver))) {
+          const channels = signatureChannels(decl.node);
+          if (channels.length === 0) continue;
+          const name = decl.receiver ? `${decl.receiver}.${decl.name}` : decl.name;
+          points.push({ kind: 'channel', name, declaration: decl.kind, type: channels.map(c => c.type).join(', '), flow: combine(channels.map(c => c.flow)), ...at });
+        } else if (decl.kind === 'var' && !isSentinelError(decl.node)) {
+          const type = decl.node.childForFieldName('type');
+          const channel = type?.type === 'channel_type' ? type : madeChannel(decl.node);
+          const point: CommunicationPoint = channel
+            ? { kind: 'channel', name: decl.name, declaration: 'var', type: squash(channel.text), flow: channelFlow(channel, false), ...at }
+            : { kind: 'shared-state', name: decl.name, declaration: 'var', type: type ? squash(type.text) : '', ...at };
+          points.push(point);
+          vars.set(`${key}.${decl.name}`, point);
+        }
+      }
+    }
+  }
+  collectVarUses(project, vars);
+
+  // Calls of funcs and methods with channels are resolved by the call graph
+  const byId = new Map(points.filter(p => p.declaration !== 'var').map(p => [`${p.package}.${p.name}`, p]));
+  for (const call of graph.calls) {
+    const point = byId.get(call.callee);
+    if (!point || call.reference) continue;
+    const caller = graph.functions.get(call.caller)!;
+    point.uses.push({ package: caller.package, file: call.file, line: call.line, function: caller.id, access: 'call' });
+  }
+
+  return points
+    .filter((p) => {
+      if (p.declaration === 'var') return writersAndReaders(p).some(([from, to]) => from !== to);
+      return p.uses.some(u => u.package !== p.package);
+    })
+    .sort((a, b) => a.package.localeCompare(b.package) || a.name.localeCompare(b.name));
+}
+
+export async function analyzeGlobalState(projectRoot: string, options: GlobalStateOptions = {}): Promise<GlobalStateReport> {
+  const variables = globalVariables(await loadGoProject(projectRoot), options);
+  const edges = edgeCollector();
+  for (const variable of variables) {
+    for (const writer of variable.writers) {
+      for (const reader of variable.readers) {
+        edges.add(writer, reader, 'shared-state', variable.name, variable.uses.filter(u => u.package === reader && u.access === 'read').length);
+      }
+    }
+  }
+  return { projectRoot, variables, edges: edges.sorted() };
+}
+
+/**
+ * Package-level vars written after init. Channels are left to the
+ * communication topology: sending on one doesn't change the var.
+ */
+export function globalVariables(project: GoProject, options: GlobalStateOptions = {}): GlobalVariable[] {
+  const vars = new Map<string, GlobalVariable>();
+  for (const pkg of project.packages.values()) {
+    const key = packageKey(pkg);
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      for (const decl of goDeclarations(file)) {
+        if (decl.kind !== 'var' || decl.name === '_' || (!decl.exported && !options.unexported) || isSentinelError(decl.node)) continue;
+        const type = decl.node.childForFieldName('type');
+        if (type?.type === 'channel_type' || madeChannel(decl.node)) continue;
+        vars.set(`${key}.${decl.name}`, {
+          package: key,
+          name: decl.name,
+          exported: decl.exported,
+          type: type ? squash(type.text) : '',
+          file: file.file,
+          line: decl.line,
+          writes: [],
+          writers: [],
+          readers: [],
+          uses: [],
+        });
+      }
+    }
+  }
+  collectVarUses(project, vars);
+
+  const variables: GlobalVariable[] = [];
+  for (const variable of vars.values()) {
+    const init = `${variable.package}.init`;
+    variable.writes = variable.uses.filter(u => u.access === 'write' && u.function !== null && u.function !== init);
+    if (variable.writes.length === 0) continue;
+    variable.writers = [...new Set(variable.writes.map(u => u.package))].sort();
+    variable.readers = [...new Set(variable.uses.filter(u => u.access === 'read').map(u => u.package))].sort();
+    variables.push(variable);
+  }
+  return variables.sort((a, b) => a.package.localeCompare(b.package) || a.name.localeCompare(b.name));
+}
+
+/**
+ * Add accessor → variable edges (kind 'shared-state', with the access) for
+ * package-level vars written after init to a built graph, exported or not,
+ * given its non-test Go files parsed (parseGoSources). Runs only for
+ * projects with Go files.
+ */
+export function detectSharedStateEdges(sources: GoSourceFile[], projectRoot: string, graph: DirectedGraph): GlobalVariable[] | null {
+  if (sources.length === 0) return null;
+
+  const variables = globalVariables(groupGoPackages(projectRoot, sources), { unexported: true });
+  for (const variable of variables) {
+    const target = `${variable.file}::${variable.name}`;
+    for (const use of variable.uses) {
+      if (use.function === null || use.access === 'call') continue;
+      const source = `${use.file}::${use.function.slice(use.package.length + 1)}`;
+      if (!graph.hasNode(source) || !graph.hasNode(target) || graph.hasEdge(source, target)) continue;
+      graph.addEdge(source, target, { kind: 'shared-state', filePath: use.file, line: use.line, access: use.access });
+    }
+  }
+  return variables;
+}
+
+// Edges merged by (from, to, kind), dropping a package's edges to itself
+function edgeCollector() {
+  const edges = new Map<string, CommunicationEdge>();
+  return {
+    add(from: string, to: string, kind: CommunicationKind, via: string, uses: number) {
+      if (from === to) return;
+      const key = `${from}\0${to}\0${kind}`;
+      const edge = edges.get(key) ?? { from, to, kind, via: [], uses: 0 };
+      if (!edge.via.includes(via)) edge.via.push(via);
+      edge.uses += uses;
+      edges.set(key, edge);
+    },
+    sorted(): CommunicationEdge[] {
+      return [...edges.values()].sort((a, b) => b.uses - a.uses || a.from.localeCompare(b.from) || a.to.localeCompare(b.to));
+    },
+  };
+}
+
+/** Edges in the direction data moves */
+export function communicationEdges(points: CommunicationPoint[]): CommunicationEdge[] {
+  const edges = edgeCollector();
+  const add = (from: string, to: string, point: CommunicationPoint, uses: number) => edges.add(from, to, point.kind, point.name, uses);
+
+  for (const point of points) {
+    if (point.declaration !== 'var') {
+      // A call hands channels between the caller's package and the declaring one
+      const callers = new Map<string, number>();
+      for (const use of point.uses) callers.set(use.package, (callers.get(use.package) ?? 0) + 1);
+      for (const [caller, uses] of callers) {
+        if (point.flow !== 'in') add(point.package, caller, point, uses);
+        if (point.flow !== 'out') add(caller, point.package, point, uses);
+      }
+      continue;
+    }
+    for (const [from, to, uses] of writersAndReaders(point)) add(from, to, point, uses);
+  }
+  return edges.sorted();
+}
+
+/**
+ * (writing package, reading package, uses) for every pair. The declaring
+ * package initializes shared state, so it writes too; a channel is only
+ * written by its senders.
+ */
+function writersAndReaders(point: CommunicationPoint): Array<[string, string, number]> {
+  const writers = new Set(point.uses.filter(u => u.access === 'write').map(u => u.package));
+  if (point.kind === 'shared-state') writers.add(point.package);
+  const readers = new Map<string, number>();
+  for (const use of point.uses) {
+    if (use.access === 'read') readers.set(use.package, (readers.get(use.package) ?? 0) + 1);
+  }
+  const pairs: Array<[string, string, number]> = [];
+  for (const writer of writers) {
+    for (const [reader, uses] of readers) pairs.push([writer, reader, uses]);
+  }
+  return pairs;
+}
+
+/** Record the uses of package-level vars, keyed "<package key>.<Name>": pkg.Var elsewhere, Var in their own package */
+function collectVarUses(project: GoProject, vars: Map<string, { uses: CommunicationUse[] }>): void {
+  for (const pkg of project.packages.values()) {
+    const key = packageKey(pkg);
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      const qualifiers = fileQualifiers(file, project);
+      walk(file.root, (node) => {
+        let variable: { uses: CommunicationUse[] } | undefined;
+        if (node.type === 'selector_expression') {
+          const operand = node.childForFieldName('operand');
+          const path = operand?.type === 'identifier' ? qualifiers.get(operand.text) : undefined;
+          variable = path ? vars.get(`${path}.${node.childForFieldName('field')?.text}`) : undefined;
+        } else if (node.type === 'identifier' && !isDeclaration(node)) {
+          variable = vars.get(`${key}.${node.text}`);
+        }
+        if (!variable) return;
+        const fn = enclosingFunction(node);
+        variable.uses.push({
+          package: key,
+          file: file.file,
+          line: node.startPosition.row + 1,
+          function: fn ? `${key}.${fn}` : null,
+          access: varAccess(node),
+        });
+        return false;
+      });
+    }
+  }
+}
+
+// "Name" or "Type.Name" of the func or method declaration a node is in
+function enclosingFunction(node: Node): string | null {
+  let current = node.parent;
+  while (current && current.type !== 'function_declaration' && current.type !== 'method_declaration') current = current.parent;
+  const name = current?.childForFieldName('name')?.text;
+  if (!current || !name) return null;
+  if (current.type === 'function_declaration') return name;
+  const receiver = receiverTypeName(current);
+  return receiver ? `${receiver}.${name}` : null;
+}
+
+interface SignatureChannel {
+  type: string;
+  flow: ChannelFlow;
+}
+
+/** Channel-typed parameters and results of a func or method declaration */
+function signatureChannels(decl: Node): SignatureChannel[] {
+  const channels: SignatureChannel[] = [];
+  const collect = (node: Node | null, result: boolean) => {
+    if (!node) return;
+    walk(node, (child) => {
+      if (child.type === 'func_literal' || child.type === 'function_type') return false;
+      if (child.type !== 'channel_type') return;
+      channels.push({ type: squash(child.text), flow: channelFlow(child, !result) });
+      return false;
+    });
+  };
+  collect(decl.childForFieldName('parameters'), false);
+  collect(decl.childForFieldName('result'), true);
+  return channels;
+}
+
+/**
+ * Flow seen from the declaring side. A result or var of type <-chan T is
+ * received from by users, so data flows out. A parameter of type <-chan T
+ * is received from by the function, so data flows in.
+ */
+function channelFlow(type: Node, parameter: boolean): ChannelFlow {
+  const text = type.text.replace(/\s+/g, '');
+  if (text.startsWith('<-chan')) return parameter ? 'in' : 'out';
+  if (text.startsWith('chan<-')) return parameter ? 'out' : 'in';
+  return 'both';
+}
+
+function combine(flows: ChannelFlow[]): ChannelFlow {
+  return flows.every(f => f === flows[0]) ? flows[0] : 'both';
+}
+
+// var Events = make(chan Event, 16)
+function madeChannel(spec: Node): Node | null {
+  const value = spec.childForFieldName('value')?.namedChildren[0];
+  if (value?.type !== 'call_expression' || value.childForFieldName('function')?.text !== 'make') return null;
+  const type = value.childForFieldName('arguments')?.namedChildren[0];
+  return type?.type === 'channel_type' ? type : null;
+}
+
+// var ErrNotFound = errors.New("not found") is a constant in all but name
+function isSentinelError(spec: Node): boolean {
+  const value = spec.childForFieldName('value')?.namedChildren[0];
+  const callee = value?.type === 'call_expression' ? value.childForFieldName('function')?.text : undefined;
+  return callee === 'errors.New' || callee === 'fmt.Errorf';
+}
+
+// A name being declared, or a struct literal key (Config{Timeout: t}), rather than a use
+function isDeclaration(ident: Node): boolean {
+  const parent = ident.parent;
+  if (!parent) return false;
+  if (parent.type === 'var_spec' || parent.type === 'const_spec' || parent.type === 'parameter_declaration') {
+    return parent.childrenForFieldName('name').some(n => n?.startIndex === ident.startIndex);
+  }
+  const element = parent.type === 'literal_element' ? parent : ident;
+  return element.parent?.type === 'keyed_element' && element.parent.namedChildren[0]?.startIndex === element.startIndex;
+}
+
+/** Whether pkg.Var (or Var) is written: assigned, incremented, sent to or has its address taken, directly or through a field or index */
+function varAccess(use: Node): 'write' | 'read' {
+  let node = use;
+  for (let parent = node.parent; parent; parent = node.parent) {
+    const through = (parent.type === 'selector_expression' || parent.type === 'index_expression' || parent.type === 'parenthesized_expression')
+      && parent.namedChildren[0]?.startIndex === node.startIndex;
+    if (!through) break;
+    node = parent;
+  }
+  const parent = node.parent;
+  if (!parent) return 'read';
+  if (parent.type === 'inc_dec_statement') return 'write';
+  if (parent.type === 'send_statement' && parent.childForFieldName('channel')?.startIndex === node.startIndex) return 'write';
+  if (parent.type === 'unary_expression' && parent.childForFieldName('operator')?.text === '&') return 'write';
+  if (parent.type === 'expression_list' && parent.parent?.type === 'assignment_statement') {
+    return parent.parent.childForFieldName('left')?.startIndex === parent.startIndex ? 'write' : 'read';
+  }
+  return 'read';
+}
+
+const squash = (text: string) => text.replace(/\s+/g, ' ').trim();
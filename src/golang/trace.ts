@@ -0,0 +1,319 @@
+import { createHash } from 'crypto';
+import { existsSync, mkdirSync, readFileSync, rmSync, writeFileSync } from 'fs';
+import { dirname, join, posix } from 'path';
+import type { DirectedGraph } from 'graphology';
+import type { ParsedFile, ProjectGraph } from '../parser/types.js';
+import { findGoModules, GoModuleIndex } from './modules.js';
+
+/**
+ * Call edges recorded while a Go program runs, for what static analysis
+ * can't follow: reflection, plugins, registries. The depwiretrace package
+ * (go/depwiretrace in this repository) samples goroutine stacks and writes
+ * the cross-package calls it sees in depwire's graph format, with files
+ * named by import path since a binary doesn't know where its source is.
+ * Importing a trace maps those files onto the project and keeps the edges
+ * in .depwire/runtime-edges.json. Graphs only include them, as 'runtime'
+ * edges where no static edge already connects the two symbols, when asked
+ * to (the global --runtime-edges), so a local store never changes what
+ * lint, freeze or dead-code decide. A trace imported twice counts once.
+ */
+
+export const RUNTIME_EDGES_FILE = join('.depwire', 'runtime-edges.json');
+
+export interface RuntimeEdge {
+  /** Symbol ids in the project's graph */
+  source: string;
+  target: string;
+  /** Call site in the caller */
+  filePath: string;
+  line: number;
+  /** Samples the call was seen in, over every imported trace */
+  count: number;
+  /** The outermost non-workspace function between the two, such as reflect.Value.Call */
+  via: string | null;
+}
+
+export interface TraceSource {
+  file: string;
+  /** sha256 of the trace file, so importing it again doesn't count its calls twice */
+  digest?: string;
+  /** Main module and main package of the traced program */
+  module: string;
+  main: string;
+  startedAt: string;
+  seconds: number;
+  samples: number;
+}
+
+export interface RuntimeEdgeStore {
+  traces: TraceSource[];
+  edges: RuntimeEdge[];
+}
+
+/** The graph file depwiretrace writes: a ProjectGraph with the run in its metadata */
+export interface RuntimeTrace extends Omit<ProjectGraph, 'edges' | 'metadata'> {
+  edges: Array<ProjectGraph['edges'][number] & { count?: number; via?: string }>;
+  metadata: ProjectGraph['metadata'] & { trace?: Omit<TraceSource, 'file'> & { packages?: string[]; goVersion?: string } };
+}
+
+export interface UnmatchedEdge {
+  source: string;
+  target: string;
+  reason: 'outside the project' | 'not in the graph';
+}
+
+export interface TraceImport {
+  traces: TraceSource[];
+  /** Edges recorded in the imported traces */
+  recorded: number;
+  /** Recorded edges static analysis also finds */
+  confirmed: number;
+  /** Recorded edges only the traces show */
+  dynamic: RuntimeEdge[];
+  unmatched: UnmatchedEdge[];
+  /** Edges in the store after the import */
+  stored: number;
+  /** Trace files skipped because they were imported before */
+  skipped: string[];
+}
+
+export interface TraceImportOptions {
+  /** Drop the edges of earlier imports */
+  replace?: boolean;
+}
+
+export function readRuntimeEdges(projectRoot: string): RuntimeEdgeStore | null {
+  const file = join(projectRoot, RUNTIME_EDGES_FILE);
+  if (!existsSync(file)) return null;
+  try {
+    const store = JSON.parse(readFileSync(file, 'utf-8')) as RuntimeEdgeStore;
+    return Array.isArray(store.edges) ? store : null;
+  } catch {
+    return null;
+  }
+}
+
+/** Forget every imported trace; true when there was something to remove */
+export function clearRuntimeEdges(projectRoot: string): boolean {
+  const file = join(projectRoot, RUNTIME_EDGES_FILE);
+  if (!existsSync(file)) return false;
+  rmSync(file);
+  return true;
+}
+
+export function parseTrace(json: string, file: string): RuntimeTrace {
+  let trace: RuntimeTrace;
+  try {
+    trace = JSON.parse(json) as RuntimeTrace;
+  } catch (err) {
+    throw new Error(`${file} is not valid JSON: ${err instanceof Error ? err.message : err}`);
+  }
+  if (!Array.isArray(trace.edges) || !trace.metadata?.trace) {
+    throw new Error(`${file} is not a trace written by depwiretrace`);
+  }
+  return trace;
+}
+
+/**
+ * Map a symbol id of a trace (example.com/app/store/db.go::DB.Get) onto the
+ * project (services/api/store/db.go::DB.Get); null outside its modules.
+ */
+export function projectSymbol(id: string, index: GoModuleIndex): string | null {
+  const sep = id.indexOf('::');
+  const file = sep === -1 ? id : id.slice(0, sep);
+  const dir = index.dirForImport(posix.dirname(file));
+  if (dir === null) return null;
+  const path = dir === '.' ? posix.basename(file) : `${dir}/${posix.basename(file)}`;
+  return sep === -1 ? path : `${path}${id.slice(sep)}`;
+}
+
+// A trace's edges in the project's symbol ids, one per caller and callee; the rest go to unmatched
+function mapTraceEdges(trace: RuntimeTrace, index: GoModuleIndex, graph: DirectedGraph, unmatched: UnmatchedEdge[]): RuntimeEdge[] {
+  const edges: RuntimeEdge[] = [];
+  for (const edge of trace.edges) {
+    const source = projectSymbol(edge.source, index);
+    const target = projectSymbol(edge.target, index);
+    if (source === null || target === null) {
+      unmatched.push({ source: edge.source, target: edge.target, reason: 'outside the project' });
+      continue;
+    }
+    // Generic and package-level closure code, or a trace of other source than the checkout
+    if (!graph.hasNode(source) || !graph.hasNode(target)) {
+      unmatched.push({ source: edge.source, target: edge.target, reason: 'not in the graph' });
+      continue;
+    }
+    const filePath = projectSymbol(edge.filePath, index) ?? source.slice(0, source.indexOf('::'));
+    edges.push({ source, target, filePath, line: edge.line, count: edge.count ?? 1, via: edge.via ?? null });
+  }
+  return edges;
+}
+
+function traceSource(file: string, trace: RuntimeTrace, digest?: string): TraceSource {
+  const { module, main, startedAt, seconds, samples } = trace.metadata.trace!;
+  return { file, ...(digest ? { digest } : {}), module, main, startedAt, seconds, samples };
+}
+
+// Stores written before traces had a digest are matched on the run itself
+function sameRun(a: TraceSource, b: TraceSource): boolean {
+  if (a.digest && b.digest) return a.digest === b.digest;
+  return a.module === b.module && a.main === b.main && a.startedAt === b.startedAt && a.samples === b.samples;
+}
+
+/** Merge traces into the project's runtime edges; graph is the project's graph, to match symbols against */
+export function importTraces(projectRoot: string, files: string[], graph: DirectedGraph, options: TraceImportOptions = {}): TraceImport {
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const previous = options.replace ? null : readRuntimeEdges(projectRoot);
+  const edges = new Map<string, RuntimeEdge>((previous?.edges ?? []).map(e => [`${e.source}\0${e.target}`, e]));
+  const traces: TraceSource[] = [];
+  const unmatched: UnmatchedEdge[] = [];
+  const dynamic = new Map<string, RuntimeEdge>();
+  const skipped: string[] = [];
+  let recorded = 0;
+  let confirmed = 0;
+
+  for (const file of files) {
+    const content = readFileSync(file, 'utf-8');
+    const trace = parseTrace(content, file);
+    const source = traceSource(file, trace, createHash('sha256').update(content).digest('hex'));
+    if ([...(previous?.traces ?? []), ...traces].some(t => sameRun(t, source))) {
+      skipped.push(file);
+      continue;
+    }
+    traces.push(source);
+    recorded += trace.edges.length;
+    for (const edge of mapTraceEdges(trace, index, graph, unmatched)) {
+      const { source, target } = edge;
+      const key = `${source}\0${target}`;
+      let stored = edges.get(key);
+      if (!stored) {
+        stored = { ...edge, count: 0 };
+        edges.set(key, stored);
+      }
+      stored.count += edge.count;
+      const existing = graph.hasEdge(source, target) ? graph.getEdgeAttribute(graph.edge(source, target)!, 'kind') : null;
+      if (existing && existing !== 'runtime') confirmed++;
+      else dynamic.set(key, stored);
+    }
+  }
+
+  const store: RuntimeEdgeStore = {
+    traces: [...(previous?.traces ?? []), ...traces],
+    edges: [...edges.values()].sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target)),
+  };
+  const out = join(projectRoot, RUNTIME_EDGES_FILE);
+  mkdirSync(dirname(out), { recursive: true });
+  writeFileSync(out, JSON.stringify(store, null, 2) + '\n');
+  return { traces, recorded, confirmed, dynamic: [...dynamic.values()], unmatched, stored: store.edges.length, skipped };
+}
+
+/**
+ * Add caller → callee edges (kind 'runtime') from imported traces to a
+ * built graph. Runs only for projects with Go files and a runtime edge store;
+ * buildGraph calls it only with runtime edges switched on.
+ */
+export function detectRuntimeEdges(files: ParsedFile[], projectRoot: string, graph: DirectedGraph): number | null {
+  if (!files.some(f => f.filePath.endsWith('.go'))) return null;
+  const store = readRuntimeEdges(projectRoot);
+  if (!store) return null;
+  let added = 0;
+  for (const edge of store.edges) {
+    if (!graph.hasNode(edge.source) || !graph.hasNode(edge.target) || graph.hasEdge(edge.source, edge.target)) continue;
+    graph.addEdge(edge.source, edge.target, { kind: 'runtime', filePath: edge.filePath, line: edge.line, count: edge.count, via: edge.via });
+    added++;
+  }
+  return added;
+}
+
+export interface StaticEdge {
+  source: string;
+  target: string;
+  filePath: string;
+  line: number;
+}
+
+export interface PackageCoverage {
+  /** Project-relative package directory */
+  package: string;
+  /** Its cross-package calls in the static graph, and how many the traces saw */
+  calls: number;
+  exercised: number;
+  /** Whether any of its functions ran in a trace */
+  seen: boolean;
+}
+
+export interface TraceComparison {
+  traces: TraceSource[];
+  /** Cross-package calls between the project's Go functions in the static graph */
+  staticCalls: number;
+  exercised: number;
+  /** Static calls no trace saw: candidates for dead weight, or paths the traced runs didn't take */
+  unexercised: StaticEdge[];
+  /** Calls the traces saw that the static graph doesn't have */
+  missed: RuntimeEdge[];
+  /** By calling package, least exercised first */
+  packages: PackageCoverage[];
+  unmatched: UnmatchedEdge[];
+}
+
+const goPackage = (file: string) => posix.dirname(file);
+const isGoSource = (file: string) => file.endsWith('.go') && !file.endsWith('_test.go');
+
+/**
+ * The static graph against traces: which cross-package calls between the
+ * project's Go functions ran and which didn't, and the calls only the
+ * traces show. Runtime edges already imported into the graph don't count
+ * as static.
+ */
+export function compareWithTraces(projectRoot: string, graph: DirectedGraph, traces: Array<{ file: string; trace: RuntimeTrace }>): TraceComparison {
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const sources: TraceSource[] = [];
+  const unmatched: UnmatchedEdge[] = [];
+  const dynamic = new Map<string, RuntimeEdge>();
+  const ran = new Set<string>();
+
+  for (const { file, trace } of traces) {
+    sources.push(traceSource(file, trace));
+    for (const edge of mapTraceEdges(trace, index, graph, unmatched)) {
+      ran.add(edge.source);
+      ran.add(edge.target);
+      const key = `${edge.source}\0${edge.target}`;
+      const seen = dynamic.get(key);
+      if (seen) seen.count += edge.count;
+      else dynamic.set(key, edge);
+    }
+  }
+
+  const packages = new Map<string, PackageCoverage>();
+  const coverage = (pkg: string) => {
+    let entry = packages.get(pkg);
+    if (!entry) packages.set(pkg, entry = { package: pkg, calls: 0, exercised: 0, seen: false });
+    return entry;
+  };
+  const unexercised: StaticEdge[] = [];
+  let staticCalls = 0;
+  graph.forEachEdge((_edge, attrs, source, target) => {
+    if (attrs.kind !== 'calls') return;
+    const from = graph.getNodeAttribute(source, 'filePath') as string;
+    const to = graph.getNodeAttribute(target, 'filePath') as string;
+    if (!isGoSource(from) || !isGoSource(to) || goPackage(from) === goPackage(to)) return;
+    staticCalls++;
+    const entry = coverage(goPackage(from));
+    entry.calls++;
+    if (dynamic.delete(`${source}\0${target}`)) entry.exercised++;
+    else unexercised.push({ source, target, filePath: attrs.filePath, line: attrs.line });
+  });
+  for (const id of ran) coverage(goPackage(graph.getNodeAttribute(id, 'filePath') as string)).seen = true;
+
+  return {
+    traces: sources,
+    staticCalls,
+    exercised: staticCalls - unexercised.length,
+    unexercised: unexercised.sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target)),
+    // What is left ran without a static call; other static edges (a DI binding, a reference) still connect some
+    missed: [...dynamic.values()].filter(e => !graph.hasEdge(e.source, e.target) || graph.getEdgeAttribute(graph.edge(e.source, e.target)!, 'kind') === 'runtime').sort((a, b) => b.count - a.count || a.source.localeCompare(b.source)),
+    packages: [...packages.values()]
+      .filter(p => p.calls > 0)
+      .sort((a, b) => a.exercised / a.calls - b.exercised / b.calls || b.calls - a.calls || a.package.localeCompare(b.package)),
+    unmatched,
+  };
+}
@@ -0,0 +1,52 @@
+import { existsSync, readFileSync } from 'fs';
+import { join } from 'path';
+
+/**
+ * vendor/modules.txt parser. `go mod vendor` writes one "# module version"
+ * header per module (with "=> replacement" when replaced), "##" annotation
+ * lines (explicit: required directly in go.mod; go: the module's go
+ * version), then the module's vendored packages, one import path per line.
+ */
+
+export interface VendoredModule {
+  path: string;
+  /** null for replacement-only entries ("# example.com/m => ../m") */
+  version: string | null;
+  replacement?: { path: string; version?: string };
+  /** Marked "## explicit": go.mod requires it (Go 1.14+) */
+  explicit: boolean;
+  go?: string;
+  packages: string[];
+  line: number;
+}
+
+export function parseModulesTxt(content: string): VendoredModule[] {
+  const modules: VendoredModule[] = [];
+  let current: VendoredModule | null = null;
+  content.split('\n').forEach((raw, i) => {
+    const line = raw.trim();
+    if (!line) return;
+    if (line.startsWith('## ')) {
+      if (!current) return;
+      for (const annotation of line.slice(3).split(';').map(a => a.trim())) {
+        if (annotation === 'explicit') current.explicit = true;
+        else if (annotation.startsWith('go ')) current.go = annotation.slice(3).trim();
+      }
+      return;
+    }
+    if (line.startsWith('# ')) {
+      const [left, right] = line.slice(2).split('=>').map(part => part.trim().split(/\s+/));
+      current = { path: left[0], version: left[1] ?? null, explicit: false, packages: [], line: i + 1 };
+      if (right?.[0]) current.replacement = { path: right[0], version: right[1] };
+      modules.push(current);
+      return;
+    }
+    if (current && !line.startsWith('#')) current.packages.push(line);
+  });
+  return modules;
+}
+
+export function readModulesTxt(moduleRoot: string): VendoredModule[] | null {
+  const file = join(moduleRoot, 'vendor', 'modules.txt');
+  return existsSync(file) ? parseModulesTxt(readFileSync(file, 'utf-8')) : null;
+}
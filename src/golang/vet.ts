@@ -0,0 +1,154 @@
+import { isAbsolute, relative } from 'path';
+import { runGo } from './toolchain.js';
+
+/**
+ * Run golang.org/x/tools/go/analysis analyzers through `go vet -json`.
+ *
+ * Any analyzer binary built with unitchecker or multichecker works as a
+ * -vettool; bundling several analyzers in one multichecker binary means each
+ * package is type-checked once for all of them.
+ */
+
+export interface GoDiagnostic {
+  analyzer: string;
+  /** Import path of the package the diagnostic was reported in */
+  package: string;
+  /** Project-relative path (absolute if outside the project) */
+  file: string;
+  line: number;
+  column: number;
+  message: string;
+  category?: string;
+}
+
+export interface GoAnalysisOptions {
+  /** Path to an analysis driver binary; defaults to go vet's built-in analyzers */
+  vettool?: string;
+  /** Extra flags passed to the analyzers, e.g. ['-printf.funcs=Logf'] */
+  flags?: string[];
+  /** Package patterns, default ./... */
+  packages?: string[];
+  signal?: AbortSignal;
+}
+
+export interface GoAnalysisResult {
+  diagnostics: GoDiagnostic[];
+  /** Errors reported by analyzers or the build (e.g. a package that doesn't compile) */
+  errors: Array<{ package?: string; analyzer?: string; message: string }>;
+}
+
+export async function runGoAnalyzers(moduleRoot: string, projectRoot: string, options: GoAnalysisOptions = {}): Promise<GoAnalysisResult> {
+  const args = ['vet', '-json'];
+  if (options.vettool) args.push(`-vettool=${options.vettool}`);
+  args.push(...(options.flags ?? []), ...(options.packages ?? ['./...']));
+
+  const { stdout, stderr, exitCode } = await runGo(args, { cwd: moduleRoot, signal: options.signal });
+  const result = parseVetOutput(`${stdout}\n${stderr}`, projectRoot);
+
+  if (exitCode !== 0 && result.diagnostics.length === 0 && result.errors.length === 0) {
+    result.errors.push({ message: stderr.trim() || `go vet exited with code ${exitCode}` });
+  }
+  return result;
+}
+
+/**
+ * Parse `go vet -json` output: "# pkg" comment lines interleaved with JSON
+ * objects of the form { pkg: { analyzer: [diagnostic...] | { error } } }.
+ * Non-JSON lines (build errors) are collected as errors.
+ */
+export function parseVetOutput(output: string, projectRoot: string): GoAnalysisResult {
+  const result: GoAnalysisResult = { diagnostics: [], errors: [] };
+
+  for (const chunk of splitJsonObjects(output)) {
+    if (chunk.kind === 'text') {
+      for (const line of chunk.text.split('\n')) {
+        const trimmed = line.trim();
+        if (trimmed && !trimmed.startsWith('#')) result.errors.push({ message: trimmed });
+      }
+      continue;
+    }
+
+    let parsed: Record<string, Record<string, any>>;
+    try {
+      parsed = JSON.parse(chunk.text);
+    } catch {
+      result.errors.push({ message: chunk.text.trim() });
+      continue;
+    }
+
+    for (const [pkg, analyzers] of Object.entries(parsed)) {
+      for (const [analyzer, value] of Object.entries(analyzers)) {
+        if (!Array.isArray(value)) {
+          if (value?.error) result.errors.push({ package: pkg, analyzer, message: value.error.err ?? String(value.error) });
+          continue;
+        }
+        for (const diag of value) {
+          const position = parsePosition(diag.posn ?? '');
+          result.diagnostics.push({
+            analyzer,
+            package: pkg,
+            file: isAbsolute(position.file) ? toProjectPath(position.file, projectRoot) : position.file,
+            line: position.line,
+            column: position.column,
+            message: diag.message,
+            category: diag.category,
+          });
+        }
+      }
+    }
+  }
+
+  return result;
+}
+
+function toProjectPath(file: string, projectRoot: string): string {
+  const rel = relative(projectRoot, file);
+  return rel.startsWith('..') ? file : rel;
+}
+
+// "path/to/file.go:12:5" — the path itself may contain colons (Windows drives)
+function parsePosition(posn: string): { file: string; line: number; column: number } {
+  const match = /^(.*):(\d+):(\d+)$/.exec(posn) ?? /^(.*):(\d+)$/.exec(posn);
+  if (!match) return { file: posn, line: 0, column: 0 };
+  return { file: match[1], line: parseInt(match[2], 10), column: match[3] ? parseInt(match[3], 10) : 0 };
+}
+
+/** Split output into top-level JSON objects and the text between them */
+function splitJsonObjects(output: string): Array<{ kind: 'json' | 'text'; text: string }> {
+  const chunks: Array<{ kind: 'json' | 'text'; text: string }> = [];
+  let depth = 0;
+  let inString = false;
+  let escaped = false;
+  let start = 0;
+
+  for (let i = 0; i < output.length; i++) {
+    const ch = output[i];
+    if (depth > 0 && inString) {
+      if (escaped) escaped = false;
+      else if (ch === '\\') escaped = true;
+      else if (ch === '"') inString = false;
+      continue;
+    }
+    if (ch === '"' && depth > 0) {
+      inString = true;
+    } else if (ch === '{') {
+      // Only a '{' at the start of a line opens a top-level object
+      if (depth === 0) {
+        if (i > 0 && output[i - 1] !== '\n') continue;
+        if (i > start) chunks.push({ kind: 'text', text: output.slice(start, i) });
+        start = i;
+      }
+      depth++;
+    } else if (ch === '}' && depth > 0) {
+      depth--;
+      if (depth === 0) {
+        chunks.push({ kind: 'json', text: output.slice(start, i + 1) });
+        start = i + 1;
+      }
+    }
+  }
+  if (start < output.length) {
+    chunks.push({ kind: depth > 0 ? 'json' : 'text', text: output.slice(start) });
+  }
+  return chunks;
+}
@@ -0,0 +1,149 @@
+import { describe, it } from 'node:test';
+import assert from 'node:assert';
+import { DirectedGraph } from 'graphology';
+import {
+  topologicalSort,
+  stronglyConnectedComponents,
+  findCycles,
+  cycleChanges,
+  dominators,
+  shortestPath,
+  reachable,
+  neighborhood,
+  communities,
+} from './algorithms.js';
+import { toFileGraph } from './model.js';
+
+function createGraph(edges: Array<[string, string]>): DirectedGraph {
+  const graph = new DirectedGraph();
+  for (const [source, target] of edges) {
+    if (!graph.hasNode(source)) graph.addNode(source);
+    if (!graph.hasNode(target)) graph.addNode(target);
+    graph.mergeEdge(source, target);
+  }
+  return graph;
+}
+
+describe('graph algorithms', () => {
+  it('topologicalSort should place dependents before dependencies', () => {
+    const graph = createGraph([['main', 'service'], ['service', 'model'], ['main', 'config']]);
+    const order = topologicalSort(graph);
+
+    assert.strictEqual(order.length, 4);
+    assert.ok(order.indexOf('main') < order.indexOf('service'));
+    assert.ok(order.indexOf('service') < order.indexOf('model'));
+    assert.ok(order.indexOf('main') < order.indexOf('config'));
+  });
+
+  it('topologicalSort should throw on cycles', () => {
+    const graph = createGraph([['a', 'b'], ['b', 'a']]);
+    assert.throws(() => topologicalSort(graph), /cycle/);
+  });
+
+  it('stronglyConnectedComponents should group cyclic nodes', () => {
+    const graph = createGraph([['a', 'b'], ['b', 'c'], ['c', 'a'], ['c', 'd']]);
+    const components = stronglyConnectedComponents(graph);
+
+    assert.deepStrictEqual(components[0], ['a', 'b', 'c']);
+    assert.deepStrictEqual(components[1], ['d']);
+    assert.deepStrictEqual(findCycles(graph), [['a', 'b', 'c']]);
+  });
+
+  it('cycleChanges should report new and grown cycles but not legacy ones', () => {
+    const before = findCycles(createGraph([['a', 'b'], ['b', 'a'], ['c', 'd'], ['d', 'c'], ['p', 'q'], ['q', 'r'], ['r', 'p']]));
+    const after = findCycles(createGraph([
+      ['a', 'b'], ['b', 'a'],
+      ['c', 'd'], ['d', 'e'], ['e', 'c'],
+      ['p', 'q'], ['q', 'p'],
+      ['x', 'y'], ['y', 'x'],
+    ]));
+    const changes = cycleChanges(before, after);
+
+    assert.strictEqual(changes.length, 2);
+    const grown = changes.find(c => c.kind === 'grown')!;
+    assert.deepStrictEqual(grown.added, ['e']);
+    assert.deepStrictEqual(grown.previous, [['c', 'd']]);
+    const added = changes.find(c => c.kind === 'new')!;
+    assert.deepStrictEqual(added.cycle, ['x', 'y']);
+  });
+
+  it('cycleChanges should treat two cycles merging as growth', () => {
+    const before = findCycles(createGraph([['a', 'b'], ['b', 'a'], ['c', 'd'], ['d', 'c']]));
+    const after = findCycles(createGraph([['a', 'b'], ['b', 'a'], ['c', 'd'], ['d', 'c'], ['b', 'c'], ['d', 'a']]));
+    const [change] = cycleChanges(before, after);
+
+    assert.strictEqual(change.kind, 'grown');
+    assert.deepStrictEqual(change.added, []);
+    assert.strictEqual(change.previous.length, 2);
+  });
+
+  it('dominators should find the immediate dominator of each node', () => {
+    // main → router → {users, orders} → db
+    const graph = createGraph([
+      ['main', 'router'],
+      ['router', 'users'],
+      ['router', 'orders'],
+      ['users', 'db'],
+      ['orders', 'db'],
+    ]);
+    const idom = dominators(graph, 'main');
+
+    assert.strictEqual(idom.get('main'), null);
+    assert.strictEqual(idom.get('router'), 'main');
+    assert.strictEqual(idom.get('users'), 'router');
+    assert.strictEqual(idom.get('db'), 'router');
+  });
+
+  it('shortestPath should return the node sequence or null', () => {
+    const graph = createGraph([['a', 'b'], ['b', 'c'], ['a', 'c'], ['c', 'd']]);
+
+    assert.deepStrictEqual(shortestPath(graph, 'a', 'd'), ['a', 'c', 'd']);
+    assert.strictEqual(shortestPath(graph, 'd', 'a'), null);
+    assert.deepStrictEqual(shortestPath(graph, 'd', 'a', 'in'), ['d', 'c', 'a']);
+  });
+
+  it('reachable should collect transitive dependents with direction "in"', () => {
+    const graph = createGraph([['a', 'b'], ['b', 'c'], ['x', 'c']]);
+
+    assert.deepStrictEqual([...reachable(graph, 'c', 'in')].sort(), ['a', 'b', 'x']);
+    assert.deepStrictEqual([...reachable(graph, 'a')].sort(), ['b', 'c']);
+  });
+
+  it('neighborhood should stop after the given number of hops', () => {
+    const graph = createGraph([['a', 'b'], ['b', 'c'], ['c', 'd'], ['x', 'b']]);
+
+    assert.deepStrictEqual([...neighborhood(graph, 'b', 1)].sort(), [['a', 1], ['b', 0], ['c', 1], ['x', 1]]);
+    assert.deepStrictEqual([...neighborhood(graph, 'a', 2, 'out')], [['a', 0], ['b', 1], ['c', 2]]);
+    assert.deepStrictEqual([...neighborhood(graph, 'c', 2, 'in')], [['c', 0], ['b', 1], ['a', 2], ['x', 2]]);
+  });
+
+  it('communities should separate densely connected groups', () => {
+    const graph = createGraph([
+      ['a', 'b'], ['b', 'c'], ['c', 'a'], ['a', 'd'], ['d', 'b'],
+      ['x', 'y'], ['y', 'z'], ['z', 'x'], ['x', 'w'], ['w', 'y'],
+      ['c', 'x'],
+    ]);
+    const groups = communities(graph);
+
+    assert.strictEqual(new Set(groups.values()).size, 2);
+    assert.ok(['b', 'c', 'd'].every(n => groups.get(n) === groups.get('a')));
+    assert.ok(['y', 'z', 'w'].every(n => groups.get(n) === groups.get('x')));
+    assert.notStrictEqual(groups.get('a'), groups.get('x'));
+  });
+
+  it('toFileGraph should collapse symbols into weighted file edges', () => {
+    const graph = new DirectedGraph();
+    graph.addNode('a.ts::A', { name: 'A', kind: 'class', filePath: 'a.ts', startLine: 1, endLine: 1, exported: true });
+    graph.addNode('a.ts::a2', { name: 'a2', kind: 'function', filePath: 'a.ts', startLine: 2, endLine: 2, exported: true });
+    graph.addNode('b.ts::B', { name: 'B', kind: 'class', filePath: 'b.ts', startLine: 1, endLine: 1, exported: true });
+    graph.mergeEdge('a.ts::A', 'b.ts::B', { kind: 'imports' });
+    graph.mergeEdge('a.ts::a2', 'b.ts::B', { kind: 'calls' });
+    graph.mergeEdge('a.ts::a2', 'a.ts::A', { kind: 'calls' });
+
+    const fileGraph = toFileGraph(graph);
+
+    assert.strictEqual(fileGraph.order, 2);
+    assert.strictEqual(fileGraph.size, 1);
+    assert.strictEqual(fileGraph.getEdgeAttribute('a.ts', 'b.ts', 'weight'), 2);
+  });
+});
@@ -0,0 +1,426 @@
+import type { AbstractGraph } from 'graphology-types';
+
+/**
+ * Graph algorithms over any directed graphology graph.
+ *
+ * Works equally on the symbol graph from buildGraph() and on the file-level
+ * graph from toFileGraph(). Edge direction follows the depwire convention:
+ * A → B means "A depends on B".
+ *
+ * All results are deterministic — ties are broken by node ID order.
+ */
+
+type Graph = AbstractGraph<any, any, any>;
+
+export type Direction = 'out' | 'in' | 'both';
+
+function neighbors(graph: Graph, node: string, direction: Direction): string[] {
+  if (direction === 'out') return graph.outNeighbors(node);
+  if (direction === 'in') return graph.inNeighbors(node);
+  return graph.neighbors(node);
+}
+
+/**
+ * Topological sort (Kahn's algorithm).
+ * Dependents come before their dependencies; reverse the result for build order.
+ * Throws if the graph contains a cycle.
+ */
+export function topologicalSort(graph: Graph): string[] {
+  const inDegree = new Map<string, number>();
+  graph.forEachNode((node) => {
+    inDegree.set(node, graph.inNeighbors(node).filter((n) => n !== node).length);
+  });
+
+  const ready = [...inDegree.entries()].filter(([, d]) => d === 0).map(([n]) => n).sort();
+  const order: string[] = [];
+
+  while (ready.length > 0) {
+    const node = ready.shift()!;
+    order.push(node);
+
+    const released: string[] = [];
+    for (const next of graph.outNeighbors(node)) {
+      if (next === node) continue;
+      const d = inDegree.get(next)! - 1;
+      inDegree.set(next, d);
+      if (d === 0) released.push(next);
+    }
+    if (released.length > 0) {
+      ready.push(...released);
+      ready.sort();
+    }
+  }
+
+  if (order.length !== graph.order) {
+    const remaining = [...inDegree.entries()].filter(([, d]) => d > 0).map(([n]) => n).sort();
+    throw new Error(`Graph contains a cycle involving ${remaining.length} nodes (e.g. ${remaining.slice(0, 3).join(', ')})`);
+  }
+
+  return order;
+}
+
+/**
+ * Strongly connected components (Tarjan's algorithm, iterative).
+ * Returns every component including singletons, each sorted, largest first.
+ */
+export function stronglyConnectedComponents(graph: Graph): string[][] {
+  const index = new Map<string, number>();
+  const lowlink = new Map<string, number>();
+  const onStack = new Set<string>();
+  const stack: string[] = [];
+  const components: string[][] = [];
+  let counter = 0;
+
+  const roots = graph.nodes().sort();
+
+  for (const root of roots) {
+    if (index.has(root)) continue;
+
+    // Explicit call stack: [node, sorted successors, next successor position]
+    const work: Array<[string, string[], number]> = [[root, graph.outNeighbors(root).sort(), 0]];
+    index.set(root, counter);
+    lowlink.set(root, counter);
+    counter++;
+    stack.push(root);
+    onStack.add(root);
+
+    while (work.length > 0) {
+      const frame = work[work.length - 1];
+      const [node, successors] = frame;
+
+      if (frame[2] < successors.length) {
+        const next = successors[frame[2]++];
+        if (!index.has(next)) {
+          index.set(next, counter);
+          lowlink.set(next, counter);
+          counter++;
+          stack.push(next);
+          onStack.add(next);
+          work.push([next, graph.outNeighbors(next).sort(), 0]);
+        } else if (onStack.has(next)) {
+          lowlink.set(node, Math.min(lowlink.get(node)!, index.get(next)!));
+        }
+        continue;
+      }
+
+      work.pop();
+      if (work.length > 0) {
+        const parent = work[work.length - 1][0];
+        lowlink.set(parent, Math.min(lowlink.get(parent)!, lowlink.get(node)!));
+      }
+
+      if (lowlink.get(node) === index.get(node)) {
+        const component: string[] = [];
+        let member: string;
+        do {
+          member = stack.pop()!;
+          onStack.delete(member);
+          component.push(member);
+        } while (member !== node);
+        components.push(component.sort());
+      }
+    }
+  }
+
+  return components.sort((a, b) => b.length - a.length || a[0].localeCompare(b[0]));
+}
+
+/**
+ * Components that form cycles — SCCs with more than one node, plus self-loops.
+ */
+export function findCycles(graph: Graph): string[][] {
+  return stronglyConnectedComponents(graph).filter(
+    (c) => c.length > 1 || graph.hasEdge(c[0], c[0])
+  );
+}
+
+export interface CycleChange {
+  /** The cycle as it is now */
+  cycle: string[];
+  /** new: none of its nodes were in a cycle before; grown: it gained nodes or swallowed another cycle */
+  kind: 'new' | 'grown';
+  /** Nodes that are in this cycle now but were in none of the cycles it came from */
+  added: string[];
+  /** The earlier cycles it overlaps */
+  previous: string[][];
+}
+
+/**
+ * Cycles that are new or larger than before, given the cycles of two versions
+ * of a graph (from findCycles()). A cycle that shrank or stayed the same is
+ * not a change; neither is one whose nodes all come from a single earlier cycle.
+ */
+export function cycleChanges(before: string[][], after: string[][]): CycleChange[] {
+  const cycleOf = new Map<string, number>();
+  before.forEach((cycle, i) => cycle.forEach(node => cycleOf.set(node, i)));
+
+  const changes: CycleChange[] = [];
+  for (const cycle of after) {
+    const overlapping = [...new Set(cycle.filter(n => cycleOf.has(n)).map(n => cycleOf.get(n)!))].sort((a, b) => a - b);
+    const added = cycle.filter(n => !cycleOf.has(n) || !overlapping.includes(cycleOf.get(n)!));
+    if (overlapping.length === 0) {
+      changes.push({ cycle, kind: 'new', added, previous: [] });
+    } else if (added.length > 0 || overlapping.length > 1) {
+      changes.push({ cycle, kind: 'grown', added, previous: overlapping.map(i => before[i]) });
+    }
+  }
+  return changes;
+}
+
+/**
+ * Immediate dominators from a root (Cooper, Harvey & Kennedy).
+ * A node D dominates N if every path from root to N passes through D.
+ * Returns a map of node → immediate dominator; the root maps to null.
+ * Nodes unreachable from the root are omitted.
+ */
+export function dominators(graph: Graph, root: string): Map<string, string | null> {
+  if (!graph.hasNode(root)) {
+    throw new Error(`Node not found: ${root}`);
+  }
+
+  // Reverse postorder over nodes reachable from root
+  const postorder: string[] = [];
+  const seen = new Set<string>([root]);
+  const work: Array<[string, string[], number]> = [[root, graph.outNeighbors(root).sort(), 0]];
+  while (work.length > 0) {
+    const frame = work[work.length - 1];
+    if (frame[2] < frame[1].length) {
+      const next = frame[1][frame[2]++];
+      if (!seen.has(next)) {
+        seen.add(next);
+        work.push([next, graph.outNeighbors(next).sort(), 0]);
+      }
+    } else {
+      postorder.push(frame[0]);
+      work.pop();
+    }
+  }
+
+  const order = new Map<string, number>();
+  postorder.forEach((node, i) => order.set(node, i));
+  const rpo = [...postorder].reverse();
+
+  const idom = new Map<string, string>();
+  idom.set(root, root);
+
+  const intersect = (a: string, b: string): string => {
+    while (a !== b) {
+      while (order.get(a)! < order.get(b)!) a = idom.get(a)!;
+      while (order.get(b)! < order.get(a)!) b = idom.get(b)!;
+    }
+    return a;
+  };
+
+  let changed = true;
+  while (changed) {
+    changed = false;
+    for (const node of rpo) {
+      if (node === root) continue;
+
+      let newIdom: string | null = null;
+      for (const pred of graph.inNeighbors(node)) {
+        if (!idom.has(pred)) continue;
+        newIdom = newIdom === null ? pred : intersect(pred, newIdom);
+      }
+
+      if (newIdom !== null && idom.get(node) !== newIdom) {
+        idom.set(node, newIdom);
+        changed = true;
+      }
+    }
+  }
+
+  const result = new Map<string, string | null>();
+  for (const node of rpo) {
+    result.set(node, node === root ? null : idom.get(node)!);
+  }
+  return result;
+}
+
+/**
+ * Shortest path between two nodes (unweighted BFS).
+ * Returns the node sequence including both endpoints, or null if unreachable.
+ */
+export function shortestPath(
+  graph: Graph,
+  from: string,
+  to: string,
+  direction: Direction = 'out'
+): string[] | null {
+  if (!graph.hasNode(from) || !graph.hasNode(to)) return null;
+  if (from === to) return [from];
+
+  const previous = new Map<string, string>();
+  const visited = new Set<string>([from]);
+  const queue: string[] = [from];
+
+  while (queue.length > 0) {
+    const current = queue.shift()!;
+    for (const next of neighbors(graph, current, direction).sort()) {
+      if (visited.has(next)) continue;
+      visited.add(next);
+      previous.set(next, current);
+
+      if (next === to) {
+        const path = [to];
+        let step = to;
+        while (step !== from) {
+          step = previous.get(step)!;
+          path.push(step);
+        }
+        return path.reverse();
+      }
+      queue.push(next);
+    }
+  }
+
+  return null;
+}
+
+/**
+ * All nodes reachable from a start node (excluding the start node itself).
+ * Use direction 'in' to collect everything that transitively depends on it.
+ */
+export function reachable(graph: Graph, from: string, direction: Direction = 'out'): Set<string> {
+  const visited = new Set<string>();
+  if (!graph.hasNode(from)) return visited;
+
+  const queue: string[] = [from];
+  while (queue.length > 0) {
+    const current = queue.shift()!;
+    for (const next of neighbors(graph, current, direction)) {
+      if (next === from || visited.has(next)) continue;
+      visited.add(next);
+      queue.push(next);
+    }
+  }
+
+  return visited;
+}
+
+/**
+ * Nodes within `hops` edges of a start node, mapped to their distance
+ * (the start node itself is at 0). Direction 'in' walks towards dependents.
+ */
+export function neighborhood(graph: Graph, from: string, hops: number, direction: Direction = 'both'): Map<string, number> {
+  const distance = new Map<string, number>();
+  if (!graph.hasNode(from)) return distance;
+
+  distance.set(from, 0);
+  let frontier = [from];
+  for (let depth = 1; depth <= hops && frontier.length > 0; depth++) {
+    const next: string[] = [];
+    for (const current of frontier) {
+      for (const neighbor of [...neighbors(graph, current, direction)].sort()) {
+        if (distance.has(neighbor)) continue;
+        distance.set(neighbor, depth);
+        next.push(neighbor);
+      }
+    }
+    frontier = next;
+  }
+
+  return distance;
+}
+
+/**
+ * Community detection (Louvain modularity optimization) on the undirected,
+ * weighted view of the graph. Returns node → community index; communities
+ * are numbered largest first. `weight` names a numeric edge attribute
+ * (edges without it count as 1); higher `resolution` yields smaller groups.
+ */
+export function communities(
+  graph: Graph,
+  options: { weight?: string; resolution?: number } = {}
+): Map<string, number> {
+  const resolution = options.resolution ?? 1;
+  const names = graph.nodes().sort();
+  const indexOf = new Map(names.map((name, i) => [name, i]));
+
+  // Symmetric adjacency; self-loops are stored doubled so degrees are row sums
+  let adjacency: Array<Map<number, number>> = names.map(() => new Map());
+  graph.forEachEdge((_edge, attrs, source, target) => {
+    const w = options.weight && typeof attrs[options.weight] === 'number' ? attrs[options.weight] : 1;
+    const i = indexOf.get(source)!;
+    const j = indexOf.get(target)!;
+    if (i === j) {
+      adjacency[i].set(i, (adjacency[i].get(i) ?? 0) + 2 * w);
+    } else {
+      adjacency[i].set(j, (adjacency[i].get(j) ?? 0) + w);
+      adjacency[j].set(i, (adjacency[j].get(i) ?? 0) + w);
+    }
+  });
+
+  // membership[original node] = node of the current aggregated graph
+  let membership = names.map((_, i) => i);
+
+  for (;;) {
+    const n = adjacency.length;
+    const degree = adjacency.map(row => [...row.values()].reduce((a, b) => a + b, 0));
+    const twoM = degree.reduce((a, b) => a + b, 0);
+    if (twoM === 0) break;
+
+    const community = adjacency.map((_, i) => i);
+    const total = [...degree];
+    let moved = false;
+    let improved = true;
+    while (improved) {
+      improved = false;
+      for (let i = 0; i < n; i++) {
+        const current = community[i];
+        const links = new Map<number, number>();
+        for (const [j, w] of adjacency[i]) {
+          if (j !== i) links.set(community[j], (links.get(community[j]) ?? 0) + w);
+        }
+        total[current] -= degree[i];
+
+        const gain = (c: number) => (links.get(c) ?? 0) - resolution * total[c] * degree[i] / twoM;
+        let best = current;
+        let bestGain = gain(current);
+        for (const c of [...links.keys()].sort((a, b) => a - b)) {
+          const g = gain(c);
+          if (g > bestGain + 1e-12) {
+            best = c;
+            bestGain = g;
+          }
+        }
+
+        total[best] += degree[i];
+        if (best !== current) {
+          community[i] = best;
+          improved = true;
+          moved = true;
+        }
+      }
+    }
+    if (!moved) break;
+
+    // Aggregate each community into a single node and repeat
+    const renumber = new Map<number, number>();
+    for (const c of community) {
+      if (!renumber.has(c)) renumber.set(c, renumber.size);
+    }
+    const next: Array<Map<number, number>> = [...renumber.keys()].map(() => new Map());
+    for (let i = 0; i < n; i++) {
+      const ci = renumber.get(community[i])!;
+      for (const [j, w] of adjacency[i]) {
+        const cj = renumber.get(community[j])!;
+        next[ci].set(cj, (next[ci].get(cj) ?? 0) + w);
+      }
+    }
+    membership = membership.map(m => renumber.get(community[m])!);
+    adjacency = next;
+  }
+
+  // Number communities by size, then by their first member's name
+  const members = new Map<number, string[]>();
+  names.forEach((name, i) => {
+    const list = members.get(membership[i]) ?? [];
+    list.push(name);
+    members.set(membership[i], list);
+  });
+  const ordered = [...members.values()].sort((a, b) => b.length - a.length || a[0].localeCompare(b[0]));
+  const result = new Map<string, number>();
+  ordered.forEach((group, index) => group.forEach(name => result.set(name, index)));
+  return result;
+}
@@ -0,0 +1,57 @@
+import { DirectedGraph } from 'graphology';
+import { nodes, edges, toFileGraph, type GraphEdge } from './model.js';
+import { stableNodeId } from './stable-id.js';
+import type { SymbolNode } from '../parser/types.js';
+
+export interface GraphDelta {
+  addedNodes: SymbolNode[];
+  removedNodes: SymbolNode[];
+  addedEdges: GraphEdge[];
+  removedEdges: GraphEdge[];
+  /** File-level dependencies that appeared or disappeared */
+  addedFileEdges: Array<{ source: string; target: string }>;
+  removedFileEdges: Array<{ source: string; target: string }>;
+}
+
+/**
+ * Compare two builds of the symbol graph.
+ * Nodes are matched by stable ID, so moving a symbol within its file
+ * (line changes) is not reported as a change.
+ */
+export function diffGraphs(before: DirectedGraph, after: DirectedGraph): GraphDelta {
+  const keyOf = (node: SymbolNode) => node.stableId ?? stableNodeId(node);
+
+  const beforeNodes = new Map([...nodes(before)].map(n => [keyOf(n), n]));
+  const afterNodes = new Map([...nodes(after)].map(n => [keyOf(n), n]));
+
+  const edgeKey = (graph: DirectedGraph, edge: GraphEdge) => {
+    const source = graph.getNodeAttribute(edge.source, 'stableId') ?? edge.source;
+    const target = graph.getNodeAttribute(edge.target, 'stableId') ?? edge.target;
+    return `${source}|${target}|${edge.kind}`;
+  };
+  const beforeEdges = new Map([...edges(before)].map(e => [edgeKey(before, e), e]));
+  const afterEdges = new Map([...edges(after)].map(e => [edgeKey(after, e), e]));
+
+  const fileEdgeKeys = (graph: DirectedGraph) => {
+    const keys = new Set<string>();
+    toFileGraph(graph).forEachEdge((_edge, _attrs, source, target) => {
+      keys.add(`${source}|${target}`);
+    });
+    return keys;
+  };
+  const beforeFileEdges = fileEdgeKeys(before);
+  const afterFileEdges = fileEdgeKeys(after);
+  const toPair = (key: string) => {
+    const [source, target] = key.split('|');
+    return { source, target };
+  };
+
+  return {
+    addedNodes: [...afterNodes].filter(([key]) => !beforeNodes.has(key)).map(([, n]) => n),
+    removedNodes: [...beforeNodes].filter(([key]) => !afterNodes.has(key)).map(([, n]) => n),
+    addedEdges: [...afterEdges].filter(([key]) => !beforeEdges.has(key)).map(([, e]) => e),
+    removedEdges: [...beforeEdges].filter(([key]) => !afterEdges.has(key)).map(([, e]) => e),
+    addedFileEdges: [...afterFileEdges].filter(key => !beforeFileEdges.has(key)).sort().map(toPair),
+    removedFileEdges: [...beforeFileEdges].filter(key => !afterFileEdges.has(key)).sort().map(toPair),
+  };
+}
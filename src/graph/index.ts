@@ -1,8 +1,26 @@
 import { DirectedGraph } from 'graphology';
 import { ParsedFile, SymbolNode } from '../parser/types.js';
 import { detectCrossLanguageEdges } from '../cross-language/index.js';
+import { stableNodeId } from './stable-id.js';
+import { detectDiEdges } from '../golang/di.js';
+import { detectSharedStateEdges } from '../golang/topology.js';
+import { annotateObservability } from '../golang/observability.js';
+import { detectRuntimeEdges } from '../golang/trace.js';
+import { parseGoSources } from '../golang/source.js';
+import { logger } from '../utils/log.js';
 
-export function buildGraph(parsedFiles: ParsedFile[], projectRoot?: string): DirectedGraph {
+const log = logger('graph');
+
+export interface BuildGraphOptions {
+  /**
+   * Add the runtime edges of imported traces (depwire trace import). Off by
+   * default, so a local trace store doesn't change what lint, freeze or
+   * dead-code decide.
+   */
+  runtimeEdges?: boolean;
+}
+
+export function buildGraph(parsedFiles: ParsedFile[], projectRoot?: string, options: BuildGraphOptions = {}): DirectedGraph {
   const graph = new DirectedGraph();
   
   // First pass: Add all nodes
@@ -17,6 +35,7 @@ export function buildGraph(parsedFiles: ParsedFile[], projectRoot?: string): Dir
           endLine: symbol.endLine,
           exported: symbol.exported,
           scope: symbol.scope,
+          stableId: stableNodeId(symbol),
         });
       }
     }
@@ -37,6 +56,7 @@ export function buildGraph(parsedFiles: ParsedFile[], projectRoot?: string): Dir
           startLine: 1,
           endLine: 1,
           exported: false,
+          stableId: stableNodeId({ id: edge.source, kind: 'import', filePath }),
         });
       }
       // Also create target __file__ nodes
@@ -50,6 +70,7 @@ export function buildGraph(parsedFiles: ParsedFile[], projectRoot?: string): Dir
           startLine: 1,
           endLine: 1,
           exported: false,
+          stableId: stableNodeId({ id: edge.target, kind: 'import', filePath }),
         });
       }
     }
@@ -65,6 +86,7 @@ export function buildGraph(parsedFiles: ParsedFile[], projectRoot?: string): Dir
           kind: edge.kind,
           filePath: edge.filePath,
           line: edge.line,
+          column: edge.column,
         });
       }
     }
@@ -74,7 +96,34 @@ export function buildGraph(parsedFiles: ParsedFile[], projectRoot?: string): Dir
   if (projectRoot) {
     const result = detectCrossLanguageEdges(parsedFiles, projectRoot, graph);
     if (result.stats.restApiEdges > 0 || result.stats.subprocessEdges > 0) {
-      console.error(`Cross-language edges: ${result.stats.restApiEdges} rest-api, ${result.stats.subprocessEdges} subprocess detected`);
+      log.info(`Cross-language edges: ${result.stats.restApiEdges} rest-api, ${result.stats.subprocessEdges} subprocess detected`, { restApi: result.stats.restApiEdges, subprocess: result.stats.subprocessEdges });
+    }
+
+    // The Go hooks below share one syntax tree per file
+    const goSources = parseGoSources(projectRoot, parsedFiles);
+
+    // Go dependency injection: consumers reach implementations without importing them
+    const di = detectDiEdges(goSources, projectRoot, graph);
+    if (di && di.bindings.length > 0) {
+      log.info(`DI edges: ${di.bindings.length} bindings in ${di.containers.length} containers`, { bindings: di.bindings.length, containers: di.containers.length });
+    }
+
+    // Go global state: functions coupled through package-level vars written after init
+    const globals = detectSharedStateEdges(goSources, projectRoot, graph);
+    if (globals && globals.length > 0) {
+      log.info(`Shared-state edges: ${globals.length} package variables written after init`, { variables: globals.length });
+    }
+
+    // Go runtime traces: calls through reflection, plugins and registries, recorded by depwiretrace
+    const traced = options.runtimeEdges ? detectRuntimeEdges(parsedFiles, projectRoot, graph) : null;
+    if (traced && traced > 0) {
+      log.info(`Runtime edges: ${traced} calls from imported traces`, { edges: traced });
+    }
+
+    // Go observability: which logging/metrics/tracing libraries each declaration uses
+    const observed = annotateObservability(goSources, graph);
+    if (observed > 0) {
+      log.info(`Observability: ${observed} declarations use logging, metrics or tracing libraries`, { declarations: observed });
     }
   }
 
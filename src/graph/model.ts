@@ -0,0 +1,190 @@
+import { DirectedGraph } from 'graphology';
+import { posix } from 'path';
+import type { SymbolKind, EdgeKind, SymbolNode } from '../parser/types.js';
+
+/**
+ * Typed view of the graph built by buildGraph().
+ *
+ * The graph itself is a plain graphology DirectedGraph — these types describe
+ * the attributes every node and edge carries so consumers don't have to cast.
+ */
+
+export interface NodeAttributes {
+  name: string;
+  kind: SymbolKind;
+  filePath: string;
+  startLine: number;
+  endLine: number;
+  exported: boolean;
+  scope?: string;
+  /** Content-addressable ID — see stableNodeId() */
+  stableId?: string;
+  /** Go: logging, metrics and tracing libraries the declaration uses ("zap", "prometheus") */
+  observability?: string[];
+}
+
+export interface EdgeAttributes {
+  kind: EdgeKind;
+  filePath?: string;
+  line?: number;
+  /** 1-based; absent for references matched line by line in build files */
+  column?: number;
+  crossLanguage?: boolean;
+  edgeType?: string;
+}
+
+export type DepwireGraph = DirectedGraph<NodeAttributes, EdgeAttributes>;
+
+export interface GraphEdge extends EdgeAttributes {
+  source: string;
+  target: string;
+}
+
+/** Where a dependency shows up in source: an import statement or a call site */
+export interface SourcePosition {
+  file: string;
+  line: number;
+  column?: number;
+}
+
+/** Edge attributes of the collapsed file and package graphs */
+export interface WeightedEdgeAttributes {
+  weight: number;
+  /** The first reference that creates the edge */
+  witness?: SourcePosition;
+}
+
+/** Where a symbol edge occurs, falling back to its source symbol's declaration for edges without a line */
+export function edgePosition(attrs: EdgeAttributes, sourceAttrs: NodeAttributes): SourcePosition {
+  if (!attrs.line) return { file: sourceAttrs.filePath, line: sourceAttrs.startLine };
+  return { file: attrs.filePath ?? sourceAttrs.filePath, line: attrs.line, ...(attrs.column ? { column: attrs.column } : {}) };
+}
+
+/** Get a node as a SymbolNode, or null if it doesn't exist */
+export function getNode(graph: DirectedGraph, id: string): SymbolNode | null {
+  if (!graph.hasNode(id)) return null;
+  return toSymbolNode(id, graph.getNodeAttributes(id) as NodeAttributes);
+}
+
+/** Find a node by its content-addressable stable ID, or null if it doesn't exist */
+export function getNodeByStableId(graph: DirectedGraph, stableId: string): SymbolNode | null {
+  const id = graph.findNode((_node, attrs) => attrs.stableId === stableId);
+  return id === undefined ? null : getNode(graph, id);
+}
+
+/** Iterate over every node in the graph */
+export function* nodes(graph: DirectedGraph): IterableIterator<SymbolNode> {
+  for (const { node, attributes } of graph.nodeEntries()) {
+    yield toSymbolNode(node, attributes as NodeAttributes);
+  }
+}
+
+/** Iterate over every edge in the graph */
+export function* edges(graph: DirectedGraph): IterableIterator<GraphEdge> {
+  for (const { source, target, attributes } of graph.edgeEntries()) {
+    yield { ...(attributes as EdgeAttributes), source, target };
+  }
+}
+
+/** Iterate over the edges leaving a node (what it depends on) */
+export function* outEdges(graph: DirectedGraph, id: string): IterableIterator<GraphEdge> {
+  if (!graph.hasNode(id)) return;
+  for (const { source, target, attributes } of graph.outEdgeEntries(id)) {
+    yield { ...(attributes as EdgeAttributes), source, target };
+  }
+}
+
+/** Iterate over the edges entering a node (what depends on it) */
+export function* inEdges(graph: DirectedGraph, id: string): IterableIterator<GraphEdge> {
+  if (!graph.hasNode(id)) return;
+  for (const { source, target, attributes } of graph.inEdgeEntries(id)) {
+    yield { ...(attributes as EdgeAttributes), source, target };
+  }
+}
+
+/**
+ * Collapse the symbol graph into a file-level graph.
+ * Nodes are file paths; an edge A → B exists if any symbol in A references a symbol in B.
+ * The edge attribute `weight` counts the underlying symbol edges, `witness` locates the first.
+ */
+export function toFileGraph(graph: DirectedGraph): DirectedGraph<{ filePath: string }, WeightedEdgeAttributes> {
+  const fileGraph = new DirectedGraph<{ filePath: string }, WeightedEdgeAttributes>();
+
+  graph.forEachNode((_node, attrs) => {
+    if (!fileGraph.hasNode(attrs.filePath)) {
+      fileGraph.addNode(attrs.filePath, { filePath: attrs.filePath });
+    }
+  });
+
+  graph.forEachEdge((_edge, attrs, source, target) => {
+    const sourceAttrs = graph.getNodeAttributes(source);
+    const sourceFile = sourceAttrs.filePath;
+    const targetFile = graph.getNodeAttributes(target).filePath;
+    if (sourceFile === targetFile) return;
+
+    if (fileGraph.hasEdge(sourceFile, targetFile)) {
+      fileGraph.updateEdgeAttribute(sourceFile, targetFile, 'weight', (w) => (w || 0) + 1);
+    } else {
+      fileGraph.addEdge(sourceFile, targetFile, { weight: 1, witness: edgePosition(attrs, sourceAttrs) });
+    }
+  });
+
+  return fileGraph;
+}
+
+/** The package a file belongs to — its directory ('.' for the project root) */
+export function packageOf(filePath: string): string {
+  return posix.dirname(filePath.replace(/\\/g, '/'));
+}
+
+/**
+ * Collapse the symbol graph into a package (directory) level graph.
+ * Edge `weight` counts the underlying symbol edges, `witness` locates the
+ * first; intra-package edges are dropped.
+ */
+export function toPackageGraph(graph: DirectedGraph): DirectedGraph<{ files: number; symbols: number }, WeightedEdgeAttributes> {
+  const packageGraph = new DirectedGraph<{ files: number; symbols: number }, WeightedEdgeAttributes>();
+  const filesByPackage = new Map<string, Set<string>>();
+
+  graph.forEachNode((_node, attrs) => {
+    const pkg = packageOf(attrs.filePath);
+    if (!packageGraph.hasNode(pkg)) {
+      packageGraph.addNode(pkg, { files: 0, symbols: 0 });
+      filesByPackage.set(pkg, new Set());
+    }
+    filesByPackage.get(pkg)!.add(attrs.filePath);
+    if (attrs.name !== '__file__') {
+      packageGraph.updateNodeAttribute(pkg, 'symbols', (n) => (n ?? 0) + 1);
+    }
+  });
+  for (const [pkg, files] of filesByPackage) {
+    packageGraph.setNodeAttribute(pkg, 'files', files.size);
+  }
+
+  graph.forEachEdge((_edge, attrs, _source, _target, sourceAttrs, targetAttrs) => {
+    const from = packageOf(sourceAttrs.filePath);
+    const to = packageOf(targetAttrs.filePath);
+    if (from === to) return;
+    if (packageGraph.hasEdge(from, to)) {
+      packageGraph.updateEdgeAttribute(from, to, 'weight', (w) => (w ?? 0) + 1);
+    } else {
+      packageGraph.addEdge(from, to, { weight: 1, witness: edgePosition(attrs, sourceAttrs) });
+    }
+  });
+
+  return packageGraph;
+}
+
+function toSymbolNode(id: string, attrs: NodeAttributes): SymbolNode {
+  return {
+    id,
+    name: attrs.name,
+    kind: attrs.kind,
+    filePath: attrs.filePath,
+    startLine: attrs.startLine,
+    endLine: attrs.endLine,
+    exported: attrs.exported,
+    scope: attrs.scope,
+    stableId: attrs.stableId,
+  };
+}
@@ -1,5 +1,6 @@
 import { DirectedGraph } from 'graphology';
 import { ProjectGraph, SymbolNode, SymbolEdge } from '../parser/types.js';
+import { stableNodeId, stableEdgeId } from './stable-id.js';
 
 export function exportToJSON(graph: DirectedGraph, projectRoot: string): ProjectGraph {
   const nodes: SymbolNode[] = [];
@@ -17,12 +18,15 @@ export function exportToJSON(graph: DirectedGraph, projectRoot: string): Project
       endLine: attrs.endLine,
       exported: attrs.exported,
       scope: attrs.scope,
+      stableId: attrs.stableId ?? stableNodeId({ id: nodeId, kind: attrs.kind, filePath: attrs.filePath }),
+      ...(attrs.observability ? { observability: attrs.observability } : {}),
     });
     
     fileSet.add(attrs.filePath);
   });
   
   // Extract all edges
+  const stableIds = new Map(nodes.map(n => [n.id, n.stableId!]));
   graph.forEachEdge((edge, attrs, source, target) => {
     edges.push({
       source,
@@ -30,6 +34,8 @@ export function exportToJSON(graph: DirectedGraph, projectRoot: string): Project
       kind: attrs.kind,
       filePath: attrs.filePath,
       line: attrs.line,
+      ...(attrs.column ? { column: attrs.column } : {}),
+      stableId: stableEdgeId(stableIds.get(source)!, stableIds.get(target)!, attrs.kind),
     });
   });
   
@@ -60,6 +66,8 @@ export function importFromJSON(json: ProjectGraph): DirectedGraph {
       endLine: node.endLine,
       exported: node.exported,
       scope: node.scope,
+      stableId: node.stableId ?? stableNodeId(node),
+      ...(node.observability ? { observability: node.observability } : {}),
     });
   }
   
@@ -70,6 +78,7 @@ export function importFromJSON(json: ProjectGraph): DirectedGraph {
         kind: edge.kind,
         filePath: edge.filePath,
         line: edge.line,
+        column: edge.column,
       });
     }
   }
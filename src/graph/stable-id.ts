@@ -0,0 +1,40 @@
+import { createHash } from 'crypto';
+import type { SymbolKind, EdgeKind } from '../parser/types.js';
+
+/**
+ * Content-addressable IDs for graph nodes and edges.
+ *
+ * Graph node IDs ("src/a.ts::Foo.bar") read well but depend on how each parser
+ * spells them. The stable ID is a hash of kind + path + signature only, so it is
+ * identical on every machine and across runs, and survives line-number churn —
+ * use it to correlate nodes in diffs, baselines and external databases.
+ */
+
+const STABLE_ID_LENGTH = 32; // hex chars = 128 bits
+
+function hash(parts: string[]): string {
+  return createHash('sha256').update(parts.join('\0')).digest('hex').slice(0, STABLE_ID_LENGTH);
+}
+
+function normalizePath(filePath: string): string {
+  return filePath.replace(/\\/g, '/').replace(/^\.\//, '');
+}
+
+/**
+ * The part of a node ID that identifies the symbol within its file
+ * ("Foo.bar" for "src/a.ts::Foo.bar").
+ */
+function signatureOf(id: string, filePath: string): string {
+  const prefix = `${filePath}::`;
+  if (id.startsWith(prefix)) return id.slice(prefix.length);
+  const separator = id.indexOf('::');
+  return separator >= 0 ? id.slice(separator + 2) : id;
+}
+
+export function stableNodeId(node: { id: string; kind: SymbolKind | string; filePath: string }): string {
+  return hash([node.kind, normalizePath(node.filePath), signatureOf(node.id, node.filePath)]);
+}
+
+export function stableEdgeId(sourceStableId: string, targetStableId: string, kind: EdgeKind | string): string {
+  return hash([kind, sourceStableId, targetStableId]);
+}
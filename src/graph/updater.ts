@@ -2,6 +2,10 @@ import { DirectedGraph } from 'graphology';
 import { join } from 'path';
 import { parseTypeScriptFile } from '../parser/typescript.js';
 import type { ParsedFile } from '../parser/types.js';
+import { stableNodeId } from './stable-id.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('graph');
 
 export function removeFileFromGraph(graph: DirectedGraph, filePath: string): void {
   // Find all nodes where the file path matches
@@ -37,6 +41,7 @@ export function addFileToGraph(graph: DirectedGraph, parsedFile: ParsedFile): vo
         endLine: symbol.location.endLine,
         exported: symbol.exported,
         scope: symbol.scope,
+        stableId: stableNodeId({ id: nodeId, kind: symbol.kind, filePath: parsedFile.filePath }),
       });
     } catch (error) {
       // Node might already exist, skip
@@ -74,7 +79,7 @@ export async function updateFileInGraph(
     // Add new version
     addFileToGraph(graph, parsedFile);
   } catch (error) {
-    console.error(`Failed to parse file ${relativeFilePath}:`, error);
+    log.warn(`Failed to parse file ${relativeFilePath}`, { file: relativeFilePath, error: error });
     // Don't re-add if parsing failed
   }
 }
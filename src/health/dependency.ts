@@ -0,0 +1,69 @@
+import type { DirectedGraph } from 'graphology';
+import { calculateCircularDepsScore, calculateCouplingScore, calculateDepthScore, scoreToGrade } from './metrics.js';
+import { graphMetrics } from '../temporal/history.js';
+
+/**
+ * Dependency health: one 0-100 score for how a project depends — on itself
+ * (cycles, coupling, depth) and on the outside world (direct modules) —
+ * with weights a project can tune to what it cares about.
+ */
+
+export type DependencyHealthComponent = 'cycles' | 'coupling' | 'depth' | 'modules';
+
+export const DEFAULT_DEPENDENCY_HEALTH_WEIGHTS: Record<DependencyHealthComponent, number> = {
+  cycles: 0.35,
+  coupling: 0.25,
+  depth: 0.2,
+  modules: 0.2,
+};
+
+export interface DependencyHealth {
+  score: number;
+  grade: string;
+  components: Array<{ name: DependencyHealthComponent; score: number; weight: number }>;
+}
+
+export interface DependencyHealthOptions {
+  /** Relative weights; missing components keep their default, 0 drops one. Normalized to sum to 1 */
+  weights?: Partial<Record<DependencyHealthComponent, number>>;
+  /** Direct modules allowed before the modules score drops (default 20, or the lint budget) */
+  moduleAllowance?: number;
+}
+
+export function parseHealthWeights(value: unknown, source: string): Partial<Record<DependencyHealthComponent, number>> {
+  if (typeof value !== 'object' || value === null || Array.isArray(value)) {
+    throw new Error(`${source}: health weights must map components to numbers`);
+  }
+  const weights: Partial<Record<DependencyHealthComponent, number>> = {};
+  for (const [name, weight] of Object.entries(value)) {
+    if (!(name in DEFAULT_DEPENDENCY_HEALTH_WEIGHTS)) {
+      throw new Error(`${source}: unknown health component "${name}" (expected ${Object.keys(DEFAULT_DEPENDENCY_HEALTH_WEIGHTS).join(', ')})`);
+    }
+    if (typeof weight !== 'number' || !Number.isFinite(weight) || weight < 0) {
+      throw new Error(`${source}: weight for ${name} must be a non-negative number`);
+    }
+    weights[name as DependencyHealthComponent] = weight;
+  }
+  return weights;
+}
+
+export function dependencyHealth(graph: DirectedGraph, projectRoot: string, options: DependencyHealthOptions = {}): DependencyHealth {
+  const weights = { ...DEFAULT_DEPENDENCY_HEALTH_WEIGHTS, ...options.weights };
+  const total = Object.values(weights).reduce((sum, w) => sum + w, 0);
+  if (total === 0) throw new Error('At least one health weight must be above zero');
+
+  // Full marks up to the allowance, then proportionally less: twice the allowance scores 50
+  const allowance = options.moduleAllowance ?? 20;
+  const direct = graphMetrics(graph, projectRoot).directModules;
+  const scores: Record<DependencyHealthComponent, number> = {
+    cycles: calculateCircularDepsScore(graph).score,
+    coupling: calculateCouplingScore(graph).score,
+    depth: calculateDepthScore(graph).score,
+    modules: Math.round(100 * Math.min(1, allowance / Math.max(direct, 1))),
+  };
+
+  const components = (Object.keys(scores) as DependencyHealthComponent[])
+    .map(name => ({ name, score: scores[name], weight: weights[name] / total }));
+  const score = Math.round(components.reduce((sum, c) => sum + c.score * c.weight, 0));
+  return { score, grade: scoreToGrade(score), components };
+}
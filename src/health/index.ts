@@ -9,8 +9,9 @@ import {
   calculateDepthScore,
   scoreToGrade
 } from './metrics.js';
-import { readFileSync, writeFileSync, existsSync, mkdirSync } from 'fs';
+import { readFileSync, existsSync, mkdirSync } from 'fs';
 import { join, dirname, resolve } from 'path';
+import { writeFileAtomic } from '../utils/files.js';
 
 /**
  * Calculate the overall health score for a project
@@ -190,7 +191,7 @@ function saveHealthHistory(projectRoot: string, report: HealthReport): void {
   mkdirSync(dirname(historyFile), { recursive: true });
   
   if (!historyFile.startsWith(resolvedRoot)) return; // resolve() containment
-  writeFileSync(historyFile, JSON.stringify(history, null, 2), 'utf-8');
+  writeFileAtomic(historyFile, JSON.stringify(history, null, 2));
 }
 
 /**
@@ -0,0 +1,14 @@
+/**
+ * Changed file paths from a unified diff (git diff, patch files).
+ * Both sides are collected so renames and deletions count as changes.
+ */
+export function parseUnifiedDiff(diff: string): string[] {
+  const files = new Set<string>();
+  for (const line of diff.split('\n')) {
+    const match = /^(?:\+\+\+|---) (?:[ab]\/)?(.+?)(?:\t.*)?$/.exec(line);
+    if (match && match[1] !== '/dev/null') {
+      files.add(match[1].trim());
+    }
+  }
+  return [...files].sort();
+}
@@ -0,0 +1,68 @@
+import chalk from 'chalk';
+import type { AffectedItem, ChangeImpactResult } from './types.js';
+import type { TestImpactResult } from './tests.js';
+
+function chainText(item: AffectedItem): string {
+  return item.chain.length > 1 ? chalk.dim(`  via ${item.chain.slice(1).join(' → ')}`) : chalk.dim('  (changed)');
+}
+
+function section(lines: string[], title: string, items: AffectedItem[], limit: number, describe?: (item: any) => string): void {
+  lines.push(chalk.bold(`${title} (${items.length})`));
+  if (items.length === 0) {
+    lines.push(chalk.dim('  none'));
+  }
+  for (const item of items.slice(0, limit)) {
+    const extra = describe ? chalk.dim(` [${describe(item)}]`) : '';
+    lines.push(`  ${item.name}${extra}${chainText(item)}`);
+  }
+  if (items.length > limit) {
+    lines.push(chalk.dim(`  … ${items.length - limit} more (use --limit or --format json)`));
+  }
+  lines.push('');
+}
+
+export function formatImpactReport(result: ChangeImpactResult, limit: number): string {
+  const lines: string[] = [];
+
+  lines.push('');
+  lines.push(chalk.bold('Depwire Change Impact'));
+  lines.push('');
+  lines.push(`Changed files: ${result.changedFiles.length}` +
+    (result.untrackedChanges.length > 0 ? chalk.dim(` (${result.untrackedChanges.length} not in the graph)`) : ''));
+  lines.push('');
+
+  section(lines, 'Services to redeploy', result.services, limit, (s) => `${s.path} · ${s.detectedBy}`);
+  section(lines, 'Binaries to rebuild', result.binaries, limit, (b) => `${b.path} · ${b.detectedBy}`);
+  section(lines, 'Affected packages', result.affectedPackages, limit);
+  section(lines, 'Affected files', result.affectedFiles, limit);
+
+  return lines.join('\n');
+}
+
+export function formatTestImpact(tests: TestImpactResult, limit: number): string {
+  const lines: string[] = [];
+  lines.push(chalk.bold(`Go test packages to run (${tests.goPackages.length})`));
+  if (tests.goPackages.length === 0) {
+    lines.push(chalk.dim('  none — all Go tests can be skipped'));
+  }
+  for (const pkg of tests.goPackages.slice(0, limit)) {
+    lines.push(`  ${pkg.importPath ?? pkg.dir}${chalk.dim(`  ${pkg.reason}`)}`);
+  }
+  if (tests.goPackages.length > limit) {
+    lines.push(chalk.dim(`  … ${tests.goPackages.length - limit} more`));
+  }
+  if (tests.goTestArgs) {
+    lines.push('');
+    lines.push(chalk.dim(`  go test ${tests.goTestArgs.join(' ')}`));
+  }
+  lines.push('');
+
+  if (tests.testFiles.length > 0) {
+    lines.push(chalk.bold(`Other affected test files (${tests.testFiles.length})`));
+    for (const file of tests.testFiles.slice(0, limit)) {
+      lines.push(`  ${file}`);
+    }
+    lines.push('');
+  }
+  return lines.join('\n');
+}
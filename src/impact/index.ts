@@ -0,0 +1,216 @@
+import { existsSync, readFileSync, readdirSync } from 'fs';
+import { join, posix, basename } from 'path';
+import type { DirectedGraph } from 'graphology';
+import { toFileGraph, packageOf } from '../graph/model.js';
+import { scanDirectory } from '../utils/files.js';
+import type { AffectedItem, AffectedTarget, ChangeImpactResult } from './types.js';
+
+export type { AffectedItem, AffectedTarget, ChangeImpactResult } from './types.js';
+
+export interface TargetCandidate {
+  name: string;
+  path: string;
+  detectedBy: string;
+  /** Whether files in subdirectories belong to the target (a Go package main does not) */
+  recursive: boolean;
+}
+
+interface Reach {
+  distance: number;
+  /** Next hop towards the nearest changed file, or null for a changed file */
+  next: string | null;
+  changedDependencies: Set<string>;
+}
+
+/**
+ * Compute everything transitively affected by a set of changed files:
+ * dependent files, their packages, and the binaries and services built from them.
+ * Results are ranked by how many changed files reach them, then by distance.
+ */
+export function analyzeChangeImpact(
+  graph: DirectedGraph,
+  projectRoot: string,
+  changedFiles: string[]
+): ChangeImpactResult {
+  const fileGraph = toFileGraph(graph);
+  const known = changedFiles.filter(f => fileGraph.hasNode(f));
+  const reach = new Map<string, Reach>();
+
+  // One reverse BFS per changed file: distances/chains keep the nearest change,
+  // changedDependencies accumulates across all of them
+  for (const changed of known) {
+    const visited = new Set<string>([changed]);
+    const queue: Array<{ file: string; distance: number; next: string | null }> = [{ file: changed, distance: 0, next: null }];
+
+    while (queue.length > 0) {
+      const { file, distance, next } = queue.shift()!;
+      const entry = reach.get(file);
+      if (!entry) {
+        reach.set(file, { distance, next, changedDependencies: new Set([changed]) });
+      } else {
+        entry.changedDependencies.add(changed);
+        if (distance < entry.distance) {
+          entry.distance = distance;
+          entry.next = next;
+        }
+      }
+
+      for (const dependent of fileGraph.inNeighbors(file)) {
+        if (!visited.has(dependent)) {
+          visited.add(dependent);
+          queue.push({ file: dependent, distance: distance + 1, next: file });
+        }
+      }
+    }
+  }
+
+  const chainOf = (file: string): string[] => {
+    const chain = [file];
+    let current = reach.get(file)?.next ?? null;
+    while (current) {
+      chain.push(current);
+      current = reach.get(current)!.next;
+    }
+    return chain;
+  };
+
+  const affectedFiles: AffectedItem[] = [...reach].map(([file, r]) => ({
+    name: file,
+    distance: r.distance,
+    changedDependencies: r.changedDependencies.size,
+    chain: chainOf(file),
+  }));
+
+  // A package is affected through its nearest affected file
+  const byPackage = new Map<string, { nearest: string; changed: Set<string> }>();
+  for (const [file, r] of reach) {
+    const pkg = packageOf(file);
+    const entry = byPackage.get(pkg);
+    if (!entry) {
+      byPackage.set(pkg, { nearest: file, changed: new Set(r.changedDependencies) });
+    } else {
+      r.changedDependencies.forEach(c => entry.changed.add(c));
+      if (r.distance < reach.get(entry.nearest)!.distance) entry.nearest = file;
+    }
+  }
+  const affectedPackages: AffectedItem[] = [...byPackage].map(([pkg, entry]) => ({
+    name: pkg,
+    distance: reach.get(entry.nearest)!.distance,
+    changedDependencies: entry.changed.size,
+    chain: chainOf(entry.nearest),
+  }));
+
+  // Binaries and services are affected if any affected file lives under their directory
+  const targetsFor = (targets: TargetCandidate[]): AffectedTarget[] => {
+    const result: AffectedTarget[] = [];
+    for (const { recursive, ...target } of targets) {
+      let nearest: string | null = null;
+      const changed = new Set<string>();
+      for (const [file, r] of reach) {
+        if (recursive ? !isUnder(file, target.path) : packageOf(file) !== target.path) continue;
+        r.changedDependencies.forEach(c => changed.add(c));
+        if (nearest === null || r.distance < reach.get(nearest)!.distance) nearest = file;
+      }
+      if (nearest !== null) {
+        result.push({
+          ...target,
+          distance: reach.get(nearest)!.distance,
+          changedDependencies: changed.size,
+          chain: chainOf(nearest),
+        });
+      }
+    }
+    return rank(result);
+  };
+
+  return {
+    projectRoot,
+    changedFiles: [...changedFiles].sort(),
+    untrackedChanges: changedFiles.filter(f => !fileGraph.hasNode(f)).sort(),
+    affectedFiles: rank(affectedFiles),
+    affectedPackages: rank(affectedPackages),
+    binaries: targetsFor(findBinaries(graph, projectRoot)),
+    services: targetsFor(findServices(projectRoot)),
+  };
+}
+
+function rank<T extends AffectedItem>(items: T[]): T[] {
+  return items.sort((a, b) =>
+    b.changedDependencies - a.changedDependencies ||
+    a.distance - b.distance ||
+    a.name.localeCompare(b.name)
+  );
+}
+
+export function isUnder(file: string, dir: string): boolean {
+  return dir === '.' || file.startsWith(`${dir}/`);
+}
+
+/**
+ * Binaries: Go `package main` directories with a main function, and
+ * package.json "bin" entries that point at files in the graph.
+ */
+export function findBinaries(graph: DirectedGraph, projectRoot: string): TargetCandidate[] {
+  const binaries = new Map<string, TargetCandidate>();
+
+  graph.forEachNode((_node, attrs) => {
+    if (attrs.name !== 'main' || attrs.kind !== 'function' || !attrs.filePath.endsWith('.go')) return;
+    const dir = packageOf(attrs.filePath);
+    if (binaries.has(dir)) return;
+    try {
+      const source = readFileSync(join(projectRoot, attrs.filePath), 'utf-8');
+      if (/^package\s+main\b/m.test(source)) {
+        binaries.set(dir, { name: dir === '.' ? basename(projectRoot) : posix.basename(dir), path: dir, detectedBy: 'package main', recursive: false });
+      }
+    } catch { /* unreadable — skip */ }
+  });
+
+  const pkgPath = join(projectRoot, 'package.json');
+  if (existsSync(pkgPath)) {
+    try {
+      const pkg = JSON.parse(readFileSync(pkgPath, 'utf-8'));
+      const bins: Record<string, string> = typeof pkg.bin === 'string'
+        ? { [pkg.name ?? basename(projectRoot)]: pkg.bin }
+        : pkg.bin ?? {};
+      for (const [name, file] of Object.entries(bins)) {
+        const normalized = posix.normalize(file);
+        // bin usually points at build output; treat the whole package as the binary's sources
+        binaries.set(`bin:${name}`, { name, path: '.', detectedBy: `package.json bin (${normalized})`, recursive: true });
+      }
+    } catch { /* invalid package.json — skip */ }
+  }
+
+  return [...binaries.values()];
+}
+
+/** Services: directories with a Dockerfile or Procfile */
+export function findServices(projectRoot: string): TargetCandidate[] {
+  const services: TargetCandidate[] = [];
+  const dirs = new Set<string>(['.']);
+  for (const file of scanDirectory(projectRoot)) {
+    let dir = packageOf(file);
+    while (dir !== '.' && !dirs.has(dir)) {
+      dirs.add(dir);
+      dir = posix.dirname(dir);
+    }
+  }
+
+  for (const dir of [...dirs].sort()) {
+    let entries: string[];
+    try {
+      entries = readdirSync(join(projectRoot, dir));
+    } catch {
+      continue;
+    }
+    const marker = entries.find(e => e === 'Dockerfile' || e.startsWith('Dockerfile.') || e.endsWith('.Dockerfile') || e === 'Procfile');
+    if (marker) {
+      services.push({
+        name: dir === '.' ? basename(projectRoot) : posix.basename(dir),
+        path: dir,
+        detectedBy: marker,
+        recursive: true,
+      });
+    }
+  }
+  return services;
+}
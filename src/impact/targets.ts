@@ -0,0 +1,98 @@
+import { posix } from 'path';
+import { minimatch } from 'minimatch';
+import type { DirectedGraph } from 'graphology';
+import { analyzeChangeImpact, findBinaries, findServices, isUnder, type TargetCandidate } from './index.js';
+import { packageOf } from '../graph/model.js';
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+
+/**
+ * The build targets a change touches, for monorepo CI deciding what to
+ * build and deploy. A target is affected when its transitive closure
+ * contains a changed file, when a file in its own directory changed
+ * (a Dockerfile, say), or when a change can affect everything: go.mod,
+ * go.sum or go.work of its module, or a file matching an "always" glob.
+ */
+
+export type TargetKind = 'binary' | 'service';
+
+export interface ChangedTarget {
+  kind: TargetKind;
+  name: string;
+  /** Project-relative directory the target is built from */
+  path: string;
+  /** Go import path of a binary's package, when known */
+  importPath: string | null;
+  detectedBy: string;
+  /** Why it is affected, in words */
+  reason: string;
+  /** For dependency changes: files from the target back to a changed file */
+  chain: string[];
+}
+
+export interface ChangedTargetsOptions {
+  kinds?: TargetKind[];
+  /** Changed files matching these globs affect every target */
+  always?: string[];
+}
+
+export interface ChangedTargetsResult {
+  changedFiles: string[];
+  targets: ChangedTarget[];
+}
+
+const MODULE_FILES = new Set(['go.mod', 'go.sum', 'go.work', 'go.work.sum']);
+
+function owns(candidate: TargetCandidate, file: string): boolean {
+  return candidate.recursive ? isUnder(file, candidate.path) : packageOf(file) === candidate.path;
+}
+
+export function changedTargets(
+  graph: DirectedGraph,
+  projectRoot: string,
+  changedFiles: string[],
+  options: ChangedTargetsOptions = {}
+): ChangedTargetsResult {
+  const kinds = options.kinds ?? ['binary', 'service'];
+  const impact = analyzeChangeImpact(graph, projectRoot, changedFiles);
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const candidates: Array<TargetCandidate & { kind: TargetKind }> = [
+    ...(kinds.includes('binary') ? findBinaries(graph, projectRoot).map(c => ({ ...c, kind: 'binary' as const })) : []),
+    ...(kinds.includes('service') ? findServices(projectRoot).map(c => ({ ...c, kind: 'service' as const })) : []),
+  ];
+  const affected = new Map([...impact.binaries, ...impact.services].map(t => [`${t.path}\0${t.detectedBy}`, t]));
+  const always = changedFiles.find(f => (options.always ?? []).some(glob => minimatch(f, glob)));
+  const moduleChanges = changedFiles.filter(f => MODULE_FILES.has(posix.basename(f)));
+
+  const targets: ChangedTarget[] = [];
+  for (const candidate of candidates) {
+    const { recursive, kind, ...rest } = candidate;
+    const target = { ...rest, kind, importPath: kind === 'binary' ? index.importForDir(rest.path) : null };
+    const own = changedFiles.find(f => owns(candidate, f));
+    // go.mod of the target's module, or one inside a service directory
+    const moduleChange = moduleChanges.find(f => {
+      const dir = packageOf(f);
+      return isUnder(rest.path, dir) || rest.path === dir || (recursive && isUnder(dir, rest.path));
+    });
+    const reached = affected.get(`${rest.path}\0${rest.detectedBy}`);
+
+    if (always) {
+      targets.push({ ...target, reason: `${always} matches an always-rebuild pattern`, chain: [] });
+    } else if (moduleChange) {
+      targets.push({ ...target, reason: `${moduleChange} changed`, chain: [] });
+    } else if (reached) {
+      const changed = reached.chain[reached.chain.length - 1];
+      targets.push({
+        ...target,
+        reason: reached.distance === 0 ? `${changed} changed` : `depends on ${changed} (${reached.distance} hops)`,
+        chain: reached.chain,
+      });
+    } else if (own) {
+      targets.push({ ...target, reason: `${own} changed`, chain: [] });
+    }
+  }
+
+  return {
+    changedFiles: [...changedFiles].sort(),
+    targets: targets.sort((a, b) => a.kind.localeCompare(b.kind) || a.path.localeCompare(b.path) || a.name.localeCompare(b.name)),
+  };
+}
@@ -0,0 +1,116 @@
+import { readFileSync } from 'fs';
+import { join, posix, relative } from 'path';
+import { parseGoFile } from '../parser/go.js';
+import { initParser } from '../parser/wasm-init.js';
+import { packageOf } from '../graph/model.js';
+import { scanGoTestFiles } from '../utils/files.js';
+import { findGoModuleRoot } from '../golang/toolchain.js';
+import { readGoMod, importPathOf } from '../golang/modfile.js';
+import type { ChangeImpactResult } from './types.js';
+
+export interface AffectedTestPackage {
+  /** Package directory, relative to the project root */
+  dir: string;
+  /** Go import path, when the module path is known */
+  importPath: string | null;
+  /** Why the package's tests must run */
+  reason: string;
+  /** Dependency chain from the test to a changed file */
+  chain: string[];
+}
+
+export interface TestImpactResult {
+  goPackages: AffectedTestPackage[];
+  /** Non-Go test files (*.test.ts, test_*.py, ...) reached by the change */
+  testFiles: string[];
+  /** Arguments for `go test`, or null when no Go tests are affected */
+  goTestArgs: string[] | null;
+}
+
+const TEST_FILE_PATTERN = /(^|\/)(__tests__\/|tests?\/).+|\.(test|spec)\.[a-z]+$|(^|\/)test_[^/]+\.py$|_test\.py$/;
+
+/**
+ * Reduce a change impact to the tests that exercise changed code.
+ *
+ * A Go test package runs if its own package is affected (in-package tests
+ * exercise it) or if any of its _test.go files imports an affected package
+ * (external test packages and test-only imports). Everything else is safe to skip.
+ */
+export async function analyzeTestImpact(projectRoot: string, impact: ChangeImpactResult): Promise<TestImpactResult> {
+  await initParser();
+  const affectedPackages = new Map(impact.affectedPackages.map(p => [p.name, p]));
+  const changed = new Set(impact.changedFiles);
+
+  const goPackages = new Map<string, AffectedTestPackage>();
+  const testFilesByDir = new Map<string, string[]>();
+  for (const file of scanGoTestFiles(projectRoot)) {
+    const dir = packageOf(file);
+    if (!testFilesByDir.has(dir)) testFilesByDir.set(dir, []);
+    testFilesByDir.get(dir)!.push(file);
+  }
+
+  for (const [dir, testFiles] of testFilesByDir) {
+    // A changed test file always reruns
+    const changedTest = testFiles.find(f => changed.has(f));
+    if (changedTest) {
+      goPackages.set(dir, { dir, importPath: null, reason: `${changedTest} changed`, chain: [changedTest] });
+      continue;
+    }
+
+    const own = affectedPackages.get(dir);
+    if (own) {
+      goPackages.set(dir, { dir, importPath: null, reason: 'package is affected', chain: own.chain });
+      continue;
+    }
+
+    for (const testFile of testFiles) {
+      const imported = testImports(projectRoot, testFile);
+      const hit = imported.find(pkg => affectedPackages.has(pkg));
+      if (hit) {
+        goPackages.set(dir, {
+          dir,
+          importPath: null,
+          reason: `${testFile} imports affected package ${hit}`,
+          chain: [testFile, ...affectedPackages.get(hit)!.chain],
+        });
+        break;
+      }
+    }
+  }
+
+  // Attach import paths from go.mod so the list can be passed straight to `go test`
+  const moduleRoot = findGoModuleRoot(projectRoot);
+  const modulePath = moduleRoot ? readGoMod(moduleRoot)?.module ?? null : null;
+  const projectInModule = moduleRoot ? relative(moduleRoot, projectRoot).split('\\').join('/') : '';
+  for (const pkg of goPackages.values()) {
+    if (modulePath) {
+      const moduleDir = projectInModule ? posix.join(projectInModule, pkg.dir) : pkg.dir;
+      pkg.importPath = importPathOf(modulePath, moduleDir === '.' ? '' : moduleDir);
+    }
+  }
+
+  const sorted = [...goPackages.values()].sort((a, b) => a.dir.localeCompare(b.dir));
+  return {
+    goPackages: sorted,
+    testFiles: impact.affectedFiles.map(f => f.name).filter(f => TEST_FILE_PATTERN.test(f)).sort(),
+    goTestArgs: sorted.length > 0
+      ? sorted.map(p => p.importPath ?? (p.dir === '.' ? '.' : `./${p.dir}`))
+      : null,
+  };
+}
+
+/** Packages (project-relative directories) a Go test file depends on */
+function testImports(projectRoot: string, testFile: string): string[] {
+  try {
+    const source = readFileSync(join(projectRoot, testFile), 'utf-8');
+    const parsed = parseGoFile(testFile, source, projectRoot);
+    const dirs = new Set<string>();
+    for (const edge of parsed.edges) {
+      const targetFile = edge.target.split('::')[0];
+      if (targetFile !== testFile) dirs.add(packageOf(targetFile));
+    }
+    return [...dirs];
+  } catch {
+    return [];
+  }
+}
@@ -0,0 +1,31 @@
+export interface AffectedItem {
+  /** File path, package directory, binary or service name */
+  name: string;
+  /** Dependency hops from the nearest changed file (0 = changed directly) */
+  distance: number;
+  /** How many of the changed files reach this item */
+  changedDependencies: number;
+  /**
+   * Why it is affected: file paths from the item back to a changed file,
+   * each depending on the next — ["cmd/api/main.go", "internal/db/conn.go"]
+   */
+  chain: string[];
+}
+
+export interface AffectedTarget extends AffectedItem {
+  /** Directory the binary or service is built from */
+  path: string;
+  /** How the target was detected, e.g. "package main", "package.json bin", "Dockerfile" */
+  detectedBy: string;
+}
+
+export interface ChangeImpactResult {
+  projectRoot: string;
+  changedFiles: string[];
+  /** Changed files depwire doesn't know (deleted, non-source, excluded) */
+  untrackedChanges: string[];
+  affectedFiles: AffectedItem[];
+  affectedPackages: AffectedItem[];
+  binaries: AffectedTarget[];
+  services: AffectedTarget[];
+}
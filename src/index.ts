@@ -2,7 +2,7 @@
 
 import { Command } from 'commander';
 import { resolve, dirname, join } from 'path';
-import { writeFileSync, readFileSync, existsSync } from 'fs';
+import { readFileSync, existsSync } from 'fs';
 import { fileURLToPath } from 'url';
 import { parseProject } from './parser/index.js';
 import { buildGraph } from './graph/index.js';
@@ -19,12 +19,76 @@ import { calculateHealthScore, getHealthTrend } from './health/index.js';
 import { formatHealthReport } from './health/display.js';
 import { readFileSync as readFileSyncNode, appendFileSync, existsSync as existsSyncNode } from 'fs';
 import { createInterface } from 'readline';
-import { findProjectRoot } from './utils/files.js';
+import { findProjectRoot, writeFileAtomic } from './utils/files.js';
 import { runTemporalAnalysis } from './temporal/index.js';
 import { analyzeDeadCode } from './dead-code/index.js';
+import { dynamicRootLocations } from './golang/dynamic.js';
 import { trackCommand } from './telemetry.js';
 import { whatif } from './commands/whatif.js';
 import { securityCommand } from './commands/security.js';
+import { lintCommand } from './commands/lint.js';
+import { freezeCommand } from './commands/freeze.js';
+import { waiveCommand } from './commands/waive.js';
+import { serveCommand } from './commands/serve.js';
+import { impactCommand } from './commands/impact.js';
+import { targetsCommand } from './commands/targets.js';
+import { verdictAssertCommand } from './commands/verdict.js';
+import { badgeCommand } from './commands/badge.js';
+import { analyzeModCommand, analyzeArchiveCommand } from './commands/analyze.js';
+import { compareModCommand, compareTracesCommand } from './commands/compare.js';
+import { dependentsCommand } from './commands/dependents.js';
+import { pseudoCommand } from './commands/pseudo.js';
+import { toolchainCommand } from './commands/toolchain.js';
+import { doctorCommand } from './commands/doctor.js';
+import { modgraphCommand } from './commands/modgraph.js';
+import { completionCommand, completeCommand } from './commands/completion.js';
+import { vendorVerifyCommand } from './commands/vendor.js';
+import { diCommand } from './commands/di.js';
+import { initsCommand } from './commands/inits.js';
+import { goroutinesCommand } from './commands/goroutines.js';
+import { topologyCommand } from './commands/topology.js';
+import { globalsCommand } from './commands/globals.js';
+import { errorsCommand } from './commands/errors.js';
+import { contextsCommand } from './commands/contexts.js';
+import { panicsCommand } from './commands/panics.js';
+import { observabilityCommand } from './commands/observability.js';
+import { buildConfigsCommand } from './commands/buildconfigs.js';
+import { routesCommand } from './commands/routes.js';
+import { sequenceCommand } from './commands/sequence.js';
+import { binaryCommand } from './commands/binary.js';
+import { buildtimeCommand } from './commands/buildtime.js';
+import { genFixtureCommand } from './commands/gen-fixture.js';
+import { imageCommand } from './commands/image.js';
+import { workloadsCommand } from './commands/workloads.js';
+import { traceImportCommand, traceClearCommand } from './commands/trace.js';
+import { taintCommand } from './commands/taint.js';
+import { unsafeCommand } from './commands/unsafe.js';
+import { capabilitiesCommand } from './commands/capabilities.js';
+import { typosquatCommand } from './commands/typosquat.js';
+import { confusionCommand } from './commands/confusion.js';
+import { scorecardCommand } from './commands/scorecard.js';
+import { tripwireCommand } from './commands/tripwire.js';
+import { attestCreateCommand, attestVerifyCommand } from './commands/attest.js';
+import { graphCommand } from './commands/graph.js';
+import { historyCommand } from './commands/history.js';
+import { depsCommand } from './commands/deps.js';
+import { tuiCommand } from './commands/tui.js';
+import { driftCommand } from './commands/drift.js';
+import { reportCommand } from './commands/report.js';
+import { changelogCommand } from './commands/changelog.js';
+import { siteCommand } from './commands/site.js';
+import { packageDocsCommand } from './commands/package-docs.js';
+import { readingOrderCommand } from './commands/reading-order.js';
+import { apidiffCommand } from './commands/apidiff.js';
+import { apiSurfaceCommand } from './commands/api-surface.js';
+import { simulateCommand } from './commands/simulate.js';
+import { refactorPreviewCommand, refactorCyclesCommand } from './commands/refactor.js';
+import { startLspServer } from './serve/lsp.js';
+import { parseWithProgress, exitIfCancelled, scopeToChangesSince, changeScopeFiles, includeRuntimeEdges, graphOptions } from './commands/load.js';
+import { createFilter, type FilterOptions } from './parser/filter.js';
+import { createSpinner, withInterrupt } from './utils/progress.js';
+import { logger, configureLogging, verbosityLevel } from './utils/log.js';
+import { applyOutputFlags, answerQuietly, printPorcelain, readStdinQueries, type OutputFlags } from './utils/porcelain.js';
 
 // Read version from package.json
 const __filename = fileURLToPath(import.meta.url);
@@ -33,11 +97,46 @@ const packageJsonPath = join(__dirname, '../package.json');
 const packageJson = JSON.parse(readFileSync(packageJsonPath, 'utf-8'));
 
 const program = new Command();
+const log = logger('cli');
+
+const FILTERED_COMMANDS = ['parse', 'graph', 'deps', 'tui', 'health', 'badge', 'lint', 'drift', 'report', 'changelog', 'site', 'targets', 'doctor'];
+const SCOPED_COMMANDS = ['graph', 'deps', 'tui', 'lint'];
+const LABELED_COMMANDS = ['graph', 'deps', 'tui', 'report', 'site'];
+// Servers, history walks, doctor and remote analysis load projects (or past revisions) themselves, outside the global --since scope
+const UNSCOPED_COMMANDS = ['temporal', 'history', 'mcp', 'serve', 'lsp', 'doctor', 'analyze'];
+// Commands answering a question, with a --porcelain record format and a --quiet exit code
+const QUERY_COMMANDS = [
+  'query', 'deps', 'impact', 'targets', 'dead-code', 'health', 'lint', 'security', 'doctor', 'modgraph', 'toolchain', 'dependents',
+  'api-surface', 'apidiff', 'reading-order', 'inits', 'goroutines', 'topology', 'globals', 'errors', 'contexts', 'panics', 'observability', 'build-configs', 'routes', 'di', 'taint', 'unsafe', 'capabilities', 'typosquat', 'confusion', 'scorecard', 'pseudo', 'tripwire', 'binary', 'buildtime', 'compare', 'image', 'workloads',
+];
 
 program
   .name('depwire')
   .description('Code cross-reference graph builder for multi-language projects')
-  .version(packageJson.version);
+  .version(packageJson.version)
+  // Global options go before the command (depwire --since main lint), so history keeps its own --since
+  .option('--since <gitref>', 'Only analyze packages changed since this git ref, plus their immediate neighbors')
+  .option('--runtime-edges', 'Include the runtime edges of imported traces (depwire trace import) in the graph')
+  .option('-v, --verbose', 'More log output on stderr: -v for debug, -vv for trace', (_value: string, count: number) => count + 1, 0)
+  .option('--log-level <level>', 'Log level: error, warn, info (default), debug, trace (overrides -v; default from DEPWIRE_LOG_LEVEL)')
+  .option('--log-format <format>', 'Log format on stderr: text (default), json (one object per line, for CI; default from DEPWIRE_LOG_FORMAT)')
+  .enablePositionalOptions()
+  .hook('preAction', (_program, command) => {
+    const { since, runtimeEdges, verbose, logLevel, logFormat } = program.opts();
+    const output = command.opts() as OutputFlags;
+    try {
+      applyOutputFlags(output);
+      // Porcelain keeps stderr to warnings and errors unless more was asked for
+      configureLogging({ level: logLevel ?? (verbose > 0 ? verbosityLevel(verbose) : output.porcelain ? 'warn' : undefined), format: logFormat });
+    } catch (err) {
+      program.error(err instanceof Error ? err.message : String(err));
+    }
+    if (since && UNSCOPED_COMMANDS.includes(command.name())) {
+      program.error(`--since doesn't apply to ${command.name()}: it loads the project itself, so it would analyze everything anyway`);
+    }
+    if (since) scopeToChangesSince(since);
+    if (runtimeEdges) includeRuntimeEdges();
+  });
 
 program
   .command('parse')
@@ -46,9 +145,8 @@ program
   .option('-o, --output <path>', 'Output JSON file path', 'depwire-output.json')
   .option('--pretty', 'Pretty-print JSON output')
   .option('--stats', 'Print summary statistics')
-  .option('--exclude <patterns...>', 'Glob patterns to exclude (e.g., "**/*.test.*" "dist/**")')
   .option('--verbose', 'Show detailed parsing progress')
-  .action(async (directory: string | undefined, options: { output: string; pretty?: boolean; stats?: boolean; exclude?: string[]; verbose?: boolean }) => {
+  .action(async (directory: string | undefined, options: FilterOptions & { output: string; pretty?: boolean; stats?: boolean; verbose?: boolean }) => {
     trackCommand('parse', packageJson.version);
     const startTime = Date.now();
     
@@ -58,14 +156,14 @@ program
       console.log(`Parsing project: ${projectRoot}`);
       
       // Parse all source files
-      const parsedFiles = await parseProject(projectRoot, {
-        exclude: options.exclude,
+      const parsedFiles = await parseWithProgress(projectRoot, {
+        filter: createFilter(projectRoot, options).includesFile,
         verbose: options.verbose
       });
       console.log(`Parsed ${parsedFiles.length} files`);
       
       // Build the graph
-      const graph = buildGraph(parsedFiles, projectRoot);
+      const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
       
       // Export to JSON
       const projectGraph = exportToJSON(graph, projectRoot);
@@ -75,7 +173,7 @@ program
         ? JSON.stringify(projectGraph, null, 2) 
         : JSON.stringify(projectGraph);
       
-      writeFileSync(options.output, json, 'utf-8');
+      writeFileAtomic(options.output, json);
       console.log(`Graph exported to: ${options.output}`);
       
       // Print stats if requested
@@ -101,7 +199,8 @@ program
         }
       }
     } catch (err) {
-      console.error('Error parsing project:', err);
+      exitIfCancelled(err);
+      log.error('Error parsing project', { error: err });
       process.exit(1);
     }
   });
@@ -110,64 +209,85 @@ program
   .command('query')
   .description('Query impact analysis for a symbol')
   .argument('<directory>', 'Project directory')
-  .argument('<symbol-name>', 'Symbol name to query')
-  .action(async (directory: string, symbolName: string) => {
+  .argument('<symbol-name>', 'Symbol name to query (- reads names from stdin, one per line)')
+  .action(async (directory: string, symbolName: string, options: OutputFlags) => {
     trackCommand('query', packageJson.version);
     try {
+      // - answers a whole list of symbols from one load
+      const names = symbolName === '-' ? readStdinQueries() : [symbolName];
+      if (names.length === 0) throw new Error('No symbols on stdin');
       const projectRoot = resolve(directory);
       const cacheFile = resolve('depwire-output.json');
       
       let graph;
       
-      // Try to load from cache first
-      if (existsSync(cacheFile)) {
-        console.log('Loading from cache...');
+      // Try to load from cache first; it holds the whole project, so not under --since
+      if (existsSync(cacheFile) && !program.opts().since) {
+        if (!options.porcelain) console.log('Loading from cache...');
         const json = JSON.parse(readFileSync(cacheFile, 'utf-8'));
         graph = importFromJSON(json);
       } else {
-        console.log('Parsing project...');
-        const parsedFiles = await parseProject(projectRoot);
-        graph = buildGraph(parsedFiles, projectRoot);
+        if (!options.porcelain) console.log('Parsing project...');
+        const parsedFiles = await parseWithProgress(projectRoot);
+        graph = buildGraph(parsedFiles, projectRoot, graphOptions());
       }
       
-      // Search for the symbol
-      const matches = searchSymbols(graph, symbolName);
-      
-      if (matches.length === 0) {
-        console.log(`No symbols found matching: ${symbolName}`);
+      // Search for the symbols
+      const found = names.map(name => ({ name, matches: searchSymbols(graph, name) }));
+      answerQuietly(found.some(f => f.matches.length > 0));
+
+      if (options.porcelain) {
+        // symbol <query> <id> <name> <kind> <file> <line>, then its dependents and affected files; missing <query> without a match
+        printPorcelain(found.flatMap(({ name, matches }) => matches.length === 0 ? [['missing', name]] : matches.flatMap(match => {
+          const impact = getImpact(graph, match.id);
+          return [
+            ['symbol', name, match.id, match.name, match.kind, match.filePath, match.startLine],
+            ...impact.directDependents.map(dep => ['dependent', name, match.id, dep.id, dep.name, dep.kind, dep.filePath, dep.startLine]),
+            ...impact.transitiveDependents.filter(dep => !impact.directDependents.some(d => d.id === dep.id))
+              .map(dep => ['transitive', name, match.id, dep.id, dep.name, dep.kind, dep.filePath, dep.startLine]),
+            ...impact.affectedFiles.map(file => ['file', name, match.id, file]),
+          ];
+        })));
         return;
       }
       
-      if (matches.length > 1) {
-        console.log(`Found ${matches.length} symbols matching "${symbolName}":`);
-        for (const match of matches) {
-          console.log(`  - ${match.name} (${match.kind}) in ${match.filePath}:${match.startLine}`);
+      for (const { name, matches } of found) {
+        if (matches.length === 0) {
+          console.log(`No symbols found matching: ${name}`);
+          continue;
         }
-        console.log('\nShowing impact for all matches...\n');
-      }
       
-      // Show impact for each match
-      for (const match of matches) {
-        console.log(`=== Impact Analysis: ${match.name} (${match.kind}) ===`);
-        console.log(`Location: ${match.filePath}:${match.startLine}-${match.endLine}`);
+        if (matches.length > 1) {
+          console.log(`Found ${matches.length} symbols matching "${name}":`);
+          for (const match of matches) {
+            console.log(`  - ${match.name} (${match.kind}) in ${match.filePath}:${match.startLine}`);
+          }
+          console.log('\nShowing impact for all matches...\n');
+        }
+      
+        // Show impact for each match
+        for (const match of matches) {
+          console.log(`=== Impact Analysis: ${match.name} (${match.kind}) ===`);
+          console.log(`Location: ${match.filePath}:${match.startLine}-${match.endLine}`);
         
-        const impact = getImpact(graph, match.id);
+          const impact = getImpact(graph, match.id);
         
-        console.log(`\nDirect Dependents: ${impact.directDependents.length}`);
-        for (const dep of impact.directDependents) {
-          console.log(`  - ${dep.name} (${dep.kind}) in ${dep.filePath}:${dep.startLine}`);
-        }
+          console.log(`\nDirect Dependents: ${impact.directDependents.length}`);
+          for (const dep of impact.directDependents) {
+            console.log(`  - ${dep.name} (${dep.kind}) in ${dep.filePath}:${dep.startLine}`);
+          }
         
-        console.log(`\nTotal Transitive Dependents: ${impact.transitiveDependents.length}`);
-        console.log(`Affected Files: ${impact.affectedFiles.length}`);
-        for (const file of impact.affectedFiles) {
-          console.log(`  - ${file}`);
-        }
+          console.log(`\nTotal Transitive Dependents: ${impact.transitiveDependents.length}`);
+          console.log(`Affected Files: ${impact.affectedFiles.length}`);
+          for (const file of impact.affectedFiles) {
+            console.log(`  - ${file}`);
+          }
         
-        console.log('');
+          console.log('');
+        }
       }
     } catch (err) {
-      console.error('Error querying symbol:', err);
+      log.error('Error querying symbol', { error: err });
       process.exit(1);
     }
   });
@@ -180,7 +300,8 @@ program
   .option('--no-open', 'Don\'t auto-open browser')
   .option('--exclude <patterns...>', 'Glob patterns to exclude (e.g., "**/*.test.*" "dist/**")')
   .option('--verbose', 'Show detailed parsing progress')
-  .action(async (directory: string | undefined, options: { port: string; open: boolean; exclude?: string[]; verbose?: boolean }) => {
+  .option('--collapse <prefixes...>', 'Start with these path or import-path prefixes collapsed (e.g. internal/store "github.com/org/x/...")')
+  .action(async (directory: string | undefined, options: { port: string; open: boolean; exclude?: string[]; verbose?: boolean; collapse?: string[] }) => {
     trackCommand('viz', packageJson.version);
     try {
       const projectRoot = directory ? resolve(directory) : findProjectRoot();
@@ -188,14 +309,14 @@ program
       console.log(`Parsing project: ${projectRoot}`);
       
       // Parse all source files
-      const parsedFiles = await parseProject(projectRoot, {
-        exclude: options.exclude,
+      const parsedFiles = await parseWithProgress(projectRoot, {
+        filter: createFilter(projectRoot, options).includesFile,
         verbose: options.verbose
       });
       console.log(`Parsed ${parsedFiles.length} files`);
       
       // Build the graph
-      const graph = buildGraph(parsedFiles, projectRoot);
+      const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
       
       // Prepare visualization data
       const vizData = prepareVizData(graph, projectRoot);
@@ -205,10 +326,14 @@ program
       const port = parseInt(options.port, 10);
       await startVizServer(vizData, graph, projectRoot, port, options.open, {
         exclude: options.exclude,
-        verbose: options.verbose
+        verbose: options.verbose,
+        collapse: options.collapse,
+        only: changeScopeFiles(),
+        ...graphOptions(),
       });
     } catch (err) {
-      console.error('Error starting visualization:', err);
+      exitIfCancelled(err);
+      log.error('Error starting visualization', { error: err });
       process.exit(1);
     }
   });
@@ -228,16 +353,23 @@ program
     try {
       const projectRoot = directory ? resolve(directory) : findProjectRoot();
       
-      await runTemporalAnalysis(projectRoot, {
+      const spinner = createSpinner('Snapshotting commits');
+      await withInterrupt((signal) => runTemporalAnalysis(projectRoot, {
         commits: parseInt(options.commits, 10),
         strategy: options.strategy as 'even' | 'weekly' | 'monthly',
         port: parseInt(options.port, 10),
         output: options.output,
         verbose: options.verbose,
         stats: options.stats,
-      });
+        signal,
+        onProgress: (event) => {
+          spinner.update(event);
+          if (event.completed === event.total) spinner.stop();
+        },
+      }));
     } catch (err) {
-      console.error('Error running temporal analysis:', err);
+      exitIfCancelled(err);
+      log.error('Error running temporal analysis', { error: err });
       process.exit(1);
     }
   });
@@ -271,15 +403,16 @@ program
       if (projectRootToConnect) {
         
         // Log to stderr only (NEVER stdout - it corrupts MCP protocol)
-        console.error(`Parsing project: ${projectRootToConnect}`);
+        const mcpLog = logger('mcp');
+        mcpLog.info(`Parsing project: ${projectRootToConnect}`, { projectRoot: projectRootToConnect });
         
         // Parse all source files
         const parsedFiles = await parseProject(projectRootToConnect);
-        console.error(`Parsed ${parsedFiles.length} files`);
+        mcpLog.info(`Parsed ${parsedFiles.length} files`, { files: parsedFiles.length });
         
         // Build the graph
-        const graph = buildGraph(parsedFiles, projectRootToConnect);
-        console.error(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
+        const graph = buildGraph(parsedFiles, projectRootToConnect, graphOptions());
+        mcpLog.info(`Built graph: ${graph.order} symbols, ${graph.size} edges`, { symbols: graph.order, edges: graph.size });
         
         // Set initial state
         state.graph = graph;
@@ -287,36 +420,36 @@ program
         state.projectName = projectRootToConnect.split('/').pop() || 'project';
 
         // Start file watcher
-        console.error("Starting file watcher...");
+        mcpLog.info('Starting file watcher...');
         state.watcher = watchProject(projectRootToConnect, {
           onFileChanged: async (filePath: string) => {
-            console.error(`File changed: ${filePath}`);
+            mcpLog.debug(`File changed: ${filePath}`, { file: filePath });
             try {
               await updateFileInGraph(state.graph!, projectRootToConnect, filePath);
-              console.error(`Graph updated for ${filePath}`);
+              mcpLog.info(`Graph updated for ${filePath}`, { file: filePath });
             } catch (error) {
-              console.error(`Failed to update graph: ${error}`);
+              mcpLog.warn('Failed to update graph', { file: filePath, error });
             }
           },
           onFileAdded: async (filePath: string) => {
-            console.error(`File added: ${filePath}`);
+            mcpLog.debug(`File added: ${filePath}`, { file: filePath });
             try {
               await updateFileInGraph(state.graph!, projectRootToConnect, filePath);
-              console.error(`Graph updated for ${filePath}`);
+              mcpLog.info(`Graph updated for ${filePath}`, { file: filePath });
             } catch (error) {
-              console.error(`Failed to update graph: ${error}`);
+              mcpLog.warn('Failed to update graph', { file: filePath, error });
             }
           },
           onFileDeleted: (filePath: string) => {
-            console.error(`File deleted: ${filePath}`);
+            mcpLog.debug(`File deleted: ${filePath}`, { file: filePath });
             try {
               const fileNodes = state.graph!.filterNodes((node, attrs) => 
                 attrs.filePath === filePath
               );
               fileNodes.forEach(node => state.graph!.dropNode(node));
-              console.error(`Removed ${filePath} from graph`);
+              mcpLog.info(`Removed ${filePath} from graph`, { file: filePath });
             } catch (error) {
-              console.error(`Failed to remove file: ${error}`);
+              mcpLog.warn('Failed to remove file', { file: filePath, error });
             }
           },
         });
@@ -325,7 +458,7 @@ program
       // Start MCP server (communicates via stdin/stdout)
       await startMcpServer(state);
     } catch (err) {
-      console.error('Error starting MCP server:', err);
+      log.error('Error starting MCP server', { error: err });
       process.exit(1);
     }
   });
@@ -344,6 +477,8 @@ program
   .option('--verbose', 'Show generation progress')
   .option('--stats', 'Show generation statistics at the end')
   .option('--exclude <patterns...>', 'Glob patterns to exclude (e.g., "**/*.test.*" "dist/**")')
+  .option('--package-deps', 'Instead, write each Go package\'s imports and importers into a generated section of its doc.go (under go:generate, only the current package)')
+  .option('--sidecar', 'With --package-deps, write DEPENDENCIES.md next to each package instead of editing doc.go')
   .action(async (directory: string | undefined, options: {
     output?: string;
     format: 'markdown' | 'json';
@@ -354,12 +489,18 @@ program
     verbose?: boolean;
     stats?: boolean;
     exclude?: string[];
+    packageDeps?: boolean;
+    sidecar?: boolean;
   }) => {
     trackCommand('docs', packageJson.version);
     const startTime = Date.now();
     
     try {
       const projectRoot = directory ? resolve(directory) : findProjectRoot();
+      if (options.packageDeps) {
+        await packageDocsCommand(projectRoot, { sidecar: options.sidecar });
+        return;
+      }
       const outputDir = options.output ? resolve(options.output) : join(projectRoot, '.depwire');
       
       // Parse include/only lists - always split by comma
@@ -382,14 +523,14 @@ program
       console.log(`Parsing project: ${projectRoot}`);
       
       // Parse all files
-      const parsedFiles = await parseProject(projectRoot, {
-        exclude: options.exclude,
+      const parsedFiles = await parseWithProgress(projectRoot, {
+        filter: createFilter(projectRoot, options).includesFile,
         verbose: options.verbose
       });
       console.log(`Parsed ${parsedFiles.length} files`);
       
       // Build the graph
-      const graph = buildGraph(parsedFiles, projectRoot);
+      const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
       const parseTime = (Date.now() - startTime) / 1000;
       
       console.log(`Built graph: ${graph.order} symbols, ${graph.size} edges`);
@@ -427,7 +568,26 @@ program
         process.exit(1);
       }
     } catch (err) {
-      console.error('Error generating documentation:', err);
+      exitIfCancelled(err);
+      log.error('Error generating documentation', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Reading order command
+program
+  .command('reading-order')
+  .description('Suggest an order for new engineers to read the Go packages in: leaves first, entry points last, with package summaries')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--format <format>', 'Output format: table (default), markdown, json', 'table')
+  .option('--limit <n>', 'Packages to show in table output', '50')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('reading-order', packageJson.version);
+    try {
+      await readingOrderCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error computing reading order', { error: err });
       process.exit(1);
     }
   });
@@ -466,7 +626,7 @@ function addToGitignore(projectRoot: string, pattern: string): void {
     appendFileSync(gitignorePath, content.endsWith('\n') ? `${pattern}\n` : `\n${pattern}\n`, 'utf-8');
     console.log(`Added ${pattern} to .gitignore`);
   } catch (err) {
-    console.error(`Warning: Failed to update .gitignore: ${err}`);
+    log.warn('Failed to update .gitignore', { error: err });
   }
 }
 
@@ -477,15 +637,15 @@ program
   .argument('[directory]', 'Project directory to analyze (defaults to current directory or auto-detected project root)')
   .option('--json', 'Output as JSON')
   .option('--verbose', 'Show detailed breakdown')
-  .action(async (directory: string | undefined, options: { json?: boolean; verbose?: boolean }) => {
+  .action(async (directory: string | undefined, options: FilterOptions & OutputFlags & { json?: boolean; verbose?: boolean }) => {
     trackCommand('health', packageJson.version);
     try {
       const projectRoot = directory ? resolve(directory) : findProjectRoot();
       const startTime = Date.now();
       
       // Parse project
-      const parsedFiles = await parseProject(projectRoot);
-      const graph = buildGraph(parsedFiles, projectRoot);
+      const parsedFiles = await parseWithProgress(projectRoot, { filter: createFilter(projectRoot, options).includesFile });
+      const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
       const parseTime = Date.now() - startTime;
       
       // Calculate health score
@@ -495,6 +655,12 @@ program
       if (options.json) {
         // JSON output (for CI/automation)
         console.log(JSON.stringify(report, null, 2));
+      } else if (options.porcelain) {
+        // score <overall> <grade>, then dimension <name> <score> <grade> <weight>
+        printPorcelain([
+          ['score', report.overall, report.grade],
+          ...report.dimensions.map(d => ['dimension', d.name, d.score, d.grade, d.weight]),
+        ]);
       } else {
         // Human-readable output
         const formatted = formatHealthReport(report, trend, options.verbose || false);
@@ -504,7 +670,29 @@ program
         console.log(`Analysis completed in ${(totalTime / 1000).toFixed(2)}s (parse: ${(parseTime / 1000).toFixed(2)}s)\n`);
       }
     } catch (err) {
-      console.error('Error analyzing health:', err);
+      exitIfCancelled(err);
+      log.error('Error analyzing health', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Badge command
+program
+  .command('badge')
+  .description('Write a shields-style SVG badge for direct dependencies, import cycles or dependency health')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--metric <metric>', 'deps, cycles or health (weighted cycles, coupling, depth and module count)', 'health')
+  .option('-o, --output <file>', 'Write the SVG here instead of stdout')
+  .option('--label <text>', 'Left-hand text (default depends on the metric)')
+  .option('--weight <component=weight...>', 'Health weights, e.g. cycles=0.5 modules=0 (overrides "health.weights" in depwire.json)')
+  .option('--config <file>', 'Config with health weights and budgets (default: depwire.json in the project root, if present)')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('badge', packageJson.version);
+    try {
+      await badgeCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error generating badge', { error: err });
       process.exit(1);
     }
   });
@@ -521,14 +709,16 @@ program
   .option('--include-tests', 'Include test files in analysis')
   .option('--include-low', 'Shortcut for --confidence low')
   .option('--debug', 'Show debug information (exclusion stats)')
-  .action(async (directory: string | undefined, options: { confidence?: string; json?: boolean; verbose?: boolean; stats?: boolean; includeTests?: boolean; includeLow?: boolean; debug?: boolean }) => {
+  .option('--dynamic-roots', 'Go: keep functions reached through plugins, reflect.New and name-to-constructor registries')
+  .action(async (directory: string | undefined, options: OutputFlags & { confidence?: string; json?: boolean; verbose?: boolean; stats?: boolean; includeTests?: boolean; includeLow?: boolean; debug?: boolean; dynamicRoots?: boolean }) => {
     trackCommand('dead-code', packageJson.version);
     try {
       const projectRoot = directory ? resolve(directory) : findProjectRoot();
       const startTime = Date.now();
       
-      const parsedFiles = await parseProject(projectRoot);
-      const graph = buildGraph(parsedFiles, projectRoot);
+      const parsedFiles = await parseWithProgress(projectRoot);
+      const graph = buildGraph(parsedFiles, projectRoot, graphOptions());
+      const dynamicRoots = options.dynamicRoots ? await dynamicRootLocations(projectRoot) : undefined;
       
       const confidence = options.includeLow ? 'low' : (options.confidence || 'medium');
       
@@ -537,12 +727,18 @@ program
         includeTests: options.includeTests || false,
         verbose: options.verbose || false,
         stats: options.stats || false,
-        json: options.json || false,
+        json: options.json || options.porcelain || false,
         debug: options.debug || false,
+        dynamicRoots,
       });
+      answerQuietly(report.symbols.length > 0);
       
       if (options.json) {
         console.log(JSON.stringify(report, null, 2));
+      } else if (options.porcelain) {
+        // <file> <line> <name> <kind> <confidence> <exported>
+        printPorcelain(report.symbols.map(sym => [sym.file, sym.line, sym.name, sym.kind, sym.confidence, sym.exported]));
+        return;
       }
       
       const totalTime = Date.now() - startTime;
@@ -550,7 +746,8 @@ program
         console.log(`\nAnalysis completed in ${(totalTime / 1000).toFixed(2)}s\n`);
       }
     } catch (err) {
-      console.error('Error analyzing dead code:', err);
+      exitIfCancelled(err);
+      log.error('Error analyzing dead code', { error: err });
       process.exit(1);
     }
   });
@@ -572,7 +769,29 @@ program
     try {
       await whatif(directory || '.', options);
     } catch (err) {
-      console.error('Error running simulation:', err);
+      exitIfCancelled(err);
+      log.error('Error running simulation', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Simulate command
+program
+  .command('simulate')
+  .description('Recompute metrics, cycles and rule violations on a hypothetical graph')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--remove-edge <edges...>', 'Remove the dependency A->B (files or directories)')
+  .option('--move-package <moves...>', 'Move every file under directory X into dir: X->dir')
+  .option('--rules <modules...>', 'Check these rule sets (layer rules) as well as the built-in rules')
+  .option('--no-builtin', 'Do not run the built-in rules')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('simulate', packageJson.version);
+    try {
+      await simulateCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error running simulation', { error: err });
       process.exit(1);
     }
   });
@@ -586,14 +805,1312 @@ program
   .option('--class <classes...>', 'Only run specific vulnerability class checks')
   .option('--format <format>', 'Output format: table (default), json, sarif', 'table')
   .option('--fail-on <level>', 'Exit with code 1 if findings at this severity or above')
+  .option('--deps', 'Also scan Go dependency sources (module cache or vendor/) for hardcoded secrets')
+  .option('--attest <file>', 'Also write a signed in-toto attestation of the report')
+  .option('--attest-key <pem>', 'Private key for --attest (default: DEPWIRE_ATTEST_KEY)')
+  .option('--keyless', 'Sign --attest keylessly with Sigstore using the CI OIDC identity')
   .action(async (directory: string | undefined, options: any) => {
     trackCommand('security', packageJson.version);
     try {
       await securityCommand(directory || '.', options);
     } catch (err) {
-      console.error('Error running security scan:', err);
+      exitIfCancelled(err);
+      log.error('Error running security scan', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Lint command
+program
+  .command('lint')
+  .description('Check the dependency graph against architecture rules')
+  .argument('[directory]', 'Project directory to lint (defaults to current directory or auto-detected project root)')
+  .option('--rules <modules...>', 'Load rule sets from local files or installed packages')
+  .option('--rule <ids...>', 'Run only these rules (IDs, or the aliases cycles, budgets, lock and rego)')
+  .option('--no-builtin', 'Do not run the built-in rules')
+  .option('--delta', 'Only fail on cycles that are new or larger than at --base')
+  .option('--base <ref>', 'Git ref for --delta (default: origin/main)')
+  .option('--go-vet', 'Also run go vet analyzers and merge their findings')
+  .option('--vettool <path>', 'Run go/analysis analyzers from this driver binary (unitchecker/multichecker) via go vet')
+  .option('--go-imports', 'Report blank (_) and dot (.) Go imports of packages outside the allowlist')
+  .option('--allow-import <globs...>', 'Extra import paths allowed as blank or dot imports (implies --go-imports)')
+  .option('--forbid-capability <caps...>', 'Fail on third-party Go modules with these capabilities (network, exec, fs-write, fs-read, env, syscall, cgo, unsafe)')
+  .option('--min-scorecard <score>', 'Fail on direct Go dependencies whose OpenSSF Scorecard score is below this')
+  .option('--scorecard-check <check=min...>', 'Minimum scores for individual Scorecard checks, e.g. Maintained=5 Dangerous-Workflow=10')
+  .option('--scorecard-base <ref>', 'Apply the Scorecard minimums only to modules added since this git ref')
+  .option('--init-tripwire [base]', 'Fail when initializing a dependency (or one added since base) runs exec, network or payload decoding')
+  .option('--go-init <globs...>', 'Forbid network, file, env, exec and goroutine work during init in Go packages matching these globs')
+  .option('--forbid-panics <globs...>', 'Fail when a panic can escape an exported function of Go packages matching these globs')
+  .option('--go-replace', 'Check local replace directives: the target exists, is the replaced module, and is inside the repository')
+  .option('--forbid-local-replace [allow...]', 'Fail on any local replace directive, except for module paths matching the allow globs (implies --go-replace)')
+  .option('--config <file>', 'Lint config with dependency budgets and layers (default: depwire.json in the project root, if present)')
+  .option('--lock <file>', 'Fail on modules and cross-layer edges missing from this lockfile (default: depwire.lock.json, if present)')
+  .option('--adr <dir>', 'Write an ADR stub (context, decision, consequences) to this directory for each cross-layer edge missing from the lockfile')
+  .option('--waivers <file>', 'Time-boxed exceptions for individual findings (default: depwire.waivers.json, if present)')
+  .option('--ratchet <rules...>', 'Only fail when these rules find more than last time; counts are kept in depwire.ratchet.json and only go down')
+  .option('--verdict <file>', 'Also write a pass/fail verdict (per rule, ratchet deltas, waivers applied) for merge queues')
+  .option('--policy <paths...>', 'Evaluate Rego policies (files or directories) with opa: data.depwire.deny fails, data.depwire.warn warns')
+  .option('--format <format>', 'Output format: table (default), json, sarif', 'table')
+  .option('--max-warnings <n>', 'Exit with code 1 if there are more than n warnings')
+  .option('--attest <file>', 'Also write a signed in-toto attestation of the report')
+  .option('--attest-key <pem>', 'Private key for --attest (default: DEPWIRE_ATTEST_KEY)')
+  .option('--keyless', 'Sign --attest keylessly with Sigstore using the CI OIDC identity')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('lint', packageJson.version);
+    try {
+      await lintCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error running lint', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Verdict command
+const verdict = program
+  .command('verdict')
+  .description('Gate on verdicts written by depwire lint --verdict');
+
+verdict
+  .command('assert')
+  .description('Exit with code 1 unless the verdict passes')
+  .argument('<file>', 'Verdict file')
+  .option('--rule <ids...>', 'Require only these rules to have run and passed, instead of the whole verdict')
+  .option('--commit <sha>', 'Require the verdict to be for this commit')
+  .option('--forbid-waivers', 'Fail if any finding was waived')
+  .action(async (file: string, options: any) => {
+    trackCommand('verdict', packageJson.version);
+    try {
+      await verdictAssertCommand(file, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error checking verdict', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Freeze command
+program
+  .command('freeze')
+  .description('Write a lockfile of the approved external modules and cross-layer edges for lint to enforce')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--config <file>', 'Lint config declaring the layers (default: depwire.json in the project root, if present)')
+  .option('-o, --output <file>', 'Lockfile to write (default: depwire.lock.json in the project root)')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('freeze', packageJson.version);
+    try {
+      await freezeCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error writing lockfile', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Waive command
+program
+  .command('waive')
+  .description('Add a time-boxed waiver for one lint finding (fingerprint from lint --format json)')
+  .argument('<rule>', 'Rule ID of the finding')
+  .argument('<fingerprint>', 'Fingerprint of the finding')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .requiredOption('--approver <name>', 'Who approves the exception')
+  .requiredOption('--reason <text>', 'Why the finding is accepted')
+  .requiredOption('--expires <date>', 'Last day the waiver applies (YYYY-MM-DD)')
+  .option('--key <pem>', 'Sign the waiver with the approver\'s private key (default: DEPWIRE_WAIVER_KEY)')
+  .option('--waivers <file>', 'Waivers file (default: depwire.waivers.json in the project root)')
+  .action(async (rule: string, fingerprint: string, directory: string | undefined, options: any) => {
+    trackCommand('waive', packageJson.version);
+    try {
+      await waiveCommand(rule, fingerprint, directory || '.', options);
+    } catch (err) {
+      log.error('Error adding waiver', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Impact command
+program
+  .command('impact')
+  .description('Rank the packages, binaries and services affected by a change, with the chain that makes them affected')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--base <ref>', 'Compare against a git ref (default: uncommitted changes vs HEAD)')
+  .option('--files <files...>', 'Changed files, relative to the project root')
+  .option('--diff <file>', 'Read changed files from a unified diff (- for stdin)')
+  .option('--tests', 'Also compute the minimal set of test packages to run')
+  .option('--format <format>', 'Output format: table (default), json, go-test (package list for go test)', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '20')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('impact', packageJson.version);
+    try {
+      await impactCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error running impact analysis', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Targets command
+program
+  .command('targets')
+  .description('List the binaries and services whose dependencies include changed files, for CI to build and deploy')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--changed-since <ref>', 'Compare against a git ref, e.g. origin/main (default: uncommitted changes vs HEAD)')
+  .option('--files <files...>', 'Changed files, relative to the project root')
+  .option('--kind <kind>', 'Targets to list: binary, service or all', 'all')
+  .option('--always <globs...>', 'Changed files that rebuild every target (e.g. Makefile ".github/**")')
+  .option('--format <format>', 'Output format: lines (one path per line), json, github-matrix', 'lines')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('targets', packageJson.version);
+    try {
+      await targetsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error listing targets', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Refactor command
+const refactor = program
+  .command('refactor')
+  .description('Preview refactorings before making them');
+
+refactor
+  .command('preview')
+  .description('List the imports, go.mod and build files a package move touches, and the cycles it would create')
+  .requiredOption('--move <paths...>', 'Move a package: <from> <to> (import paths or directories; subpackages move too)')
+  .option('--dir <directory>', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '50')
+  .action(async (options: any) => {
+    trackCommand('refactor', packageJson.version);
+    try {
+      await refactorPreviewCommand(options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error previewing refactor', { error: err });
+      process.exit(1);
+    }
+  });
+
+refactor
+  .command('cycles')
+  .description('Suggest an interface extraction that breaks each package import cycle')
+  .option('--dir <directory>', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--skeleton', 'Print a skeleton .go file for each suggested interface')
+  .option('--out <directory>', 'Write skeleton .go files under this directory (existing files are not overwritten)')
+  .action(async (options: any) => {
+    trackCommand('refactor', packageJson.version);
+    try {
+      await refactorCyclesCommand(options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error suggesting cycle breaks', { error: err });
+      process.exit(1);
+    }
+  });
+
+// API surface command
+program
+  .command('api-surface')
+  .description('Inventory each Go package\'s exported identifiers with their external reference counts')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--package <prefix>', 'Only packages whose directory or import path starts with this prefix')
+  .option('--unreferenced', 'Only list exports nothing outside their package uses')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Exports per package in table output', '25')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('api-surface', packageJson.version);
+    try {
+      await apiSurfaceCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error building API surface', { error: err });
+      process.exit(1);
+    }
+  });
+
+// API diff command
+program
+  .command('apidiff')
+  .description('Report incompatible API changes in a Go module between two git refs, and the consumers they break')
+  .argument('<old>', 'Old version (tag, branch or commit)')
+  .argument('[new]', 'New version (defaults to the working tree)')
+  .option('--dir <directory>', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--module <path>', 'Module to compare, in multi-module projects')
+  .option('--all', 'Also list compatible changes')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (oldRef: string, newRef: string | undefined, options: any) => {
+    trackCommand('apidiff', packageJson.version);
+    try {
+      await apidiffCommand(oldRef, newRef, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error comparing APIs', { error: err });
+      process.exit(1);
+    }
+  });
+
+// DI wiring command
+program
+  .command('di')
+  .description('Show wire/fx/dig provider wiring and report missing or duplicate providers')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('di', packageJson.version);
+    try {
+      await diCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing DI wiring', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Package initialization command
+program
+  .command('inits')
+  .description('Show Go init order, what each init() touches, and side-effect (blank) imports')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('inits', packageJson.version);
+    try {
+      await initsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing package initialization', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Goroutine spawn graph command
+program
+  .command('goroutines')
+  .description('Show where Go goroutines are started and whose code they run, as a package spawn graph')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--package <importPath>', 'Only goroutines this package (import path or a suffix of it) starts or whose code it runs')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json, dot', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '30')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('goroutines', packageJson.version);
+    try {
+      await goroutinesCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing goroutines', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Communication topology command
+program
+  .command('topology')
+  .description('Show how Go packages communicate through channels and shared package-level variables')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--package <importPath>', 'Only channels, variables and edges this package (import path or a suffix of it) declares or uses')
+  .option('--format <format>', 'Output format: table (default), json, dot', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '30')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('topology', packageJson.version);
+    try {
+      await topologyCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing communication topology', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Global mutable state command
+program
+  .command('globals')
+  .description('List Go package-level variables written after init, with the packages that write and read them')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--unexported', 'Include unexported variables')
+  .option('--package <importPath>', 'Only variables this package (import path or a suffix of it) declares, writes or reads')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '30')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('globals', packageJson.version);
+    try {
+      await globalsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing global state', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Error flow command
+program
+  .command('errors')
+  .description('Trace Go sentinel errors and error types across packages: where they are returned, wrapped with %w and checked with errors.Is/As')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--package <importPath>', 'Only errors this package (import path or a suffix of it) declares or uses')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json, dot', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '30')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('errors', packageJson.version);
+    try {
+      await errorsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing error flow', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Taint command
+program
+  .command('taint')
+  .description('Track untrusted data from sources (HTTP requests, env) to sinks (SQL, exec, files) across Go packages')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--config <file>', 'JSON file with extra sources, sinks and sanitizers')
+  .option('--no-builtin', 'Only use the sources, sinks and sanitizers from --config')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Flows to show in table output', '20')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('taint', packageJson.version);
+    try {
+      await taintCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error running taint analysis', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Context propagation command
+program
+  .command('contexts')
+  .description('Find Go call chains that drop a context.Context: a caller has one, a function further down creates context.Background/TODO')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--package <importPath>', 'Only chains passing through this package (import path or a suffix of it)')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Findings to show in table output', '20')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('contexts', packageJson.version);
+    try {
+      await contextsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error auditing context propagation', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Panic reachability command
+program
+  .command('panics')
+  .description('Report exported Go functions from which an unrecovered panic is reachable, grouped by package')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--package <importPath>', 'Only this package (import path or a suffix of it)')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Functions to show in table output', '30')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('panics', packageJson.version);
+    try {
+      await panicsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing panic reachability', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Observability library inventory command
+program
+  .command('observability')
+  .description('Inventory the logging, metrics and tracing libraries each Go package uses, with call counts')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--category <category>', 'Only logging, metrics or tracing')
+  .option('--package <importPath>', 'Only this package (import path or a suffix of it)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Packages to show in table output', '30')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('observability', packageJson.version);
+    try {
+      await observabilityCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error inventorying observability libraries', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Build configuration command
+program
+  .command('build-configs')
+  .description('Enumerate the GOOS/GOARCH/tag configurations a Go workspace builds in, and label imports with the build constraints they depend on')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--tags <tags...>', 'Extra custom build tags to try (comma or space separated)')
+  .option('--all', 'Also list imports active in every configuration')
+  .option('--package <importPath>', 'Only imports from this package (import path or a suffix of it)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Imports to show in table output', '40')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('build-configs', packageJson.version);
+    try {
+      await buildConfigsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error enumerating build configurations', { error: err });
+      process.exit(1);
+    }
+  });
+
+// HTTP routes command
+program
+  .command('routes')
+  .description('Map HTTP routes (net/http, gorilla/mux, chi, gin, echo) to their handler, its package and the packages reachable from it')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--path <path>', 'Only routes serving this request path (/api/users/42) or written as this pattern, with their dependencies')
+  .option('--package <importPath>', 'Only routes whose handler is in this package (import path or a suffix of it)')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Routes to show in table output', '50')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('routes', packageJson.version);
+    try {
+      await routesCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error mapping HTTP routes', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Sequence diagram command
+program
+  .command('sequence')
+  .description('Draw a Mermaid sequence diagram of the cross-package calls from an entry point or the handler of a route')
+  .argument('<entry>', 'Function (main.main, handlers.Server.GetUser, cmd/api.main) or route ("GET /api/users/42")')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--depth <n>', 'Cross-package calls to follow from the entry point', '4')
+  .option('--external', 'Also draw calls into third-party packages (never the standard library)')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: mermaid (default), markdown (a section with the fenced diagram), json')
+  .option('-o, --output <file>', 'Write the diagram to a file instead of stdout')
+  .action(async (entry: string, directory: string | undefined, options: any) => {
+    trackCommand('sequence', packageJson.version);
+    try {
+      await sequenceCommand(entry, directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error drawing sequence diagram', { error: err });
+      process.exit(1);
+    }
+  });
+
+// unsafe/reflect usage command
+program
+  .command('unsafe')
+  .description('Inventory unsafe and reflect usage in Go code and dependencies, with reachability from entry points')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--no-deps', 'Only the workspace, not third-party modules')
+  .option('--unreachable', 'Also list uses not reachable from any entry point')
+  .option('--dynamic-roots', 'Count functions reached through plugins, reflect.New and name-to-constructor registries as entry points')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Uses to show in table output', '30')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('unsafe', packageJson.version);
+    try {
+      await unsafeCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing unsafe usage', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Dependency capabilities command
+program
+  .command('capabilities')
+  .description('Capability matrix of third-party Go modules: network, exec, file system, env, syscall, cgo, unsafe')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--module <path>', 'Show the call sites behind one module\'s capabilities')
+  .option('--all', 'Include modules without any tracked capability')
+  .option('--run <command>', 'Run a command (a test binary, say) under strace -f -k and check the capabilities it exercises against the static report')
+  .option('--observe <log>', 'Check a recorded strace -f -k log instead of running a command')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('capabilities', packageJson.version);
+    try {
+      await capabilitiesCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing capabilities', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Typosquat command
+program
+  .command('typosquat')
+  .description('Check new Go module dependencies for lookalikes of popular or internal module paths')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--base <ref>', 'Only check modules added since this git ref (default: every required module)')
+  .option('--allow <globs...>', 'Module paths that are trusted even if they resemble another')
+  .option('--internal <prefixes...>', 'Your organization\'s module prefixes, e.g. github.com/acme')
+  .option('--popular <modules...>', 'Extra module paths to protect')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('typosquat', packageJson.version);
+    try {
+      await typosquatCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error checking module paths', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Analyze command
+const analyze = program
+  .command('analyze')
+  .description('Analyze code that is not checked out, such as a dependency you are about to add');
+
+analyze
+  .command('mod')
+  .description('Download a Go module from GOPROXY and run the full analysis on it, without git or a workspace')
+  .argument('<module>', 'Module and version: github.com/foo/bar@v1.2.3 (default version: latest)')
+  .option('--proxy <url>', 'GOPROXY list to download from (default: go env GOPROXY)')
+  .option('--cache-dir <dir>', 'Where extracted modules are kept (default: ~/.cache/depwire/mod)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '10')
+  .action(async (module: string, options: any) => {
+    trackCommand('analyze', packageJson.version);
+    try {
+      await analyzeModCommand(module, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing module', { error: err });
+      process.exit(1);
+    }
+  });
+
+analyze
+  .command('archive')
+  .description('Run the full analysis on a module zip or source tarball (.tar, .tar.gz) without network access')
+  .argument('<file>', 'Module zip (as served by GOPROXY) or source tarball')
+  .option('--sum <h1:hash>', 'Fail unless the module zip has this go.sum hash')
+  .option('--cache-dir <dir>', 'Where extracted archives are kept (default: ~/.cache/depwire/mod)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Rows per section in table output', '10')
+  .action(async (file: string, options: any) => {
+    trackCommand('analyze', packageJson.version);
+    try {
+      await analyzeArchiveCommand(file, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing archive', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Compare command
+const compare = program
+  .command('compare')
+  .description('Compare two versions of a dependency before upgrading, or the static graph against runtime traces')
+  // Its --format is not the one of compare mod
+  .enablePositionalOptions()
+  .option('--static <graph>', 'Graph saved with depwire parse (default: parse the project)')
+  .option('--dynamic <traces...>', 'Traces written by the depwiretrace Go package')
+  .option('--dir <directory>', 'Project directory, for its go.mod files (defaults to the graph\'s project root or auto-detected)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Calls and packages to show per section in table output', '20')
+  .action(async (options: any) => {
+    trackCommand('compare', packageJson.version);
+    try {
+      await compareTracesCommand(options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error comparing the graph with traces', { error: err });
+      process.exit(1);
+    }
+  });
+
+compare
+  .command('mod')
+  .description('Fetch two versions of a Go module and report new and removed transitive modules, license changes and the API delta')
+  .argument('<from>', 'Current version: github.com/foo/bar@v1.4.0')
+  .argument('<to>', 'Version to upgrade to: github.com/foo/bar@v1.5.0, or just v1.5.0')
+  .option('--proxy <url>', 'GOPROXY list to download from (default: go env GOPROXY)')
+  .option('--cache-dir <dir>', 'Where extracted modules are kept (default: ~/.cache/depwire/mod)')
+  .option('--no-licenses', 'Do not download new and upgraded modules to compare their licenses')
+  .option('--format <format>', 'Output format: markdown (default), json', 'markdown')
+  .option('-o, --output <file>', 'Write the summary to a file instead of stdout')
+  .option('--fail-on <risk>', 'Exit 1 when the upgrade risk is at least this: low, medium, high')
+  .action(async (from: string, to: string, options: any) => {
+    trackCommand('compare', packageJson.version);
+    try {
+      await compareModCommand(from, to, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error comparing module versions', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Confusion command
+program
+  .command('confusion')
+  .description('Check whether internal Go module paths resolve on public proxies and GOPRIVATE covers them')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--private <prefixes...>', 'Private module prefixes (default: GOPRIVATE)')
+  .option('--proxy <url>', 'Public proxy to query', 'https://proxy.golang.org')
+  .option('--offline', 'Only check GOPRIVATE/GONOPROXY/GONOSUMDB, do not query the proxy')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('confusion', packageJson.version);
+    try {
+      await confusionCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error checking dependency confusion', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Scorecard command
+program
+  .command('scorecard')
+  .description('Show OpenSSF Scorecard results for the repositories behind external Go modules')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--offline', 'Use cached results only')
+  .option('--all', 'Include indirect dependencies')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('scorecard', packageJson.version);
+    try {
+      await scorecardCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error fetching scorecards', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Dependents command
+program
+  .command('dependents')
+  .description('Find public modules that depend on this one (deps.dev, pkg.go.dev), before making a breaking change')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--module <path>', 'Module to look up (default: the module at the project root)')
+  .option('--version <version>', 'Version to count dependents of (default: the latest release deps.dev knows)')
+  .option('--package <paths...>', 'Only list importers of these packages')
+  .option('--counts-only', 'Only fetch dependent counts, not the importers of each package')
+  .option('--offline', 'Use cached results only')
+  .option('--limit <n>', 'Dependent projects to show in table output', '20')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('dependents', packageJson.version);
+    try {
+      await dependentsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error finding dependents', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Pseudo-version command
+program
+  .command('pseudo')
+  .description('Resolve dependencies pinned to pseudo-versions to their commits and check those commits still exist upstream')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--all', 'Include indirect dependencies')
+  .option('--offline', 'Only decode the pseudo-versions, without querying the proxy or repositories')
+  .option('--proxy <url>', 'GOPROXY list to query (default: go env GOPROXY)')
+  .option('--cache-dir <dir>', 'Where bare repository clones are kept (default: ~/.cache/depwire/git)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('pseudo', packageJson.version);
+    try {
+      await pseudoCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error checking pseudo-versions', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Toolchain command
+program
+  .command('toolchain')
+  .description('Audit go and toolchain directives across the module graph and the Go features that set our minimum version')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--all', 'List every module in the graph, not just direct ones')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('toolchain', packageJson.version);
+    try {
+      await toolchainCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error auditing toolchain versions', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Doctor command
+program
+  .command('doctor')
+  .description('Diagnose what makes analysis slow or incomplete: the Go toolchain, module cache, packages that fail to load and files the parser skips')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--all', 'List every problem, not just the first 10 of each check')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('doctor', packageJson.version);
+    try {
+      await doctorCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error running diagnostics', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Modgraph command
+program
+  .command('modgraph')
+  .description('Show the module requirement graph with the package imports behind each requirement')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--module <path>', 'Explain a single module')
+  .option('--unexplained', 'Only requirements that no package import uses')
+  .option('--tests', 'Count imports from _test.go files')
+  .option('--format <format>', 'Output format: table (default), json, dot', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('modgraph', packageJson.version);
+    try {
+      await modgraphCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error explaining module graph', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Vendor command
+const vendor = program
+  .command('vendor')
+  .description('Check a vendor/ tree');
+
+vendor
+  .command('verify')
+  .description('Verify vendor/ matches go.mod and vendor/modules.txt exactly, and every file matches the go.sum-verified module source')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--offline', 'Compare only with the module cache and local replacements, without downloading')
+  .option('--proxy <url>', 'GOPROXY list to download missing sources from (default: go env GOPROXY)')
+  .option('--cache-dir <dir>', 'Where downloaded modules are kept (default: ~/.cache/depwire/mod)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('vendor', packageJson.version);
+    try {
+      await vendorVerifyCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error verifying vendor directory', { error: err });
       process.exit(1);
     }
   });
 
+// Tripwire command
+program
+  .command('tripwire')
+  .description('Flag Go dependencies whose init paths run processes, open connections or decode payloads')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--base <ref>', 'Only check modules added since this git ref (default: every dependency)')
+  .option('--algo <algo>', 'Call graph: syntactic (default) or pta (SSA pointer analysis through go: exact through interfaces and function values, slower, cached)', 'syntactic')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('tripwire', packageJson.version);
+    try {
+      await tripwireCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error checking init paths', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Binary command
+program
+  .command('binary')
+  .description('List the modules a built Go binary was linked from, with versions, replacements and the bytes each contributes, from the binary alone')
+  .argument('<binary>', 'Go executable (./bin/server)')
+  .option('--packages', 'Also list the largest packages')
+  .option('--baseline <file>', 'Compare with an earlier build (a binary, or its --format json report): which modules and packages grew')
+  .option('--record', 'Add the sizes of this build to .depwire/binary-history.json for trends')
+  .option('--trend', 'Show the recorded builds of this program and the modules that grew most')
+  .option('--dir <dir>', 'Project directory holding the history (defaults to the detected project root)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Modules (and packages) to show in table output', '30')
+  .action(async (binary: string, options: any) => {
+    trackCommand('binary', packageJson.version);
+    try {
+      await binaryCommand(binary, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error reading binary', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Build time command
+program
+  .command('buildtime')
+  .description('Attribute Go compile time to packages and rank them by rebuild cost: the compile time a change to each one triggers, against its fan-in')
+  .argument('[dir]', 'Project directory (defaults to the detected project root)')
+  .option('--actiongraph <files...>', 'Use go build -debug-actiongraph output (from CI, say) instead of running go build -a')
+  .option('--all', 'Include dependencies and the standard library')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Packages to show in table output', '20')
+  .action(async (dir: string | undefined, options: any) => {
+    trackCommand('buildtime', packageJson.version);
+    try {
+      await buildtimeCommand(dir, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error measuring build time', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Image command
+program
+  .command('image')
+  .description('Audit a container image: find the Go binaries in its layers and list the modules each was built from, or emit a CycloneDX SBOM')
+  .argument('<ref>', 'Image reference (ghcr.io/org/app:v1, alpine@sha256:…) or a docker save archive')
+  .option('--platform <os/arch>', 'Platform to pull from a multi-platform image', 'linux/amd64')
+  .option('--format <format>', 'Output format: table (default), json, cyclonedx (SBOM with the image → binary → module graph)', 'table')
+  .option('-o, --output <file>', 'Write to a file instead of stdout')
+  .option('--limit <n>', 'Modules to show in table output', '30')
+  .action(async (ref: string, options: any) => {
+    trackCommand('image', packageJson.version);
+    try {
+      await imageCommand(ref, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error analyzing image', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Workloads command
+program
+  .command('workloads')
+  .description('Map Kubernetes workloads to their images, the Go binaries in them and the modules each ships: which services ship a module or advisory')
+  .argument('[manifests]', 'Directory or file of Kubernetes manifests, YAML or JSON (defaults to current directory)')
+  .option('--cluster', 'Read the workloads of the live cluster through kubectl instead')
+  .option('--context <name>', 'kubectl context for --cluster')
+  .option('--namespace <ns>', 'Only workloads in this namespace')
+  .option('--module <path[@version]>', 'Only workloads shipping this module (at this version)')
+  .option('--vuln <id>', 'Only workloads shipping a module version affected by this advisory (GO-, CVE- or GHSA- id, via OSV)')
+  .option('--vulns', 'Only module versions with known vulnerabilities (via OSV)')
+  .option('--platform <os/arch>', 'Platform to pull from multi-platform images', 'linux/amd64')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Rows to show in table output', '30')
+  .action(async (manifests: string | undefined, options: any) => {
+    trackCommand('workloads', packageJson.version);
+    try {
+      await workloadsCommand(manifests, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error mapping workloads', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Runtime trace commands
+const trace = program
+  .command('trace')
+  .description('Merge Go call edges recorded at runtime by the depwiretrace package into the project graph');
+
+trace
+  .command('import')
+  .description('Import traces written by depwiretrace: their calls become runtime edges in graphs built with --runtime-edges')
+  .argument('<trace...>', 'Trace files (DEPWIRE_TRACE output)')
+  .option('--dir <directory>', 'Project directory (defaults to auto-detected project root)')
+  .option('--replace', 'Drop the edges of earlier imports instead of adding to them (a trace imported again is skipped)')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .option('--limit <n>', 'Runtime-only calls to show in table output', '30')
+  .action(async (files: string[], options: any) => {
+    trackCommand('trace', packageJson.version);
+    try {
+      await traceImportCommand(files, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error importing traces', { error: err });
+      process.exit(1);
+    }
+  });
+
+trace
+  .command('clear')
+  .description('Remove every imported trace from the project')
+  .option('--dir <directory>', 'Project directory (defaults to auto-detected project root)')
+  .action(async (options: any) => {
+    trackCommand('trace', packageJson.version);
+    try {
+      await traceClearCommand(options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error clearing traces', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Attest command
+const attest = program
+  .command('attest')
+  .description('Create and verify signed in-toto attestations of depwire reports');
+
+attest
+  .command('create')
+  .description('Attest a report saved with --format json')
+  .argument('<report>', 'JSON report file')
+  .option('--dir <directory>', 'Project directory the report was computed for (defaults to auto-detected project root)')
+  .option('--command <name>', 'Command that produced the report', 'report')
+  .option('-o, --attest <file>', 'Attestation output (default: <report>.intoto.json)')
+  .option('--attest-key <pem>', 'Private key (default: DEPWIRE_ATTEST_KEY)')
+  .option('--keyless', 'Sign keylessly with Sigstore using the CI OIDC identity')
+  .action(async (report: string, options: any) => {
+    trackCommand('attest', packageJson.version);
+    try {
+      await attestCreateCommand(report, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error creating attestation', { error: err });
+      process.exit(1);
+    }
+  });
+
+attest
+  .command('verify')
+  .description('Verify an attestation\'s signature, report digest and commit')
+  .argument('<file>', 'DSSE envelope or Sigstore bundle')
+  .option('--key <pem>', 'Public key for key-signed attestations')
+  .option('--trusted-root <file>', 'Sigstore trusted root for keyless bundles (from `gh attestation trusted-root`)')
+  .option('--certificate-identity <san>', 'Keyless: the signing certificate\'s identity, e.g. https://github.com/org/repo/.github/workflows/ci.yml@refs/heads/main')
+  .option('--certificate-identity-regexp <regexp>', 'Keyless: a regular expression the whole certificate identity must match')
+  .option('--certificate-oidc-issuer <url>', 'Keyless: the OIDC issuer, e.g. https://token.actions.githubusercontent.com')
+  .option('--commit <sha>', 'Require the attestation to be for this commit')
+  .option('--format <format>', 'Output format: table (default), json', 'table')
+  .action(async (file: string, options: any) => {
+    trackCommand('attest', packageJson.version);
+    try {
+      await attestVerifyCommand(file, options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Attestation verification failed', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Graph export command
+program
+  .command('graph')
+  .description('Export the package or file dependency graph as Graphviz DOT, SVG, HTML, GEXF or JSON')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--level <level>', 'Node granularity: package (default), file', 'package')
+  .option('--format <format>', 'Output format: dot (default), svg, html (default for --treemap), gexf (Gephi), mermaid, json')
+  .option('--color-by <metric>', 'Heatmap nodes by loc, churn, vulns, instability, binsize, coverage, cpu, buildtime, or any numeric node attribute (e.g. symbols)')
+  .option('--churn-since <date>', 'History window for --color-by churn (git --since)', '90 days ago')
+  .option('--treemap', 'Render a treemap of package sizes instead of a node-link diagram (svg or html)')
+  .option('--size <metric>', 'Treemap area: loc (default), binsize, symbols, files or another numeric metric', 'loc')
+  .option('--binary <file>', 'Go binary for the binsize metric (bytes each package contributes)')
+  .option('--coverprofile <files...>', 'go test -coverprofile output for the coverage metric (also set on edges, for the package depended on)')
+  .option('--pprof <files...>', 'pprof CPU profiles for the cpu metric: share of time per package, and along each edge the calls carry')
+  .option('--actiongraph <files...>', 'go build -debug-actiongraph output for the buildtime metric (compile ms per package, and rebuild beside it)')
+  .option('--diff <ref>', 'Overlay changes since a git ref: added edges green, removed dashed red, changed nodes highlighted')
+  .option('--cluster', 'Group packages into de facto modules by community detection (Louvain)')
+  .option('--resolution <n>', 'Community resolution for --cluster; higher gives smaller clusters', '1')
+  .option('--focus <node>', 'Only emit the neighborhood of this package (directory, import path or unique suffix) or file')
+  .option('--hops <n>', 'Neighborhood radius for --focus', '2')
+  .option('--direction <dir>', 'Neighborhood direction for --focus: out (dependencies), in (dependents), both', 'both')
+  .option('-o, --output <file>', 'Write to a file instead of stdout')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('graph', packageJson.version);
+    try {
+      await graphCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error exporting graph', { error: err });
+      process.exit(1);
+    }
+  });
+
+// History command
+program
+  .command('history')
+  .description('Dependency graph metrics at fixed steps since a tag, commit or date, with a CSV and a steppable viewer')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .requiredOption('--since <ref-or-date>', 'Where to start: a tag, commit or date (e.g. v1.0.0, 2025-01-01)')
+  .option('--step <interval>', 'Time between revisions (e.g. 1month, 2weeks, 10days, quarterly)', '1month')
+  .option('--format <format>', 'Output format: table (default), json, csv', 'table')
+  .option('--csv <file>', 'Also write the metrics over time as CSV')
+  .option('--gexf <file>', 'Also write a dynamic GEXF package graph (per-revision weights) for Gephi')
+  .option('--viz', 'Open the temporal viewer to step or animate through the revisions')
+  .option('-p, --port <number>', 'Viewer port', '3334')
+  .option('--output <path>', 'Snapshot cache (default: .depwire/temporal/, shared with depwire temporal)')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('history', packageJson.version);
+    try {
+      await historyCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error computing history', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Deps command
+program
+  .command('deps')
+  .description('Print the package dependency tree, or a compact adjacency matrix, in the terminal')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--package <pkg>', 'Start from this package (directory, import path or unique suffix) instead of the entry points; - reads one per line from stdin')
+  .option('--reverse', 'Show what depends on each package instead of what it depends on')
+  .option('--depth <n>', 'Levels to expand (with --matrix: hops around --package, default 1)')
+  .option('--level <level>', 'Node granularity: package (default), file', 'package')
+  .option('--matrix', 'Draw an adjacency matrix instead of a tree (small graphs)')
+  .option('--ascii', 'Plain ASCII instead of box-drawing characters')
+  .option('--width <columns>', 'Fit output to this width instead of the terminal\'s')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('deps', packageJson.version);
+    try {
+      await depsCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error rendering dependencies', { error: err });
+      process.exit(1);
+    }
+  });
+
+// TUI command
+program
+  .command('tui')
+  .description('Explore the dependency graph in a terminal UI: dependencies and dependents panes, fuzzy search, metric columns, jump to source')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--level <level>', 'Node granularity: package (default), file', 'package')
+  .option('--metrics <metrics...>', 'Extra columns: loc, churn, vulns, instability')
+  .option('--churn-since <date>', 'History window for the churn column (git --since)', '90 days ago')
+  .option('--ascii', 'Plain ASCII instead of box-drawing characters')
+  .option('--collapse <prefixes...>', 'Start with these path or import-path prefixes collapsed (e.g. internal/store "github.com/org/x/...")')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('tui', packageJson.version);
+    try {
+      await tuiCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error starting the explorer', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Drift command
+program
+  .command('drift')
+  .description('Report how the dependency graph has drifted since a stored baseline')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .requiredOption('--baseline <location>', 'Baseline file or URL: a path, file://, http(s)://, s3:// (aws CLI) or gs:// (gsutil)')
+  .option('--capture', 'Store the current graph as the baseline instead of comparing against it')
+  .option('--format <format>', 'Report format: markdown (default), json')
+  .option('-o, --output <file>', 'Write the report to a file instead of stdout')
+  .option('--fail-on-regression', 'Exit with code 1 on new direct modules, new or grown cycles, or worse metrics')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('drift', packageJson.version);
+    try {
+      await driftCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error computing drift', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Architecture report command
+program
+  .command('report')
+  .description('Generate an ARCHITECTURE.md to commit: components, mermaid diagrams, metrics and external dependencies')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--format <format>', 'Report format: markdown (default), json')
+  .option('-o, --output <file>', 'Write the report to a file instead of stdout')
+  .option('--check', 'Exit with code 1 if the -o file differs from what would be written, instead of writing it')
+  .option('--profile <profile>', 'Export for a wiki, with the diagrams as SVG files next to -o: confluence (storage format XHTML), notion (Markdown with image links)')
+  .option('--glossary', 'Add a glossary of the external modules: description, maintainer, latest version (pkg.go.dev, deps.dev, GOPROXY)')
+  .option('--proxy <url>', 'GOPROXY list to resolve latest versions with (default: go env GOPROXY)')
+  .option('--offline', 'Build the glossary from the .depwire cache only')
+  .option('--coverprofile <files...>', 'Add a test coverage section from go test -coverprofile output, with the concentration risks')
+  .option('--coverage-below <percent>', 'Concentration risk: covered below this percentage', '50')
+  .option('--min-dependents <n>', 'Concentration risk: at least this many packages depend on it, directly or transitively', '3')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('report', packageJson.version);
+    try {
+      await reportCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error generating architecture report', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Changelog command
+program
+  .command('changelog')
+  .description('Summarize dependency changes between two releases for the release notes: modules, licenses, new advisories, graph size')
+  .argument('<range>', 'Git refs to compare: v1.2.0..v1.3.0 (an empty side is HEAD)')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('--proxy <url>', 'GOPROXY list to download modules from for license checks (default: go env GOPROXY)')
+  .option('--cache-dir <dir>', 'Where extracted modules are kept (default: ~/.cache/depwire/mod)')
+  .option('--no-licenses', 'Do not download added and changed modules to compare their licenses')
+  .option('--no-vulns', 'Do not query OSV for advisories of added and changed modules')
+  .option('--format <format>', 'Output format: markdown (default), json')
+  .option('-o, --output <file>', 'Write the changelog to a file instead of stdout')
+  .action(async (range: string, directory: string | undefined, options: any) => {
+    trackCommand('changelog', packageJson.version);
+    try {
+      await changelogCommand(range, directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error generating dependency changelog', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Documentation site command
+program
+  .command('site')
+  .description('Generate a static documentation site: overview, per-package pages, the interactive graph, metrics and findings')
+  .argument('[directory]', 'Project directory (defaults to current directory or auto-detected project root)')
+  .option('-o, --output <dir>', 'Directory to write the site to', 'docs-site')
+  .option('--format <format>', 'Site format: html (default, ready to publish), mkdocs (mkdocs.yml and docs/), hugo (hugo.toml, content/ and layouts)')
+  .option('--no-findings', 'Leave lint findings out of the site')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('site', packageJson.version);
+    try {
+      await siteCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error generating documentation site', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Serve command
+program
+  .command('serve')
+  .description('Keep a project loaded and serve analysis, queries and diffs over an API')
+  .argument('[directory]', 'Project directory to serve (defaults to current directory or auto-detected project root)')
+  .option('-p, --port <number>', 'REST API port', '3334')
+  .option('--no-rest', 'Do not serve the REST API')
+  .option('--grpc', 'Serve the gRPC API (depwire.v1.Depwire, see dist/serve/depwire.proto)')
+  .option('--grpc-port <number>', 'gRPC port', '50051')
+  .option('--host <host>', 'Interface to bind', '127.0.0.1')
+  .option('--watch', 'Update the graph as files change')
+  .action(async (directory: string | undefined, options: any) => {
+    trackCommand('serve', packageJson.version);
+    try {
+      await serveCommand(directory || '.', options);
+    } catch (err) {
+      exitIfCancelled(err);
+      log.error('Error starting server', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Fixture generator command
+program
+  .command('gen-fixture')
+  .description('Generate a synthetic Go project of a given shape, for benchmarks and for testing rules against known structures')
+  .option('--packages <n>', 'Packages', '50')
+  .option('--edges <n>', 'Package imports, cycle edges included (default: three per package)')
+  .option('--cycles <n>', 'Import cycles, each its own strongly connected component of 2–4 packages (Go rejects them, so the project no longer builds)', '0')
+  .option('--files <n>', 'Go files per package', '2')
+  .option('--functions <n>', 'Functions per file', '3')
+  .option('--module <path>', 'Module path', 'example.com/fixture')
+  .option('--seed <n>', 'Random seed; the same seed gives the same project', '1')
+  .option('-o, --output <dir>', 'Directory to write (empty, new, or an earlier fixture)', 'fixture')
+  .action((options: any) => {
+    trackCommand('gen-fixture', packageJson.version);
+    try {
+      genFixtureCommand(options);
+    } catch (err) {
+      log.error('Error generating fixture', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Completion command
+program
+  .command('completion')
+  .description('Print a shell completion script; packages and modules complete from the workspace')
+  .argument('<shell>', 'bash, zsh or fish')
+  .addHelpText('after', '\nExamples:\n  source <(depwire completion bash)    # ~/.bashrc\n  source <(depwire completion zsh)     # ~/.zshrc\n  depwire completion fish > ~/.config/fish/completions/depwire.fish')
+  .action((shell: string) => {
+    trackCommand('completion', packageJson.version);
+    try {
+      completionCommand(shell);
+    } catch (err) {
+      log.error('Error', { error: err });
+      process.exit(1);
+    }
+  });
+
+// Called by the completion scripts on every TAB — no telemetry
+program
+  .command('__complete', { hidden: true })
+  .argument('[words...]')
+  .action((words: string[]) => {
+    completeCommand(program, words);
+  });
+
+// LSP command
+program
+  .command('lsp')
+  .description('Start a JSON-RPC server on stdio for editor plugins (LSP framing, depwire/* requests)')
+  .argument('[directory]', 'Project directory (defaults to the root sent by the client in initialize)')
+  .action((directory: string | undefined) => {
+    trackCommand('lsp', packageJson.version);
+    // stdout carries the protocol — all logging goes to stderr
+    startLspServer({ projectRoot: directory ? resolve(directory) : undefined });
+  });
+
+// The shared output flags (src/utils/porcelain.ts), filter flags (src/parser/filter.ts) and display-name flags (src/export/labels.ts), spelled and honored the same on every command that takes them
+for (const command of program.commands) {
+  if (QUERY_COMMANDS.includes(command.name())) {
+    command
+      .option('--porcelain', 'Stable tab-separated records for scripts: no color, headings or progress')
+      .option('-q, --quiet', 'Print nothing; the exit code is the answer');
+  }
+  if (!FILTERED_COMMANDS.includes(command.name())) continue;
+  command
+    .option('--include <globs...>', 'Only analyze files matching these globs (e.g., "internal/**")')
+    .option('--exclude <globs...>', 'Skip files matching these globs (e.g., "**/*_test.go" "vendor/**")')
+    .option('--exclude-generated', 'Skip generated code ("Code generated ... DO NOT EDIT." headers, @generated, *.pb.go)');
+  if (SCOPED_COMMANDS.includes(command.name())) {
+    command
+      .option('--only-internal', 'Only dependencies between the workspace\'s own packages')
+      .option('--only-external', 'Only dependencies on third-party modules');
+  }
+  if (LABELED_COMMANDS.includes(command.name())) {
+    command
+      .option('--strip-module', 'Label packages relative to their module (internal/store, not github.com/org/repo/internal/store)')
+      .option('--alias <pattern=label...>', 'Rename nodes whose full label matches a regex; $1 refers to a group (e.g. "^.*/internal/(.*)$=int/$1")')
+      .option('--shorten <segments>', 'Keep only the last n path segments of each label, more where names would collide');
+  }
+}
+
 program.parse();
@@ -284,6 +284,7 @@ function processIncludeDirective(node: Parser.SyntaxNode, context: Context): voi
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -311,6 +312,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
     kind: 'calls',
     filePath: context.filePath,
     line: node.startPosition.row + 1,
+    column: node.startPosition.column + 1,
   });
 }
 
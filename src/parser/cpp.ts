@@ -250,6 +250,7 @@ function processBaseClassClause(node: Parser.SyntaxNode, sourceId: string, conte
             kind: 'inherits',
             filePath: context.filePath,
             line: child.startPosition.row + 1,
+            column: child.startPosition.column + 1,
           });
         }
       }
@@ -607,6 +608,7 @@ function processIncludeDirective(node: Parser.SyntaxNode, context: Context): voi
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -659,6 +661,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
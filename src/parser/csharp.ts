@@ -241,6 +241,7 @@ function processTypeDeclaration(
               kind: edgeKind,
               filePath: context.filePath,
               line: child.startPosition.row + 1,
+              column: child.startPosition.column + 1,
             });
           }
         }
@@ -496,6 +497,7 @@ function processUsingDirective(node: Parser.SyntaxNode, context: Context): void
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -554,6 +556,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -0,0 +1,95 @@
+import { existsSync } from 'fs';
+import { join } from 'path';
+import { minimatch } from 'minimatch';
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+import type { LintResult } from '../rules/types.js';
+import { summarize } from '../rules/engine.js';
+
+/**
+ * The one include/exclude filter every command honors the same way:
+ * graph, deps, tui and the exporters, health and badge metrics, and lint.
+ * File filters (--include, --exclude, --exclude-generated) decide what is
+ * parsed, and drop findings that rules reading files on their own (go vet,
+ * go.mod checks) report in filtered files. The scope flags pick between
+ * dependencies among the workspace's own packages (--only-internal) and
+ * dependencies on third-party modules (--only-external).
+ */
+
+export interface FilterOptions {
+  /** Globs a file must match (any of them) to be analyzed */
+  include?: string[];
+  /** Globs that take a file out, even if included */
+  exclude?: string[];
+  /** Skip generated code: "Code generated … DO NOT EDIT." headers, @generated markers, *.pb.go and the like */
+  excludeGenerated?: boolean;
+  onlyExternal?: boolean;
+  onlyInternal?: boolean;
+}
+
+export type DependencyScope = 'all' | 'internal' | 'external';
+
+export interface AnalysisFilter {
+  scope: DependencyScope;
+  /** Whether a project-relative file is analyzed; pass its source to check for generated-code markers */
+  includesFile(file: string, source?: string): boolean;
+  /** Whether a dependency target (file, symbol, import or module path) is in scope */
+  includesTarget(target: string): boolean;
+  /** Whether any option is set — commands skip the work when nothing filters */
+  active: boolean;
+}
+
+// Go's convention (https://go.dev/s/generatedcode) and the markers other generators leave
+const GO_GENERATED = /^\/\/ Code generated .* DO NOT EDIT\.$/m;
+const GENERATED_MARKER = /@generated\b|\bDO NOT EDIT\b|<auto-generated|\bautogenerated\b/i;
+const GENERATED_NAMES = /(\.pb\.go|\.pb\.gw\.go|_pb2(_grpc)?\.py|\.generated\.\w+|\.g\.dart|\.min\.js|zz_generated\.[\w.]+)$/;
+
+export function isGeneratedFile(file: string, source?: string): boolean {
+  if (GENERATED_NAMES.test(file)) return true;
+  if (source === undefined) return false;
+  // Generators put their marker at the top; a mention deep in hand-written code doesn't count
+  if (file.endsWith('.go')) {
+    const clause = source.search(/^package\s/m);
+    return GO_GENERATED.test(clause < 0 ? source : source.slice(0, clause));
+  }
+  return GENERATED_MARKER.test(source.slice(0, 1024));
+}
+
+export function createFilter(projectRoot: string, options: FilterOptions = {}): AnalysisFilter {
+  if (options.onlyExternal && options.onlyInternal) throw new Error('--only-external and --only-internal exclude each other');
+  const scope: DependencyScope = options.onlyExternal ? 'external' : options.onlyInternal ? 'internal' : 'all';
+  const include = options.include ?? [];
+  const exclude = options.exclude ?? [];
+  let index: GoModuleIndex | null = null;
+
+  const includesFile = (file: string, source?: string) => {
+    if (include.length > 0 && !include.some(glob => minimatch(file, glob, { matchBase: true }))) return false;
+    if (exclude.some(glob => minimatch(file, glob, { matchBase: true }))) return false;
+    return !options.excludeGenerated || !isGeneratedFile(file, source);
+  };
+
+  // Ours: a symbol ID, a project path, or an import path inside one of the project's modules
+  const isInternal = (target: string) => {
+    if (target.includes('::') || existsSync(join(projectRoot, target))) return true;
+    index ??= new GoModuleIndex(findGoModules(projectRoot));
+    return index.moduleForImport(target.split('@')[0]) !== null;
+  };
+
+  return {
+    scope,
+    includesFile,
+    includesTarget: (target) => scope === 'all' || (scope === 'internal') === isInternal(target),
+    active: include.length + exclude.length > 0 || Boolean(options.excludeGenerated) || scope !== 'all',
+  };
+}
+
+/** Drop findings in filtered files, and dependency findings outside the scope */
+export function filterLintResult(result: LintResult, filter: AnalysisFilter): LintResult {
+  if (!filter.active) return result;
+  const findings = result.findings.filter(f => {
+    if (f.file && !filter.includesFile(f.file)) return false;
+    if (f.target && !filter.includesTarget(f.target)) return false;
+    // Findings without a target are about the workspace itself
+    return f.target !== undefined || filter.scope !== 'external';
+  });
+  return { ...result, findings, summary: summarize(findings) };
+}
@@ -2,6 +2,9 @@ import { getParser } from './wasm-init.js';
 import { SymbolNode, SymbolEdge, ParsedFile, LanguageParser } from './types.js';
 import { existsSync, readFileSync, readdirSync } from 'fs';
 import { join, dirname, resolve } from 'path';
+import { logger } from '../utils/log.js';
+
+const log = logger('parser');
 
 interface Context {
   filePath: string;
@@ -219,6 +222,7 @@ function processTypeDeclaration(node: Parser.SyntaxNode, context: Context): void
                     kind: 'inherits',
                     filePath: context.filePath,
                     line: field.startPosition.row + 1,
+                    column: field.startPosition.column + 1,
                   });
                 }
               }
@@ -352,6 +356,7 @@ function processImportDeclaration(node: Parser.SyntaxNode, context: Context): vo
           kind: 'imports',
           filePath: context.filePath,
           line: importSpec.startPosition.row + 1,
+          column: importSpec.startPosition.column + 1,
         });
       }
     }
@@ -392,6 +397,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -417,7 +423,7 @@ function readGoModuleName(projectRoot: string): string | null {
           }
         }
       } catch (error) {
-        console.error(`Error reading go.mod: ${error}`);
+        log.warn('Error reading go.mod', { error: error });
       }
     }
     
@@ -474,7 +480,7 @@ function findGoFilesInDir(dir: string, projectRoot: string): string[] {
       return fullPath.substring(projectRoot.length + 1);
     });
   } catch (error) {
-    console.error(`[findGoFilesInDir] Error:`, error);
+    log.debug(`Error listing Go files in ${dir}`, { error: error });
     return [];
   }
 }
@@ -0,0 +1,60 @@
+import { posix } from 'path';
+
+/**
+ * Import statements found by regex, without a parse: for cheap passes over
+ * the whole tree (--since neighbors, third-party module edges) where
+ * loading tree-sitter for every file would cost more than the answer is
+ * worth. Comments and strings that look like imports can fool them.
+ */
+
+const GO_IMPORT_BLOCK = /^import\s*\(([\s\S]*?)^\)/gm;
+const GO_IMPORT_LINE = /^import\s+(?:[\w.]+\s+)?"([^"]+)"/gm;
+const QUOTED = /"([^"]+)"/g;
+const JS_RELATIVE_IMPORT = /(?:\bfrom\s*|\bimport\s*\(?\s*|\brequire\(\s*)['"](\.{1,2}\/[^'"]*)['"]/g;
+const JS_FILE = /\.(m|c)?[jt]sx?$/;
+
+export interface ScannedImport {
+  path: string;
+  /** 1-based position of the opening quote */
+  line: number;
+  column: number;
+}
+
+// 1-based line and column of an offset, from the offsets where lines start
+function positionAt(lineStarts: number[], offset: number): { line: number; column: number } {
+  let low = 0;
+  let high = lineStarts.length - 1;
+  while (low < high) {
+    const mid = (low + high + 1) >> 1;
+    if (lineStarts[mid] <= offset) low = mid;
+    else high = mid - 1;
+  }
+  return { line: low + 1, column: offset - lineStarts[low] + 1 };
+}
+
+/** Import specs of a Go source file, with where each path literal is */
+export function scanGoImportSpecs(source: string): ScannedImport[] {
+  const lineStarts = [0];
+  for (let i = source.indexOf('\n'); i >= 0; i = source.indexOf('\n', i + 1)) lineStarts.push(i + 1);
+  const specs: ScannedImport[] = [];
+  for (const block of source.matchAll(GO_IMPORT_BLOCK)) {
+    // The block's body ends right before the closing paren
+    const body = block.index! + block[0].length - 1 - block[1].length;
+    for (const quoted of block[1].matchAll(QUOTED)) specs.push({ path: quoted[1], ...positionAt(lineStarts, body + quoted.index!) });
+  }
+  for (const line of source.matchAll(GO_IMPORT_LINE)) {
+    specs.push({ path: line[1], ...positionAt(lineStarts, line.index! + line[0].length - line[1].length - 2) });
+  }
+  return specs.sort((a, b) => a.line - b.line || a.column - b.column);
+}
+
+/** Import paths of a Go source file */
+export function scanGoImports(source: string): string[] {
+  return scanGoImportSpecs(source).map(spec => spec.path);
+}
+
+/** Project-relative paths of a JS/TS file's relative imports ("./x", "../lib/y"), as written — no extension resolution */
+export function scanRelativeImports(file: string, source: string): string[] {
+  if (!JS_FILE.test(file)) return [];
+  return [...source.matchAll(JS_RELATIVE_IMPORT)].map(match => posix.normalize(posix.join(posix.dirname(file), match[1])).replace(/\/$/, ''));
+}
@@ -8,44 +8,109 @@ import { readFileSync, statSync } from 'fs';
 import { join, resolve } from 'path';
 import { scanDirectory } from '../utils/files.js';
 import { getParserForFile } from './detect.js';
-import { ParsedFile } from './types.js';
+import { ParsedFile, SymbolNode, SymbolEdge } from './types.js';
 import { minimatch } from 'minimatch';
 import { initParser } from './wasm-init.js';
+import { checkCancelled, yieldToEventLoop, type ProgressCallback } from '../utils/progress.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('parser');
 
 const MAX_FILE_SIZE = 1_000_000; // 1MB — files larger than this are likely generated
 
-function shouldParseFile(fullPath: string): boolean {
+/**
+ * Why a file found in the project was not parsed: excluded and filtered are
+ * what the caller asked for; the rest leave the graph incomplete.
+ */
+export type SkipReason = 'outside-project' | 'excluded' | 'filtered' | 'too-large' | 'unreadable' | 'no-parser' | 'error';
+
+function shouldParseFile(fullPath: string): SkipReason | null {
   try {
     const stats = statSync(fullPath);
     if (stats.size > MAX_FILE_SIZE) {
-      console.error(`[Parser] Skipping ${fullPath} — file too large (${(stats.size / 1024).toFixed(0)}KB)`);
-      return false;
+      log.warn(`Skipping ${fullPath} — file too large (${(stats.size / 1024).toFixed(0)}KB)`, { file: fullPath, bytes: stats.size });
+      return 'too-large';
     }
-    return true;
+    return null;
   } catch (error) {
-    return false;
+    return 'unreadable';
+  }
+}
+
+export interface ParseOptions {
+  exclude?: string[];
+  verbose?: boolean;
+  /** Abort parsing early — parseProject rejects with the signal's reason */
+  signal?: AbortSignal;
+  /** Parse only these project-relative files (see changeScope for --since) */
+  only?: Set<string>;
+  /** Skip files this rejects; gets the source, for generated-code markers (see createFilter) */
+  filter?: (file: string, source: string) => boolean;
+  /** Called before each file is parsed, and once more when parsing completes */
+  onProgress?: ProgressCallback;
+  /**
+   * Streaming callbacks, invoked as each file is parsed so callers can index
+   * results incrementally. Edges are reported as parsed — the target may belong
+   * to a file that has not been parsed yet (or to no file at all, for unresolved
+   * references), so consumers must tolerate dangling targets until parsing completes.
+   * An exception thrown from a callback aborts parseProject.
+   */
+  onNode?: (node: SymbolNode) => void;
+  onEdge?: (edge: SymbolEdge) => void;
+  /** Called once per parsed file, after onNode/onEdge for its contents */
+  onFile?: (file: ParsedFile) => void;
+  /** Called for each file that was not parsed, with the parse error for 'error' */
+  onSkip?: (file: string, reason: SkipReason, error?: unknown) => void;
+}
+
+function emitParsedFile(parsed: ParsedFile, options?: ParseOptions): void {
+  if (options?.onNode) {
+    for (const symbol of parsed.symbols) {
+      options.onNode(symbol);
+    }
+  }
+  if (options?.onEdge) {
+    for (const edge of parsed.edges) {
+      options.onEdge(edge);
+    }
   }
+  options?.onFile?.(parsed);
 }
 
 export async function parseProject(
   projectRoot: string,
-  options?: { exclude?: string[]; verbose?: boolean }
+  options?: ParseOptions
 ): Promise<ParsedFile[]> {
   // Initialize WASM parsers (no-op if already initialized)
   await initParser();
   
-  const files = scanDirectory(projectRoot);
+  const files = scanDirectory(projectRoot).filter(file => !options?.only || options.only.has(file));
   const parsedFiles: ParsedFile[] = [];
   let skippedFiles = 0;
   let errorFiles = 0;
+  // Per-file records are trace (-vv) unless the caller asked for verbose parsing
+  const detail = options?.verbose ? log.info : log.trace;
   
-  for (const file of files) {
+  for (let i = 0; i < files.length; i++) {
+    const file = files[i];
+
+    // Let Ctrl-C handlers run between batches of synchronous parsing
+    if (i % 25 === 0) {
+      await yieldToEventLoop();
+    }
+    checkCancelled(options?.signal);
+    options?.onProgress?.({ phase: 'parse', completed: i, total: files.length, item: file });
+
+    let parsed: ParsedFile | undefined;
+    let skipped: SkipReason | undefined;
+    let error: unknown;
     try {
       const fullPath = join(projectRoot, file);
       
       // Path containment check
       if (!resolve(fullPath).startsWith(resolve(projectRoot))) {
         skippedFiles++;
+        skipped = 'outside-project';
         continue;
       }
       
@@ -55,52 +120,64 @@ export async function parseProject(
           minimatch(file, pattern, { matchBase: true })
         );
         if (shouldExclude) {
-          if (options.verbose) {
-            console.error(`[Parser] Excluded: ${file}`);
-          }
+          detail(`Excluded: ${file}`, { file });
           skippedFiles++;
+          skipped = 'excluded';
           continue;
         }
       }
       
       // Skip large files
-      if (!shouldParseFile(fullPath)) {
+      const unparsable = shouldParseFile(fullPath);
+      if (unparsable) {
         skippedFiles++;
+        skipped = unparsable;
         continue;
       }
       
-      if (options?.verbose) {
-        console.error(`[Parser] Parsing: ${file}`);
-      }
+      detail(`Parsing: ${file}`, { file });
       
       // fullPath validated via resolve().startsWith() containment check above
       const sourceCode = readFileSync(fullPath, 'utf-8');
+      if (options?.filter && !options.filter(file, sourceCode)) {
+        detail(`Filtered: ${file}`, { file });
+        skippedFiles++;
+        skipped = 'filtered';
+        continue;
+      }
 
       const parser = getParserForFile(file, sourceCode);
       if (!parser) {
-        console.error(`No parser found for file: ${file}`);
+        log.debug(`No parser found for file: ${file}`, { file });
         skippedFiles++;
+        skipped = 'no-parser';
         continue;
       }
       
-      const parsed = parser.parseFile(file, sourceCode, projectRoot);
+      parsed = parser.parseFile(file, sourceCode, projectRoot);
       parsedFiles.push(parsed);
     } catch (err) {
       errorFiles++;
-      console.error(`Error parsing file ${file}:`, err instanceof Error ? err.message : err);
+      log.warn(`Error parsing file ${file}`, { file, error: err });
+      skipped = 'error';
+      error = err;
+    } finally {
+      // Out of the try (and run after its continues) so a failing callback isn't reported as a parse error
+      if (parsed) {
+        emitParsedFile(parsed, options);
+      } else if (skipped) {
+        options?.onSkip?.(file, skipped, error);
+      }
     }
   }
+
+  options?.onProgress?.({ phase: 'parse', completed: files.length, total: files.length });
   
-  if (options?.verbose || errorFiles > 0) {
-    console.error(`\n[Parser] Summary:`);
-    console.error(`  Parsed: ${parsedFiles.length} files`);
-    if (skippedFiles > 0) {
-      console.error(`  Skipped: ${skippedFiles} files`);
-    }
-    if (errorFiles > 0) {
-      console.error(`  Errors: ${errorFiles} files`);
-    }
-  }
+  const summary = `Parsed ${parsedFiles.length} files${skippedFiles > 0 ? `, skipped ${skippedFiles}` : ''}${errorFiles > 0 ? `, ${errorFiles} failed` : ''}`;
+  const counts = { parsed: parsedFiles.length, skipped: skippedFiles, errors: errorFiles };
+  if (errorFiles > 0) log.warn(summary, counts);
+  else if (options?.verbose) log.info(summary, counts);
+  else log.debug(summary, counts);
   
   return parsedFiles;
 }
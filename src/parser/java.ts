@@ -180,6 +180,7 @@ function processImportDeclaration(node: Parser.SyntaxNode, context: Context): vo
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
 
     // Map the simple name to the resolved file for call resolution
@@ -264,6 +265,7 @@ function processTypeDeclaration(
           kind: 'inherits',
           filePath: context.filePath,
           line: superclass.startPosition.row + 1,
+          column: superclass.startPosition.column + 1,
         });
       }
     }
@@ -313,6 +315,7 @@ function processInterfaceList(node: Parser.SyntaxNode, sourceId: string, context
             kind: 'implements',
             filePath: context.filePath,
             line: child.startPosition.row + 1,
+            column: child.startPosition.column + 1,
           });
         }
       }
@@ -552,6 +555,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -574,6 +578,7 @@ function processObjectCreation(node: Parser.SyntaxNode, context: Context): void
       kind: 'references',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 
@@ -203,6 +203,7 @@ function processClassDeclaration(node: Parser.SyntaxNode, context: Context): voi
               kind: 'extends',
               filePath: context.filePath,
               line: child.startPosition.row + 1,
+              column: child.startPosition.column + 1,
             });
           }
         }
@@ -337,6 +338,7 @@ function processRequireCall(declarator: Parser.SyntaxNode, callNode: Parser.Synt
         kind: 'imports',
         filePath: context.filePath,
         line: callNode.startPosition.row + 1,
+        column: callNode.startPosition.column + 1,
       });
     } else if (nameNode.type === 'object_pattern') {
       // const { validate, sanitize } = require('./utils');
@@ -367,6 +369,7 @@ function processRequireCall(declarator: Parser.SyntaxNode, callNode: Parser.Synt
           kind: 'imports',
           filePath: context.filePath,
           line: callNode.startPosition.row + 1,
+          column: callNode.startPosition.column + 1,
         });
       }
     }
@@ -410,6 +413,7 @@ function processImportStatement(node: Parser.SyntaxNode, context: Context): void
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
   
@@ -434,6 +438,7 @@ function processImportStatement(node: Parser.SyntaxNode, context: Context): void
           kind: 'imports',
           filePath: context.filePath,
           line: node.startPosition.row + 1,
+          column: node.startPosition.column + 1,
         });
       }
     }
@@ -454,6 +459,7 @@ function processImportStatement(node: Parser.SyntaxNode, context: Context): void
         kind: 'imports',
         filePath: context.filePath,
         line: node.startPosition.row + 1,
+        column: node.startPosition.column + 1,
       });
     }
   }
@@ -508,6 +514,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -530,6 +537,7 @@ function processNewExpression(node: Parser.SyntaxNode, context: Context): void {
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -571,6 +579,7 @@ function processJSXElement(node: Parser.SyntaxNode, context: Context): void {
       kind: 'references',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
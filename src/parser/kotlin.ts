@@ -166,6 +166,7 @@ function processImportHeader(node: Parser.SyntaxNode, context: Context): void {
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
 
     // Map simple name to resolved file for call resolution
@@ -354,6 +355,7 @@ function processDelegationSpecifiers(
           kind: edgeKind,
           filePath: context.filePath,
           line: child.startPosition.row + 1,
+          column: child.startPosition.column + 1,
         });
       }
     }
@@ -575,6 +577,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
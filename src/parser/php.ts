@@ -166,6 +166,7 @@ function processUseDeclaration(node: Parser.SyntaxNode, context: Context): void
         kind: 'imports',
         filePath: context.filePath,
         line: node.startPosition.row + 1,
+        column: node.startPosition.column + 1,
       });
 
       context.imports.set(simpleName, `${resolvedPath}::${parts[parts.length - 1]}`);
@@ -221,6 +222,7 @@ function processClassDeclaration(node: Parser.SyntaxNode, context: Context): voi
           kind: 'inherits',
           filePath: context.filePath,
           line: node.startPosition.row + 1,
+          column: node.startPosition.column + 1,
         });
       }
     }
@@ -242,6 +244,7 @@ function processClassDeclaration(node: Parser.SyntaxNode, context: Context): voi
             kind: 'implements',
             filePath: context.filePath,
             line: node.startPosition.row + 1,
+            column: node.startPosition.column + 1,
           });
         }
       }
@@ -539,6 +542,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -573,6 +577,7 @@ function processIncludeRequire(node: Parser.SyntaxNode, context: Context): void
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -160,6 +160,7 @@ function processClassDefinition(node: Parser.SyntaxNode, context: Context): void
             kind: 'inherits',
             filePath: context.filePath,
             line: arg.startPosition.row + 1,
+            column: arg.startPosition.column + 1,
           });
         }
       }
@@ -247,6 +248,7 @@ function processImportStatement(node: Parser.SyntaxNode, context: Context): void
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
   // Else: external import, skip
@@ -308,6 +310,7 @@ function processImportFromStatement(node: Parser.SyntaxNode, context: Context):
         kind: 'imports',
         filePath: context.filePath,
         line: node.startPosition.row + 1,
+        column: node.startPosition.column + 1,
       });
     }
   }
@@ -351,6 +354,7 @@ function processDecoratedDefinition(node: Parser.SyntaxNode, context: Context):
             kind: 'decorates',
             filePath: context.filePath,
             line: node.startPosition.row + 1,
+            column: node.startPosition.column + 1,
           });
         }
       }
@@ -394,6 +398,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
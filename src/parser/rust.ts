@@ -323,6 +323,7 @@ function processUseDeclaration(node: Parser.SyntaxNode, context: Context): void
       kind: 'imports',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
@@ -353,6 +354,7 @@ function processModItem(node: Parser.SyntaxNode, context: Context): void {
           kind: 'imports',
           filePath: context.filePath,
           line: node.startPosition.row + 1,
+          column: node.startPosition.column + 1,
         });
       }
     }
@@ -398,6 +400,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
     kind: 'calls',
     filePath: context.filePath,
     line: node.startPosition.row + 1,
+    column: node.startPosition.column + 1,
   });
 }
 
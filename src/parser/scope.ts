@@ -0,0 +1,82 @@
+import { existsSync, readFileSync } from 'fs';
+import { join } from 'path';
+import { scanDirectory } from '../utils/files.js';
+import { packageOf } from '../graph/model.js';
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+import { getChangedFiles, isGitRepo } from '../temporal/git.js';
+import { scanGoImports, scanRelativeImports } from './imports.js';
+
+/**
+ * The files `--since <ref>` parses: the packages touched since a git ref
+ * (committed, uncommitted and untracked changes) plus their immediate
+ * neighbors — the packages they import and the packages importing them.
+ * Neighbors come from a regex scan of import statements, not a parse: Go
+ * imports resolve through the project's go.mod module paths, JS/TS
+ * relative imports by path. Other languages contribute touched packages
+ * only.
+ */
+
+export interface ChangeScope {
+  ref: string;
+  /** Changed source files */
+  changed: string[];
+  /** Packages (directories) with changed files */
+  packages: string[];
+  /** Packages importing or imported by them */
+  neighbors: string[];
+  /** Project-relative files to parse */
+  files: Set<string>;
+}
+
+function importedPackages(file: string, source: string, index: GoModuleIndex, packages: Set<string>): string[] {
+  const found = new Set<string>();
+  if (file.endsWith('.go')) {
+    for (const path of scanGoImports(source)) {
+      const dir = index.dirForImport(path);
+      if (dir !== null) found.add(dir);
+    }
+  } else {
+    for (const target of scanRelativeImports(file, source)) {
+      // "./components" may be a directory with an index file, or a file without its extension
+      found.add(packages.has(target) ? target : packageOf(target));
+    }
+  }
+  return [...found];
+}
+
+export function changeScope(projectRoot: string, ref: string): ChangeScope {
+  if (!isGitRepo(projectRoot)) throw new Error('Not a git repository — --since scopes analysis to changes since a git ref');
+  const sources = scanDirectory(projectRoot);
+  const sourceSet = new Set(sources);
+  const packages = new Set(sources.map(packageOf));
+  // Deleted files still touch their package, if anything is left of it
+  const changed = getChangedFiles(projectRoot, ref).filter(f => sourceSet.has(f) || (!existsSync(join(projectRoot, f)) && packages.has(packageOf(f))));
+  const touched = new Set(changed.map(packageOf));
+
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  const neighbors = new Set<string>();
+  for (const file of sources) {
+    let source: string;
+    try {
+      source = readFileSync(join(projectRoot, file), 'utf-8');
+    } catch {
+      continue;
+    }
+    const from = packageOf(file);
+    for (const to of importedPackages(file, source, index, packages)) {
+      if (to === from || !packages.has(to)) continue;
+      if (touched.has(from)) neighbors.add(to);
+      if (touched.has(to)) neighbors.add(from);
+    }
+  }
+  for (const pkg of touched) neighbors.delete(pkg);
+
+  const scoped = new Set([...touched, ...neighbors]);
+  return {
+    ref,
+    changed,
+    packages: [...touched].sort(),
+    neighbors: [...neighbors].sort(),
+    files: new Set(sources.filter(f => scoped.has(packageOf(f)))),
+  };
+}
@@ -22,6 +22,8 @@ export interface SymbolNode {
   endLine: number;
   exported: boolean;
   scope?: string;      // Parent class/namespace if nested (e.g., "MyClass")
+  stableId?: string;   // Content-addressable hash of kind + path + signature (set by buildGraph)
+  observability?: string[]; // Go: logging/metrics/tracing libraries used (set by buildGraph)
 }
 
 export type EdgeKind =
@@ -32,7 +34,10 @@ export type EdgeKind =
   | 'inherits'       // Python: class inheritance
   | 'decorates'      // Python: decorator application
   | 'references'
-  | 'type_references';
+  | 'type_references'
+  | 'consumes'       // Go DI: a consumer receives a provider's result through wire/fx/dig
+  | 'shared-state'   // Go: a function reads or writes a package-level var that is written after init
+  | 'runtime';       // Go: a call recorded by depwiretrace while the program ran (depwire trace import)
 
 export interface SymbolEdge {
   source: string;      // Source symbol ID
@@ -40,6 +45,8 @@ export interface SymbolEdge {
   kind: EdgeKind;
   filePath: string;    // File where the reference occurs
   line: number;
+  column?: number;     // 1-based, where the parser knows it
+  stableId?: string;   // Hash of kind + source/target stable IDs (set on export)
 }
 
 export interface ParsedFile {
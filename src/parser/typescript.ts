@@ -166,6 +166,7 @@ function processClassDeclaration(node: Parser.SyntaxNode, context: Context): voi
               kind: 'extends',
               filePath: context.filePath,
               line: typeNode.startPosition.row + 1,
+              column: typeNode.startPosition.column + 1,
             });
           }
         }
@@ -186,6 +187,7 @@ function processClassDeclaration(node: Parser.SyntaxNode, context: Context): voi
               kind: 'implements',
               filePath: context.filePath,
               line: typeNode.startPosition.row + 1,
+              column: typeNode.startPosition.column + 1,
             });
           }
         }
@@ -438,6 +440,7 @@ function processImportStatement(node: Parser.SyntaxNode, context: Context): void
         kind: 'imports',
         filePath: context.filePath,
         line: node.startPosition.row + 1,
+        column: node.startPosition.column + 1,
       });
     }
   }
@@ -490,6 +493,7 @@ function processExportStatement(node: Parser.SyntaxNode, context: Context): void
           kind: 'imports',
           filePath: context.filePath,
           line: startLine,
+          column: node.startPosition.column + 1,
         });
       }
     }
@@ -530,6 +534,7 @@ function processCallExpression(node: Parser.SyntaxNode, context: Context): void
         kind: 'calls',
         filePath: context.filePath,
         line: node.startPosition.row + 1,
+        column: node.startPosition.column + 1,
       });
     }
   }
@@ -552,6 +557,7 @@ function processNewExpression(node: Parser.SyntaxNode, context: Context): void {
       kind: 'calls',
       filePath: context.filePath,
       line: node.startPosition.row + 1,
+      column: node.startPosition.column + 1,
     });
   }
 }
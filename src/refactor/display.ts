@@ -0,0 +1,108 @@
+import chalk from 'chalk';
+import type { MovePreview } from './move.js';
+import type { CycleBreakSuggestion } from './interfaces.js';
+
+export function formatMovePreview(preview: MovePreview, limit: number): string {
+  const lines: string[] = [];
+
+  lines.push('');
+  lines.push(chalk.bold('Depwire Move Preview'));
+  lines.push('');
+  lines.push(`  ${preview.from.importPath} → ${preview.to.importPath}`);
+  if (preview.movedPackages.length > 1) {
+    lines.push(chalk.dim(`  moves ${preview.movedPackages.length} packages (including subpackages)`));
+  }
+  lines.push('');
+
+  for (const warning of preview.warnings) {
+    lines.push(chalk.yellow(`  ⚠ ${warning}`));
+  }
+  if (preview.warnings.length > 0) lines.push('');
+
+  const files = new Set(preview.imports.map(i => i.file));
+  lines.push(chalk.bold(`Import statements to update (${preview.imports.length} in ${files.size} files)`));
+  if (preview.imports.length === 0) lines.push(chalk.dim('  none'));
+  for (const imp of preview.imports.slice(0, limit)) {
+    lines.push(`  ${imp.file}:${imp.line}  ${chalk.red(imp.oldPath)} → ${chalk.green(imp.newPath)}`);
+  }
+  if (preview.imports.length > limit) {
+    lines.push(chalk.dim(`  … ${preview.imports.length - limit} more (use --limit or --format json)`));
+  }
+  lines.push('');
+
+  lines.push(chalk.bold(`go.mod files (${preview.goModFiles.length})`));
+  if (preview.goModFiles.length === 0) lines.push(chalk.dim('  none'));
+  for (const ref of preview.goModFiles) {
+    lines.push(`  ${ref.file}:${ref.line}  ${ref.reason}`);
+  }
+  lines.push('');
+
+  lines.push(chalk.bold(`Build files (${preview.buildFiles.length})`));
+  if (preview.buildFiles.length === 0) lines.push(chalk.dim('  none'));
+  for (const ref of preview.buildFiles.slice(0, limit)) {
+    lines.push(`  ${ref.file}:${ref.line}  ${chalk.dim(ref.text)}`);
+  }
+  lines.push('');
+
+  if (preview.newCycles.length > 0) {
+    lines.push(chalk.red.bold(`New import cycles (${preview.newCycles.length}) — Go will refuse to build`));
+    for (const cycle of preview.newCycles) {
+      lines.push(`  ${[...cycle, cycle[0]].join(' → ')}`);
+    }
+  } else {
+    lines.push(chalk.green('✓ No new import cycles'));
+  }
+
+  if (preview.internalViolations.length > 0) {
+    lines.push('');
+    lines.push(chalk.red.bold(`internal/ import violations (${preview.internalViolations.length})`));
+    for (const v of preview.internalViolations) {
+      lines.push(`  ${v.file}:${v.line}  ${v.importer} may not import ${v.imported}`);
+    }
+  }
+  lines.push('');
+
+  return lines.join('\n');
+}
+
+export function formatCycleBreaks(suggestions: CycleBreakSuggestion[], showSkeletons: boolean): string {
+  const lines: string[] = [];
+
+  lines.push('');
+  lines.push(chalk.bold('Depwire Cycle Breaking'));
+  lines.push('');
+  if (suggestions.length === 0) {
+    lines.push(chalk.green('✓ No import cycles between packages'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  for (const s of suggestions) {
+    lines.push(chalk.bold(`Cycle: ${[...s.cycle, s.cycle[0]].join(' → ')}`));
+    lines.push(`  Cut ${chalk.cyan(s.backEdge.from)} → ${chalk.cyan(s.backEdge.to)}`);
+    if (s.interfaces.length === 0) {
+      lines.push(chalk.yellow('  No method calls cross this edge — move or inject the references below instead'));
+    }
+    for (const iface of s.interfaces) {
+      lines.push(`  interface ${chalk.green(iface.name)} in ${s.location.file} replacing ${iface.replaces}`);
+      for (const method of iface.methods) {
+        lines.push(chalk.dim(`    ${method.name}${method.signature}  (${method.usedAt.length} call${method.usedAt.length === 1 ? '' : 's'})`));
+      }
+      if (iface.leakedTypes.length > 0) {
+        lines.push(chalk.yellow(`    signatures still use ${iface.leakedTypes.join(', ')}`));
+      }
+    }
+    if (s.otherReferences.length > 0) {
+      lines.push(`  Also referenced: ${s.otherReferences.map(r => `${r.name} (${r.kind})`).join(', ')}`);
+    }
+    lines.push(s.complete ? chalk.green('  ✓ Extraction alone removes the import') : chalk.dim('  Extraction is a start — remaining references must move or be injected'));
+    if (showSkeletons) {
+      lines.push('');
+      lines.push(chalk.dim(`  // ${s.location.file}`));
+      for (const line of s.skeleton.trimEnd().split('\n')) lines.push(`  ${line}`);
+    }
+    lines.push('');
+  }
+
+  return lines.join('\n');
+}
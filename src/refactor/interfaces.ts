@@ -0,0 +1,249 @@
+import { DirectedGraph } from 'graphology';
+import type { Node } from 'web-tree-sitter';
+import { stronglyConnectedComponents } from '../graph/algorithms.js';
+import { walk, goDeclarations, type GoDeclaration } from '../golang/source.js';
+import { importQualifiers, qualifiedReferences, methodCalls } from '../golang/references.js';
+import { loadGoProject, toGoImportGraph, type GoPackage, type GoProject } from '../golang/packages.js';
+
+/**
+ * Suggestions for breaking Go package import cycles by extracting an interface.
+ *
+ * For each cycle, every import edge inside it is scored by how much of the
+ * imported package the importer actually uses. The cheapest edge whose
+ * removal splits the cycle is the back-edge; the methods the importer calls
+ * on the imported package's types become an interface declared on the
+ * importer's side (Go's "accept interfaces" idiom), so the import can go.
+ *
+ * Method calls are matched by name — without type checking, a call like
+ * x.Get() is attributed to a type from the imported package when that type
+ * is referenced in the same file and declares a Get method.
+ */
+
+export interface CrossReference {
+  name: string;
+  kind: GoDeclaration['kind'];
+  /** "file:line" of each use in the importing package */
+  usedAt: string[];
+}
+
+export interface SuggestedMethod {
+  name: string;
+  /** Parameters and results as declared on the concrete type */
+  signature: string;
+  usedAt: string[];
+}
+
+export interface SuggestedInterface {
+  name: string;
+  /** The concrete type in the imported package it stands in for */
+  replaces: string;
+  methods: SuggestedMethod[];
+  /** Types of the imported package that still appear in method signatures */
+  leakedTypes: string[];
+}
+
+export interface CycleBreakSuggestion {
+  /** Package import paths (or directories outside a module) forming the cycle */
+  cycle: string[];
+  backEdge: { from: string; to: string; fromDir: string; toDir: string };
+  interfaces: SuggestedInterface[];
+  /** Non-method references (funcs, consts, vars) that also cross the back-edge */
+  otherReferences: CrossReference[];
+  /** True when the interfaces account for every use of the imported package */
+  complete: boolean;
+  location: { dir: string; file: string; packageName: string };
+  skeleton: string;
+}
+
+interface EdgeUsage {
+  from: GoPackage;
+  to: GoPackage;
+  refs: Map<string, CrossReference>;
+  /** Method name → call sites, for calls not qualified by a package */
+  methodCalls: Map<string, string[]>;
+}
+
+export async function suggestCycleBreaks(projectRoot: string): Promise<CycleBreakSuggestion[]> {
+  const project = await loadGoProject(projectRoot);
+  const graph = toGoImportGraph(project);
+  const suggestions: CycleBreakSuggestion[] = [];
+
+  for (const component of stronglyConnectedComponents(graph)) {
+    if (component.length < 2) continue;
+    const members = new Set(component);
+
+    let best = null as { usage: EdgeUsage; splits: boolean; cost: number } | null;
+    graph.forEachOutEdge((edge, _attrs, source, target) => {
+      if (!members.has(source) || !members.has(target)) return;
+      const usage = edgeUsage(project, source, target);
+      const splits = removalSplits(graph, members, edge, source, target);
+      const cost = usage.refs.size;
+      if (!best || (splits && !best.splits) || (splits === best.splits && cost < best.cost)) {
+        best = { usage, splits, cost };
+      }
+    });
+    if (!best) continue;
+    suggestions.push(buildSuggestion(project, component, best.usage));
+  }
+
+  return suggestions;
+}
+
+/** Whether deleting one edge separates its endpoints into different components */
+function removalSplits(graph: DirectedGraph, members: Set<string>, edge: string, source: string, target: string): boolean {
+  const sub = new DirectedGraph();
+  for (const node of members) sub.addNode(node);
+  graph.forEachEdge((e, _attrs, s, t) => {
+    if (e !== edge && members.has(s) && members.has(t)) sub.mergeEdge(s, t);
+  });
+  return !stronglyConnectedComponents(sub).some(c => c.includes(source) && c.includes(target));
+}
+
+function edgeUsage(project: GoProject, fromDir: string, toDir: string): EdgeUsage {
+  const from = project.packages.get(fromDir)!;
+  const to = project.packages.get(toDir)!;
+  const qualifiers = importQualifiers(from, to.importPath ?? toDir, to.name);
+
+  const declarations = new Map<string, GoDeclaration>();
+  for (const file of to.files) {
+    for (const decl of goDeclarations(file)) {
+      if (decl.kind !== 'method' && decl.exported) declarations.set(decl.name, decl);
+    }
+  }
+
+  const refs = new Map<string, CrossReference>();
+  const calls = new Map<string, string[]>();
+  for (const file of from.files) {
+    for (const ref of qualifiedReferences(file, qualifiers)) {
+      if (!refs.has(ref.name)) refs.set(ref.name, { name: ref.name, kind: declarations.get(ref.name)?.kind ?? 'func', usedAt: [] });
+      refs.get(ref.name)!.usedAt.push(`${ref.file}:${ref.line}`);
+    }
+    for (const call of methodCalls(file, qualifiers)) {
+      if (!calls.has(call.name)) calls.set(call.name, []);
+      calls.get(call.name)!.push(`${call.file}:${call.line}`);
+    }
+  }
+
+  return { from, to, refs, methodCalls: calls };
+}
+
+function buildSuggestion(project: GoProject, component: string[], usage: EdgeUsage): CycleBreakSuggestion {
+  const { from, to, refs, methodCalls } = usage;
+  const toName = to.name || (to.importPath ?? to.dir).split('/').pop()!;
+  const taken = new Set<string>();
+  for (const file of from.files) for (const decl of goDeclarations(file)) taken.add(decl.name);
+
+  const methodsByType = new Map<string, GoDeclaration[]>();
+  for (const file of to.files) {
+    for (const decl of goDeclarations(file)) {
+      if (decl.kind !== 'method' || !decl.exported || !decl.receiver) continue;
+      if (!methodsByType.has(decl.receiver)) methodsByType.set(decl.receiver, []);
+      methodsByType.get(decl.receiver)!.push(decl);
+    }
+  }
+  const localTypes = new Set([...methodsByType.keys(), ...[...refs.values()].filter(r => r.kind === 'type' || r.kind === 'interface').map(r => r.name)]);
+
+  const interfaces: SuggestedInterface[] = [];
+  const covered = new Set<string>();
+  for (const ref of refs.values()) {
+    if (ref.kind !== 'type') continue;
+    const methods: SuggestedMethod[] = [];
+    const leaked = new Set<string>();
+    const files = new Set(ref.usedAt.map(at => at.slice(0, at.lastIndexOf(':'))));
+    for (const decl of methodsByType.get(ref.name) ?? []) {
+      // Only calls from files that mention the type count toward it
+      const calls = (methodCalls.get(decl.name) ?? []).filter(at => files.has(at.slice(0, at.lastIndexOf(':'))));
+      if (calls.length === 0) continue;
+      const signature = methodSignature(decl.node, toName, localTypes, leaked);
+      methods.push({ name: decl.name, signature, usedAt: calls });
+    }
+    if (methods.length === 0) continue;
+    covered.add(ref.name);
+    let name = ref.name;
+    if (taken.has(name)) name = `${ref.name}API`;
+    taken.add(name);
+    interfaces.push({
+      name,
+      replaces: `${toName}.${ref.name}`,
+      methods: methods.sort((a, b) => a.name.localeCompare(b.name)),
+      leakedTypes: [...leaked].sort(),
+    });
+  }
+
+  const otherReferences = [...refs.values()]
+    .filter(r => !covered.has(r.name))
+    .sort((a, b) => a.name.localeCompare(b.name));
+  const cycle = component.map(dir => project.packages.get(dir)?.importPath ?? dir);
+  const fromPath = from.importPath ?? from.dir;
+  const toPath = to.importPath ?? to.dir;
+  const file = `${from.dir === '.' ? '' : `${from.dir}/`}${toName}_iface.go`;
+
+  return {
+    cycle,
+    backEdge: { from: fromPath, to: toPath, fromDir: from.dir, toDir: to.dir },
+    interfaces,
+    otherReferences,
+    complete: otherReferences.length === 0 && interfaces.every(i => i.leakedTypes.length === 0),
+    location: { dir: from.dir, file, packageName: from.name },
+    skeleton: renderSkeleton(from.name, toPath, cycle, interfaces, otherReferences),
+  };
+}
+
+/** Parameter and result text of a method, with the imported package's own types qualified */
+function methodSignature(method: Node, pkgName: string, localTypes: Set<string>, leaked: Set<string>): string {
+  const qualify = (node: Node | null) => {
+    if (!node) return '';
+    let text = '';
+    let last = node.startIndex;
+    const base = node.startIndex;
+    const source = node.text;
+    walk(node, (child) => {
+      if (child.type === 'qualified_type') return false;
+      if (child.type === 'type_identifier' && localTypes.has(child.text)) {
+        text += source.slice(last - base, child.startIndex - base) + `${pkgName}.${child.text}`;
+        last = child.endIndex;
+        leaked.add(child.text);
+      }
+    });
+    return text + source.slice(last - base);
+  };
+  const params = qualify(method.childForFieldName('parameters'));
+  const result = qualify(method.childForFieldName('result'));
+  return result ? `${params} ${result}` : params;
+}
+
+function renderSkeleton(
+  packageName: string,
+  importedPath: string,
+  cycle: string[],
+  interfaces: SuggestedInterface[],
+  otherReferences: CrossReference[]
+): string {
+  const lines: string[] = [];
+  lines.push(`package ${packageName}`);
+  lines.push('');
+  lines.push(`// Interfaces extracted to remove the import of ${importedPath},`);
+  lines.push(`// breaking the cycle ${[...cycle, cycle[0]].join(' -> ')}.`);
+  lines.push('// Accept these instead of the concrete types and wire the implementations in from the caller.');
+  for (const iface of interfaces) {
+    lines.push('');
+    lines.push(`// ${iface.name} is the subset of ${iface.replaces} this package uses.`);
+    if (iface.leakedTypes.length > 0) {
+      lines.push(`// TODO: signatures still mention ${iface.leakedTypes.join(', ')} — move or abstract them too.`);
+    }
+    lines.push(`type ${iface.name} interface {`);
+    for (const method of iface.methods) {
+      lines.push(`\t${method.name}${method.signature}`);
+    }
+    lines.push('}');
+  }
+  if (otherReferences.length > 0) {
+    lines.push('');
+    lines.push(`// TODO: also referenced from ${importedPath} — inject or move before dropping the import:`);
+    for (const ref of otherReferences) {
+      lines.push(`//   ${ref.name} (${ref.kind}, ${ref.usedAt.length} use${ref.usedAt.length === 1 ? '' : 's'})`);
+    }
+  }
+  lines.push('');
+  return lines.join('\n');
+}
@@ -0,0 +1,247 @@
+import { readFileSync, readdirSync, lstatSync } from 'fs';
+import { join, relative } from 'path';
+import { DirectedGraph } from 'graphology';
+import { findCycles } from '../graph/algorithms.js';
+import { loadGoProject, toGoImportGraph, internalImportAllowed, type GoProject } from '../golang/packages.js';
+
+export interface ImportUpdate {
+  file: string;
+  line: number;
+  column: number;
+  oldPath: string;
+  newPath: string;
+}
+
+export interface FileReference {
+  file: string;
+  line: number;
+  text: string;
+  reason: string;
+}
+
+export interface MovePreview {
+  from: { importPath: string; dir: string | null };
+  to: { importPath: string; dir: string | null };
+  /** Packages moved — the package itself and its subpackages */
+  movedPackages: string[];
+  imports: ImportUpdate[];
+  goModFiles: FileReference[];
+  buildFiles: FileReference[];
+  /** Package-level import cycles that exist only after the move */
+  newCycles: string[][];
+  /** Imports that Go's internal-package rule would reject after the move */
+  internalViolations: Array<{ importer: string; imported: string; file: string; line: number }>;
+  warnings: string[];
+}
+
+const BUILD_FILE_PATTERN = /^(BUILD|BUILD\.bazel|WORKSPACE|MODULE\.bazel|Makefile|GNUmakefile|Dockerfile.*|.*\.Dockerfile|Taskfile\.ya?ml|justfile|go\.work|.*\.bzl|.*\.mk|\.goreleaser\.ya?ml|skaffold\.ya?ml|docker-compose\.ya?ml|compose\.ya?ml)$/;
+const SKIP_DIRS = new Set(['node_modules', 'vendor', '.git', 'dist']);
+
+/**
+ * Preview moving a Go package (and its subpackages) to a new import path:
+ * every import that must be rewritten, go.mod and build files that mention the
+ * old location, and the import cycles and internal-rule violations the move
+ * would introduce. Nothing is written.
+ */
+export async function previewPackageMove(projectRoot: string, from: string, to: string): Promise<MovePreview> {
+  const project = await loadGoProject(projectRoot, { tests: true });
+  if (project.modules.modules.length === 0) {
+    throw new Error('No go.mod found — package moves are only supported for Go modules');
+  }
+
+  const fromPath = toImportPath(project, from);
+  const toPath = toImportPath(project, to);
+  if (fromPath === toPath) {
+    throw new Error('Source and destination are the same package');
+  }
+  if (toPath.startsWith(`${fromPath}/`)) {
+    throw new Error('Cannot move a package into its own subpackage');
+  }
+
+  const rewrite = (path: string): string | null =>
+    path === fromPath ? toPath : path.startsWith(`${fromPath}/`) ? toPath + path.slice(fromPath.length) : null;
+
+  const movedPackages = [...project.packages.values()]
+    .filter(p => p.importPath && rewrite(p.importPath) !== null)
+    .map(p => p.importPath!)
+    .sort();
+  const warnings: string[] = [];
+  if (movedPackages.length === 0) {
+    throw new Error(`Package not found: ${fromPath}`);
+  }
+
+  const fromDir = project.modules.dirForImport(fromPath);
+  const toDir = project.modules.dirForImport(toPath);
+  if (!toDir) {
+    warnings.push(`${toPath} is outside every module in this project — the code must be added to that module, and importers need a require for it`);
+  } else if (project.packages.has(toDir)) {
+    warnings.push(`${toPath} already exists — the move merges two packages; package names and identifiers may clash`);
+  }
+
+  // Import statements to rewrite
+  const imports: ImportUpdate[] = [];
+  for (const pkg of project.packages.values()) {
+    for (const table of [pkg.imports, pkg.testImports]) {
+      for (const [path, uses] of table) {
+        const newPath = rewrite(path);
+        if (!newPath) continue;
+        for (const use of uses) {
+          imports.push({ file: use.file, line: use.line, column: use.column, oldPath: path, newPath });
+        }
+      }
+    }
+  }
+  imports.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+
+  // Internal-rule checks compare pre- and post-move paths on both sides
+  const internalViolations: MovePreview['internalViolations'] = [];
+  for (const pkg of project.packages.values()) {
+    if (!pkg.importPath) continue;
+    const importerAfter = rewrite(pkg.importPath) ?? pkg.importPath;
+    for (const table of [pkg.imports, pkg.testImports]) {
+      for (const [path, uses] of table) {
+        if (!project.modules.moduleForImport(path)) continue;
+        const importedAfter = rewrite(path) ?? path;
+        const changed = importerAfter !== pkg.importPath || importedAfter !== path;
+        if (changed && internalImportAllowed(pkg.importPath, path) && !internalImportAllowed(importerAfter, importedAfter)) {
+          internalViolations.push({ importer: importerAfter, imported: importedAfter, file: uses[0].file, line: uses[0].line });
+        }
+      }
+    }
+  }
+
+  const fromModule = project.modules.moduleForImport(fromPath);
+  const toModule = project.modules.moduleForImport(toPath);
+  const crossModule = fromModule?.path !== toModule?.path;
+
+  const goModFiles: FileReference[] = [];
+  for (const mod of project.modules.modules) {
+    const importsMoved = imports.some(i => project.modules.moduleForDir(dirOf(i.file))?.path === mod.path);
+    if (crossModule && toModule && importsMoved && mod.path !== toModule.path &&
+        !mod.mod.require.some(r => r.path === toModule.path)) {
+      const fromRequire = mod.mod.require.find(r => r.path === fromModule?.path);
+      goModFiles.push({
+        file: mod.goModFile,
+        line: fromRequire?.line ?? 1,
+        text: fromRequire ? `require ${fromRequire.path} ${fromRequire.version}` : `module ${mod.path}`,
+        reason: `add require ${toModule.path} — this module imports the moved package`,
+      });
+    }
+    for (const rep of mod.mod.replace) {
+      if (rewrite(rep.oldPath) || rewrite(rep.newPath)) {
+        goModFiles.push({ file: mod.goModFile, line: rep.line, text: `replace ${rep.oldPath} => ${rep.newPath}`, reason: 'replace directive mentions the moved path' });
+      }
+    }
+    if (crossModule && toModule && mod.path === toModule.path) {
+      goModFiles.push({ file: mod.goModFile, line: 1, text: `module ${mod.path}`, reason: 'destination module — add requires for the moved package\'s dependencies' });
+    }
+  }
+
+  const buildFiles = findBuildFileReferences(projectRoot, fromPath, fromDir);
+  const newCycles = cyclesIntroduced(project, rewrite);
+
+  return {
+    from: { importPath: fromPath, dir: fromDir },
+    to: { importPath: toPath, dir: toDir },
+    movedPackages,
+    imports,
+    goModFiles,
+    buildFiles,
+    newCycles,
+    internalViolations,
+    warnings,
+  };
+}
+
+function dirOf(file: string): string {
+  const index = file.lastIndexOf('/');
+  return index < 0 ? '.' : file.slice(0, index);
+}
+
+/** Accept an import path or a project-relative directory */
+function toImportPath(project: GoProject, value: string): string {
+  const cleaned = value.replace(/\/+$/, '').replace(/^\.\//, '');
+  if (project.modules.moduleForImport(cleaned)) return cleaned;
+  const fromDir = project.modules.importForDir(cleaned || '.');
+  if (fromDir && project.packages.has(cleaned || '.')) return fromDir;
+  // Unknown locations are taken as import paths (e.g. a destination in another module)
+  return cleaned;
+}
+
+/** Cycles in the package import graph after the move that don't exist before it */
+function cyclesIntroduced(project: GoProject, rewrite: (path: string) => string | null): string[][] {
+  const before = toGoImportGraph(project);
+  const nameAfter = (dir: string) => {
+    const path = before.getNodeAttribute(dir, 'importPath') ?? dir;
+    return rewrite(path) ?? path;
+  };
+  // Cycles are compared by member set, expressed in post-move names
+  const key = (names: string[]) => [...names].sort().join('\n');
+  const existing = new Set(findCycles(before).map(cycle => key(cycle.map(nameAfter))));
+
+  const after = new DirectedGraph();
+  before.forEachNode((dir) => {
+    const name = nameAfter(dir);
+    if (!after.hasNode(name)) after.addNode(name);
+  });
+  before.forEachEdge((_edge, _attrs, source, target) => {
+    const s = nameAfter(source);
+    const t = nameAfter(target);
+    if (s !== t) after.mergeEdge(s, t);
+  });
+
+  return findCycles(after).filter(cycle => !existing.has(key(cycle)));
+}
+
+/** Lines in build and deployment files that mention the package's import path or directory */
+function findBuildFileReferences(projectRoot: string, importPath: string, dir: string | null): FileReference[] {
+  const refs: FileReference[] = [];
+  const needles = [importPath];
+  if (dir && dir !== '.') needles.push(`//${dir}`, `./${dir}`, `${dir}/`);
+
+  const visit = (absDir: string) => {
+    let entries: string[];
+    try {
+      entries = readdirSync(absDir);
+    } catch {
+      return;
+    }
+    for (const entry of entries) {
+      if (SKIP_DIRS.has(entry)) continue;
+      const full = join(absDir, entry);
+      let isDir = false;
+      try {
+        isDir = lstatSync(full).isDirectory();
+      } catch {
+        continue;
+      }
+      if (isDir) {
+        if (!entry.startsWith('.') || entry === '.github') visit(full);
+        continue;
+      }
+      const inCi = relative(projectRoot, full).startsWith('.github/');
+      if (!BUILD_FILE_PATTERN.test(entry) && !(inCi && /\.ya?ml$/.test(entry))) continue;
+
+      let content: string;
+      try {
+        content = readFileSync(full, 'utf-8');
+      } catch {
+        continue;
+      }
+      content.split('\n').forEach((line, index) => {
+        const needle = needles.find(n => line.includes(n));
+        if (needle) {
+          refs.push({
+            file: relative(projectRoot, full).split('\\').join('/'),
+            line: index + 1,
+            text: line.trim(),
+            reason: needle === importPath ? 'mentions the import path' : 'mentions the package directory',
+          });
+        }
+      });
+    }
+  };
+
+  visit(projectRoot);
+  return refs;
+}
@@ -0,0 +1,117 @@
+import { describe, it } from 'node:test';
+import assert from 'node:assert';
+import { existsSync, mkdtempSync, readFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { readZip, readTar, readArchive, unsafeEntryName, extractEntries, commonPrefix } from './archive.js';
+
+// A zip of stored (uncompressed) entries; readZip doesn't check CRCs
+function createZip(files: Array<[string, string]>): Buffer {
+  const locals: Buffer[] = [];
+  const centrals: Buffer[] = [];
+  let offset = 0;
+  for (const [name, content] of files) {
+    const nameBytes = Buffer.from(name, 'utf-8');
+    const data = Buffer.from(content, 'utf-8');
+    const local = Buffer.alloc(30);
+    local.writeUInt32LE(0x04034b50, 0);
+    local.writeUInt32LE(data.length, 18);
+    local.writeUInt32LE(data.length, 22);
+    local.writeUInt16LE(nameBytes.length, 26);
+    const central = Buffer.alloc(46);
+    central.writeUInt32LE(0x02014b50, 0);
+    central.writeUInt32LE(data.length, 20);
+    central.writeUInt32LE(data.length, 24);
+    central.writeUInt16LE(nameBytes.length, 28);
+    central.writeUInt32LE(offset, 42);
+    locals.push(local, nameBytes, data);
+    centrals.push(central, nameBytes);
+    offset += local.length + nameBytes.length + data.length;
+  }
+  const directory = Buffer.concat(centrals);
+  const eocd = Buffer.alloc(22);
+  eocd.writeUInt32LE(0x06054b50, 0);
+  eocd.writeUInt16LE(files.length, 8);
+  eocd.writeUInt16LE(files.length, 10);
+  eocd.writeUInt32LE(directory.length, 12);
+  eocd.writeUInt32LE(offset, 16);
+  return Buffer.concat([...locals, directory, eocd]);
+}
+
+// A ustar archive; type is the typeflag ('0' file, '1' hard link, '2' symlink, '5' directory)
+function createTar(entries: Array<{ name: string; type?: string; content?: string; link?: string }>): Buffer {
+  const blocks: Buffer[] = [];
+  for (const entry of entries) {
+    const data = Buffer.from(entry.content ?? '', 'utf-8');
+    const header = Buffer.alloc(512);
+    header.write(entry.name, 0, 100, 'utf-8');
+    header.write('0000644\0', 100, 'latin1');
+    header.write(`${data.length.toString(8).padStart(11, '0')}\0`, 124, 'latin1');
+    header.write(entry.type ?? '0', 156, 'latin1');
+    if (entry.link) header.write(entry.link, 157, 100, 'utf-8');
+    header.write('ustar\0' + '00', 257, 'latin1');
+    blocks.push(header, data, Buffer.alloc((512 - (data.length % 512)) % 512));
+  }
+  return Buffer.concat([...blocks, Buffer.alloc(1024)]);
+}
+
+describe('remote archives', () => {
+  it('unsafeEntryName should reject names that escape the extraction directory', () => {
+    assert.strictEqual(unsafeEntryName('../evil.go'), 'contains ..');
+    assert.strictEqual(unsafeEntryName('example.com/m@v1.0.0/../../evil.go'), 'contains ..');
+    assert.strictEqual(unsafeEntryName('/etc/passwd'), 'is absolute');
+    assert.strictEqual(unsafeEntryName('C:/Windows/evil.go'), 'is absolute');
+    assert.strictEqual(unsafeEntryName('dir\\..\\evil.go'), 'contains a backslash');
+    assert.strictEqual(unsafeEntryName('evil.go\0.txt'), 'contains a NUL byte');
+    assert.strictEqual(unsafeEntryName('example.com/m@v1.0.0/a..b/c.go'), null);
+  });
+
+  it('extractEntries should refuse an unsafe entry and leave nothing behind', () => {
+    const dir = mkdtempSync(join(tmpdir(), 'depwire-archive-'));
+    try {
+      const dest = join(dir, 'out');
+      assert.throws(
+        () => extractEntries([{ name: 'm/ok.go', data: Buffer.from('package m\n') }, { name: 'm/../../evil.go', data: Buffer.from('x') }], dest, 'm/'),
+        /contains \.\./,
+      );
+      assert.ok(!existsSync(dest));
+      assert.ok(!existsSync(join(dir, 'evil.go')));
+
+      assert.strictEqual(extractEntries([{ name: 'm/a/ok.go', data: Buffer.from('package a\n') }], dest, 'm/'), 1);
+      assert.strictEqual(readFileSync(join(dest, 'a', 'ok.go'), 'utf-8'), 'package a\n');
+    } finally {
+      rmSync(dir, { recursive: true, force: true });
+    }
+  });
+
+  it('readTar should drop symlinks and hard links', () => {
+    const tar = createTar([
+      { name: 'repo-main/', type: '5' },
+      { name: 'repo-main/main.go', content: 'package main\n' },
+      { name: 'repo-main/passwd', type: '2', link: '/etc/passwd' },
+      { name: 'repo-main/shadow', type: '1', link: '/etc/shadow' },
+    ]);
+    const entries = readTar(tar);
+
+    assert.deepStrictEqual(entries.map(e => e.name), ['repo-main/main.go']);
+    assert.strictEqual(entries[0].data.toString('utf-8'), 'package main\n');
+    assert.deepStrictEqual(readArchive(tar).map(e => e.name), ['repo-main/main.go']);
+  });
+
+  it('readTar should enforce the unpacked size cap', () => {
+    const tar = createTar([{ name: 'big.go', content: 'x'.repeat(2048) }]);
+    assert.throws(() => readTar(tar, 1024), /unpacks to more than/);
+  });
+
+  it('readZip should read a module zip and find its prefix', () => {
+    const zip = createZip([
+      ['example.com/tiny@v1.0.0/go.mod', 'module example.com/tiny\n'],
+      ['example.com/tiny@v1.0.0/tiny.go', 'package tiny\n'],
+    ]);
+    const entries = readArchive(zip);
+
+    assert.deepStrictEqual(readZip(zip).map(e => e.name), entries.map(e => e.name));
+    assert.deepStrictEqual(entries.map(e => e.data.toString('utf-8')), ['module example.com/tiny\n', 'package tiny\n']);
+    assert.strictEqual(commonPrefix(entries), 'example.com/tiny@v1.0.0/');
+  });
+});
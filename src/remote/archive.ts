@@ -0,0 +1,175 @@
+import { inflateRawSync, gunzipSync } from 'zlib';
+import { mkdirSync, mkdtempSync, writeFileSync, renameSync, rmSync, existsSync } from 'fs';
+import { dirname, join } from 'path';
+
+/**
+ * Reading module zips and source tarballs (.tar, .tar.gz) without unzip or
+ * tar on PATH. Entries are validated the way the go command validates
+ * module zips — no absolute paths, no "..", no backslashes, a 500 MB cap on
+ * the unpacked size — and links in tarballs are skipped, so an archive
+ * can't write outside the directory it is extracted to.
+ */
+
+export interface ArchiveEntry {
+  /** Slash-separated path inside the archive */
+  name: string;
+  data: Buffer;
+}
+
+/** Same cap as the go command's module zips */
+export const MAX_UNPACKED_SIZE = 500 << 20;
+
+const EOCD = 0x06054b50;
+const CENTRAL = 0x02014b50;
+const LOCAL = 0x04034b50;
+
+export function readZip(zip: Buffer): ArchiveEntry[] {
+  // The end-of-central-directory record is in the last 64 KB (it may be followed by a comment)
+  let eocd = -1;
+  for (let i = zip.length - 22; i >= Math.max(0, zip.length - 65557); i--) {
+    if (zip.readUInt32LE(i) === EOCD) {
+      eocd = i;
+      break;
+    }
+  }
+  if (eocd < 0) throw new Error('Not a zip archive');
+  const count = zip.readUInt16LE(eocd + 10);
+  let offset = zip.readUInt32LE(eocd + 16);
+  if (count === 0xffff || offset === 0xffffffff) throw new Error('Zip64 archives are not supported');
+
+  const entries: ArchiveEntry[] = [];
+  let unpacked = 0;
+  for (let i = 0; i < count; i++) {
+    if (zip.readUInt32LE(offset) !== CENTRAL) throw new Error('Corrupt zip central directory');
+    const flags = zip.readUInt16LE(offset + 8);
+    const method = zip.readUInt16LE(offset + 10);
+    const compressedSize = zip.readUInt32LE(offset + 20);
+    const size = zip.readUInt32LE(offset + 24);
+    const nameLength = zip.readUInt16LE(offset + 28);
+    const extraLength = zip.readUInt16LE(offset + 30);
+    const commentLength = zip.readUInt16LE(offset + 32);
+    const localOffset = zip.readUInt32LE(offset + 42);
+    const name = zip.toString('utf-8', offset + 46, offset + 46 + nameLength);
+    offset += 46 + nameLength + extraLength + commentLength;
+
+    if (name.endsWith('/')) continue;
+    if (flags & 1) throw new Error(`${name} is encrypted`);
+    unpacked += size;
+    if (unpacked > MAX_UNPACKED_SIZE) throw new Error(`Archive unpacks to more than ${MAX_UNPACKED_SIZE >> 20} MB`);
+
+    if (zip.readUInt32LE(localOffset) !== LOCAL) throw new Error(`Corrupt zip entry ${name}`);
+    const start = localOffset + 30 + zip.readUInt16LE(localOffset + 26) + zip.readUInt16LE(localOffset + 28);
+    const raw = zip.subarray(start, start + compressedSize);
+    let data: Buffer;
+    if (method === 0) data = Buffer.from(raw);
+    else if (method === 8) data = inflateRawSync(raw, { maxOutputLength: Math.max(size, 1) });
+    else throw new Error(`${name} uses unsupported compression method ${method}`);
+    if (data.length !== size) throw new Error(`${name} is truncated`);
+    entries.push({ name, data });
+  }
+  return entries;
+}
+
+function tarString(block: Buffer, start: number, length: number): string {
+  const end = block.indexOf(0, start);
+  return block.toString('utf-8', start, end >= 0 && end < start + length ? end : start + length);
+}
+
+// PAX extended headers: "<length> <key>=<value>\n" records
+function paxPath(data: Buffer): string | undefined {
+  for (const record of data.toString('utf-8').split('\n')) {
+    const match = /^\d+ path=(.*)$/.exec(record);
+    if (match) return match[1];
+  }
+  return undefined;
+}
+
+export function readTar(tar: Buffer, maxSize = MAX_UNPACKED_SIZE): ArchiveEntry[] {
+  const entries: ArchiveEntry[] = [];
+  let offset = 0;
+  let unpacked = 0;
+  let longName: string | undefined;
+  while (offset + 512 <= tar.length) {
+    const header = tar.subarray(offset, offset + 512);
+    if (header.every(b => b === 0)) break;
+    const size = parseInt(tarString(header, 124, 12).trim() || '0', 8);
+    if (Number.isNaN(size)) throw new Error('Corrupt tar header');
+    const type = String.fromCharCode(header[156] || 48);
+    const data = tar.subarray(offset + 512, offset + 512 + size);
+    offset += 512 + Math.ceil(size / 512) * 512;
+
+    if (type === 'x' || type === 'L') {
+      // The next entry's real name (PAX or GNU long name)
+      longName = type === 'x' ? paxPath(data) : tarString(data, 0, data.length);
+      continue;
+    }
+    const prefix = header.toString('latin1', 257, 262) === 'ustar' ? tarString(header, 345, 155) : '';
+    const name = longName ?? (prefix ? `${prefix}/${tarString(header, 0, 100)}` : tarString(header, 0, 100));
+    longName = undefined;
+    if (type !== '0' && type !== '7') continue;  // directories, links, devices, global headers
+    unpacked += size;
+    if (unpacked > maxSize) throw new Error(`Archive unpacks to more than ${maxSize >> 20} MB`);
+    if (data.length !== size) throw new Error(`${name} is truncated`);
+    entries.push({ name: name.replace(/^\.\//, ''), data: Buffer.from(data) });
+  }
+  return entries;
+}
+
+/** Entries of a zip, tar or gzipped tar, told apart by their contents rather than the file name */
+export function readArchive(data: Buffer): ArchiveEntry[] {
+  if (data.length >= 4 && data.readUInt32LE(0) === LOCAL) return readZip(data);
+  if (data.length >= 2 && data[0] === 0x1f && data[1] === 0x8b) {
+    return readTar(gunzipSync(data, { maxOutputLength: MAX_UNPACKED_SIZE + (1 << 20) }));
+  }
+  if (data.length >= 262 && data.toString('latin1', 257, 262) === 'ustar') return readTar(data);
+  throw new Error('Not a zip, tar or tar.gz archive');
+}
+
+/** The directory every entry is under ("example.com/m@v1.2.3/", "repo-main/"), or '' */
+export function commonPrefix(entries: ArchiveEntry[]): string {
+  if (entries.length === 0) return '';
+  const first = entries[0].name;
+  // Module zip prefixes contain slashes of their own: use the segment up to "@version/"
+  const moduleMatch = /^(.+?@[^/]+\/)/.exec(first);
+  const candidates = [moduleMatch?.[1], first.includes('/') ? `${first.split('/')[0]}/` : undefined].filter((p): p is string => !!p);
+  return candidates.find(prefix => entries.every(e => e.name.startsWith(prefix))) ?? '';
+}
+
+/** Why an entry name is unsafe to extract, or null */
+export function unsafeEntryName(name: string): string | null {
+  if (name.includes('\\')) return 'contains a backslash';
+  if (name.startsWith('/') || /^[A-Za-z]:/.test(name)) return 'is absolute';
+  if (name.split('/').some(part => part === '..')) return 'contains ..';
+  if (name.includes('\0')) return 'contains a NUL byte';
+  return null;
+}
+
+/**
+ * Write entries under dest, dropping `prefix` from each name. Extraction
+ * goes to a sibling directory first, so dest is either complete or absent.
+ */
+export function extractEntries(entries: ArchiveEntry[], dest: string, prefix = ''): number {
+  // A fresh directory of our own next to dest: nothing else can have planted links in it
+  mkdirSync(dirname(dest), { recursive: true });
+  const staging = mkdtempSync(`${dest}.tmp-`);
+  let written = 0;
+  try {
+    for (const entry of entries) {
+      const problem = unsafeEntryName(entry.name);
+      if (problem) throw new Error(`Archive entry ${entry.name} ${problem}`);
+      if (!entry.name.startsWith(prefix)) throw new Error(`Archive entry ${entry.name} is outside ${prefix}`);
+      const relative = entry.name.slice(prefix.length);
+      if (!relative) continue;
+      const target = join(staging, relative);
+      mkdirSync(dirname(target), { recursive: true });
+      writeFileSync(target, entry.data);
+      written++;
+    }
+    if (existsSync(dest)) rmSync(dest, { recursive: true, force: true });
+    renameSync(staging, dest);
+  } catch (err) {
+    rmSync(staging, { recursive: true, force: true });
+    throw err;
+  }
+  return written;
+}
@@ -0,0 +1,67 @@
+import { readFileSync } from 'fs';
+import { join } from 'path';
+import { homedir } from 'os';
+
+/**
+ * Credentials for module downloads, found where the go command finds them:
+ * the .netrc file named by NETRC, or ~/.netrc (~/_netrc on Windows). Proxy
+ * and checksum-database requests send them as HTTP basic auth to the
+ * matching host.
+ */
+
+export interface NetrcEntry {
+  machine: string;
+  login: string;
+  password: string;
+}
+
+export function parseNetrc(content: string): NetrcEntry[] {
+  const entries: NetrcEntry[] = [];
+  const tokens = content.split(/\s+/).filter(Boolean);
+  let current: Partial<NetrcEntry> | null = null;
+  for (let i = 0; i < tokens.length; i++) {
+    const token = tokens[i];
+    if (token === 'machine' || token === 'default') {
+      if (current?.machine && current.login !== undefined && current.password !== undefined) entries.push(current as NetrcEntry);
+      // "default" applies to any machine; the go command ignores it, and so do we
+      current = token === 'machine' ? { machine: tokens[++i] } : null;
+    } else if (token === 'macdef') {
+      // Macro definitions run to the next blank line, which splitting has erased; stop here
+      break;
+    } else if (current && (token === 'login' || token === 'password')) {
+      current[token] = tokens[++i];
+    } else if (token === 'account') {
+      i++;
+    }
+  }
+  if (current?.machine && current.login !== undefined && current.password !== undefined) entries.push(current as NetrcEntry);
+  return entries;
+}
+
+export function netrcPath(env: NodeJS.ProcessEnv = process.env): string {
+  return env.NETRC || join(homedir(), process.platform === 'win32' ? '_netrc' : '.netrc');
+}
+
+let cached: { path: string; entries: NetrcEntry[] } | null = null;
+
+function netrcEntries(): NetrcEntry[] {
+  const path = netrcPath();
+  if (cached?.path !== path) {
+    let entries: NetrcEntry[] = [];
+    try {
+      entries = parseNetrc(readFileSync(path, 'utf-8'));
+    } catch {
+      // No .netrc: anonymous requests
+    }
+    cached = { path, entries };
+  }
+  return cached.entries;
+}
+
+/** The Authorization header for a URL, when .netrc has credentials for its host */
+export function authorizationFor(url: string): string | null {
+  const host = new URL(url).hostname;
+  // The last entry for a machine wins, as in the go command
+  const entry = netrcEntries().filter(e => e.machine === host).pop();
+  return entry ? `Basic ${Buffer.from(`${entry.login}:${entry.password}`).toString('base64')}` : null;
+}
@@ -0,0 +1,268 @@
+import { existsSync, readFileSync } from 'fs';
+import { join } from 'path';
+import { loadGoProject } from '../golang/packages.js';
+import { extractGoApi } from '../golang/api.js';
+import { parseGoMod, readGoMod, type GoModFile } from '../golang/modfile.js';
+import { compareVersions } from '../golang/modgraph.js';
+import { compareApis, type ApiChange } from '../apidiff/index.js';
+import { readGoModuleEnv, type GoModuleEnv } from '../supply-chain/confusion.js';
+import { checkCancelled } from '../utils/progress.js';
+import { fetchModule, fetchModuleGoMod, parseModuleSpec, type FetchModuleOptions, type FetchedModule } from './index.js';
+import { detectLicense, type DetectedLicense } from './license.js';
+import { parseGoSum } from './sumdb.js';
+
+/**
+ * What upgrading a dependency from one version to another brings with it:
+ * the modules it adds to (or drops from) a consumer's build list, license
+ * changes in the module and everything new or upgraded under it, and the
+ * module's API delta — the risk summary an upgrade PR should carry.
+ *
+ * Build lists come from minimal version selection over the .mod files of
+ * the whole requirement graph, as `go list -m all` computes it for a
+ * consumer that requires only this module. Graph pruning (go 1.17+) can
+ * leave some of these modules out of a consumer's go.sum, never out of
+ * its reach, so the lists err on the side of reporting more.
+ */
+
+export interface ComparedVersion {
+  version: string;
+  time: string | null;
+  /** The go directive of the version's go.mod */
+  go: string | null;
+  license: DetectedLicense;
+  /** Modules in the build list, besides the module itself */
+  modules: number;
+}
+
+export interface ModuleVersionChange {
+  path: string;
+  from: string;
+  to: string;
+}
+
+export interface LicenseChange {
+  path: string;
+  /** undefined for a module new to the build list */
+  from?: DetectedLicense;
+  to: DetectedLicense;
+}
+
+export type UpgradeRisk = 'high' | 'medium' | 'low';
+
+export interface ModuleComparison {
+  module: string;
+  from: ComparedVersion;
+  to: ComparedVersion;
+  modules: {
+    added: Array<{ path: string; version: string }>;
+    removed: Array<{ path: string; version: string }>;
+    upgraded: ModuleVersionChange[];
+    downgraded: ModuleVersionChange[];
+  };
+  licenses: LicenseChange[];
+  api: { changes: ApiChange[]; summary: { incompatible: number; compatible: number } };
+  risk: { level: UpgradeRisk; reasons: string[] };
+  /** Modules whose go.mod or source could not be fetched */
+  warnings: string[];
+}
+
+export interface CompareModuleOptions extends FetchModuleOptions {
+  /** Fetch added and upgraded modules to compare their licenses (default true) */
+  licenses?: boolean;
+  /** Downloads in flight at once (default 8) */
+  concurrency?: number;
+}
+
+// Licenses that put obligations on the code that links them
+const COPYLEFT = /\b(A?GPL|LGPL|MPL|BSL|SSPL|EUPL)-/;
+
+/** fn over items with at most limit calls in flight, results in order */
+export async function mapLimit<T, R>(items: T[], limit: number, fn: (item: T) => Promise<R>): Promise<R[]> {
+  const results: R[] = new Array(items.length);
+  let next = 0;
+  const worker = async () => {
+    while (next < items.length) {
+      const index = next++;
+      results[index] = await fn(items[index]);
+    }
+  };
+  await Promise.all(Array.from({ length: Math.min(limit, items.length) }, worker));
+  return results;
+}
+
+/** Minimal version selection: the highest version of each module reachable from the root's requirements */
+async function buildList(
+  root: { path: string; mod: GoModFile },
+  readMod: (path: string, version: string) => Promise<GoModFile | null>,
+  options: CompareModuleOptions
+): Promise<Map<string, string>> {
+  const selected = new Map<string, string>();
+  const visited = new Set<string>();
+  let frontier = root.mod.require.map(({ path, version }) => ({ path, version }));
+
+  while (frontier.length > 0) {
+    checkCancelled(options.signal);
+    const pending = frontier.filter(({ path, version }) => {
+      const key = `${path}@${version}`;
+      if (path === root.path || visited.has(key)) return false;
+      visited.add(key);
+      const current = selected.get(path);
+      if (!current || compareVersions(version, current) > 0) selected.set(path, version);
+      return true;
+    });
+    const mods = await mapLimit(pending, options.concurrency ?? 8, ({ path, version }) => readMod(path, version));
+    frontier = mods.flatMap(mod => (mod?.require ?? []).map(({ path, version }) => ({ path, version })));
+  }
+  return selected;
+}
+
+function diffBuildLists(before: Map<string, string>, after: Map<string, string>): ModuleComparison['modules'] {
+  const modules: ModuleComparison['modules'] = { added: [], removed: [], upgraded: [], downgraded: [] };
+  for (const [path, version] of after) {
+    const old = before.get(path);
+    if (!old) modules.added.push({ path, version });
+    else if (compareVersions(version, old) > 0) modules.upgraded.push({ path, from: old, to: version });
+    else if (compareVersions(version, old) < 0) modules.downgraded.push({ path, from: old, to: version });
+  }
+  for (const [path, version] of before) {
+    if (!after.has(path)) modules.removed.push({ path, version });
+  }
+  for (const list of Object.values(modules) as Array<Array<{ path: string }>>) list.sort((a, b) => a.path.localeCompare(b.path));
+  return modules;
+}
+
+function assessRisk(comparison: Omit<ModuleComparison, 'risk'>): ModuleComparison['risk'] {
+  const high: string[] = [];
+  const medium: string[] = [];
+  const { api, modules, licenses, from, to } = comparison;
+
+  if (api.summary.incompatible > 0) high.push(`${api.summary.incompatible} incompatible API change${api.summary.incompatible === 1 ? '' : 's'}`);
+  for (const change of licenses) {
+    const serious = change.path === comparison.module || change.to === null || change.to === 'unknown' || COPYLEFT.test(change.to);
+    // A permissively licensed new module is covered by the new-module count
+    if (change.from === undefined && !serious) continue;
+    const what = change.path === comparison.module ? 'license' : `${change.path} license`;
+    const message = change.from === undefined ? `${what} ${change.to ?? 'missing'} (new module)` : `${what} ${change.from ?? 'none'} → ${change.to ?? 'none'}`;
+    (serious ? high : medium).push(message);
+  }
+  if (modules.added.length > 0) medium.push(`${modules.added.length} new module${modules.added.length === 1 ? '' : 's'} in the build list`);
+  if (modules.downgraded.length > 0) medium.push(`${modules.downgraded.length} module${modules.downgraded.length === 1 ? '' : 's'} downgraded`);
+  if (from.go && to.go && compareVersions(`v${to.go}`, `v${from.go}`) > 0) medium.push(`go directive raised from ${from.go} to ${to.go}`);
+
+  return { level: high.length > 0 ? 'high' : medium.length > 0 ? 'medium' : 'low', reasons: [...high, ...medium] };
+}
+
+/** Fetch both versions of a module and compare what each brings into a consumer's build */
+export async function compareModuleVersions(fromSpec: string, toSpecOrVersion: string, options: CompareModuleOptions = {}): Promise<ModuleComparison> {
+  const path = parseModuleSpec(fromSpec).path;
+  // "v1.5.0" alone means the same module
+  const toSpecFull = toSpecOrVersion.includes('@') ? toSpecOrVersion : `${path}@${toSpecOrVersion}`;
+  if (parseModuleSpec(toSpecFull).path !== path) {
+    throw new Error(`compare mod compares two versions of one module (got ${path} and ${parseModuleSpec(toSpecFull).path})`);
+  }
+
+  const env: GoModuleEnv = await readGoModuleEnv(process.cwd(), options.signal);
+  const warnings: string[] = [];
+  const [before, after] = await Promise.all([fetchModule(fromSpec, options), fetchModule(toSpecFull, options)]);
+  // The compared modules' own go.sum files, verified with their zips, vouch for most of the .mod files
+  const goSum = new Map(options.goSum);
+  for (const fetched of [before, after].filter(f => f.verifiedSum)) {
+    const file = join(fetched.dir, 'go.sum');
+    if (existsSync(file)) for (const [key, hash] of parseGoSum(readFileSync(file, 'utf-8'))) if (!goSum.has(key)) goSum.set(key, hash);
+  }
+
+  const mods = new Map<string, Promise<GoModFile | null>>();
+  const readMod = (modPath: string, version: string) => {
+    const key = `${modPath}@${version}`;
+    if (!mods.has(key)) {
+      mods.set(key, fetchModuleGoMod(modPath, version, env, { ...options, goSum }).then(parseGoMod, (err) => {
+        if (options.signal?.aborted) throw err;
+        warnings.push(`${key}: ${err instanceof Error ? err.message : err} (its requirements are missing from the build list)`);
+        return null;
+      }));
+    }
+    return mods.get(key)!;
+  };
+  const rootMod = (fetched: FetchedModule) => readGoMod(fetched.dir) ?? { module: path, go: null, toolchain: null, require: [], replace: [], exclude: [], retract: [] };
+  const [beforeMod, afterMod] = [rootMod(before), rootMod(after)];
+  // Sequential, so the shared .mod files of both graphs are fetched once
+  const beforeList = await buildList({ path, mod: beforeMod }, readMod, options);
+  const afterList = await buildList({ path, mod: afterMod }, readMod, options);
+  const modules = diffBuildLists(beforeList, afterList);
+
+  checkCancelled(options.signal);
+  const changes = compareApis(
+    extractGoApi(await loadGoProject(before.dir), path),
+    extractGoApi(await loadGoProject(after.dir), path)
+  );
+  const incompatible = changes.filter(c => !c.compatible).length;
+
+  const licenses: LicenseChange[] = [];
+  const [fromLicense, toLicense] = [detectLicense(before.dir), detectLicense(after.dir)];
+  if (fromLicense !== toLicense) licenses.push({ path, from: fromLicense, to: toLicense });
+  if (options.licenses !== false) {
+    const licenseOf = async (modPath: string, version: string): Promise<DetectedLicense | undefined> => {
+      try {
+        return detectLicense((await fetchModule(`${modPath}@${version}`, options)).dir);
+      } catch (err) {
+        if (options.signal?.aborted) throw err;
+        warnings.push(`${modPath}@${version}: ${err instanceof Error ? err.message : err} (license not checked)`);
+        return undefined;
+      }
+    };
+    const checks = [
+      ...modules.added.map(m => async () => {
+        const to = await licenseOf(m.path, m.version);
+        if (to !== undefined) licenses.push({ path: m.path, to });
+      }),
+      ...[...modules.upgraded, ...modules.downgraded].map(m => async () => {
+        const [from, to] = [await licenseOf(m.path, m.from), await licenseOf(m.path, m.to)];
+        if (from !== undefined && to !== undefined && from !== to) licenses.push({ path: m.path, from, to });
+      }),
+    ];
+    await mapLimit(checks, options.concurrency ?? 8, check => check());
+    licenses.sort((a, b) => Number(a.path !== path) - Number(b.path !== path) || a.path.localeCompare(b.path));
+  }
+
+  const comparison: Omit<ModuleComparison, 'risk'> = {
+    module: path,
+    from: { version: before.version, time: before.time, go: beforeMod.go, license: fromLicense, modules: beforeList.size },
+    to: { version: after.version, time: after.time, go: afterMod.go, license: toLicense, modules: afterList.size },
+    modules,
+    licenses,
+    api: { changes, summary: { incompatible, compatible: changes.length - incompatible } },
+    warnings: warnings.sort(),
+  };
+  return { ...comparison, risk: assessRisk(comparison) };
+}
+
+/** The comparison as a PR comment: risk first, then the details behind it */
+export function formatModuleComparisonMarkdown(c: ModuleComparison, limit = 50): string {
+  const lines: string[] = [];
+  lines.push(`# Upgrading ${c.module} ${c.from.version} → ${c.to.version}`, '');
+  lines.push(`**Risk: ${c.risk.level}**${c.risk.reasons.length > 0 ? ` — ${c.risk.reasons.join('; ')}` : ' — no new modules, license or API breakage'}`, '');
+
+  lines.push(`| | ${c.from.version} | ${c.to.version} |`, '| --- | --- | --- |');
+  lines.push(`| Published | ${c.from.time?.slice(0, 10) ?? '—'} | ${c.to.time?.slice(0, 10) ?? '—'} |`);
+  lines.push(`| go directive | ${c.from.go ?? '—'} | ${c.to.go ?? '—'} |`);
+  lines.push(`| License | ${c.from.license ?? 'none'} | ${c.to.license ?? 'none'} |`);
+  lines.push(`| Modules in build list | ${c.from.modules} | ${c.to.modules} |`, '');
+
+  const section = (title: string, items: string[]) => {
+    if (items.length === 0) return;
+    lines.push(`## ${title} (${items.length})`, '', ...items.slice(0, limit).map(item => `- ${item}`));
+    if (items.length > limit) lines.push(`- … ${items.length - limit} more`);
+    lines.push('');
+  };
+  section('New modules', c.modules.added.map(m => `\`${m.path}\` ${m.version}`));
+  section('Removed modules', c.modules.removed.map(m => `\`${m.path}\` ${m.version}`));
+  section('Upgraded modules', c.modules.upgraded.map(m => `\`${m.path}\` ${m.from} → ${m.to}`));
+  section('Downgraded modules', c.modules.downgraded.map(m => `\`${m.path}\` ${m.from} → ${m.to}`));
+  section('License changes', c.licenses.map(l => `\`${l.path}\` ${l.from === undefined ? `${l.to ?? 'no license file'} (new)` : `${l.from ?? 'none'} → ${l.to ?? 'none'}`}`));
+
+  const incompatible = c.api.changes.filter(change => !change.compatible);
+  section('Incompatible API changes', incompatible.map(change => `\`${change.package}\`${change.name ? ` ${change.name}` : ''}: ${change.message}`));
+  if (c.api.summary.compatible > 0) lines.push(`${c.api.summary.compatible} compatible API additions.`, '');
+  section('Warnings', c.warnings);
+  return lines.join('\n');
+}
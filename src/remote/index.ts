@@ -0,0 +1,301 @@
+import { existsSync, readFileSync, writeFileSync, mkdirSync } from 'fs';
+import { createHash } from 'crypto';
+import { join, basename, dirname } from 'path';
+import { tmpdir } from 'os';
+import { ensurePrivateDir, userCacheDir } from '../utils/files.js';
+import { logger } from '../utils/log.js';
+import { parseProject } from '../parser/index.js';
+import { buildGraph } from '../graph/index.js';
+import { toPackageGraph } from '../graph/model.js';
+import { calculateHealthScore } from '../health/index.js';
+import { scanSecurity } from '../security/scanner.js';
+import { loadGoFiles } from '../golang/source.js';
+import { readGoMod } from '../golang/modfile.js';
+import { fileCapabilities, CAPABILITIES, type Capability, type CapabilityEvidence } from '../capabilities/index.js';
+import { escapeModulePath, readGoModuleEnv, matchesGoPrefixPatterns, type GoModuleEnv } from '../supply-chain/confusion.js';
+import { runGo } from '../golang/toolchain.js';
+import { parseJsonStream } from '../golang/deps.js';
+import { readZip, readArchive, commonPrefix, extractEntries } from './archive.js';
+import { resolveModuleVersion, downloadModuleZip, downloadModuleGoMod, ModuleNotOnProxyError } from './proxy.js';
+import { hashModuleZip, hashModuleDir, hashDirectory, hashGoMod, lookupModuleSum, lookupGoModSum } from './sumdb.js';
+import type { SecurityScanResult } from '../security/types.js';
+
+const log = logger('remote');
+
+export { readZip, readTar, readArchive, commonPrefix, extractEntries, unsafeEntryName, MAX_UNPACKED_SIZE, type ArchiveEntry } from './archive.js';
+export { parseGoProxy, proxyGet, resolveModuleVersion, downloadModuleZip, downloadModuleGoMod, ModuleNotOnProxyError, type ProxyEntry, type ModuleInfo } from './proxy.js';
+export { hashModuleZip, hashModuleDir, hashDirectory, hashGoMod, parseGoSum, lookupModuleSum, lookupGoModSum, sumdbURL } from './sumdb.js';
+export { parseNetrc, netrcPath, authorizationFor, type NetrcEntry } from './auth.js';
+
+/**
+ * Analysis of code that isn't checked out: a module fetched from the module
+ * proxy, extracted to a temporary directory and run through the same
+ * parsing, health, security and capability analysis as a local project.
+ *
+ * Fetching follows the go command's rules: modules matching GONOPROXY
+ * (GOPRIVATE by default), or missing from every proxy before a "direct"
+ * entry, come from their repository through `go mod download`, so .netrc,
+ * git credential helpers, SSH keys and insteadOf rewrites all apply.
+ * Proxy downloads are checked against GOSUMDB unless GONOSUMDB covers them.
+ * Archives on disk (module zips, source tarballs) are analyzed without any
+ * network access at all.
+ */
+
+export interface FetchedModule {
+  path: string;
+  version: string;
+  /** Commit time the proxy reports, when it does */
+  time: string | null;
+  /** Where the source came from: a proxy URL, or "direct" */
+  origin: string;
+  /** Extracted source */
+  dir: string;
+  /** Already extracted by an earlier run (and checked again when there is a sum to check against) */
+  cached: boolean;
+  /** The h1: hash checked against the checksum database, or null when GONOSUMDB/GOSUMDB=off skip it */
+  verifiedSum: string | null;
+}
+
+export interface FetchModuleOptions {
+  /** Overrides GOPROXY from go env */
+  proxy?: string;
+  /** Where extracted modules are kept (default: defaultCacheDir()) */
+  cacheDir?: string;
+  /** go.sum lines (parseGoSum) that .mod files are checked against before the checksum database is asked */
+  goSum?: Map<string, string>;
+  signal?: AbortSignal;
+}
+
+export interface RemoteAnalysis {
+  module: string;
+  version: string | null;
+  time: string | null;
+  origin: string;
+  dir: string;
+  /** The go directive of the module's go.mod */
+  go: string | null;
+  requires: { direct: Array<{ path: string; version: string }>; indirect: number };
+  files: number;
+  packages: number;
+  symbols: number;
+  health: { score: number; grade: string };
+  /** Capability → where the module's own code uses it */
+  capabilities: Partial<Record<Capability, CapabilityEvidence[]>>;
+  security: Pick<SecurityScanResult, 'summary' | 'findings'>;
+}
+
+/** "github.com/foo/bar@v1.2.3" → path and version ("latest" when omitted) */
+export function parseModuleSpec(spec: string): { path: string; version: string } {
+  const at = spec.lastIndexOf('@');
+  const path = at > 0 ? spec.slice(0, at) : spec;
+  const version = at > 0 ? spec.slice(at + 1) : 'latest';
+  if (!path || !version || /\s/.test(spec)) throw new Error(`Invalid module "${spec}" (expected <module path>@<version>)`);
+  return { path, version };
+}
+
+/** ~/.cache/depwire/mod: per user, since a shared directory would let others plant modules in it */
+export function defaultCacheDir(): string {
+  return userCacheDir('mod');
+}
+
+interface GoModDownload {
+  Path: string;
+  Version: string;
+  Error?: string;
+  Info?: string;
+  Dir?: string;
+  GoMod?: string;
+  Sum?: string;
+}
+
+// From the repository, exactly as the go command would fetch it (and with its credentials)
+async function downloadDirect(path: string, query: string, reason: string, checked: boolean, signal?: AbortSignal): Promise<FetchedModule> {
+  const result = await runGo(['mod', 'download', '-json', `${path}@${query}`], { cwd: tmpdir(), signal }).catch((err) => {
+    throw new Error(`${path} ${reason}, so it is fetched from its repository, which needs the go command: ${err instanceof Error ? err.message : err}`);
+  });
+  const download = parseJsonStream(result.stdout)[0] as GoModDownload | undefined;
+  if (!download || download.Error || !download.Dir) {
+    const message = download?.Error ?? result.stderr.trim().split('\n').pop() || `exit code ${result.exitCode}`;
+    throw new Error(`go mod download ${path}@${query}: ${message} — check .netrc, git credentials and SSH access to the repository`);
+  }
+  let time: string | null = null;
+  try {
+    time = download.Info ? (JSON.parse(readFileSync(download.Info, 'utf-8')) as { Time?: string }).Time ?? null : null;
+  } catch {
+    // The .info file is optional metadata
+  }
+  // The go command checked the sum against GOSUMDB itself, when GONOSUMDB doesn't cover the module
+  return { path, version: download.Version, time, origin: 'direct', dir: download.Dir, cached: false, verifiedSum: checked ? download.Sum ?? null : null };
+}
+
+function skipsSumdb(env: GoModuleEnv, path: string): boolean {
+  return env.GOSUMDB === 'off' || matchesGoPrefixPatterns(env.GONOSUMDB, path);
+}
+
+/** Download and extract a module: from GOPROXY without git, a toolchain or a workspace where the go command would use a proxy */
+export async function fetchModule(spec: string, options: FetchModuleOptions = {}): Promise<FetchedModule> {
+  const { path, version: query } = parseModuleSpec(spec);
+  const env = await readGoModuleEnv(process.cwd(), options.signal);
+  const goproxy = options.proxy ?? env.GOPROXY;
+  if (matchesGoPrefixPatterns(env.GONOPROXY, path)) {
+    return downloadDirect(path, query, 'matches GONOPROXY/GOPRIVATE', !skipsSumdb(env, path), options.signal);
+  }
+
+  let info: Awaited<ReturnType<typeof resolveModuleVersion>>;
+  try {
+    info = await resolveModuleVersion(goproxy, path, query, options.signal);
+  } catch (err) {
+    if (err instanceof ModuleNotOnProxyError && err.direct) return downloadDirect(path, query, 'is not on the proxy', !skipsSumdb(env, path), options.signal);
+    throw err;
+  }
+  const dir = join(ensurePrivateDir(options.cacheDir ?? defaultCacheDir()), `${escapeModulePath(path)}@${escapeModulePath(info.Version)}`);
+  const base = { path, version: info.Version, time: info.Time ?? null, origin: info.proxy, dir };
+  const expected = skipsSumdb(env, path) ? null : await lookupModuleSum(env.GOSUMDB, path, info.Version, options.signal);
+
+  // Versions are immutable, but an extraction is only as good as whoever wrote it: hash it again
+  if (existsSync(dir)) {
+    if (!expected) return { ...base, cached: true, verifiedSum: null };
+    const actual = hashModuleDir(dir, path, info.Version);
+    if (actual === expected) return { ...base, cached: true, verifiedSum: actual };
+    log.warn(`${dir} does not match the checksum database (${actual}, expected ${expected}); downloading it again`, { module: path, version: info.Version });
+  }
+  const { proxy, data } = await downloadModuleZip(goproxy, path, info.Version, options.signal);
+  const entries = readZip(data);
+  let verifiedSum: string | null = null;
+  if (expected) {
+    const actual = hashModuleZip(entries);
+    if (actual !== expected) {
+      throw new Error(`SECURITY ERROR: ${path}@${info.Version} from ${proxy} does not match the checksum database (downloaded ${actual}, expected ${expected})`);
+    }
+    verifiedSum = actual;
+  }
+  // Module zips hold every file under "<module>@<version>/"
+  extractEntries(entries, dir, `${path}@${info.Version}/`);
+  return { ...base, origin: proxy, cached: false, verifiedSum };
+}
+
+/**
+ * The go.mod of module@version without its source: the proxy's .mod file,
+ * or `go mod download` for modules that come from their repository. Kept
+ * under the cache directory, since a version's go.mod never changes. Each
+ * read, cached or not, is checked against the "/go.mod" hash of go.sum
+ * (options.goSum) or the checksum database, unless GONOSUMDB covers it.
+ */
+export async function fetchModuleGoMod(path: string, version: string, env: GoModuleEnv, options: FetchModuleOptions = {}): Promise<string> {
+  const cached = join(ensurePrivateDir(options.cacheDir ?? defaultCacheDir()), 'mod', `${escapeModulePath(path)}@${escapeModulePath(version)}.mod`);
+  const expected = options.goSum?.get(`${path} ${version}/go.mod`)
+    ?? (skipsSumdb(env, path) ? null : await lookupGoModSum(env.GOSUMDB, path, version, options.signal));
+  const matches = (content: string) => !expected || hashGoMod(Buffer.from(content, 'utf-8')) === expected;
+  if (existsSync(cached)) {
+    const content = readFileSync(cached, 'utf-8');
+    if (matches(content)) return content;
+    log.warn(`${cached} does not match its go.sum hash ${expected}; downloading it again`, { module: path, version });
+  }
+
+  let content: string;
+  const direct = async () => {
+    const result = await runGo(['mod', 'download', '-json', `${path}@${version}`], { cwd: tmpdir(), signal: options.signal });
+    const download = parseJsonStream(result.stdout)[0] as GoModDownload | undefined;
+    if (!download?.GoMod) throw new Error(`go mod download ${path}@${version}: ${download?.Error ?? (result.stderr.trim() || 'no go.mod')}`);
+    return readFileSync(download.GoMod, 'utf-8');
+  };
+  if (matchesGoPrefixPatterns(env.GONOPROXY, path)) {
+    content = await direct();
+  } else {
+    try {
+      content = await downloadModuleGoMod(options.proxy ?? env.GOPROXY, path, version, options.signal);
+    } catch (err) {
+      if (!(err instanceof ModuleNotOnProxyError && err.direct)) throw err;
+      content = await direct();
+    }
+  }
+  if (!matches(content)) {
+    throw new Error(`SECURITY ERROR: the go.mod of ${path}@${version} hashes to ${hashGoMod(Buffer.from(content, 'utf-8'))}, expected ${expected}`);
+  }
+  mkdirSync(dirname(cached), { recursive: true });
+  writeFileSync(cached, content);
+  return content;
+}
+
+export interface OpenArchiveOptions {
+  cacheDir?: string;
+  /** Expected h1: hash (from go.sum) of a module zip */
+  sum?: string;
+}
+
+/** Extract a module zip or source tarball from disk, for review where nothing may be fetched */
+export function openArchive(file: string, options: OpenArchiveOptions = {}): FetchedModule {
+  const data = readFileSync(file);
+  const entries = readArchive(data);
+  if (entries.length === 0) throw new Error(`${file} contains no files`);
+  const prefix = commonPrefix(entries);
+  // "example.com/m@v1.2.3/" names the module and version, as in a module zip
+  const moduleMatch = /^(.+)@([^/@]+)\/$/.exec(prefix);
+
+  const hash = hashModuleZip(entries);
+  let verifiedSum: string | null = null;
+  if (options.sum) {
+    if (hash !== options.sum) throw new Error(`SECURITY ERROR: ${file} hashes to ${hash}, expected ${options.sum}`);
+    verifiedSum = hash;
+  }
+
+  // Keyed by content, so a changed archive at the same path is extracted afresh
+  const digest = createHash('sha256').update(data).digest('hex').slice(0, 16);
+  const dir = join(ensurePrivateDir(options.cacheDir ?? defaultCacheDir()), `archive-${digest}`);
+  // An earlier extraction counts only if it still holds exactly the archive's files
+  let cached = existsSync(dir);
+  if (cached && hashDirectory(dir, prefix) !== hash) {
+    log.warn(`${dir} no longer matches ${file}; extracting it again`, { file });
+    cached = false;
+  }
+  if (!cached) extractEntries(entries, dir, prefix);
+  return {
+    path: moduleMatch?.[1] ?? readGoMod(dir)?.module ?? basename(file),
+    version: moduleMatch?.[2] ?? '',
+    time: null,
+    origin: file,
+    dir,
+    cached,
+    verifiedSum,
+  };
+}
+
+/** Run the standard analyses against extracted module source */
+export async function analyzeSource(
+  dir: string,
+  meta: { module?: string; version?: string | null; time?: string | null; origin: string },
+  options: { signal?: AbortSignal } = {}
+): Promise<RemoteAnalysis> {
+  const parsedFiles = await parseProject(dir, { signal: options.signal });
+  const graph = buildGraph(parsedFiles, dir);
+  const health = calculateHealthScore(graph, dir);
+  const security = await scanSecurity(dir, graph, { signal: options.signal });
+  const mod = readGoMod(dir);
+
+  const capabilities: Partial<Record<Capability, CapabilityEvidence[]>> = {};
+  for (const file of await loadGoFiles(dir)) {
+    for (const evidence of fileCapabilities(file)) {
+      (capabilities[evidence.capability] ??= []).push(evidence);
+    }
+  }
+  const ordered = Object.fromEntries(CAPABILITIES.filter(c => capabilities[c]).map(c => [c, capabilities[c]!]));
+
+  return {
+    module: meta.module ?? mod?.module ?? dir,
+    version: meta.version ?? null,
+    time: meta.time ?? null,
+    origin: meta.origin,
+    dir,
+    go: mod?.go ?? null,
+    requires: {
+      direct: (mod?.require ?? []).filter(r => !r.indirect).map(({ path, version }) => ({ path, version })),
+      indirect: (mod?.require ?? []).filter(r => r.indirect).length,
+    },
+    files: parsedFiles.length,
+    packages: toPackageGraph(graph).order,
+    symbols: graph.order,
+    health: { score: health.overall, grade: health.grade },
+    capabilities: ordered,
+    security: { summary: security.summary, findings: security.findings },
+  };
+}
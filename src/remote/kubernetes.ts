@@ -0,0 +1,301 @@
+import { execFile } from 'child_process';
+import { lstatSync, readdirSync, readFileSync } from 'fs';
+import { join, relative } from 'path';
+
+/**
+ * Kubernetes workloads and the container images they run, from manifests
+ * on disk (YAML or JSON, several documents per file, kubectl List output)
+ * or from a live cluster through kubectl. Only the block YAML manifests are
+ * written in is read: maps, sequences, plain and quoted scalars, block
+ * scalars and one-level flow collections; anchors and tags aren't.
+ * Templates (Helm, Kustomize patches) should be rendered first.
+ */
+
+export const WORKLOAD_KINDS = ['Deployment', 'StatefulSet', 'DaemonSet', 'ReplicaSet', 'Job', 'CronJob', 'Pod'];
+
+export interface WorkloadContainer {
+  name: string;
+  image: string;
+  /** An init container, run before the others */
+  init: boolean;
+}
+
+export interface Workload {
+  kind: string;
+  name: string;
+  namespace: string | null;
+  /** Manifest file, relative to the directory read, or "cluster" */
+  source: string;
+  containers: WorkloadContainer[];
+}
+
+export interface ClusterOptions {
+  /** kubectl context; defaults to the current one */
+  context?: string;
+  /** One namespace instead of all of them */
+  namespace?: string;
+  signal?: AbortSignal;
+}
+
+interface YamlLine {
+  indent: number;
+  text: string;
+}
+
+// Drop a trailing comment: a # at the start or after a space, outside quotes
+function stripComment(line: string): string {
+  let quote: string | null = null;
+  for (let i = 0; i < line.length; i++) {
+    const c = line[i];
+    if (quote) {
+      if (c === quote) quote = null;
+    } else if (c === '"' || c === "'") {
+      if (i === 0 || /[\s:\-[{,]/.test(line[i - 1])) quote = c;
+    } else if (c === '#' && (i === 0 || /\s/.test(line[i - 1]))) {
+      return line.slice(0, i).trimEnd();
+    }
+  }
+  return line.trimEnd();
+}
+
+function scalar(text: string): unknown {
+  const value = text.trim();
+  if (value === '[]') return [];
+  if (value === '{}') return {};
+  if (value.startsWith('"') && value.endsWith('"') && value.length > 1) {
+    try {
+      return JSON.parse(value);
+    } catch {
+      return value.slice(1, -1);
+    }
+  }
+  if (value.startsWith("'") && value.endsWith("'") && value.length > 1) return value.slice(1, -1).replace(/''/g, "'");
+  if (value.startsWith('[') || value.startsWith('{')) {
+    try {
+      return JSON.parse(value);
+    } catch {
+      return flow(value);
+    }
+  }
+  if (value === '' || value === '~' || value === 'null') return null;
+  return value;
+}
+
+// A one-level flow collection of plain or quoted scalars: [a, b], {name: x, app: y}
+function flow(value: string): unknown {
+  const inner = value.slice(1, -1);
+  if (!/^[[{].*[\]}]$/.test(value) || /[[\]{}]/.test(inner)) return value;
+  const items = inner.split(',').map(s => s.trim()).filter(Boolean);
+  if (value.startsWith('[')) return items.map(scalar);
+  const map: Record<string, unknown> = {};
+  for (const item of items) {
+    const colon = item.indexOf(':');
+    if (colon === -1) return value;
+    map[String(scalar(item.slice(0, colon)))] = scalar(item.slice(colon + 1));
+  }
+  return map;
+}
+
+const KEY = /^("(?:[^"\\]|\\.)*"|'[^']*'|[^\s'"#-][^:]*?|-[^\s:][^:]*?)\s*:(?:\s+(.*))?$/;
+
+class YamlReader {
+  private pos = 0;
+
+  constructor(private readonly lines: YamlLine[]) {}
+
+  parse(): unknown {
+    return this.pos < this.lines.length ? this.block(this.lines[this.pos].indent) : null;
+  }
+
+  private block(indent: number): unknown {
+    const line = this.lines[this.pos];
+    return line.text === '-' || line.text.startsWith('- ') ? this.sequence(indent) : this.map(indent);
+  }
+
+  private sequence(indent: number): unknown[] {
+    const items: unknown[] = [];
+    while (this.pos < this.lines.length) {
+      const line = this.lines[this.pos];
+      if (line.indent !== indent || !(line.text === '-' || line.text.startsWith('- '))) break;
+      const rest = line.text.slice(1).trimStart();
+      if (!rest) {
+        this.pos++;
+        items.push(this.pos < this.lines.length && this.lines[this.pos].indent > indent ? this.block(this.lines[this.pos].indent) : null);
+      } else if (KEY.test(rest)) {
+        // "- key: value" opens a map whose keys line up with "key"
+        this.lines[this.pos] = { indent: indent + line.text.length - rest.length, text: rest };
+        items.push(this.map(this.lines[this.pos].indent));
+      } else {
+        this.pos++;
+        items.push(scalar(rest));
+      }
+    }
+    return items;
+  }
+
+  private map(indent: number): Record<string, unknown> {
+    const map: Record<string, unknown> = {};
+    while (this.pos < this.lines.length) {
+      const line = this.lines[this.pos];
+      if (line.indent !== indent) break;
+      const match = KEY.exec(line.text);
+      if (!match) break;
+      this.pos++;
+      const key = String(scalar(match[1]));
+      const value = match[2] ?? '';
+      if (/^[|>][-+0-9]*$/.test(value)) {
+        const text: string[] = [];
+        while (this.pos < this.lines.length && this.lines[this.pos].indent > indent) text.push(this.lines[this.pos++].text);
+        map[key] = text.join(value.startsWith('|') ? '\n' : ' ');
+      } else if (value) {
+        map[key] = scalar(value);
+      } else if (this.pos < this.lines.length) {
+        const next = this.lines[this.pos];
+        // A sequence may sit at its key's own indentation
+        const nested = next.indent > indent || (next.indent === indent && (next.text === '-' || next.text.startsWith('- ')));
+        map[key] = nested ? this.block(next.indent) : null;
+      } else {
+        map[key] = null;
+      }
+    }
+    return map;
+  }
+}
+
+/** The documents of a YAML stream, in the subset manifests use */
+export function parseYamlDocuments(content: string): unknown[] {
+  const documents: unknown[] = [];
+  let lines: YamlLine[] = [];
+  const flush = () => {
+    if (lines.length > 0) documents.push(new YamlReader(lines).parse());
+    lines = [];
+  };
+  for (const raw of content.split(/\r?\n/)) {
+    if (/^---(\s|$)/.test(raw) || /^\.\.\.\s*$/.test(raw)) {
+      flush();
+      continue;
+    }
+    const text = stripComment(raw.replace(/\t/g, '  '));
+    if (!text.trim() || text.trim().startsWith('%')) continue;
+    lines.push({ indent: text.length - text.trimStart().length, text: text.trim() });
+  }
+  flush();
+  return documents;
+}
+
+function record(value: unknown): Record<string, any> | null {
+  return value && typeof value === 'object' && !Array.isArray(value) ? value as Record<string, any> : null;
+}
+
+// The pod spec of a workload object
+function podSpec(object: Record<string, any>): Record<string, any> | null {
+  switch (object.kind) {
+    case 'Pod': return record(object.spec);
+    case 'CronJob': return record(object.spec?.jobTemplate?.spec?.template?.spec);
+    default: return record(object.spec?.template?.spec);
+  }
+}
+
+/** Workloads among Kubernetes objects; List objects are unpacked */
+export function workloadsOf(objects: unknown[], source: string): Workload[] {
+  const workloads: Workload[] = [];
+  for (const value of objects) {
+    const object = record(value);
+    if (!object) continue;
+    if (Array.isArray(object.items)) {
+      workloads.push(...workloadsOf(object.items, source));
+      continue;
+    }
+    if (!WORKLOAD_KINDS.includes(object.kind)) continue;
+    const spec = podSpec(object);
+    const containers: WorkloadContainer[] = [];
+    for (const [field, init] of [['initContainers', true], ['containers', false]] as const) {
+      for (const container of Array.isArray(spec?.[field]) ? spec![field] : []) {
+        if (typeof container?.image === 'string') containers.push({ name: String(container.name ?? ''), image: container.image, init });
+      }
+    }
+    workloads.push({
+      kind: object.kind,
+      name: String(object.metadata?.name ?? ''),
+      namespace: object.metadata?.namespace ? String(object.metadata.namespace) : null,
+      source,
+      containers,
+    });
+  }
+  return workloads;
+}
+
+export function parseManifest(content: string, source: string): Workload[] {
+  const trimmed = content.trimStart();
+  if (trimmed.startsWith('{') || trimmed.startsWith('[')) {
+    const parsed = JSON.parse(content) as unknown;
+    return workloadsOf(Array.isArray(parsed) ? parsed : [parsed], source);
+  }
+  return workloadsOf(parseYamlDocuments(content), source);
+}
+
+function manifestFiles(root: string, dir: string): string[] {
+  const files: string[] = [];
+  let entries: string[];
+  try {
+    entries = readdirSync(dir);
+  } catch {
+    return files;
+  }
+  for (const entry of entries.sort()) {
+    if (entry.startsWith('.') || entry === 'node_modules' || entry === 'vendor') continue;
+    const path = join(dir, entry);
+    const stats = lstatSync(path);
+    if (stats.isDirectory()) files.push(...manifestFiles(root, path));
+    else if (stats.isFile() && /\.(ya?ml|json)$/.test(entry)) files.push(path);
+  }
+  return files;
+}
+
+/**
+ * Workloads in every manifest under dir (or in one file). Files that don't
+ * parse are skipped with a warning; most YAML in a repository isn't a
+ * manifest at all.
+ */
+export function readManifests(path: string, warnings: string[] = []): Workload[] {
+  const stats = lstatSync(path);
+  const files = stats.isDirectory() ? manifestFiles(path, path) : [path];
+  const workloads: Workload[] = [];
+  for (const file of files) {
+    const source = stats.isDirectory() ? relative(path, file).split('\\').join('/') : file;
+    try {
+      workloads.push(...parseManifest(readFileSync(file, 'utf-8'), source));
+    } catch (err) {
+      warnings.push(`${source}: ${err instanceof Error ? err.message : err}`);
+    }
+  }
+  return workloads;
+}
+
+/**
+ * Workloads running in a cluster, through kubectl. Pods and Jobs a
+ * controller owns are left out: their Deployment, StatefulSet, DaemonSet
+ * or CronJob stands for them.
+ */
+export function clusterWorkloads(options: ClusterOptions = {}): Promise<Workload[]> {
+  const args = ['get', 'deployments,statefulsets,daemonsets,cronjobs,jobs,pods', '-o', 'json'];
+  args.push(...(options.namespace ? ['--namespace', options.namespace] : ['--all-namespaces']));
+  if (options.context) args.push('--context', options.context);
+  return new Promise((resolve, reject) => {
+    execFile('kubectl', args, { signal: options.signal, maxBuffer: 256 * 1024 * 1024, encoding: 'utf-8' }, (error, stdout, stderr) => {
+      if (error && (error as NodeJS.ErrnoException).code === 'ENOENT') {
+        reject(new Error('kubectl not found — install it, or point depwire at a directory of manifests'));
+      } else if (error) {
+        reject(options.signal?.aborted ? error : new Error(`kubectl get failed: ${stderr.trim() || error.message}`));
+      } else {
+        const list = JSON.parse(stdout) as { items?: Array<Record<string, any>> };
+        const items = (list.items ?? []).filter(item =>
+          !(item.kind === 'Pod' || item.kind === 'Job') || !(item.metadata?.ownerReferences ?? []).some((o: { controller?: boolean }) => o.controller));
+        resolve(workloadsOf(items, 'cluster'));
+      }
+    });
+  });
+}
+
+/** namespace/Kind/name, with "default" for manifests that leave the namespace out */
+export const workloadId = (w: Pick<Workload, 'kind' | 'name' | 'namespace'>) => `${w.namespace ?? 'default'}/${w.kind}/${w.name}`;
@@ -0,0 +1,50 @@
+import { readdirSync, readFileSync } from 'fs';
+import { join } from 'path';
+
+/**
+ * License detection for extracted module source: the license file in the
+ * module root (LICENSE, LICENCE, COPYING, with any extension) matched
+ * against the distinctive phrases of common licenses. Good enough to notice
+ * that an upgrade changed a license; not a substitute for a legal review.
+ */
+
+/** SPDX identifier, "unknown" for a license file that matches nothing, or null without one */
+export type DetectedLicense = string | null;
+
+const LICENSE_FILE = /^(licen[cs]e|copying|unlicense)([-_.].*)?$/i;
+
+// Most specific first: the LGPL text mentions the GPL, BSD-3 contains BSD-2
+const PATTERNS: Array<[string, RegExp]> = [
+  ['AGPL-3.0', /GNU AFFERO GENERAL PUBLIC LICENSE/i],
+  ['LGPL-3.0', /GNU LESSER GENERAL PUBLIC LICENSE\s+Version 3/i],
+  ['LGPL-2.1', /GNU LESSER GENERAL PUBLIC LICENSE\s+Version 2\.1/i],
+  ['GPL-3.0', /GNU GENERAL PUBLIC LICENSE\s+Version 3/i],
+  ['GPL-2.0', /GNU GENERAL PUBLIC LICENSE\s+Version 2/i],
+  ['MPL-2.0', /Mozilla Public License,?\s+(version|v\.?)\s*2\.0/i],
+  ['Apache-2.0', /Apache License,?\s+Version 2\.0/i],
+  ['BSD-3-Clause', /Neither the name of .{0,200}? nor the names of/is],
+  ['BSD-2-Clause', /Redistributions in binary form must reproduce/i],
+  ['MIT', /Permission is hereby granted, free of charge, to any person obtaining a copy/i],
+  ['ISC', /Permission to use, copy, modify, and(\/or)? distribute this software for any purpose/i],
+  ['Unlicense', /This is free and unencumbered software released into the public domain/i],
+  ['CC0-1.0', /CC0 1\.0 Universal/i],
+  ['BSL-1.1', /Business Source License 1\.1/i],
+];
+
+export function identifyLicense(text: string): string {
+  return PATTERNS.find(([, pattern]) => pattern.test(text))?.[0] ?? 'unknown';
+}
+
+export function detectLicense(dir: string): DetectedLicense {
+  let files: string[];
+  try {
+    files = readdirSync(dir, { withFileTypes: true }).filter(e => e.isFile() && LICENSE_FILE.test(e.name)).map(e => e.name).sort();
+  } catch {
+    return null;
+  }
+  if (files.length === 0) return null;
+  // Dual-licensed modules ship one file per license
+  const ids = [...new Set(files.map(name => identifyLicense(readFileSync(join(dir, name), 'utf-8'))))];
+  const known = ids.filter(id => id !== 'unknown');
+  return known.length > 0 ? known.join(' OR ') : 'unknown';
+}
@@ -0,0 +1,98 @@
+import { escapeModulePath, PUBLIC_PROXY } from '../supply-chain/confusion.js';
+import { authorizationFor } from './auth.js';
+
+/**
+ * A client for the GOPROXY protocol (https://go.dev/ref/mod#goproxy-protocol).
+ * GOPROXY lists proxies separated by "," (try the next one only when this
+ * one doesn't know the module: 404 or 410) or "|" (try the next one after
+ * any error), like the go command. Private proxies get the credentials
+ * .netrc has for their host.
+ */
+
+export interface ProxyEntry {
+  url: string;
+  /** Fall through to the next entry on any error, not just 404/410 */
+  fallbackOnError: boolean;
+}
+
+export interface ModuleInfo {
+  Version: string;
+  Time?: string;
+  /** Where the proxy got the version from (Go 1.21+ proxies record it) */
+  Origin?: { VCS?: string; URL?: string; Hash?: string; Ref?: string };
+}
+
+export interface ProxyResponse {
+  proxy: string;
+  data: Buffer;
+}
+
+export function parseGoProxy(goproxy: string): Array<ProxyEntry | 'direct' | 'off'> {
+  const entries: Array<ProxyEntry | 'direct' | 'off'> = [];
+  const pattern = /([^,|]+)([,|]?)/g;
+  let match: RegExpExecArray | null;
+  while ((match = pattern.exec(goproxy || `${PUBLIC_PROXY},direct`))) {
+    const value = match[1].trim();
+    if (value === 'direct' || value === 'off') entries.push(value);
+    else if (value) entries.push({ url: value.replace(/\/$/, ''), fallbackOnError: match[2] === '|' });
+  }
+  return entries;
+}
+
+class NotFound extends Error {}
+
+async function get(url: string, signal?: AbortSignal): Promise<Buffer> {
+  const authorization = authorizationFor(url);
+  const response = await fetch(url, { signal, headers: authorization ? { authorization } : undefined });
+  if (response.status === 404 || response.status === 410) throw new NotFound(`${url}: ${response.status}`);
+  if (!response.ok) throw new Error(`GET ${url}: ${response.status} ${response.statusText}`);
+  return Buffer.from(await response.arrayBuffer());
+}
+
+/** The module isn't on any proxy; `direct` when GOPROXY says to fetch it from its repository next */
+export class ModuleNotOnProxyError extends Error {
+  constructor(message: string, readonly direct: boolean) {
+    super(message);
+  }
+}
+
+/**
+ * GET a path of the module (e.g. "@v/v1.2.3.zip") from the first proxy
+ * that has it. Reaching "direct" throws ModuleNotOnProxyError for the
+ * caller to fetch from the repository instead; "off" refuses.
+ */
+export async function proxyGet(goproxy: string, module: string, path: string, signal?: AbortSignal): Promise<ProxyResponse> {
+  const errors: string[] = [];
+  const miss = (direct: boolean) =>
+    new ModuleNotOnProxyError(`${module}: not found on any proxy${errors.length > 0 ? ` (${errors.join('; ')})` : ''}`, direct);
+  for (const entry of parseGoProxy(goproxy)) {
+    if (entry === 'off') throw new Error(`GOPROXY=off disallows downloading ${module}`);
+    if (entry === 'direct') throw miss(true);
+    try {
+      return { proxy: entry.url, data: await get(`${entry.url}/${escapeModulePath(module)}/${path}`, signal) };
+    } catch (err) {
+      if (signal?.aborted) throw err;
+      if (!(err instanceof NotFound) && !entry.fallbackOnError) throw err;
+      errors.push(err instanceof Error ? err.message : String(err));
+    }
+  }
+  throw miss(false);
+}
+
+/** Resolve a version or query ("latest", a branch, a tag) to the canonical version */
+export async function resolveModuleVersion(goproxy: string, module: string, version: string, signal?: AbortSignal): Promise<ModuleInfo & { proxy: string }> {
+  const path = version === 'latest' ? '@latest' : `@v/${escapeModulePath(version)}.info`;
+  const { proxy, data } = await proxyGet(goproxy, module, path, signal);
+  const info = JSON.parse(data.toString('utf-8')) as ModuleInfo;
+  if (!info.Version) throw new Error(`${proxy} returned no version for ${module}@${version}`);
+  return { ...info, proxy };
+}
+
+export async function downloadModuleZip(goproxy: string, module: string, version: string, signal?: AbortSignal): Promise<ProxyResponse> {
+  return proxyGet(goproxy, module, `@v/${escapeModulePath(version)}.zip`, signal);
+}
+
+export async function downloadModuleGoMod(goproxy: string, module: string, version: string, signal?: AbortSignal): Promise<string> {
+  const { data } = await proxyGet(goproxy, module, `@v/${escapeModulePath(version)}.mod`, signal);
+  return data.toString('utf-8');
+}
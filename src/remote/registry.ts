@@ -0,0 +1,222 @@
+import { createHash } from 'crypto';
+import { readFileSync } from 'fs';
+import { join } from 'path';
+import { homedir } from 'os';
+
+/**
+ * A client for pulling images from OCI distribution registries
+ * (https://github.com/opencontainers/distribution-spec): Docker Hub, GHCR,
+ * ECR, Artifact Registry, a local registry:2. Multi-platform indexes are
+ * resolved to one platform. Credentials come from the Docker config
+ * (DOCKER_CONFIG/config.json or ~/.docker/config.json), as `docker login`
+ * leaves them; credential helpers aren't run, so without an "auth" entry
+ * pulls are anonymous. Bearer token challenges are answered the way the
+ * docker CLI answers them.
+ */
+
+export interface ImageReference {
+  /** Registry host, registry-1.docker.io for Docker Hub */
+  registry: string;
+  /** library/alpine, org/app */
+  repository: string;
+  tag: string | null;
+  digest: string | null;
+}
+
+export interface Platform {
+  os: string;
+  architecture: string;
+  variant?: string;
+}
+
+export interface Descriptor {
+  mediaType: string;
+  digest: string;
+  size: number;
+  platform?: Platform;
+}
+
+export interface PulledImage {
+  reference: ImageReference;
+  /** Digest of the manifest pulled: the platform's, under a multi-platform index */
+  digest: string;
+  platform: Platform | null;
+  /** The image config (Env, Entrypoint, Labels, ...) */
+  config: Record<string, any>;
+  /** Layer blobs, base layer first, as stored (usually gzipped tars) */
+  layers: Array<{ descriptor: Descriptor; data: Buffer }>;
+}
+
+export interface PullOptions {
+  /** os/arch[/variant], e.g. linux/arm64; defaults to linux/amd64 */
+  platform?: string;
+  signal?: AbortSignal;
+}
+
+const DOCKER_HUB = 'registry-1.docker.io';
+
+const INDEX_TYPES = ['application/vnd.oci.image.index.v1+json', 'application/vnd.docker.distribution.manifest.list.v2+json'];
+const MANIFEST_TYPES = ['application/vnd.oci.image.manifest.v1+json', 'application/vnd.docker.distribution.manifest.v2+json'];
+
+/** Parse an image reference the way docker does: alpine, ghcr.io/org/app:v1, localhost:5000/app@sha256:… */
+export function parseImageReference(ref: string): ImageReference {
+  let rest = ref.trim();
+  let digest: string | null = null;
+  const at = rest.indexOf('@');
+  if (at !== -1) {
+    digest = rest.slice(at + 1);
+    rest = rest.slice(0, at);
+    if (!/^[a-z0-9]+:[a-f0-9]{32,}$/.test(digest)) throw new Error(`Invalid digest in image reference ${ref}`);
+  }
+  let tag: string | null = null;
+  const colon = rest.lastIndexOf(':');
+  if (colon > rest.lastIndexOf('/')) {
+    tag = rest.slice(colon + 1);
+    rest = rest.slice(0, colon);
+  }
+  const slash = rest.indexOf('/');
+  const first = slash === -1 ? '' : rest.slice(0, slash);
+  // The first component is a registry when it looks like a host
+  let registry = DOCKER_HUB;
+  let repository = rest;
+  if (first && (first.includes('.') || first.includes(':') || first === 'localhost')) {
+    registry = first === 'docker.io' || first === 'index.docker.io' ? DOCKER_HUB : first;
+    repository = rest.slice(slash + 1);
+  }
+  if (registry === DOCKER_HUB && !repository.includes('/')) repository = `library/${repository}`;
+  if (!repository || !/^[a-z0-9]+(?:[._-][a-z0-9]+)*(?:\/[a-z0-9]+(?:[._-][a-z0-9]+)*)*$/.test(repository)) {
+    throw new Error(`Invalid image reference ${ref}`);
+  }
+  return { registry, repository, tag: tag ?? (digest ? null : 'latest'), digest };
+}
+
+export function formatImageReference(ref: ImageReference): string {
+  const host = ref.registry === DOCKER_HUB ? '' : `${ref.registry}/`;
+  const repository = ref.registry === DOCKER_HUB ? ref.repository.replace(/^library\//, '') : ref.repository;
+  return `${host}${repository}${ref.tag ? `:${ref.tag}` : ''}${ref.digest ? `@${ref.digest}` : ''}`;
+}
+
+export function parsePlatform(platform: string): Platform {
+  const [os, architecture, variant] = platform.split('/');
+  if (!os || !architecture) throw new Error(`Invalid platform ${platform} (expected os/arch, e.g. linux/amd64)`);
+  return variant ? { os, architecture, variant } : { os, architecture };
+}
+
+export const formatPlatform = (p: Platform) => `${p.os}/${p.architecture}${p.variant ? `/${p.variant}` : ''}`;
+
+/** Basic credentials `docker login` stored for a registry, as an Authorization header value */
+export function dockerCredentials(registry: string, env: NodeJS.ProcessEnv = process.env): string | null {
+  let config: { auths?: Record<string, { auth?: string }> };
+  try {
+    config = JSON.parse(readFileSync(join(env.DOCKER_CONFIG || join(homedir(), '.docker'), 'config.json'), 'utf-8'));
+  } catch {
+    return null;
+  }
+  const keys = registry === DOCKER_HUB ? ['https://index.docker.io/v1/', 'index.docker.io', 'docker.io'] : [registry, `https://${registry}`];
+  for (const key of keys) {
+    const auth = config.auths?.[key]?.auth;
+    if (auth) return `Basic ${auth}`;
+  }
+  return null;
+}
+
+// key="value" pairs of a WWW-Authenticate challenge
+function challengeParams(header: string): Record<string, string> {
+  const params: Record<string, string> = {};
+  for (const match of header.matchAll(/(\w+)="([^"]*)"/g)) params[match[1]] = match[2];
+  return params;
+}
+
+class Registry {
+  private authorization: string | null = null;
+  private readonly credentials: string | null;
+
+  constructor(private readonly ref: ImageReference, private readonly signal?: AbortSignal) {
+    this.credentials = dockerCredentials(ref.registry);
+  }
+
+  private url(path: string): string {
+    const scheme = /^(localhost|127\.0\.0\.1)(:\d+)?$/.test(this.ref.registry) ? 'http' : 'https';
+    return `${scheme}://${this.ref.registry}/v2/${this.ref.repository}/${path}`;
+  }
+
+  async get(path: string, accept?: string[]): Promise<Response> {
+    const request = () => fetch(this.url(path), {
+      signal: this.signal,
+      headers: { ...(accept ? { accept: accept.join(', ') } : {}), ...(this.authorization ? { authorization: this.authorization } : {}) },
+    });
+    let response = await request();
+    if (response.status === 401 && !this.authorization) {
+      await this.authenticate(response.headers.get('www-authenticate') ?? '');
+      response = await request();
+    }
+    if (response.status === 401 || response.status === 403) {
+      throw new Error(`${this.ref.registry}/${this.ref.repository}: access denied (${response.status}); run docker login, or check the name`);
+    }
+    if (response.status === 404) throw new Error(`${this.url(path)}: not found`);
+    if (!response.ok) throw new Error(`GET ${this.url(path)}: ${response.status} ${response.statusText}`);
+    return response;
+  }
+
+  private async authenticate(challenge: string): Promise<void> {
+    if (/^basic/i.test(challenge)) {
+      if (!this.credentials) throw new Error(`${this.ref.registry} needs credentials; run docker login ${this.ref.registry}`);
+      this.authorization = this.credentials;
+      return;
+    }
+    const { realm, service } = challengeParams(challenge);
+    if (!/^bearer/i.test(challenge) || !realm) throw new Error(`${this.ref.registry}: unsupported authentication challenge "${challenge}"`);
+    const url = new URL(realm);
+    if (service) url.searchParams.set('service', service);
+    url.searchParams.set('scope', `repository:${this.ref.repository}:pull`);
+    const response = await fetch(url, { signal: this.signal, headers: this.credentials ? { authorization: this.credentials } : undefined });
+    if (!response.ok) throw new Error(`${this.ref.registry}: token request failed: ${response.status} ${response.statusText}`);
+    const body = await response.json() as { token?: string; access_token?: string };
+    const token = body.token ?? body.access_token;
+    if (!token) throw new Error(`${this.ref.registry}: token response has no token`);
+    this.authorization = `Bearer ${token}`;
+  }
+}
+
+/** Pull an image's manifest, config and layers into memory */
+export async function pullImage(ref: string, options: PullOptions = {}): Promise<PulledImage> {
+  const reference = parseImageReference(ref);
+  const registry = new Registry(reference, options.signal);
+  const wanted = parsePlatform(options.platform ?? 'linux/amd64');
+
+  let response = await registry.get(`manifests/${reference.digest ?? reference.tag}`, [...INDEX_TYPES, ...MANIFEST_TYPES]);
+  let manifest = await response.json() as { mediaType?: string; manifests?: Descriptor[]; config?: Descriptor; layers?: Descriptor[] };
+  let digest = response.headers.get('docker-content-digest') ?? reference.digest ?? '';
+  let platform: Platform | null = null;
+
+  if (manifest.manifests) {
+    const match = manifest.manifests.find(m => m.platform
+      && m.platform.os === wanted.os && m.platform.architecture === wanted.architecture
+      && (!wanted.variant || m.platform.variant === wanted.variant));
+    if (!match) {
+      const available = manifest.manifests.filter(m => m.platform && m.platform.os !== 'unknown').map(m => formatPlatform(m.platform!));
+      throw new Error(`${formatImageReference(reference)} has no ${formatPlatform(wanted)} image (available: ${available.join(', ') || 'none'})`);
+    }
+    platform = match.platform!;
+    digest = match.digest;
+    response = await registry.get(`manifests/${match.digest}`, MANIFEST_TYPES);
+    manifest = await response.json() as typeof manifest;
+  }
+  if (!manifest.config || !Array.isArray(manifest.layers)) {
+    throw new Error(`${formatImageReference(reference)}: unsupported manifest type ${manifest.mediaType ?? response.headers.get('content-type') ?? 'unknown'}`);
+  }
+
+  const blob = async (descriptor: Descriptor) => {
+    const data = Buffer.from(await (await registry.get(`blobs/${descriptor.digest}`)).arrayBuffer());
+    const [algorithm, hex] = descriptor.digest.split(':');
+    if (algorithm === 'sha256' && createHash('sha256').update(data).digest('hex') !== hex) {
+      throw new Error(`${formatImageReference(reference)}: blob ${descriptor.digest} doesn't match its digest`);
+    }
+    return data;
+  };
+  const config = JSON.parse((await blob(manifest.config)).toString('utf-8')) as Record<string, any>;
+  platform ??= config.os && config.architecture ? { os: config.os, architecture: config.architecture, ...(config.variant ? { variant: config.variant } : {}) } : null;
+  const layers: PulledImage['layers'] = [];
+  for (const descriptor of manifest.layers) layers.push({ descriptor, data: await blob(descriptor) });
+  return { reference, digest, platform, config, layers };
+}
@@ -0,0 +1,41 @@
+import { describe, it } from 'node:test';
+import assert from 'node:assert';
+import { hashModuleZip, hashGoMod, parseGoSum, sumdbURL } from './sumdb.js';
+
+const TINY_GO_MOD = 'module example.com/tiny\n';
+
+describe('checksum database', () => {
+  it('hashModuleZip should reproduce the go command\'s h1: hash', () => {
+    // The Sum `go mod download -json example.com/tiny@v1.0.0` reports for this zip
+    const entries = [
+      { name: 'example.com/tiny@v1.0.0/tiny.go', data: Buffer.from('package tiny\n') },
+      { name: 'example.com/tiny@v1.0.0/go.mod', data: Buffer.from(TINY_GO_MOD) },
+    ];
+    assert.strictEqual(hashModuleZip(entries), 'h1:Gj/ArbtkDyOorqulig3ML48xGAeZ3nxv0jwo2N6xPag=');
+  });
+
+  it('hashGoMod should reproduce go.sum /go.mod lines', () => {
+    assert.strictEqual(hashGoMod(Buffer.from(TINY_GO_MOD)), 'h1:fFKn0pE9rOHvRxkVEAOmYkCZu8NJKbPjfwAhP5M6EBk=');
+    // rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=
+    const quote = 'module "rsc.io/quote"\n\nrequire "rsc.io/sampler" v1.3.0\n';
+    assert.strictEqual(hashGoMod(Buffer.from(quote)), 'h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=');
+  });
+
+  it('parseGoSum should key module and go.mod lines apart', () => {
+    const sums = parseGoSum([
+      'rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=',
+      'rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=',
+      '',
+    ].join('\n'));
+
+    assert.strictEqual(sums.get('rsc.io/quote v1.5.2'), 'h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=');
+    assert.strictEqual(sums.get('rsc.io/quote v1.5.2/go.mod'), 'h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=');
+    assert.strictEqual(sums.size, 2);
+  });
+
+  it('sumdbURL should follow GOSUMDB', () => {
+    assert.strictEqual(sumdbURL('sum.golang.org'), 'https://sum.golang.org');
+    assert.strictEqual(sumdbURL('sum.example.com+abc123 https://sum.example.com/db/'), 'https://sum.example.com/db');
+    assert.strictEqual(sumdbURL('off'), null);
+  });
+});
@@ -0,0 +1,325 @@
+import { basename, extname } from 'path';
+import { DirectedGraph } from 'graphology';
+import { buildExportGraph, clusterGraph, type ExportGraph, type Cluster } from '../export/graph.js';
+import { relabelGraph, type LabelOptions } from '../export/labels.js';
+import { applyMetric } from '../export/metrics.js';
+import { toMermaid, type MermaidOptions } from '../export/mermaid.js';
+import { toPackageGraph } from '../graph/model.js';
+import { findCycles } from '../graph/algorithms.js';
+import { graphMetrics } from '../temporal/history.js';
+import { longestChain } from '../rules/budgets.js';
+import { calculateHealthScore } from '../health/index.js';
+import { findGoModules } from '../golang/modules.js';
+import type { DriftMetrics } from '../drift/index.js';
+import { formatGlossaryMarkdown, type ModuleGlossaryEntry } from '../supply-chain/glossary.js';
+import { concentrationRisks, packageCoverage, percentCovered, unprofiledPackages, type ConcentrationRisk, type CoverageData, type CoverageRiskOptions } from '../golang/coverage.js';
+
+/**
+ * A single ARCHITECTURE.md meant to be committed: the components (package
+ * communities), mermaid diagrams of how they depend on each other, metrics
+ * and the external modules with the packages importing them. Nothing in it
+ * depends on when or by which depwire version it was generated, so running
+ * it again (by hand or through go:generate) only changes the file when the
+ * architecture changed — except for the optional glossary of upstream
+ * metadata, which changes when the modules' upstreams do, and the optional
+ * test coverage, which changes with the tests.
+ */
+
+const GENERATED_HEADER = '<!-- Code generated by depwire report; DO NOT EDIT. -->';
+
+/** Package diagrams past this many packages draw the components only */
+const MAX_DIAGRAM_PACKAGES = 40;
+
+const LANGUAGES: Record<string, string> = {
+  '.go': 'Go', '.ts': 'TypeScript', '.tsx': 'TypeScript', '.js': 'JavaScript', '.jsx': 'JavaScript', '.mjs': 'JavaScript', '.cjs': 'JavaScript',
+  '.py': 'Python', '.rs': 'Rust', '.c': 'C', '.h': 'C', '.cpp': 'C++', '.cc': 'C++', '.hpp': 'C++', '.java': 'Java', '.cs': 'C#', '.proto': 'Protocol Buffers',
+};
+
+export interface ArchitecturePackage {
+  /** Package directory */
+  package: string;
+  label: string;
+  /** Index into the report's components */
+  component: number;
+  files: number;
+  symbols: number;
+  loc: number;
+  fanIn: number;
+  fanOut: number;
+  /** fanOut / (fanIn + fanOut); null for isolated packages */
+  instability: number | null;
+}
+
+export interface ArchitectureModule {
+  path: string;
+  version: string;
+  indirect: boolean;
+  /** Labels of the packages importing it */
+  importers: string[];
+}
+
+export interface ArchitectureReport {
+  project: string;
+  metrics: DriftMetrics;
+  /** Files per language */
+  languages: Record<string, number>;
+  health: { score: number; grade: string; dimensions: Array<{ name: string; score: number; grade: string; details: string }> };
+  components: Cluster[];
+  packages: ArchitecturePackage[];
+  /** Package dependencies, by label, with the number of references behind each */
+  dependencies: Array<{ from: string; to: string; weight: number }>;
+  /** Package cycles, by label */
+  cycles: string[][];
+  /** Longest package import chain, by label */
+  longestChain: string[];
+  modules: ArchitectureModule[];
+  /** Upstream metadata of the modules, with --glossary */
+  glossary?: ModuleGlossaryEntry[];
+  /** Test coverage from go test -coverprofile, with --coverprofile */
+  coverage?: ArchitectureCoverage;
+}
+
+export interface ArchitectureCoverage {
+  mode: string;
+  /** Percentage of the workspace's statements covered */
+  total: number;
+  /** Packages the profiles cover, by label */
+  packages: Array<{ label: string; coverage: number; statements: number }>;
+  /** Heavily depended-on packages with poor coverage, riskiest first */
+  risks: Array<ConcentrationRisk & { label: string }>;
+  /** Packages with functions the profiles have no entry for, by label: their coverage is unknown */
+  unprofiled: string[];
+  thresholds: Required<CoverageRiskOptions>;
+}
+
+export interface ArchitectureReportOptions {
+  labels?: LabelOptions;
+  coverage?: CoverageData;
+  coverageRisk?: CoverageRiskOptions;
+}
+
+export async function buildArchitectureReport(graph: DirectedGraph, projectRoot: string, options: ArchitectureReportOptions = {}): Promise<ArchitectureReport> {
+  const packageGraph = buildExportGraph(graph, projectRoot);
+  await applyMetric(packageGraph, 'loc', { graph, projectRoot, level: 'package' });
+  await applyMetric(packageGraph, 'instability', { graph, projectRoot, level: 'package' });
+  relabelGraph(packageGraph, projectRoot, options.labels ?? {});
+  const components = clusterGraph(packageGraph);
+  const label = (pkg: string) => packageGraph.hasNode(pkg) ? packageGraph.getNodeAttribute(pkg, 'label') : pkg;
+
+  const packages = packageGraph.mapNodes((pkg, attrs): ArchitecturePackage => ({
+    package: pkg,
+    label: attrs.label,
+    component: attrs.cluster ?? 0,
+    files: attrs.files,
+    symbols: attrs.symbols,
+    loc: typeof attrs.loc === 'number' ? attrs.loc : 0,
+    fanIn: packageGraph.inNeighbors(pkg).filter(n => n !== pkg).length,
+    fanOut: packageGraph.outNeighbors(pkg).filter(n => n !== pkg).length,
+    instability: typeof attrs.instability === 'number' ? attrs.instability : null,
+  })).sort((a, b) => a.label.localeCompare(b.label));
+
+  const dependencies = packageGraph.mapEdges((_edge, attrs, source, target) => ({ from: label(source), to: label(target), weight: attrs.weight }))
+    .sort((a, b) => a.from.localeCompare(b.from) || a.to.localeCompare(b.to));
+
+  const files = new Set<string>();
+  graph.forEachNode((_node, attrs) => files.add(attrs.filePath));
+  const languages: Record<string, number> = {};
+  for (const file of files) {
+    const language = LANGUAGES[extname(file).toLowerCase()] ?? 'Other';
+    languages[language] = (languages[language] ?? 0) + 1;
+  }
+
+  const health = calculateHealthScore(graph, projectRoot);
+  const plain = toPackageGraph(graph);
+  const chain = longestChain(plain);
+  const coverage = options.coverage ? architectureCoverage(graph, packageGraph, options.coverage, options.coverageRisk ?? {}, label) : undefined;
+  return {
+    project: basename(projectRoot),
+    metrics: { ...graphMetrics(graph, projectRoot), depth: chain.depth },
+    languages,
+    health: {
+      score: health.overall,
+      grade: health.grade,
+      dimensions: health.dimensions.map(d => ({ name: d.name, score: d.score, grade: d.grade, details: d.details })),
+    },
+    components,
+    packages,
+    dependencies,
+    cycles: findCycles(plain).filter(c => c.length > 1).map(c => c.map(label)),
+    longestChain: chain.packages.map(label),
+    modules: externalModules(graph, projectRoot, label),
+    ...(coverage ? { coverage } : {}),
+  };
+}
+
+function architectureCoverage(graph: DirectedGraph, packageGraph: ExportGraph, data: CoverageData, risk: CoverageRiskOptions, label: (pkg: string) => string): ArchitectureCoverage {
+  const thresholds = { maxCoverage: risk.maxCoverage ?? 50, minDependents: risk.minDependents ?? 3 };
+  const counts = [...packageCoverage(data)].filter(([pkg]) => packageGraph.hasNode(pkg));
+  const statements = counts.reduce((n, [, c]) => n + c.statements, 0);
+  const covered = counts.reduce((n, [, c]) => n + c.covered, 0);
+  return {
+    mode: data.mode,
+    total: percentCovered({ statements, covered }),
+    packages: counts.map(([pkg, c]) => ({ label: label(pkg), coverage: percentCovered(c), statements: c.statements })).sort((a, b) => a.label.localeCompare(b.label)),
+    risks: concentrationRisks(packageGraph, data, thresholds).map(r => ({ ...r, label: label(r.package) })),
+    unprofiled: unprofiledPackages(graph, data, packageGraph).map(label),
+    thresholds,
+  };
+}
+
+// Requirements of the workspace's go.mod files, with the packages importing each
+function externalModules(graph: DirectedGraph, projectRoot: string, label: (pkg: string) => string): ArchitectureModule[] {
+  const modules = findGoModules(projectRoot);
+  const local = new Set(modules.map(m => m.path));
+  const external = buildExportGraph(graph, projectRoot, { scope: 'external' });
+  const found = new Map<string, ArchitectureModule>();
+  for (const mod of modules) {
+    for (const req of mod.mod.require) {
+      if (local.has(req.path) || found.has(req.path)) continue;
+      const node = `mod:${req.path}`;
+      const importers = external.hasNode(node) ? external.inNeighbors(node).map(label).sort() : [];
+      found.set(req.path, { path: req.path, version: req.version, indirect: req.indirect, importers });
+    }
+  }
+  return [...found.values()].sort((a, b) => Number(a.indirect) - Number(b.indirect) || a.path.localeCompare(b.path));
+}
+
+export interface ArchitectureMarkdownOptions {
+  /** Lines standing in for a diagram (default: a mermaid fence); name is components, packages or cycles */
+  diagram?: (name: string, graph: ExportGraph, options: MermaidOptions) => string[];
+  /** The generated-file comment and the go:generate note, which only make sense in the repository (default true) */
+  generated?: boolean;
+}
+
+/** Markdown for committing as ARCHITECTURE.md */
+export function formatArchitectureMarkdown(report: ArchitectureReport, options: ArchitectureMarkdownOptions = {}): string {
+  const lines: string[] = [];
+  const { metrics } = report;
+  const diagram = options.diagram ?? ((_name, graph, mermaid) => ['```mermaid', toMermaid(graph, mermaid).trimEnd(), '```', '']);
+  const fence = (name: string, graph: ExportGraph, mermaid: MermaidOptions = {}) => lines.push(...diagram(name, graph, mermaid));
+  const code = (value: string) => `\`${value}\``;
+
+  if (options.generated !== false) lines.push(GENERATED_HEADER, '');
+  lines.push(`# ${report.project} architecture`, '');
+  if (options.generated !== false) {
+    lines.push('> Generated by `depwire report --format=markdown`. To keep it current, add');
+    lines.push('> `//go:generate depwire report --format=markdown -o ARCHITECTURE.md` to a Go file in the project root and run `go generate`.', '');
+  }
+
+  lines.push('## Overview', '');
+  const languages = Object.entries(report.languages).sort((a, b) => b[1] - a[1] || a[0].localeCompare(b[0]));
+  lines.push(`- **${metrics.packages}** packages in **${report.components.length}** components, ${metrics.files} files, ${metrics.symbols} symbols`);
+  lines.push(`- Languages: ${languages.map(([language, count]) => `${language} (${count} files)`).join(', ')}`);
+  lines.push(`- Health: **${report.health.score}/100 (${report.health.grade})**`);
+  lines.push(`- External modules: ${metrics.directModules} direct, ${metrics.indirectModules} indirect`, '');
+
+  lines.push('## Components', '');
+  lines.push('Components are the communities of the package graph: packages that reference each other more than they reference the rest.', '');
+  const componentOf = new Map(report.packages.map(p => [p.label, p.component]));
+  const componentRows = report.components.map(c => {
+    const members = report.packages.filter(p => p.component === c.id);
+    const uses = [...new Set(report.dependencies
+      .filter(d => componentOf.get(d.from) === c.id && componentOf.get(d.to) !== c.id)
+      .map(d => report.components[componentOf.get(d.to)!].label))].sort();
+    return `| ${code(c.label)} | ${members.length} | ${members.reduce((n, p) => n + p.files, 0)} | ${members.reduce((n, p) => n + p.loc, 0)} | ${uses.map(code).join(', ') || '—'} |`;
+  });
+  lines.push('| Component | Packages | Files | Lines | Depends on |', '| --- | ---: | ---: | ---: | --- |', ...componentRows, '');
+  if (report.components.length > 1) fence('components', componentGraph(report));
+
+  lines.push('## Package dependencies', '');
+  if (report.packages.length <= MAX_DIAGRAM_PACKAGES) {
+    fence('packages', packageGraph(report, report.packages.map(p => p.label)), { clusters: packageClusters(report) });
+  } else {
+    lines.push(`${report.packages.length} packages are too many to draw; the component diagram above condenses them.`, '');
+  }
+  if (report.longestChain.length > 1) {
+    lines.push(`Longest import chain (${report.longestChain.length - 1} steps): ${report.longestChain.map(code).join(' → ')}`, '');
+  }
+
+  lines.push('## Metrics', '');
+  lines.push('| Metric | Value |', '| --- | ---: |');
+  lines.push(`| Packages | ${metrics.packages} |`, `| Package dependencies | ${metrics.packageEdges} |`, `| Import depth | ${metrics.depth} |`);
+  lines.push(`| Package cycles | ${metrics.cycles} |`, `| Largest cycle | ${metrics.largestCycle} |`, `| Symbol edges | ${metrics.edges} |`, '');
+  lines.push('| Health dimension | Score | Grade | |', '| --- | ---: | :---: | --- |');
+  for (const d of report.health.dimensions) lines.push(`| ${d.name} | ${d.score} | ${d.grade} | ${d.details} |`);
+  lines.push('');
+  lines.push('| Package | Files | Lines | Symbols | Fan-in | Fan-out | Instability |', '| --- | ---: | ---: | ---: | ---: | ---: | ---: |');
+  for (const p of report.packages) {
+    lines.push(`| ${code(p.label)} | ${p.files} | ${p.loc} | ${p.symbols} | ${p.fanIn} | ${p.fanOut} | ${p.instability === null ? '—' : p.instability.toFixed(2)} |`);
+  }
+  lines.push('');
+
+  if (report.coverage) {
+    const { coverage } = report;
+    const percent = (value: number) => `${value.toFixed(1)}%`;
+    lines.push('## Test coverage', '');
+    lines.push(`${percent(coverage.total)} of the statements in ${coverage.packages.length} packages are covered by tests.`, '');
+    if (coverage.risks.length === 0) {
+      lines.push(`No package that ${coverage.thresholds.minDependents} or more packages depend on is covered below ${coverage.thresholds.maxCoverage}%.`, '');
+    } else {
+      lines.push(`Concentration risks: packages that ${coverage.thresholds.minDependents} or more packages depend on, directly or through others, with under ${coverage.thresholds.maxCoverage}% coverage. A bug in them reaches every dependent.`, '');
+      lines.push('| Package | Coverage | Statements | Fan-in | Dependents |', '| --- | ---: | ---: | ---: | ---: |');
+      for (const r of coverage.risks) lines.push(`| ${code(r.label)} | ${percent(r.coverage)} | ${r.statements} | ${r.fanIn} | ${r.dependents} |`);
+      lines.push('');
+    }
+    if (coverage.unprofiled.length > 0) {
+      lines.push(`No coverage data for ${coverage.unprofiled.length} packages (no tests ran them, or \`-coverpkg\` left them out), so they aren't judged: ${coverage.unprofiled.map(code).join(', ')}.`, '');
+    }
+  }
+
+  if (report.cycles.length > 0) {
+    lines.push('## Dependency cycles', '');
+    for (const cycle of report.cycles) lines.push(`- ${cycle.map(code).join(' → ')} → ${code(cycle[0])}`);
+    lines.push('');
+    fence('cycles', packageGraph(report, [...new Set(report.cycles.flat())]));
+  }
+
+  lines.push('## External dependencies', '');
+  if (report.modules.length === 0) {
+    lines.push('No external Go modules are required.', '');
+  } else {
+    lines.push('| Module | Version | | Imported by |', '| --- | --- | --- | --- |');
+    for (const m of report.modules) {
+      const importers = m.importers.length === 0 ? '—' : m.importers.length > 3
+        ? `${m.importers.slice(0, 3).map(code).join(', ')} and ${m.importers.length - 3} more`
+        : m.importers.map(code).join(', ');
+      lines.push(`| ${code(m.path)} | ${m.version} | ${m.indirect ? 'indirect' : 'direct'} | ${importers} |`);
+    }
+    lines.push('');
+  }
+  if (report.glossary && report.modules.length > 0) lines.push(...formatGlossaryMarkdown(report.glossary).split('\n'));
+  return lines.join('\n');
+}
+
+/** The packages named, and the dependencies among them, as an export graph to draw */
+export function packageGraph(report: ArchitectureReport, labels: string[]): ExportGraph {
+  const graph: ExportGraph = new DirectedGraph();
+  const wanted = new Set(labels);
+  // Aliases can give packages the same label; those are drawn as one
+  for (const p of report.packages) if (wanted.has(p.label)) graph.mergeNode(p.label, { label: p.label, package: p.package, files: p.files, symbols: p.symbols });
+  for (const d of report.dependencies) if (d.from !== d.to && graph.hasNode(d.from) && graph.hasNode(d.to)) graph.mergeEdge(d.from, d.to, { weight: d.weight });
+  return graph;
+}
+
+/** The components as clusters of package labels, for mermaid subgraphs */
+export function packageClusters(report: ArchitectureReport): Cluster[] {
+  return report.components.map(c => ({ ...c, members: report.packages.filter(p => p.component === c.id).map(p => p.label) }));
+}
+
+/** One node per component, the package dependencies between components summed into its edges */
+export function componentGraph(report: ArchitectureReport): ExportGraph {
+  const graph: ExportGraph = new DirectedGraph();
+  const componentOf = new Map(report.packages.map(p => [p.label, p.component]));
+  for (const c of report.components) {
+    const members = report.packages.filter(p => p.component === c.id);
+    graph.addNode(`c${c.id}`, { label: c.label, package: '', files: members.reduce((n, p) => n + p.files, 0), symbols: members.reduce((n, p) => n + p.symbols, 0) });
+  }
+  for (const d of report.dependencies) {
+    const from = `c${componentOf.get(d.from)}`;
+    const to = `c${componentOf.get(d.to)}`;
+    if (from === to) continue;
+    if (graph.hasEdge(from, to)) graph.updateEdgeAttribute(from, to, 'weight', (w) => (w ?? 0) + d.weight);
+    else graph.addEdge(from, to, { weight: d.weight });
+  }
+  return graph;
+}
@@ -0,0 +1,57 @@
+import { toSvg, escapeXml } from '../export/svg.js';
+import type { ExportGraph } from '../export/graph.js';
+import type { MermaidOptions } from '../export/mermaid.js';
+import { markdownToHtml } from '../site/html.js';
+import { formatArchitectureMarkdown, type ArchitectureReport } from './index.js';
+
+/**
+ * The architecture report for the wiki instead of the repository. Wikis
+ * don't render mermaid, so each diagram becomes an SVG file to attach to
+ * the page, and the notes about committing and regenerating the file are
+ * left out. The confluence profile is Confluence's storage format (the
+ * XHTML its REST API and "Insert markup" take), with the diagrams as
+ * attachment images; notion is plain Markdown with image links, which
+ * Notion, and most wikis that import Markdown with its images, take as is.
+ */
+
+export type WikiProfile = 'confluence' | 'notion';
+
+export const WIKI_PROFILES: WikiProfile[] = ['confluence', 'notion'];
+
+export interface WikiAttachment {
+  /** File name the page refers to it by */
+  name: string;
+  content: string;
+}
+
+export interface WikiExport {
+  document: string;
+  attachments: WikiAttachment[];
+}
+
+const DIAGRAM_TITLES: Record<string, string> = {
+  components: 'Component dependencies',
+  packages: 'Package dependencies',
+  cycles: 'Dependency cycles',
+};
+
+/** The report for a wiki; attachment names start with prefix ("architecture" gives architecture-components.svg) */
+export function formatArchitectureWiki(report: ArchitectureReport, profile: WikiProfile, prefix: string): WikiExport {
+  if (!WIKI_PROFILES.includes(profile)) throw new Error(`Unknown export profile "${profile}" (expected ${WIKI_PROFILES.join(' or ')})`);
+  const attachments: WikiAttachment[] = [];
+  const diagram = (name: string, graph: ExportGraph, options: MermaidOptions) => {
+    const title = DIAGRAM_TITLES[name] ?? name;
+    // The SVG export colors clusters by node attribute rather than member list
+    options.clusters?.forEach((cluster, i) => cluster.members.forEach(m => graph.hasNode(m) && graph.setNodeAttribute(m, 'cluster', i)));
+    const file = `${prefix}-${name}.svg`;
+    attachments.push({ name: file, content: toSvg(graph, { name: title, clusters: options.clusters }) });
+    return [`![${title}](${file})`, ''];
+  };
+  const markdown = formatArchitectureMarkdown(report, { diagram, generated: false });
+  if (profile === 'notion') return { document: markdown, attachments };
+
+  const document = markdownToHtml(markdown, {
+    image: (alt, src) => `<p><ac:image ac:alt="${escapeXml(alt)}"><ri:attachment ri:filename="${escapeXml(src)}" /></ac:image></p>`,
+  });
+  return { document: document + '\n', attachments };
+}
@@ -0,0 +1,86 @@
+import { readdirSync, readFileSync, writeFileSync, mkdirSync, existsSync } from 'fs';
+import { join } from 'path';
+import type { DirectedGraph } from 'graphology';
+import { LOCKFILE, type LayerEdge } from './lockfile.js';
+
+/**
+ * Architecture decision record stubs for cross-layer edges the lockfile
+ * doesn't approve yet: one Markdown file per new layer edge, numbered after
+ * the records already in the directory, with the context prefilled from
+ * the file edges behind it and the decision left to whoever adds the
+ * dependency. Each stub carries a marker naming its edge, so a stub is
+ * written once however many lint runs see the edge.
+ */
+
+const MARKER = /<!-- depwire-edge: (.+?) -->/;
+/** File edges listed in a stub's context */
+const MAX_LISTED_EDGES = 10;
+
+export interface AdrStub {
+  edge: string;
+  file: string;
+  number: number;
+}
+
+export interface AdrOptions {
+  /** YYYY-MM-DD for the record; defaults to today */
+  date?: string;
+}
+
+/** Write a stub for every edge without one in dir; returns the stubs written */
+export function writeAdrStubs(dir: string, edges: LayerEdge[], fileGraph: DirectedGraph, options: AdrOptions = {}): AdrStub[] {
+  if (!existsSync(dir)) mkdirSync(dir, { recursive: true });
+  const recorded = new Set<string>();
+  let last = 0;
+  for (const name of readdirSync(dir)) {
+    const number = /^(\d+)-.*\.md$/.exec(name);
+    if (!number) continue;
+    last = Math.max(last, parseInt(number[1], 10));
+    const marker = MARKER.exec(readFileSync(join(dir, name), 'utf-8'));
+    if (marker) recorded.add(marker[1]);
+  }
+
+  const written: AdrStub[] = [];
+  const date = options.date ?? new Date().toISOString().slice(0, 10);
+  for (const edge of edges) {
+    const key = `${edge.from} -> ${edge.to}`;
+    if (recorded.has(key)) continue;
+    const number = ++last;
+    const file = join(dir, `${String(number).padStart(4, '0')}-${slug(edge.from)}-depends-on-${slug(edge.to)}.md`);
+    writeFileSync(file, adrStub(edge, number, fileGraph, date));
+    written.push({ edge: key, file, number });
+  }
+  return written;
+}
+
+/** The Markdown of one stub: context filled in, decision and consequences to write */
+export function adrStub(edge: LayerEdge, number: number, fileGraph: DirectedGraph, date: string): string {
+  const lines: string[] = [];
+  lines.push(`# ${number}. Let ${edge.from} depend on ${edge.to}`, '');
+  lines.push(`<!-- depwire-edge: ${edge.from} -> ${edge.to} -->`, '');
+  lines.push(`- Status: proposed`, `- Date: ${date}`, '');
+
+  lines.push('## Context', '');
+  lines.push(`\`${edge.from}\` does not depend on \`${edge.to}\` in ${LOCKFILE}. It now does, through ${edge.files.length} file edge${edge.files.length === 1 ? '' : 's'}:`, '');
+  for (const [source, target] of edge.files.slice(0, MAX_LISTED_EDGES)) {
+    const witness = fileGraph.hasEdge(source, target) ? fileGraph.getEdgeAttribute(source, target, 'witness') : undefined;
+    const weight = fileGraph.hasEdge(source, target) ? fileGraph.getEdgeAttribute(source, target, 'weight') : undefined;
+    const at = witness ? `${source}:${witness.line}` : source;
+    lines.push(`- \`${at}\` → \`${target}\`${weight ? ` (${weight} reference${weight === 1 ? '' : 's'})` : ''}`);
+  }
+  if (edge.files.length > MAX_LISTED_EDGES) lines.push(`- … and ${edge.files.length - MAX_LISTED_EDGES} more`);
+  lines.push('');
+  lines.push('<!-- Why is the dependency needed? Which alternatives (an interface in the lower layer, moving the code, an event) were considered? -->', '');
+
+  lines.push('## Decision', '');
+  lines.push(`<!-- Accept or reject the ${edge.from} → ${edge.to} edge, and why. -->`, '');
+
+  lines.push('## Consequences', '');
+  lines.push(`<!-- What the coupling costs: changes to ${edge.to} that now affect ${edge.from}, build and test impact. -->`, '');
+  lines.push(`Once accepted, run \`depwire freeze\` so ${LOCKFILE} approves the edge; if rejected, remove the dependency.`, '');
+  return lines.join('\n');
+}
+
+function slug(layer: string): string {
+  return layer.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-|-$/g, '') || 'root';
+}
@@ -0,0 +1,244 @@
+import { relative } from 'path';
+import { createRule } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { toPackageGraph } from '../graph/model.js';
+import { stronglyConnectedComponents } from '../graph/algorithms.js';
+import { findGoModules } from '../golang/modules.js';
+import { loadGoProject } from '../golang/packages.js';
+import { goListDeps, type GoListPackage } from '../golang/deps.js';
+import { findGoModuleRoot } from '../golang/toolchain.js';
+
+export interface DependencyBudgets {
+  /** Direct requirements across the project's go.mod files (workspace modules excluded) */
+  maxDirectModules?: number;
+  /** Non-standard-library packages each Go binary (package main) pulls in, its own included */
+  maxBinaryClosure?: number;
+  /** Longest chain of package imports, cycles counted as one step */
+  maxDepth?: number;
+}
+
+const BUDGET_KEYS: Array<keyof DependencyBudgets> = ['maxDirectModules', 'maxBinaryClosure', 'maxDepth'];
+
+/** Validate the "budgets" section of a lint config */
+export function parseBudgets(value: unknown, source: string): DependencyBudgets {
+  if (typeof value !== 'object' || value === null || Array.isArray(value)) {
+    throw new Error(`Lint config ${source}: "budgets" must be an object`);
+  }
+  const budgets: DependencyBudgets = {};
+  for (const [key, limit] of Object.entries(value)) {
+    if (!BUDGET_KEYS.includes(key as keyof DependencyBudgets)) {
+      throw new Error(`Lint config ${source}: unknown budget "${key}" (expected ${BUDGET_KEYS.join(', ')})`);
+    }
+    if (typeof limit !== 'number' || !Number.isInteger(limit) || limit < 0) {
+      throw new Error(`Lint config ${source}: budget "${key}" must be a non-negative integer`);
+    }
+    budgets[key as keyof DependencyBudgets] = limit;
+  }
+  return budgets;
+}
+
+function list(items: string[], limit = 5): string {
+  return items.length > limit ? `${items.slice(0, limit).join(', ')}, … (${items.length - limit} more)` : items.join(', ');
+}
+
+export interface ImportChain {
+  /** Packages along the chain, each importing the next */
+  packages: string[];
+  /** Its length with each import cycle on the way counted as one step */
+  depth: number;
+}
+
+// Shortest import path from one member of a cycle to another, staying inside it
+function pathWithin(graph: ReturnType<typeof toPackageGraph>, from: string, to: string, inside: (pkg: string) => boolean): string[] {
+  const previous = new Map<string, string | null>([[from, null]]);
+  const queue = [from];
+  while (queue.length > 0 && !previous.has(to)) {
+    const pkg = queue.shift()!;
+    for (const next of graph.outNeighbors(pkg)) {
+      if (previous.has(next) || !inside(next)) continue;
+      previous.set(next, pkg);
+      queue.push(next);
+    }
+  }
+  const path: string[] = [];
+  for (let pkg: string | null | undefined = to; pkg; pkg = previous.get(pkg)) path.unshift(pkg);
+  return path;
+}
+
+/** The longest import chain, walking the condensation of the package graph */
+export function longestChain(graph: ReturnType<typeof toPackageGraph>): ImportChain {
+  const components = stronglyConnectedComponents(graph);
+  const componentOf = new Map<string, number>();
+  components.forEach((members, i) => members.forEach(m => componentOf.set(m, i)));
+
+  // Per component: the components the longest chain from it passes through, and the import it leaves by.
+  // The condensation is acyclic, so the recursion ends; it is only as deep as the chain
+  const chains = new Map<number, { length: number; exit?: [string, string] }>();
+  const chainFrom = (component: number): { length: number; exit?: [string, string] } => {
+    const cached = chains.get(component);
+    if (cached) return cached;
+    let best: { length: number; exit?: [string, string] } = { length: 1 };
+    for (const member of components[component]) {
+      for (const next of graph.outNeighbors(member)) {
+        const target = componentOf.get(next)!;
+        if (target === component) continue;
+        const length = chainFrom(target).length + 1;
+        if (length > best.length) best = { length, exit: [member, next] };
+      }
+    }
+    chains.set(component, best);
+    return best;
+  };
+
+  let start = -1;
+  let longest = 0;
+  components.forEach((_members, i) => {
+    const { length } = chainFrom(i);
+    if (length > longest) {
+      longest = length;
+      start = i;
+    }
+  });
+  if (start < 0) return { packages: [], depth: 0 };
+
+  // Follow the exits; inside a cycle, the chain goes from where it entered to where it leaves
+  const packages: string[] = [];
+  let component = start;
+  let entry = chains.get(start)!.exit?.[0] ?? components[start][0];
+  for (;;) {
+    const { exit } = chains.get(component)!;
+    if (!exit) {
+      packages.push(entry);
+      break;
+    }
+    packages.push(...pathWithin(graph, entry, exit[0], pkg => componentOf.get(pkg) === component));
+    entry = exit[1];
+    component = componentOf.get(entry)!;
+  }
+  return { packages, depth: longest - 1 };
+}
+
+interface BinaryClosure {
+  binary: string;
+  /** Project-relative package directory */
+  dir?: string;
+  packages: string[];
+  /** Packages from modules outside the project */
+  external: string[];
+}
+
+// From go list -deps: the exact build of each main package
+function closuresFromList(listed: GoListPackage[], projectRoot: string): BinaryClosure[] {
+  const byPath = new Map(listed.map(pkg => [pkg.ImportPath, pkg]));
+  return listed
+    .filter(pkg => pkg.Name === 'main' && pkg.Module?.Main && !pkg.DepOnly)
+    .map(main => {
+      const seen = new Set<string>([main.ImportPath]);
+      const queue = [main.ImportPath];
+      while (queue.length > 0) {
+        for (const imp of byPath.get(queue.shift()!)?.Imports ?? []) {
+          if (!seen.has(imp)) {
+            seen.add(imp);
+            queue.push(imp);
+          }
+        }
+      }
+      const packages = [...seen].filter(path => !byPath.get(path)?.Standard).sort();
+      const external = packages.filter(path => !byPath.get(path)?.Module?.Main);
+      return { binary: main.ImportPath, dir: main.Dir ? relative(projectRoot, main.Dir) || '.' : undefined, packages, external };
+    });
+}
+
+// Without a toolchain: local packages are followed, third-party imports counted but not expanded
+async function closuresFromSource(projectRoot: string): Promise<BinaryClosure[]> {
+  const project = await loadGoProject(projectRoot);
+  const byPath = new Map([...project.packages.values()].filter(p => p.importPath).map(p => [p.importPath!, p]));
+  // The project's own packages first: a module path like "myapp" has no dot either
+  const isStandard = (path: string) => !byPath.has(path) && !project.modules.moduleForImport(path) && !path.split('/')[0].includes('.');
+  return [...project.packages.values()]
+    .filter(pkg => pkg.name === 'main' && pkg.importPath)
+    .map(main => {
+      const seen = new Set<string>([main.importPath!]);
+      const queue = [main.importPath!];
+      while (queue.length > 0) {
+        for (const imp of byPath.get(queue.shift()!)?.imports.keys() ?? []) {
+          if (isStandard(imp) || seen.has(imp)) continue;
+          seen.add(imp);
+          queue.push(imp);
+        }
+      }
+      const packages = [...seen].sort();
+      return { binary: main.importPath!, dir: main.dir, packages, external: packages.filter(path => !project.modules.moduleForImport(path)) };
+    });
+}
+
+/**
+ * A rule that fails when the project outgrows a dependency budget: too many
+ * direct module requirements, a binary that builds too many packages, or an
+ * import chain that is too deep. Findings give the count, the budget and
+ * what makes up the excess.
+ *
+ *   dependencyBudgetRule({ maxDirectModules: 40, maxDepth: 12 })
+ */
+export function dependencyBudgetRule(budgets: DependencyBudgets, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('dependency-budget', async (ctx) => {
+    if (budgets.maxDirectModules !== undefined) {
+      const modules = findGoModules(ctx.projectRoot);
+      const local = new Set(modules.map(m => m.path));
+      const direct = new Map<string, { file: string; line?: number }>();
+      for (const mod of modules) {
+        for (const req of mod.mod.require) {
+          if (!req.indirect && !local.has(req.path) && !direct.has(req.path)) direct.set(req.path, { file: mod.goModFile, line: req.line });
+        }
+      }
+      if (direct.size > budgets.maxDirectModules) {
+        // Point at the last requirement — usually the one a PR just added
+        const [lastPath, last] = [...direct].pop()!;
+        ctx.report({
+          message: `${direct.size} direct external modules exceed the budget of ${budgets.maxDirectModules} (+${direct.size - budgets.maxDirectModules}): ${list([...direct.keys()].sort())}`,
+          file: last.file,
+          line: last.line,
+          target: lastPath,
+        });
+      }
+    }
+
+    if (budgets.maxBinaryClosure !== undefined) {
+      let closures: BinaryClosure[];
+      let approximate = false;
+      const moduleRoot = findGoModuleRoot(ctx.projectRoot);
+      try {
+        if (!moduleRoot) throw new Error('no go.mod');
+        closures = closuresFromList(await goListDeps(moduleRoot, ['./...']), ctx.projectRoot);
+      } catch {
+        closures = await closuresFromSource(ctx.projectRoot);
+        approximate = true;
+      }
+      for (const closure of closures) {
+        if (closure.packages.length <= budgets.maxBinaryClosure) continue;
+        const { external } = closure;
+        ctx.report({
+          message: `${closure.binary} builds ${closure.packages.length} packages, over the budget of ${budgets.maxBinaryClosure} (+${closure.packages.length - budgets.maxBinaryClosure})` +
+            `${approximate ? ' (counted without go list: third-party imports not expanded)' : ''}` +
+            `${external.length ? `; from modules outside the project: ${list(external)}` : ''}`,
+          file: closure.dir,
+          target: closure.binary,
+        });
+      }
+    }
+
+    if (budgets.maxDepth !== undefined) {
+      const { packages: chain, depth } = longestChain(toPackageGraph(ctx.graph));
+      if (depth > budgets.maxDepth) {
+        ctx.report({
+          message: `Import chain of depth ${depth} exceeds the budget of ${budgets.maxDepth}: ${chain.join(' → ')}`,
+          source: chain[0],
+          target: chain[chain.length - 1],
+        });
+      }
+    }
+  }, {
+    description: definition.description ?? 'The project must stay within its dependency budgets',
+    severity: definition.severity ?? 'error',
+  });
+}
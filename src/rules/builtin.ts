@@ -0,0 +1,70 @@
+import { findCycles } from '../graph/algorithms.js';
+import { createRule, matchesAny } from './engine.js';
+import type { SourcePosition } from '../graph/model.js';
+import type { Rule, RuleContext, RuleDefinitionOptions } from './types.js';
+
+export interface ForbiddenDependency {
+  /** Glob(s) for the importing files */
+  from: string | string[];
+  /** Glob(s) for the files they must not depend on */
+  to: string | string[];
+  /** Glob(s) for importing files that are exempt */
+  except?: string | string[];
+}
+
+/**
+ * Build a rule that forbids file-level dependencies between two sets of files.
+ *
+ *   forbidDependency('no-db-in-models', { from: 'src/models/**', to: 'src/db/**' })
+ */
+export function forbidDependency(
+  id: string,
+  forbidden: ForbiddenDependency,
+  options: RuleDefinitionOptions = {}
+): Rule {
+  return createRule(id, (ctx) => {
+    ctx.fileGraph.forEachEdge((_edge, attrs, source, target) => {
+      if (!matchesAny(source, forbidden.from) || matchesAny(source, forbidden.except)) return;
+      if (!matchesAny(target, forbidden.to)) return;
+      ctx.report({
+        message: `${source} must not depend on ${target}`,
+        file: source,
+        line: attrs.witness?.line,
+        column: attrs.witness?.column,
+        source,
+        target,
+      });
+    });
+  }, {
+    description: options.description ?? `Files matching ${[forbidden.from].flat().join(', ')} must not depend on ${[forbidden.to].flat().join(', ')}`,
+    severity: options.severity,
+  });
+}
+
+/** An import from one of a cycle's files to another file in it, for pointing at the loop */
+export function cycleWitness(fileGraph: RuleContext['fileGraph'], cycle: string[], from = cycle[0]): SourcePosition | undefined {
+  const members = new Set(cycle);
+  const next = fileGraph.outNeighbors(from).find(node => members.has(node));
+  return next === undefined ? undefined : fileGraph.getEdgeAttribute(from, next, 'witness');
+}
+
+/** Report each file-level dependency cycle once */
+export const noCircularDependencies = createRule('no-circular-dependencies', (ctx) => {
+  for (const cycle of findCycles(ctx.fileGraph)) {
+    const witness = cycleWitness(ctx.fileGraph, cycle);
+    ctx.report({
+      message: cycle.length === 1
+        ? `${cycle[0]} imports itself`
+        : `Circular dependency between ${cycle.length} files: ${cycle.join(' → ')}`,
+      file: cycle[0],
+      line: witness?.line,
+      column: witness?.column,
+    });
+  }
+}, {
+  description: 'Files must not form import cycles',
+  severity: 'warning',
+});
+
+/** Rules run by `depwire lint` when no others are configured */
+export const builtinRules: Rule[] = [noCircularDependencies];
@@ -0,0 +1,86 @@
+import { readFileSync, existsSync } from 'fs';
+import { join, resolve, dirname } from 'path';
+import { parseBudgets, type DependencyBudgets } from './budgets.js';
+import { parseHealthWeights, type DependencyHealthComponent } from '../health/dependency.js';
+import { parseLabelOptions, type LabelOptions } from '../export/labels.js';
+
+/**
+ * The lint config file, depwire.json in the project root:
+ *
+ *   {
+ *     "budgets": { "maxDirectModules": 40, "maxBinaryClosure": 300, "maxDepth": 12 },
+ *     "layers": { "api": "internal/api/**", "store": ["internal/store/**", "internal/db/**"] },
+ *     "ratchet": ["no-circular-dependencies", "go-blank-import"],
+ *     "approvers": ["security@example.com", "arch-review@example.com"],
+ *     "health": { "weights": { "cycles": 0.5, "modules": 0.1 } },
+ *     "labels": { "stripModule": true, "aliases": { "^.*/internal/(.*)$": "int/$1" }, "maxSegments": 3 }
+ *   }
+ *
+ * "approvers" may instead map each approver to their public key file, and
+ * then every waiver must carry that approver's signature.
+ */
+
+export const LINT_CONFIG_FILE = 'depwire.json';
+
+export interface LintConfig {
+  budgets?: DependencyBudgets;
+  /** Layer name → globs of the files in it; the first matching layer wins */
+  layers?: Record<string, string[]>;
+  /** Rule IDs whose finding count may only go down */
+  ratchet?: string[];
+  /** Who may approve waivers */
+  approvers?: string[];
+  /** Approver → public key file (absolute), when waivers must be signed */
+  approverKeys?: Record<string, string>;
+  /** Weights of the dependency health score (depwire badge --metric health) */
+  healthWeights?: Partial<Record<DependencyHealthComponent, number>>;
+  /** Display names in graph, deps and tui */
+  labels?: LabelOptions;
+}
+
+export function loadLintConfig(path: string): LintConfig {
+  let raw: any;
+  try {
+    raw = JSON.parse(readFileSync(path, 'utf-8'));
+  } catch (err) {
+    throw new Error(`Could not read lint config ${path}: ${err instanceof Error ? err.message : err}`);
+  }
+  const config: LintConfig = {};
+  if (raw.budgets !== undefined) config.budgets = parseBudgets(raw.budgets, path);
+  if (raw.layers !== undefined) {
+    if (typeof raw.layers !== 'object' || raw.layers === null || Array.isArray(raw.layers)) {
+      throw new Error(`Lint config ${path}: "layers" must map layer names to globs`);
+    }
+    config.layers = {};
+    for (const [name, globs] of Object.entries(raw.layers)) {
+      const list = [globs].flat();
+      if (list.length === 0 || !list.every(g => typeof g === 'string')) {
+        throw new Error(`Lint config ${path}: layer "${name}" must be a glob or a list of globs`);
+      }
+      config.layers[name] = list as string[];
+    }
+  }
+  if (raw.ratchet !== undefined) {
+    if (!Array.isArray(raw.ratchet) || !raw.ratchet.every((r: unknown) => typeof r === 'string')) {
+      throw new Error(`Lint config ${path}: "ratchet" must be a list of rule IDs`);
+    }
+    config.ratchet = raw.ratchet;
+  }
+  if (Array.isArray(raw.approvers) && raw.approvers.every((a: unknown) => typeof a === 'string')) {
+    config.approvers = raw.approvers;
+  } else if (typeof raw.approvers === 'object' && raw.approvers !== null && Object.values(raw.approvers).every(k => typeof k === 'string')) {
+    config.approvers = Object.keys(raw.approvers);
+    config.approverKeys = Object.fromEntries(Object.entries(raw.approvers as Record<string, string>).map(([name, key]) => [name, resolve(dirname(path), key)]));
+  } else if (raw.approvers !== undefined) {
+    throw new Error(`Lint config ${path}: "approvers" must be a list of names, or map names to public key files`);
+  }
+  if (raw.health?.weights !== undefined) config.healthWeights = parseHealthWeights(raw.health.weights, `Lint config ${path}`);
+  if (raw.labels !== undefined) config.labels = parseLabelOptions(raw.labels, `Lint config ${path}`);
+  return config;
+}
+
+/** The config from an explicit file, or from depwire.json when the project has one */
+export function findLintConfig(projectRoot: string, file?: string): LintConfig {
+  const path = file ?? join(projectRoot, LINT_CONFIG_FILE);
+  return file || existsSync(path) ? loadLintConfig(path) : {};
+}
@@ -0,0 +1,99 @@
+import { describe, it } from 'node:test';
+import assert from 'node:assert';
+import { DirectedGraph } from 'graphology';
+import { createRule, RuleRegistry } from './engine.js';
+import { forbidDependency, noCircularDependencies } from './builtin.js';
+import { applyRatchet } from './ratchet.js';
+
+function createGraph(edges: Array<[string, string, number]>): DirectedGraph {
+  const graph = new DirectedGraph();
+  for (const [source, target, line] of edges) {
+    for (const id of [source, target]) {
+      if (!graph.hasNode(id)) {
+        graph.addNode(id, { name: id.split('::')[1], kind: 'function', filePath: id.split('::')[0], startLine: 1, endLine: 1, exported: true });
+      }
+    }
+    graph.mergeEdge(source, target, { kind: 'calls', line });
+  }
+  return graph;
+}
+
+describe('rule engine', () => {
+  it('runs registered rules and returns structured findings', async () => {
+    const graph = createGraph([
+      ['src/models/user.ts::User', 'src/db/client.ts::query', 12],
+      ['src/api/user.ts::get', 'src/db/client.ts::query', 3],
+    ]);
+
+    const registry = new RuleRegistry().register(
+      forbidDependency('no-db-in-models', { from: 'src/models/**', to: 'src/db/**' })
+    );
+    const result = await registry.run(graph, '/project');
+
+    assert.strictEqual(result.findings.length, 1);
+    assert.deepStrictEqual(
+      { rule: result.findings[0].rule, file: result.findings[0].file, line: result.findings[0].line, target: result.findings[0].target },
+      { rule: 'no-db-in-models', file: 'src/models/user.ts', line: 12, target: 'src/db/client.ts' }
+    );
+    assert.strictEqual(result.summary.error, 1);
+  });
+
+  it('accepts findings returned from the matcher and applies severity overrides', async () => {
+    const graph = createGraph([['a.ts::a', 'b.ts::b', 1], ['b.ts::b', 'a.ts::a', 2]]);
+    const custom = createRule('acme/files', (ctx) => [{ message: `${ctx.fileGraph.order} files` }], { severity: 'info' });
+
+    const result = await new RuleRegistry()
+      .register([custom, noCircularDependencies])
+      .run(graph, '/project', { severities: { 'no-circular-dependencies': 'error' } });
+
+    assert.deepStrictEqual(result.rules, ['acme/files', 'no-circular-dependencies']);
+    assert.strictEqual(result.summary.info, 1);
+    assert.strictEqual(result.summary.error, 1);
+  });
+
+  it('reports a throwing rule as a finding instead of aborting', async () => {
+    const broken = createRule('broken', () => { throw new Error('boom'); });
+    const result = await new RuleRegistry().register(broken).run(createGraph([]), '/project');
+
+    assert.strictEqual(result.findings.length, 1);
+    assert.match(result.findings[0].message, /boom/);
+  });
+
+  it('fingerprints findings in one file apart, ignoring their lines', async () => {
+    const rule = createRule('acme/caps', (ctx) => {
+      ctx.report({ message: 'example.com/lib has the forbidden capability exec', file: 'go.mod', line: 5, target: 'example.com/lib', key: 'exec' });
+      ctx.report({ message: 'example.com/lib has the forbidden capability network', file: 'go.mod', line: 5, target: 'example.com/lib', key: 'network' });
+      ctx.report({ message: 'blank import of "example.com/a" at line 3', file: 'main.go', line: 3 });
+      ctx.report({ message: 'blank import of "example.com/b" at line 4', file: 'main.go', line: 4 });
+    });
+    const moved = createRule('acme/caps', (ctx) => {
+      ctx.report({ message: 'blank import of "example.com/a" at line 9', file: 'main.go', line: 9 });
+    });
+    const [exec, network, a, b] = (await new RuleRegistry().register(rule).run(createGraph([]), '/project')).findings;
+    const [movedA] = (await new RuleRegistry().register(moved).run(createGraph([]), '/project')).findings;
+
+    assert.notStrictEqual(exec.fingerprint, network.fingerprint);
+    assert.notStrictEqual(a.fingerprint, b.fingerprint);
+    assert.strictEqual(movedA.fingerprint, a.fingerprint);
+  });
+
+  it('rejects invalid and duplicate rule ids', () => {
+    assert.throws(() => createRule('Not Kebab', () => {}));
+    const rule = createRule('dup', () => {});
+    assert.throws(() => new RuleRegistry().register(rule, rule));
+  });
+
+  it('ratchets fail when a count grows and tighten when it shrinks', async () => {
+    const graph = createGraph([['a.ts::a', 'b.ts::b', 1], ['b.ts::b', 'a.ts::a', 2], ['c.ts::c', 'd.ts::d', 3], ['d.ts::d', 'c.ts::c', 4]]);
+    const result = await new RuleRegistry().register(noCircularDependencies).run(graph, '/project');
+
+    const grown = applyRatchet(result, { version: 1, counts: { 'no-circular-dependencies': 1 } }, ['no-circular-dependencies']);
+    assert.strictEqual(grown.regressed.length, 1);
+    assert.strictEqual(grown.result.summary.error, 1);
+    assert.strictEqual(grown.result.summary.warning, 0);
+
+    const shrunk = applyRatchet(result, { version: 1, counts: { 'no-circular-dependencies': 5 } }, ['no-circular-dependencies']);
+    assert.strictEqual(shrunk.result.summary.error, 0);
+    assert.deepStrictEqual(shrunk.state.counts, { 'no-circular-dependencies': 2 });
+  });
+});
@@ -0,0 +1,177 @@
+import { createHash } from 'crypto';
+import type { DirectedGraph } from 'graphology';
+import { minimatch } from 'minimatch';
+import { toFileGraph, type DepwireGraph } from '../graph/model.js';
+import type { ParsedFile } from '../parser/types.js';
+import type {
+  Rule,
+  RuleMatcher,
+  RuleDefinitionOptions,
+  RuleFinding,
+  RuleFindingInput,
+  LintResult,
+  LintOptions,
+} from './types.js';
+
+const RULE_ID_PATTERN = /^[a-z0-9][a-z0-9-]*(\/[a-z0-9][a-z0-9-]*)?$/;
+
+/** Whether a file, package directory or import path matches a glob of a rule's options */
+export function matchesAny(value: string, patterns: string | string[] | undefined): boolean {
+  if (!patterns) return false;
+  return (Array.isArray(patterns) ? patterns : [patterns]).some(p => minimatch(value, p));
+}
+
+/**
+ * Define a lint rule.
+ *
+ *   const rule = createRule('no-db-in-models', (ctx) => { ... ctx.report({ message, file }) });
+ *
+ * IDs are kebab-case and may carry a rule-set prefix ('acme/no-db-in-models').
+ */
+export function createRule(id: string, check: RuleMatcher, options: RuleDefinitionOptions = {}): Rule {
+  if (!RULE_ID_PATTERN.test(id)) {
+    throw new Error(`Invalid rule id "${id}" — use kebab-case, optionally prefixed with a rule-set name (e.g. "acme/no-db-in-models")`);
+  }
+  if (typeof check !== 'function') {
+    throw new Error(`Rule "${id}" must have a matcher function`);
+  }
+  return {
+    id,
+    description: options.description ?? id,
+    severity: options.severity ?? 'error',
+    check,
+  };
+}
+
+export function isRule(value: unknown): value is Rule {
+  const rule = value as Rule;
+  return typeof rule === 'object' && rule !== null
+    && typeof rule.id === 'string'
+    && typeof rule.check === 'function';
+}
+
+/**
+ * A finding's fingerprint stays the same while the finding does: it ignores
+ * line numbers, which churn with unrelated edits. Two findings about the same
+ * file or module are told apart by their key, or without one by their message
+ * with the numbers (lines, scores, counts) taken out.
+ */
+export function findingFingerprint(finding: Omit<RuleFinding, 'severity' | 'fingerprint'>): string {
+  const subject = [finding.file, finding.symbol, finding.source, finding.target].map(v => v ?? '');
+  const parts = [finding.rule, ...subject, finding.key ?? finding.message.replace(/\d+(\.\d+)?/g, '#')];
+  return createHash('sha256').update(parts.join('\0')).digest('hex').slice(0, 16);
+}
+
+/**
+ * Holds the rules to run. Register rules (or whole rule sets) before analysis,
+ * then call run() with the built graph.
+ */
+export class RuleRegistry {
+  private rules = new Map<string, Rule>();
+
+  register(...rules: (Rule | Rule[])[]): this {
+    for (const rule of rules.flat()) {
+      if (!isRule(rule)) {
+        throw new Error('register() expects rules created with createRule()');
+      }
+      if (this.rules.has(rule.id)) {
+        throw new Error(`Rule "${rule.id}" is already registered`);
+      }
+      this.rules.set(rule.id, rule);
+    }
+    return this;
+  }
+
+  unregister(id: string): boolean {
+    return this.rules.delete(id);
+  }
+
+  get(id: string): Rule | undefined {
+    return this.rules.get(id);
+  }
+
+  list(): Rule[] {
+    return [...this.rules.values()];
+  }
+
+  run(
+    graph: DirectedGraph,
+    projectRoot: string,
+    options: LintOptions & { parsedFiles?: ParsedFile[] } = {}
+  ): Promise<LintResult> {
+    return runRules(this.list(), graph, projectRoot, options);
+  }
+}
+
+/**
+ * Run rules against a graph and collect their findings.
+ * A rule that throws is reported as an error finding rather than aborting the run.
+ */
+export async function runRules(
+  rules: Rule[],
+  graph: DirectedGraph,
+  projectRoot: string,
+  options: LintOptions & { parsedFiles?: ParsedFile[] } = {}
+): Promise<LintResult> {
+  const fileGraph = toFileGraph(graph);
+  const findings: RuleFinding[] = [];
+  const ran: string[] = [];
+
+  for (const rule of rules) {
+    const override = options.severities?.[rule.id];
+    if (override === 'off') continue;
+    const severity = override ?? rule.severity;
+    ran.push(rule.id);
+
+    const toFinding = (input: RuleFindingInput): RuleFinding => ({
+      ...input,
+      rule: rule.id,
+      severity: input.severity ?? severity,
+      fingerprint: findingFingerprint({ ...input, rule: rule.id }),
+    });
+
+    try {
+      const returned = await rule.check({
+        projectRoot,
+        graph: graph as DepwireGraph,
+        fileGraph,
+        parsedFiles: options.parsedFiles ?? [],
+        options: options.ruleOptions?.[rule.id] ?? {},
+        report: (input) => findings.push(toFinding(input)),
+      });
+      if (Array.isArray(returned)) {
+        findings.push(...returned.map(toFinding));
+      }
+    } catch (err) {
+      findings.push({
+        rule: rule.id,
+        severity: 'error',
+        message: `Rule failed: ${err instanceof Error ? err.message : String(err)}`,
+      });
+    }
+  }
+
+  findings.sort((a, b) =>
+    (a.file ?? '').localeCompare(b.file ?? '') ||
+    (a.line ?? 0) - (b.line ?? 0) ||
+    (a.column ?? 0) - (b.column ?? 0) ||
+    a.rule.localeCompare(b.rule)
+  );
+
+  return {
+    projectRoot,
+    checkedAt: new Date().toISOString(),
+    rules: ran,
+    findings,
+    summary: summarize(findings),
+  };
+}
+
+export function summarize(findings: RuleFinding[]): LintResult['summary'] {
+  return {
+    error: findings.filter(f => f.severity === 'error').length,
+    warning: findings.filter(f => f.severity === 'warning').length,
+    info: findings.filter(f => f.severity === 'info').length,
+    total: findings.length,
+  };
+}
@@ -0,0 +1,36 @@
+import { createRule } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { findGoModuleRoot } from '../golang/toolchain.js';
+import { runGoAnalyzers, type GoAnalysisOptions } from '../golang/vet.js';
+
+/**
+ * A rule that runs go/analysis analyzers (via `go vet -json`) and merges their
+ * diagnostics into the lint result, prefixed with the analyzer name.
+ * Projects without a go.mod are skipped.
+ */
+export function goAnalysisRule(options: GoAnalysisOptions = {}, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('go-vet', async (ctx) => {
+    const moduleRoot = findGoModuleRoot(ctx.projectRoot);
+    if (!moduleRoot) return;
+
+    const result = await runGoAnalyzers(moduleRoot, ctx.projectRoot, options);
+    for (const diag of result.diagnostics) {
+      ctx.report({
+        message: `${diag.analyzer}: ${diag.message}`,
+        file: diag.file,
+        line: diag.line,
+      });
+    }
+    for (const error of result.errors) {
+      ctx.report({
+        severity: 'error',
+        message: `${error.analyzer ? `${error.analyzer}: ` : ''}${error.message}`,
+      });
+    }
+  }, {
+    description: definition.description ?? (options.vettool
+      ? `go/analysis analyzers from ${options.vettool}`
+      : 'go vet analyzers'),
+    severity: definition.severity ?? 'warning',
+  });
+}
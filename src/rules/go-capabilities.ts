@@ -0,0 +1,58 @@
+import { join, relative } from 'path';
+import { minimatch } from 'minimatch';
+import { createRule } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { analyzeCapabilities, type Capability } from '../capabilities/index.js';
+import { readGoMod } from '../golang/modfile.js';
+import { findGoModuleRoot } from '../golang/toolchain.js';
+
+export interface GoCapabilityOptions {
+  /** Capabilities third-party modules must not have */
+  forbid: Capability[];
+  /** Module path globs → capabilities they are allowed despite forbid */
+  allow?: Record<string, Capability[]>;
+  /** Also count capabilities inherited from a module's own dependencies (default true) */
+  transitive?: boolean;
+}
+
+/**
+ * A rule that fails when a third-party Go module can do something the
+ * project has forbidden, unless it's allowlisted for that capability.
+ *
+ *   goCapabilityRule({ forbid: ['exec', 'network'], allow: { 'github.com/aws/**': ['network'] } })
+ */
+export function goCapabilityRule(options: GoCapabilityOptions, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('go-capabilities', async (ctx) => {
+    const report = await analyzeCapabilities(ctx.projectRoot);
+    const moduleRoot = findGoModuleRoot(ctx.projectRoot);
+    if (!moduleRoot) return;
+    const goMod = relative(ctx.projectRoot, join(moduleRoot, 'go.mod')).split('\\').join('/');
+    const requires = new Map((readGoMod(moduleRoot)?.require ?? []).map(r => [r.path, r.line]));
+
+    for (const entry of report.modules) {
+      const allowed = new Set(Object.entries(options.allow ?? {})
+        .filter(([pattern]) => minimatch(entry.module, pattern))
+        .flatMap(([, caps]) => caps));
+      const caps = [
+        ...entry.direct.map(capability => ({ capability, via: null as string | null })),
+        ...(options.transitive === false ? [] : entry.transitive),
+      ];
+      for (const { capability, via } of caps) {
+        if (!options.forbid.includes(capability) || allowed.has(capability)) continue;
+        const evidence = entry.evidence.find(e => e.capability === capability);
+        ctx.report({
+          message: via
+            ? `${entry.module} has the forbidden capability ${capability} through ${via}`
+            : `${entry.module} has the forbidden capability ${capability}${evidence ? ` (${evidence.api} at ${evidence.file}:${evidence.line})` : ''}`,
+          file: requires.has(entry.module) ? goMod : undefined,
+          line: requires.get(entry.module),
+          target: entry.module,
+          key: capability,
+        });
+      }
+    }
+  }, {
+    description: definition.description ?? `Third-party Go modules must not use ${options.forbid.join(', ')}`,
+    severity: definition.severity ?? 'error',
+  });
+}
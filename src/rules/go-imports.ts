@@ -0,0 +1,98 @@
+import { createRule, matchesAny } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { loadGoProject } from '../golang/packages.js';
+import { goImports, type GoImportKind } from '../golang/source.js';
+
+export interface GoImportPolicyOptions {
+  /** Import path globs that may be imported this way (replaces the default allowlist) */
+  allow?: string[];
+  /** Extra import path globs allowed on top of the default allowlist */
+  alsoAllow?: string[];
+  /** Glob(s) for importing package directories that are exempt */
+  except?: string | string[];
+  /** Check _test.go files too (default true) */
+  tests?: boolean;
+  /** Allow the import anywhere in package main (default: true for blank imports, false for dot imports) */
+  allowInMain?: boolean;
+}
+
+/** Packages that are normally imported only for their init side effects */
+export const DEFAULT_BLANK_IMPORT_ALLOWLIST = [
+  'github.com/go-sql-driver/mysql',
+  'github.com/lib/pq',
+  'github.com/jackc/pgx/*/stdlib',
+  'github.com/jackc/pgx/stdlib',
+  'github.com/mattn/go-sqlite3',
+  'modernc.org/sqlite',
+  'github.com/microsoft/go-mssqldb',
+  'github.com/denisenkom/go-mssqldb',
+  'github.com/sijms/go-ora/*',
+  'image/png',
+  'image/jpeg',
+  'image/gif',
+  'golang.org/x/image/**',
+  'embed',
+  'time/tzdata',
+  'go.uber.org/automaxprocs',
+];
+
+/** Dot imports that are idiomatic — test DSLs */
+export const DEFAULT_DOT_IMPORT_ALLOWLIST = [
+  'github.com/onsi/ginkgo',
+  'github.com/onsi/ginkgo/v2',
+  'github.com/onsi/gomega',
+];
+
+/**
+ * A rule that reports every `import _ "path"` outside the allowlist.
+ *
+ *   goBlankImportRule({ alsoAllow: ['example.com/plugins/**'] })
+ */
+export function goBlankImportRule(options: GoImportPolicyOptions = {}, definition: RuleDefinitionOptions = {}): Rule {
+  return importPolicyRule('go-blank-import', 'blank', DEFAULT_BLANK_IMPORT_ALLOWLIST, true, options, definition);
+}
+
+/**
+ * A rule that reports every `import . "path"` outside the allowlist.
+ *
+ *   goDotImportRule({ allow: [] })   // no dot imports at all
+ */
+export function goDotImportRule(options: GoImportPolicyOptions = {}, definition: RuleDefinitionOptions = {}): Rule {
+  return importPolicyRule('go-dot-import', 'dot', DEFAULT_DOT_IMPORT_ALLOWLIST, false, options, definition);
+}
+
+function importPolicyRule(
+  id: string,
+  kind: GoImportKind,
+  defaults: string[],
+  defaultAllowInMain: boolean,
+  options: GoImportPolicyOptions,
+  definition: RuleDefinitionOptions
+): Rule {
+  const allow = [...(options.allow ?? defaults), ...(options.alsoAllow ?? [])];
+  const allowInMain = options.allowInMain ?? defaultAllowInMain;
+  const label = kind === 'blank' ? 'Blank (_)' : 'Dot (.)';
+
+  return createRule(id, async (ctx) => {
+    const project = await loadGoProject(ctx.projectRoot, { tests: options.tests ?? true });
+    for (const pkg of project.packages.values()) {
+      if (matchesAny(pkg.dir, options.except)) continue;
+      for (const file of pkg.files) {
+        if (allowInMain && !file.isTest && file.packageName === 'main') continue;
+        for (const imp of goImports(file)) {
+          if (imp.kind !== kind || matchesAny(imp.path, allow)) continue;
+          ctx.report({
+            message: `${label} import of "${imp.path}" is not in the allowlist`,
+            file: file.file,
+            line: imp.line,
+            column: imp.column,
+            target: imp.path,
+          });
+        }
+      }
+    }
+  }, {
+    description: definition.description ?? `${label} imports only of allowlisted packages${allowInMain ? ' (package main exempt)' : ''}`,
+    severity: definition.severity ?? 'warning',
+  });
+}
@@ -0,0 +1,47 @@
+import { createRule, matchesAny } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { analyzeInit, type InitEffect } from '../golang/init.js';
+
+export interface GoInitOptions {
+  /** Glob(s) for the package directories the rule applies to (default: every package) */
+  packages?: string | string[];
+  /** Glob(s) for package directories that are exempt, e.g. cmd/** */
+  except?: string | string[];
+  /** Effects that are not allowed during init (default: network, file, env, exec, goroutine) */
+  forbid?: InitEffect[];
+}
+
+const HEAVY_EFFECTS: InitEffect[] = ['network', 'file', 'env', 'exec', 'goroutine'];
+
+/**
+ * A rule that forbids heavy work in init() and package-level var
+ * initializers — typically applied to library layers, with entry points
+ * exempt.
+ *
+ *   goInitRule({ packages: 'pkg/**', forbid: ['network', 'file', 'env'] })
+ */
+export function goInitRule(options: GoInitOptions = {}, definition: RuleDefinitionOptions = {}): Rule {
+  const forbid = new Set(options.forbid ?? HEAVY_EFFECTS);
+  return createRule('go-init', async (ctx) => {
+    const report = await analyzeInit(ctx.projectRoot);
+    for (const pkg of report.packages) {
+      if (options.packages && !matchesAny(pkg.dir, options.packages)) continue;
+      if (matchesAny(pkg.dir, options.except)) continue;
+      for (const site of pkg.sites) {
+        for (const evidence of site.evidence) {
+          if (!forbid.has(evidence.effect)) continue;
+          const where = site.kind === 'init' ? 'init()' : `initializer of ${site.name}`;
+          ctx.report({
+            message: `${evidence.effect} access during package initialization: ${evidence.call} in ${where}`,
+            file: evidence.file,
+            line: evidence.line,
+            key: `${evidence.effect} ${evidence.call} ${where}`,
+          });
+        }
+      }
+    }
+  }, {
+    description: definition.description ?? `No ${[...forbid].join('/')} work during package initialization`,
+    severity: definition.severity ?? 'warning',
+  });
+}
@@ -0,0 +1,43 @@
+import { createRule, matchesAny } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { analyzePanics } from '../golang/panics.js';
+
+export interface GoPanicOptions {
+  /** Glob(s) for the package directories whose exported API must not panic (default: every package) */
+  packages?: string | string[];
+  /** Glob(s) for package directories that are exempt */
+  except?: string | string[];
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+}
+
+/**
+ * A rule that fails when a panic can escape an exported function of a
+ * library layer, naming the chain down to the panic. Chains matched by
+ * method name only are reported as warnings.
+ *
+ *   goPanicRule({ packages: 'pkg/**' })
+ */
+export function goPanicRule(options: GoPanicOptions = {}, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('go-no-panics', async (ctx) => {
+    const report = await analyzePanics(ctx.projectRoot, { algo: options.algo });
+    for (const pkg of report.packages) {
+      if (options.packages && !matchesAny(pkg.dir, options.packages)) continue;
+      if (matchesAny(pkg.dir, options.except)) continue;
+      for (const exposure of pkg.exposed) {
+        const chain = exposure.chain.map(s => s.function).join(' → ');
+        const last = exposure.chain[exposure.chain.length - 1];
+        ctx.report({
+          message: `${exposure.call}() can escape ${exposure.function}: ${chain} (${last.file}:${last.line})`,
+          file: exposure.file,
+          line: exposure.line,
+          symbol: exposure.function,
+          severity: exposure.approximate ? 'warning' : undefined,
+        });
+      }
+    }
+  }, {
+    description: definition.description ?? 'Exported functions must return errors instead of letting panics escape',
+    severity: definition.severity ?? 'error',
+  });
+}
@@ -0,0 +1,79 @@
+import { execFileSync } from 'child_process';
+import { existsSync, statSync } from 'fs';
+import { isAbsolute, join, relative, resolve } from 'path';
+import { minimatch } from 'minimatch';
+import { createRule } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { findGoModules } from '../golang/modules.js';
+import { readGoMod } from '../golang/modfile.js';
+
+export interface GoLocalReplaceOptions {
+  /** Report every local replace, for CI that must build from published modules only */
+  forbid?: boolean;
+  /** Module path globs whose local replaces are fine even with forbid (e.g. a monorepo's own modules) */
+  allow?: string[];
+}
+
+// A developer's home directory on Linux, macOS or Windows, or a ~ path the go command won't expand
+const HOME_DIR = /^(~|\/home\/[^/]+|\/Users\/[^/]+|\/root|[A-Za-z]:[\\/](Users|Documents and Settings)[\\/][^\\/]+)([\\/]|$)/;
+
+function repositoryRoot(projectRoot: string): string {
+  try {
+    return execFileSync('git', ['rev-parse', '--show-toplevel'], { cwd: projectRoot, encoding: 'utf-8', stdio: ['ignore', 'pipe', 'ignore'] }).trim();
+  } catch {
+    return projectRoot;
+  }
+}
+
+/**
+ * A rule that checks `replace` directives with local targets: the directory
+ * must exist, hold a go.mod declaring the replaced module path, and live in
+ * the repository — not at an absolute path in someone's home directory,
+ * which builds on one laptop and nowhere else.
+ *
+ *   goLocalReplaceRule()                                   // sanity checks
+ *   goLocalReplaceRule({ forbid: true, allow: ['example.com/mono/**'] })
+ */
+export function goLocalReplaceRule(options: GoLocalReplaceOptions = {}, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('go-local-replace', (ctx) => {
+    const repoRoot = repositoryRoot(ctx.projectRoot);
+    for (const mod of findGoModules(ctx.projectRoot)) {
+      const moduleDir = join(ctx.projectRoot, mod.dir);
+      for (const rep of mod.mod.replace) {
+        if (!rep.local) continue;
+        const at = { file: mod.goModFile, line: rep.line, target: rep.newPath };
+        const directive = `replace ${rep.oldPath}${rep.oldVersion ? ` ${rep.oldVersion}` : ''} => ${rep.newPath}`;
+        const allowed = options.allow?.some(glob => minimatch(rep.oldPath, glob)) ?? false;
+
+        if (HOME_DIR.test(rep.newPath)) {
+          ctx.report({ ...at, severity: 'error', message: `${directive} points into a developer's home directory — it builds on one machine only` });
+        } else if (isAbsolute(rep.newPath) || /^[A-Za-z]:[\\/]/.test(rep.newPath)) {
+          ctx.report({ ...at, severity: 'warning', message: `${directive} uses an absolute path, which other checkouts and CI won't have` });
+        }
+
+        const target = resolve(moduleDir, rep.newPath);
+        const outside = relative(repoRoot, target);
+        if (!isAbsolute(rep.newPath) && (outside.startsWith('..') || isAbsolute(outside))) {
+          ctx.report({ ...at, severity: 'warning', message: `${directive} leaves the repository, so a fresh clone can't build` });
+        }
+        if (!existsSync(target) || !statSync(target).isDirectory()) {
+          ctx.report({ ...at, severity: 'error', message: `${directive}: ${rep.newPath} does not exist` });
+        } else {
+          const replacement = readGoMod(target);
+          if (!replacement) {
+            ctx.report({ ...at, severity: 'error', message: `${directive}: ${rep.newPath} is not a module (no go.mod)` });
+          } else if (replacement.module !== rep.oldPath) {
+            ctx.report({ ...at, severity: 'error', message: `${directive}: ${rep.newPath}/go.mod declares module ${replacement.module}, so the go command rejects the replacement` });
+          }
+        }
+
+        if (options.forbid && !allowed) {
+          ctx.report({ ...at, message: `${directive}: local replace directives are not allowed` });
+        }
+      }
+    }
+  }, {
+    description: definition.description ?? (options.forbid ? 'No local replace directives in go.mod' : 'Local replace directives point at modules inside the repository'),
+    severity: definition.severity ?? 'error',
+  });
+}
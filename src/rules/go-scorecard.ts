@@ -0,0 +1,59 @@
+import { createRule } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { analyzeScorecards } from '../supply-chain/scorecard.js';
+import { newModules } from '../supply-chain/typosquat.js';
+
+export interface GoScorecardOptions {
+  /** Minimum aggregate Scorecard score (0–10) */
+  minScore?: number;
+  /** Minimum scores for individual checks, e.g. { Maintained: 5, 'Dangerous-Workflow': 10 } */
+  checks?: Record<string, number>;
+  /** Only check modules added since this git ref (default: direct dependencies) */
+  base?: string;
+  /** Fail modules that have no Scorecard result (default false) */
+  requireScorecard?: boolean;
+  /** Use cached results only */
+  offline?: boolean;
+}
+
+/**
+ * A rule that holds new Go dependencies to minimum OpenSSF Scorecard scores.
+ *
+ *   goScorecardRule({ minScore: 5, checks: { Maintained: 3 }, base: 'origin/main' })
+ */
+export function goScorecardRule(options: GoScorecardOptions, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('go-scorecard', async (ctx) => {
+    const added = options.base ? newModules(ctx.projectRoot, options.base) : null;
+    const report = await analyzeScorecards(ctx.projectRoot, {
+      offline: options.offline,
+      modules: added?.map(m => m.path),
+    });
+    const locations = new Map((added ?? newModules(ctx.projectRoot)).map(m => [m.path, m]));
+
+    for (const entry of report.modules) {
+      if (!added && !entry.direct) continue;
+      const location = locations.get(entry.module);
+      const at = { file: location?.goModFile, line: location?.line, target: entry.module };
+      const card = entry.scorecard;
+      if (!card) {
+        if (options.requireScorecard) {
+          ctx.report({ message: `${entry.module} has no OpenSSF Scorecard result${entry.repo ? ` (${entry.repo})` : ''}`, ...at, key: 'missing' });
+        }
+        continue;
+      }
+      if (options.minScore !== undefined && card.score < options.minScore) {
+        ctx.report({ message: `${entry.module} has Scorecard score ${card.score.toFixed(1)}, below the minimum ${options.minScore}`, ...at, key: 'score' });
+      }
+      for (const [check, min] of Object.entries(options.checks ?? {})) {
+        const score = card.checks[check];
+        // -1 is inconclusive, not a failure
+        if (score !== undefined && score >= 0 && score < min) {
+          ctx.report({ message: `${entry.module} scores ${score} on ${check}, below the minimum ${min}`, ...at, key: check });
+        }
+      }
+    }
+  }, {
+    description: definition.description ?? 'New Go dependencies must meet minimum OpenSSF Scorecard scores',
+    severity: definition.severity ?? 'error',
+  });
+}
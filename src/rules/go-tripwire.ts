@@ -0,0 +1,41 @@
+import { createRule } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { analyzeTripwire } from '../supply-chain/tripwire.js';
+import { newModules } from '../supply-chain/typosquat.js';
+
+export interface GoTripwireOptions {
+  /** Only check modules added since this git ref (default: every dependency) */
+  base?: string;
+  /** Also fail on decoding alone, without exec or network nearby (default false) */
+  decode?: boolean;
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+}
+
+/**
+ * A rule that fails when initializing a (new) dependency package runs a
+ * process, opens a connection or decodes an embedded payload. Findings point
+ * at the module's require line and name the call chain.
+ *
+ *   goTripwireRule({ base: 'origin/main' })
+ */
+export function goTripwireRule(options: GoTripwireOptions = {}, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('go-init-tripwire', async (ctx) => {
+    const report = await analyzeTripwire(ctx.projectRoot, { base: options.base, algo: options.algo });
+    const requires = new Map(newModules(ctx.projectRoot).map(m => [m.path, m]));
+
+    for (const f of report.findings) {
+      if (f.severity !== 'high' && !options.decode) continue;
+      const chain = [...f.chain.map(s => s.function), f.call].join(' → ');
+      ctx.report({
+        message: `Importing ${f.package} runs ${f.kind === 'decode' ? 'payload decoding' : f.kind} at init: ${chain} (${f.file}:${f.line})`,
+        file: requires.get(f.module)?.goModFile,
+        line: requires.get(f.module)?.line,
+        target: f.module,
+      });
+    }
+  }, {
+    description: definition.description ?? 'Dependencies must not run processes, open connections or decode payloads during package initialization',
+    severity: definition.severity ?? 'error',
+  });
+}
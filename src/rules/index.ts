@@ -0,0 +1,77 @@
+import { resolve, join } from 'path';
+import { existsSync } from 'fs';
+import { createRequire } from 'module';
+import { pathToFileURL } from 'url';
+import { isRule } from './engine.js';
+import type { Rule } from './types.js';
+
+export { createRule, isRule, RuleRegistry, runRules, findingFingerprint, summarize } from './engine.js';
+export { forbidDependency, noCircularDependencies, builtinRules, cycleWitness } from './builtin.js';
+export type { ForbiddenDependency } from './builtin.js';
+export { goAnalysisRule } from './go-analysis.js';
+export { goInitRule } from './go-init.js';
+export type { GoInitOptions } from './go-init.js';
+export { goBlankImportRule, goDotImportRule, DEFAULT_BLANK_IMPORT_ALLOWLIST, DEFAULT_DOT_IMPORT_ALLOWLIST } from './go-imports.js';
+export type { GoImportPolicyOptions } from './go-imports.js';
+export { goLocalReplaceRule } from './go-replace.js';
+export type { GoLocalReplaceOptions } from './go-replace.js';
+export { goCapabilityRule } from './go-capabilities.js';
+export type { GoCapabilityOptions } from './go-capabilities.js';
+export { goScorecardRule } from './go-scorecard.js';
+export type { GoScorecardOptions } from './go-scorecard.js';
+export { goTripwireRule } from './go-tripwire.js';
+export type { GoTripwireOptions } from './go-tripwire.js';
+export { goPanicRule } from './go-panics.js';
+export type { GoPanicOptions } from './go-panics.js';
+export { noNewCircularDependencies } from './new-cycles.js';
+export type { NewCyclesOptions } from './new-cycles.js';
+export { dependencyBudgetRule, parseBudgets, longestChain } from './budgets.js';
+export type { DependencyBudgets, ImportChain } from './budgets.js';
+export { loadLintConfig, findLintConfig, LINT_CONFIG_FILE } from './config.js';
+export type { LintConfig } from './config.js';
+export { applyWaivers, readWaivers, signWaiver, WAIVERS_FILE } from './waivers.js';
+export type { Waiver, WaiverOptions, WaiverOutcome } from './waivers.js';
+export { applyRatchet, readRatchet, writeRatchet, RATCHET_FILE } from './ratchet.js';
+export type { RatchetState, RatchetChange, RatchetOutcome } from './ratchet.js';
+export { regoPolicyRule, policyInput } from './rego.js';
+export type { RegoPolicyOptions, PolicyInput } from './rego.js';
+export { frozenDependenciesRule, snapshotLock, readLock, writeLock, layerOf, layerEdges, unapprovedEdges, LOCKFILE } from './lockfile.js';
+export type { DependencyLock, LayerEdge } from './lockfile.js';
+export { writeAdrStubs, adrStub } from './adr.js';
+export type { AdrStub, AdrOptions } from './adr.js';
+export { buildVerdict, readVerdict, writeVerdict, assertVerdict, VERDICT_VERSION } from './verdict.js';
+export type { Verdict, VerdictRule, VerdictRatchet, VerdictWaiver, VerdictStatus, VerdictOptions, VerdictAssertion } from './verdict.js';
+export * from './types.js';
+
+/**
+ * Load rules from a rule-set module — a local file or an installed package.
+ * The module's default export (or its `rules` export) must be a rule or an array of rules.
+ */
+export async function loadRuleModule(specifier: string, projectRoot: string): Promise<Rule[]> {
+  const url = pathToFileURL(resolveRuleModule(specifier, projectRoot)).href;
+
+  let mod: any;
+  try {
+    mod = await import(url);
+  } catch (err) {
+    throw new Error(`Could not load rule module "${specifier}": ${err instanceof Error ? err.message : err}`);
+  }
+
+  const exported = mod.default ?? mod.rules;
+  const rules = (Array.isArray(exported) ? exported : [exported]).flat();
+  if (rules.length === 0 || !rules.every(isRule)) {
+    throw new Error(`Rule module "${specifier}" must export a rule or an array of rules (created with createRule)`);
+  }
+  return rules;
+}
+
+// Resolve packages from the analyzed project's node_modules, not depwire's own
+function resolveRuleModule(specifier: string, projectRoot: string): string {
+  const localPath = resolve(projectRoot, specifier);
+  if (existsSync(localPath)) return localPath;
+  try {
+    return createRequire(join(projectRoot, 'package.json')).resolve(specifier);
+  } catch {
+    throw new Error(`Rule module "${specifier}" not found (looked for a file and for a package installed in ${projectRoot})`);
+  }
+}
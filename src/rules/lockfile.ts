@@ -0,0 +1,138 @@
+import { readFileSync, writeFileSync } from 'fs';
+import { minimatch } from 'minimatch';
+import type { DirectedGraph } from 'graphology';
+import { createRule } from './engine.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { findGoModules } from '../golang/modules.js';
+
+/**
+ * The dependency lockfile written by `depwire freeze`: the external modules
+ * and the cross-layer edges a reviewer has approved. Layers come from the
+ * lint config; without one, each top-level directory is a layer.
+ */
+
+export const LOCKFILE = 'depwire.lock.json';
+
+export interface DependencyLock {
+  version: 1;
+  /** External module paths, without versions — upgrades don't need a re-freeze */
+  modules: string[];
+  /** "from -> to" layer pairs */
+  edges: string[];
+}
+
+export interface LayerEdge {
+  from: string;
+  to: string;
+  /** The file-level edges behind it, sorted */
+  files: Array<[string, string]>;
+}
+
+/** Layer of a project-relative file, or null when configured layers don't cover it */
+export function layerOf(file: string, layers?: Record<string, string[]>): string | null {
+  if (!layers) return file.includes('/') ? file.slice(0, file.indexOf('/')) : '.';
+  for (const [name, globs] of Object.entries(layers)) {
+    if (globs.some(g => minimatch(file, g))) return name;
+  }
+  return null;
+}
+
+export function layerEdges(fileGraph: DirectedGraph, layers?: Record<string, string[]>): Map<string, LayerEdge> {
+  const edges = new Map<string, LayerEdge>();
+  fileGraph.forEachEdge((_edge, _attrs, source, target) => {
+    const from = layerOf(source, layers);
+    const to = layerOf(target, layers);
+    if (from === null || to === null || from === to) return;
+    const key = `${from} -> ${to}`;
+    if (!edges.has(key)) edges.set(key, { from, to, files: [] });
+    edges.get(key)!.files.push([source, target]);
+  });
+  for (const edge of edges.values()) edge.files.sort((a, b) => a[0].localeCompare(b[0]) || a[1].localeCompare(b[1]));
+  return edges;
+}
+
+/** Required modules across the project's go.mod files, workspace modules excluded */
+export function externalModules(projectRoot: string): Map<string, { goModFile: string; line?: number }> {
+  const modules = findGoModules(projectRoot);
+  const local = new Set(modules.map(m => m.path));
+  const external = new Map<string, { goModFile: string; line?: number }>();
+  for (const mod of modules) {
+    for (const req of mod.mod.require) {
+      if (!local.has(req.path) && !external.has(req.path)) external.set(req.path, { goModFile: mod.goModFile, line: req.line });
+    }
+  }
+  return external;
+}
+
+export function snapshotLock(projectRoot: string, fileGraph: DirectedGraph, layers?: Record<string, string[]>): DependencyLock {
+  return {
+    version: 1,
+    modules: [...externalModules(projectRoot).keys()].sort(),
+    edges: [...layerEdges(fileGraph, layers).keys()].sort(),
+  };
+}
+
+export function readLock(path: string): DependencyLock {
+  let raw: any;
+  try {
+    raw = JSON.parse(readFileSync(path, 'utf-8'));
+  } catch (err) {
+    throw new Error(`Could not read lockfile ${path}: ${err instanceof Error ? err.message : err}`);
+  }
+  if (raw.version !== 1 || !Array.isArray(raw.modules) || !Array.isArray(raw.edges)) {
+    throw new Error(`Lockfile ${path} is not a version 1 depwire lockfile — re-run depwire freeze`);
+  }
+  return raw as DependencyLock;
+}
+
+export function writeLock(path: string, lock: DependencyLock): void {
+  writeFileSync(path, JSON.stringify(lock, null, 2) + '\n');
+}
+
+/** Cross-layer edges of the file graph that the lockfile doesn't approve */
+export function unapprovedEdges(lock: DependencyLock, fileGraph: DirectedGraph, layers?: Record<string, string[]>): LayerEdge[] {
+  const edges = new Set(lock.edges);
+  return [...layerEdges(fileGraph, layers)].filter(([key]) => !edges.has(key)).map(([, edge]) => edge);
+}
+
+/**
+ * A rule that fails on any external module or cross-layer edge missing from
+ * the lockfile, so new dependencies need a reviewed `depwire freeze`.
+ *
+ *   frozenDependenciesRule(readLock('depwire.lock.json'), { layers: config.layers })
+ */
+export function frozenDependenciesRule(
+  lock: DependencyLock,
+  options: { layers?: Record<string, string[]> } = {},
+  definition: RuleDefinitionOptions = {}
+): Rule {
+  return createRule('frozen-dependencies', (ctx) => {
+    const modules = new Set(lock.modules);
+    for (const [path, req] of externalModules(ctx.projectRoot)) {
+      if (modules.has(path)) continue;
+      ctx.report({
+        message: `Module ${path} is not in ${LOCKFILE} — approve it by re-running depwire freeze`,
+        file: req.goModFile,
+        line: req.line,
+        target: path,
+      });
+    }
+
+    for (const edge of unapprovedEdges(lock, ctx.fileGraph, options.layers)) {
+      const [source, target] = edge.files[0];
+      const more = edge.files.length > 1 ? ` (and ${edge.files.length - 1} more file edges)` : '';
+      const witness = ctx.fileGraph.getEdgeAttribute(source, target, 'witness');
+      ctx.report({
+        message: `${edge.from} → ${edge.to} is not an approved edge in ${LOCKFILE}: ${source} imports ${target}${more} — approve it by re-running depwire freeze`,
+        file: source,
+        line: witness?.line,
+        column: witness?.column,
+        source,
+        target,
+      });
+    }
+  }, {
+    description: definition.description ?? `External modules and cross-layer edges must be approved in ${LOCKFILE}`,
+    severity: definition.severity ?? 'error',
+  });
+}
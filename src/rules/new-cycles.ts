@@ -0,0 +1,58 @@
+import { createRule } from './engine.js';
+import { cycleWitness } from './builtin.js';
+import type { Rule, RuleDefinitionOptions } from './types.js';
+import { findCycles, cycleChanges } from '../graph/algorithms.js';
+import { buildGraph } from '../graph/index.js';
+import { toFileGraph } from '../graph/model.js';
+import { parseProject } from '../parser/index.js';
+import { isGitRepo, withWorktree } from '../temporal/git.js';
+
+export interface NewCyclesOptions {
+  /** Git ref whose cycles are tolerated, e.g. origin/main */
+  base: string;
+}
+
+/**
+ * The delta version of no-circular-dependencies: cycles that already exist
+ * at the base ref are tolerated, and only a new cycle — or an existing one
+ * that gains files or merges with another — is reported.
+ *
+ *   noNewCircularDependencies({ base: 'origin/main' })
+ */
+export function noNewCircularDependencies(options: NewCyclesOptions, definition: RuleDefinitionOptions = {}): Rule {
+  return createRule('no-circular-dependencies', async (ctx) => {
+    if (!isGitRepo(ctx.projectRoot)) throw new Error(`Not a git repository — cannot compare cycles against ${options.base}`);
+    const before = await withWorktree(ctx.projectRoot, options.base, async (baseDir) =>
+      findCycles(toFileGraph(buildGraph(await parseProject(baseDir), baseDir)))
+    );
+
+    for (const change of cycleChanges(before, findCycles(ctx.fileGraph))) {
+      const { cycle, added, previous } = change;
+      const file = change.kind === 'new' ? cycle[0] : added[0] ?? cycle[0];
+      const witness = cycleWitness(ctx.fileGraph, cycle, file);
+      if (change.kind === 'new') {
+        ctx.report({
+          message: cycle.length === 1
+            ? `${cycle[0]} now imports itself`
+            : `New circular dependency between ${cycle.length} files: ${cycle.join(' → ')}`,
+          file,
+          line: witness?.line,
+          column: witness?.column,
+        });
+        continue;
+      }
+      const was = previous.reduce((n, p) => n + p.length, 0);
+      const merged = previous.length > 1 ? `, merging ${previous.length} cycles` : '';
+      ctx.report({
+        message: `Circular dependency grew from ${was} to ${cycle.length} files since ${options.base}${merged}` +
+          `${added.length ? ` (now includes ${added.join(', ')})` : ''}: ${cycle.join(' → ')}`,
+        file,
+        line: witness?.line,
+        column: witness?.column,
+      });
+    }
+  }, {
+    description: definition.description ?? `Changes since ${options.base} must not add import cycles or grow existing ones`,
+    severity: definition.severity ?? 'error',
+  });
+}
@@ -0,0 +1,98 @@
+import { readFileSync, writeFileSync, existsSync } from 'fs';
+import { summarize } from './engine.js';
+import type { LintResult, RuleFinding } from './types.js';
+
+/**
+ * Ratchets: for the rules they cover, the number of findings may go down
+ * but never up. The allowed counts live in a committed state file; each
+ * lint that finds fewer lowers them, so debt can only shrink.
+ */
+
+export const RATCHET_FILE = 'depwire.ratchet.json';
+
+export interface RatchetState {
+  version: 1;
+  /** Allowed finding count, by rule ID */
+  counts: Record<string, number>;
+}
+
+export interface RatchetChange {
+  rule: string;
+  /** The allowed count before this run (undefined when the rule is new to the ratchet) */
+  allowed?: number;
+  count: number;
+}
+
+export interface RatchetOutcome {
+  result: LintResult;
+  state: RatchetState;
+  /** Rules whose count went up — the run fails */
+  regressed: RatchetChange[];
+  /** Rules whose count went down, or that were recorded for the first time */
+  tightened: RatchetChange[];
+}
+
+export function readRatchet(path: string): RatchetState {
+  if (!existsSync(path)) return { version: 1, counts: {} };
+  let raw: any;
+  try {
+    raw = JSON.parse(readFileSync(path, 'utf-8'));
+  } catch (err) {
+    throw new Error(`Could not read ratchet state ${path}: ${err instanceof Error ? err.message : err}`);
+  }
+  if (raw.version !== 1 || typeof raw.counts !== 'object' || raw.counts === null) {
+    throw new Error(`Ratchet state ${path} is not a version 1 depwire ratchet file`);
+  }
+  return raw as RatchetState;
+}
+
+export function writeRatchet(path: string, state: RatchetState): void {
+  const counts = Object.fromEntries(Object.entries(state.counts).sort(([a], [b]) => a.localeCompare(b)));
+  writeFileSync(path, JSON.stringify({ version: 1, counts }, null, 2) + '\n');
+}
+
+/**
+ * Apply ratchets to a lint result. Findings of ratcheted rules are demoted
+ * to info — the count is what is enforced — and a rule whose count went up
+ * gets one error finding saying by how much.
+ */
+export function applyRatchet(result: LintResult, state: RatchetState, rules: string[]): RatchetOutcome {
+  const counts = { ...state.counts };
+  const regressed: RatchetChange[] = [];
+  const tightened: RatchetChange[] = [];
+  const ratcheted = new Set(rules);
+  const overruns: RuleFinding[] = [];
+  for (const rule of rules) {
+    // Only rules that ran are counted: a rule switched off must not reset its allowance to zero
+    if (!result.rules.includes(rule)) continue;
+    const count = result.findings.filter(f => f.rule === rule && f.severity !== 'info').length;
+    const allowed = state.counts[rule];
+    if (allowed === undefined || count < allowed) {
+      tightened.push({ rule, allowed, count });
+      counts[rule] = count;
+    } else if (count > allowed) {
+      regressed.push({ rule, allowed, count });
+      overruns.push({
+        rule,
+        severity: 'error',
+        message: `${count} ${rule} findings, up from the ratchet's ${allowed} (+${count - allowed}) — fix the new ones; the count may only go down`,
+      });
+    }
+  }
+
+  // Project-level findings sort first, like the engine's own
+  const findings: RuleFinding[] = [
+    ...overruns,
+    ...result.findings.map(f => (ratcheted.has(f.rule) && f.severity !== 'info' ? { ...f, severity: 'info' as const } : f)),
+  ];
+  return {
+    result: {
+      ...result,
+      findings,
+      summary: summarize(findings),
+    },
+    state: { version: 1, counts },
+    regressed,
+    tightened,
+  };
+}
@@ -0,0 +1,117 @@
+import { execFile } from 'child_process';
+import { mkdtempSync, writeFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join, resolve } from 'path';
+import { createRule } from './engine.js';
+import type { Rule, RuleContext, RuleDefinitionOptions, RuleFindingInput } from './types.js';
+import { buildExportGraph, toGraphDocument, type GraphDocument } from '../export/graph.js';
+import { findGoModules } from '../golang/modules.js';
+
+/**
+ * Rego policies evaluated with Open Policy Agent against the dependency graph.
+ * Policies live in package depwire and produce `deny` (errors) and `warn`
+ * (warnings) sets, the conftest convention:
+ *
+ *   package depwire
+ *
+ *   deny contains msg if {
+ *     some e in input.packages.edges
+ *     startswith(e.source, "internal/api")
+ *     startswith(e.target, "internal/db")
+ *     msg := sprintf("%s must go through the service layer", [e.source])
+ *   }
+ *
+ * input.packages and input.files are `depwire graph --format json` documents
+ * at each level. A message may also be an object with msg and optional file,
+ * line, source and target, to point the finding somewhere.
+ */
+
+export interface RegoPolicyOptions {
+  /** .rego files or directories of them */
+  policies: string[];
+  /** The opa binary (default: DEPWIRE_OPA, then opa on PATH) */
+  opa?: string;
+}
+
+/** The document policies see as `input` */
+export interface PolicyInput {
+  projectRoot: string;
+  packages: GraphDocument;
+  files: GraphDocument;
+  modules: Array<{ path: string; version: string; indirect: boolean; goModFile: string }>;
+}
+
+export function policyInput(ctx: Pick<RuleContext, 'projectRoot' | 'graph'>): PolicyInput {
+  const modules = findGoModules(ctx.projectRoot);
+  const local = new Set(modules.map(m => m.path));
+  return {
+    projectRoot: ctx.projectRoot,
+    packages: toGraphDocument(buildExportGraph(ctx.graph, ctx.projectRoot, { level: 'package' })),
+    files: toGraphDocument(buildExportGraph(ctx.graph, ctx.projectRoot, { level: 'file' })),
+    modules: modules.flatMap(mod => mod.mod.require
+      .filter(req => !local.has(req.path))
+      .map(req => ({ path: req.path, version: req.version, indirect: req.indirect, goModFile: mod.goModFile }))),
+  };
+}
+
+function runOpa(opa: string, args: string[]): Promise<string> {
+  return new Promise((resolvePromise, reject) => {
+    execFile(opa, args, { maxBuffer: 256 * 1024 * 1024, encoding: 'utf-8' }, (error, stdout, stderr) => {
+      if (error && (error as NodeJS.ErrnoException).code === 'ENOENT') {
+        reject(new Error(`${opa} not found — install Open Policy Agent or set DEPWIRE_OPA`));
+      } else if (error) {
+        // Compile errors come back as JSON on stdout with --format json
+        let detail = stderr.trim();
+        try {
+          detail = JSON.parse(stdout).errors.map((e: { message: string; location?: { file: string; row: number } }) =>
+            e.location ? `${e.location.file}:${e.location.row}: ${e.message}` : e.message).join('; ');
+        } catch { /* not JSON */ }
+        reject(new Error(`opa eval failed: ${detail || error.message}`));
+      } else {
+        resolvePromise(stdout);
+      }
+    });
+  });
+}
+
+function toFinding(value: unknown): RuleFindingInput {
+  if (typeof value === 'string') return { message: value };
+  const v = (value ?? {}) as Record<string, unknown>;
+  return {
+    message: typeof v.msg === 'string' ? v.msg : JSON.stringify(value),
+    file: typeof v.file === 'string' ? v.file : undefined,
+    line: typeof v.line === 'number' ? v.line : undefined,
+    source: typeof v.source === 'string' ? v.source : undefined,
+    target: typeof v.target === 'string' ? v.target : undefined,
+  };
+}
+
+/**
+ * A rule that fails on every message in `data.depwire.deny` and warns on
+ * every message in `data.depwire.warn`.
+ *
+ *   regoPolicyRule({ policies: ['policy/'] })
+ */
+export function regoPolicyRule(options: RegoPolicyOptions, definition: RuleDefinitionOptions = {}): Rule {
+  const opa = options.opa ?? process.env.DEPWIRE_OPA ?? 'opa';
+  return createRule('rego-policy', async (ctx) => {
+    const dir = mkdtempSync(join(tmpdir(), 'depwire-opa-'));
+    try {
+      const inputFile = join(dir, 'input.json');
+      writeFileSync(inputFile, JSON.stringify(policyInput(ctx)));
+      const args = ['eval', '--format', 'json', '--input', inputFile];
+      for (const policy of options.policies) args.push('--data', resolve(ctx.projectRoot, policy));
+      args.push('data.depwire');
+
+      const output = JSON.parse(await runOpa(opa, args));
+      const result = output.result?.[0]?.expressions?.[0]?.value ?? {};
+      for (const value of [result.deny ?? []].flat()) ctx.report({ ...toFinding(value), severity: 'error' });
+      for (const value of [result.warn ?? []].flat()) ctx.report({ ...toFinding(value), severity: 'warning' });
+    } finally {
+      rmSync(dir, { recursive: true, force: true });
+    }
+  }, {
+    description: definition.description ?? `The dependency graph must satisfy the Rego policies in ${options.policies.join(', ')}`,
+    severity: definition.severity ?? 'error',
+  });
+}
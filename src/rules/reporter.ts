@@ -0,0 +1,115 @@
+import chalk from 'chalk';
+import type { LintResult, RuleSeverity } from './types.js';
+import { porcelainLine } from '../utils/porcelain.js';
+
+const SEVERITY_COLORS: Record<RuleSeverity, (s: string) => string> = {
+  error: chalk.red,
+  warning: chalk.yellow,
+  info: chalk.dim,
+};
+
+export function formatLintTable(result: LintResult): string {
+  const lines: string[] = [];
+
+  lines.push('');
+  lines.push(chalk.bold('Depwire Lint'));
+  lines.push('');
+
+  if (result.findings.length === 0) {
+    lines.push(chalk.green.bold(`  No findings (${result.rules.length} rules checked).`));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  // Group findings by file, in the order the engine sorted them
+  const byFile = new Map<string, typeof result.findings>();
+  for (const finding of result.findings) {
+    const key = finding.file ?? '(project)';
+    if (!byFile.has(key)) byFile.set(key, []);
+    byFile.get(key)!.push(finding);
+  }
+
+  for (const [file, findings] of byFile) {
+    lines.push(chalk.underline(file));
+    for (const finding of findings) {
+      const colorFn = SEVERITY_COLORS[finding.severity];
+      const position = finding.line && finding.column ? `${finding.line}:${finding.column}` : `${finding.line ?? ''}`;
+      const location = position.padStart(5);
+      lines.push(`  ${chalk.dim(location)}  ${colorFn(finding.severity.padEnd(7))}  ${finding.message}  ${chalk.dim(finding.rule)}`);
+    }
+    lines.push('');
+  }
+
+  const { error, warning, info } = result.summary;
+  const parts = [
+    error > 0 ? chalk.red(`${error} error${error === 1 ? '' : 's'}`) : null,
+    warning > 0 ? chalk.yellow(`${warning} warning${warning === 1 ? '' : 's'}`) : null,
+    info > 0 ? chalk.dim(`${info} info`) : null,
+  ].filter(Boolean);
+  lines.push(`${parts.join(', ')} from ${result.rules.length} rules`);
+  lines.push('');
+
+  return lines.join('\n');
+}
+
+export function formatLintJSON(result: LintResult): string {
+  return JSON.stringify(result, null, 2);
+}
+
+/** One finding per line: severity, rule, file, line, column, message, fingerprint; nothing when clean */
+export function formatLintPorcelain(result: LintResult): string {
+  return result.findings
+    .map(f => porcelainLine([f.severity, f.rule, f.file, f.line, f.column, f.message, f.fingerprint]))
+    .join('\n');
+}
+
+const SARIF_LEVELS: Record<RuleSeverity, string> = {
+  error: 'error',
+  warning: 'warning',
+  info: 'note',
+};
+
+/** SARIF 2.1.0 for code scanning and editor annotations; dependency findings point at the import or call that creates the edge */
+export function formatLintSARIF(result: LintResult, version: string): string {
+  // Findings that still share a fingerprint (the same call twice in a file) are numbered, as GitHub does its line hashes
+  const seen = new Map<string, number>();
+  const partialFingerprint = (fingerprint: string) => {
+    const n = (seen.get(fingerprint) ?? 0) + 1;
+    seen.set(fingerprint, n);
+    return `${fingerprint}:${n}`;
+  };
+  const results = result.findings.map(f => ({
+    ruleId: f.rule,
+    level: SARIF_LEVELS[f.severity],
+    message: { text: f.message },
+    locations: f.file ? [
+      {
+        physicalLocation: {
+          artifactLocation: { uri: f.file },
+          region: f.line ? { startLine: f.line, ...(f.column ? { startColumn: f.column } : {}) } : undefined,
+        },
+      },
+    ] : undefined,
+    partialFingerprints: f.fingerprint ? { 'depwire/v1': partialFingerprint(f.fingerprint) } : undefined,
+    properties: f.source || f.target || f.symbol ? { source: f.source, target: f.target, symbol: f.symbol } : undefined,
+  }));
+
+  const sarif = {
+    $schema: 'https://json.schemastore.org/sarif-2.1.0.json',
+    version: '2.1.0',
+    runs: [
+      {
+        tool: {
+          driver: {
+            name: 'depwire',
+            version,
+            rules: result.rules.map(id => ({ id })),
+          },
+        },
+        results,
+      },
+    ],
+  };
+
+  return JSON.stringify(sarif, null, 2);
+}
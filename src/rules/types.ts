@@ -0,0 +1,78 @@
+import type { DirectedGraph } from 'graphology';
+import type { DepwireGraph, WeightedEdgeAttributes } from '../graph/model.js';
+import type { ParsedFile } from '../parser/types.js';
+
+export type RuleSeverity = 'error' | 'warning' | 'info';
+
+export interface RuleFinding {
+  /** ID of the rule that produced the finding */
+  rule: string;
+  severity: RuleSeverity;
+  message: string;
+  file?: string;
+  line?: number;
+  /** 1-based column, when the rule knows it */
+  column?: number;
+  /** Symbol ID the finding is about, when it is symbol-level */
+  symbol?: string;
+  /** For dependency findings: the offending edge, as file paths or symbol IDs */
+  source?: string;
+  target?: string;
+  /** Tells the finding apart from others about the same subject (a capability, a check); fingerprinted instead of the message */
+  key?: string;
+  /** What the finding is about (rule, file, symbol, edge, key) hashed, without line or wording — waivers key on it */
+  fingerprint?: string;
+}
+
+/** A finding as reported from a matcher — rule and severity default to the reporting rule */
+export type RuleFindingInput = Omit<RuleFinding, 'rule' | 'severity' | 'fingerprint'> & { severity?: RuleSeverity };
+
+export interface RuleContext {
+  projectRoot: string;
+  /** Symbol-level graph from buildGraph() */
+  graph: DepwireGraph;
+  /** File-level graph (computed once, shared by all rules) */
+  fileGraph: DirectedGraph<{ filePath: string }, WeightedEdgeAttributes>;
+  parsedFiles: ParsedFile[];
+  /** Options for this rule from the caller (e.g. a config file) */
+  options: Record<string, unknown>;
+  report(finding: RuleFindingInput): void;
+}
+
+/**
+ * A matcher inspects the graph and reports findings, either through
+ * context.report() or by returning them.
+ */
+export type RuleMatcher = (context: RuleContext) => RuleFindingInput[] | void | Promise<RuleFindingInput[] | void>;
+
+export interface Rule {
+  id: string;
+  description: string;
+  severity: RuleSeverity;
+  check: RuleMatcher;
+}
+
+export interface RuleDefinitionOptions {
+  description?: string;
+  severity?: RuleSeverity;
+}
+
+export interface LintResult {
+  projectRoot: string;
+  checkedAt: string;
+  rules: string[];
+  findings: RuleFinding[];
+  summary: {
+    error: number;
+    warning: number;
+    info: number;
+    total: number;
+  };
+}
+
+export interface LintOptions {
+  /** Per-rule options, keyed by rule ID */
+  ruleOptions?: Record<string, Record<string, unknown>>;
+  /** Override rule severities, keyed by rule ID; 'off' disables the rule */
+  severities?: Record<string, RuleSeverity | 'off'>;
+}
@@ -0,0 +1,185 @@
+import { readFileSync, writeFileSync } from 'fs';
+import type { LintResult } from './types.js';
+import type { WaiverOutcome } from './waivers.js';
+import type { RatchetOutcome, RatchetState } from './ratchet.js';
+
+/**
+ * The verdict: one small document saying whether a lint run passes, for
+ * merge-queue bots and other gates that shouldn't parse the full report.
+ * It records pass/fail per rule, how ratcheted counts moved and which
+ * waivers were used. The JSON Schema ships as dist/rules/verdict.schema.json;
+ * fields are only ever added within a version.
+ */
+
+export const VERDICT_VERSION = 1;
+
+export type VerdictStatus = 'pass' | 'fail';
+
+export interface VerdictRule {
+  id: string;
+  status: VerdictStatus;
+  errors: number;
+  warnings: number;
+  info: number;
+  /** Findings exempted by a waiver (counted under info) */
+  waived: number;
+}
+
+export interface VerdictRatchet {
+  rule: string;
+  /** Allowed count before this run, or null the first time the rule is ratcheted */
+  allowed: number | null;
+  count: number;
+  /** count − allowed: negative when debt shrank */
+  change: number;
+}
+
+export interface VerdictWaiver {
+  rule: string;
+  fingerprint: string;
+  approver: string;
+  reason: string;
+  expires: string;
+  file?: string;
+}
+
+export interface Verdict {
+  version: typeof VERDICT_VERSION;
+  verdict: VerdictStatus;
+  /** Why it failed, one line each; empty on pass */
+  reasons: string[];
+  projectRoot: string;
+  commit: string | null;
+  checkedAt: string;
+  depwireVersion: string | null;
+  summary: LintResult['summary'];
+  rules: VerdictRule[];
+  delta: {
+    /** Git ref cycles were compared against (lint --delta), or null for absolute checks */
+    base: string | null;
+    ratchets: VerdictRatchet[];
+  };
+  waivers: {
+    applied: VerdictWaiver[];
+    /** Waivers that match no finding */
+    unused: number;
+  };
+}
+
+export interface VerdictOptions {
+  commit?: string | null;
+  depwireVersion?: string;
+  /** Base ref when cycles were checked with --delta */
+  base?: string;
+  maxWarnings?: number;
+  waivers?: WaiverOutcome;
+  ratchet?: { rules: string[]; previous: RatchetState; outcome: RatchetOutcome };
+}
+
+export function buildVerdict(result: LintResult, options: VerdictOptions = {}): Verdict {
+  const ids = [...new Set([...result.rules, ...result.findings.map(f => f.rule)])].sort();
+  const applied = options.waivers?.applied ?? [];
+  const rules: VerdictRule[] = ids.map(id => {
+    const findings = result.findings.filter(f => f.rule === id);
+    const errors = findings.filter(f => f.severity === 'error').length;
+    return {
+      id,
+      status: errors > 0 ? 'fail' : 'pass',
+      errors,
+      warnings: findings.filter(f => f.severity === 'warning').length,
+      info: findings.filter(f => f.severity === 'info').length,
+      waived: applied.filter(a => a.finding.rule === id).length,
+    };
+  });
+
+  const ratchets: VerdictRatchet[] = [];
+  if (options.ratchet) {
+    const { rules: ratcheted, previous, outcome } = options.ratchet;
+    for (const rule of ratcheted.filter(r => result.rules.includes(r)).sort()) {
+      const allowed = previous.counts[rule] ?? null;
+      const count = outcome.regressed.find(r => r.rule === rule)?.count ?? outcome.state.counts[rule] ?? 0;
+      ratchets.push({ rule, allowed, count, change: allowed === null ? 0 : count - allowed });
+    }
+  }
+
+  const reasons = rules
+    .filter(r => r.status === 'fail')
+    .map(r => `${r.id}: ${r.errors} error${r.errors === 1 ? '' : 's'}`);
+  if (options.maxWarnings !== undefined && result.summary.warning > options.maxWarnings) {
+    reasons.push(`${result.summary.warning} warnings exceed the maximum of ${options.maxWarnings}`);
+  }
+
+  return {
+    version: VERDICT_VERSION,
+    verdict: reasons.length > 0 ? 'fail' : 'pass',
+    reasons,
+    projectRoot: result.projectRoot,
+    commit: options.commit ?? null,
+    checkedAt: result.checkedAt,
+    depwireVersion: options.depwireVersion ?? null,
+    summary: result.summary,
+    rules,
+    delta: { base: options.base ?? null, ratchets },
+    waivers: {
+      applied: applied.map(({ waiver, finding }) => ({
+        rule: waiver.rule,
+        fingerprint: waiver.fingerprint,
+        approver: waiver.approver,
+        reason: waiver.reason,
+        expires: waiver.expires,
+        ...(finding.file ? { file: finding.file } : {}),
+      })),
+      unused: options.waivers?.unused.length ?? 0,
+    },
+  };
+}
+
+export function writeVerdict(path: string, verdict: Verdict): void {
+  writeFileSync(path, JSON.stringify(verdict, null, 2) + '\n');
+}
+
+export function readVerdict(path: string): Verdict {
+  let raw: any;
+  try {
+    raw = JSON.parse(readFileSync(path, 'utf-8'));
+  } catch (err) {
+    throw new Error(`Could not read verdict ${path}: ${err instanceof Error ? err.message : err}`);
+  }
+  if (raw?.version !== VERDICT_VERSION || (raw.verdict !== 'pass' && raw.verdict !== 'fail') || !Array.isArray(raw.rules)) {
+    throw new Error(`${path} is not a version ${VERDICT_VERSION} depwire verdict (written by depwire lint --verdict)`);
+  }
+  return raw as Verdict;
+}
+
+export interface VerdictAssertion {
+  /** Gate on these rules only — each must have run and passed — instead of the overall verdict */
+  rules?: string[];
+  /** The commit the verdict must be for (a prefix is enough) */
+  commit?: string;
+  /** Fail when any finding was waived */
+  noWaivers?: boolean;
+}
+
+/** Check a verdict for a downstream gate; returns the failures, empty when it holds */
+export function assertVerdict(verdict: Verdict, assertion: VerdictAssertion = {}): string[] {
+  const failures: string[] = [];
+  if (assertion.rules && assertion.rules.length > 0) {
+    for (const id of assertion.rules) {
+      const rule = verdict.rules.find(r => r.id === id);
+      if (!rule) failures.push(`${id} did not run`);
+      else if (rule.status === 'fail') failures.push(`${id}: ${rule.errors} error${rule.errors === 1 ? '' : 's'}`);
+    }
+  } else if (verdict.verdict === 'fail') {
+    failures.push(...(verdict.reasons.length > 0 ? verdict.reasons : ['verdict is fail']));
+  }
+  if (assertion.commit) {
+    if (!verdict.commit) failures.push(`verdict has no commit, expected ${assertion.commit}`);
+    else if (!verdict.commit.startsWith(assertion.commit) && !assertion.commit.startsWith(verdict.commit)) {
+      failures.push(`verdict is for ${verdict.commit.slice(0, 12)}, not ${assertion.commit.slice(0, 12)}`);
+    }
+  }
+  if (assertion.noWaivers && verdict.waivers.applied.length > 0) {
+    failures.push(`${verdict.waivers.applied.length} findings were waived`);
+  }
+  return failures;
+}
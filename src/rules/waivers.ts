@@ -0,0 +1,144 @@
+import { readFileSync } from 'fs';
+import { createPrivateKey, createPublicKey, sign, verify } from 'crypto';
+import { summarize } from './engine.js';
+import { pae } from '../attest/dsse.js';
+import { algorithmFor } from '../attest/index.js';
+import type { LintResult, RuleFinding } from './types.js';
+
+/**
+ * Waivers: committed, time-boxed exceptions for individual findings. Each
+ * names the rule and the finding's fingerprint (from `depwire lint --format
+ * json`), who approved it, why, and when it expires:
+ *
+ *   { "waivers": [{ "rule": "go-capabilities", "fingerprint": "3f2a9c1e0b7d4a56",
+ *       "approver": "security@example.com", "reason": "vendored exec wrapper, see SEC-214",
+ *       "expires": "2026-12-31" }] }
+ */
+
+export const WAIVERS_FILE = 'depwire.waivers.json';
+
+export interface Waiver {
+  rule: string;
+  fingerprint: string;
+  approver: string;
+  reason: string;
+  /** Last day the waiver applies (YYYY-MM-DD, inclusive) */
+  expires: string;
+  /** The approver's signature over the other fields (base64), when approvers have keys */
+  signature?: string;
+}
+
+export interface WaiverOptions {
+  /** Who may approve waivers; any approver when unset */
+  approvers?: string[];
+  /** Approver → public key (PEM, or a file containing it); waivers by these approvers must be signed */
+  keys?: Record<string, string>;
+  /** Defaults to today */
+  now?: Date;
+}
+
+export interface WaiverOutcome {
+  result: LintResult;
+  /** Waivers that exempted a finding */
+  applied: Array<{ waiver: Waiver; finding: RuleFinding }>;
+  /** Waivers that no longer match any finding — safe to delete */
+  unused: Waiver[];
+}
+
+const REQUIRED: Array<keyof Waiver> = ['rule', 'fingerprint', 'approver', 'reason', 'expires'];
+const WAIVER_PAYLOAD_TYPE = 'application/vnd.depwire.waiver+json';
+
+// What the signature covers: every field but the signature, in a fixed order
+function waiverMessage(waiver: Waiver): Buffer {
+  const payload = JSON.stringify(REQUIRED.map(key => waiver[key]));
+  return pae(WAIVER_PAYLOAD_TYPE, Buffer.from(payload, 'utf-8'));
+}
+
+/** Sign a waiver with the approver's private key (PEM) */
+export function signWaiver(waiver: Waiver, privateKeyPem: string): Waiver {
+  const key = createPrivateKey(privateKeyPem);
+  return { ...waiver, signature: sign(algorithmFor(key), waiverMessage(waiver), key).toString('base64') };
+}
+
+export function readWaivers(path: string): unknown[] {
+  let raw: any;
+  try {
+    raw = JSON.parse(readFileSync(path, 'utf-8'));
+  } catch (err) {
+    throw new Error(`Could not read waivers ${path}: ${err instanceof Error ? err.message : err}`);
+  }
+  if (!Array.isArray(raw.waivers)) throw new Error(`Waivers ${path}: expected { "waivers": [...] }`);
+  return raw.waivers;
+}
+
+// Problems with the waiver itself, or null when it is valid
+function waiverProblem(value: unknown, options: WaiverOptions): string | null {
+  const waiver = value as Partial<Waiver>;
+  const missing = REQUIRED.filter(key => typeof waiver?.[key] !== 'string' || !waiver[key]!.trim());
+  if (missing.length > 0) return `is missing ${missing.join(', ')}`;
+  if (!/^\d{4}-\d{2}-\d{2}$/.test(waiver.expires!) || Number.isNaN(Date.parse(waiver.expires!))) {
+    return `has an invalid expiry "${waiver.expires}" (expected YYYY-MM-DD)`;
+  }
+  if (options.approvers && !options.approvers.includes(waiver.approver!)) {
+    return `is approved by ${waiver.approver}, who is not an allowed approver (${options.approvers.join(', ')})`;
+  }
+  const publicKey = options.keys?.[waiver.approver!];
+  if (publicKey) {
+    if (!waiver.signature) return `is not signed by ${waiver.approver}`;
+    const key = createPublicKey(publicKey.includes('-----BEGIN') ? publicKey : readFileSync(publicKey, 'utf-8'));
+    if (!verify(algorithmFor(key), waiverMessage(waiver as Waiver), key, Buffer.from(waiver.signature, 'base64'))) {
+      return `has a signature that does not verify with ${waiver.approver}'s key — was it edited after signing?`;
+    }
+  }
+  return null;
+}
+
+/**
+ * Exempt waived findings: they stay in the report as info, annotated with
+ * the approver and expiry. Invalid and expired waivers are errors of their
+ * own and exempt nothing; unused ones are warnings, so the file doesn't rot.
+ */
+export function applyWaivers(result: LintResult, waivers: unknown[], options: WaiverOptions = {}): WaiverOutcome {
+  const today = (options.now ?? new Date()).toISOString().slice(0, 10);
+  const problems: RuleFinding[] = [];
+  const active = new Map<string, Waiver>();
+
+  waivers.forEach((value, i) => {
+    const waiver = value as Waiver;
+    const name = `Waiver #${i + 1}${typeof waiver?.rule === 'string' ? ` (${waiver.rule} ${waiver.fingerprint ?? ''})` : ''}`;
+    const problem = waiverProblem(value, options);
+    if (problem) {
+      problems.push({ rule: 'waiver', severity: 'error', file: WAIVERS_FILE, message: `${name} ${problem}` });
+    } else if (waiver.expires < today) {
+      problems.push({ rule: 'waiver', severity: 'error', file: WAIVERS_FILE, message: `${name} expired on ${waiver.expires} — fix the finding or renew the waiver` });
+    } else {
+      active.set(`${waiver.rule}\0${waiver.fingerprint}`, waiver);
+    }
+  });
+
+  const applied: WaiverOutcome['applied'] = [];
+  const findings = result.findings.map(finding => {
+    const waiver = active.get(`${finding.rule}\0${finding.fingerprint}`);
+    if (!waiver) return finding;
+    applied.push({ waiver, finding });
+    return { ...finding, severity: 'info' as const, message: `${finding.message} (waived by ${waiver.approver} until ${waiver.expires})` };
+  });
+
+  const used = new Set(applied.map(a => a.waiver));
+  // A waiver for a rule that didn't run (--rule) isn't stale
+  const unused = [...active.values()].filter(w => !used.has(w) && result.rules.includes(w.rule));
+  for (const waiver of unused) {
+    problems.push({ rule: 'waiver', severity: 'warning', file: WAIVERS_FILE, message: `Waiver for ${waiver.rule} ${waiver.fingerprint} matches no finding — remove it` });
+  }
+
+  const all = [...problems, ...findings].sort((a, b) => (a.file ?? '').localeCompare(b.file ?? '') || (a.line ?? 0) - (b.line ?? 0));
+  return {
+    result: {
+      ...result,
+      findings: all,
+      summary: summarize(all),
+    },
+    applied,
+    unused,
+  };
+}
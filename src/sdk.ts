@@ -21,19 +21,43 @@ export const DepwireSDKVersion: string = packageJson.version;
 
 /** Parse a codebase directory and return raw parsed data */
 export { parseProject } from './parser/index.js';
+export type { ParseOptions, SkipReason } from './parser/index.js';
+
+/** The include/exclude/generated-code and internal/external filters commands share */
+export { createFilter, isGeneratedFile, filterLintResult } from './parser/filter.js';
+export type { FilterOptions, AnalysisFilter, DependencyScope } from './parser/filter.js';
+
+/** Cancellation and progress reporting for long-running operations (pass signal/onProgress in options) */
+export { CancelledError, isCancelled } from './utils/progress.js';
+export type { ProgressEvent, ProgressCallback } from './utils/progress.js';
 
 /** Build a graphology DirectedGraph from parsed data */
 export { buildGraph } from './graph/index.js';
+export type { BuildGraphOptions } from './graph/index.js';
 
 /** Calculate 0-100 architecture health score from a graph */
 export { calculateHealthScore } from './health/index.js';
 
+/** Weighted dependency health score (cycles, coupling, depth, direct modules) and shields-style SVG badges */
+export { dependencyHealth, DEFAULT_DEPENDENCY_HEALTH_WEIGHTS } from './health/dependency.js';
+export type { DependencyHealth, DependencyHealthOptions, DependencyHealthComponent } from './health/dependency.js';
+export { renderBadge, scoreColor } from './export/badge.js';
+export type { Badge, BadgeColor } from './export/badge.js';
+
 /** Detect unused symbols with High/Medium/Low confidence */
 export { analyzeDeadCode } from './dead-code/index.js';
 
 /** Generate the architecture documents for a codebase */
 export { generateDocs } from './docs/index.js';
 
+/** Per-package dependency sections in doc.go, or DEPENDENCIES.md sidecars */
+export { packageDependencies, writePackageDependencyDocs, injectDocSection, dependencyDocSection, dependencyMarkdown } from './docs/packages.js';
+export type { PackageDependencies, PackageDocOptions } from './docs/packages.js';
+
+/** Package reading order for onboarding: leaves first, entry points last */
+export { readingOrder, packageSynopsis, formatReadingOrderMarkdown } from './docs/reading-order.js';
+export type { ReadingOrder, ReadingStep, ReadingRole } from './docs/reading-order.js';
+
 /** Search for symbols by name across the graph (partial matching) */
 export { searchSymbols } from './graph/queries.js';
 
@@ -43,6 +67,38 @@ export { getImpact } from './graph/queries.js';
 /** Get high-level architecture summary — file count, symbol count, most connected files */
 export { getArchitectureSummary } from './graph/queries.js';
 
+/** Typed node/edge accessors and iterators over the graph, plus file- and package-level collapsing */
+export { getNode, getNodeByStableId, nodes, edges, outEdges, inEdges, toFileGraph, toPackageGraph, packageOf, edgePosition } from './graph/model.js';
+export type { DepwireGraph, NodeAttributes, EdgeAttributes, GraphEdge, SourcePosition, WeightedEdgeAttributes } from './graph/model.js';
+
+/** Content-addressable node/edge IDs — identical across runs and machines, for correlating baselines and external stores */
+export { stableNodeId, stableEdgeId } from './graph/stable-id.js';
+
+/** Graph algorithms — topological sort, SCCs, cycles, dominators, shortest path, reachability, neighborhoods, communities */
+export {
+  topologicalSort,
+  stronglyConnectedComponents,
+  findCycles,
+  dominators,
+  shortestPath,
+  reachable,
+  neighborhood,
+  communities,
+} from './graph/algorithms.js';
+export type { Direction } from './graph/algorithms.js';
+
+/** Graph data types produced by the parser and serializer */
+export type { SymbolNode, SymbolEdge, SymbolKind, EdgeKind, ParsedFile, ProjectGraph } from './parser/types.js';
+
+/** Change impact — files, packages, binaries and services transitively affected by changed files */
+export { analyzeChangeImpact } from './impact/index.js';
+export { changedTargets } from './impact/targets.js';
+export type { ChangedTarget, ChangedTargetsOptions, ChangedTargetsResult, TargetKind } from './impact/targets.js';
+export { parseUnifiedDiff } from './impact/changes.js';
+export { analyzeTestImpact } from './impact/tests.js';
+export type { TestImpactResult, AffectedTestPackage } from './impact/tests.js';
+export type { ChangeImpactResult, AffectedItem, AffectedTarget } from './impact/types.js';
+
 /** Simulation engine — simulate a move/delete/rename/split/merge before touching code */
 export { SimulationEngine } from './simulation/engine.js';
 
@@ -76,6 +132,84 @@ export type {
   VulnerabilityClass
 } from './security/types.js';
 
+/**
+ * Lint rule engine — define rules with createRule(), register them (or whole
+ * rule sets) on a RuleRegistry, then run against a built graph.
+ * Findings come back as structured RuleFinding values.
+ */
+export {
+  createRule,
+  RuleRegistry,
+  runRules,
+  forbidDependency,
+  noCircularDependencies,
+  builtinRules,
+  goAnalysisRule,
+  goInitRule,
+  goLocalReplaceRule,
+  goBlankImportRule,
+  goDotImportRule,
+  goCapabilityRule,
+  goScorecardRule,
+  goTripwireRule,
+  goPanicRule,
+  noNewCircularDependencies,
+  dependencyBudgetRule,
+  loadLintConfig,
+  frozenDependenciesRule,
+  snapshotLock,
+  readLock,
+  writeLock,
+  unapprovedEdges,
+  writeAdrStubs,
+  adrStub,
+  regoPolicyRule,
+  policyInput,
+  applyRatchet,
+  applyWaivers,
+  signWaiver,
+  findingFingerprint,
+  buildVerdict,
+  readVerdict,
+  writeVerdict,
+  assertVerdict,
+} from './rules/index.js';
+export type {
+  Rule,
+  RuleMatcher,
+  RuleContext,
+  RuleFinding,
+  RuleFindingInput,
+  RuleSeverity,
+  RuleDefinitionOptions,
+  LintResult,
+  LintOptions,
+  ForbiddenDependency,
+  GoInitOptions,
+  GoImportPolicyOptions,
+  GoLocalReplaceOptions,
+  GoCapabilityOptions,
+  GoScorecardOptions,
+  GoTripwireOptions,
+  GoPanicOptions,
+  NewCyclesOptions,
+  DependencyBudgets,
+  LintConfig,
+  DependencyLock,
+  LayerEdge,
+  AdrStub,
+  AdrOptions,
+  RegoPolicyOptions,
+  PolicyInput,
+  RatchetState,
+  RatchetOutcome,
+  Waiver,
+  WaiverOutcome,
+  Verdict,
+  VerdictRule,
+  VerdictAssertion,
+} from './rules/index.js';
+
 /**
  * Detect cross-language edges (REST API calls, subprocess invocations)
  * between files written in different languages.
@@ -83,3 +217,261 @@ export type {
  */
 export { detectCrossLanguageEdges } from './cross-language/index.js';
 export type { CrossLanguageEdge, CrossLanguageDetectionResult } from './cross-language/types.js';
+
+/** Run go/analysis analyzers through `go vet -json` and get structured diagnostics */
+export { runGoAnalyzers } from './golang/vet.js';
+export type { GoDiagnostic, GoAnalysisOptions, GoAnalysisResult } from './golang/vet.js';
+
+/** Preview a Go package move — imports, go.mod and build files to update, and new import cycles */
+export { previewPackageMove } from './refactor/move.js';
+export type { MovePreview, ImportUpdate, FileReference } from './refactor/move.js';
+
+/** Interface-extraction suggestions (with skeleton .go files) that break Go package import cycles */
+export { suggestCycleBreaks } from './refactor/interfaces.js';
+export type { CycleBreakSuggestion, SuggestedInterface, SuggestedMethod, CrossReference } from './refactor/interfaces.js';
+
+/** Exported identifiers of each Go package with external reference counts */
+export { analyzeApiSurface } from './api-surface/index.js';
+export type { ApiSurfaceReport, PackageApiSurface, ExportedSymbol, ApiSurfaceOptions } from './api-surface/index.js';
+
+/** apidiff-style comparison of a Go module's API between two git refs, with the internal consumers each break affects */
+export { diffModuleApi, compareApis } from './apidiff/index.js';
+export type { ApiDiffResult, ApiChange, ApiChangeType, ApiConsumer, ApiDiffOptions } from './apidiff/index.js';
+
+/** Go dependency-injection wiring (wire, fx, dig) — providers, consumer → provider bindings, missing providers */
+export { analyzeDiWiring } from './golang/di.js';
+export type { DiAnalysis, DiContainer, DiProvider, DiBinding, DiIssue, DiFramework } from './golang/di.js';
+
+/** Go package initialization — init order, coarse effects of init() code, and side-effect imports */
+export { analyzeInit, initOrder } from './golang/init.js';
+export type { InitReport, PackageInit, InitSite, InitEffect, SideEffectImport } from './golang/init.js';
+
+/** Go goroutine spawns — which functions start goroutines and whose code they run, per package */
+export { analyzeGoroutines, goroutineSpawns, spawnEdges } from './golang/goroutines.js';
+export type { GoroutineReport, GoroutineSpawn, GoroutineTarget, GoroutineEdge, GoroutineOptions } from './golang/goroutines.js';
+
+/** Go communication topology — channels and shared package variables crossing package boundaries */
+export { analyzeCommunication, communicationPoints, communicationEdges, analyzeGlobalState, globalVariables, detectSharedStateEdges } from './golang/topology.js';
+export type { CommunicationReport, CommunicationPoint, CommunicationEdge, CommunicationUse, CommunicationKind, ChannelFlow, GlobalStateReport, GlobalVariable, GlobalStateOptions } from './golang/topology.js';
+
+/** Go error flow — sentinel errors and error types returned, wrapped and checked across packages */
+export { analyzeErrorFlow, traceErrors, errorFlowEdges } from './golang/errorflow.js';
+export type { ErrorFlowReport, GoError, GoErrorKind, ErrorUse, ErrorUseKind, ErrorFlowEdge, ErrorFlowOptions } from './golang/errorflow.js';
+
+/** Go context propagation — call chains where a context.Context is dropped for context.Background/TODO */
+export { analyzeContextPropagation, droppedContexts } from './golang/contexts.js';
+export type { ContextReport, ContextFinding, ContextStep, ContextOptions } from './golang/contexts.js';
+
+/** Go panic reachability — exported functions a panic can escape from, with the chain down to it */
+export { analyzePanics, panicExposure } from './golang/panics.js';
+export type { PanicReport, PanicPackage, PanicExposure, PanicStep, PanicOptions } from './golang/panics.js';
+
+/** Go observability inventory — logging, metrics and tracing libraries per package */
+export { analyzeObservability, observabilityInventory, observabilitySites, observabilityLibrary, annotateObservability, OBSERVABILITY_CATEGORIES } from './golang/observability.js';
+export type { ObservabilityReport, ObservabilityCategory, ObservabilitySite, PackageObservability, PackageLibraryUse, LibraryUsage } from './golang/observability.js';
+
+/** Go build configurations — imports selected by build tags, GOOS and GOARCH */
+export { analyzeBuildConfigurations, buildConfigurations, fileConstraint, parseConstraint, evalConstraint, formatConstraint, KNOWN_GOOS, KNOWN_GOARCH } from './golang/buildtags.js';
+export type { BuildConfigReport, BuildConfigOptions, BuildConfiguration, ConditionalImport, ConditionalImportFile, BuildConstraint } from './golang/buildtags.js';
+
+/** HTTP routes mapped to handlers, their packages and transitive dependencies */
+export { analyzeRoutes, httpRoutes, routeMatches } from './golang/routes.js';
+export type { RouteReport, HttpRoute, RouteOptions, RouterFramework } from './golang/routes.js';
+
+/** Mermaid sequence diagrams of cross-package call flows */
+export { analyzeSequence, callSequence, toMermaidSequence } from './golang/sequence.js';
+export type { CallSequence, SequenceCall, SequenceOptions } from './golang/sequence.js';
+
+/** Modules and per-module sizes of a built Go binary, from its build info and symbol table */
+export { analyzeBinary, parseBuildInfo, parseSymbolSizes, symbolPackage, compareBinaryReports, recordBinarySize, readBinaryHistory, BINARY_HISTORY_FILE } from './golang/binary.js';
+export type { BinaryReport, BinaryModule, BinaryModuleVersion, BinaryPackage, BuildInfo, BinaryOptions, BinaryComparison, SizeChange, BuildSummary, BinarySizeRecord } from './golang/binary.js';
+
+/** Go binaries of a container image, pulled from an OCI registry or read from docker save, and their SBOM */
+export { analyzeImage, imageSbom, flattenGoBinaries, isGoBinary } from './golang/image.js';
+export type { ImageReport, ImageBinary, ImageModule, ImageOptions } from './golang/image.js';
+export { pullImage, parseImageReference, formatImageReference, dockerCredentials } from './remote/registry.js';
+export type { ImageReference, PulledImage, PullOptions, Platform, Descriptor } from './remote/registry.js';
+
+/** Kubernetes workloads mapped to images, Go binaries and the modules they ship */
+export { readManifests, parseManifest, parseYamlDocuments, workloadsOf, clusterWorkloads, workloadId, WORKLOAD_KINDS } from './remote/kubernetes.js';
+export type { Workload, WorkloadContainer, ClusterOptions } from './remote/kubernetes.js';
+export { auditWorkloads } from './supply-chain/workloads.js';
+export type { WorkloadAudit, WorkloadAuditOptions, WorkloadImage, ShippedModule } from './supply-chain/workloads.js';
+
+/** Test coverage from go test -coverprofile and the concentration risks it shows */
+export { parseCoverProfiles, readCoverProfiles, packageCoverage, percentCovered, concentrationRisks, unprofiledPackages } from './golang/coverage.js';
+export type { CoverageData, CoverageCount, ConcentrationRisk, CoverageRiskOptions } from './golang/coverage.js';
+
+/** pprof CPU profiles mapped onto the project's files and packages */
+export { parseCpuProfile, readCpuProfiles, hotPaths } from './golang/pprof.js';
+export type { CpuProfile, ProfileSample, ProfileFrame, HotPaths, HotSpot } from './golang/pprof.js';
+
+/** Synthetic Go projects of a chosen shape, for benchmarks and rule tests */
+export { generateFixture, writeFixture, FIXTURE_MANIFEST } from './golang/fixture.js';
+export type { FixtureOptions, FixtureShape, FixtureFile } from './golang/fixture.js';
+
+/** Compile time per package from go build -debug-actiongraph, and the rebuild each change triggers */
+export { parseActionGraph, readActionGraphs, attributeBuildTime, measureBuild } from './golang/buildtime.js';
+export type { BuildAction, BuildTimeReport, PackageBuildTime, MeasureBuildOptions } from './golang/buildtime.js';
+
+/** Runtime call edges recorded by the depwiretrace Go package */
+export { importTraces, compareWithTraces, readRuntimeEdges, clearRuntimeEdges, parseTrace, projectSymbol, detectRuntimeEdges, RUNTIME_EDGES_FILE } from './golang/trace.js';
+export type { RuntimeEdge, RuntimeEdgeStore, RuntimeTrace, TraceSource, TraceImport, TraceImportOptions, UnmatchedEdge, TraceComparison, StaticEdge, PackageCoverage } from './golang/trace.js';
+
+/** Go call graph — functions, resolved calls, entry points and reachability, syntactic or from pointer analysis */
+export { buildGoCallGraph, loadGoCallGraph, reachableFunctions, callPath, CALL_GRAPH_ALGORITHMS } from './golang/callgraph.js';
+export type { GoCallGraph, GoFunction, GoCall, CallGraphAlgorithm, LoadCallGraphOptions } from './golang/callgraph.js';
+export { pointerCallEdges, parseCallgraphOutput, ssaFunctionId, POINTER_ANALYSIS_TOOL } from './golang/pta.js';
+export type { PointerEdge, PointerAnalysis } from './golang/pta.js';
+
+/** Go code reached only dynamically — plugins, reflect.New factories and name-to-constructor registries */
+export { findDynamicSites, dynamicRootLocations } from './golang/dynamic.js';
+export type { DynamicSite, DynamicReport, DynamicKind } from './golang/dynamic.js';
+
+/** Interprocedural taint tracking for Go with configurable sources, sinks and sanitizers */
+export { analyzeTaint, runTaint } from './taint/index.js';
+export { DEFAULT_TAINT_CONFIG, loadTaintConfig } from './taint/config.js';
+export type { TaintResult, TaintFinding, TaintStep, TaintOptions } from './taint/index.js';
+export type { TaintConfig, TaintSource, TaintSink, TaintSanitizer } from './taint/config.js';
+
+/** Third-party Go package sources (go list -deps, or vendor/) */
+export { loadGoDependencies, withDependencies, goListDeps } from './golang/deps.js';
+export type { GoDependencies, GoDependencyModule, GoListPackage } from './golang/deps.js';
+
+/** unsafe and reflect usage in the workspace and dependencies, with entry-point reachability */
+export { analyzeUnsafe } from './unsafe/index.js';
+export type { UnsafeReport, UnsafeUse, UnsafeRisk, UnsafeReportOptions } from './unsafe/index.js';
+
+/** Per-module capabilities of third-party Go dependencies (network, exec, fs, env, syscall, cgo, unsafe) */
+export { analyzeCapabilities, fileCapabilities, CAPABILITIES } from './capabilities/index.js';
+export type { CapabilityReport, ModuleCapabilities, Capability, CapabilityEvidence } from './capabilities/index.js';
+export { parseStraceLog, syscallCapability, socketAddress, observeCapabilities, traceCommand, OBSERVABLE } from './capabilities/observe.js';
+export type { SyscallEvent, ObservedUse, ObservedModule, ObservationStatus, CapabilityObservation, ObserveOptions } from './capabilities/observe.js';
+
+/** Typosquatting checks for Go module paths (edit distance, homoglyphs, internal prefix lookalikes) */
+export { checkTyposquats, checkModulePath, newModules, editDistance, foldHomoglyphs, POPULAR_GO_MODULES } from './supply-chain/typosquat.js';
+export type { TyposquatReport, TyposquatMatch, TyposquatOptions, TyposquatReason, NewModule } from './supply-chain/typosquat.js';
+
+/** Dependency confusion checks for private Go module prefixes */
+export { analyzeConfusion, readGoModuleEnv, matchesGoPrefixPatterns, escapeModulePath, publicVersions } from './supply-chain/confusion.js';
+export type { ConfusionReport, ConfusionFinding, ConfusionOptions, ConfusionRisk, GoModuleEnv } from './supply-chain/confusion.js';
+
+/** Fetch a Go module from the module proxy and analyze its source without a checkout */
+export { fetchModule, openArchive, analyzeSource, parseModuleSpec, readZip, readArchive, extractEntries, parseGoProxy, resolveModuleVersion, hashModuleZip, hashModuleDir, parseGoSum, lookupModuleSum, parseNetrc } from './remote/index.js';
+export type { FetchedModule, FetchModuleOptions, OpenArchiveOptions, RemoteAnalysis, ArchiveEntry } from './remote/index.js';
+
+/** Upgrade comparison between two versions of a module */
+export { compareModuleVersions, formatModuleComparisonMarkdown } from './remote/compare.js';
+export type { ModuleComparison, CompareModuleOptions, LicenseChange, UpgradeRisk } from './remote/compare.js';
+export { detectLicense, identifyLicense } from './remote/license.js';
+
+/** Go module requirement graph (go mod graph) with enrichable node attributes */
+export { buildModuleGraph, compareVersions } from './golang/modgraph.js';
+export type { ModuleGraph, ModuleNodeAttributes, ModuleEdgeAttributes } from './golang/modgraph.js';
+
+/** Module requirements explained by the package imports that use them */
+export { explainModuleGraph } from './golang/modreasons.js';
+export type { ModuleGraphExplanation, ExplainedModule, ModuleRequirement, PackageImport, ExplainModuleGraphOptions } from './golang/modreasons.js';
+
+/** go/toolchain directives across the module graph and version-dependent language features */
+export { analyzeGoVersions, detectLanguageFeatures, compareGoVersions } from './golang/goversion.js';
+export type { GoVersionReport, ModuleGoVersion, LanguageFeatureUse } from './golang/goversion.js';
+
+/** Environment and completeness diagnostics: toolchain, module cache, load failures, skipped files */
+export { runDoctor } from './doctor/index.js';
+export type { DoctorReport, DoctorCheck, DoctorOptions, CheckStatus } from './doctor/index.js';
+
+/** OpenSSF Scorecard results for external Go modules */
+export { analyzeScorecards, enrichWithScorecards, fetchScorecard, repositoryOf, KEY_CHECKS } from './supply-chain/scorecard.js';
+export type { ScorecardReport, ScorecardResult, ModuleScorecard, ScorecardOptions } from './supply-chain/scorecard.js';
+
+/** Public modules that depend on ours (deps.dev counts, pkg.go.dev importers) */
+export { analyzeDependents, fetchDependentCounts, fetchImportedBy, parseImportedBy } from './supply-chain/dependents.js';
+export type { DependentsReport, DependentsOptions, DependentCounts, DependentProject, PackageImporters } from './supply-chain/dependents.js';
+
+/** Glossary of external modules with upstream metadata (deps.dev, pkg.go.dev, GOPROXY) */
+export { moduleGlossary, fetchModuleMetadata, formatGlossaryMarkdown, parsePkgGoDevSynopsis } from './supply-chain/glossary.js';
+export type { ModuleGlossaryEntry, GlossaryOptions } from './supply-chain/glossary.js';
+
+/** Pseudo-version pins resolved to commits and their upstream branch status */
+export { analyzePseudoVersions, discoverRepository } from './supply-chain/pseudo.js';
+export type { PseudoVersionReport, PseudoVersionPin, PseudoVersionOptions, PinStatus } from './supply-chain/pseudo.js';
+export { parsePseudoVersion, isPseudoVersion, type PseudoVersion } from './golang/pseudo.js';
+
+/** vendor/ verification against go.mod, modules.txt and go.sum-verified module sources */
+export { verifyVendor } from './supply-chain/vendor.js';
+export type { VendorReport, VendorFinding, VendorFindingKind, VendorVerifyOptions } from './supply-chain/vendor.js';
+export { parseModulesTxt, readModulesTxt, type VendoredModule } from './golang/vendor.js';
+
+/** Init-time tripwire: exec, network and payload decoding reachable from dependency initialization */
+export { analyzeTripwire } from './supply-chain/tripwire.js';
+export type { TripwireReport, TripwireFinding, TripwireStep, TripwireKind, TripwireOptions } from './supply-chain/tripwire.js';
+
+/** Known vulnerabilities of module versions from OSV */
+export { queryVulnerabilities, fetchAdvisory, OSV_API } from './supply-chain/osv.js';
+export type { OsvAdvisory, OsvOptions } from './supply-chain/osv.js';
+
+/** Hardcoded credentials in Go dependency sources, with the package they belong to */
+export { checkDependencySecrets, findSecretLiterals, shannonEntropy } from './security/checks/dependency-secrets.js';
+
+/** Signed in-toto attestations (DSSE, optionally keyless via Sigstore) of depwire reports */
+export { attestReport, createStatement, signEnvelope, verifyAttestation, pae, keyId, STATEMENT_TYPE, PREDICATE_TYPE } from './attest/index.js';
+export { keylessSign, checkCertificateIdentity, certificateIdentities, certificateIssuer } from './attest/sigstore.js';
+export type { Statement, AnalysisPredicate, DsseEnvelope, AttestOptions, VerifiedAttestation, AttestationVerifyOptions } from './attest/index.js';
+export type { SigstoreBundle, KeylessOptions, CertificateIdentity } from './attest/sigstore.js';
+
+/** Package/file graph exports (Graphviz DOT, SVG, HTML, GEXF, Mermaid, JSON), focused neighborhoods, metric heatmaps, clusters, diff overlays, treemaps, display names and terminal trees */
+export { buildExportGraph, focusGraph, resolveFocus, clusterGraph, toGraphDocument } from './export/graph.js';
+export { toDot, quoteDot } from './export/dot.js';
+export { toSvg, escapeXml } from './export/svg.js';
+export { toHtml } from './export/html.js';
+export { layeredLayout } from './export/layout.js';
+export { applyMetric, heatmap, legendStops, BUILTIN_METRICS } from './export/metrics.js';
+export { overlayDiff, diffSummary } from './export/diff.js';
+export { toGexf, historyGexf } from './export/gexf.js';
+export { toMermaid, quoteMermaid } from './export/mermaid.js';
+export { toTreemapSvg, toTreemapHtml } from './export/treemap.js';
+export { relabelGraph, parseAlias, resolveLabelOptions } from './export/labels.js';
+export { renderTree, renderMatrix, matrixFits } from './export/terminal.js';
+export { detectTerminal, fitLine, visibleLength } from './utils/terminal.js';
+export type { ExportGraph, ExportLevel, ExportNodeAttributes, ExportEdgeAttributes, Cluster, GraphDocument } from './export/graph.js';
+export type { DotOptions } from './export/dot.js';
+export type { MermaidOptions } from './export/mermaid.js';
+export type { SvgOptions } from './export/svg.js';
+export type { Layout, NodeBox, LayoutOptions } from './export/layout.js';
+export type { Heatmap, MetricContext, BuiltinMetric } from './export/metrics.js';
+export type { ChangeKind, DiffSummary } from './export/diff.js';
+export type { TreemapOptions } from './export/treemap.js';
+export type { LabelOptions, LabelAlias, LabelFlags } from './export/labels.js';
+export type { TreeOptions } from './export/terminal.js';
+export type { Terminal, TerminalOptions } from './utils/terminal.js';
+
+/** Leveled logging on stderr, as text or one JSON object per line */
+export { logger, configureLogging, verbosityLevel, LOG_LEVELS, LOG_FORMATS } from './utils/log.js';
+export type { Logger, LogLevel, LogFormat, LogFields } from './utils/log.js';
+
+/** The --porcelain record format: tab-separated, escaped, fields only ever appended */
+export { porcelainLine, printPorcelain } from './utils/porcelain.js';
+export type { OutputFlags, PorcelainField } from './utils/porcelain.js';
+
+/** Dependency metrics at fixed time steps over git history */
+export { analyzeHistory, sampleSteps, parseStep, graphMetrics, historyCsv } from './temporal/history.js';
+export type { HistoryPoint, HistoryMetrics, HistoryStep, HistoryOptions, HistoryResult } from './temporal/history.js';
+
+/** Drift against a stored baseline, with pluggable baseline storage */
+export { captureBaseline, compareBaseline, encodeBaseline, decodeBaseline, formatDriftMarkdown, registerBaselineStore, baselineStore } from './drift/index.js';
+export type { Baseline, DriftReport, DriftMetrics, MetricDrift, BaselineStore } from './drift/index.js';
+
+/** Dependency changes between two releases, for release notes */
+export { dependencyChangelog, formatChangelogMarkdown, parseReleaseRange } from './changelog/index.js';
+export type { DependencyChangelog, ChangelogAdvisory, ChangelogOptions } from './changelog/index.js';
+
+/** Committable architecture report (ARCHITECTURE.md) */
+export { buildArchitectureReport, formatArchitectureMarkdown } from './report/index.js';
+export type { ArchitectureReport, ArchitectureReportOptions, ArchitecturePackage, ArchitectureModule, ArchitectureMarkdownOptions, ArchitectureCoverage } from './report/index.js';
+export { formatArchitectureWiki, WIKI_PROFILES } from './report/wiki.js';
+export type { WikiProfile, WikiExport, WikiAttachment } from './report/wiki.js';
+
+/** Static documentation site (plain HTML, MkDocs or Hugo) from the architecture report */
+export { siteFiles, writeSite, buildSitePages, markdownToHtml, SITE_FORMATS } from './site/index.js';
+export type { SiteFormat, SiteFile, SitePage, SiteInput } from './site/index.js';
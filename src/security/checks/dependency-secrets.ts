@@ -0,0 +1,127 @@
+import type { Node } from 'web-tree-sitter';
+import { walk, type GoSourceFile } from '../../golang/source.js';
+import { loadGoDependencies, dependencyModule } from '../../golang/deps.js';
+import { SECRET_PATTERNS } from './secrets.js';
+import type { SecurityFinding, Severity } from '../types.js';
+
+/**
+ * Credential-looking string literals in the sources of Go dependencies
+ * (module cache or vendor/). First-party code is covered by checkSecrets.
+ * Literals are found with the parser, so comments and identifiers don't
+ * match, and each finding names the package it belongs to.
+ */
+
+// Provider key formats; the generic name=value patterns in secrets.ts are line based
+const TOKEN_PATTERNS: Array<{ pattern: RegExp; title: string }> = [
+  ...SECRET_PATTERNS.filter(p => p.severity === 'critical').map(p => ({ pattern: p.pattern, title: p.title })),
+  { pattern: /\b(?:gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b/, title: 'GitHub OAuth Token' },
+  { pattern: /\bgithub_pat_[A-Za-z0-9_]{60,}\b/, title: 'GitHub Fine-Grained Token' },
+  { pattern: /\bglpat-[A-Za-z0-9_-]{20}\b/, title: 'GitLab Personal Token' },
+  { pattern: /\bxox[abprs]-[A-Za-z0-9-]{10,}/, title: 'Slack Token' },
+  { pattern: /\bAIza[0-9A-Za-z_-]{35}\b/, title: 'Google API Key' },
+  { pattern: /\bnpm_[A-Za-z0-9]{36}\b/, title: 'npm Token' },
+  { pattern: /\bSG\.[A-Za-z0-9_-]{22}\.[A-Za-z0-9_-]{43}\b/, title: 'SendGrid API Key' },
+  { pattern: /\brk_live_[a-zA-Z0-9]{24,}/, title: 'Stripe Restricted Key' },
+];
+
+const CREDENTIAL_NAME = /(?:passw(?:or)?d|passwd|secret|token|api_?key|apikey|access_?key|private_?key|credential|auth_?key)/i;
+
+// Values that are obviously not a real credential
+const PLACEHOLDER = /^(?:x+|\*+|changeme|change_me|password|secret|token|example|test|dummy|fake|redacted|todo|none|null)$|[%{}<>$ ]|^https?:\/\//i;
+
+const MIN_LENGTH = 16;
+const MIN_ENTROPY = 3.5;
+
+/** Shannon entropy in bits per character */
+export function shannonEntropy(value: string): number {
+  const counts = new Map<string, number>();
+  for (const ch of value) counts.set(ch, (counts.get(ch) ?? 0) + 1);
+  let entropy = 0;
+  for (const n of counts.values()) {
+    const p = n / value.length;
+    entropy -= p * Math.log2(p);
+  }
+  return entropy;
+}
+
+function literalValue(node: Node): string {
+  return node.text.slice(1, -1);
+}
+
+// The identifier a literal is assigned or keyed to: const apiKey = "…", Config{Token: "…"}, m["password"] = "…"
+function assignedName(node: Node): string | null {
+  let self = node;
+  let n: Node | null = node.parent;
+  if (n?.type === 'expression_list' || n?.type === 'literal_element') {
+    self = n;
+    n = n.parent;
+  }
+  if (!n) return null;
+  if (n.type === 'const_spec' || n.type === 'var_spec') return n.childForFieldName('name')?.text ?? null;
+  if (n.type === 'keyed_element') {
+    const key = n.namedChildren[0];
+    return key && key.id !== self.id ? key.text.replace(/^"|"$/g, '') : null;
+  }
+  if (n.type === 'assignment_statement' || n.type === 'short_var_declaration') {
+    return n.childForFieldName('left')?.text ?? null;
+  }
+  return null;
+}
+
+/** Credential-looking literals in one Go file */
+export function findSecretLiterals(file: GoSourceFile): Array<{ line: number; title: string; severity: Severity; name: string | null }> {
+  const found: Array<{ line: number; title: string; severity: Severity; name: string | null }> = [];
+  walk(file.root, (node) => {
+    if (node.type !== 'interpreted_string_literal' && node.type !== 'raw_string_literal') return;
+    const value = literalValue(node);
+    const line = node.startPosition.row + 1;
+    const name = assignedName(node);
+
+    const token = TOKEN_PATTERNS.find(p => p.pattern.test(value));
+    if (token) {
+      found.push({ line, title: token.title, severity: 'critical', name });
+      return false;
+    }
+    if (name && CREDENTIAL_NAME.test(name) && value.length >= MIN_LENGTH && !PLACEHOLDER.test(value) &&
+      shannonEntropy(value) >= MIN_ENTROPY) {
+      found.push({ line, title: 'Hardcoded Credential', severity: 'medium', name });
+    }
+    return false;
+  });
+  return found;
+}
+
+// Fixtures and examples are expected to carry sample keys
+function isSampleCode(path: string): boolean {
+  return /(?:^|\/)(?:testdata|examples?|_examples|fixtures?|mocks?)\//.test(path);
+}
+
+export async function checkDependencySecrets(projectRoot: string, options: { signal?: AbortSignal } = {}): Promise<SecurityFinding[]> {
+  const findings: SecurityFinding[] = [];
+  const deps = await loadGoDependencies(projectRoot, { signal: options.signal });
+  for (const pkg of deps.packages) {
+    const mod = dependencyModule(pkg.dir);
+    if (!mod) continue;
+    for (const file of pkg.files) {
+      if (file.isTest || isSampleCode(file.file)) continue;
+      for (const hit of findSecretLiterals(file)) {
+        findings.push({
+          id: '',
+          // A key shipped in someone else's module is leaked already — it's theirs to revoke
+          severity: hit.severity === 'critical' ? 'high' : hit.severity,
+          vulnerabilityClass: 'secrets',
+          file: file.file,
+          line: hit.line,
+          symbol: hit.name ?? undefined,
+          package: pkg.importPath ?? pkg.dir,
+          module: `${mod.path}@${mod.version}`,
+          title: hit.title,
+          description: `Potential ${hit.title.toLowerCase()} in a string literal${hit.name ? ` assigned to ${hit.name}` : ''} in dependency ${mod.path}@${mod.version}.`,
+          attackScenario: 'Anyone who downloads the module can read the credential; if it is live, it grants access to whatever it was issued for.',
+          suggestedFix: 'Report the credential to the module maintainers so it can be revoked, and make sure your code does not rely on it.',
+        });
+      }
+    }
+  }
+  return findings;
+}
@@ -2,17 +2,18 @@ import { readFileSync } from 'fs';
 import { join } from 'path';
 import type { ParsedFile } from '../../parser/types.js';
 import type { SecurityFinding, Severity } from '../types.js';
+import { packageOf } from '../../graph/model.js';
 
 const SKIP_DIRS = ['node_modules/', 'dist/', '.git/', '.wrangler/', 'src/security/checks/'];
 const TEST_PATTERNS = ['test', 'spec', 'fixture', 'mock', '__tests__', '__mocks__', '.example', '.sample'];
 
-interface SecretPattern {
+export interface SecretPattern {
   pattern: RegExp;
   title: string;
   severity: Severity;
 }
 
-const SECRET_PATTERNS: SecretPattern[] = [
+export const SECRET_PATTERNS: SecretPattern[] = [
   // API Keys
   { pattern: /sk-[a-zA-Z0-9]{32,}/, title: 'OpenAI API Key', severity: 'critical' },
   { pattern: /AKIA[0-9A-Z]{16}/, title: 'AWS Access Key', severity: 'critical' },
@@ -75,6 +76,7 @@ export async function checkSecrets(
               vulnerabilityClass: 'secrets',
               file: file.filePath,
               line: i + 1,
+              package: packageOf(file.filePath),
               title: sp.title,
               description: `Potential ${sp.title.toLowerCase()} detected in source code.`,
               attackScenario: 'An attacker with source code access could extract credentials and use them to access external services or escalate privileges.',
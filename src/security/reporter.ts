@@ -57,6 +57,9 @@ export function formatTable(result: SecurityScanResult, elapsedMs: number): stri
     for (const finding of group) {
       lines.push(`  ${colorFn(`[${finding.id}]`)} ${finding.title}`);
       lines.push(`  File: ${finding.file}${finding.line ? `:${finding.line}` : ''}`);
+      if (finding.module) {
+        lines.push(`  Package: ${finding.package} ${chalk.dim(`(${finding.module})`)}`);
+      }
       lines.push(`  ${chalk.dim(finding.description)}`);
       lines.push(`  ${chalk.dim('Fix:')} ${finding.suggestedFix}`);
 
@@ -109,6 +112,7 @@ export function formatSARIF(result: SecurityScanResult, version: string): string
       ruleId: f.id,
       level,
       message: { text: `${f.title}: ${f.description}` },
+      properties: f.package ? { package: f.package, module: f.module } : undefined,
       locations: [
         {
           physicalLocation: {
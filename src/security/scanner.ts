@@ -6,6 +6,7 @@ import type { SecurityScanResult, SecurityScanOptions, Severity, VulnerabilityCl
 import { checkDependencies } from './checks/dependencies.js';
 import { checkInjection } from './checks/injection.js';
 import { checkSecrets } from './checks/secrets.js';
+import { checkDependencySecrets } from './checks/dependency-secrets.js';
 import { checkPathTraversal } from './checks/path-traversal.js';
 import { checkAuth } from './checks/auth.js';
 import { checkInputValidation } from './checks/input-validation.js';
@@ -25,7 +26,7 @@ export async function scanSecurity(
   const startTime = Date.now();
 
   // Parse project to get files with content access
-  const parsedFiles = await parseProject(projectRoot);
+  const parsedFiles = options.parsedFiles ?? await parseProject(projectRoot, { signal: options.signal });
 
   // Filter to target if specified
   const filteredFiles = options.target
@@ -48,6 +49,7 @@ export async function scanSecurity(
     checkCryptography(filteredFiles, projectRoot),
     hasFrontendFiles ? checkFrontend(filteredFiles, projectRoot) : Promise.resolve([]),
     checkArchitecture(filteredFiles, projectRoot, graph),
+    options.dependencySecrets && !options.target ? checkDependencySecrets(projectRoot, { signal: options.signal }) : Promise.resolve([]),
   ]);
 
   let findings = checkResults.flat();
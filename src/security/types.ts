@@ -1,3 +1,5 @@
+import type { ParsedFile } from '../parser/types.js';
+
 export type Severity = 'critical' | 'high' | 'medium' | 'low' | 'info';
 
 export type VulnerabilityClass =
@@ -21,6 +23,10 @@ export interface SecurityFinding {
   file: string;
   line?: number;
   symbol?: string;
+  /** Package the file belongs to: its directory for project files, the import path for Go dependencies */
+  package?: string;
+  /** module@version when the file is in a dependency */
+  module?: string;
   title: string;
   description: string;
   attackScenario: string;
@@ -58,4 +64,9 @@ export interface SecurityScanOptions {
   format?: 'table' | 'json' | 'sarif';
   failOn?: Severity;
   graphAware?: boolean;
+  /** Also scan Go dependency sources for hardcoded secrets */
+  dependencySecrets?: boolean;
+  /** The files the graph was built from, so they aren't parsed again (and a --since scope holds) */
+  parsedFiles?: ParsedFile[];
+  signal?: AbortSignal;
 }
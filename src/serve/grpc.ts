@@ -0,0 +1,375 @@
+import { createServer, constants, type Http2Server, type ServerHttp2Stream, type IncomingHttpHeaders } from 'http2';
+import { once } from 'events';
+import { encode, decode, type MessageSpec } from './protobuf.js';
+import type { Workspace } from './workspace.js';
+import { nodes, edges } from '../graph/model.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('grpc');
+
+/**
+ * gRPC server for `depwire serve --grpc`, implementing depwire.v1.Depwire
+ * (see depwire.proto) directly on node:http2.
+ *
+ * Unary and server-streaming RPCs only; identity encoding only.
+ */
+
+const SERVICE = 'depwire.v1.Depwire';
+
+// gRPC status codes
+const Status = {
+  OK: 0,
+  CANCELLED: 1,
+  INVALID_ARGUMENT: 3,
+  NOT_FOUND: 5,
+  UNIMPLEMENTED: 12,
+  INTERNAL: 13,
+} as const;
+
+class GrpcError extends Error {
+  readonly code: number;
+
+  constructor(code: number, message: string) {
+    super(message);
+    this.code = code;
+  }
+}
+
+// ── Message specs (field numbers must match depwire.proto) ──────────────────
+
+const Empty: MessageSpec = [];
+
+const SymbolMsg: MessageSpec = [
+  { name: 'id', no: 1, type: 'string' },
+  { name: 'name', no: 2, type: 'string' },
+  { name: 'kind', no: 3, type: 'string' },
+  { name: 'filePath', no: 4, type: 'string' },
+  { name: 'startLine', no: 5, type: 'int32' },
+  { name: 'endLine', no: 6, type: 'int32' },
+  { name: 'exported', no: 7, type: 'bool' },
+  { name: 'scope', no: 8, type: 'string' },
+  { name: 'stableId', no: 9, type: 'string' },
+];
+
+const EdgeMsg: MessageSpec = [
+  { name: 'source', no: 1, type: 'string' },
+  { name: 'target', no: 2, type: 'string' },
+  { name: 'kind', no: 3, type: 'string' },
+  { name: 'filePath', no: 4, type: 'string' },
+  { name: 'line', no: 5, type: 'int32' },
+];
+
+const FileEdgeMsg: MessageSpec = [
+  { name: 'source', no: 1, type: 'string' },
+  { name: 'target', no: 2, type: 'string' },
+];
+
+const SummaryMsg: MessageSpec = [
+  { name: 'projectRoot', no: 1, type: 'string' },
+  { name: 'fileCount', no: 2, type: 'int32' },
+  { name: 'symbolCount', no: 3, type: 'int32' },
+  { name: 'edgeCount', no: 4, type: 'int32' },
+  { name: 'version', no: 5, type: 'int32' },
+  { name: 'loadedAt', no: 6, type: 'string' },
+  {
+    name: 'mostConnectedFiles', no: 7, type: 'message', repeated: true, message: [
+      { name: 'filePath', no: 1, type: 'string' },
+      { name: 'connections', no: 2, type: 'int32' },
+    ],
+  },
+  { name: 'orphanFiles', no: 8, type: 'string', repeated: true },
+];
+
+const SearchRequest: MessageSpec = [
+  { name: 'query', no: 1, type: 'string' },
+  { name: 'limit', no: 2, type: 'int32' },
+];
+
+const SymbolRequest: MessageSpec = [{ name: 'symbol', no: 1, type: 'string' }];
+
+const ImpactMsg: MessageSpec = [
+  { name: 'symbol', no: 1, type: 'message', message: SymbolMsg },
+  { name: 'directDependents', no: 2, type: 'message', repeated: true, message: SymbolMsg },
+  { name: 'transitiveDependents', no: 3, type: 'message', repeated: true, message: SymbolMsg },
+  { name: 'affectedFiles', no: 4, type: 'string', repeated: true },
+];
+
+const PathRequest: MessageSpec = [
+  { name: 'from', no: 1, type: 'string' },
+  { name: 'to', no: 2, type: 'string' },
+];
+
+const PathMsg: MessageSpec = [
+  { name: 'found', no: 1, type: 'bool' },
+  { name: 'nodes', no: 2, type: 'string', repeated: true },
+];
+
+const MetricsMsg: MessageSpec = [
+  { name: 'overall', no: 1, type: 'int32' },
+  { name: 'grade', no: 2, type: 'string' },
+  {
+    name: 'dimensions', no: 3, type: 'message', repeated: true, message: [
+      { name: 'name', no: 1, type: 'string' },
+      { name: 'score', no: 2, type: 'int32' },
+      { name: 'grade', no: 3, type: 'string' },
+      { name: 'details', no: 4, type: 'string' },
+    ],
+  },
+  { name: 'summary', no: 4, type: 'string' },
+  { name: 'recommendations', no: 5, type: 'string', repeated: true },
+];
+
+const ListRequest: MessageSpec = [
+  { name: 'filePrefix', no: 1, type: 'string' },
+  { name: 'kind', no: 2, type: 'string' },
+];
+
+const DiffRequest: MessageSpec = [{ name: 'baseRef', no: 1, type: 'string' }];
+
+const ChangeMsg: MessageSpec = [
+  { name: 'change', no: 1, type: 'string' },
+  { name: 'node', no: 2, type: 'message', message: SymbolMsg },
+  { name: 'edge', no: 3, type: 'message', message: EdgeMsg },
+  { name: 'fileEdge', no: 4, type: 'message', message: FileEdgeMsg },
+];
+
+// ── Methods ─────────────────────────────────────────────────────────────────
+
+type Message = Record<string, any>;
+
+interface Method {
+  request: MessageSpec;
+  response: MessageSpec;
+  /** Unary methods return a message; streaming methods return an iterable of messages */
+  streaming: boolean;
+  handle(workspace: Workspace, request: Message, signal: AbortSignal): Promise<Message | Iterable<Message>> | Message | Iterable<Message>;
+}
+
+function requireField(request: Message, field: string): string {
+  if (!request[field]) {
+    throw new GrpcError(Status.INVALID_ARGUMENT, `${field} is required`);
+  }
+  return request[field];
+}
+
+function matchesList(request: Message, filePath: string | undefined, kind: string): boolean {
+  if (request.filePrefix && !(filePath ?? '').startsWith(request.filePrefix)) return false;
+  if (request.kind && kind !== request.kind) return false;
+  return true;
+}
+
+function toMetrics(workspace: Workspace): Message {
+  const report = workspace.metrics();
+  return {
+    overall: Math.round(report.overall),
+    grade: report.grade,
+    dimensions: report.dimensions.map(d => ({ name: d.name, score: Math.round(d.score), grade: d.grade, details: d.details })),
+    summary: report.summary,
+    recommendations: report.recommendations,
+  };
+}
+
+const METHODS: Record<string, Method> = {
+  GetSummary: {
+    request: Empty, response: SummaryMsg, streaming: false,
+    handle: (ws) => ws.summary(),
+  },
+  Reload: {
+    request: Empty, response: SummaryMsg, streaming: false,
+    handle: async (ws, _req, signal) => {
+      await ws.load(signal);
+      return ws.summary();
+    },
+  },
+  SearchSymbols: {
+    request: SearchRequest, response: SymbolMsg, streaming: true,
+    handle: (ws, req) => ws.search(requireField(req, 'query'), req.limit || 50),
+  },
+  GetSymbol: {
+    request: SymbolRequest, response: SymbolMsg, streaming: false,
+    handle: (ws, req) => ws.symbol(requireField(req, 'symbol')),
+  },
+  GetDependencies: {
+    request: SymbolRequest, response: SymbolMsg, streaming: true,
+    handle: (ws, req) => ws.dependencies(requireField(req, 'symbol')),
+  },
+  GetDependents: {
+    request: SymbolRequest, response: SymbolMsg, streaming: true,
+    handle: (ws, req) => ws.dependents(requireField(req, 'symbol')),
+  },
+  GetImpact: {
+    request: SymbolRequest, response: ImpactMsg, streaming: false,
+    handle: (ws, req) => ws.impact(requireField(req, 'symbol')),
+  },
+  FindPath: {
+    request: PathRequest, response: PathMsg, streaming: false,
+    handle: (ws, req) => {
+      const path = ws.path(requireField(req, 'from'), requireField(req, 'to'));
+      return { found: path !== null, nodes: path ?? [] };
+    },
+  },
+  GetMetrics: {
+    request: Empty, response: MetricsMsg, streaming: false,
+    handle: (ws) => toMetrics(ws),
+  },
+  ListNodes: {
+    request: ListRequest, response: SymbolMsg, streaming: true,
+    handle: function* (ws, req) {
+      for (const node of nodes(ws.graph)) {
+        if (matchesList(req, node.filePath, node.kind)) yield node;
+      }
+    },
+  },
+  ListEdges: {
+    request: ListRequest, response: EdgeMsg, streaming: true,
+    handle: function* (ws, req) {
+      for (const edge of edges(ws.graph)) {
+        if (matchesList(req, edge.filePath, edge.kind)) yield edge;
+      }
+    },
+  },
+  Diff: {
+    request: DiffRequest, response: ChangeMsg, streaming: true,
+    handle: async (ws, req, signal) => {
+      const delta = await ws.diff(requireField(req, 'baseRef'), signal);
+      return [
+        ...delta.addedFileEdges.map(fileEdge => ({ change: 'added', fileEdge })),
+        ...delta.removedFileEdges.map(fileEdge => ({ change: 'removed', fileEdge })),
+        ...delta.addedNodes.map(node => ({ change: 'added', node })),
+        ...delta.removedNodes.map(node => ({ change: 'removed', node })),
+        ...delta.addedEdges.map(edge => ({ change: 'added', edge })),
+        ...delta.removedEdges.map(edge => ({ change: 'removed', edge })),
+      ];
+    },
+  },
+};
+
+// ── Transport ───────────────────────────────────────────────────────────────
+
+// A request that doesn't decode is the client's fault, whatever the decoder threw
+function decodeRequest(spec: MessageSpec, body: Buffer): Message {
+  try {
+    return decode(spec, unframe(body));
+  } catch (err) {
+    if (err instanceof GrpcError) throw err;
+    throw new GrpcError(Status.INVALID_ARGUMENT, `Malformed request: ${err instanceof Error ? err.message : String(err)}`);
+  }
+}
+
+// Lookups that find nothing and bad git refs are the client's; anything else is a server fault
+function toGrpcError(err: unknown): GrpcError {
+  if (err instanceof GrpcError) return err;
+  const message = err instanceof Error ? err.message : String(err);
+  if (/not found/i.test(message)) return new GrpcError(Status.NOT_FOUND, message);
+  if (/^(Unknown|Invalid) git ref/.test(message)) return new GrpcError(Status.INVALID_ARGUMENT, message);
+  return new GrpcError(Status.INTERNAL, message);
+}
+
+/** Length-prefixed message framing: 1 byte compressed flag, 4 bytes big-endian length */
+function frame(message: Buffer): Buffer {
+  const header = Buffer.alloc(5);
+  header.writeUInt8(0, 0);
+  header.writeUInt32BE(message.length, 1);
+  return Buffer.concat([header, message]);
+}
+
+function unframe(body: Buffer): Buffer {
+  if (body.length === 0) return body; // empty request message
+  if (body.length < 5) throw new GrpcError(Status.INVALID_ARGUMENT, 'Malformed request frame');
+  if (body.readUInt8(0) !== 0) throw new GrpcError(Status.UNIMPLEMENTED, 'Compressed requests are not supported');
+  const length = body.readUInt32BE(1);
+  if (body.length < 5 + length) throw new GrpcError(Status.INVALID_ARGUMENT, 'Truncated request frame');
+  return body.subarray(5, 5 + length);
+}
+
+function sendTrailersOnly(stream: ServerHttp2Stream, error: GrpcError): void {
+  if (stream.destroyed) return;
+  stream.respond({
+    ':status': 200,
+    'content-type': 'application/grpc',
+    'grpc-status': String(error.code),
+    'grpc-message': encodeURIComponent(error.message),
+  }, { endStream: true });
+}
+
+async function handleStream(workspace: Workspace, stream: ServerHttp2Stream, headers: IncomingHttpHeaders): Promise<void> {
+  const path = String(headers[constants.HTTP2_HEADER_PATH] ?? '');
+  const contentType = String(headers[constants.HTTP2_HEADER_CONTENT_TYPE] ?? '');
+
+  if (!contentType.startsWith('application/grpc')) {
+    stream.respond({ ':status': 415 }, { endStream: true });
+    return;
+  }
+
+  const [, service, methodName] = path.split('/');
+  const method = service === SERVICE ? METHODS[methodName] : undefined;
+  if (!method) {
+    sendTrailersOnly(stream, new GrpcError(Status.UNIMPLEMENTED, `Unknown method ${path}`));
+    return;
+  }
+
+  const controller = new AbortController();
+  stream.on('close', () => controller.abort());
+
+  const chunks: Buffer[] = [];
+  for await (const chunk of stream) chunks.push(chunk as Buffer);
+
+  let responseStarted = false;
+  try {
+    const request = decodeRequest(method.request, Buffer.concat(chunks));
+    const result = await method.handle(workspace, request, controller.signal);
+
+    stream.respond({
+      ':status': 200,
+      'content-type': 'application/grpc',
+      'grpc-accept-encoding': 'identity',
+    }, { waitForTrailers: true });
+    responseStarted = true;
+    stream.once('wantTrailers', () => stream.sendTrailers({ 'grpc-status': String(Status.OK) }));
+
+    const messages = method.streaming ? result as Iterable<Message> : [result as Message];
+    for (const message of messages) {
+      if (controller.signal.aborted) return;
+      // Respect backpressure so large graph exports don't buffer in memory
+      if (!stream.write(frame(encode(method.response, message)))) {
+        await once(stream, 'drain');
+      }
+    }
+    stream.end();
+  } catch (err) {
+    const error = controller.signal.aborted ? new GrpcError(Status.CANCELLED, 'Cancelled') : toGrpcError(err);
+    if (!responseStarted) {
+      sendTrailersOnly(stream, error);
+    } else if (!stream.destroyed) {
+      stream.removeAllListeners('wantTrailers');
+      stream.once('wantTrailers', () => stream.sendTrailers({
+        'grpc-status': String(error.code),
+        'grpc-message': encodeURIComponent(error.message),
+      }));
+      stream.end();
+    }
+  }
+}
+
+export interface GrpcServerOptions {
+  host: string;
+  port: number;
+}
+
+export async function startGrpcServer(workspace: Workspace, options: GrpcServerOptions): Promise<Http2Server> {
+  const server = createServer();
+
+  server.on('stream', (stream, headers) => {
+    handleStream(workspace, stream, headers).catch((err) => {
+      log.error('Stream error', { error: err });
+      if (!stream.destroyed) stream.close(constants.NGHTTP2_INTERNAL_ERROR);
+    });
+  });
+  server.on('sessionError', (err) => {
+    log.error('Session error', { error: err });
+  });
+
+  server.listen(options.port, options.host);
+  await once(server, 'listening');
+  return server;
+}
@@ -0,0 +1,85 @@
+import type { Readable, Writable } from 'stream';
+
+/**
+ * JSON-RPC 2.0 over LSP-style framing ("Content-Length: N\r\n\r\n<json>").
+ */
+
+export interface JsonRpcMessage {
+  jsonrpc: '2.0';
+  id?: number | string | null;
+  method?: string;
+  params?: any;
+  result?: unknown;
+  error?: { code: number; message: string; data?: unknown };
+}
+
+export const ErrorCodes = {
+  ParseError: -32700,
+  InvalidRequest: -32600,
+  MethodNotFound: -32601,
+  InvalidParams: -32602,
+  InternalError: -32603,
+  ServerNotInitialized: -32002,
+  RequestCancelled: -32800,
+} as const;
+
+export class JsonRpcError extends Error {
+  readonly code: number;
+
+  constructor(code: number, message: string) {
+    super(message);
+    this.code = code;
+  }
+}
+
+const HEADER_SEPARATOR = Buffer.from('\r\n\r\n');
+const MAX_MESSAGE_SIZE = 64 * 1024 * 1024;
+
+/** Read framed messages from a stream, calling onMessage for each */
+export function readMessages(input: Readable, onMessage: (message: JsonRpcMessage | null) => void): void {
+  let buffer = Buffer.alloc(0);
+
+  input.on('data', (chunk: Buffer) => {
+    buffer = Buffer.concat([buffer, chunk]);
+
+    for (;;) {
+      const headerEnd = buffer.indexOf(HEADER_SEPARATOR);
+      if (headerEnd < 0) return;
+
+      const headers = buffer.subarray(0, headerEnd).toString('ascii');
+      const match = /Content-Length:\s*(\d+)/i.exec(headers);
+      if (!match) {
+        // Unrecoverable framing error — drop what we have
+        buffer = Buffer.alloc(0);
+        onMessage(null);
+        return;
+      }
+      const length = parseInt(match[1], 10);
+      if (length > MAX_MESSAGE_SIZE) {
+        buffer = Buffer.alloc(0);
+        onMessage(null);
+        return;
+      }
+
+      const bodyStart = headerEnd + HEADER_SEPARATOR.length;
+      if (buffer.length < bodyStart + length) return;
+
+      const body = buffer.subarray(bodyStart, bodyStart + length).toString('utf-8');
+      buffer = buffer.subarray(bodyStart + length);
+
+      let message: JsonRpcMessage | null;
+      try {
+        message = JSON.parse(body);
+      } catch {
+        message = null;
+      }
+      onMessage(message);
+    }
+  });
+}
+
+export function writeMessage(output: Writable, message: JsonRpcMessage): void {
+  const body = Buffer.from(JSON.stringify(message), 'utf-8');
+  output.write(`Content-Length: ${body.length}\r\n\r\n`);
+  output.write(body);
+}
@@ -0,0 +1,192 @@
+import { relative, resolve, isAbsolute } from 'path';
+import { fileURLToPath } from 'url';
+import type { Readable, Writable } from 'stream';
+import { readMessages, writeMessage, JsonRpcError, ErrorCodes, type JsonRpcMessage } from './jsonrpc.js';
+import { Workspace } from './workspace.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('lsp');
+
+/**
+ * Long-lived JSON-RPC server for editor plugins (`depwire lsp`).
+ *
+ * Speaks LSP framing and lifecycle (initialize / shutdown / exit) so any LSP
+ * client library can host it, plus depwire/* requests answered from the
+ * in-memory graph. File arguments accept project-relative paths, absolute
+ * paths or file:// URIs; symbol arguments accept node IDs or bare names.
+ */
+
+type Params = Record<string, any>;
+type Handler = (workspace: Workspace, params: Params, signal: AbortSignal) => unknown;
+
+function requireParam(params: Params, name: string): any {
+  if (params?.[name] === undefined || params[name] === '') {
+    throw new JsonRpcError(ErrorCodes.InvalidParams, `Missing parameter: ${name}`);
+  }
+  return params[name];
+}
+
+const HANDLERS: Record<string, Handler> = {
+  'depwire/summary': (ws) => ws.summary(),
+  'depwire/reload': async (ws, _params, signal) => {
+    await ws.load(signal);
+    return ws.summary();
+  },
+  'depwire/search': (ws, params) => ws.search(requireParam(params, 'query'), params.limit),
+  'depwire/symbol': (ws, params) => ws.symbol(requireParam(params, 'symbol')),
+  'depwire/symbolAt': (ws, params) =>
+    ws.symbolAt(toProjectPath(ws, requireParam(params, 'file')), toLine(params)),
+  'depwire/dependencies': (ws, params) => params.file
+    ? ws.fileDependencies(toProjectPath(ws, params.file))
+    : ws.dependencies(requireParam(params, 'symbol')),
+  'depwire/dependents': (ws, params) => params.file
+    ? ws.fileDependents(toProjectPath(ws, params.file))
+    : ws.dependents(requireParam(params, 'symbol')),
+  'depwire/impact': (ws, params) => ws.impact(requireParam(params, 'symbol')),
+  'depwire/path': (ws, params) => {
+    const from = requireParam(params, 'from');
+    const to = requireParam(params, 'to');
+    const path = ws.path(maybeProjectPath(ws, from), maybeProjectPath(ws, to));
+    return { found: path !== null, path: path ?? [] };
+  },
+  'depwire/metrics': (ws) => ws.metrics(),
+  'depwire/diff': (ws, params, signal) => ws.diff(requireParam(params, 'baseRef'), signal),
+};
+
+/** Convert a URI, absolute path or relative path to a project-relative path */
+function toProjectPath(ws: Workspace, file: string): string {
+  const path = file.startsWith('file://') ? fileURLToPath(file) : file;
+  return isAbsolute(path) ? relative(ws.projectRoot, path) : path;
+}
+
+// path endpoints may be symbols or files — only rewrite values that look like files
+function maybeProjectPath(ws: Workspace, value: string): string {
+  return value.startsWith('file://') || isAbsolute(value) ? toProjectPath(ws, value) : value;
+}
+
+// LSP positions are 0-based; accept either { line } (1-based) or { position: { line } }
+function toLine(params: Params): number {
+  if (params.position && typeof params.position.line === 'number') return params.position.line + 1;
+  return requireParam(params, 'line');
+}
+
+export interface LspServerOptions {
+  /** Project root; when omitted, taken from the client's initialize request */
+  projectRoot?: string;
+  input?: Readable;
+  output?: Writable;
+}
+
+export function startLspServer(options: LspServerOptions = {}): void {
+  const input = options.input ?? process.stdin;
+  const output = options.output ?? process.stdout;
+
+  let workspace: Workspace | null = null;
+  let ready: Promise<void> | null = null;
+  let shuttingDown = false;
+  const inFlight = new Map<number | string, AbortController>();
+
+  const respond = (id: JsonRpcMessage['id'], result: unknown) =>
+    writeMessage(output, { jsonrpc: '2.0', id, result: result ?? null });
+  const respondError = (id: JsonRpcMessage['id'], code: number, message: string) =>
+    writeMessage(output, { jsonrpc: '2.0', id: id ?? null, error: { code, message } });
+
+  const initialize = (params: Params) => {
+    const rootFromClient = params?.rootUri
+      ? fileURLToPath(params.rootUri)
+      : params?.rootPath;
+    const projectRoot = resolve(options.projectRoot ?? rootFromClient ?? process.cwd());
+    workspace = new Workspace(projectRoot);
+    ready = workspace.load().then(() => {
+      log.info(`Loaded ${projectRoot}: ${workspace!.graph.order} symbols`, { symbols: workspace!.graph.order });
+      if (params?.initializationOptions?.watch !== false) {
+        workspace!.watch();
+      }
+    });
+    // Surface load failures on the first request rather than crashing the server
+    ready.catch((err) => log.error('Failed to load workspace', { error: err }));
+
+    return {
+      capabilities: {
+        textDocumentSync: { openClose: false, save: true },
+        experimental: { depwire: { methods: Object.keys(HANDLERS) } },
+      },
+      serverInfo: { name: 'depwire' },
+    };
+  };
+
+  const handleRequest = async (message: JsonRpcMessage) => {
+    const { id, method, params } = message;
+
+    if (method === 'initialize') {
+      respond(id, initialize(params));
+      return;
+    }
+    if (method === 'shutdown') {
+      shuttingDown = true;
+      await workspace?.close();
+      respond(id, null);
+      return;
+    }
+    if (!workspace || !ready) {
+      respondError(id, ErrorCodes.ServerNotInitialized, 'Server not initialized');
+      return;
+    }
+
+    const handler = HANDLERS[method!];
+    if (!handler) {
+      respondError(id, ErrorCodes.MethodNotFound, `Unknown method: ${method}`);
+      return;
+    }
+
+    const controller = new AbortController();
+    inFlight.set(id!, controller);
+    try {
+      await ready;
+      const result = await handler(workspace, params ?? {}, controller.signal);
+      if (controller.signal.aborted) {
+        respondError(id, ErrorCodes.RequestCancelled, 'Request cancelled');
+      } else {
+        respond(id, result);
+      }
+    } catch (err) {
+      if (controller.signal.aborted) {
+        respondError(id, ErrorCodes.RequestCancelled, 'Request cancelled');
+      } else if (err instanceof JsonRpcError) {
+        respondError(id, err.code, err.message);
+      } else {
+        const text = err instanceof Error ? err.message : String(err);
+        respondError(id, /not found/i.test(text) ? ErrorCodes.InvalidParams : ErrorCodes.InternalError, text);
+      }
+    } finally {
+      inFlight.delete(id!);
+    }
+  };
+
+  const handleNotification = (message: JsonRpcMessage) => {
+    switch (message.method) {
+      case 'exit':
+        process.exit(shuttingDown ? 0 : 1);
+      case '$/cancelRequest':
+        inFlight.get(message.params?.id)?.abort();
+        return;
+      // Other notifications (initialized, didOpen, didChange, didSave, ...) need no
+      // action — the file watcher keeps the graph current
+    }
+  };
+
+  readMessages(input, (message) => {
+    if (!message || message.jsonrpc !== '2.0') {
+      respondError(null, message ? ErrorCodes.InvalidRequest : ErrorCodes.ParseError, 'Invalid message');
+      return;
+    }
+    if (message.method && message.id !== undefined) {
+      handleRequest(message).catch((err) => log.error('Unhandled error', { error: err }));
+    } else if (message.method) {
+      handleNotification(message);
+    }
+    // Responses to server-initiated requests are not used
+  });
+
+  input.on('end', () => process.exit(shuttingDown ? 0 : 1));
+}
@@ -0,0 +1,169 @@
+/**
+ * Minimal proto3 wire-format codec for the gRPC server.
+ *
+ * Supports what depwire.proto uses — string, bool, int32, nested messages,
+ * and repeated strings/messages. Messages are plain objects whose keys are the
+ * camelCase field names; unknown fields are skipped on decode.
+ */
+
+export type FieldType = 'string' | 'bool' | 'int32' | 'message';
+
+export interface FieldSpec {
+  name: string;
+  no: number;
+  type: FieldType;
+  repeated?: boolean;
+  message?: MessageSpec;
+}
+
+export type MessageSpec = FieldSpec[];
+
+const WIRE_VARINT = 0;
+const WIRE_I64 = 1;
+const WIRE_LEN = 2;
+const WIRE_I32 = 5;
+
+class Writer {
+  private chunks: Buffer[] = [];
+
+  varint(value: number | bigint): void {
+    let n = BigInt.asUintN(64, BigInt(value));
+    const bytes: number[] = [];
+    do {
+      let byte = Number(n & 0x7fn);
+      n >>= 7n;
+      if (n > 0n) byte |= 0x80;
+      bytes.push(byte);
+    } while (n > 0n);
+    this.chunks.push(Buffer.from(bytes));
+  }
+
+  tag(no: number, wireType: number): void {
+    this.varint((no << 3) | wireType);
+  }
+
+  bytes(data: Buffer): void {
+    this.varint(data.length);
+    this.chunks.push(data);
+  }
+
+  finish(): Buffer {
+    return Buffer.concat(this.chunks);
+  }
+}
+
+export function encode(spec: MessageSpec, value: Record<string, any>): Buffer {
+  const writer = new Writer();
+
+  for (const field of spec) {
+    const raw = value[field.name];
+    if (raw === undefined || raw === null) continue;
+    const values = field.repeated ? raw as unknown[] : [raw];
+
+    for (const v of values) {
+      switch (field.type) {
+        case 'string':
+          // proto3 omits default values for singular fields
+          if (!field.repeated && v === '') break;
+          writer.tag(field.no, WIRE_LEN);
+          writer.bytes(Buffer.from(String(v), 'utf-8'));
+          break;
+        case 'bool':
+          if (!v) break;
+          writer.tag(field.no, WIRE_VARINT);
+          writer.varint(1);
+          break;
+        case 'int32':
+          if (!field.repeated && v === 0) break;
+          writer.tag(field.no, WIRE_VARINT);
+          writer.varint(Math.trunc(Number(v)) | 0);
+          break;
+        case 'message':
+          writer.tag(field.no, WIRE_LEN);
+          writer.bytes(encode(field.message!, v as Record<string, any>));
+          break;
+      }
+    }
+  }
+
+  return writer.finish();
+}
+
+export function decode(spec: MessageSpec, data: Buffer): Record<string, any> {
+  const byNumber = new Map(spec.map(f => [f.no, f]));
+  const result: Record<string, any> = {};
+  for (const field of spec) {
+    if (field.repeated) result[field.name] = [];
+    else if (field.type === 'string') result[field.name] = '';
+    else if (field.type === 'bool') result[field.name] = false;
+    else if (field.type === 'int32') result[field.name] = 0;
+  }
+
+  let pos = 0;
+  const readVarint = (): bigint => {
+    let value = 0n;
+    let shift = 0n;
+    for (;;) {
+      if (pos >= data.length) throw new Error('Truncated varint');
+      const byte = data[pos++];
+      value |= BigInt(byte & 0x7f) << shift;
+      if ((byte & 0x80) === 0) return value;
+      shift += 7n;
+      if (shift > 63n) throw new Error('Varint too long');
+    }
+  };
+
+  while (pos < data.length) {
+    const key = Number(readVarint());
+    const no = key >>> 3;
+    const wireType = key & 0x7;
+    const field = byNumber.get(no);
+
+    let payload: Buffer | bigint;
+    switch (wireType) {
+      case WIRE_VARINT:
+        payload = readVarint();
+        break;
+      case WIRE_LEN: {
+        const length = Number(readVarint());
+        if (pos + length > data.length) throw new Error('Truncated length-delimited field');
+        payload = data.subarray(pos, pos + length);
+        pos += length;
+        break;
+      }
+      case WIRE_I64:
+        pos += 8;
+        continue;
+      case WIRE_I32:
+        pos += 4;
+        continue;
+      default:
+        throw new Error(`Unsupported wire type ${wireType}`);
+    }
+    if (!field) continue;
+
+    let value: unknown;
+    switch (field.type) {
+      case 'string':
+        value = (payload as Buffer).toString('utf-8');
+        break;
+      case 'bool':
+        value = payload !== 0n;
+        break;
+      case 'int32':
+        value = Number(BigInt.asIntN(32, payload as bigint));
+        break;
+      case 'message':
+        value = decode(field.message!, payload as Buffer);
+        break;
+    }
+
+    if (field.repeated) {
+      result[field.name].push(value);
+    } else {
+      result[field.name] = value;
+    }
+  }
+
+  return result;
+}
@@ -0,0 +1,200 @@
+import express, { type Request, type Response } from 'express';
+import { createHash } from 'crypto';
+import type { Server } from 'http';
+import { once } from 'events';
+import { nodes, edges, packageOf } from '../graph/model.js';
+import type { Workspace } from './workspace.js';
+
+/**
+ * Read-only REST API for dashboards (`depwire serve`).
+ *
+ * List endpoints use cursor pagination: responses carry `nextCursor`, pass it
+ * back as ?cursor= to get the next page. Cursors are tied to the graph version —
+ * if the graph changes mid-pagination (--watch) the server answers 409 and the
+ * client restarts from the first page.
+ *
+ * Successful responses carry an ETag derived from the graph version and the
+ * request; a matching If-None-Match is answered with 304 once the handler has
+ * succeeded, so errors are never cached or revalidated.
+ */
+
+const DEFAULT_LIMIT = 100;
+const MAX_LIMIT = 1000;
+
+class HttpError extends Error {
+  readonly status: number;
+
+  constructor(status: number, message: string) {
+    super(message);
+    this.status = status;
+  }
+}
+
+interface Page<T> {
+  items: T[];
+  total: number;
+  nextCursor: string | null;
+}
+
+function encodeCursor(version: number, offset: number): string {
+  return Buffer.from(JSON.stringify({ v: version, o: offset })).toString('base64url');
+}
+
+function decodeCursor(cursor: string): { v: number; o: number } {
+  try {
+    const parsed = JSON.parse(Buffer.from(cursor, 'base64url').toString('utf-8'));
+    if (Number.isInteger(parsed.v) && Number.isInteger(parsed.o) && parsed.o >= 0) return parsed;
+  } catch { /* fall through */ }
+  throw new HttpError(400, 'Invalid cursor');
+}
+
+function paginate<T>(items: T[], req: Request, version: number): Page<T> {
+  const limit = req.query.limit === undefined ? DEFAULT_LIMIT : parseInt(String(req.query.limit), 10);
+  if (!Number.isInteger(limit) || limit < 1 || limit > MAX_LIMIT) {
+    throw new HttpError(400, `limit must be between 1 and ${MAX_LIMIT}`);
+  }
+
+  let offset = 0;
+  if (typeof req.query.cursor === 'string' && req.query.cursor) {
+    const cursor = decodeCursor(req.query.cursor);
+    if (cursor.v !== version) {
+      throw new HttpError(409, 'The graph changed since this cursor was issued — restart from the first page');
+    }
+    offset = cursor.o;
+  }
+
+  const end = offset + limit;
+  return {
+    items: items.slice(offset, end),
+    total: items.length,
+    nextCursor: end < items.length ? encodeCursor(version, end) : null,
+  };
+}
+
+function queryString(req: Request, name: string): string | undefined {
+  const value = req.query[name];
+  return typeof value === 'string' && value !== '' ? value : undefined;
+}
+
+/** Matches an exact value, or a path prefix when the filter ends with '/' or '*' */
+function matchesFilter(value: string | undefined, filter: string | undefined): boolean {
+  if (filter === undefined) return true;
+  if (value === undefined) return false;
+  if (filter.endsWith('*')) return value.startsWith(filter.slice(0, -1));
+  if (filter.endsWith('/')) return value.startsWith(filter);
+  return value === filter;
+}
+
+export interface RestServerOptions {
+  host: string;
+  port: number;
+}
+
+export async function startRestServer(workspace: Workspace, options: RestServerOptions): Promise<Server> {
+  const app = express();
+  app.set('etag', false);
+  app.set('x-powered-by', false);
+
+  const route = (path: string, handler: (req: Request) => unknown) => {
+    app.get(path, async (req: Request, res: Response) => {
+      try {
+        // The version the answer was computed from; a reload mid-request only makes the tag older
+        const version = workspace.version;
+        const body = await handler(req);
+        // Conditional requests, for successful answers only: an error is never a 304
+        const tag = createHash('sha1').update(`${version}\0${req.originalUrl}`).digest('hex').slice(0, 20);
+        const etag = `"${version}-${tag}"`;
+        res.setHeader('ETag', etag);
+        res.setHeader('Cache-Control', 'no-cache');
+        if (req.headers['if-none-match'] === etag) {
+          res.status(304).end();
+          return;
+        }
+        res.json(body);
+      } catch (err) {
+        const status = err instanceof HttpError ? err.status : /not found/i.test(String(err)) ? 404 : 500;
+        res.status(status).json({ error: err instanceof Error ? err.message : String(err) });
+      }
+    });
+  };
+
+  route('/api/v1/summary', () => workspace.summary());
+
+  route('/api/v1/metrics', () => workspace.metrics());
+
+  // ?prefix=internal/ — packages under a directory
+  route('/api/v1/packages', (req) => {
+    const packageGraph = workspace.packageGraph();
+    const prefix = queryString(req, 'prefix');
+    const items = packageGraph.nodes()
+      .filter(pkg => !prefix || pkg.startsWith(prefix))
+      .sort()
+      .map(pkg => ({
+        name: pkg,
+        files: packageGraph.getNodeAttribute(pkg, 'files'),
+        symbols: packageGraph.getNodeAttribute(pkg, 'symbols'),
+        dependencies: packageGraph.outNeighbors(pkg).sort(),
+        dependents: packageGraph.inDegree(pkg),
+      }));
+    return paginate(items, req, workspace.version);
+  });
+
+  // ?file=src/api/*&kind=function&q=user
+  route('/api/v1/nodes', (req) => {
+    const file = queryString(req, 'file');
+    const kind = queryString(req, 'kind');
+    const q = queryString(req, 'q')?.toLowerCase();
+    const items = [...nodes(workspace.graph)]
+      .filter(n => matchesFilter(n.filePath, file) && matchesFilter(n.kind, kind))
+      .filter(n => !q || n.name.toLowerCase().includes(q))
+      .sort((a, b) => a.id.localeCompare(b.id));
+    return paginate(items, req, workspace.version);
+  });
+
+  // ?from=src/api/*&to=src/db/*&kind=calls&level=symbol|file|package
+  route('/api/v1/edges', (req) => {
+    const from = queryString(req, 'from');
+    const to = queryString(req, 'to');
+    const kind = queryString(req, 'kind');
+    const level = queryString(req, 'level') ?? 'symbol';
+
+    let items: Array<Record<string, unknown>>;
+    if (level === 'symbol') {
+      items = [...edges(workspace.graph)]
+        .filter(e => matchesFilter(e.kind, kind))
+        .filter(e => matchesFilter(e.source, from) || matchesFilter(workspace.graph.getNodeAttribute(e.source, 'filePath'), from))
+        .filter(e => matchesFilter(e.target, to) || matchesFilter(workspace.graph.getNodeAttribute(e.target, 'filePath'), to))
+        .sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target));
+    } else if (level === 'file' || level === 'package') {
+      if (kind) throw new HttpError(400, `kind is only supported for level=symbol`);
+      const graph = level === 'file' ? workspace.fileGraph() : workspace.packageGraph();
+      items = graph.edges()
+        .map(edge => ({ source: graph.source(edge), target: graph.target(edge), weight: graph.getEdgeAttribute(edge, 'weight') }))
+        .filter(e => matchesFilter(e.source, from) && matchesFilter(e.target, to))
+        .sort((a, b) => a.source.localeCompare(b.source) || a.target.localeCompare(b.target));
+    } else {
+      throw new HttpError(400, 'level must be symbol, file or package');
+    }
+    return paginate(items, req, workspace.version);
+  });
+
+  // ?rule=no-circular-dependencies&severity=error&package=src/api
+  route('/api/v1/findings', async (req) => {
+    const rule = queryString(req, 'rule');
+    const severity = queryString(req, 'severity');
+    const pkg = queryString(req, 'package');
+    const result = await workspace.findings();
+    const items = result.findings
+      .filter(f => matchesFilter(f.rule, rule) && matchesFilter(f.severity, severity))
+      .filter(f => !pkg || (f.file !== undefined && packageOf(f.file) === pkg));
+    return paginate(items, req, workspace.version);
+  });
+
+  app.use('/api', (_req, res) => {
+    res.status(404).json({ error: 'Not found' });
+  });
+
+  const server = app.listen(options.port, options.host);
+  await once(server, 'listening');
+  return server;
+}
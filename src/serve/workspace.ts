@@ -0,0 +1,210 @@
+import { DirectedGraph } from 'graphology';
+import type { FSWatcher } from 'chokidar';
+import { parseProject } from '../parser/index.js';
+import type { ParsedFile, SymbolNode } from '../parser/types.js';
+import { buildGraph } from '../graph/index.js';
+import { updateFileInGraph } from '../graph/updater.js';
+import { findSymbols, getDependencies, getDependents, getImpact, searchSymbols, getArchitectureSummary } from '../graph/queries.js';
+import { getNode, toFileGraph, toPackageGraph } from '../graph/model.js';
+import { shortestPath } from '../graph/algorithms.js';
+import { diffGraphs, type GraphDelta } from '../graph/diff.js';
+import { calculateHealthScore } from '../health/index.js';
+import type { HealthReport } from '../health/types.js';
+import { withWorktree } from '../temporal/git.js';
+import { runRules, builtinRules } from '../rules/index.js';
+import type { LintResult } from '../rules/index.js';
+import { watchProject } from '../watcher.js';
+import type { ProgressCallback } from '../utils/progress.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('workspace');
+
+type FileGraph = ReturnType<typeof toFileGraph>;
+type PackageGraph = ReturnType<typeof toPackageGraph>;
+
+/**
+ * A loaded project held in memory for long-lived servers (serve, lsp).
+ *
+ * Queries run against the already-built graph, so they answer in milliseconds.
+ * With watch enabled the graph is patched as files change; `version` increments
+ * on every change so callers can invalidate caches (and compute ETags).
+ */
+export class Workspace {
+  readonly projectRoot: string;
+  graph: DirectedGraph = new DirectedGraph();
+  parsedFiles: ParsedFile[] = [];
+  version = 0;
+  loadedAt: string | null = null;
+
+  private watcher: FSWatcher | null = null;
+  private healthCache: { version: number; report: HealthReport } | null = null;
+  private fileGraphCache: { version: number; graph: FileGraph } | null = null;
+  private packageGraphCache: { version: number; graph: PackageGraph } | null = null;
+  private lintCache: { version: number; result: Promise<LintResult> } | null = null;
+
+  constructor(projectRoot: string) {
+    this.projectRoot = projectRoot;
+  }
+
+  async load(signal?: AbortSignal, onProgress?: ProgressCallback): Promise<void> {
+    this.parsedFiles = await parseProject(this.projectRoot, { signal, onProgress });
+    this.graph = buildGraph(this.parsedFiles, this.projectRoot);
+    this.loadedAt = new Date().toISOString();
+    this.version++;
+  }
+
+  /** Keep the graph up to date as files change on disk */
+  watch(): void {
+    if (this.watcher) return;
+    const update = async (filePath: string) => {
+      try {
+        await updateFileInGraph(this.graph, this.projectRoot, filePath);
+        this.version++;
+      } catch (error) {
+        log.error(`Failed to update graph for ${filePath}`, { file: filePath, error });
+      }
+    };
+    this.watcher = watchProject(this.projectRoot, {
+      onFileChanged: update,
+      onFileAdded: update,
+      onFileDeleted: (filePath: string) => {
+        this.graph.filterNodes((_node, attrs) => attrs.filePath === filePath)
+          .forEach(node => this.graph.dropNode(node));
+        this.version++;
+      },
+    });
+  }
+
+  async close(): Promise<void> {
+    await this.watcher?.close();
+    this.watcher = null;
+  }
+
+  /**
+   * Resolve a symbol ID or name to a node ID.
+   * Names resolve to the most-depended-on match; unknown symbols throw.
+   */
+  resolveSymbol(query: string): string {
+    if (this.graph.hasNode(query)) return query;
+    const matches = findSymbols(this.graph, query);
+    if (matches.length === 0) {
+      throw new Error(`Symbol not found: ${query}`);
+    }
+    return matches[0].id;
+  }
+
+  symbol(query: string): SymbolNode {
+    return getNode(this.graph, this.resolveSymbol(query))!;
+  }
+
+  search(query: string, limit = 50): SymbolNode[] {
+    return searchSymbols(this.graph, query).slice(0, limit);
+  }
+
+  dependencies(query: string): SymbolNode[] {
+    return getDependencies(this.graph, this.resolveSymbol(query));
+  }
+
+  dependents(query: string): SymbolNode[] {
+    return getDependents(this.graph, this.resolveSymbol(query));
+  }
+
+  impact(query: string): ReturnType<typeof getImpact> & { symbol: SymbolNode } {
+    const id = this.resolveSymbol(query);
+    return { symbol: getNode(this.graph, id)!, ...getImpact(this.graph, id) };
+  }
+
+  /** File-level graph for the current version (cached until the graph changes) */
+  fileGraph(): FileGraph {
+    if (this.fileGraphCache?.version !== this.version) {
+      this.fileGraphCache = { version: this.version, graph: toFileGraph(this.graph) };
+    }
+    return this.fileGraphCache.graph;
+  }
+
+  /** Package-level graph for the current version (cached until the graph changes) */
+  packageGraph(): PackageGraph {
+    if (this.packageGraphCache?.version !== this.version) {
+      this.packageGraphCache = { version: this.version, graph: toPackageGraph(this.graph) };
+    }
+    return this.packageGraphCache.graph;
+  }
+
+  /** Files the given file depends on */
+  fileDependencies(filePath: string): string[] {
+    const fileGraph = this.fileGraph();
+    if (!fileGraph.hasNode(filePath)) throw new Error(`File not found: ${filePath}`);
+    return fileGraph.outNeighbors(filePath).sort();
+  }
+
+  /** Files that depend on the given file */
+  fileDependents(filePath: string): string[] {
+    const fileGraph = this.fileGraph();
+    if (!fileGraph.hasNode(filePath)) throw new Error(`File not found: ${filePath}`);
+    return fileGraph.inNeighbors(filePath).sort();
+  }
+
+  /** Innermost symbol in a file whose line range contains the given (1-based) line */
+  symbolAt(filePath: string, line: number): SymbolNode | null {
+    let best = null as string | null;
+    let bestSpan = Infinity;
+    this.graph.forEachNode((node, attrs) => {
+      if (attrs.filePath !== filePath || attrs.name === '__file__') return;
+      if (line < attrs.startLine || line > attrs.endLine) return;
+      const span = attrs.endLine - attrs.startLine;
+      if (span < bestSpan) {
+        best = node;
+        bestSpan = span;
+      }
+    });
+    return best ? getNode(this.graph, best) : null;
+  }
+
+  /**
+   * Shortest dependency path between two files or two symbols, following
+   * "depends on" edges. Returns null if `to` is not reachable from `from`.
+   */
+  path(from: string, to: string): string[] | null {
+    const fileGraph = this.fileGraph();
+    if (fileGraph.hasNode(from) && fileGraph.hasNode(to)) {
+      return shortestPath(fileGraph, from, to);
+    }
+    return shortestPath(this.graph, this.resolveSymbol(from), this.resolveSymbol(to));
+  }
+
+  summary(): ReturnType<typeof getArchitectureSummary> & { projectRoot: string; version: number; loadedAt: string | null } {
+    return {
+      projectRoot: this.projectRoot,
+      version: this.version,
+      loadedAt: this.loadedAt,
+      ...getArchitectureSummary(this.graph),
+    };
+  }
+
+  /** Health report for the current graph (cached until the graph changes) */
+  metrics(): HealthReport {
+    if (this.healthCache?.version !== this.version) {
+      this.healthCache = { version: this.version, report: calculateHealthScore(this.graph, this.projectRoot) };
+    }
+    return this.healthCache.report;
+  }
+
+  /** Built-in lint rule findings for the current graph (cached until the graph changes) */
+  findings(): Promise<LintResult> {
+    if (this.lintCache?.version !== this.version) {
+      this.lintCache = {
+        version: this.version,
+        result: runRules(builtinRules, this.graph, this.projectRoot, { parsedFiles: this.parsedFiles }),
+      };
+    }
+    return this.lintCache.result;
+  }
+
+  /** Diff the graph at a git ref (before) against the loaded graph (after) */
+  async diff(ref: string, signal?: AbortSignal): Promise<GraphDelta> {
+    const before = await withWorktree(this.projectRoot, ref, async (dir) =>
+      buildGraph(await parseProject(dir, { signal }), dir)
+    );
+    return diffGraphs(before, this.graph);
+  }
+}
@@ -187,4 +187,31 @@ describe('SimulationEngine', () => {
     // Node count stays same, but helperA is now under src/a-helpers.ts
     assert.strictEqual(result.simulatedGraph.nodeCount, graph.order);
   });
+
+  it('remove-edge action should drop every dependency between the two paths', () => {
+    const graph = createTestGraph();
+    const engine = new SimulationEngine(graph);
+
+    const result = engine.simulate({ type: 'remove-edge', target: 'src/c.ts', dependency: 'src/a.ts' });
+
+    assert.strictEqual(result.simulatedGraph.edgeCount, graph.size - 1);
+    assert.deepStrictEqual(result.diff.removedEdges.map((e) => e.source + '->' + e.target), ['src/c.ts::Baz->src/a.ts::Foo']);
+    assert.throws(
+      () => engine.simulate({ type: 'remove-edge', target: 'src/a.ts', dependency: 'src/c.ts' }),
+      /No dependency/
+    );
+  });
+
+  it('move-package action should move every file under the directory', () => {
+    const graph = createTestGraph();
+    const engine = new SimulationEngine(graph);
+
+    const result = engine.simulate({ type: 'move-package', target: 'src', destination: 'lib' });
+    const files = new Set(result.simulatedGraphInstance!.mapNodes((_node, attrs) => attrs.filePath));
+
+    assert.deepStrictEqual([...files].sort(), ['lib/a.ts', 'lib/b.ts', 'lib/c.ts']);
+    assert.strictEqual(result.simulatedGraph.edgeCount, graph.size);
+    // Every importer moved along with the package
+    assert.strictEqual(result.diff.brokenImports.length, 0);
+  });
 });
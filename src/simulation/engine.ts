@@ -9,6 +9,7 @@ import {
   calculateDepthScore,
 } from '../health/metrics.js';
 import type { HealthDimension } from '../health/types.js';
+import { stableNodeId } from '../graph/stable-id.js';
 
 // ── Types ──────────────────────────────────────────────────────────
 
@@ -17,7 +18,11 @@ export type SimulationAction =
   | { type: 'delete'; target: string }
   | { type: 'rename'; target: string; newName: string }
   | { type: 'split'; target: string; newFile: string; symbols: string[] }
-  | { type: 'merge'; target: string; source: string };
+  | { type: 'merge'; target: string; source: string }
+  /** Drop every dependency of target (file or directory) on dependency (file or directory) */
+  | { type: 'remove-edge'; target: string; dependency: string }
+  /** Move every file under the target directory into the destination directory */
+  | { type: 'move-package'; target: string; destination: string };
 
 export interface SimulationResult {
   action: SimulationAction;
@@ -84,6 +89,15 @@ function fileMatch(nodeFilePath: string, target: string): boolean {
   return a === b || a.endsWith('/' + b) || b.endsWith('/' + a);
 }
 
+function isUnder(nodeFilePath: string, dir: string): boolean {
+  return dir !== '' && dir !== '.' && normalizePath(nodeFilePath).startsWith(dir + '/');
+}
+
+// A file, or any file under a directory
+function pathMatch(nodeFilePath: string, target: string): boolean {
+  return fileMatch(nodeFilePath, target) || isUnder(nodeFilePath, normalizePath(target));
+}
+
 export class SimulationEngine {
   private readonly original: DirectedGraph;
 
@@ -92,27 +106,25 @@ export class SimulationEngine {
   }
 
   simulate(action: SimulationAction): SimulationResult {
+    return this.simulateAll([action]);
+  }
+
+  /**
+   * Apply several actions, in order, to one hypothetical graph.
+   * The result's action is the last one applied.
+   */
+  simulateAll(actions: SimulationAction[]): SimulationResult {
+    if (actions.length === 0) {
+      throw new Error('No simulation actions given');
+    }
     const clone = this.original.copy();
 
     const brokenImports: BrokenImport[] = [];
 
-    switch (action.type) {
-      case 'move':
-        this.applyMove(clone, action.target, action.destination, brokenImports);
-        break;
-      case 'delete':
-        this.applyDelete(clone, action.target, brokenImports);
-        break;
-      case 'rename':
-        this.applyRename(clone, action.target, action.newName, brokenImports);
-        break;
-      case 'split':
-        this.applySplit(clone, action.target, action.newFile, action.symbols, brokenImports);
-        break;
-      case 'merge':
-        this.applyMerge(clone, action.target, action.source, brokenImports);
-        break;
+    for (const step of actions) {
+      this.apply(clone, step, brokenImports);
     }
+    const action = actions[actions.length - 1];
 
     const diff = this.computeDiff(this.original, clone, brokenImports);
     const beforeHealth = this.computeHealthScore(this.original);
@@ -156,6 +168,75 @@ export class SimulationEngine {
 
   // ── Action implementations ─────────────────────────────────────
 
+  private apply(clone: DirectedGraph, action: SimulationAction, brokenImports: BrokenImport[]): void {
+    switch (action.type) {
+      case 'move':
+        this.applyMove(clone, action.target, action.destination, brokenImports);
+        break;
+      case 'delete':
+        this.applyDelete(clone, action.target, brokenImports);
+        break;
+      case 'rename':
+        this.applyRename(clone, action.target, action.newName, brokenImports);
+        break;
+      case 'split':
+        this.applySplit(clone, action.target, action.newFile, action.symbols, brokenImports);
+        break;
+      case 'merge':
+        this.applyMerge(clone, action.target, action.source, brokenImports);
+        break;
+      case 'remove-edge':
+        this.applyRemoveEdge(clone, action.target, action.dependency);
+        break;
+      case 'move-package':
+        this.applyMovePackage(clone, action.target, action.destination, brokenImports);
+        break;
+    }
+  }
+
+  private applyRemoveEdge(clone: DirectedGraph, target: string, dependency: string): void {
+    const dropped = clone.filterEdges((_edge, _attrs, source, edgeTarget) =>
+      pathMatch(clone.getNodeAttribute(source, 'filePath'), target) &&
+      pathMatch(clone.getNodeAttribute(edgeTarget, 'filePath'), dependency)
+    );
+    if (dropped.length === 0) {
+      throw new Error(`No dependency from ${target} on ${dependency}`);
+    }
+    for (const edge of dropped) {
+      clone.dropEdge(edge);
+    }
+  }
+
+  private applyMovePackage(
+    clone: DirectedGraph,
+    target: string,
+    destination: string,
+    brokenImports: BrokenImport[]
+  ): void {
+    const from = normalizePath(target);
+    const to = normalizePath(destination);
+    if (from === '' || from === '.') {
+      throw new Error('Cannot move the project root');
+    }
+    const files = new Set<string>();
+    clone.forEachNode((_node, attrs) => {
+      if (isUnder(attrs.filePath, from)) files.add(normalizePath(attrs.filePath));
+    });
+    if (files.size === 0) {
+      throw new Error(`No files under ${target}`);
+    }
+    const moved = new Set<string>();
+    const broken: BrokenImport[] = [];
+    for (const file of files) {
+      const rest = file.slice(from.length + 1);
+      const newFile = to === '' || to === '.' ? rest : `${to}/${rest}`;
+      moved.add(newFile);
+      this.applyMove(clone, file, newFile, broken);
+    }
+    // Files of the package move together, so their imports of each other still resolve
+    brokenImports.push(...broken.filter((b) => !files.has(b.file) && !moved.has(b.file)));
+  }
+
   private applyMove(
     clone: DirectedGraph,
     target: string,
@@ -189,7 +270,7 @@ export class SimulationEngine {
 
       // Add new node
       if (!clone.hasNode(newId)) {
-        clone.addNode(newId, { ...attrs, filePath: normalizedDest });
+        clone.addNode(newId, { ...attrs, filePath: normalizedDest, stableId: stableNodeId({ id: newId, kind: attrs.kind, filePath: normalizedDest }) });
       }
 
       // Rewire edges
@@ -289,7 +370,7 @@ export class SimulationEngine {
 
       // Add new node
       if (!clone.hasNode(newId)) {
-        clone.addNode(newId, { ...attrs, filePath: normalizedNewFile });
+        clone.addNode(newId, { ...attrs, filePath: normalizedNewFile, stableId: stableNodeId({ id: newId, kind: attrs.kind, filePath: normalizedNewFile }) });
       }
 
       // Rewire edges
@@ -355,7 +436,7 @@ export class SimulationEngine {
       });
 
       if (!clone.hasNode(newId)) {
-        clone.addNode(newId, { ...attrs, filePath: normalizedTarget });
+        clone.addNode(newId, { ...attrs, filePath: normalizedTarget, stableId: stableNodeId({ id: newId, kind: attrs.kind, filePath: normalizedTarget }) });
       }
 
       // Rewire edges
@@ -0,0 +1,85 @@
+import { escapeXml } from '../export/svg.js';
+
+/**
+ * Markdown to HTML for the plain-HTML site (and Confluence's storage
+ * format): only what depwire's own pages are written with — headings,
+ * paragraphs, lists, tables, blockquotes, fenced code (mermaid fences
+ * become <pre class="mermaid"> for mermaid.js), images on a line of their
+ * own, raw HTML lines, and inline code, bold and links.
+ */
+
+export interface MarkdownHtmlOptions {
+  /** Markup for an image line; the default is <img> */
+  image?: (alt: string, src: string) => string;
+}
+
+export function inlineHtml(text: string): string {
+  // Code spans first, so nothing inside them is taken for markup
+  return text.split(/(`[^`]*`)/).map(part => {
+    if (/^`[^`]*`$/.test(part)) return `<code>${escapeXml(part.slice(1, -1))}</code>`;
+    return escapeXml(part)
+      .replace(/\*\*([^*]+)\*\*/g, '<strong>$1</strong>')
+      .replace(/\[([^\]]*)\]\(([^)\s]+)\)/g, '<a href="$2">$1</a>');
+  }).join('')
+    // Links whose text is a code span were split apart above
+    .replace(/\[(<code>[^<]*<\/code>)\]\(([^)\s]+)\)/g, '<a href="$2">$1</a>');
+}
+
+function tableCells(row: string): string[] {
+  return row.trim().replace(/^\||\|$/g, '').split(/(?<!\\)\|/).map(c => c.trim().replace(/\\\|/g, '|'));
+}
+
+export function markdownToHtml(markdown: string, options: MarkdownHtmlOptions = {}): string {
+  const image = options.image ?? ((alt, src) => `<p><img src="${escapeXml(src)}" alt="${escapeXml(alt)}" /></p>`);
+  const out: string[] = [];
+  const lines = markdown.split('\n');
+  let i = 0;
+  while (i < lines.length) {
+    const line = lines[i];
+    if (line.trim() === '') {
+      i++;
+    } else if (line.startsWith('```')) {
+      const lang = line.slice(3).trim();
+      const body: string[] = [];
+      for (i++; i < lines.length && !lines[i].startsWith('```'); i++) body.push(lines[i]);
+      i++;
+      out.push(lang === 'mermaid' ? `<pre class="mermaid">${escapeXml(body.join('\n'))}</pre>` : `<pre><code>${escapeXml(body.join('\n'))}</code></pre>`);
+    } else if (/^!\[[^\]]*\]\([^)\s]+\)$/.test(line.trim())) {
+      const [, alt, src] = /^!\[([^\]]*)\]\(([^)\s]+)\)$/.exec(line.trim())!;
+      out.push(image(alt, src));
+      i++;
+    } else if (/^#{1,6} /.test(line)) {
+      const level = line.indexOf(' ');
+      out.push(`<h${level}>${inlineHtml(line.slice(level + 1))}</h${level}>`);
+      i++;
+    } else if (line.startsWith('|') && /^\|[\s:|-]+\|$/.test(lines[i + 1]?.trim() ?? '')) {
+      const align = tableCells(lines[i + 1]).map(c => c.endsWith(':') ? (c.startsWith(':') ? 'center' : 'right') : 'left');
+      const cells = (row: string, tag: string) => tableCells(row).map((c, n) => `<${tag}${align[n] !== 'left' ? ` style="text-align: ${align[n]}"` : ''}>${inlineHtml(c)}</${tag}>`).join('');
+      out.push('<table>', `<thead><tr>${cells(line, 'th')}</tr></thead>`, '<tbody>');
+      for (i += 2; i < lines.length && lines[i].startsWith('|'); i++) out.push(`<tr>${cells(lines[i], 'td')}</tr>`);
+      out.push('</tbody>', '</table>');
+    } else if (/^(- |\d+\. )/.test(line)) {
+      const ordered = !line.startsWith('- ');
+      out.push(ordered ? '<ol>' : '<ul>');
+      for (; i < lines.length && /^(- |\d+\. |\s+)/.test(lines[i]) && lines[i].trim() !== ''; i++) {
+        const item = lines[i];
+        // Indented lines continue the item before
+        if (/^\s/.test(item)) out[out.length - 1] = out[out.length - 1].replace(/<\/li>$/, ` ${inlineHtml(item.trim().replace(/^- /, ''))}</li>`);
+        else out.push(`<li>${inlineHtml(item.replace(/^(- |\d+\. )/, ''))}</li>`);
+      }
+      out.push(ordered ? '</ol>' : '</ul>');
+    } else if (line.startsWith('>')) {
+      const quote: string[] = [];
+      for (; i < lines.length && lines[i].startsWith('>'); i++) quote.push(lines[i].replace(/^>\s?/, ''));
+      out.push(`<blockquote><p>${inlineHtml(quote.join(' '))}</p></blockquote>`);
+    } else if (line.startsWith('<')) {
+      out.push(line);
+      i++;
+    } else {
+      const paragraph: string[] = [line];
+      for (i++; i < lines.length && lines[i].trim() !== '' && !/^(#{1,6} |```|\||- |\d+\. |>|<|!\[)/.test(lines[i]); i++) paragraph.push(lines[i]);
+      out.push(`<p>${inlineHtml(paragraph.join(' '))}</p>`);
+    }
+  }
+  return out.join('\n');
+}
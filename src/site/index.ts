@@ -0,0 +1,206 @@
+import { existsSync, mkdirSync, readdirSync, readFileSync, rmSync, writeFileSync } from 'fs';
+import { dirname, join, posix } from 'path';
+import { escapeXml } from '../export/svg.js';
+import { markdownToHtml } from './html.js';
+import { buildSitePages, relativeLinks, type SiteInput, type SitePage } from './pages.js';
+
+export { buildSitePages, markdownToHtml };
+export type { SiteInput, SitePage };
+
+/**
+ * A static documentation site from the architecture report, lint findings
+ * and the arc diagram: for MkDocs (mkdocs.yml and docs/, Material theme for
+ * the mermaid diagrams), for Hugo (hugo.toml, content/ and minimal layouts
+ * so no theme is needed) or as plain HTML to publish as is. A manifest of
+ * the files written lets regenerating the site remove pages of packages
+ * that are gone without touching anything else in the directory, such as
+ * a CNAME file.
+ */
+
+export type SiteFormat = 'html' | 'mkdocs' | 'hugo';
+
+export const SITE_FORMATS: SiteFormat[] = ['html', 'mkdocs', 'hugo'];
+
+export interface SiteFile {
+  /** Relative to the output directory */
+  path: string;
+  content: string;
+}
+
+const MANIFEST = '.depwire-site.json';
+
+/** The interactive graph, as a page of its own next to the generated ones */
+const GRAPH_ASSET = 'assets/arc-diagram.html';
+
+const MERMAID_SCRIPT = '<script type="module">import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@11/dist/mermaid.esm.min.mjs"; mermaid.initialize({ startOnLoad: true });</script>';
+
+const STYLE = `body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif; color: #1a1a2e; line-height: 1.5; }
+nav { padding: 12px 24px; border-bottom: 1px solid #e0e0ea; background: #f7f7fb; }
+nav a { margin-right: 16px; color: #1a1a2e; text-decoration: none; font-weight: 500; }
+nav .site { font-weight: 700; }
+main { max-width: 1100px; padding: 8px 24px 48px; }
+a { color: #2b6cb0; }
+code { font-size: 90%; background: #f0f0f5; padding: 1px 4px; border-radius: 3px; }
+table { border-collapse: collapse; margin: 12px 0; font-size: 14px; }
+th, td { border: 1px solid #e0e0ea; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f7f7fb; }
+pre.mermaid { background: none; }`;
+
+// Top-level pages in navigation order; package pages are reached from Packages
+const NAV: Array<[string, string]> = [
+  ['index', 'Overview'], ['packages', 'Packages'], ['graph', 'Graph'], ['metrics', 'Metrics'], ['findings', 'Findings'], ['dependencies', 'Dependencies'],
+];
+
+/** The files of the site in a format; graphHtml is the self-contained arc diagram */
+export function siteFiles(input: Omit<SiteInput, 'graphAsset'>, format: SiteFormat, graphHtml: string): SiteFile[] {
+  if (!SITE_FORMATS.includes(format)) throw new Error(`Unknown site format "${format}" (expected ${SITE_FORMATS.join(', ')})`);
+  const title = `${input.report.project} architecture`;
+  const pages = buildSitePages({ ...input, graphAsset: GRAPH_ASSET }, relativeLinks(format === 'mkdocs' ? '.md' : '.html'));
+  if (format === 'mkdocs') return mkdocsFiles(title, pages, graphHtml);
+  if (format === 'hugo') return hugoFiles(title, pages, graphHtml);
+  return htmlFiles(title, pages, graphHtml);
+}
+
+function htmlFiles(title: string, pages: SitePage[], graphHtml: string): SiteFile[] {
+  const link = relativeLinks('.html');
+  const files = pages.map((page): SiteFile => {
+    const body = markdownToHtml(page.markdown);
+    const nav = [`<a class="site" href="${link(page.slug, 'index')}">${escapeXml(title)}</a>`, ...NAV.slice(1).map(([slug, name]) => `<a href="${link(page.slug, slug)}">${name}</a>`)];
+    return {
+      path: `${page.slug}.html`,
+      content: `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>${escapeXml(page.slug === 'index' ? title : `${page.title} — ${title}`)}</title>
+  <style>
+${STYLE}
+  </style>
+</head>
+<body>
+  <nav>${nav.join('')}</nav>
+  <main>
+${body}
+  </main>${body.includes('class="mermaid"') ? `\n  ${MERMAID_SCRIPT}` : ''}
+</body>
+</html>
+`,
+    };
+  });
+  // GitHub Pages would otherwise run the files through Jekyll
+  return [...files, { path: GRAPH_ASSET, content: graphHtml }, { path: '.nojekyll', content: '' }];
+}
+
+function mkdocsFiles(title: string, pages: SitePage[], graphHtml: string): SiteFile[] {
+  const quote = (value: string) => JSON.stringify(value);
+  const packages = pages.filter(p => p.slug.startsWith('pkg/'));
+  const nav = NAV.flatMap(([slug, name]) => slug === 'packages'
+    ? [`  - ${name}:`, `      - packages.md`, ...packages.map(p => `      - ${quote(p.title)}: ${p.slug}.md`)]
+    : [`  - ${name}: ${slug}.md`]);
+  const config = [
+    `site_name: ${quote(title)}`,
+    // Pages at graph.html rather than graph/, so relative links to the assets hold
+    'use_directory_urls: false',
+    'theme:',
+    '  name: material',
+    'nav:',
+    ...nav,
+    'markdown_extensions:',
+    '  - tables',
+    '  - pymdownx.superfences:',
+    '      custom_fences:',
+    '        - name: mermaid',
+    '          class: mermaid',
+    '          format: !!python/name:pymdownx.superfences.fence_code_format',
+    '',
+  ].join('\n');
+  return [
+    { path: 'mkdocs.yml', content: config },
+    ...pages.map(page => ({ path: `docs/${page.slug}.md`, content: page.markdown })),
+    { path: `docs/${GRAPH_ASSET}`, content: graphHtml },
+  ];
+}
+
+const HUGO_LAYOUTS: Record<string, string> = {
+  'layouts/_default/baseof.html': `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>{{ if .IsHome }}{{ .Site.Title }}{{ else }}{{ .Title }} — {{ .Site.Title }}{{ end }}</title>
+  <style>
+${STYLE}
+  </style>
+</head>
+<body>
+  <nav><a class="site" href="{{ "index.html" | relURL }}">{{ .Site.Title }}</a>{{ range .Site.Menus.main }}<a href="{{ .URL | relURL }}">{{ .Name }}</a>{{ end }}</nav>
+  <main>
+{{ block "main" . }}{{ end }}
+  </main>
+  {{ if .Store.Get "hasMermaid" }}${MERMAID_SCRIPT}{{ end }}
+</body>
+</html>
+`,
+  'layouts/_default/single.html': '{{ define "main" }}{{ .Content }}{{ end }}\n',
+  'layouts/index.html': '{{ define "main" }}{{ .Content }}{{ end }}\n',
+  'layouts/_default/_markup/render-codeblock-mermaid.html': '<pre class="mermaid">{{ .Inner | htmlEscape | safeHTML }}</pre>\n{{ .Page.Store.Set "hasMermaid" true }}\n',
+};
+
+function hugoFiles(title: string, pages: SitePage[], graphHtml: string): SiteFile[] {
+  const quote = (value: string) => JSON.stringify(value);
+  const config = [
+    `title = ${quote(title)}`,
+    "baseURL = '/'",
+    // Pages at graph.html rather than graph/, so the relative links between them hold
+    'uglyURLs = true',
+    'relativeURLs = true',
+    // Only the pages written here: no lists of the pkg/ section
+    "disableKinds = ['section', 'taxonomy', 'term', 'RSS', 'sitemap']",
+    '',
+    '[markup.goldmark.renderer]',
+    // The graph page embeds the arc diagram in an iframe
+    'unsafe = true',
+    '',
+    ...NAV.slice(1).flatMap(([slug, name], i) => ['[[menus.main]]', `name = ${quote(name)}`, `url = ${quote(`/${slug}.html`)}`, `weight = ${i + 1}`, '']),
+  ].join('\n');
+  // Hugo's home page is _index.md
+  const contentPath = (slug: string) => `content/${slug === 'index' ? '_index' : slug}.md`;
+  return [
+    { path: 'hugo.toml', content: config },
+    ...Object.entries(HUGO_LAYOUTS).map(([path, content]) => ({ path, content })),
+    ...pages.map(page => ({ path: contentPath(page.slug), content: `---\ntitle: ${quote(page.title)}\n---\n\n${page.markdown}` })),
+    { path: `static/${GRAPH_ASSET}`, content: graphHtml },
+  ];
+}
+
+/**
+ * Write the site into outDir. A directory that has content but no manifest
+ * isn't overwritten; files a previous run wrote and this one didn't are
+ * removed.
+ */
+export function writeSite(outDir: string, files: SiteFile[]): { written: number; removed: number } {
+  const manifestFile = join(outDir, MANIFEST);
+  let previous: string[] = [];
+  if (existsSync(manifestFile)) {
+    previous = (JSON.parse(readFileSync(manifestFile, 'utf-8')) as { files?: string[] }).files ?? [];
+  } else if (existsSync(outDir) && readdirSync(outDir).length > 0) {
+    throw new Error(`${outDir} is not empty and wasn't generated by depwire site; pick an empty or new directory`);
+  }
+
+  const current = new Set(files.map(f => f.path));
+  let removed = 0;
+  for (const path of previous) {
+    // The manifest only lists paths inside the site
+    if (current.has(path) || posix.isAbsolute(path) || path.split('/').includes('..')) continue;
+    rmSync(join(outDir, path), { force: true });
+    removed++;
+  }
+  for (const file of files) {
+    const target = join(outDir, file.path);
+    mkdirSync(dirname(target), { recursive: true });
+    writeFileSync(target, file.content);
+  }
+  writeFileSync(manifestFile, JSON.stringify({ files: [...current].sort() }, null, 2) + '\n');
+  return { written: files.length, removed };
+}
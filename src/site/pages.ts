@@ -0,0 +1,266 @@
+import { posix } from 'path';
+import { toMermaid } from '../export/mermaid.js';
+import { packageGraph, packageClusters, componentGraph, type ArchitectureReport, type ArchitecturePackage } from '../report/index.js';
+import type { LintResult, RuleFinding } from '../rules/types.js';
+
+/**
+ * The pages of a documentation site, as Markdown: an overview, a page per
+ * package (what it imports and what imports it, its files, external
+ * modules and findings), the interactive graph, metrics, findings and
+ * external dependencies. Links between pages go through a link function so
+ * the same pages serve MkDocs (links to .md files) and Hugo or plain HTML
+ * (links to .html files).
+ */
+
+export interface SitePage {
+  /** Path without extension: "index", "packages", "pkg/internal-api" */
+  slug: string;
+  title: string;
+  markdown: string;
+}
+
+export interface SiteInput {
+  report: ArchitectureReport;
+  /** Files of each package directory */
+  files: Map<string, string[]>;
+  /** First sentence of each package directory's doc comment */
+  synopses: Map<string, string>;
+  /** null when findings were skipped */
+  lint: LintResult | null;
+  /** Where the interactive graph page is, relative to the site root */
+  graphAsset: string;
+}
+
+/** Relative link from one page to a page or asset of the site */
+export type SiteLink = (from: string, to: string) => string;
+
+/** Packages listed on a page before "and N more" */
+const MAX_LISTED = 50;
+
+/** A file-name-safe slug per package directory, unique within the site */
+export function packageSlugs(packages: ArchitecturePackage[]): Map<string, string> {
+  const slugs = new Map<string, string>();
+  const used = new Set<string>();
+  for (const p of packages) {
+    const base = p.package === '.' ? 'root' : p.package.replace(/[^A-Za-z0-9._-]+/g, '-').replace(/^[.-]+/, '') || 'package';
+    let slug = base;
+    for (let n = 2; used.has(slug); n++) slug = `${base}-${n}`;
+    used.add(slug);
+    slugs.set(p.package, slug);
+  }
+  return slugs;
+}
+
+/** Links between pages by relative path, with the extension pages have on the built site */
+export function relativeLinks(extension: string): SiteLink {
+  return (from, to) => {
+    const target = posix.extname(to) ? to : `${to}${extension}`;
+    return posix.relative(posix.dirname(from), target) || posix.basename(target);
+  };
+}
+
+const code = (value: string) => `\`${value}\``;
+const cell = (value: string) => value.replace(/\|/g, '\\|').replace(/\s+/g, ' ');
+const fence = (chart: string) => ['```mermaid', chart.trimEnd(), '```', ''];
+
+export function buildSitePages(input: SiteInput, link: SiteLink): SitePage[] {
+  const { report } = input;
+  const slugs = packageSlugs(report.packages);
+  const byLabel = new Map(report.packages.map(p => [p.label, p]));
+  const packagePage = (p: ArchitecturePackage) => `pkg/${slugs.get(p.package)}`;
+  const packageLink = (from: string, label: string) => {
+    const p = byLabel.get(label);
+    return p ? `[${code(label)}](${link(from, packagePage(p))})` : code(label);
+  };
+  const findingsOf = (p: ArchitecturePackage) => (input.lint?.findings ?? []).filter(f => f.file && posix.dirname(f.file) === p.package);
+
+  return [
+    overviewPage(input, link, packageLink),
+    packagesPage(input, link, packagePage),
+    ...report.packages.map(p => packageDetailPage(input, p, packagePage(p), link, packageLink, findingsOf(p))),
+    graphPage(input, link),
+    metricsPage(input, packageLink),
+    findingsPage(input),
+    dependenciesPage(input, packageLink),
+  ];
+}
+
+function overviewPage(input: SiteInput, link: SiteLink, packageLink: (from: string, label: string) => string): SitePage {
+  const { report } = input;
+  const { metrics } = report;
+  const slug = 'index';
+  const lines: string[] = [`# ${report.project}`, ''];
+  const languages = Object.entries(report.languages).sort((a, b) => b[1] - a[1] || a[0].localeCompare(b[0]));
+  lines.push(`- **${metrics.packages}** packages in **${report.components.length}** components, ${metrics.files} files, ${metrics.symbols} symbols`);
+  lines.push(`- Languages: ${languages.map(([language, count]) => `${language} (${count} files)`).join(', ')}`);
+  lines.push(`- Health: **${report.health.score}/100 (${report.health.grade})** — see [Metrics](${link(slug, 'metrics')})`);
+  if (input.lint) lines.push(`- Findings: ${input.lint.summary.error} errors, ${input.lint.summary.warning} warnings — see [Findings](${link(slug, 'findings')})`);
+  lines.push(`- External modules: ${metrics.directModules} direct, ${metrics.indirectModules} indirect — see [Dependencies](${link(slug, 'dependencies')})`, '');
+  lines.push(`Browse the [packages](${link(slug, 'packages')}) or explore the [interactive graph](${link(slug, 'graph')}).`, '');
+
+  lines.push('## Components', '');
+  lines.push('Components are the communities of the package graph: packages that reference each other more than they reference the rest.', '');
+  if (report.components.length > 1) lines.push(...fence(toMermaid(componentGraph(report))));
+  lines.push('| Component | Packages |', '| --- | --- |');
+  for (const c of report.components) {
+    const members = report.packages.filter(p => p.component === c.id).map(p => p.label);
+    const shown = members.slice(0, 10).map(label => packageLink(slug, label)).join(', ');
+    lines.push(`| ${code(c.label)} | ${shown}${members.length > 10 ? ` and ${members.length - 10} more` : ''} |`);
+  }
+  lines.push('');
+  if (report.cycles.length > 0) {
+    lines.push('## Dependency cycles', '');
+    for (const cycle of report.cycles) lines.push(`- ${cycle.map(label => packageLink(slug, label)).join(' → ')} → ${code(cycle[0])}`);
+    lines.push('');
+  }
+  return { slug, title: 'Overview', markdown: lines.join('\n') };
+}
+
+function packagesPage(input: SiteInput, link: SiteLink, packagePage: (p: ArchitecturePackage) => string): SitePage {
+  const slug = 'packages';
+  const lines: string[] = ['# Packages', ''];
+  lines.push('| Package | | Files | Lines | Fan-in | Fan-out |', '| --- | --- | ---: | ---: | ---: | ---: |');
+  for (const p of input.report.packages) {
+    const synopsis = input.synopses.get(p.package);
+    lines.push(`| [${code(p.label)}](${link(slug, packagePage(p))}) | ${synopsis ? cell(synopsis) : ''} | ${p.files} | ${p.loc} | ${p.fanIn} | ${p.fanOut} |`);
+  }
+  lines.push('');
+  return { slug, title: 'Packages', markdown: lines.join('\n') };
+}
+
+function packageDetailPage(
+  input: SiteInput,
+  p: ArchitecturePackage,
+  slug: string,
+  link: SiteLink,
+  packageLink: (from: string, label: string) => string,
+  findings: RuleFinding[]
+): SitePage {
+  const { report } = input;
+  const lines: string[] = [`# ${p.label}`, ''];
+  const synopsis = input.synopses.get(p.package);
+  if (synopsis) lines.push(synopsis, '');
+  const component = report.components.find(c => c.id === p.component);
+  lines.push(`- Directory: ${code(p.package)}`);
+  if (component) lines.push(`- Component: ${code(component.label)}`);
+  lines.push(`- ${p.files} files, ${p.loc} lines, ${p.symbols} symbols`);
+  lines.push(`- Fan-in ${p.fanIn}, fan-out ${p.fanOut}, instability ${p.instability === null ? '—' : p.instability.toFixed(2)}`, '');
+
+  const imports = report.dependencies.filter(d => d.from === p.label && d.to !== p.label);
+  const importedBy = report.dependencies.filter(d => d.to === p.label && d.from !== p.label);
+  const neighborhood = [p.label, ...imports.map(d => d.to), ...importedBy.map(d => d.from)];
+  if (neighborhood.length > 1 && neighborhood.length <= MAX_LISTED) lines.push(...fence(toMermaid(packageGraph(report, neighborhood))));
+
+  const listed = (deps: typeof imports, side: 'from' | 'to') => {
+    for (const d of deps.slice(0, MAX_LISTED)) lines.push(`- ${packageLink(slug, d[side])} (${d.weight} references)`);
+    if (deps.length > MAX_LISTED) lines.push(`- … and ${deps.length - MAX_LISTED} more`);
+    lines.push('');
+  };
+  lines.push('## Imports', '');
+  if (imports.length === 0) lines.push('No workspace packages.', '');
+  else listed(imports, 'to');
+  lines.push('## Imported by', '');
+  if (importedBy.length === 0) lines.push('No workspace packages.', '');
+  else listed(importedBy, 'from');
+
+  const modules = report.modules.filter(m => m.importers.includes(p.label));
+  if (modules.length > 0) {
+    lines.push('## External modules', '');
+    for (const m of modules) lines.push(`- ${code(m.path)} ${m.version}`);
+    lines.push('');
+  }
+
+  lines.push('## Files', '');
+  for (const file of input.files.get(p.package) ?? []) lines.push(`- ${code(file)}`);
+  lines.push('');
+
+  if (findings.length > 0) {
+    lines.push('## Findings', '');
+    lines.push(...findingRows(findings), '');
+  }
+  return { slug, title: p.label, markdown: lines.join('\n') };
+}
+
+function graphPage(input: SiteInput, link: SiteLink): SitePage {
+  const slug = 'graph';
+  const lines: string[] = ['# Graph', ''];
+  lines.push(`The file dependency graph as an arc diagram: hover a file to highlight what it depends on and what depends on it. [Open it on its own](${link(slug, input.graphAsset)}).`, '');
+  lines.push(`<iframe src="${link(slug, input.graphAsset)}" title="Dependency graph" style="width: 100%; height: 720px; border: 1px solid #e0e0ea;"></iframe>`, '');
+  const { report } = input;
+  lines.push('## Package dependencies', '');
+  if (report.packages.length <= 40) {
+    lines.push(...fence(toMermaid(packageGraph(report, report.packages.map(p => p.label)), { clusters: packageClusters(report) })));
+  } else {
+    lines.push(`${report.packages.length} packages are too many to draw; the [overview](${link(slug, 'index')}) has the component diagram.`, '');
+  }
+  return { slug, title: 'Graph', markdown: lines.join('\n') };
+}
+
+function metricsPage(input: SiteInput, packageLink: (from: string, label: string) => string): SitePage {
+  const slug = 'metrics';
+  const { report } = input;
+  const { metrics } = report;
+  const lines: string[] = ['# Metrics', ''];
+  lines.push('| Metric | Value |', '| --- | ---: |');
+  lines.push(`| Packages | ${metrics.packages} |`, `| Package dependencies | ${metrics.packageEdges} |`, `| Import depth | ${metrics.depth} |`);
+  lines.push(`| Package cycles | ${metrics.cycles} |`, `| Largest cycle | ${metrics.largestCycle} |`, `| Symbol edges | ${metrics.edges} |`, '');
+  lines.push(`## Health: ${report.health.score}/100 (${report.health.grade})`, '');
+  lines.push('| Dimension | Score | Grade | |', '| --- | ---: | :---: | --- |');
+  for (const d of report.health.dimensions) lines.push(`| ${d.name} | ${d.score} | ${d.grade} | ${cell(d.details)} |`);
+  lines.push('');
+  lines.push('## Packages', '');
+  lines.push('| Package | Files | Lines | Symbols | Fan-in | Fan-out | Instability |', '| --- | ---: | ---: | ---: | ---: | ---: | ---: |');
+  for (const p of report.packages) {
+    lines.push(`| ${packageLink(slug, p.label)} | ${p.files} | ${p.loc} | ${p.symbols} | ${p.fanIn} | ${p.fanOut} | ${p.instability === null ? '—' : p.instability.toFixed(2)} |`);
+  }
+  lines.push('');
+  if (report.longestChain.length > 1) {
+    lines.push(`Longest import chain (${report.longestChain.length - 1} steps): ${report.longestChain.map(label => packageLink(slug, label)).join(' → ')}`, '');
+  }
+  return { slug, title: 'Metrics', markdown: lines.join('\n') };
+}
+
+function findingRows(findings: RuleFinding[]): string[] {
+  const rows = ['| Severity | Rule | Where | Message |', '| --- | --- | --- | --- |'];
+  for (const f of findings) {
+    const where = f.file ? code(f.line ? `${f.file}:${f.line}` : f.file) : f.source && f.target ? `${code(f.source)} → ${code(f.target)}` : '—';
+    rows.push(`| ${f.severity} | ${code(f.rule)} | ${where} | ${cell(f.message)} |`);
+  }
+  return rows;
+}
+
+function findingsPage(input: SiteInput): SitePage {
+  const slug = 'findings';
+  const lines: string[] = ['# Findings', ''];
+  if (!input.lint) {
+    lines.push('Findings were not collected for this site.', '');
+  } else if (input.lint.findings.length === 0) {
+    lines.push(`No findings from ${input.lint.rules.length} rules.`, '');
+  } else {
+    const { summary } = input.lint;
+    lines.push(`${summary.error} errors, ${summary.warning} warnings and ${summary.info} notes from ${input.lint.rules.length} rules.`, '');
+    const order = { error: 0, warning: 1, info: 2 };
+    const sorted = [...input.lint.findings].sort((a, b) => order[a.severity] - order[b.severity] || a.rule.localeCompare(b.rule) || (a.file ?? '').localeCompare(b.file ?? ''));
+    lines.push(...findingRows(sorted), '');
+  }
+  return { slug, title: 'Findings', markdown: lines.join('\n') };
+}
+
+function dependenciesPage(input: SiteInput, packageLink: (from: string, label: string) => string): SitePage {
+  const slug = 'dependencies';
+  const { report } = input;
+  const lines: string[] = ['# External dependencies', ''];
+  if (report.modules.length === 0) {
+    lines.push('No external Go modules are required.', '');
+  } else {
+    lines.push('| Module | Version | | Imported by |', '| --- | --- | --- | --- |');
+    for (const m of report.modules) {
+      const importers = m.importers.length === 0 ? '—' : m.importers.length > 5
+        ? `${m.importers.slice(0, 5).map(label => packageLink(slug, label)).join(', ')} and ${m.importers.length - 5} more`
+        : m.importers.map(label => packageLink(slug, label)).join(', ');
+      lines.push(`| ${code(m.path)} | ${m.version} | ${m.indirect ? 'indirect' : 'direct'} | ${importers} |`);
+    }
+    lines.push('');
+  }
+  return { slug, title: 'Dependencies', markdown: lines.join('\n') };
+}
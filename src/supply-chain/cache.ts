@@ -0,0 +1,66 @@
+import { existsSync, mkdirSync, readFileSync, writeFileSync } from 'fs';
+import { dirname, join } from 'path';
+
+/**
+ * Results fetched from public services, kept in a JSON file in .depwire/
+ * with the time each was fetched. Entries are fresh for a day; offline any
+ * entry is used, and a stale one still stands in when a request fails.
+ */
+
+export const CACHE_TTL = 24 * 60 * 60 * 1000;
+
+interface CacheEntry<T> {
+  fetched: number;
+  value: T;
+}
+
+export class FetchCache<T> {
+  readonly file: string;
+  private entries: Record<string, CacheEntry<T>>;
+  private dirty = false;
+
+  constructor(projectRoot: string, name: string, private readonly ttl = CACHE_TTL) {
+    this.file = join(projectRoot, '.depwire', name);
+    this.entries = readEntries<T>(this.file);
+  }
+
+  has(key: string): boolean {
+    return key in this.entries;
+  }
+
+  /** The value if it's fresh, or offline if there is one at all; undefined when it has to be fetched */
+  fresh(key: string, offline = false): T | undefined {
+    const entry = this.entries[key];
+    return entry && (offline || Date.now() - entry.fetched < this.ttl) ? entry.value : undefined;
+  }
+
+  /** The value however old, to fall back on */
+  stale(key: string): T | undefined {
+    return this.entries[key]?.value;
+  }
+
+  set(key: string, value: T): void {
+    this.entries[key] = { fetched: Date.now(), value };
+    this.dirty = true;
+  }
+
+  /** Write the file when anything was fetched */
+  save(): void {
+    if (!this.dirty) return;
+    try {
+      mkdirSync(dirname(this.file), { recursive: true });
+      writeFileSync(this.file, JSON.stringify(this.entries, null, 2));
+      this.dirty = false;
+    } catch { /* read-only checkout — the cache is an optimization */ }
+  }
+}
+
+function readEntries<T>(file: string): Record<string, CacheEntry<T>> {
+  try {
+    const entries = existsSync(file) ? JSON.parse(readFileSync(file, 'utf-8')) as Record<string, Partial<CacheEntry<T>>> : {};
+    // Entries of an older layout are fetched again
+    return Object.fromEntries(Object.entries(entries).filter(([, e]) => typeof e?.fetched === 'number' && 'value' in e)) as Record<string, CacheEntry<T>>;
+  } catch {
+    return {};
+  }
+}
@@ -0,0 +1,207 @@
+import { minimatch } from 'minimatch';
+import { findGoModules } from '../golang/modules.js';
+import { runGo } from '../golang/toolchain.js';
+
+/**
+ * Dependency confusion for Go modules: an internal module path that also
+ * resolves on a public proxy can be served from there instead of from the
+ * organization's own source, and GOPRIVATE/GONOPROXY/GONOSUMDB gaps decide
+ * whether the go command will ask the public proxy and checksum database at
+ * all (leaking the name even when nothing is published).
+ */
+
+export const PUBLIC_PROXY = 'https://proxy.golang.org';
+
+// Proxies anyone can publish to (through the module's origin)
+const PUBLIC_PROXIES = ['proxy.golang.org', 'goproxy.io', 'goproxy.cn', 'mirrors.aliyun.com/goproxy', 'goproxy.dev'];
+
+export interface GoModuleEnv {
+  GOPROXY: string;
+  GOPRIVATE: string;
+  GONOPROXY: string;
+  GONOSUMDB: string;
+  GOSUMDB: string;
+  GOINSECURE: string;
+  GOFLAGS: string;
+  /** Where the values came from */
+  source: 'go env' | 'environment';
+}
+
+export type ConfusionRisk = 'critical' | 'high' | 'medium' | 'low';
+
+export interface ConfusionFinding {
+  module: string;
+  /** Where the internal path was found */
+  source: 'workspace' | 'require';
+  goModFile: string;
+  line: number | null;
+  /** Versions the public proxy serves; null when not checked */
+  publicVersions: string[] | null;
+  /** The go command would fetch it through a public proxy (not covered by GONOPROXY) */
+  viaPublicProxy: boolean;
+  /** The go command would look it up in the public checksum database (not covered by GONOSUMDB) */
+  viaPublicSumdb: boolean;
+  risk: ConfusionRisk;
+  message: string;
+}
+
+export interface ConfusionReport {
+  projectRoot: string;
+  prefixes: string[];
+  env: GoModuleEnv;
+  proxy: string;
+  /** False with --offline: public resolvability wasn't checked */
+  checkedProxy: boolean;
+  findings: ConfusionFinding[];
+  warnings: string[];
+}
+
+export interface ConfusionOptions {
+  /** Private module prefixes; defaults to the GOPRIVATE patterns */
+  prefixes?: string[];
+  /** Public proxy to query (default proxy.golang.org) */
+  proxy?: string;
+  /** Don't query the proxy, only check configuration */
+  offline?: boolean;
+  signal?: AbortSignal;
+}
+
+/** The module-fetch settings the go command would use, from `go env` or the process environment */
+export async function readGoModuleEnv(cwd: string, signal?: AbortSignal): Promise<GoModuleEnv> {
+  const keys = ['GOPROXY', 'GOPRIVATE', 'GONOPROXY', 'GONOSUMDB', 'GOSUMDB', 'GOINSECURE', 'GOFLAGS'] as const;
+  try {
+    const result = await runGo(['env', '-json', ...keys], { cwd, signal });
+    if (result.exitCode === 0) {
+      const values = JSON.parse(result.stdout) as Record<string, string>;
+      return { ...Object.fromEntries(keys.map(k => [k, values[k] ?? ''])), source: 'go env' } as GoModuleEnv;
+    }
+  } catch (err) {
+    if (signal?.aborted) throw err;
+  }
+  // Same defaults the go command applies
+  const env = process.env;
+  const goprivate = env.GOPRIVATE ?? '';
+  return {
+    GOPROXY: env.GOPROXY || `${PUBLIC_PROXY},direct`,
+    GOPRIVATE: goprivate,
+    GONOPROXY: env.GONOPROXY || goprivate,
+    GONOSUMDB: env.GONOSUMDB || goprivate,
+    GOSUMDB: env.GOSUMDB || 'sum.golang.org',
+    GOINSECURE: env.GOINSECURE ?? '',
+    GOFLAGS: env.GOFLAGS ?? '',
+    source: 'environment',
+  };
+}
+
+/**
+ * Whether a module path matches a GOPRIVATE-style list: comma-separated
+ * globs, each matched against the same number of leading path elements.
+ */
+export function matchesGoPrefixPatterns(patterns: string, path: string): boolean {
+  for (const pattern of patterns.split(',').map(p => p.trim()).filter(Boolean)) {
+    const elements = pattern.split('/').length;
+    const prefix = path.split('/').slice(0, elements).join('/');
+    if (prefix.split('/').length === elements && minimatch(prefix, pattern)) return true;
+  }
+  return false;
+}
+
+/** Module paths escape upper-case letters as !lower for proxy URLs */
+export function escapeModulePath(path: string): string {
+  return path.replace(/[A-Z]/g, c => `!${c.toLowerCase()}`);
+}
+
+/** Versions a GOPROXY-protocol server lists for a module, or null when it doesn't know it */
+export async function publicVersions(proxy: string, module: string, signal?: AbortSignal): Promise<string[] | null> {
+  const base = `${proxy.replace(/\/$/, '')}/${escapeModulePath(module)}/@v`;
+  const list = await fetch(`${base}/list`, { signal });
+  if (list.status === 404 || list.status === 410) return null;
+  if (!list.ok) throw new Error(`${proxy} returned ${list.status} for ${module}`);
+  const versions = (await list.text()).split('\n').map(v => v.trim()).filter(Boolean);
+  if (versions.length > 0) return versions;
+  // No tagged versions, but a pseudo-version may still resolve
+  const latest = await fetch(`${proxy.replace(/\/$/, '')}/${escapeModulePath(module)}/@latest`, { signal });
+  if (!latest.ok) return null;
+  const info = await latest.json() as { Version?: string };
+  return info.Version ? [info.Version] : null;
+}
+
+function usesPublicProxy(goproxy: string): boolean {
+  // Entries are separated by , (fall back on 404/410) or | (fall back on any error)
+  return goproxy.split(/[,|]/).map(e => e.trim()).some(entry =>
+    entry !== 'direct' && entry !== 'off' && PUBLIC_PROXIES.some(host => entry.replace(/^https?:\/\//, '').startsWith(host)));
+}
+
+export async function analyzeConfusion(projectRoot: string, options: ConfusionOptions = {}): Promise<ConfusionReport> {
+  const env = await readGoModuleEnv(projectRoot, options.signal);
+  const prefixes = options.prefixes?.length
+    ? options.prefixes
+    : env.GOPRIVATE.split(',').map(p => p.trim()).filter(Boolean);
+  if (prefixes.length === 0) {
+    throw new Error('No private module prefixes — pass --private <prefixes...> or set GOPRIVATE');
+  }
+  const proxy = options.proxy ?? PUBLIC_PROXY;
+  const warnings: string[] = [];
+  const isInternal = (path: string) => matchesGoPrefixPatterns(prefixes.join(','), path);
+
+  // Internal modules: the workspace's own, and every internal module it requires
+  const internal = new Map<string, Pick<ConfusionFinding, 'module' | 'source' | 'goModFile' | 'line'>>();
+  for (const mod of findGoModules(projectRoot)) {
+    if (isInternal(mod.path) && !internal.has(mod.path)) {
+      internal.set(mod.path, { module: mod.path, source: 'workspace', goModFile: mod.goModFile, line: null });
+    }
+    // A local replace means the path is never fetched, whatever the environment says
+    const replacedLocally = new Set(mod.mod.replace.filter(r => r.local).map(r => r.oldPath));
+    for (const req of mod.mod.require) {
+      if (isInternal(req.path) && !replacedLocally.has(req.path) && !internal.has(req.path)) {
+        internal.set(req.path, { module: req.path, source: 'require', goModFile: mod.goModFile, line: req.line });
+      }
+    }
+  }
+
+  const publicProxy = usesPublicProxy(env.GOPROXY);
+  const publicSumdb = env.GOSUMDB !== 'off' && !/-mod=vendor/.test(env.GOFLAGS);
+  const findings: ConfusionFinding[] = [];
+
+  for (const entry of internal.values()) {
+    let versions: string[] | null = null;
+    if (!options.offline) {
+      try {
+        versions = await publicVersions(proxy, entry.module, options.signal);
+      } catch (err) {
+        if (options.signal?.aborted) throw err;
+        warnings.push(`${entry.module}: ${err instanceof Error ? err.message : err}`);
+      }
+    }
+    const viaPublicProxy = publicProxy && !matchesGoPrefixPatterns(env.GONOPROXY, entry.module);
+    const viaPublicSumdb = publicSumdb && !matchesGoPrefixPatterns(env.GONOSUMDB, entry.module);
+    const resolvable = versions !== null && versions.length > 0;
+
+    let risk: ConfusionRisk;
+    let message: string;
+    if (resolvable && viaPublicProxy) {
+      risk = 'critical';
+      message = `Resolves on ${proxy} (${versions!.length} version${versions!.length === 1 ? '' : 's'}) and GONOPROXY doesn't cover it — builds may fetch the public copy`;
+    } else if (resolvable) {
+      risk = 'high';
+      message = `Resolves on ${proxy} — any machine without GOPRIVATE set will fetch the public copy`;
+    } else if (viaPublicProxy) {
+      risk = 'medium';
+      message = 'Not covered by GONOPROXY — the name is requested from the public proxy, and a later public upload would be used';
+    } else if (viaPublicSumdb) {
+      risk = 'low';
+      message = 'Not covered by GONOSUMDB — the name is sent to the public checksum database';
+    } else {
+      continue;
+    }
+    findings.push({ ...entry, publicVersions: versions, viaPublicProxy, viaPublicSumdb, risk, message });
+  }
+
+  const order: ConfusionRisk[] = ['critical', 'high', 'medium', 'low'];
+  findings.sort((a, b) => order.indexOf(a.risk) - order.indexOf(b.risk) || a.module.localeCompare(b.module));
+
+  if (!env.GOPRIVATE) {
+    warnings.push('GOPRIVATE is not set — internal modules are treated as public by the go command');
+  }
+  return { projectRoot, prefixes, env, proxy, checkedProxy: !options.offline, findings, warnings };
+}
@@ -0,0 +1,176 @@
+import { FetchCache } from './cache.js';
+import { loadGoProject } from '../golang/packages.js';
+import { extractGoApi } from '../golang/api.js';
+import { readGoMod } from '../golang/modfile.js';
+import { checkCancelled } from '../utils/progress.js';
+import { repositoryOf } from './scorecard.js';
+
+/**
+ * Public modules that depend on ours, for sizing a breaking change before
+ * making it. deps.dev (which builds its Go graph from index.golang.org)
+ * counts direct and indirect dependents of a module version; pkg.go.dev
+ * lists the known importers of each package. Importers are grouped into
+ * the repositories they live in, so the report reads "these projects use
+ * these packages of ours". Both sources only see public code, and results
+ * are cached in .depwire/ for a day.
+ */
+
+export const DEPS_DEV_API = 'https://api.deps.dev';
+export const PKG_GO_DEV = 'https://pkg.go.dev';
+
+export interface DependentCounts {
+  /** The module version deps.dev counted dependents of */
+  version: string;
+  direct: number;
+  indirect: number;
+  total: number;
+}
+
+export interface PackageImporters {
+  importPath: string;
+  /** The count pkg.go.dev shows, which can exceed the importers it lists */
+  knownImporters: number | null;
+  /** Importing packages outside our module */
+  importers: string[];
+}
+
+export interface DependentProject {
+  /** Repository (github.com/owner/repo) or, for other hosts, the importing package tree */
+  project: string;
+  /** Their packages that import ours */
+  importers: string[];
+  /** Our packages they import */
+  uses: string[];
+}
+
+export interface DependentsReport {
+  module: string;
+  counts: DependentCounts | null;
+  packages: PackageImporters[];
+  dependents: DependentProject[];
+  warnings: string[];
+}
+
+export interface DependentsOptions {
+  /** Module to look up (default: the module at the project root) */
+  module?: string;
+  /** Version deps.dev counts dependents of (default: its default version, usually the latest release) */
+  version?: string;
+  /** Only these packages (default: every importable package of the module) */
+  packages?: string[];
+  /** Counts only: skip the per-package importer lists */
+  countsOnly?: boolean;
+  /** Only read the cache, never the network */
+  offline?: boolean;
+  signal?: AbortSignal;
+}
+
+/** GET JSON, or null on 404 */
+export async function getJson<T>(url: string, signal?: AbortSignal): Promise<T | null> {
+  const response = await fetch(url, { signal, headers: { accept: 'application/json' } });
+  if (response.status === 404) return null;
+  if (!response.ok) throw new Error(`${new URL(url).host} returned ${response.status} for ${url}`);
+  return await response.json() as T;
+}
+
+/** deps.dev's dependent counts for module@version (its default version when omitted), or null if it doesn't know the module */
+export async function fetchDependentCounts(module: string, version?: string, options: { api?: string; signal?: AbortSignal } = {}): Promise<DependentCounts | null> {
+  const base = `${options.api ?? DEPS_DEV_API}/v3alpha/systems/go/packages/${encodeURIComponent(module)}`;
+  let resolved = version;
+  if (!resolved) {
+    const pkg = await getJson<{ versions?: Array<{ versionKey: { version: string }; isDefault?: boolean }> }>(base, options.signal);
+    const versions = pkg?.versions ?? [];
+    resolved = (versions.find(v => v.isDefault) ?? versions[versions.length - 1])?.versionKey.version;
+    if (!resolved) return null;
+  }
+  const counts = await getJson<{ dependentCount?: number; directDependentCount?: number; indirectDependentCount?: number }>(
+    `${base}/versions/${encodeURIComponent(resolved)}:dependents`, options.signal);
+  if (!counts) return null;
+  return {
+    version: resolved,
+    direct: counts.directDependentCount ?? 0,
+    indirect: counts.indirectDependentCount ?? 0,
+    total: counts.dependentCount ?? 0,
+  };
+}
+
+/** Importers pkg.go.dev lists on a package's "Imported by" tab */
+export function parseImportedBy(html: string): { count: number | null; importers: string[] } {
+  const importers = new Set<string>();
+  const link = /<a\b[^>]*href="\/([^"?#]+)"[^>]*data-gtmc="importedby link"[^>]*>/g;
+  let match: RegExpExecArray | null;
+  while ((match = link.exec(html))) importers.add(decodeURIComponent(match[1]));
+  const count = /Known importers:?\s*(?:<[^>]+>\s*)*([\d,]+)/i.exec(html);
+  return { count: count ? Number(count[1].replace(/,/g, '')) : null, importers: [...importers] };
+}
+
+export async function fetchImportedBy(importPath: string, options: { site?: string; signal?: AbortSignal } = {}): Promise<{ count: number | null; importers: string[] } | null> {
+  const response = await fetch(`${options.site ?? PKG_GO_DEV}/${importPath}?tab=importedby`, { signal: options.signal });
+  if (response.status === 404) return null;
+  if (!response.ok) throw new Error(`pkg.go.dev returned ${response.status} for ${importPath}`);
+  return parseImportedBy(await response.text());
+}
+
+function projectOf(importPath: string): string {
+  return repositoryOf(importPath) ?? importPath;
+}
+
+export async function analyzeDependents(projectRoot: string, options: DependentsOptions = {}): Promise<DependentsReport> {
+  const module = options.module ?? readGoMod(projectRoot)?.module;
+  if (!module) throw new Error('No go.mod at the project root — name the module with --module');
+
+  const warnings: string[] = [];
+  const cache = new FetchCache<unknown>(projectRoot, 'dependents.json');
+
+  // Cached lookups: fresh entries (or any entry offline) skip the network, failures fall back to stale ones
+  const cached = async <T>(key: string, fetcher: () => Promise<T>): Promise<T | null> => {
+    const fresh = cache.fresh(key, options.offline);
+    if (fresh !== undefined) return fresh as T;
+    if (options.offline) return null;
+    try {
+      const value = await fetcher();
+      cache.set(key, value);
+      return value;
+    } catch (err) {
+      if (options.signal?.aborted) throw err;
+      warnings.push(err instanceof Error ? err.message : String(err));
+      return (cache.stale(key) as T | undefined) ?? null;
+    }
+  };
+
+  const counts = await cached(`deps.dev:${module}@${options.version ?? ''}`, () => fetchDependentCounts(module, options.version, { signal: options.signal }));
+
+  let packages = options.packages;
+  if (!packages && !options.countsOnly) {
+    // Importable packages: not main, not internal — the same set apidiff guards
+    const local = readGoMod(projectRoot)?.module === module ? [...extractGoApi(await loadGoProject(projectRoot), module).keys()] : [];
+    packages = local.length > 0 ? local.sort() : [module];
+  }
+
+  const results: PackageImporters[] = [];
+  for (const importPath of options.countsOnly ? [] : packages ?? []) {
+    checkCancelled(options.signal);
+    const found = await cached(`pkg.go.dev:${importPath}`, () => fetchImportedBy(importPath, { signal: options.signal }));
+    const importers = (found?.importers ?? []).filter(p => p !== module && !p.startsWith(`${module}/`));
+    results.push({ importPath, knownImporters: found?.count ?? null, importers });
+  }
+
+  const projects = new Map<string, DependentProject>();
+  for (const pkg of results) {
+    for (const importer of pkg.importers) {
+      const key = projectOf(importer);
+      if (!projects.has(key)) projects.set(key, { project: key, importers: [], uses: [] });
+      const project = projects.get(key)!;
+      if (!project.importers.includes(importer)) project.importers.push(importer);
+      if (!project.uses.includes(pkg.importPath)) project.uses.push(pkg.importPath);
+    }
+  }
+  const dependents = [...projects.values()].sort((a, b) =>
+    b.uses.length - a.uses.length || b.importers.length - a.importers.length || a.project.localeCompare(b.project));
+
+  cache.save();
+  if (options.offline && !counts && results.every(r => r.knownImporters === null && r.importers.length === 0)) {
+    warnings.push('Offline and nothing cached for this module — run once without --offline');
+  }
+  return { module, counts, packages: results, dependents, warnings };
+}
@@ -0,0 +1,173 @@
+import { FetchCache } from './cache.js';
+import { matchesGoPrefixPatterns, readGoModuleEnv } from './confusion.js';
+import { DEPS_DEV_API, PKG_GO_DEV, getJson } from './dependents.js';
+import { repositoryOf } from './scorecard.js';
+import { resolveModuleVersion } from '../remote/proxy.js';
+import { mapLimit } from '../remote/compare.js';
+
+/**
+ * A glossary of external modules for reports, so a reviewer reading
+ * ARCHITECTURE.md knows what each dependency is without looking it up:
+ * what it does, who maintains it, the latest version and when it was
+ * committed. The latest version comes from GOPROXY; the description,
+ * homepage, license and stars from deps.dev's project for the module's
+ * source repository, with pkg.go.dev's synopsis when deps.dev has no
+ * description. Results are cached in .depwire/ for a day. Private modules
+ * (GOPRIVATE, GONOPROXY) are never looked up: their paths would reach the
+ * proxy and public services, and those know nothing about them anyway.
+ */
+
+export interface ModuleGlossaryEntry {
+  module: string;
+  /** The version required */
+  version: string;
+  /** Latest version on the proxy; null when it couldn't be resolved */
+  latest: string | null;
+  /** Commit time of the latest version — the head of the default branch for modules without releases */
+  latestTime: string | null;
+  description: string | null;
+  homepage: string | null;
+  /** Source repository, github.com/owner/repo */
+  repo: string | null;
+  /** Owner of the source repository (user or organization) */
+  maintainer: string | null;
+  license: string | null;
+  stars: number | null;
+}
+
+export interface GlossaryOptions {
+  /** GOPROXY list to resolve latest versions with (default: go env GOPROXY) */
+  proxy?: string;
+  /** Only read the cache, never the network */
+  offline?: boolean;
+  concurrency?: number;
+  signal?: AbortSignal;
+}
+
+interface DepsDevVersion {
+  licenses?: string[];
+  links?: Array<{ label: string; url: string }>;
+  relatedProjects?: Array<{ projectKey: { id: string }; relationType: string }>;
+}
+
+interface DepsDevProject {
+  description?: string;
+  homepage?: string;
+  license?: string;
+  starsCount?: number;
+}
+
+/** The synopsis pkg.go.dev shows for a package, from its description meta tag */
+export function parsePkgGoDevSynopsis(html: string): string | null {
+  const meta = /<meta\b[^>]*name="description"[^>]*content="([^"]*)"/i.exec(html);
+  const text = meta?.[1].replace(/&#34;|&quot;/g, '"').replace(/&#39;/g, "'").replace(/&lt;/g, '<').replace(/&gt;/g, '>').replace(/&amp;/g, '&').trim();
+  return text || null;
+}
+
+async function fetchSynopsis(module: string, signal?: AbortSignal): Promise<string | null> {
+  const response = await fetch(`${PKG_GO_DEV}/${module}`, { signal });
+  if (response.status === 404) return null;
+  if (!response.ok) throw new Error(`pkg.go.dev returned ${response.status} for ${module}`);
+  return parsePkgGoDevSynopsis(await response.text());
+}
+
+/** Upstream metadata for one module; version is the one looked up on deps.dev when the latest can't be resolved */
+export async function fetchModuleMetadata(module: string, version: string, goproxy: string, signal?: AbortSignal): Promise<{ entry: Omit<ModuleGlossaryEntry, 'version'>; warnings: string[] }> {
+  const warnings: string[] = [];
+  const attempt = async <T>(what: () => Promise<T>): Promise<T | null> => {
+    try {
+      return await what();
+    } catch (err) {
+      if (signal?.aborted) throw err;
+      warnings.push(`${module}: ${err instanceof Error ? err.message : err}`);
+      return null;
+    }
+  };
+
+  const latest = await attempt(() => resolveModuleVersion(goproxy, module, 'latest', signal));
+  const base = `${DEPS_DEV_API}/v3/systems/go/packages/${encodeURIComponent(module)}`;
+  const info = await attempt(() => getJson<DepsDevVersion>(`${base}/versions/${encodeURIComponent(latest?.Version ?? version)}`, signal));
+  const repo = info?.relatedProjects?.find(p => p.relationType === 'SOURCE_REPO')?.projectKey.id ?? repositoryOf(module);
+  const project = repo ? await attempt(() => getJson<DepsDevProject>(`${DEPS_DEV_API}/v3/projects/${encodeURIComponent(repo)}`, signal)) : null;
+  // deps.dev only describes GitHub and GitLab projects; the package comment does for the rest
+  const description = project?.description || await attempt(() => fetchSynopsis(module, signal));
+
+  return {
+    entry: {
+      module,
+      latest: latest?.Version ?? null,
+      latestTime: latest?.Time ?? null,
+      description: description || null,
+      homepage: project?.homepage || info?.links?.find(l => l.label === 'HOMEPAGE')?.url || null,
+      repo,
+      maintainer: repo ? repo.split('/')[1] ?? null : null,
+      license: info?.licenses?.join(', ') || project?.license || null,
+      stars: project?.starsCount ?? null,
+    },
+    warnings,
+  };
+}
+
+/** Glossary entries for modules, in the order given */
+export async function moduleGlossary(
+  projectRoot: string,
+  modules: Array<{ path: string; version: string }>,
+  options: GlossaryOptions = {},
+): Promise<{ entries: ModuleGlossaryEntry[]; warnings: string[] }> {
+  const warnings: string[] = [];
+  const cache = new FetchCache<Omit<ModuleGlossaryEntry, 'version'>>(projectRoot, 'glossary.json');
+  const env = options.offline ? null : await readGoModuleEnv(projectRoot, options.signal);
+  const goproxy = options.proxy ?? env?.GOPROXY ?? '';
+  const isPrivate = (path: string) => !!env && (matchesGoPrefixPatterns(env.GOPRIVATE, path) || matchesGoPrefixPatterns(env.GONOPROXY, path));
+  const hidden = modules.filter(m => isPrivate(m.path)).map(m => m.path);
+  if (hidden.length > 0) warnings.push(`${hidden.length} private modules (GOPRIVATE/GONOPROXY) were not looked up: ${hidden.join(', ')}`);
+
+  const entries = await mapLimit(modules, options.concurrency ?? 8, async ({ path, version }): Promise<ModuleGlossaryEntry> => {
+    const cached = cache.fresh(path, options.offline);
+    if (cached) return { ...cached, version };
+    if (options.offline || isPrivate(path)) {
+      const repo = repositoryOf(path);
+      return {
+        module: path, version, latest: null, latestTime: null, description: null, homepage: null,
+        repo, maintainer: repo?.split('/')[1] ?? null, license: null, stars: null,
+      };
+    }
+    const fetched = await fetchModuleMetadata(path, version, goproxy, options.signal);
+    warnings.push(...fetched.warnings);
+    const entry = { ...fetched.entry };
+    const stale = cache.stale(path);
+    if (stale && fetched.warnings.length > 0) {
+      // A failed request keeps what the stale entry knew
+      for (const key of Object.keys(entry) as Array<keyof typeof entry>) {
+        if (entry[key] === null) (entry as Record<string, unknown>)[key] = stale[key];
+      }
+    }
+    cache.set(path, entry);
+    return { ...entry, version };
+  });
+
+  cache.save();
+  if (options.offline && modules.length > 0 && modules.every(m => !cache.has(m.path))) {
+    warnings.push('Offline and nothing cached for these modules — run once without --offline');
+  }
+  return { entries, warnings };
+}
+
+/** The glossary as a Markdown table */
+export function formatGlossaryMarkdown(entries: ModuleGlossaryEntry[]): string {
+  const lines: string[] = ['## Glossary of external modules', ''];
+  if (entries.length === 0) {
+    lines.push('No external Go modules are required.', '');
+    return lines.join('\n');
+  }
+  const cell = (value: string) => value.replace(/\|/g, '\\|').replace(/\s+/g, ' ');
+  lines.push('| Module | Description | Maintainer | Version | Latest | Latest committed | License |', '| --- | --- | --- | --- | --- | --- | --- |');
+  for (const e of entries) {
+    const name = e.homepage ? `[\`${e.module}\`](${e.homepage})` : `\`${e.module}\``;
+    const maintainer = e.maintainer ? (e.stars !== null ? `${cell(e.maintainer)} (${e.stars.toLocaleString('en-US')} ★)` : cell(e.maintainer)) : '—';
+    const latest = e.latest === null ? '—' : e.latest === e.version ? 'same' : e.latest;
+    lines.push(`| ${name} | ${e.description ? cell(e.description) : '—'} | ${maintainer} | ${e.version} | ${latest} | ${e.latestTime?.slice(0, 10) ?? '—'} | ${e.license ? cell(e.license) : '—'} |`);
+  }
+  lines.push('');
+  return lines.join('\n');
+}
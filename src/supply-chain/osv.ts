@@ -0,0 +1,85 @@
+/**
+ * Known vulnerabilities of Go module versions from the OSV database
+ * (https://osv.dev), which carries the Go vulnerability database's reports.
+ * One batch query covers every module version; the advisories found are
+ * then fetched for their summaries and aliases. Modules matching GOPRIVATE
+ * are left out of the query, so private module paths don't reach a public
+ * service, unless the caller asks for them (a private OSV mirror).
+ */
+
+import { matchesGoPrefixPatterns, readGoModuleEnv } from './confusion.js';
+
+export const OSV_API = 'https://api.osv.dev';
+
+/** Module versions per batch query; the API accepts up to 1000 */
+const BATCH_SIZE = 1000;
+
+export interface OsvAdvisory {
+  /** "GO-2024-2687" */
+  id: string;
+  /** CVE and GHSA identifiers of the same issue */
+  aliases: string[];
+  summary: string | null;
+  url: string;
+}
+
+export interface OsvOptions {
+  /** Defaults to DEPWIRE_OSV_API, then the public API */
+  api?: string;
+  /** Module path patterns never sent to the API (default: GOPRIVATE from go env) */
+  goprivate?: string;
+  /** Query private modules too, for an OSV mirror of your own (default: DEPWIRE_OSV_PRIVATE=1) */
+  includePrivate?: boolean;
+  /** Called with the module versions left out as private */
+  onPrivate?: (modules: Array<{ path: string; version: string }>) => void;
+  signal?: AbortSignal;
+}
+
+function apiBase(options: OsvOptions): string {
+  return (options.api ?? process.env.DEPWIRE_OSV_API ?? OSV_API).replace(/\/$/, '');
+}
+
+/** Advisory ids affecting each module version, keyed "path@version"; private modules have no entry */
+export async function queryVulnerabilities(
+  modules: Array<{ path: string; version: string }>,
+  options: OsvOptions = {}
+): Promise<Map<string, string[]>> {
+  const result = new Map<string, string[]>();
+  if (!(options.includePrivate ?? process.env.DEPWIRE_OSV_PRIVATE === '1') && modules.length > 0) {
+    const goprivate = options.goprivate ?? (await readGoModuleEnv(process.cwd(), options.signal)).GOPRIVATE;
+    const hidden = modules.filter(m => matchesGoPrefixPatterns(goprivate, m.path));
+    if (hidden.length > 0) {
+      modules = modules.filter(m => !hidden.includes(m));
+      options.onPrivate?.(hidden);
+    }
+  }
+  for (let start = 0; start < modules.length; start += BATCH_SIZE) {
+    const batch = modules.slice(start, start + BATCH_SIZE);
+    const response = await fetch(`${apiBase(options)}/v1/querybatch`, {
+      method: 'POST',
+      signal: options.signal,
+      headers: { 'content-type': 'application/json', accept: 'application/json' },
+      body: JSON.stringify({
+        queries: batch.map(m => ({ package: { name: m.path, ecosystem: 'Go' }, version: m.version.replace(/^v/, '') })),
+      }),
+    });
+    if (!response.ok) throw new Error(`OSV API returned ${response.status} for a batch of ${batch.length} modules`);
+    const body = await response.json() as { results?: Array<{ vulns?: Array<{ id: string }> }> };
+    batch.forEach((m, i) => {
+      result.set(`${m.path}@${m.version}`, (body.results?.[i]?.vulns ?? []).map(v => v.id).sort());
+    });
+  }
+  return result;
+}
+
+export async function fetchAdvisory(id: string, options: OsvOptions = {}): Promise<OsvAdvisory> {
+  const url = `https://osv.dev/vulnerability/${id}`;
+  const response = await fetch(`${apiBase(options)}/v1/vulns/${encodeURIComponent(id)}`, {
+    signal: options.signal,
+    headers: { accept: 'application/json' },
+  });
+  if (!response.ok) throw new Error(`OSV API returned ${response.status} for ${id}`);
+  const body = await response.json() as { id: string; aliases?: string[]; summary?: string; details?: string };
+  const summary = body.summary || body.details?.split('\n')[0] || null;
+  return { id, aliases: (body.aliases ?? []).sort(), summary, url };
+}
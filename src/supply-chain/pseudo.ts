@@ -0,0 +1,208 @@
+import { execFile } from 'child_process';
+import { existsSync, rmSync } from 'fs';
+import { join } from 'path';
+import { readGoMod } from '../golang/modfile.js';
+import { parsePseudoVersion } from '../golang/pseudo.js';
+import { resolveModuleVersion, type ModuleInfo } from '../remote/proxy.js';
+import { checkCancelled } from '../utils/progress.js';
+import { ensurePrivateDir, userCacheDir } from '../utils/files.js';
+import { escapeModulePath, matchesGoPrefixPatterns, readGoModuleEnv } from './confusion.js';
+
+/**
+ * Dependencies pinned to pseudo-versions, resolved to the commit behind
+ * them and checked against the upstream repository: is the commit on the
+ * default branch, only on some other branch or tag, or on none at all —
+ * force-pushed away, or from a deleted fork branch. Such orphaned commits
+ * build today only because the module proxy cached them, and fail for
+ * anyone fetching direct (GOPRIVATE, GOPROXY=direct) or once the proxy forgets.
+ *
+ * Repositories are found from the proxy's Origin metadata or the module
+ * path's go-import meta tag, and inspected through a bare, treeless clone
+ * kept in the temp directory (commits only, no file contents).
+ */
+
+export type PinStatus =
+  /** Reachable from the default branch */
+  | 'merged'
+  /** Reachable only from other branches or tags */
+  | 'branch'
+  /** On no upstream branch or tag; the proxy still serves it */
+  | 'orphaned'
+  /** Neither upstream nor on the proxy */
+  | 'missing'
+  /** Not checked (offline, not a git repository, clone failed) */
+  | 'unknown';
+
+export interface PseudoVersionPin {
+  module: string;
+  version: string;
+  direct: boolean;
+  /** Set when a replace directive points the requirement here */
+  replaces?: string;
+  /** The tag the pseudo-version sorts after, or null */
+  base: string | null;
+  /** Commit time encoded in the pseudo-version */
+  time: string;
+  /** Full commit hash when known, otherwise the 12-character prefix */
+  commit: string;
+  repository: string | null;
+  status: PinStatus;
+  /** The default branch (merged), or the branches and tags holding the commit (branch) */
+  refs: string[];
+  /** Why the status is unknown, or other detail */
+  note?: string;
+}
+
+export interface PseudoVersionReport {
+  projectRoot: string;
+  pins: PseudoVersionPin[];
+  summary: Record<PinStatus, number>;
+}
+
+export interface PseudoVersionOptions {
+  /** Include indirect requirements */
+  all?: boolean;
+  /** Parse only: no proxy and no git */
+  offline?: boolean;
+  /** Overrides GOPROXY from go env */
+  proxy?: string;
+  /** Where bare clones are kept (default: ~/.cache/depwire/git) */
+  cacheDir?: string;
+  signal?: AbortSignal;
+}
+
+function git(args: string[], cwd: string, signal?: AbortSignal): Promise<string> {
+  return new Promise((resolve, reject) => {
+    execFile('git', args, { cwd, signal, maxBuffer: 64 * 1024 * 1024, env: { ...process.env, GIT_TERMINAL_PROMPT: '0' } }, (err, stdout, stderr) => {
+      if (err) reject(new Error(stderr.trim().split('\n').pop() || err.message));
+      else resolve(stdout);
+    });
+  });
+}
+
+/** The repository a module path's go-import meta tag names, as `go get` discovers it */
+export async function discoverRepository(modulePath: string, signal?: AbortSignal): Promise<{ vcs: string; url: string } | null> {
+  const response = await fetch(`https://${modulePath}?go-get=1`, { signal });
+  if (!response.ok) return null;
+  const html = await response.text();
+  const meta = /<meta\s+name=["']go-import["']\s+content=["']([^"']+)["']/gi;
+  let match: RegExpExecArray | null;
+  while ((match = meta.exec(html))) {
+    const [prefix, vcs, url] = match[1].trim().split(/\s+/);
+    if (vcs !== 'mod' && (modulePath === prefix || modulePath.startsWith(`${prefix}/`))) return { vcs, url };
+  }
+  return null;
+}
+
+/**
+ * A bare, treeless clone of url, fetched fresh on every call. A clone whose
+ * origin isn't url any more is not this repository's, and is cloned again.
+ */
+async function mirror(url: string, cacheDir: string, signal?: AbortSignal): Promise<string> {
+  const dir = join(cacheDir, escapeModulePath(url.replace(/^[a-z+]+:\/\//, '').replace(/\.git$/, '')));
+  if (existsSync(join(dir, 'HEAD'))) {
+    const origin = (await git(['config', '--get', 'remote.origin.url'], dir, signal).catch(() => '')).trim();
+    if (origin === url) {
+      await git(['fetch', '--quiet', '--prune', '--tags', 'origin', '+refs/heads/*:refs/heads/*'], dir, signal);
+      return dir;
+    }
+    rmSync(dir, { recursive: true, force: true });
+  }
+  await git(['clone', '--bare', '--quiet', '--filter=tree:0', url, dir], cacheDir, signal);
+  return dir;
+}
+
+async function commitStatus(dir: string, revision: string, fullHash: string | undefined, signal?: AbortSignal): Promise<{ commit: string; status: 'merged' | 'branch' | null; refs: string[] }> {
+  const resolve = () => git(['rev-parse', '--verify', '--quiet', `${fullHash ?? revision}^{commit}`], dir, signal).then(out => out.trim(), () => null);
+  let commit = await resolve();
+  if (!commit && fullHash) {
+    // Hosts like GitHub still serve unreachable commits by full hash
+    await git(['fetch', '--quiet', 'origin', fullHash], dir, signal).catch(() => undefined);
+    commit = await resolve();
+  }
+  if (!commit) return { commit: fullHash ?? revision, status: null, refs: [] };
+
+  const head = (await git(['symbolic-ref', '--short', 'HEAD'], dir, signal).catch(() => '')).trim();
+  if (head && await git(['merge-base', '--is-ancestor', commit, head], dir, signal).then(() => true, () => false)) {
+    return { commit, status: 'merged', refs: [head] };
+  }
+  const refs = (await git(['for-each-ref', '--contains', commit, '--format=%(refname:short)', 'refs/heads', 'refs/tags'], dir, signal))
+    .split('\n').map(r => r.trim()).filter(Boolean);
+  return { commit, status: refs.length > 0 ? 'branch' : null, refs };
+}
+
+export async function analyzePseudoVersions(projectRoot: string, options: PseudoVersionOptions = {}): Promise<PseudoVersionReport> {
+  const mod = readGoMod(projectRoot);
+  if (!mod) throw new Error('No go.mod found — pseudo-versions are a Go modules feature');
+
+  // What the build uses: a replacement module@version stands in for the requirement
+  const candidates: Array<{ module: string; version: string; direct: boolean; replaces?: string }> = [];
+  for (const req of mod.require) {
+    if (req.indirect && !options.all) continue;
+    const replace = mod.replace.find(r => r.oldPath === req.path && (!r.oldVersion || r.oldVersion === req.version));
+    if (replace?.local) continue;
+    if (replace?.newVersion) candidates.push({ module: replace.newPath, version: replace.newVersion, direct: !req.indirect, replaces: req.path });
+    else candidates.push({ module: req.path, version: req.version, direct: !req.indirect });
+  }
+
+  const env = options.offline ? null : await readGoModuleEnv(projectRoot, options.signal);
+  const cacheDir = env ? ensurePrivateDir(options.cacheDir ?? userCacheDir('git')) : '';
+  const mirrors = new Map<string, Promise<string>>();
+  const pins: PseudoVersionPin[] = [];
+
+  for (const candidate of candidates) {
+    const pseudo = parsePseudoVersion(candidate.version);
+    if (!pseudo) continue;
+    checkCancelled(options.signal);
+    const pin: PseudoVersionPin = {
+      ...candidate,
+      base: pseudo.base,
+      time: pseudo.time,
+      commit: pseudo.revision,
+      repository: null,
+      status: 'unknown',
+      refs: [],
+    };
+    pins.push(pin);
+    if (!env) {
+      pin.note = 'offline';
+      continue;
+    }
+
+    // Private modules are never named to a proxy, as with the go command; their repository is found directly
+    let info: ModuleInfo | null = null;
+    if (!matchesGoPrefixPatterns(env.GONOPROXY, candidate.module)) {
+      try {
+        info = await resolveModuleVersion(options.proxy ?? env.GOPROXY, candidate.module, candidate.version, options.signal);
+      } catch (err) {
+        if (options.signal?.aborted) throw err;
+      }
+    }
+    try {
+      const origin = info?.Origin?.URL ? { vcs: info.Origin.VCS ?? 'git', url: info.Origin.URL } : await discoverRepository(candidate.module, options.signal);
+      if (!origin) {
+        pin.note = 'no go-import meta tag names the repository';
+      } else if (origin.vcs !== 'git') {
+        pin.repository = origin.url;
+        pin.note = `${origin.vcs} repositories are not checked`;
+      } else {
+        pin.repository = origin.url;
+        if (!mirrors.has(origin.url)) mirrors.set(origin.url, mirror(origin.url, cacheDir, options.signal));
+        const dir = await mirrors.get(origin.url)!;
+        const found = await commitStatus(dir, pseudo.revision, info?.Origin?.Hash, options.signal);
+        pin.commit = found.commit;
+        pin.refs = found.refs;
+        pin.status = found.status ?? (info ? 'orphaned' : 'missing');
+      }
+    } catch (err) {
+      if (options.signal?.aborted) throw err;
+      pin.note = err instanceof Error ? err.message : String(err);
+    }
+  }
+
+  const summary: Record<PinStatus, number> = { merged: 0, branch: 0, orphaned: 0, missing: 0, unknown: 0 };
+  for (const pin of pins) summary[pin.status]++;
+  const order: PinStatus[] = ['missing', 'orphaned', 'branch', 'unknown', 'merged'];
+  pins.sort((a, b) => order.indexOf(a.status) - order.indexOf(b.status) || a.module.localeCompare(b.module));
+  return { projectRoot, pins, summary };
+}
@@ -0,0 +1,177 @@
+import { FetchCache } from './cache.js';
+import { buildModuleGraph, type ModuleGraph } from '../golang/modgraph.js';
+import { matchesGoPrefixPatterns, readGoModuleEnv } from './confusion.js';
+
+/**
+ * OpenSSF Scorecard results for the repositories behind external Go modules,
+ * fetched from the public Scorecard API and attached to the module graph as
+ * the `scorecard` node attribute. Results are cached in .depwire/ for a day —
+ * Scorecard itself only rescans weekly. Private modules (GOPRIVATE/GONOPROXY)
+ * are never sent to the public API.
+ */
+
+export const SCORECARD_API = 'https://api.securityscorecards.dev';
+
+/** The checks shown by default; every check is kept in the result */
+export const KEY_CHECKS = ['Maintained', 'Branch-Protection', 'Dangerous-Workflow', 'Code-Review', 'Vulnerabilities'];
+
+export interface ScorecardResult {
+  /** "github.com/sirupsen/logrus" */
+  repo: string;
+  date: string;
+  commit: string | null;
+  /** Aggregate score, 0–10 */
+  score: number;
+  /** Check name → score 0–10, or -1 when the check was inconclusive */
+  checks: Record<string, number>;
+}
+
+export interface ModuleScorecard {
+  module: string;
+  version: string;
+  direct: boolean;
+  /** Repository the module was mapped to; null when the host isn't one Scorecard scans */
+  repo: string | null;
+  /** null when Scorecard has no result for the repository */
+  scorecard: ScorecardResult | null;
+}
+
+export interface ScorecardReport {
+  projectRoot: string;
+  modules: ModuleScorecard[];
+  warnings: string[];
+}
+
+export interface ScorecardOptions {
+  /** Only read the cache, never the network */
+  offline?: boolean;
+  /** Only these module paths */
+  modules?: string[];
+  api?: string;
+  signal?: AbortSignal;
+}
+
+// Vanity import paths of widely used modules and the GitHub repository behind them
+const VANITY: Array<[RegExp, (m: RegExpExecArray) => string]> = [
+  [/^golang\.org\/x\/([^/]+)/, m => `github.com/golang/${m[1]}`],
+  [/^google\.golang\.org\/grpc(\/|$)/, () => 'github.com/grpc/grpc-go'],
+  [/^google\.golang\.org\/protobuf(\/|$)/, () => 'github.com/protocolbuffers/protobuf-go'],
+  [/^google\.golang\.org\/api(\/|$)/, () => 'github.com/googleapis/google-api-go-client'],
+  [/^google\.golang\.org\/genproto/, () => 'github.com/googleapis/go-genproto'],
+  [/^cloud\.google\.com\/go/, () => 'github.com/googleapis/google-cloud-go'],
+  [/^go\.uber\.org\/([^/]+)/, m => `github.com/uber-go/${m[1]}`],
+  [/^k8s\.io\/([^/]+)/, m => `github.com/kubernetes/${m[1]}`],
+  [/^sigs\.k8s\.io\/([^/]+)/, m => `github.com/kubernetes-sigs/${m[1]}`],
+  [/^go\.opentelemetry\.io\/otel/, () => 'github.com/open-telemetry/opentelemetry-go'],
+  [/^go\.opentelemetry\.io\/contrib/, () => 'github.com/open-telemetry/opentelemetry-go-contrib'],
+  [/^go\.etcd\.io\/([^/]+)/, m => `github.com/etcd-io/${m[1]}`],
+  [/^go\.mongodb\.org\/mongo-driver/, () => 'github.com/mongodb/mongo-go-driver'],
+  [/^gorm\.io\/([^/]+)/, m => `github.com/go-gorm/${m[1]}`],
+  [/^gopkg\.in\/([^/.]+)\.v\d+/, m => `github.com/go-${m[1]}/${m[1]}`],
+  [/^gopkg\.in\/([^/]+)\/([^/.]+)\.v\d+/, m => `github.com/${m[1]}/${m[2]}`],
+];
+
+/** The repository Scorecard knows a module by, or null for hosts it doesn't scan */
+export function repositoryOf(modulePath: string): string | null {
+  const hosted = /^(github\.com|gitlab\.com)\/([^/]+)\/([^/]+)/.exec(modulePath);
+  if (hosted) return `${hosted[1]}/${hosted[2]}/${hosted[3].replace(/\.git$/, '')}`;
+  for (const [pattern, repo] of VANITY) {
+    const m = pattern.exec(modulePath);
+    if (m) return repo(m);
+  }
+  return null;
+}
+
+/** Scorecard result for one repository, or null when it hasn't been scanned */
+export async function fetchScorecard(repo: string, options: { api?: string; signal?: AbortSignal } = {}): Promise<ScorecardResult | null> {
+  const response = await fetch(`${options.api ?? SCORECARD_API}/projects/${repo}`, {
+    signal: options.signal,
+    headers: { accept: 'application/json' },
+  });
+  if (response.status === 404) return null;
+  if (!response.ok) throw new Error(`Scorecard API returned ${response.status} for ${repo}`);
+  const body = await response.json() as {
+    date: string;
+    repo?: { name?: string; commit?: string };
+    score: number;
+    checks?: Array<{ name: string; score: number }>;
+  };
+  return {
+    repo,
+    date: body.date,
+    commit: body.repo?.commit ?? null,
+    score: body.score,
+    checks: Object.fromEntries((body.checks ?? []).map(c => [c.name, c.score])),
+  };
+}
+
+/**
+ * Fetch scorecards for the external modules of a module graph and store each
+ * as the node's `scorecard` attribute (null when there's no result).
+ */
+export async function enrichWithScorecards(graph: ModuleGraph, projectRoot: string, options: ScorecardOptions = {}): Promise<string[]> {
+  const warnings: string[] = [];
+  const cache = new FetchCache<ScorecardResult | null>(projectRoot, 'scorecards.json');
+  const env = options.offline ? null : await readGoModuleEnv(projectRoot, options.signal);
+  const isPrivate = (path: string) => !!env && (matchesGoPrefixPatterns(env.GOPRIVATE, path) || matchesGoPrefixPatterns(env.GONOPROXY, path));
+  const hidden: string[] = [];
+
+  for (const node of graph.nodes()) {
+    const attrs = graph.getNodeAttributes(node);
+    if (attrs.main || (options.modules && !options.modules.includes(attrs.path))) continue;
+    const repo = repositoryOf(attrs.path);
+    graph.setNodeAttribute(node, 'repo', repo);
+    if (repo && isPrivate(attrs.path)) {
+      hidden.push(attrs.path);
+      graph.setNodeAttribute(node, 'scorecard', null);
+      continue;
+    }
+    if (!repo) {
+      graph.setNodeAttribute(node, 'scorecard', null);
+      continue;
+    }
+
+    const cached = cache.fresh(repo, options.offline);
+    if (cached !== undefined) {
+      graph.setNodeAttribute(node, 'scorecard', cached);
+      continue;
+    }
+    if (options.offline) {
+      graph.setNodeAttribute(node, 'scorecard', null);
+      continue;
+    }
+    try {
+      const result = await fetchScorecard(repo, { api: options.api, signal: options.signal });
+      cache.set(repo, result);
+      graph.setNodeAttribute(node, 'scorecard', result);
+    } catch (err) {
+      if (options.signal?.aborted) throw err;
+      warnings.push(`${repo}: ${err instanceof Error ? err.message : err}`);
+      graph.setNodeAttribute(node, 'scorecard', cache.stale(repo) ?? null);
+    }
+  }
+
+  cache.save();
+  if (hidden.length > 0) warnings.push(`${hidden.length} private modules (GOPRIVATE/GONOPROXY) were not looked up: ${hidden.join(', ')}`);
+  return warnings;
+}
+
+export async function analyzeScorecards(projectRoot: string, options: ScorecardOptions = {}): Promise<ScorecardReport> {
+  const { graph, warnings } = await buildModuleGraph(projectRoot, { signal: options.signal });
+  warnings.push(...await enrichWithScorecards(graph, projectRoot, options));
+
+  const modules: ModuleScorecard[] = [];
+  graph.forEachNode((_node, attrs) => {
+    if (attrs.main || (options.modules && !options.modules.includes(attrs.path))) return;
+    modules.push({
+      module: attrs.path,
+      version: attrs.version,
+      direct: attrs.direct,
+      repo: (attrs.repo as string | null | undefined) ?? null,
+      scorecard: (attrs.scorecard as ScorecardResult | null | undefined) ?? null,
+    });
+  });
+  modules.sort((a, b) => Number(b.direct) - Number(a.direct) ||
+    (a.scorecard?.score ?? 11) - (b.scorecard?.score ?? 11) || a.module.localeCompare(b.module));
+  return { projectRoot, modules, warnings };
+}
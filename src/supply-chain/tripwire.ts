@@ -0,0 +1,206 @@
+import type { Node } from 'web-tree-sitter';
+import { walk, goDeclarations } from '../golang/source.js';
+import { loadGoProject } from '../golang/packages.js';
+import { fileQualifiers } from '../golang/references.js';
+import { loadGoDependencies, withDependencies, dependencyModule } from '../golang/deps.js';
+import { loadGoCallGraph, functionFile, packageKey, type GoCallGraph } from '../golang/callgraph.js';
+import { newModules } from './typosquat.js';
+
+/**
+ * Init-time tripwire for new dependencies: code that runs merely because a
+ * package is imported (init functions and package-level var initializers,
+ * and everything they call) has no business running processes, opening
+ * connections or decoding embedded payloads. Any of these in a module a PR
+ * adds is worth a human look before it merges.
+ */
+
+export type TripwireKind = 'exec' | 'network' | 'decode';
+
+export interface TripwireStep {
+  /** Function id, or "var <name>" for a package-level initializer */
+  function: string;
+  file: string;
+  line: number;
+}
+
+export interface TripwireFinding {
+  module: string;
+  version: string;
+  /** Import path of the package whose initialization triggers it */
+  package: string;
+  kind: TripwireKind;
+  /** "os/exec.Command", "encoding/base64.StdEncoding.DecodeString" */
+  call: string;
+  file: string;
+  line: number;
+  /** init → … → the function making the call */
+  chain: TripwireStep[];
+  /** decode alone is medium; exec or network (or decode next to them) is high */
+  severity: 'high' | 'medium';
+}
+
+export interface TripwireReport {
+  projectRoot: string;
+  base: string | null;
+  /** Module paths that were checked */
+  modules: string[];
+  findings: TripwireFinding[];
+  warnings: string[];
+}
+
+export interface TripwireOptions {
+  /** Only check modules added since this git ref (default: every dependency) */
+  base?: string;
+  /** Call graph algorithm for init chains: syntactic (default) or pta */
+  algo?: string;
+  signal?: AbortSignal;
+}
+
+const PATTERNS: Array<[RegExp, TripwireKind]> = [
+  [/^os\/exec\./, 'exec'],
+  [/^os\.StartProcess$/, 'exec'],
+  [/^syscall\.(Exec|ForkExec|StartProcess)$/, 'exec'],
+  [/^golang\.org\/x\/sys\/unix\.(Exec|ForkExec)$/, 'exec'],
+  [/^plugin\.Open$/, 'exec'],
+  [/^net\.(Dial\w*|Listen\w*|Lookup\w*|Resolve\w+Addr|DefaultResolver\.\w+)$/, 'network'],
+  [/^net\/http\.(Get|Head|Post|PostForm|NewRequest\w*|ListenAndServe\w*|DefaultClient\.\w+)$/, 'network'],
+  [/^(net\/rpc|net\/smtp|crypto\/tls)\.Dial\w*$/, 'network'],
+  [/^net\/smtp\.SendMail$/, 'network'],
+  [/^encoding\/base64\.(\w+Encoding\.Decode\w*|NewDecoder)$/, 'decode'],
+  [/^encoding\/(base32|ascii85)\.(\w+\.Decode\w*|NewDecoder|Decode)$/, 'decode'],
+  [/^encoding\/hex\.(Decode\w*|NewDecoder)$/, 'decode'],
+  [/^compress\/(gzip|zlib|flate|bzip2|lzw)\.NewReader$/, 'decode'],
+];
+
+/** "<import path>.Sel.Ector" for a call through a package qualifier, or null */
+function qualifiedCallee(callee: Node | null, qualifiers: Map<string, string>): string | null {
+  const parts: string[] = [];
+  let n = callee;
+  while (n?.type === 'selector_expression') {
+    parts.unshift(n.childForFieldName('field')?.text ?? '');
+    n = n.childForFieldName('operand');
+  }
+  if (n?.type !== 'identifier' || parts.length === 0) return null;
+  const path = qualifiers.get(n.text);
+  return path ? `${path}.${parts.join('.')}` : null;
+}
+
+function classify(call: string): TripwireKind | null {
+  for (const [pattern, kind] of PATTERNS) {
+    if (pattern.test(call)) return kind;
+  }
+  return null;
+}
+
+export async function analyzeTripwire(projectRoot: string, options: TripwireOptions = {}): Promise<TripwireReport> {
+  const deps = await loadGoDependencies(projectRoot, { signal: options.signal });
+  const project = withDependencies(await loadGoProject(projectRoot), deps);
+  const warnings = [...deps.warnings];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+
+  const added = options.base ? new Set(newModules(projectRoot, options.base).map(m => m.path)) : null;
+  const modules = new Set<string>();
+  const findings: TripwireFinding[] = [];
+  const seen = new Set<string>();
+
+  for (const pkg of project.packages.values()) {
+    const mod = dependencyModule(pkg.dir);
+    if (!mod || (added && !added.has(mod.path))) continue;
+    modules.add(mod.path);
+    const key = packageKey(pkg);
+
+    // Roots: init functions, and package-level vars whose initializers call something
+    const roots: Array<{ step: TripwireStep; callees: string[]; body: Node; qualifiers: Map<string, string> }> = [];
+    for (const fn of graph.functions.values()) {
+      if (fn.dir !== pkg.dir || !fn.id.includes('.init#')) continue;
+      roots.push({ step: { function: fn.id, file: fn.file, line: fn.line }, callees: [], body: fn.node, qualifiers: fileQualifiers(functionFile(graph, fn), project) });
+    }
+    for (const file of pkg.files) {
+      const qualifiers = fileQualifiers(file, project);
+      for (const decl of goDeclarations(file)) {
+        const value = decl.kind === 'var' ? decl.node.childForFieldName('value') : null;
+        if (!value) continue;
+        const callees: string[] = [];
+        walk(value, (node) => {
+          if (node.type !== 'call_expression') return;
+          const callee = node.childForFieldName('function');
+          const id = callee?.type === 'identifier' ? `${key}.${callee.text}` : qualifiedCallee(callee, qualifiers);
+          if (id && graph.functions.has(id)) callees.push(id);
+        });
+        roots.push({ step: { function: `var ${decl.name}`, file: file.file, line: decl.line }, callees, body: value, qualifiers });
+      }
+    }
+
+    const report = (chain: TripwireStep[], node: Node, file: string, qualifiers: Map<string, string>) => {
+      walk(node, (n) => {
+        if (n.type !== 'call_expression') return;
+        const call = qualifiedCallee(n.childForFieldName('function'), qualifiers);
+        const kind = call ? classify(call) : null;
+        if (!call || !kind) return;
+        const line = n.startPosition.row + 1;
+        const id = `${pkg.dir}|${file}:${line}|${call}`;
+        if (seen.has(id)) return;
+        seen.add(id);
+        findings.push({
+          module: mod.path,
+          version: mod.version,
+          package: pkg.importPath ?? pkg.dir,
+          kind,
+          call,
+          file,
+          line,
+          chain,
+          severity: kind === 'decode' ? 'medium' : 'high',
+        });
+      });
+    };
+
+    for (const root of roots) {
+      report([root.step], root.body, root.step.file, root.qualifiers);
+      const start = root.step.function.startsWith('var ') ? root.callees : [root.step.function];
+      for (const [id, chain] of reachFrom(graph, start, root.step)) {
+        const fn = graph.functions.get(id)!;
+        if (chain.length === 1) continue; // the root itself, already scanned
+        report(chain, fn.node, fn.file, fileQualifiers(functionFile(graph, fn), project));
+      }
+    }
+  }
+
+  // Decoding next to exec or network in the same package is the classic dropper shape
+  for (const f of findings) {
+    if (f.kind === 'decode' && findings.some(o => o.package === f.package && o.kind !== 'decode')) f.severity = 'high';
+  }
+  findings.sort((a, b) => Number(b.severity === 'high') - Number(a.severity === 'high') ||
+    a.module.localeCompare(b.module) || a.file.localeCompare(b.file) || a.line - b.line);
+
+  return { projectRoot, base: options.base ?? null, modules: [...modules].sort(), findings, warnings };
+}
+
+/**
+ * Functions reachable from start over exact (non-approximate) calls, each
+ * with the chain of steps from the root. Name-only method matches would make
+ * every init reach half the dependency tree.
+ */
+function reachFrom(graph: GoCallGraph, start: string[], root: TripwireStep): Map<string, TripwireStep[]> {
+  const chains = new Map<string, TripwireStep[]>();
+  const queue: string[] = [];
+  const isVar = root.function.startsWith('var ');
+  for (const id of start) {
+    if (chains.has(id)) continue;
+    const fn = graph.functions.get(id)!;
+    const step = { function: fn.id, file: fn.file, line: fn.line };
+    chains.set(id, isVar ? [root, step] : [step]);
+    queue.push(id);
+  }
+  while (queue.length > 0) {
+    const id = queue.shift()!;
+    for (const call of graph.callsFrom.get(id) ?? []) {
+      if (call.external || call.approximate || chains.has(call.callee)) continue;
+      const fn = graph.functions.get(call.callee);
+      if (!fn) continue;
+      chains.set(call.callee, [...chains.get(id)!, { function: fn.id, file: fn.file, line: fn.line }]);
+      queue.push(call.callee);
+    }
+  }
+  return chains;
+}
@@ -0,0 +1,258 @@
+import { existsSync, readFileSync } from 'fs';
+import { join, relative } from 'path';
+import { minimatch } from 'minimatch';
+import { parseGoMod, type GoRequire } from '../golang/modfile.js';
+import { findGoModules } from '../golang/modules.js';
+import { readFileAtRef } from '../temporal/git.js';
+
+/**
+ * Typosquatting checks for Go module paths: a new dependency whose path is
+ * a near miss of a well-known module (or of the organization's own module
+ * prefixes) is far more likely to be an attack than a coincidence.
+ */
+
+/** Widely used Go modules that attackers imitate */
+export const POPULAR_GO_MODULES = [
+  'github.com/sirupsen/logrus', 'go.uber.org/zap', 'github.com/rs/zerolog', 'github.com/spf13/cobra',
+  'github.com/spf13/viper', 'github.com/spf13/pflag', 'github.com/urfave/cli', 'github.com/gin-gonic/gin',
+  'github.com/labstack/echo', 'github.com/gofiber/fiber', 'github.com/gorilla/mux', 'github.com/gorilla/websocket',
+  'github.com/go-chi/chi', 'github.com/julienschmidt/httprouter', 'github.com/valyala/fasthttp',
+  'github.com/stretchr/testify', 'github.com/onsi/ginkgo', 'github.com/onsi/gomega', 'github.com/golang/mock',
+  'go.uber.org/mock', 'github.com/google/go-cmp', 'github.com/google/uuid', 'github.com/gofrs/uuid',
+  'github.com/satori/go.uuid', 'github.com/pkg/errors', 'github.com/hashicorp/go-multierror',
+  'github.com/hashicorp/go-retryablehttp', 'github.com/hashicorp/vault', 'github.com/hashicorp/consul',
+  'github.com/hashicorp/terraform', 'github.com/hashicorp/hcl', 'github.com/golang/protobuf',
+  'google.golang.org/protobuf', 'google.golang.org/grpc', 'github.com/grpc-ecosystem/grpc-gateway',
+  'github.com/go-sql-driver/mysql', 'github.com/lib/pq', 'github.com/jackc/pgx', 'github.com/mattn/go-sqlite3',
+  'gorm.io/gorm', 'github.com/jmoiron/sqlx', 'go.mongodb.org/mongo-driver', 'github.com/redis/go-redis',
+  'github.com/go-redis/redis', 'github.com/gomodule/redigo', 'github.com/aws/aws-sdk-go', 'github.com/aws/aws-sdk-go-v2',
+  'cloud.google.com/go', 'github.com/Azure/azure-sdk-for-go', 'k8s.io/client-go', 'k8s.io/apimachinery',
+  'k8s.io/api', 'sigs.k8s.io/controller-runtime', 'github.com/prometheus/client_golang',
+  'go.opentelemetry.io/otel', 'github.com/golang-jwt/jwt', 'github.com/dgrijalva/jwt-go', 'golang.org/x/crypto',
+  'golang.org/x/net', 'golang.org/x/sys', 'golang.org/x/text', 'golang.org/x/sync', 'golang.org/x/oauth2',
+  'golang.org/x/tools', 'golang.org/x/mod', 'golang.org/x/exp', 'golang.org/x/time', 'gopkg.in/yaml.v2',
+  'gopkg.in/yaml.v3', 'github.com/goccy/go-yaml', 'github.com/BurntSushi/toml', 'github.com/pelletier/go-toml',
+  'github.com/json-iterator/go', 'github.com/tidwall/gjson', 'github.com/mitchellh/mapstructure',
+  'github.com/go-playground/validator', 'github.com/google/go-github', 'github.com/docker/docker',
+  'github.com/containerd/containerd', 'github.com/opencontainers/runc', 'github.com/fsnotify/fsnotify',
+  'github.com/nats-io/nats.go', 'github.com/segmentio/kafka-go', 'github.com/Shopify/sarama', 'github.com/IBM/sarama',
+  'github.com/rabbitmq/amqp091-go', 'github.com/streadway/amqp', 'github.com/cenkalti/backoff',
+  'github.com/avast/retry-go', 'github.com/robfig/cron', 'github.com/fatih/color', 'github.com/charmbracelet/bubbletea',
+  'github.com/charmbracelet/lipgloss', 'github.com/mattn/go-isatty', 'github.com/olekukonko/tablewriter',
+  'github.com/shopspring/decimal', 'github.com/dustin/go-humanize', 'github.com/patrickmn/go-cache',
+  'github.com/allegro/bigcache', 'github.com/dgraph-io/badger', 'go.etcd.io/bbolt', 'go.etcd.io/etcd',
+  'github.com/golang/glog', 'k8s.io/klog', 'github.com/go-logr/logr', 'github.com/uber-go/tally',
+  'github.com/klauspost/compress', 'github.com/golang/snappy', 'github.com/pierrec/lz4', 'github.com/ulikunitz/xz',
+  'github.com/miekg/dns', 'github.com/cespare/xxhash', 'github.com/emicklei/go-restful', 'github.com/go-openapi/spec',
+  'github.com/swaggo/swag', 'github.com/99designs/gqlgen', 'github.com/graph-gophers/graphql-go',
+  'github.com/google/wire', 'go.uber.org/fx', 'go.uber.org/dig', 'github.com/samber/lo', 'github.com/joho/godotenv',
+  'github.com/kelseyhightower/envconfig', 'github.com/caarlos0/env', 'github.com/cli/cli', 'github.com/golangci/golangci-lint',
+];
+
+export type TyposquatReason = 'non-ascii' | 'homoglyph' | 'edit-distance' | 'owner-lookalike' | 'internal-lookalike';
+
+export interface TyposquatMatch {
+  module: string;
+  /** The module it imitates */
+  resembles: string;
+  reason: TyposquatReason;
+  distance: number;
+  /** "likely" fails the build; "possible" is reported only */
+  confidence: 'likely' | 'possible';
+  message: string;
+}
+
+export interface TyposquatOptions {
+  /** Extra well-known modules to compare against */
+  popular?: string[];
+  /** Module path globs that are trusted even if they look like something else */
+  allow?: string[];
+  /** The organization's own module prefixes (e.g. github.com/acme) — lookalikes are flagged */
+  internalPrefixes?: string[];
+}
+
+export interface NewModule {
+  path: string;
+  version: string;
+  goModFile: string;
+  line: number;
+}
+
+export interface TyposquatReport {
+  projectRoot: string;
+  base: string | null;
+  checked: NewModule[];
+  matches: Array<TyposquatMatch & { goModFile: string; line: number; version: string }>;
+}
+
+// Characters that render like ASCII letters and digits, plus multi-letter lookalikes
+const HOMOGLYPHS: Array<[RegExp, string]> = [
+  [/[аạа]/g, 'a'], [/[еẹ]/g, 'e'], [/[оο0]/g, 'o'], [/[рρ]/g, 'p'], [/[сϲ]/g, 'c'], [/[хχ]/g, 'x'],
+  [/[уү]/g, 'y'], [/[іı1lǀ|]/g, 'l'], [/[ѕ]/g, 's'], [/[ԁ]/g, 'd'], [/[ɡ]/g, 'g'], [/[ո]/g, 'n'],
+  [/rn/g, 'm'], [/vv/g, 'w'], [/cl/g, 'd'],
+];
+
+/** Fold a path to a canonical form where lookalike characters compare equal */
+export function foldHomoglyphs(path: string): string {
+  let folded = path.toLowerCase().normalize('NFKC');
+  for (const [pattern, replacement] of HOMOGLYPHS) folded = folded.replace(pattern, replacement);
+  return folded.replace(/[-_.]/g, '');
+}
+
+/** Damerau–Levenshtein (optimal string alignment) distance */
+export function editDistance(a: string, b: string): number {
+  const d: number[][] = Array.from({ length: a.length + 1 }, (_, i) => [i, ...new Array(b.length).fill(0)]);
+  for (let j = 1; j <= b.length; j++) d[0][j] = j;
+  for (let i = 1; i <= a.length; i++) {
+    for (let j = 1; j <= b.length; j++) {
+      const cost = a[i - 1] === b[j - 1] ? 0 : 1;
+      d[i][j] = Math.min(d[i - 1][j] + 1, d[i][j - 1] + 1, d[i - 1][j - 1] + cost);
+      if (i > 1 && j > 1 && a[i - 1] === b[j - 2] && a[i - 2] === b[j - 1]) {
+        d[i][j] = Math.min(d[i][j], d[i - 2][j - 2] + 1);
+      }
+    }
+  }
+  return d[a.length][b.length];
+}
+
+// Major version suffixes don't make a different module for this purpose
+function stripMajor(path: string): string {
+  return path.replace(/\/v\d+$/, '').replace(/\.v\d+$/, '');
+}
+
+// Hosts where the repository is the third path element, under its owner
+const CODE_HOSTS = new Set(['github.com', 'gitlab.com', 'bitbucket.org', 'codeberg.org', 'gitea.com', 'git.sr.ht']);
+
+/**
+ * A module path's repository element and the namespace it is published
+ * under: github.com/jackc | pgx, go.uber.org | zap, golang.org/x | net,
+ * gopkg.in | yaml.v3. Only whoever controls the namespace can publish in it.
+ */
+export function splitRepository(path: string): { namespace: string; repo: string } {
+  const parts = path.split('/');
+  const size = CODE_HOSTS.has(parts[0]) || (parts[0] === 'golang.org' && parts[1] === 'x') || (parts[0] === 'gopkg.in' && parts.length > 2 && !/\.v\d+$/.test(parts[1])) ? 3 : 2;
+  const root = parts.slice(0, size);
+  return { namespace: root.slice(0, -1).join('/').toLowerCase(), repo: (root[root.length - 1] ?? '').replace(/\.v\d+$/, '').toLowerCase() };
+}
+
+// Edits a repository name can take and still read as the same one: none for names like pgx and mux
+function repoThreshold(repo: string): number {
+  return repo.length >= 12 ? 2 : repo.length >= 5 ? 1 : 0;
+}
+
+export function checkModulePath(path: string, options: TyposquatOptions = {}): TyposquatMatch | null {
+  const popular = [...new Set([...POPULAR_GO_MODULES, ...(options.popular ?? [])].map(stripMajor))];
+  const base = stripMajor(path);
+  if (popular.some(p => base === p || path.startsWith(`${p}/`))) return null;
+  if ((options.allow ?? []).some(glob => minimatch(path, glob))) return null;
+
+  const match = (resembles: string, reason: TyposquatReason, distance: number, confidence: TyposquatMatch['confidence'], message: string): TyposquatMatch =>
+    ({ module: path, resembles, reason, distance, confidence, message });
+
+  // Module paths are ASCII by spec; anything else is an attempt to look like one
+  if (/[^\x00-\x7f]/.test(path)) {
+    const folded = foldHomoglyphs(path);
+    const target = popular.find(p => foldHomoglyphs(p) === folded) ?? popular.find(p => foldHomoglyphs(p) === foldHomoglyphs(base));
+    return match(target ?? path, 'non-ascii', 0, 'likely', `${path} contains non-ASCII characters${target ? `, rendering like ${target}` : ''}`);
+  }
+
+  for (const prefix of options.internalPrefixes ?? []) {
+    if (path === prefix || path.startsWith(`${prefix}/`)) return null;
+    const candidate = path.split('/').slice(0, prefix.split('/').length).join('/');
+    const distance = editDistance(candidate.toLowerCase(), prefix.toLowerCase());
+    if (foldHomoglyphs(candidate) === foldHomoglyphs(prefix) || (distance > 0 && distance <= 2)) {
+      return match(prefix, 'internal-lookalike', distance, 'likely', `${path} looks like the internal prefix ${prefix} but isn't under it`);
+    }
+  }
+
+  let best: TyposquatMatch | null = null;
+  const consider = (m: TyposquatMatch) => {
+    if (!best || (m.confidence === 'likely' && best.confidence !== 'likely') || (m.confidence === best.confidence && m.distance < best.distance)) best = m;
+  };
+
+  for (const target of popular) {
+    if (foldHomoglyphs(base) === foldHomoglyphs(target)) {
+      consider(match(target, 'homoglyph', editDistance(base, target), 'likely', `${path} differs from ${target} only by lookalike characters or separators`));
+      continue;
+    }
+    // Siblings under the owner of the target (jackc/pgio next to jackc/pgx) are that owner's own modules
+    const { namespace, repo } = splitRepository(base);
+    const { namespace: tNamespace, repo: tRepo } = splitRepository(target);
+    if (!repo || !tRepo || namespace === tNamespace) continue;
+    const ownerDistance = editDistance(namespace, tNamespace);
+    const host = namespace.split('/')[0];
+    const sameHost = host === tNamespace.split('/')[0];
+
+    if (repo === tRepo) {
+      // Same repository name under a lookalike owner: github.com/sirupsen/logrus vs github.com/sirupsn/logrus
+      if (ownerDistance <= 2) {
+        consider(match(target, 'owner-lookalike', ownerDistance, 'likely', `${path} has the same name as ${target} under a lookalike owner`));
+      } else if (sameHost && repo.length >= 6) {
+        // Short names (cli, mux, errors) are reused too often to mean anything
+        consider(match(target, 'owner-lookalike', ownerDistance, 'possible', `${path} has the same repository name as ${target} under a different owner — a fork?`));
+      }
+      continue;
+    }
+    const distance = editDistance(repo, tRepo);
+    if (distance > repoThreshold(tRepo)) continue;
+    if (ownerDistance <= 2) {
+      consider(match(target, 'edit-distance', distance + ownerDistance, 'likely', `${path} is ${distance + ownerDistance} edit${distance + ownerDistance === 1 ? '' : 's'} away from ${target}`));
+    } else if (sameHost) {
+      consider(match(target, 'edit-distance', distance, 'possible', `${path} has a repository name ${distance} edit${distance === 1 ? '' : 's'} away from ${target}'s, under another owner`));
+    }
+  }
+  return best;
+}
+
+/**
+ * Modules required in the working tree's go.mod files that weren't required
+ * at base. Without a base, every required module is returned.
+ */
+export function newModules(projectRoot: string, base?: string): NewModule[] {
+  const result: NewModule[] = [];
+  for (const mod of findGoModules(projectRoot)) {
+    const previous = base ? readFileAtRef(projectRoot, base, mod.goModFile) : null;
+    const before = new Set((previous ? parseGoMod(previous).require : []).map(r => r.path));
+    const add = (r: GoRequire) => result.push({ path: r.path, version: r.version, goModFile: mod.goModFile, line: r.line });
+    for (const r of mod.mod.require) {
+      if (!base || !before.has(r.path)) add(r);
+    }
+  }
+  return result;
+}
+
+export function checkTyposquats(projectRoot: string, base: string | undefined, options: TyposquatOptions = {}): TyposquatReport {
+  const checked = newModules(projectRoot, base);
+  // Paths in go.sum also reach the build even if go.mod hasn't been updated by hand
+  const sumModules = base ? newSumModules(projectRoot, base) : [];
+  for (const extra of sumModules) {
+    if (!checked.some(c => c.path === extra.path)) checked.push(extra);
+  }
+
+  const matches: TyposquatReport['matches'] = [];
+  for (const mod of checked) {
+    const match = checkModulePath(mod.path, options);
+    if (match) matches.push({ ...match, goModFile: mod.goModFile, line: mod.line, version: mod.version });
+  }
+  matches.sort((a, b) => Number(b.confidence === 'likely') - Number(a.confidence === 'likely') || a.module.localeCompare(b.module));
+  return { projectRoot, base: base ?? null, checked, matches };
+}
+
+function newSumModules(projectRoot: string, base: string): NewModule[] {
+  const result: NewModule[] = [];
+  for (const mod of findGoModules(projectRoot)) {
+    const sumFile = mod.dir === '.' ? 'go.sum' : `${mod.dir}/go.sum`;
+    const abs = join(projectRoot, sumFile);
+    if (!existsSync(abs)) continue;
+    const parse = (content: string) => new Set(content.split('\n').map(l => l.split(' ')[0]).filter(Boolean));
+    const before = parse(readFileAtRef(projectRoot, base, sumFile) ?? '');
+    const lines = readFileSync(abs, 'utf-8').split('\n');
+    lines.forEach((line, i) => {
+      const [path, version] = line.split(' ');
+      if (path && !before.has(path) && !result.some(r => r.path === path)) {
+        result.push({ path, version: version?.replace(/\/go\.mod$/, '') ?? '', goModFile: relative(projectRoot, abs).split('\\').join('/'), line: i + 1 });
+      }
+    });
+  }
+  return result;
+}
@@ -0,0 +1,215 @@
+import { createHash } from 'crypto';
+import { existsSync, readFileSync, readdirSync, statSync } from 'fs';
+import { join, resolve } from 'path';
+import { readGoMod } from '../golang/modfile.js';
+import { readModulesTxt, type VendoredModule } from '../golang/vendor.js';
+import { findGoModuleRoot, goModCacheDir } from '../golang/toolchain.js';
+import { fetchModule } from '../remote/index.js';
+import { hashModuleDir, parseGoSum } from '../remote/sumdb.js';
+import { checkCancelled } from '../utils/progress.js';
+import { escapeModulePath } from './confusion.js';
+
+/**
+ * Verification of a vendor/ tree, stricter than `go build -mod=vendor`:
+ * vendor/modules.txt must agree with go.mod (every requirement and replace,
+ * versions and "## explicit" markers), every listed package must be there
+ * and nothing unlisted may be, and every vendored file must be
+ * byte-identical to the same file in the module's source — the module
+ * cache copy, or a download — whose h1: hash is checked against go.sum
+ * first. A hand-edited vendor tree builds fine and silently diverges from
+ * what go.sum promises; this catches it.
+ */
+
+export type VendorFindingKind =
+  | 'not-vendored'
+  | 'version-mismatch'
+  | 'not-explicit'
+  | 'not-required'
+  | 'replace-mismatch'
+  | 'missing-package'
+  | 'unlisted-package'
+  | 'modified'
+  | 'extra-file'
+  | 'missing-file'
+  | 'sum-mismatch'
+  | 'unverified';
+
+export interface VendorFinding {
+  kind: VendorFindingKind;
+  module?: string;
+  /** Path under vendor/ */
+  file?: string;
+  message: string;
+}
+
+export interface VendorReport {
+  projectRoot: string;
+  modules: number;
+  packages: number;
+  /** Vendored files compared with module sources */
+  filesChecked: number;
+  findings: VendorFinding[];
+}
+
+export interface VendorVerifyOptions {
+  /** Only the module cache and local replacements: no downloads */
+  offline?: boolean;
+  proxy?: string;
+  cacheDir?: string;
+  signal?: AbortSignal;
+}
+
+const sha256 = (file: string) => createHash('sha256').update(readFileSync(file)).digest('hex');
+
+function filesUnder(dir: string, prefix = ''): string[] {
+  if (!existsSync(dir)) return [];
+  const files: string[] = [];
+  for (const entry of readdirSync(join(dir, prefix), { withFileTypes: true })) {
+    const rel = prefix ? `${prefix}/${entry.name}` : entry.name;
+    if (entry.isDirectory()) files.push(...filesUnder(dir, rel));
+    else if (entry.isFile()) files.push(rel);
+  }
+  return files;
+}
+
+// Files `go mod vendor` copies for a package: everything but tests and files excluded from every build
+function vendoredGoFile(dir: string, name: string): boolean {
+  if (!name.endsWith('.go') || name.endsWith('_test.go') || name.startsWith('.') || name.startsWith('_')) return false;
+  const head = readFileSync(join(dir, name), 'utf-8').slice(0, 2048);
+  return !/^\/\/(go:build| \+build) ignore\s*$/m.test(head);
+}
+
+export async function verifyVendor(projectRoot: string, options: VendorVerifyOptions = {}): Promise<VendorReport> {
+  const moduleRoot = findGoModuleRoot(projectRoot);
+  const mod = moduleRoot ? readGoMod(moduleRoot) : null;
+  if (!moduleRoot || !mod) throw new Error('No go.mod found');
+  const manifest = readModulesTxt(moduleRoot);
+  if (!manifest) throw new Error('No vendor/modules.txt — run go mod vendor, or this project does not vendor');
+
+  const findings: VendorFinding[] = [];
+  const vendorDir = join(moduleRoot, 'vendor');
+  const byPath = new Map(manifest.filter(m => m.version !== null).map(m => [m.path, m]));
+
+  // modules.txt against go.mod, as go build -mod=vendor checks it
+  for (const req of mod.require) {
+    const entry = byPath.get(req.path);
+    if (!entry) findings.push({ kind: 'not-vendored', module: req.path, message: `go.mod requires ${req.path} ${req.version}, vendor/modules.txt does not list it` });
+    else if (entry.version !== req.version) findings.push({ kind: 'version-mismatch', module: req.path, message: `go.mod requires ${req.version}, vendor/modules.txt has ${entry.version}` });
+    else if (!entry.explicit) findings.push({ kind: 'not-explicit', module: req.path, message: 'required in go.mod but not marked "## explicit" in vendor/modules.txt' });
+  }
+  for (const entry of manifest) {
+    if (entry.explicit && !mod.require.some(r => r.path === entry.path)) {
+      findings.push({ kind: 'not-required', module: entry.path, message: 'marked "## explicit" in vendor/modules.txt but go.mod does not require it' });
+    }
+  }
+  for (const rep of mod.replace) {
+    const entry = manifest.find(m => m.path === rep.oldPath && (!rep.oldVersion || m.version === rep.oldVersion) && m.replacement);
+    if (!entry || entry.replacement!.path !== rep.newPath || (entry.replacement!.version ?? undefined) !== rep.newVersion) {
+      const target = `${rep.newPath}${rep.newVersion ? ` ${rep.newVersion}` : ''}`;
+      const used = manifest.some(m => m.path === rep.oldPath);
+      // Replacements of modules outside the build list don't appear in modules.txt
+      if (entry || used) findings.push({ kind: 'replace-mismatch', module: rep.oldPath, message: `go.mod replaces ${rep.oldPath} with ${target}, vendor/modules.txt ${entry ? `says ${entry.replacement!.path} ${entry.replacement!.version ?? ''}`.trim() : 'has no replacement'}` });
+    }
+  }
+  for (const entry of manifest.filter(m => m.replacement)) {
+    if (!mod.replace.some(r => r.oldPath === entry.path && r.newPath === entry.replacement!.path)) {
+      findings.push({ kind: 'replace-mismatch', module: entry.path, message: `vendor/modules.txt replaces ${entry.path} with ${entry.replacement!.path}, go.mod does not` });
+    }
+  }
+
+  // Listed packages against directories
+  const listed = new Set(manifest.flatMap(m => m.packages));
+  for (const entry of manifest) {
+    for (const pkg of entry.packages) {
+      if (!existsSync(join(vendorDir, pkg))) findings.push({ kind: 'missing-package', module: entry.path, file: pkg, message: `package ${pkg} is listed but vendor/${pkg} does not exist` });
+    }
+  }
+  const goDirs = new Set(filesUnder(vendorDir).filter(f => f.endsWith('.go')).map(f => f.slice(0, f.lastIndexOf('/'))));
+  for (const dir of [...goDirs].sort()) {
+    if (!listed.has(dir)) findings.push({ kind: 'unlisted-package', file: dir, message: `vendor/${dir} has Go files but vendor/modules.txt does not list the package` });
+  }
+
+  // Vendored files against the module source
+  const sums = existsSync(join(moduleRoot, 'go.sum')) ? parseGoSum(readFileSync(join(moduleRoot, 'go.sum'), 'utf-8')) : new Map<string, string>();
+  const cache = await goModCacheDir(moduleRoot, options.signal);
+  let filesChecked = 0;
+  for (const entry of manifest) {
+    if (entry.packages.length === 0 || !entry.version) continue;
+    checkCancelled(options.signal);
+    const source = await moduleSource(entry, moduleRoot, cache, sums, findings, options);
+    if (!source) continue;
+
+    // A package directory holds its own files and any embedded subdirectories, not other packages
+    for (const pkg of entry.packages) {
+      const rel = pkg === entry.path ? '' : pkg.slice(entry.path.length + 1);
+      const vendored = join(vendorDir, pkg);
+      const original = join(source, rel);
+      const own = (file: string) => {
+        const parts = file.split('/');
+        for (let i = 1; i < parts.length; i++) if (listed.has(`${pkg}/${parts.slice(0, i).join('/')}`)) return false;
+        return true;
+      };
+      const files = filesUnder(vendored).filter(own);
+      for (const file of files) {
+        filesChecked++;
+        const at = `${pkg}/${file}`;
+        if (!existsSync(join(original, file))) findings.push({ kind: 'extra-file', module: entry.path, file: at, message: `vendor/${at} is not in ${entry.path}@${entry.version}` });
+        else if (sha256(join(vendored, file)) !== sha256(join(original, file))) findings.push({ kind: 'modified', module: entry.path, file: at, message: `vendor/${at} differs from ${entry.path}@${entry.version}` });
+      }
+      if (existsSync(original) && statSync(original).isDirectory()) {
+        for (const name of readdirSync(original)) {
+          if (vendoredGoFile(original, name) && !existsSync(join(vendored, name))) {
+            findings.push({ kind: 'missing-file', module: entry.path, file: `${pkg}/${name}`, message: `vendor/${pkg}/${name} is missing` });
+          }
+        }
+      }
+    }
+  }
+
+  return {
+    projectRoot,
+    modules: manifest.filter(m => m.version !== null).length,
+    packages: listed.size,
+    filesChecked,
+    findings,
+  };
+}
+
+/** The source a vendored module was copied from, verified against go.sum where go.sum has it */
+async function moduleSource(entry: VendoredModule, moduleRoot: string, cache: string, sums: Map<string, string>, findings: VendorFinding[], options: VendorVerifyOptions): Promise<string | null> {
+  if (entry.replacement && !entry.replacement.version) {
+    const local = resolve(moduleRoot, entry.replacement.path);
+    if (existsSync(local)) return local;
+    findings.push({ kind: 'unverified', module: entry.path, message: `replacement directory ${entry.replacement.path} does not exist` });
+    return null;
+  }
+  const path = entry.replacement?.path ?? entry.path;
+  const version = entry.replacement?.version ?? entry.version!;
+
+  let dir: string | null = join(cache, `${escapeModulePath(path)}@${escapeModulePath(version)}`);
+  if (!existsSync(dir)) {
+    dir = null;
+    if (!options.offline) {
+      try {
+        dir = (await fetchModule(`${path}@${version}`, { proxy: options.proxy, cacheDir: options.cacheDir, signal: options.signal })).dir;
+      } catch (err) {
+        if (options.signal?.aborted) throw err;
+        findings.push({ kind: 'unverified', module: entry.path, message: `could not fetch ${path}@${version}: ${err instanceof Error ? err.message : err}` });
+        return null;
+      }
+    } else {
+      findings.push({ kind: 'unverified', module: entry.path, message: `${path}@${version} is not in the module cache (offline)` });
+      return null;
+    }
+  }
+
+  const expected = sums.get(`${path} ${version}`);
+  if (expected) {
+    const actual = hashModuleDir(dir, path, version);
+    if (actual !== expected) {
+      findings.push({ kind: 'sum-mismatch', module: entry.path, message: `${path}@${version} source hashes to ${actual}, go.sum expects ${expected} — the source can't vouch for vendor/` });
+      return null;
+    }
+  }
+  return dir;
+}
@@ -0,0 +1,129 @@
+import { analyzeImage, type ImageReport } from '../golang/image.js';
+import { workloadId, type Workload } from '../remote/kubernetes.js';
+import { mapLimit } from '../remote/compare.js';
+import { checkCancelled } from '../utils/progress.js';
+import { fetchAdvisory, queryVulnerabilities, type OsvAdvisory, type OsvOptions } from './osv.js';
+
+/**
+ * Which services ship a module: Kubernetes workloads mapped to the images
+ * their containers run, the Go binaries in those images and the modules
+ * built into each binary. Every image is pulled once however many
+ * workloads run it. The answer narrows to one module (--module), to one
+ * advisory (--vuln, by GO-, CVE or GHSA id, through OSV), or to every
+ * module version with a known vulnerability.
+ */
+
+export interface WorkloadImage {
+  image: string;
+  /** Null when the image couldn't be pulled or read */
+  report: ImageReport | null;
+  error: string | null;
+}
+
+export interface ShippedModule {
+  /** namespace/Kind/name */
+  workload: string;
+  container: string;
+  image: string;
+  /** Path of the binary in the image */
+  binary: string;
+  module: string;
+  version: string;
+  /** OSV advisories of this module version, when vulnerabilities were checked */
+  advisories: string[];
+}
+
+export interface WorkloadAudit {
+  workloads: Workload[];
+  images: WorkloadImage[];
+  /** Module versions shipped by each container, narrowed by the query */
+  shipped: ShippedModule[];
+  /** Advisories referenced by shipped, with summaries */
+  advisories: OsvAdvisory[];
+  warnings: string[];
+}
+
+export interface WorkloadAuditOptions {
+  /** Only this module, at any version or (path@version) one */
+  module?: string;
+  /** Only module versions affected by this advisory: GO-…, CVE-… or GHSA-… */
+  vuln?: string;
+  /** Only module versions with known vulnerabilities */
+  vulns?: boolean;
+  /** os/arch to pull from multi-platform images */
+  platform?: string;
+  osv?: OsvOptions;
+  /** Images pulled at once (default 2; each is held in memory while it's read) */
+  concurrency?: number;
+  signal?: AbortSignal;
+}
+
+export async function auditWorkloads(workloads: Workload[], options: WorkloadAuditOptions = {}): Promise<WorkloadAudit> {
+  const warnings: string[] = [];
+  const refs = [...new Set(workloads.flatMap(w => w.containers.map(c => c.image)))].sort();
+  const images = await mapLimit(refs, options.concurrency ?? 2, async (image): Promise<WorkloadImage> => {
+    checkCancelled(options.signal);
+    // Unrendered templates ({{ .Values.image }}) aren't references
+    if (image.includes('{{')) return { image, report: null, error: 'not a rendered image reference' };
+    try {
+      return { image, report: await analyzeImage(image, { platform: options.platform, signal: options.signal }), error: null };
+    } catch (err) {
+      if (options.signal?.aborted) throw err;
+      return { image, report: null, error: err instanceof Error ? err.message : String(err) };
+    }
+  });
+  const byRef = new Map(images.map(i => [i.image, i]));
+  for (const image of images) if (image.error) warnings.push(`${image.image}: ${image.error}`);
+
+  const [wantedPath, wantedVersion] = options.module ? options.module.split('@') : [];
+  let shipped: ShippedModule[] = [];
+  for (const workload of workloads) {
+    for (const container of workload.containers) {
+      for (const binary of byRef.get(container.image)?.report?.binaries ?? []) {
+        for (const mod of binary.report.modules.slice(1)) {
+          if (wantedPath && (mod.path !== wantedPath || (wantedVersion && mod.version !== wantedVersion))) continue;
+          shipped.push({ workload: workloadId(workload), container: container.name, image: container.image, binary: binary.path, module: mod.path, version: mod.version, advisories: [] });
+        }
+      }
+    }
+  }
+
+  const advisories = new Map<string, OsvAdvisory>();
+  if (options.vuln || options.vulns) {
+    checkCancelled(options.signal);
+    const osv = { signal: options.signal, ...options.osv };
+    const versions = [...new Map(shipped.map(s => [`${s.module}@${s.version}`, { path: s.module, version: s.version }])).values()];
+    const ids = await queryVulnerabilities(versions, {
+      ...osv,
+      onPrivate: (hidden) => {
+        const paths = [...new Set(hidden.map(m => m.path))];
+        warnings.push(`${paths.length} private modules (GOPRIVATE) were not checked for vulnerabilities: ${paths.join(', ')}`);
+      },
+    });
+    for (const s of shipped) s.advisories = ids.get(`${s.module}@${s.version}`) ?? [];
+    const found = [...new Set(shipped.flatMap(s => s.advisories))].sort();
+    await mapLimit(found, 8, async (id) => {
+      try {
+        advisories.set(id, await fetchAdvisory(id, osv));
+      } catch (err) {
+        if (options.signal?.aborted) throw err;
+        advisories.set(id, { id, aliases: [], summary: null, url: `https://osv.dev/vulnerability/${id}` });
+      }
+    });
+    shipped = shipped.filter(s => s.advisories.length > 0);
+    if (options.vuln) {
+      const wanted = options.vuln.toUpperCase();
+      const matches = (id: string) => id === wanted || advisories.get(id)!.aliases.some(a => a.toUpperCase() === wanted);
+      shipped = shipped.filter(s => s.advisories.some(matches)).map(s => ({ ...s, advisories: s.advisories.filter(matches) }));
+    }
+  }
+
+  const referenced = new Set(shipped.flatMap(s => s.advisories));
+  return {
+    workloads,
+    images,
+    shipped: shipped.sort((a, b) => a.workload.localeCompare(b.workload) || a.container.localeCompare(b.container) || a.module.localeCompare(b.module)),
+    advisories: [...advisories.values()].filter(a => referenced.has(a.id)).sort((a, b) => a.id.localeCompare(b.id)),
+    warnings,
+  };
+}
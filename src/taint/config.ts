@@ -0,0 +1,128 @@
+import { readFileSync } from 'fs';
+
+/**
+ * Taint specifications. Functions are named by import path plus the
+ * function or "Type.Method" name:
+ *
+ *   { "package": "database/sql", "name": "DB.Query", "args": [0] }
+ */
+
+export interface TaintSource {
+  package: string;
+  /** Calls to this function or method return tainted data */
+  name?: string;
+  /** Parameters of this type are tainted (http handlers' *http.Request) */
+  type?: string;
+  description?: string;
+}
+
+export interface TaintSink {
+  package: string;
+  name: string;
+  /** Argument positions that must not be tainted (default: all) */
+  args?: number[];
+  /** Finding category, e.g. sql-injection */
+  category: string;
+}
+
+export interface TaintSanitizer {
+  package: string;
+  /** The result of this call is clean */
+  name: string;
+}
+
+export interface TaintConfig {
+  sources: TaintSource[];
+  sinks: TaintSink[];
+  sanitizers: TaintSanitizer[];
+}
+
+const sqlSinks = (type: string): TaintSink[] => [
+  { package: 'database/sql', name: `${type}.Query`, args: [0], category: 'sql-injection' },
+  { package: 'database/sql', name: `${type}.QueryRow`, args: [0], category: 'sql-injection' },
+  { package: 'database/sql', name: `${type}.Exec`, args: [0], category: 'sql-injection' },
+  { package: 'database/sql', name: `${type}.Prepare`, args: [0], category: 'sql-injection' },
+  { package: 'database/sql', name: `${type}.QueryContext`, args: [1], category: 'sql-injection' },
+  { package: 'database/sql', name: `${type}.QueryRowContext`, args: [1], category: 'sql-injection' },
+  { package: 'database/sql', name: `${type}.ExecContext`, args: [1], category: 'sql-injection' },
+  { package: 'database/sql', name: `${type}.PrepareContext`, args: [1], category: 'sql-injection' },
+];
+
+export const DEFAULT_TAINT_CONFIG: TaintConfig = {
+  sources: [
+    { package: 'net/http', type: 'Request', description: 'HTTP request' },
+    { package: 'github.com/gin-gonic/gin', type: 'Context', description: 'gin request context' },
+    { package: 'github.com/labstack/echo/v4', type: 'Context', description: 'echo request context' },
+    { package: 'github.com/gofiber/fiber/v2', type: 'Ctx', description: 'fiber request context' },
+    { package: 'os', name: 'Getenv', description: 'environment variable' },
+    { package: 'os', name: 'LookupEnv', description: 'environment variable' },
+    { package: 'net/url', name: 'ParseQuery', description: 'query string' },
+  ],
+  sinks: [
+    ...sqlSinks('DB'),
+    ...sqlSinks('Tx'),
+    ...sqlSinks('Conn'),
+    { package: 'os/exec', name: 'Command', category: 'command-injection' },
+    { package: 'os/exec', name: 'CommandContext', args: [1, 2, 3, 4, 5, 6, 7, 8], category: 'command-injection' },
+    { package: 'syscall', name: 'Exec', category: 'command-injection' },
+    { package: 'os', name: 'Open', args: [0], category: 'path-traversal' },
+    { package: 'os', name: 'OpenFile', args: [0], category: 'path-traversal' },
+    { package: 'os', name: 'Create', args: [0], category: 'path-traversal' },
+    { package: 'os', name: 'ReadFile', args: [0], category: 'path-traversal' },
+    { package: 'os', name: 'WriteFile', args: [0], category: 'path-traversal' },
+    { package: 'os', name: 'Remove', args: [0], category: 'path-traversal' },
+    { package: 'os', name: 'RemoveAll', args: [0], category: 'path-traversal' },
+    { package: 'net/http', name: 'ServeFile', args: [2], category: 'path-traversal' },
+    { package: 'net/http', name: 'Get', args: [0], category: 'ssrf' },
+    { package: 'net/http', name: 'Post', args: [0], category: 'ssrf' },
+    { package: 'net/http', name: 'NewRequest', args: [1], category: 'ssrf' },
+    { package: 'net/http', name: 'NewRequestWithContext', args: [2], category: 'ssrf' },
+    { package: 'net/http', name: 'Redirect', args: [2], category: 'open-redirect' },
+    { package: 'html/template', name: 'HTML', args: [0], category: 'xss' },
+    { package: 'html/template', name: 'JS', args: [0], category: 'xss' },
+    { package: 'text/template', name: 'Template.Parse', args: [0], category: 'template-injection' },
+  ],
+  sanitizers: [
+    { package: 'strconv', name: 'Atoi' },
+    { package: 'strconv', name: 'ParseInt' },
+    { package: 'strconv', name: 'ParseUint' },
+    { package: 'strconv', name: 'ParseFloat' },
+    { package: 'strconv', name: 'ParseBool' },
+    { package: 'html', name: 'EscapeString' },
+    { package: 'html/template', name: 'HTMLEscapeString' },
+    { package: 'html/template', name: 'JSEscapeString' },
+    { package: 'net/url', name: 'QueryEscape' },
+    { package: 'net/url', name: 'PathEscape' },
+    { package: 'path/filepath', name: 'Base' },
+  ],
+};
+
+/**
+ * Load a taint config file, merged with the defaults unless it sets
+ * "builtin": false.
+ */
+export function loadTaintConfig(path?: string, builtin = true): TaintConfig {
+  const base = builtin ? DEFAULT_TAINT_CONFIG : { sources: [], sinks: [], sanitizers: [] };
+  if (!path) return base;
+
+  let raw: any;
+  try {
+    raw = JSON.parse(readFileSync(path, 'utf-8'));
+  } catch (err) {
+    throw new Error(`Could not read taint config ${path}: ${err instanceof Error ? err.message : err}`);
+  }
+  const merged = raw.builtin === false ? { sources: [], sinks: [], sanitizers: [] } : base;
+  for (const key of ['sources', 'sinks', 'sanitizers'] as const) {
+    if (raw[key] !== undefined && !Array.isArray(raw[key])) {
+      throw new Error(`Taint config ${path}: "${key}" must be an array`);
+    }
+  }
+  for (const sink of raw.sinks ?? []) {
+    if (!sink.package || !sink.name) throw new Error(`Taint config ${path}: every sink needs "package" and "name"`);
+  }
+  return {
+    sources: [...merged.sources, ...(raw.sources ?? [])],
+    sinks: [...merged.sinks, ...(raw.sinks ?? []).map((s: TaintSink) => ({ ...s, category: s.category ?? 'tainted-data' }))],
+    sanitizers: [...merged.sanitizers, ...(raw.sanitizers ?? [])],
+  };
+}
@@ -0,0 +1,39 @@
+import chalk from 'chalk';
+import type { TaintResult } from './index.js';
+
+export function formatTaintReport(result: TaintResult, limit: number): string {
+  const lines: string[] = [];
+
+  lines.push('');
+  lines.push(chalk.bold('Depwire Taint Flow'));
+  lines.push(chalk.dim(`  ${result.summary.functions} functions analyzed`));
+  lines.push('');
+
+  if (result.findings.length === 0) {
+    lines.push(chalk.green('  ✓ No source reaches a sink'));
+    lines.push('');
+    return lines.join('\n');
+  }
+
+  for (const f of result.findings.slice(0, limit)) {
+    const icon = f.approximate ? chalk.yellow('?') : chalk.red('✗');
+    lines.push(`${icon} ${chalk.bold(f.category)}  ${f.source} → ${f.sink}` + chalk.dim(`  ${f.file}:${f.line}`));
+    if (f.packages.length > 1) lines.push(chalk.dim(`    across ${f.packages.join(' → ')}`));
+    for (const s of f.path) {
+      lines.push(`    ${chalk.dim(`${s.file}:${s.line}`.padEnd(36))} ${s.note}` + chalk.dim(`  in ${s.function}`));
+    }
+    lines.push('');
+  }
+  if (result.findings.length > limit) {
+    lines.push(chalk.dim(`  … ${result.findings.length - limit} more (use --limit or --format json)`));
+    lines.push('');
+  }
+
+  const counts = Object.entries(result.summary.byCategory).map(([c, n]) => `${n} ${c}`).join(', ');
+  lines.push(`${result.findings.length} flow${result.findings.length === 1 ? '' : 's'}: ${counts}`);
+  if (result.findings.some(f => f.approximate)) {
+    lines.push(chalk.dim('? = relies on matching a method by name; the receiver type was not visible'));
+  }
+  lines.push('');
+  return lines.join('\n');
+}
@@ -0,0 +1,381 @@
+import type { Node } from 'web-tree-sitter';
+import { walk } from '../golang/source.js';
+import { loadGoProject } from '../golang/packages.js';
+import { fileQualifiers } from '../golang/references.js';
+import { loadGoCallGraph, functionFile, qualifiedTypeName, type GoCall, type GoCallGraph, type GoFunction } from '../golang/callgraph.js';
+import { DEFAULT_TAINT_CONFIG, type TaintConfig, type TaintSink, type TaintSource } from './config.js';
+import { logger } from '../utils/log.js';
+
+const log = logger('taint');
+
+/**
+ * Interprocedural taint tracking over the Go call graph. Each function gets
+ * a summary — which parameters flow to its results and to sinks, and
+ * whether it returns source data — and summaries are propagated to a
+ * fixpoint, so a request parameter read in one package and concatenated
+ * into a query three packages away is still reported.
+ *
+ * The analysis is flow-insensitive and conservative: calls it can't see
+ * into (external functions, unresolved methods) pass taint from their
+ * arguments and receiver to their result.
+ */
+
+export interface TaintStep {
+  function: string;
+  file: string;
+  line: number;
+  note: string;
+}
+
+export interface TaintFinding {
+  category: string;
+  source: string;
+  sink: string;
+  file: string;
+  line: number;
+  /** Function containing the sink call */
+  function: string;
+  /** From the source to the sink, one step per function boundary */
+  path: TaintStep[];
+  /** Packages the flow passes through, in order */
+  packages: string[];
+  /** Some step relied on name-only method resolution, or propagation was cut short (maxRounds) */
+  approximate: boolean;
+}
+
+export interface TaintResult {
+  projectRoot: string;
+  findings: TaintFinding[];
+  summary: { functions: number; findings: number; byCategory: Record<string, number> };
+}
+
+/** Taint labels on a value: "S:<source>" or "P<index>" (from a parameter), each with one witness path */
+type Labels = Map<string, { path: TaintStep[]; approximate: boolean }>;
+
+interface SinkHit {
+  sink: TaintSink;
+  path: TaintStep[];
+  approximate: boolean;
+}
+
+interface FunctionSummary {
+  returns: Labels;
+  /** "<param index>|<sink location>" → the sink the parameter reaches */
+  sinks: Map<string, SinkHit & { param: number }>;
+}
+
+export interface TaintOptions {
+  config?: TaintConfig;
+  /** Stop summary propagation after this many rounds (default: run to the fixpoint); findings are then approximate */
+  maxRounds?: number;
+  /** Call graph algorithm: syntactic (default) or pta */
+  algo?: string;
+  signal?: AbortSignal;
+}
+
+export async function analyzeTaint(projectRoot: string, options: TaintOptions = {}): Promise<TaintResult> {
+  const project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  for (const warning of warnings) log.warn(warning);
+  const findings = runTaint(graph, options.config ?? DEFAULT_TAINT_CONFIG, options.maxRounds);
+
+  const byCategory: Record<string, number> = {};
+  for (const f of findings) byCategory[f.category] = (byCategory[f.category] ?? 0) + 1;
+  return {
+    projectRoot,
+    findings,
+    summary: { functions: graph.functions.size, findings: findings.length, byCategory },
+  };
+}
+
+export function runTaint(graph: GoCallGraph, config: TaintConfig, maxRounds = Infinity): TaintFinding[] {
+  const summaries = new Map<string, FunctionSummary>();
+  for (const id of graph.functions.keys()) summaries.set(id, { returns: new Map(), sinks: new Map() });
+
+  // Summaries only gain labels, and there are finitely many (sources and parameters), so this terminates
+  let changed = true;
+  let round = 0;
+  for (; changed && round < maxRounds; round++) {
+    changed = false;
+    for (const fn of graph.functions.values()) {
+      const result = analyzeFunction(fn, graph, config, summaries);
+      const summary = summaries.get(fn.id)!;
+      for (const [label, witness] of result.returns) {
+        if (!summary.returns.has(label)) {
+          summary.returns.set(label, witness);
+          changed = true;
+        }
+      }
+      for (const [key, hit] of result.paramSinks) {
+        if (!summary.sinks.has(key)) {
+          summary.sinks.set(key, hit);
+          changed = true;
+        }
+      }
+    }
+  }
+
+  // Cut short, deeper call chains may be missing flows, so none of the findings is the whole story
+  const truncated = changed;
+  if (truncated) log.warn(`Taint propagation stopped after ${round} rounds before reaching a fixpoint; flows through deeper call chains may be missing`);
+
+  const findings = new Map<string, TaintFinding>();
+  for (const fn of graph.functions.values()) {
+    for (const finding of analyzeFunction(fn, graph, config, summaries).findings) {
+      const key = `${finding.source}|${finding.file}:${finding.line}|${finding.sink}`;
+      if (truncated) finding.approximate = true;
+      if (!findings.has(key)) findings.set(key, finding);
+    }
+  }
+  return [...findings.values()].sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+}
+
+const specId = (spec: { package: string; name?: string; type?: string }) => `${spec.package}.${spec.name ?? spec.type}`;
+
+function analyzeFunction(fn: GoFunction, graph: GoCallGraph, config: TaintConfig, summaries: Map<string, FunctionSummary>) {
+  const file = functionFile(graph, fn);
+  const qualifiers = fileQualifiers(file, graph.project);
+  const imported = new Set(qualifiers.values());
+  const vars = new Map<string, Labels>();
+  const returns: Labels = new Map();
+  const paramSinks = new Map<string, SinkHit & { param: number }>();
+  const findings: TaintFinding[] = [];
+  let grew = false;
+
+  const step = (node: Node, note: string): TaintStep => ({ function: fn.id, file: fn.file, line: node.startPosition.row + 1, note });
+
+  const taint = (name: string, labels: Labels) => {
+    if (labels.size === 0 || name === '_') return;
+    if (!vars.has(name)) vars.set(name, new Map());
+    const current = vars.get(name)!;
+    for (const [label, witness] of labels) {
+      if (!current.has(label)) {
+        current.set(label, witness);
+        grew = true;
+      }
+    }
+  };
+
+  const sourceLabels = (source: TaintSource, node: Node): Labels =>
+    new Map([[`S:${specId(source)}`, { path: [step(node, `source: ${source.description ?? specId(source)}`)], approximate: false }]]);
+
+  // Parameters start out carrying their own label; parameters of a source type are tainted
+  const seedParams = (names: string[], types: string[], node: Node, withLabels: boolean) => {
+    names.forEach((name, i) => {
+      if (withLabels) taint(name, new Map([[`P${i}`, { path: [], approximate: false }]]));
+      const source = config.sources.find(s => s.type && types[i] === specId(s));
+      if (source) taint(name, sourceLabels(source, node));
+    });
+  };
+  seedParams(fn.params, fn.paramTypes, fn.node, true);
+
+  const matchesSpec = (call: GoCall, spec: { package: string; name?: string }): 'exact' | 'approximate' | null => {
+    if (!spec.name) return null;
+    if (call.callee === specId(spec)) return 'exact';
+    // Method calls on receivers of unknown type: match by method name in files that import the package
+    const method = spec.name.includes('.') ? spec.name.split('.').pop()! : null;
+    if (method && (call.callee === `?.${method}` || (call.approximate && call.callee.endsWith(`.${method}`))) && imported.has(spec.package)) {
+      return 'approximate';
+    }
+    return null;
+  };
+
+  const union = (into: Labels, from: Labels, extra?: (w: { path: TaintStep[]; approximate: boolean }) => { path: TaintStep[]; approximate: boolean }) => {
+    for (const [label, witness] of from) {
+      if (!into.has(label)) into.set(label, extra ? extra(witness) : witness);
+    }
+  };
+
+  const reportSink = (sink: TaintSink, labels: Labels, node: Node, approximate: boolean, calleeSteps: TaintStep[] = []) => {
+    const sinkStep = step(node, `sink: ${specId(sink)}`);
+    for (const [label, witness] of labels) {
+      const path = [...witness.path, ...(calleeSteps.length > 0 ? calleeSteps : [sinkStep])];
+      const last = path[path.length - 1];
+      const isApproximate = approximate || witness.approximate;
+      if (label.startsWith('S:')) {
+        findings.push({
+          category: sink.category,
+          source: label.slice(2),
+          sink: specId(sink),
+          file: last.file,
+          line: last.line,
+          function: last.function,
+          path,
+          packages: [...new Set(path.map(s => graph.functions.get(s.function)?.package ?? s.function))],
+          approximate: isApproximate,
+        });
+      } else {
+        const param = parseInt(label.slice(1), 10);
+        const key = `${param}|${last.file}:${last.line}|${specId(sink)}`;
+        if (!paramSinks.has(key)) paramSinks.set(key, { sink, path, approximate: isApproximate, param });
+      }
+    }
+  };
+
+  const evaluate = (node: Node | null): Labels => {
+    const labels: Labels = new Map();
+    if (!node) return labels;
+    switch (node.type) {
+      case 'identifier':
+        return vars.get(node.text) ?? labels;
+      case 'func_literal':
+      case 'interpreted_string_literal':
+      case 'raw_string_literal':
+      case 'int_literal':
+      case 'float_literal':
+      case 'rune_literal':
+      case 'true':
+      case 'false':
+      case 'nil':
+        return labels;
+      case 'call_expression':
+        return evaluateCall(node);
+      case 'composite_literal':
+        return evaluate(node.childForFieldName('body'));
+      case 'selector_expression':
+        return evaluate(node.childForFieldName('operand'));
+    }
+    for (const child of node.namedChildren) {
+      if (child && !child.type.endsWith('_type') && child.type !== 'type_identifier') union(labels, evaluate(child));
+    }
+    return labels;
+  };
+
+  const evaluateCall = (node: Node): Labels => {
+    const args = (node.childForFieldName('arguments')?.namedChildren ?? []).filter((n): n is Node => n !== null);
+    const argLabels = args.map(arg => evaluate(arg));
+    const callee = node.childForFieldName('function');
+    const operand = callee?.type === 'selector_expression' ? callee.childForFieldName('operand') : null;
+    const receiverLabels = operand && !(operand.type === 'identifier' && qualifiers.has(operand.text) && !vars.has(operand.text))
+      ? evaluate(operand)
+      : new Map() as Labels;
+    const calls = graph.calleesOf(fn.file, node);
+    const result: Labels = new Map();
+
+    for (const call of calls) {
+      if (config.sanitizers.some(s => matchesSpec(call, s) === 'exact')) return new Map();
+    }
+
+    for (const call of calls) {
+      for (const source of config.sources) {
+        if (matchesSpec(call, source)) union(result, sourceLabels(source, node));
+      }
+      for (const sink of config.sinks) {
+        const match = matchesSpec(call, sink);
+        if (!match) continue;
+        const positions = sink.args ?? args.map((_a, i) => i);
+        for (const i of positions) {
+          if (argLabels[i]) reportSink(sink, argLabels[i], node, match === 'approximate');
+        }
+      }
+
+      const summary = call.external ? undefined : summaries.get(call.callee);
+      if (!summary) continue;
+      const boundary = (note: string) => step(node, note);
+      // Source data returned by the callee
+      for (const [label, witness] of summary.returns) {
+        if (label.startsWith('S:')) {
+          union(result, new Map([[label, {
+            path: [...witness.path, boundary(`returned from ${call.callee}`)],
+            approximate: witness.approximate || call.approximate,
+          }]]));
+        } else {
+          const param = parseInt(label.slice(1), 10);
+          if (argLabels[param]) union(result, argLabels[param], w => ({ path: w.path, approximate: w.approximate || call.approximate }));
+        }
+      }
+      // Arguments that reach a sink inside the callee
+      for (const hit of summary.sinks.values()) {
+        const labels = argLabels[hit.param];
+        if (!labels || labels.size === 0) continue;
+        reportSink(hit.sink, labels, node, hit.approximate || call.approximate, [boundary(`passed to ${call.callee}`), ...hit.path]);
+      }
+    }
+
+    // Calls we can't see into: any tainted input taints the result
+    if (calls.length === 0 || calls.some(c => c.external) || calls.every(c => c.approximate)) {
+      for (const labels of argLabels) union(result, labels);
+      union(result, receiverLabels);
+    }
+    return result;
+  };
+
+  const targetName = (node: Node | null): string | null => {
+    while (node && (node.type === 'selector_expression' || node.type === 'index_expression' || node.type === 'unary_expression' || node.type === 'parenthesized_expression')) {
+      node = node.type === 'parenthesized_expression' ? node.namedChildren[0] ?? null : node.childForFieldName('operand');
+    }
+    return node?.type === 'identifier' ? node.text : null;
+  };
+
+  const assign = (left: (Node | null)[], right: (Node | null)[]) => {
+    if (right.length === 1 && left.length > 1) {
+      // v, err := f(x) — every result carries the call's taint
+      const labels = evaluate(right[0]);
+      for (const target of left) {
+        const name = targetName(target);
+        if (name) taint(name, labels);
+      }
+      return;
+    }
+    left.forEach((target, i) => {
+      const name = targetName(target);
+      if (name) taint(name, evaluate(right[i] ?? null));
+    });
+  };
+
+  // Flow-insensitive: repeat until no variable gains a label
+  for (let pass = 0; pass < 5; pass++) {
+    grew = false;
+    findings.length = 0;
+    walk(fn.node, (node) => {
+      switch (node.type) {
+        case 'short_var_declaration':
+        case 'assignment_statement':
+          assign(node.childForFieldName('left')?.namedChildren ?? [], node.childForFieldName('right')?.namedChildren ?? []);
+          break;
+        case 'var_spec':
+          assign(node.childrenForFieldName('name'), node.childForFieldName('value')?.namedChildren ?? []);
+          break;
+        case 'range_clause': {
+          const labels = evaluate(node.childForFieldName('right'));
+          for (const target of node.childForFieldName('left')?.namedChildren ?? []) {
+            const name = targetName(target);
+            if (name) taint(name, labels);
+          }
+          break;
+        }
+        case 'return_statement': {
+          if (insideFuncLiteral(node, fn.node)) break;
+          for (const expr of node.namedChildren[0]?.namedChildren ?? []) union(returns, evaluate(expr));
+          break;
+        }
+        case 'func_literal': {
+          // Handlers defined inline: func(w http.ResponseWriter, r *http.Request) { ... }
+          const params = node.childForFieldName('parameters');
+          for (const param of params?.namedChildren ?? []) {
+            if (param?.type !== 'parameter_declaration') continue;
+            const type = qualifiedTypeName(param.childForFieldName('type'), fn.package, qualifiers);
+            const names = param.childrenForFieldName('name').filter((n): n is Node => n !== null).map(n => n.text);
+            seedParams(names, names.map(() => type ?? ''), param, false);
+          }
+          break;
+        }
+        case 'call_expression':
+          // Sinks are checked as calls are evaluated; nested calls and func literals are visited too
+          evaluate(node);
+          break;
+      }
+    });
+    if (!grew) break;
+  }
+
+  return { returns, paramSinks, findings };
+}
+
+function insideFuncLiteral(node: Node, root: Node): boolean {
+  for (let p = node.parent; p && p.id !== root.id; p = p.parent) {
+    if (p.type === 'func_literal') return true;
+  }
+  return false;
+}
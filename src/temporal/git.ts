@@ -1,4 +1,7 @@
-import { execSync } from 'child_process';
+import { execSync, execFileSync } from 'child_process';
+import { mkdtempSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
 import { CommitInfo } from './types.js';
 
 export async function getCommitLog(
@@ -119,3 +122,87 @@ export function isGitRepo(dir: string): boolean {
     return false;
   }
 }
+
+/** Resolve a ref (branch, tag, SHA, HEAD~3) to a full commit hash */
+export function resolveCommit(dir: string, ref: string): string {
+  if (ref.startsWith('-')) {
+    throw new Error(`Invalid git ref: ${ref}`);
+  }
+  try {
+    return execFileSync('git', ['rev-parse', '--verify', '--quiet', `${ref}^{commit}`], {
+      cwd: dir,
+      encoding: 'utf-8',
+      stdio: ['ignore', 'pipe', 'ignore'],
+    }).trim();
+  } catch {
+    throw new Error(`Unknown git ref: ${ref}`);
+  }
+}
+
+/**
+ * Check out a ref into a temporary worktree, run fn against the equivalent of
+ * dir inside it, then remove the worktree. The user's checkout is never touched.
+ */
+export async function withWorktree<T>(
+  dir: string,
+  ref: string,
+  fn: (worktreeDir: string) => Promise<T>
+): Promise<T> {
+  const commit = resolveCommit(dir, ref);
+  const prefix = execFileSync('git', ['rev-parse', '--show-prefix'], { cwd: dir, encoding: 'utf-8' }).trim();
+  const worktree = mkdtempSync(join(tmpdir(), 'depwire-worktree-'));
+
+  try {
+    execFileSync('git', ['worktree', 'add', '--detach', '--quiet', worktree, commit], { cwd: dir, stdio: 'ignore' });
+    return await fn(join(worktree, prefix));
+  } finally {
+    try {
+      execFileSync('git', ['worktree', 'remove', '--force', worktree], { cwd: dir, stdio: 'ignore' });
+    } catch {
+      rmSync(worktree, { recursive: true, force: true });
+      try { execFileSync('git', ['worktree', 'prune'], { cwd: dir, stdio: 'ignore' }); } catch { /* best effort */ }
+    }
+  }
+}
+
+/**
+ * Files changed relative to a base ref, as paths relative to dir (files outside
+ * dir are omitted). With no base, reports uncommitted changes against HEAD.
+ * Untracked files are included; deleted files are included too.
+ */
+export function getChangedFiles(dir: string, base?: string): string[] {
+  const git = (args: string[]) => execFileSync('git', args, { cwd: dir, encoding: 'utf-8' });
+  const files = new Set<string>();
+
+  const range = base ? `${resolveCommit(dir, base)}...HEAD` : 'HEAD';
+  const lists = [
+    git(['diff', '--name-only', '--relative', range]),
+    // Working tree changes count too when comparing against a base branch
+    ...(base ? [git(['diff', '--name-only', '--relative', 'HEAD'])] : []),
+    git(['ls-files', '--others', '--exclude-standard']),
+  ];
+  for (const list of lists) {
+    for (const line of list.split('\n')) {
+      if (line.trim()) files.add(line.trim());
+    }
+  }
+  return [...files].sort();
+}
+
+/**
+ * Contents of a file (relative to dir) at a ref, or null if it didn't exist
+ * there. Reads from the object database — no checkout needed.
+ */
+export function readFileAtRef(dir: string, ref: string, file: string): string | null {
+  const commit = resolveCommit(dir, ref);
+  try {
+    return execFileSync('git', ['show', `${commit}:./${file}`], {
+      cwd: dir,
+      encoding: 'utf-8',
+      stdio: ['ignore', 'pipe', 'ignore'],
+      maxBuffer: 64 * 1024 * 1024,
+    });
+  } catch {
+    return null;
+  }
+}
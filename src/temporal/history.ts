@@ -0,0 +1,227 @@
+import { execFileSync } from 'child_process';
+import { join } from 'path';
+import type { DirectedGraph } from 'graphology';
+import { CommitInfo, TemporalSnapshot } from './types.js';
+import { isGitRepo, resolveCommit, withWorktree } from './git.js';
+import { saveSnapshot, loadSnapshot, createSnapshot } from './snapshots.js';
+import { parseProject } from '../parser/index.js';
+import { buildGraph } from '../graph/index.js';
+import { exportToJSON } from '../graph/serializer.js';
+import { toPackageGraph } from '../graph/model.js';
+import { findCycles } from '../graph/algorithms.js';
+import { findGoModules } from '../golang/modules.js';
+import { checkCancelled, type ProgressCallback } from '../utils/progress.js';
+
+/**
+ * Dependency evolution at fixed time steps: one revision per step since a
+ * tag, commit or date, each parsed in a throwaway worktree (or read from the
+ * temporal snapshot cache) and summarized as a row of metrics.
+ */
+
+export interface HistoryStep {
+  count: number;
+  unit: 'day' | 'week' | 'month' | 'year';
+}
+
+export interface HistoryMetrics {
+  files: number;
+  symbols: number;
+  edges: number;
+  packages: number;
+  packageEdges: number;
+  /** Package import cycles (SCCs with more than one package) */
+  cycles: number;
+  /** Packages in the largest cycle */
+  largestCycle: number;
+  /** Direct and indirect go.mod requirements across the workspace's modules */
+  directModules: number;
+  indirectModules: number;
+}
+
+export interface HistoryPoint {
+  commit: string;
+  date: string;
+  message: string;
+  metrics: HistoryMetrics;
+}
+
+export interface HistoryOptions {
+  /** Tag, commit or date to start from */
+  since: string;
+  step: HistoryStep;
+  /** Snapshot cache; defaults to .depwire/temporal */
+  cacheDir?: string;
+  signal?: AbortSignal;
+  onProgress?: ProgressCallback;
+}
+
+export interface HistoryResult {
+  points: HistoryPoint[];
+  snapshots: TemporalSnapshot[];
+}
+
+// History snapshots carry their metrics so later runs can skip the parse
+type HistorySnapshot = TemporalSnapshot & { metrics?: HistoryMetrics };
+
+const UNITS: Record<string, HistoryStep['unit']> = {
+  d: 'day', day: 'day', days: 'day',
+  w: 'week', week: 'week', weeks: 'week',
+  m: 'month', mo: 'month', month: 'month', months: 'month',
+  y: 'year', year: 'year', years: 'year',
+};
+
+const ALIASES: Record<string, HistoryStep> = {
+  daily: { count: 1, unit: 'day' },
+  weekly: { count: 1, unit: 'week' },
+  monthly: { count: 1, unit: 'month' },
+  quarterly: { count: 3, unit: 'month' },
+  yearly: { count: 1, unit: 'year' },
+};
+
+/** "1month", "2w", "10 days", "quarterly" */
+export function parseStep(step: string): HistoryStep {
+  const text = step.trim().toLowerCase();
+  if (ALIASES[text]) return ALIASES[text];
+  const match = text.match(/^(\d+)?\s*([a-z]+)$/);
+  const unit = match ? UNITS[match[2]] : undefined;
+  const count = match?.[1] ? parseInt(match[1], 10) : 1;
+  if (!unit || count < 1) throw new Error(`Invalid step "${step}" (e.g. 1month, 2weeks, 10days, 1year)`);
+  return { count, unit };
+}
+
+export function advance(date: Date, step: HistoryStep): Date {
+  const next = new Date(date.getTime());
+  if (step.unit === 'day') next.setUTCDate(next.getUTCDate() + step.count);
+  else if (step.unit === 'week') next.setUTCDate(next.getUTCDate() + 7 * step.count);
+  else if (step.unit === 'month') next.setUTCMonth(next.getUTCMonth() + step.count);
+  else next.setUTCFullYear(next.getUTCFullYear() + step.count);
+  return next;
+}
+
+function git(dir: string, args: string[]): string {
+  return execFileSync('git', args, { cwd: dir, encoding: 'utf-8', maxBuffer: 64 * 1024 * 1024, stdio: ['ignore', 'pipe', 'ignore'] });
+}
+
+// Mainline commits, oldest first; committer dates so rebased work sorts where it landed
+function mainlineCommits(dir: string): CommitInfo[] {
+  const output = git(dir, ['log', '--first-parent', '--reverse', '--format=%H|%cI|%an|%s', 'HEAD']).trim();
+  if (!output) return [];
+  return output.split('\n').map((line) => {
+    const [hash, date, author, ...message] = line.split('|');
+    return { hash, date, author, message: message.join('|') };
+  });
+}
+
+/** The starting point: a ref's commit date, or a date */
+function resolveSince(dir: string, since: string): { date: Date; commit: string | null } {
+  try {
+    const commit = resolveCommit(dir, since);
+    return { date: new Date(git(dir, ['log', '-1', '--format=%cI', commit]).trim()), commit };
+  } catch {
+    const date = new Date(since);
+    if (Number.isNaN(date.getTime())) throw new Error(`"${since}" is neither a git ref nor a date`);
+    return { date, commit: null };
+  }
+}
+
+/**
+ * One commit per step: the last mainline commit at or before each step
+ * boundary, starting at `since` and always ending at HEAD.
+ */
+export function sampleSteps(commits: CommitInfo[], start: Date, step: HistoryStep, startCommit: string | null = null): CommitInfo[] {
+  const picked: CommitInfo[] = [];
+  const add = (commit: CommitInfo | undefined) => {
+    if (commit && !picked.includes(commit)) picked.push(commit);
+  };
+
+  add(startCommit ? commits.find(c => c.hash === startCommit) : undefined);
+  const end = commits.length ? new Date(commits[commits.length - 1].date) : start;
+  let i = 0;
+  let latest: CommitInfo | undefined;
+  for (let boundary = start; boundary <= end; boundary = advance(boundary, step)) {
+    while (i < commits.length && new Date(commits[i].date) <= boundary) latest = commits[i++];
+    add(latest);
+  }
+  add(commits[commits.length - 1]);
+  return picked;
+}
+
+function moduleCounts(dir: string): { direct: number; indirect: number } {
+  let direct = 0;
+  let indirect = 0;
+  for (const mod of findGoModules(dir)) {
+    for (const req of mod.mod.require) {
+      if (req.indirect) indirect++;
+      else direct++;
+    }
+  }
+  return { direct, indirect };
+}
+
+export function graphMetrics(graph: DirectedGraph, dir: string): HistoryMetrics {
+  const packageGraph = toPackageGraph(graph);
+  const cycles = findCycles(packageGraph).filter(c => c.length > 1);
+  const files = new Set<string>();
+  graph.forEachNode((_node, attrs) => files.add(attrs.filePath));
+  const modules = moduleCounts(dir);
+  return {
+    files: files.size,
+    symbols: graph.order,
+    edges: graph.size,
+    packages: packageGraph.order,
+    packageEdges: packageGraph.size,
+    cycles: cycles.length,
+    largestCycle: Math.max(0, ...cycles.map(c => c.length)),
+    directModules: modules.direct,
+    indirectModules: modules.indirect,
+  };
+}
+
+export async function analyzeHistory(projectRoot: string, options: HistoryOptions): Promise<HistoryResult> {
+  if (!isGitRepo(projectRoot)) throw new Error('Not a git repository. History needs git history.');
+  const cacheDir = options.cacheDir ?? join(projectRoot, '.depwire', 'temporal');
+  const since = resolveSince(projectRoot, options.since);
+  const commits = sampleSteps(mainlineCommits(projectRoot), since.date, options.step, since.commit);
+  if (commits.length === 0) throw new Error(`No commits since ${options.since}`);
+
+  const points: HistoryPoint[] = [];
+  const snapshots: TemporalSnapshot[] = [];
+  for (let i = 0; i < commits.length; i++) {
+    const commit = commits[i];
+    checkCancelled(options.signal);
+    options.onProgress?.({ phase: 'snapshot', completed: i, total: commits.length, item: commit.hash.slice(0, 8) });
+
+    let snapshot = loadSnapshot(commit.hash, cacheDir) as HistorySnapshot | null;
+    if (!snapshot?.metrics) {
+      snapshot = await withWorktree(projectRoot, commit.hash, async (dir) => {
+        const graph = buildGraph(await parseProject(dir, { signal: options.signal }), dir);
+        const created: HistorySnapshot = createSnapshot(exportToJSON(graph, dir), commit.hash, commit.date, commit.message, commit.author);
+        created.metrics = graphMetrics(graph, dir);
+        return created;
+      });
+      saveSnapshot(snapshot, cacheDir);
+    }
+    snapshots.push(snapshot);
+    points.push({ commit: commit.hash, date: commit.date, message: commit.message, metrics: snapshot.metrics! });
+  }
+  options.onProgress?.({ phase: 'snapshot', completed: commits.length, total: commits.length });
+
+  return { points, snapshots };
+}
+
+const CSV_COLUMNS: Array<keyof HistoryMetrics> = [
+  'files', 'symbols', 'edges', 'packages', 'packageEdges', 'cycles', 'largestCycle', 'directModules', 'indirectModules',
+];
+
+function csvField(value: string): string {
+  return /[",\n]/.test(value) ? `"${value.replace(/"/g, '""')}"` : value;
+}
+
+/** Metrics over time, one row per revision */
+export function historyCsv(points: HistoryPoint[]): string {
+  const rows = [['date', 'commit', ...CSV_COLUMNS, 'message'].join(',')];
+  for (const point of points) {
+    rows.push([point.date, point.commit, ...CSV_COLUMNS.map(c => String(point.metrics[c])), csvField(point.message)].join(','));
+  }
+  return rows.join('\n') + '\n';
+}
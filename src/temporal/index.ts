@@ -20,6 +20,7 @@ import { parseProject } from '../parser/index.js';
 import { buildGraph } from '../graph/index.js';
 import { exportToJSON } from '../graph/serializer.js';
 import { startTemporalServer } from '../viz/temporal-server.js';
+import { checkCancelled } from '../utils/progress.js';
 
 export async function runTemporalAnalysis(
   projectDir: string,
@@ -60,6 +61,10 @@ export async function runTemporalAnalysis(
       const commit = sampledCommits[i];
       const progress = `[${i + 1}/${sampledCommits.length}]`;
 
+      // Stop between commits so the catch below restores the original checkout
+      checkCancelled(options.signal);
+      options.onProgress?.({ phase: 'snapshot', completed: i, total: sampledCommits.length, item: commit.hash });
+
       const existingSnapshot = loadSnapshot(commit.hash, outputDir);
       if (existingSnapshot) {
         if (options.verbose) {
@@ -79,7 +84,7 @@ export async function runTemporalAnalysis(
 
       await checkoutCommit(projectDir, commit.hash);
 
-      const parsedFiles = await parseProject(projectDir);
+      const parsedFiles = await parseProject(projectDir, { signal: options.signal });
       const graph = buildGraph(parsedFiles, projectDir);
       const projectGraph = exportToJSON(graph, projectDir);
 
@@ -95,6 +100,8 @@ export async function runTemporalAnalysis(
       snapshots.push(snapshot);
     }
 
+    options.onProgress?.({ phase: 'snapshot', completed: sampledCommits.length, total: sampledCommits.length });
+
     await restoreOriginal(projectDir, originalBranch);
     if (hadStash) {
       await popStash(projectDir);
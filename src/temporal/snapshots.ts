@@ -1,7 +1,8 @@
-import { writeFileSync, readFileSync, mkdirSync, existsSync, readdirSync } from 'fs';
+import { readFileSync, mkdirSync, existsSync, readdirSync } from 'fs';
 import { join, resolve } from 'path';
 import { TemporalSnapshot } from './types.js';
 import { ProjectGraph } from '../parser/types.js';
+import { writeFileAtomic } from '../utils/files.js';
 
 export function saveSnapshot(
   snapshot: TemporalSnapshot,
@@ -18,7 +19,7 @@ export function saveSnapshot(
     throw new Error(`Path traversal attempt blocked: ${filepath}`);
   }
 
-  writeFileSync(filepath, JSON.stringify(snapshot, null, 2), 'utf-8');
+  writeFileAtomic(filepath, JSON.stringify(snapshot, null, 2));
 }
 
 export function loadSnapshot(
@@ -1,3 +1,5 @@
+import type { ProgressCallback } from '../utils/progress.js';
+
 export interface CommitInfo {
   hash: string;
   date: string;
@@ -49,4 +51,6 @@ export interface TemporalOptions {
   output?: string;
   verbose?: boolean;
   stats?: boolean;
+  signal?: AbortSignal;
+  onProgress?: ProgressCallback;
 }
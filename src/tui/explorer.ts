@@ -0,0 +1,503 @@
+import type { DirectedGraph } from 'graphology';
+import { packageOf, type SourcePosition } from '../graph/model.js';
+import type { ExportGraph, ExportLevel } from '../export/graph.js';
+import { formatMetricValue } from '../export/metrics.js';
+import { fitLine, visibleLength, type Terminal } from '../utils/terminal.js';
+import { normalizePrefix, prefixOf } from '../viz/collapse.js';
+import { fuzzyMatch } from './fuzzy.js';
+
+/**
+ * State and rendering of `depwire tui`, kept free of terminal I/O: the
+ * command feeds it keypresses and writes the frames it renders. Three panes
+ * — every package (or file) with its metric columns, and the dependencies
+ * and dependents of the selected one — with fuzzy search, sorting by any
+ * column, a jump history for walking import chains, and path prefixes
+ * collapsed into one node.
+ */
+
+export interface ExplorerEdge {
+  node: string;
+  /** Symbol references behind the edge */
+  weight: number;
+  /** The first reference, for jump-to-source */
+  at?: SourcePosition;
+}
+
+export interface ExplorerNode {
+  id: string;
+  label: string;
+  values: Record<string, number>;
+  /** Where jump-to-source goes for the node itself */
+  source?: SourcePosition;
+  /** Set on a node standing for every node under a path prefix */
+  collapsed?: { prefix: string; members: number };
+}
+
+export interface ExplorerData {
+  name: string;
+  level: ExportLevel;
+  nodes: ExplorerNode[];
+  columns: string[];
+  dependencies: Map<string, ExplorerEdge[]>;
+  dependents: Map<string, ExplorerEdge[]>;
+}
+
+export interface Key {
+  name?: string;
+  sequence?: string;
+  ctrl?: boolean;
+  shift?: boolean;
+}
+
+export type ExplorerAction = { type: 'quit' } | { type: 'open'; location: SourcePosition } | null;
+
+type PaneId = 'nodes' | 'dependencies' | 'dependents';
+const PANES: PaneId[] = ['nodes', 'dependencies', 'dependents'];
+
+/**
+ * The explorer's data from the symbol graph and the export graph built from
+ * it: metric columns from numeric node attributes (set by applyMetric), and
+ * jump-to-source through each edge's witness.
+ */
+export function buildExplorerData(symbolGraph: DirectedGraph, graph: ExportGraph, options: { name: string; level: ExportLevel; metrics?: string[] }): ExplorerData {
+  const nodeOf = (filePath: string) => (options.level === 'file' ? filePath : packageOf(filePath));
+  const sources = new Map<string, { location: SourcePosition; symbols: number }>();
+  const symbolsPerFile = new Map<string, number>();
+  symbolGraph.forEachNode((_node, attrs) => symbolsPerFile.set(attrs.filePath, (symbolsPerFile.get(attrs.filePath) ?? 0) + 1));
+  for (const [file, symbols] of symbolsPerFile) {
+    // A package opens at its largest file
+    const node = nodeOf(file);
+    if ((sources.get(node)?.symbols ?? -1) < symbols) sources.set(node, { location: { file, line: 1 }, symbols });
+  }
+
+  const columns = ['in', 'out', ...(options.level === 'package' ? ['files'] : []), 'symbols', ...(options.metrics ?? [])];
+  const dependencies = new Map<string, ExplorerEdge[]>();
+  const dependents = new Map<string, ExplorerEdge[]>();
+  const nodes: ExplorerNode[] = graph.mapNodes((node, attrs) => {
+    const outgoing = graph.mapOutEdges(node, (_edge, e, _source, target) => ({ node: target, weight: e.weight, at: e.witness }))
+      .filter(e => e.node !== node);
+    const incoming = graph.mapInEdges(node, (_edge, e, source) => ({ node: source, weight: e.weight, at: e.witness }))
+      .filter(e => e.node !== node);
+    const byWeight = (a: ExplorerEdge, b: ExplorerEdge) => b.weight - a.weight || a.node.localeCompare(b.node);
+    dependencies.set(node, outgoing.sort(byWeight));
+    dependents.set(node, incoming.sort(byWeight));
+    const values: Record<string, number> = { in: incoming.length, out: outgoing.length, files: attrs.files, symbols: attrs.symbols };
+    for (const metric of options.metrics ?? []) {
+      if (typeof attrs[metric] === 'number') values[metric] = attrs[metric] as number;
+    }
+    return { id: node, label: attrs.label, values, source: sources.get(node)?.location };
+  });
+  return { name: options.name, level: options.level, nodes, columns, dependencies, dependents };
+}
+
+// Columns that still mean something summed over a collapsed prefix
+const ADDITIVE = ['files', 'symbols', 'loc', 'churn', 'vulns'];
+
+function collapsedId(prefix: string): string {
+  return prefix === '.' ? './…' : `${prefix}/…`;
+}
+
+// The member's label with the part of its id below the prefix cut off, so a
+// collapsed internal/store reads github.com/org/x/internal/store when labels are import paths
+function collapsedLabel(node: ExplorerNode, prefix: string, name: string): string {
+  if (prefix === '.') return name;
+  const rest = node.id.slice(prefix.length);
+  if (!rest) return node.label;
+  return node.label.endsWith(rest) && node.label.length > rest.length ? node.label.slice(0, -rest.length) : prefix;
+}
+
+/**
+ * The explorer's data with every node under a path prefix folded into one,
+ * as in the viz: edges into and out of the prefix merged with their weights
+ * summed, edges inside it dropped, and the columns that add up summed.
+ */
+export function collapseExplorerData(data: ExplorerData, prefixes: string[], ellipsis = '…'): ExplorerData {
+  const active = [...new Set(prefixes.map(normalizePrefix).filter(Boolean))];
+  if (active.length === 0) return data;
+
+  const nodeOf = new Map<string, string>();
+  const groups = new Map<string, ExplorerNode>();
+  const nodes: ExplorerNode[] = [];
+  for (const node of data.nodes) {
+    const prefix = prefixOf(node.id, active);
+    if (!prefix) {
+      nodeOf.set(node.id, node.id);
+      nodes.push({ ...node, values: { ...node.values } });
+      continue;
+    }
+    const id = collapsedId(prefix);
+    nodeOf.set(node.id, id);
+    let group = groups.get(id);
+    if (!group) {
+      group = { id, label: `${collapsedLabel(node, prefix, data.name)}/${ellipsis}`, values: {}, source: node.source, collapsed: { prefix, members: 0 } };
+      groups.set(id, group);
+      nodes.push(group);
+    }
+    group.collapsed!.members++;
+    for (const column of ADDITIVE) {
+      if (node.values[column] !== undefined) group.values[column] = (group.values[column] ?? 0) + node.values[column];
+    }
+  }
+
+  const merge = (edges: Map<string, ExplorerEdge[]>, from: string, to: ExplorerEdge[]) => {
+    const id = nodeOf.get(from)!;
+    const merged = new Map((edges.get(id) ?? []).map(e => [e.node, e]));
+    for (const edge of to) {
+      const node = nodeOf.get(edge.node) ?? edge.node;
+      if (node === id) continue;
+      const existing = merged.get(node);
+      if (existing) existing.weight += edge.weight;
+      else merged.set(node, { ...edge, node });
+    }
+    edges.set(id, [...merged.values()]);
+  };
+  const dependencies = new Map<string, ExplorerEdge[]>();
+  const dependents = new Map<string, ExplorerEdge[]>();
+  for (const node of data.nodes) {
+    merge(dependencies, node.id, data.dependencies.get(node.id) ?? []);
+    merge(dependents, node.id, data.dependents.get(node.id) ?? []);
+  }
+  const byWeight = (a: ExplorerEdge, b: ExplorerEdge) => b.weight - a.weight || a.node.localeCompare(b.node);
+  for (const node of nodes) {
+    const outgoing = (dependencies.get(node.id) ?? []).sort(byWeight);
+    const incoming = (dependents.get(node.id) ?? []).sort(byWeight);
+    dependencies.set(node.id, outgoing);
+    dependents.set(node.id, incoming);
+    node.values.in = incoming.length;
+    node.values.out = outgoing.length;
+  }
+  return { ...data, nodes, dependencies, dependents };
+}
+
+// One level up from a path, or null at the top
+function parentPrefix(path: string): string | null {
+  const slash = path.lastIndexOf('/');
+  return slash > 0 ? path.slice(0, slash) : null;
+}
+
+export class Explorer {
+  private data: ExplorerData;
+  private byId = new Map<string, ExplorerNode>();
+  private collapsed: string[] = [];
+  private visible: Array<{ node: ExplorerNode; positions: number[] }> = [];
+  private query = '';
+  private searching = false;
+  /** Index into ['name', ...columns] */
+  private sortColumn = 1;
+  private descending = true;
+  private focus: PaneId = 'nodes';
+  private readonly cursor: Record<PaneId, number> = { nodes: 0, dependencies: 0, dependents: 0 };
+  private readonly scroll: Record<PaneId, number> = { nodes: 0, dependencies: 0, dependents: 0 };
+  private readonly history: string[] = [];
+  private message = '';
+  /** Rows per pane at the last render, for paging */
+  private rows: Record<PaneId, number> = { nodes: 10, dependencies: 5, dependents: 5 };
+
+  constructor(private readonly base: ExplorerData, private readonly terminal: Terminal, collapsed: string[] = []) {
+    this.data = base;
+    this.collapseTo(collapsed);
+  }
+
+  /** Show a line in the status bar until the next key */
+  notify(message: string): void {
+    this.message = message;
+  }
+
+  private get sortKey(): string {
+    return ['name', ...this.data.columns][this.sortColumn];
+  }
+
+  private refresh(keep?: string): void {
+    const matches: Array<{ node: ExplorerNode; positions: number[]; score: number }> = [];
+    for (const node of this.data.nodes) {
+      const match = fuzzyMatch(this.query, node.label);
+      if (match) matches.push({ node, ...match });
+    }
+    const key = this.sortKey;
+    const direction = this.descending ? -1 : 1;
+    matches.sort((a, b) => {
+      // While searching, relevance first
+      if (this.query && a.score !== b.score) return b.score - a.score;
+      const order = key === 'name' ? a.node.label.localeCompare(b.node.label) : (a.node.values[key] ?? -Infinity) - (b.node.values[key] ?? -Infinity);
+      return direction * order || a.node.label.localeCompare(b.node.label);
+    });
+    this.visible = matches;
+    const index = keep ? this.visible.findIndex(v => v.node.id === keep) : -1;
+    this.cursor.nodes = Math.max(0, index);
+    this.resetEdgePanes();
+  }
+
+  // Rebuild the view for a new set of collapsed prefixes, keeping the selection on keep
+  private collapseTo(prefixes: string[], keep?: string): void {
+    this.collapsed = [...new Set(prefixes.map(normalizePrefix).filter(Boolean))];
+    this.data = collapseExplorerData(this.base, this.collapsed, this.terminal.unicode ? '…' : '...');
+    this.byId = new Map(this.data.nodes.map(n => [n.id, n]));
+    this.refresh(keep && this.viewId(keep));
+  }
+
+  /** The node showing id: itself, or the collapsed prefix it is under */
+  private viewId(id: string): string {
+    if (this.byId.has(id)) return id;
+    const prefix = prefixOf(id, this.collapsed);
+    return prefix ? collapsedId(prefix) : id;
+  }
+
+  // Fold the selected node's directory into one node; on a collapsed node, go a level up
+  private collapse(): void {
+    const selected = this.selected;
+    if (!selected) return;
+    const from = selected.collapsed?.prefix ?? selected.id;
+    const prefix = parentPrefix(from);
+    if (!prefix) {
+      this.message = `Nothing above ${from} to collapse`;
+      return;
+    }
+    this.collapseTo([...this.collapsed.filter(p => p !== selected.collapsed?.prefix), prefix], collapsedId(prefix));
+  }
+
+  private expand(): void {
+    const prefix = this.selected?.collapsed?.prefix;
+    if (!prefix) {
+      this.message = 'Not a collapsed node (c collapses one)';
+      return;
+    }
+    const member = this.base.nodes.find(n => prefixOf(n.id, [prefix]));
+    this.collapseTo(this.collapsed.filter(p => p !== prefix), member?.id);
+  }
+
+  private resetEdgePanes(): void {
+    for (const pane of ['dependencies', 'dependents'] as const) {
+      this.cursor[pane] = 0;
+      this.scroll[pane] = 0;
+    }
+  }
+
+  /** The node the edge panes describe */
+  get selected(): ExplorerNode | null {
+    return this.visible[this.cursor.nodes]?.node ?? null;
+  }
+
+  private edges(pane: 'dependencies' | 'dependents'): ExplorerEdge[] {
+    const selected = this.selected;
+    return selected ? (this.data[pane].get(selected.id) ?? []) : [];
+  }
+
+  private length(pane: PaneId): number {
+    return pane === 'nodes' ? this.visible.length : this.edges(pane).length;
+  }
+
+  private move(delta: number): void {
+    const pane = this.focus;
+    const length = this.length(pane);
+    if (length === 0) return;
+    this.cursor[pane] = Math.min(length - 1, Math.max(0, this.cursor[pane] + delta));
+    if (pane === 'nodes') this.resetEdgePanes();
+  }
+
+  /** Select a node, clearing the search if it's filtered out */
+  private select(id: string): void {
+    const node = this.viewId(id);
+    if (!this.visible.some(v => v.node.id === node)) this.query = '';
+    this.refresh(node);
+  }
+
+  private jump(): void {
+    if (this.focus === 'nodes') {
+      if (this.length('dependencies') > 0) this.focus = 'dependencies';
+      else if (this.length('dependents') > 0) this.focus = 'dependents';
+      return;
+    }
+    const edge = this.edges(this.focus)[this.cursor[this.focus]];
+    const selected = this.selected;
+    if (!edge || !selected) return;
+    this.history.push(selected.id);
+    this.select(edge.node);
+  }
+
+  private back(): void {
+    const previous = this.history.pop();
+    if (previous) this.select(previous);
+    else this.message = 'Nothing to go back to';
+  }
+
+  private open(): ExplorerAction {
+    let location = this.selected?.source;
+    if (this.focus !== 'nodes') {
+      // The reference that creates the edge, else the other end itself
+      const edge = this.edges(this.focus)[this.cursor[this.focus]];
+      location = edge?.at ?? (edge ? this.byId.get(edge.node)?.source : undefined);
+    }
+    if (!location) {
+      this.message = 'No source location';
+      return null;
+    }
+    return { type: 'open', location };
+  }
+
+  handleKey(str: string | undefined, key: Key): ExplorerAction {
+    this.message = '';
+    if (key.ctrl && key.name === 'c') return { type: 'quit' };
+
+    // Navigation works while typing a search, like in fzf
+    switch (key.name) {
+      case 'up': this.move(-1); return null;
+      case 'down': this.move(1); return null;
+      case 'pageup': this.move(-this.rows[this.focus]); return null;
+      case 'pagedown': this.move(this.rows[this.focus]); return null;
+      case 'tab':
+        this.focus = PANES[(PANES.indexOf(this.focus) + (key.shift ? PANES.length - 1 : 1)) % PANES.length];
+        return null;
+    }
+
+    if (this.searching) {
+      if (key.name === 'return' || key.name === 'enter') {
+        this.searching = false;
+      } else if (key.name === 'escape') {
+        this.searching = false;
+        this.query = '';
+        this.refresh(this.selected?.id);
+      } else if (key.name === 'backspace') {
+        this.query = this.query.slice(0, -1);
+        this.refresh();
+      } else if (str && str.length === 1 && str >= ' ' && !key.ctrl) {
+        this.query += str;
+        this.refresh();
+      }
+      return null;
+    }
+
+    switch (key.name === 'return' || key.name === 'enter' ? 'enter' : str ?? key.name) {
+      case 'q':
+        return { type: 'quit' };
+      case '/':
+        this.searching = true;
+        this.focus = 'nodes';
+        return null;
+      case 'j': this.move(1); return null;
+      case 'k': this.move(-1); return null;
+      case 'g': this.move(-Infinity); return null;
+      case 'G': this.move(Infinity); return null;
+      case 'l': this.focus = this.focus === 'nodes' ? 'dependencies' : this.focus; return null;
+      case 'h': this.focus = 'nodes'; return null;
+      case 'enter': this.jump(); return null;
+      case 'b': this.back(); return null;
+      case 'c': this.collapse(); return null;
+      case 'x': this.expand(); return null;
+      case 's':
+        this.sortColumn = (this.sortColumn + 1) % (this.data.columns.length + 1);
+        this.descending = this.sortKey !== 'name';
+        this.refresh(this.selected?.id);
+        return null;
+      case 'S':
+        this.descending = !this.descending;
+        this.refresh(this.selected?.id);
+        return null;
+      case 'o':
+      case 'e':
+        return this.open();
+    }
+    if (key.name === 'backspace') this.back();
+    else if (key.name === 'escape' && this.query) {
+      this.query = '';
+      this.refresh(this.selected?.id);
+    }
+    return null;
+  }
+
+  /** The whole screen, exactly `height` lines of at most `width` visible characters */
+  render(width: number, height: number): string[] {
+    const { color, unicode } = this.terminal;
+    const bar = unicode ? '│' : '|';
+    const arrow = this.descending ? (unicode ? '↓' : 'v') : (unicode ? '↑' : '^');
+    const kind = this.data.level === 'file' ? 'files' : 'packages';
+    const lines: string[] = [];
+
+    lines.push(fitLine(`${color.bold(`Depwire TUI: ${this.data.name}`)}  ${color.dim(`${this.visible.length}/${this.data.nodes.length} ${kind}, sorted by ${this.sortKey} ${arrow}`)}`, width, unicode));
+    lines.push(fitLine(this.searching || this.query
+      ? `${color.cyan('/')} ${this.query}${this.searching ? color.inverse(' ') : ''}`
+      : color.dim('/ search  tab pane  enter follow  b back  c/x collapse/expand  o open  s/S sort  q quit'), width, unicode));
+
+    const body = Math.max(4, height - 3);
+    const leftWidth = Math.max(30, Math.floor(width * 0.55));
+    const rightWidth = Math.max(10, width - leftWidth - 1);
+    const left = this.renderNodes(leftWidth, body);
+    const topHeight = Math.floor(body / 2);
+    const right = [
+      ...this.renderEdges('dependencies', rightWidth, topHeight),
+      ...this.renderEdges('dependents', rightWidth, body - topHeight),
+    ];
+    for (let i = 0; i < body; i++) {
+      lines.push(`${pad(left[i] ?? '', leftWidth)}${color.dim(bar)}${right[i] ?? ''}`);
+    }
+
+    const selected = this.selected;
+    const where = selected?.collapsed ? `  ${selected.collapsed.members} ${kind} collapsed (x expands)` : selected?.source ? `  ${selected.source.file}` : '';
+    const status = this.message || (selected ? `${selected.label}${where ? color.dim(where) : ''}` : `No ${kind} match "${this.query}"`);
+    lines.push(fitLine(this.message ? color.yellow(status) : status, width, unicode));
+    return lines;
+  }
+
+  private renderNodes(width: number, height: number): string[] {
+    const { color, unicode } = this.terminal;
+    const columns = this.data.columns;
+    const cell = 8;
+    const labelWidth = Math.max(10, width - columns.length * cell - 1);
+    const header = pad(' name', labelWidth) + columns.map(c => pad(c === this.sortKey ? `${c}*` : c, cell, true)).join('');
+    const rows = height - 1;
+    this.rows.nodes = rows;
+    const start = this.clampScroll('nodes', rows);
+
+    const lines = [fitLine(this.paneTitle('nodes', header), width, unicode)];
+    for (const { node, positions } of this.visible.slice(start, start + rows)) {
+      const label = fitLine(` ${highlight(node.label, positions, color.yellow)}`, labelWidth - 1, unicode);
+      const values = columns.map(c => pad(node.values[c] === undefined ? '-' : formatMetricValue(node.values[c]), cell, true)).join('');
+      const row = `${pad(label, labelWidth)}${values}`;
+      lines.push(this.mark('nodes', this.visible[this.cursor.nodes]?.node === node, fitLine(row, width, unicode)));
+    }
+    return lines;
+  }
+
+  private renderEdges(pane: 'dependencies' | 'dependents', width: number, height: number): string[] {
+    const { color, unicode } = this.terminal;
+    const edges = this.edges(pane);
+    const rows = Math.max(1, height - 1);
+    this.rows[pane] = rows;
+    const start = this.clampScroll(pane, rows);
+    const lines = [fitLine(this.paneTitle(pane, ` ${pane} (${edges.length})`), width, unicode)];
+    edges.slice(start, start + rows).forEach((edge, i) => {
+      const label = this.byId.get(edge.node)?.label ?? edge.node;
+      const row = ` ${label} ${color.dim(`×${edge.weight}`)}`;
+      lines.push(this.mark(pane, start + i === this.cursor[pane], fitLine(row, width, unicode)));
+    });
+    while (lines.length < height) lines.push('');
+    return lines.slice(0, height);
+  }
+
+  private paneTitle(pane: PaneId, text: string): string {
+    return this.focus === pane ? this.terminal.color.bold.underline(text) : this.terminal.color.dim(text);
+  }
+
+  private mark(pane: PaneId, current: boolean, row: string): string {
+    if (!current) return row;
+    return this.focus === pane ? this.terminal.color.inverse(row) : this.terminal.color.bold(row);
+  }
+
+  /** Scroll so the cursor is visible; returns the first row shown */
+  private clampScroll(pane: PaneId, rows: number): number {
+    const cursor = this.cursor[pane];
+    if (cursor < this.scroll[pane]) this.scroll[pane] = cursor;
+    else if (cursor >= this.scroll[pane] + rows) this.scroll[pane] = cursor - rows + 1;
+    return this.scroll[pane];
+  }
+}
+
+function pad(text: string, width: number, right = false): string {
+  const gap = ' '.repeat(Math.max(0, width - visibleLength(text)));
+  return right ? gap + text : text + gap;
+}
+
+function highlight(text: string, positions: number[], paint: (s: string) => string): string {
+  if (positions.length === 0) return text;
+  const marked = new Set(positions);
+  return [...text].map((ch, i) => (marked.has(i) ? paint(ch) : ch)).join('');
+}
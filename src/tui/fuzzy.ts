@@ -0,0 +1,44 @@
+/**
+ * Subsequence matching for the explorer's search, scored the way fzf-style
+ * finders are: consecutive characters and matches at the start of a path
+ * segment ("st" in internal/store) beat letters scattered through the name.
+ * Case-insensitive unless the query has an uppercase letter.
+ */
+
+export interface FuzzyMatch {
+  score: number;
+  /** Indices of the matched characters in the text, for highlighting */
+  positions: number[];
+}
+
+const BOUNDARY = /[/._\-\s]/;
+
+export function fuzzyMatch(query: string, text: string): FuzzyMatch | null {
+  if (!query) return { score: 0, positions: [] };
+  const smart = query === query.toLowerCase();
+  const haystack = smart ? text.toLowerCase() : text;
+
+  // Match right to left from the last occurrence of the final character, so
+  // "store" prefers the store segment over an s early in the path
+  const positions: number[] = [];
+  let from = haystack.length;
+  for (let q = query.length - 1; q >= 0; q--) {
+    // lastIndexOf(c, -1) searches index 0 again, which the previous character already took
+    if (from === 0) return null;
+    const at = haystack.lastIndexOf(query[q], from - 1);
+    if (at < 0) return null;
+    positions.unshift(at);
+    from = at;
+  }
+
+  let score = 0;
+  for (let i = 0; i < positions.length; i++) {
+    const at = positions[i];
+    score += 1;
+    if (i > 0 && positions[i - 1] === at - 1) score += 3;
+    if (at === 0 || BOUNDARY.test(text[at - 1])) score += 2;
+  }
+  // Shorter names and matches near the end (the package's own name) rank higher
+  score -= (text.length - positions[positions.length - 1]) * 0.05 + text.length * 0.01;
+  return { score, positions };
+}
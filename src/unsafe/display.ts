@@ -0,0 +1,61 @@
+import chalk from 'chalk';
+import type { UnsafeReport, UnsafeRisk } from './index.js';
+
+const RISK_COLORS: Record<UnsafeRisk, (s: string) => string> = {
+  high: chalk.red,
+  medium: chalk.yellow,
+  low: chalk.dim,
+};
+
+export function formatUnsafeReport(report: UnsafeReport, options: { limit: number; unreachable: boolean; dynamicRoots?: boolean }): string {
+  const lines: string[] = [];
+  const { summary } = report;
+
+  lines.push('');
+  lines.push(chalk.bold('Depwire unsafe/reflect Usage'));
+  lines.push(chalk.dim(`  ${summary.total} uses (${summary.workspace} workspace, ${summary.dependencies} dependencies), ${summary.reachable} reachable`));
+  if (report.libraryMode) {
+    lines.push(chalk.dim('  No main packages — reachability is from the exported functions of the workspace'));
+  }
+  if (report.dynamic.length > 0) {
+    const reached = new Set(report.dynamic.flatMap(s => s.targets)).size;
+    lines.push(chalk.dim(options.dynamicRoots
+      ? `  ${reached} functions reached through ${report.dynamic.length} plugin, reflect.New and registry sites count as entry points`
+      : `  ${report.dynamic.length} plugin, reflect.New and registry sites reach ${reached} functions the call graph can't follow (use --dynamic-roots)`));
+  }
+  lines.push('');
+
+  if (summary.byModule.length > 0) {
+    lines.push(chalk.bold('By module'));
+    for (const m of summary.byModule) {
+      lines.push(`  ${m.module.padEnd(56)} ${String(m.reachable).padStart(5)} reachable  ${chalk.dim(`of ${m.total}`)}`);
+    }
+    lines.push('');
+  }
+
+  const shown = report.uses.filter(u => options.unreachable || u.reachable);
+  lines.push(chalk.bold(options.unreachable ? 'Uses' : 'Reachable uses'));
+  if (shown.length === 0) lines.push(chalk.green('  ✓ none'));
+  for (const use of shown.slice(0, options.limit)) {
+    const mark = use.reachable ? chalk.red('●') : chalk.dim('○');
+    const approx = use.approximate ? chalk.dim(' (by name)') : '';
+    lines.push(`  ${mark} ${RISK_COLORS[use.risk](use.risk.padEnd(6))} ${use.api}${approx}` + chalk.dim(`  ${use.file}:${use.line}`));
+    if (use.path && use.path.length > 1) {
+      const path = use.path.length > 4 ? [...use.path.slice(0, 2), '…', ...use.path.slice(-2)] : use.path;
+      lines.push(chalk.dim(`             via ${path.join(' → ')}`));
+    }
+  }
+  if (shown.length > options.limit) {
+    lines.push(chalk.dim(`  … ${shown.length - options.limit} more (use --limit or --format json)`));
+  }
+  if (!options.unreachable && summary.total > summary.reachable) {
+    lines.push(chalk.dim(`  ${summary.total - summary.reachable} unreachable uses hidden (use --unreachable)`));
+  }
+  lines.push('');
+
+  for (const warning of report.warnings.slice(0, 5)) {
+    lines.push(chalk.yellow(`⚠ ${warning}`));
+  }
+  if (report.warnings.length > 0) lines.push('');
+  return lines.join('\n');
+}
@@ -0,0 +1,195 @@
+import type { Node } from 'web-tree-sitter';
+import { walk } from '../golang/source.js';
+import { loadGoProject } from '../golang/packages.js';
+import { fileQualifiers } from '../golang/references.js';
+import { loadGoDependencies, withDependencies, dependencyModule } from '../golang/deps.js';
+import { loadGoCallGraph, reachableFunctions, callPath } from '../golang/callgraph.js';
+import { findDynamicSites, type DynamicSite } from '../golang/dynamic.js';
+
+/**
+ * Every use of unsafe and reflect in the workspace and its dependencies,
+ * with whether the enclosing function is reachable from the binaries'
+ * entry points — so review can start with the code that actually runs.
+ */
+
+export type UnsafeRisk = 'high' | 'medium' | 'low';
+
+export interface UnsafeUse {
+  /** "unsafe.Pointer", "reflect.NewAt", "reflect.Value.Set" */
+  api: string;
+  package: 'unsafe' | 'reflect';
+  risk: UnsafeRisk;
+  file: string;
+  line: number;
+  /** Enclosing function id; null for package-level declarations */
+  function: string | null;
+  /** Import path of the package the use is in */
+  importPath: string;
+  /** Module path@version for third-party packages, null for the workspace */
+  module: string | null;
+  reachable: boolean;
+  /** Entry point → … → enclosing function, when reachable */
+  path?: string[];
+  /** Reflect method matched by name on a value of unknown type */
+  approximate?: boolean;
+}
+
+export interface UnsafeReport {
+  projectRoot: string;
+  entryPoints: string[];
+  /** True when there are no main packages and exported functions were used as entry points */
+  libraryMode: boolean;
+  /** Plugin, reflect.New and registry sites; their targets are entry points with dynamicRoots */
+  dynamic: DynamicSite[];
+  uses: UnsafeUse[];
+  summary: {
+    total: number;
+    reachable: number;
+    workspace: number;
+    dependencies: number;
+    byModule: Array<{ module: string; total: number; reachable: number }>;
+  };
+  warnings: string[];
+}
+
+export interface UnsafeReportOptions {
+  /** Include third-party packages (default true) */
+  dependencies?: boolean;
+  /** Call graph algorithm for reachability: syntactic (default) or pta */
+  algo?: string;
+  /** Treat functions reached through plugins, reflect.New and registries as entry points */
+  dynamicRoots?: boolean;
+  signal?: AbortSignal;
+}
+
+// Sizeof/Alignof/Offsetof are evaluated at compile time
+const UNSAFE_RISK: Record<string, UnsafeRisk> = {
+  Pointer: 'high', Add: 'high', Slice: 'high', String: 'high', StringData: 'high', SliceData: 'high',
+  Sizeof: 'low', Alignof: 'low', Offsetof: 'low',
+};
+
+const REFLECT_RISK: Record<string, UnsafeRisk> = {
+  NewAt: 'high', SliceHeader: 'high', StringHeader: 'high',
+  MakeFunc: 'medium', New: 'medium', MakeSlice: 'medium', MakeMap: 'medium', Copy: 'medium', Append: 'medium',
+  ValueOf: 'low', TypeOf: 'low', DeepEqual: 'low', Indirect: 'low',
+};
+
+// Methods of reflect.Value that bypass the type system or dispatch dynamically
+const REFLECT_METHODS: Record<string, UnsafeRisk> = {
+  UnsafeAddr: 'high', UnsafePointer: 'high', SetPointer: 'high', Pointer: 'medium',
+  Call: 'medium', CallSlice: 'medium', MethodByName: 'medium', FieldByName: 'low',
+  Set: 'medium', SetString: 'medium', SetInt: 'medium', SetUint: 'medium', SetBool: 'medium',
+  SetFloat: 'medium', SetBytes: 'medium', SetMapIndex: 'medium', SetLen: 'high', SetCap: 'high',
+};
+
+export async function analyzeUnsafe(projectRoot: string, options: UnsafeReportOptions = {}): Promise<UnsafeReport> {
+  let project = await loadGoProject(projectRoot);
+  const warnings: string[] = [];
+  if (options.dependencies !== false) {
+    const deps = await loadGoDependencies(projectRoot, { signal: options.signal });
+    warnings.push(...deps.warnings);
+    project = withDependencies(project, deps);
+  }
+
+  const graph = await loadGoCallGraph(project, { algo: options.algo, signal: options.signal, warnings });
+  const hasMain = graph.entryPoints.some(id => !id.includes('.init#'));
+  const libraryMode = !hasMain;
+  const dynamic = findDynamicSites(graph);
+  const roots = libraryMode
+    ? [...graph.entryPoints, ...[...graph.functions.values()].filter(f => f.exported && !dependencyModule(f.dir)).map(f => f.id)]
+    : [...graph.entryPoints];
+  if (options.dynamicRoots) roots.push(...dynamic.roots);
+  const parents = reachableFunctions(graph, roots);
+
+  const uses: UnsafeUse[] = [];
+  for (const pkg of project.packages.values()) {
+    const dep = dependencyModule(pkg.dir);
+    for (const file of pkg.files) {
+      if (file.isTest) continue;
+      const qualifiers = fileQualifiers(file, project);
+      const unsafeNames = namesFor(qualifiers, 'unsafe');
+      const reflectNames = namesFor(qualifiers, 'reflect');
+      if (unsafeNames.size === 0 && reflectNames.size === 0) continue;
+
+      const add = (node: Node, api: string, pkgName: UnsafeUse['package'], risk: UnsafeRisk, approximate = false) => {
+        const fn = graph.functionAt(file.file, node);
+        // Package-level code runs during initialization, so it's reachable whenever the package is linked in
+        const reachable = fn ? parents.has(fn.id) : true;
+        uses.push({
+          api,
+          package: pkgName,
+          risk,
+          file: file.file,
+          line: node.startPosition.row + 1,
+          function: fn?.id ?? null,
+          importPath: pkg.importPath ?? pkg.dir,
+          module: dep ? `${dep.path}@${dep.version}` : null,
+          reachable,
+          path: fn && reachable ? callPath(parents, fn.id) : undefined,
+          approximate: approximate || undefined,
+        });
+      };
+
+      walk(file.root, (node) => {
+        if (node.type === 'qualified_type' || node.type === 'selector_expression') {
+          const qualifier = node.childForFieldName(node.type === 'qualified_type' ? 'package' : 'operand');
+          const name = node.childForFieldName(node.type === 'qualified_type' ? 'name' : 'field')?.text;
+          if (qualifier && name && (qualifier.type === 'identifier' || qualifier.type === 'package_identifier')) {
+            if (unsafeNames.has(qualifier.text)) {
+              add(node, `unsafe.${name}`, 'unsafe', UNSAFE_RISK[name] ?? 'high');
+              return false;
+            }
+            if (reflectNames.has(qualifier.text)) {
+              add(node, `reflect.${name}`, 'reflect', REFLECT_RISK[name] ?? 'low');
+              return false;
+            }
+          }
+        }
+        if (node.type === 'call_expression' && reflectNames.size > 0) {
+          const fn = node.childForFieldName('function');
+          const operand = fn?.type === 'selector_expression' ? fn.childForFieldName('operand') : null;
+          const method = operand && !(operand.type === 'identifier' && qualifiers.has(operand.text))
+            ? fn!.childForFieldName('field')?.text
+            : undefined;
+          if (method && REFLECT_METHODS[method]) add(node, `reflect.Value.${method}`, 'reflect', REFLECT_METHODS[method], true);
+        }
+      });
+    }
+  }
+
+  uses.sort((a, b) => Number(b.reachable) - Number(a.reachable) || riskOrder(a.risk) - riskOrder(b.risk) ||
+    a.file.localeCompare(b.file) || a.line - b.line);
+
+  const byModule = new Map<string, { module: string; total: number; reachable: number }>();
+  for (const use of uses) {
+    const key = use.module ?? '(workspace)';
+    if (!byModule.has(key)) byModule.set(key, { module: key, total: 0, reachable: 0 });
+    const entry = byModule.get(key)!;
+    entry.total++;
+    if (use.reachable) entry.reachable++;
+  }
+
+  return {
+    projectRoot,
+    entryPoints: roots,
+    libraryMode,
+    dynamic: dynamic.sites,
+    uses,
+    summary: {
+      total: uses.length,
+      reachable: uses.filter(u => u.reachable).length,
+      workspace: uses.filter(u => !u.module).length,
+      dependencies: uses.filter(u => u.module).length,
+      byModule: [...byModule.values()].sort((a, b) => b.reachable - a.reachable || b.total - a.total),
+    },
+    warnings,
+  };
+}
+
+function namesFor(qualifiers: Map<string, string>, importPath: string): Set<string> {
+  return new Set([...qualifiers].filter(([, path]) => path === importPath).map(([name]) => name));
+}
+
+function riskOrder(risk: UnsafeRisk): number {
+  return risk === 'high' ? 0 : risk === 'medium' ? 1 : 2;
+}
@@ -1,6 +1,9 @@
-import { readdirSync, statSync, existsSync, lstatSync, realpathSync } from 'fs';
+import { readdirSync, statSync, existsSync, lstatSync, realpathSync, writeFileSync, renameSync, mkdirSync, chmodSync } from 'fs';
 import { join, relative } from 'path';
 import os from 'os';
+import { logger } from './log.js';
+
+const log = logger('files');
 
 export function scanDirectory(
   rootDir: string,
@@ -63,12 +66,44 @@ export function scanDirectory(
       }
     }
   } catch (err) {
-    console.error(`Error scanning directory ${baseDir}:`, err);
+    log.warn(`Error scanning directory ${baseDir}`, { error: err });
   }
   
   return files;
 }
 
+/**
+ * List Go test files (_test.go), which scanDirectory leaves out of the graph.
+ * Skips the same directories scanDirectory does.
+ */
+export function scanGoTestFiles(rootDir: string, baseDir: string = rootDir): string[] {
+  const files: string[] = [];
+  let entries: string[];
+  try {
+    entries = readdirSync(baseDir);
+  } catch {
+    return files;
+  }
+
+  for (const entry of entries) {
+    if (entry.startsWith('.') || entry === 'node_modules' || entry === 'vendor' || entry === 'dist' || entry === 'build') {
+      continue;
+    }
+    const fullPath = join(baseDir, entry);
+    try {
+      const stats = lstatSync(fullPath);
+      if (stats.isDirectory()) {
+        files.push(...scanGoTestFiles(rootDir, fullPath));
+      } else if (stats.isFile() && entry.endsWith('_test.go')) {
+        files.push(relative(rootDir, fullPath));
+      }
+    } catch {
+      continue;
+    }
+  }
+  return files;
+}
+
 export function fileExists(filePath: string): boolean {
   try {
     return existsSync(filePath) && statSync(filePath).isFile();
@@ -77,6 +112,16 @@ export function fileExists(filePath: string): boolean {
   }
 }
 
+/**
+ * Write a file atomically: write to a temp file next to it, then rename over it.
+ * An interrupted run never leaves a truncated cache or snapshot behind.
+ */
+export function writeFileAtomic(filePath: string, content: string): void {
+  const tmpPath = `${filePath}.${process.pid}.tmp`;
+  writeFileSync(tmpPath, content, 'utf-8');
+  renameSync(tmpPath, filePath);
+}
+
 /**
  * Find the project root by walking up directories looking for project markers
  * @param startDir Directory to start searching from (defaults to process.cwd())
@@ -116,7 +161,7 @@ export function findProjectRoot(startDir: string = process.cwd()): string {
     // Check if current directory name is in blocklist
     const dirName = currentDir.split('/').pop();
     if (dirName && blocklist.includes(dirName)) {
-      console.warn(`⚠️  Skipping blocked directory: ${dirName}`);
+      log.warn(`Skipping blocked directory: ${dirName}`, { dir: currentDir });
       break;
     }
     
@@ -141,8 +186,42 @@ export function findProjectRoot(startDir: string = process.cwd()): string {
   }
   
   // No project root found, return the starting directory with warning
-  console.warn(`⚠️  No project root found within ${maxDepth} levels. Using current directory: ${startDir}`);
+  log.warn(`No project root found within ${maxDepth} levels. Using current directory: ${startDir}`);
   return startDir;
 }
+
+/**
+ * Where depwire keeps a cache of its own for this user:
+ * $XDG_CACHE_HOME/depwire/<name>, ~/.cache/depwire/<name> by default
+ * (%LOCALAPPDATA% on Windows). Nothing is created; see ensurePrivateDir.
+ */
+export function userCacheDir(name: string): string {
+  const base = process.env.XDG_CACHE_HOME
+    || (process.platform === 'win32' && process.env.LOCALAPPDATA)
+    || join(os.homedir(), '.cache');
+  return join(base, 'depwire', name);
+}
+
+/**
+ * Create dir for this user only (0700), or check that an existing one is
+ * theirs and writable by no one else. Downloads cached where another user
+ * can write could be swapped for anything, so a directory that fails the
+ * check is refused rather than used.
+ */
+export function ensurePrivateDir(dir: string): string {
+  if (!existsSync(dir)) {
+    mkdirSync(dir, { recursive: true, mode: 0o700 });
+    // mkdir's mode is filtered by the umask
+    chmodSync(dir, 0o700);
+  }
+  const stats = lstatSync(dir);
+  if (!stats.isDirectory()) throw new Error(`${dir} is not a directory`);
+  // Ownership and permission bits don't mean the same on Windows
+  if (process.platform !== 'win32' && typeof process.getuid === 'function') {
+    if (stats.uid !== process.getuid()) throw new Error(`${dir} belongs to another user; pick a cache directory of your own (--cache-dir)`);
+    if ((stats.mode & 0o022) !== 0) throw new Error(`${dir} is writable by other users; run chmod 700 ${dir} or pick another cache directory`);
+  }
+  return dir;
+}
 // test action
 // test action v3
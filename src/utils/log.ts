@@ -0,0 +1,99 @@
+/**
+ * Leveled logging on stderr, as text for people or as one JSON object per
+ * line for CI (--log-format json). stdout stays reserved for command output.
+ *
+ * Text records print the message, plus ": <error>" when there is one; other
+ * fields (and an error's stack) show up from debug (-v) on. JSON records
+ * carry everything:
+ *
+ *   {"time":"2026-10-14T09:30:00.000Z","level":"info","component":"lint","msg":"Linting","rules":7}
+ *
+ * The CLI configures it from -v/-vv, --log-level and --log-format;
+ * DEPWIRE_LOG_LEVEL and DEPWIRE_LOG_FORMAT set the defaults.
+ */
+
+export type LogLevel = 'error' | 'warn' | 'info' | 'debug' | 'trace';
+export type LogFormat = 'text' | 'json';
+export type LogFields = Record<string, unknown>;
+
+export const LOG_LEVELS: LogLevel[] = ['error', 'warn', 'info', 'debug', 'trace'];
+export const LOG_FORMATS: LogFormat[] = ['text', 'json'];
+
+export interface Logger {
+  error(msg: string, fields?: LogFields): void;
+  warn(msg: string, fields?: LogFields): void;
+  info(msg: string, fields?: LogFields): void;
+  debug(msg: string, fields?: LogFields): void;
+  trace(msg: string, fields?: LogFields): void;
+  /** Whether records at this level are written — to skip building expensive ones */
+  enabled(level: LogLevel): boolean;
+}
+
+const ANSI = /\x1b\[[0-9;]*m/g;
+
+let level: LogLevel = parseLogLevel(process.env.DEPWIRE_LOG_LEVEL) ?? 'info';
+let format: LogFormat = parseLogFormat(process.env.DEPWIRE_LOG_FORMAT) ?? 'text';
+
+function parseLogLevel(value: string | undefined): LogLevel | undefined {
+  return LOG_LEVELS.find(l => l === value?.toLowerCase());
+}
+
+function parseLogFormat(value: string | undefined): LogFormat | undefined {
+  return LOG_FORMATS.find(f => f === value?.toLowerCase());
+}
+
+/** -v is debug, -vv and more trace */
+export function verbosityLevel(count: number): LogLevel {
+  return count >= 2 ? 'trace' : count === 1 ? 'debug' : 'info';
+}
+
+export function configureLogging(options: { level?: string; format?: string }): void {
+  if (options.level !== undefined) {
+    const parsed = parseLogLevel(options.level);
+    if (!parsed) throw new Error(`Unknown log level "${options.level}" (expected ${LOG_LEVELS.join(', ')})`);
+    level = parsed;
+  }
+  if (options.format !== undefined) {
+    const parsed = parseLogFormat(options.format);
+    if (!parsed) throw new Error(`Unknown log format "${options.format}" (expected ${LOG_FORMATS.join(' or ')})`);
+    format = parsed;
+  }
+}
+
+export function logFormat(): LogFormat {
+  return format;
+}
+
+function isEnabled(at: LogLevel): boolean {
+  return LOG_LEVELS.indexOf(at) <= LOG_LEVELS.indexOf(level);
+}
+
+function write(at: LogLevel, component: string, msg: string, fields: LogFields = {}): void {
+  if (!isEnabled(at)) return;
+  const { error: cause, ...rest } = fields;
+  // Errors are logged by message; their stack only from debug on
+  const error = cause instanceof Error ? cause.message : cause;
+  if (cause instanceof Error && cause.stack && isEnabled('debug')) rest.stack = cause.stack;
+  if (format === 'json') {
+    const record = { time: new Date().toISOString(), level: at, component, msg: msg.replace(ANSI, '').trim(), ...(error === undefined ? {} : { error }), ...rest };
+    process.stderr.write(`${JSON.stringify(record)}\n`);
+    return;
+  }
+  let line = error === undefined ? msg : `${msg}: ${error}`;
+  if (isEnabled('debug')) {
+    for (const [key, value] of Object.entries(rest)) line += ` ${key}=${typeof value === 'string' ? value : JSON.stringify(value)}`;
+  }
+  process.stderr.write(`${line}\n`);
+}
+
+/** A logger whose records are tagged with the part of depwire writing them */
+export function logger(component: string): Logger {
+  return {
+    error: (msg, fields) => write('error', component, msg, fields),
+    warn: (msg, fields) => write('warn', component, msg, fields),
+    info: (msg, fields) => write('info', component, msg, fields),
+    debug: (msg, fields) => write('debug', component, msg, fields),
+    trace: (msg, fields) => write('trace', component, msg, fields),
+    enabled: isEnabled,
+  };
+}
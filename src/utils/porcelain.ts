@@ -0,0 +1,69 @@
+import { readFileSync } from 'fs';
+import chalk from 'chalk';
+import { disableProgress } from './progress.js';
+
+/**
+ * Output modes for scripts. --porcelain is a contract: one record per line
+ * on stdout, fields separated by tabs, the record type first where a command
+ * prints more than one kind; no color, headings or progress. Later versions
+ * only add fields at the end of a record, never reorder or drop them.
+ * --quiet prints nothing at all, and the exit code is the answer: checks
+ * exit 1 on what they would fail on anyway, lookups exit 1 when there is
+ * nothing to list, like grep -q. Errors exit 1 too; where that difference
+ * matters, use --porcelain.
+ */
+
+export interface OutputFlags {
+  porcelain?: boolean;
+  quiet?: boolean;
+}
+
+export type PorcelainField = string | number | boolean | null | undefined;
+
+let quiet = false;
+
+// Tabs and newlines would split a record; backslashes are escaped so the escapes can be undone
+const escapeField = (value: string) => value.replace(/\\/g, '\\\\').replace(/\t/g, '\\t').replace(/\n/g, '\\n').replace(/\r/g, '\\r');
+
+/** One record: missing values are empty fields */
+export function porcelainLine(fields: PorcelainField[]): string {
+  return fields.map(f => (f === null || f === undefined ? '' : escapeField(String(f)))).join('\t');
+}
+
+export function printPorcelain(records: PorcelainField[][]): void {
+  if (records.length > 0) console.log(records.map(porcelainLine).join('\n'));
+}
+
+/** For the CLI's preAction hook: set up a command's run for --porcelain or --quiet */
+export function applyOutputFlags(flags: OutputFlags): void {
+  if (flags.porcelain && flags.quiet) throw new Error('--porcelain and --quiet exclude each other');
+  if (!flags.porcelain && !flags.quiet) return;
+  chalk.level = 0;
+  disableProgress();
+  if (flags.quiet) {
+    quiet = true;
+    const discard = (() => true) as typeof process.stdout.write;
+    process.stdout.write = discard;
+    process.stderr.write = discard;
+  }
+}
+
+/**
+ * The exit code of a lookup under --quiet: 1 when it found nothing. Other
+ * modes keep exiting 0, so `for dir in $(depwire targets)` still works
+ * with set -e.
+ */
+export function answerQuietly(found: boolean): void {
+  if (quiet && !found) process.exitCode = 1;
+}
+
+/**
+ * A batch of lookups from stdin, for a `-` argument: one per line, blank
+ * lines and # comments skipped. The command answers them all from one load.
+ */
+export function readStdinQueries(): string[] {
+  return readFileSync(0, 'utf-8')
+    .split('\n')
+    .map(line => line.trim())
+    .filter(line => line && !line.startsWith('#'));
+}
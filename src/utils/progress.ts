@@ -0,0 +1,120 @@
+import { logger, logFormat } from './log.js';
+
+/**
+ * Progress reporting and cancellation for long-running operations.
+ *
+ * Library callers pass an AbortSignal and an onProgress callback; the CLI wires
+ * Ctrl-C to the signal (withInterrupt) and renders events as a spinner on stderr.
+ */
+
+const log = logger('cli');
+
+export interface ProgressEvent {
+  /** What is being processed: 'parse' (files), 'snapshot' (commits), ... */
+  phase: string;
+  completed: number;
+  total: number;
+  /** The item currently being processed (file path, commit hash) */
+  item?: string;
+}
+
+export type ProgressCallback = (event: ProgressEvent) => void;
+
+export interface Spinner {
+  update: ProgressCallback;
+  stop(): void;
+}
+
+const FRAMES = ['⠋', '⠙', '⠹', '⠸', '⠼', '⠴', '⠦', '⠧', '⠇', '⠏'];
+const RENDER_INTERVAL_MS = 80;
+
+let spinnersDisabled = false;
+
+/** No spinners for the rest of the run (--porcelain, --quiet) */
+export function disableProgress(): void {
+  spinnersDisabled = true;
+}
+
+/**
+ * Create a spinner that renders progress events on stderr.
+ * Renders nothing when stderr is not a TTY (CI logs, MCP stdio, pipes) or
+ * carries JSON log records.
+ */
+export function createSpinner(label: string): Spinner {
+  const enabled = Boolean(process.stderr.isTTY) && !process.env.CI && logFormat() === 'text' && !spinnersDisabled;
+  let frame = 0;
+  let lastRender = 0;
+
+  return {
+    update(event: ProgressEvent) {
+      if (!enabled) return;
+      const now = Date.now();
+      if (now - lastRender < RENDER_INTERVAL_MS && event.completed < event.total) return;
+      lastRender = now;
+
+      const spinner = FRAMES[frame++ % FRAMES.length];
+      const line = `${spinner} ${label} ${event.completed}/${event.total}`;
+      const width = process.stderr.columns || 80;
+      process.stderr.write(`\r${line.slice(0, width - 1).padEnd(width - 1)}`);
+    },
+    stop() {
+      if (!enabled || lastRender === 0) return;
+      const width = process.stderr.columns || 80;
+      process.stderr.write(`\r${' '.repeat(width - 1)}\r`);
+    },
+  };
+}
+
+/**
+ * Run an operation with an abort signal tied to Ctrl-C.
+ * The first SIGINT aborts the signal so in-flight work can unwind and clean up
+ * (restore git checkouts, skip partial cache writes). A second SIGINT exits immediately.
+ * The handler is removed once the operation settles.
+ */
+export async function withInterrupt<T>(operation: (signal: AbortSignal) => Promise<T>): Promise<T> {
+  const controller = new AbortController();
+
+  const onInterrupt = () => {
+    // A server started by the operation installed its own shutdown handler — defer to it
+    if (process.listenerCount('SIGINT') > 1) return;
+    if (controller.signal.aborted) {
+      process.exit(130);
+    }
+    if (logFormat() === 'text') process.stderr.write('\n');
+    log.warn('Interrupted — cleaning up (press Ctrl-C again to force quit)');
+    controller.abort(new CancelledError());
+  };
+
+  process.on('SIGINT', onInterrupt);
+  try {
+    return await operation(controller.signal);
+  } finally {
+    process.off('SIGINT', onInterrupt);
+  }
+}
+
+export class CancelledError extends Error {
+  constructor(message: string = 'Operation cancelled') {
+    super(message);
+    this.name = 'CancelledError';
+  }
+}
+
+export function isCancelled(err: unknown): boolean {
+  return err instanceof CancelledError || (err instanceof Error && err.name === 'AbortError');
+}
+
+/** Throw if the signal has been aborted */
+export function checkCancelled(signal?: AbortSignal): void {
+  if (signal?.aborted) {
+    throw signal.reason instanceof Error ? signal.reason : new CancelledError();
+  }
+}
+
+/**
+ * Yield to the event loop so signal handlers (Ctrl-C) get a chance to run
+ * during otherwise synchronous loops.
+ */
+export function yieldToEventLoop(): Promise<void> {
+  return new Promise((resolve) => setImmediate(resolve));
+}
@@ -0,0 +1,59 @@
+import { Chalk, type ChalkInstance } from 'chalk';
+
+/**
+ * What rendered terminal output may use: its width, colors and box-drawing
+ * characters. NO_COLOR (https://no-color.org) and pipes turn color off;
+ * output to a pipe is never truncated unless COLUMNS says otherwise.
+ */
+
+export interface Terminal {
+  width: number;
+  color: ChalkInstance;
+  unicode: boolean;
+}
+
+export interface TerminalOptions {
+  /** Plain ASCII instead of box-drawing characters */
+  ascii?: boolean;
+  width?: number;
+}
+
+export function detectTerminal(stream: NodeJS.WriteStream = process.stdout, options: TerminalOptions = {}): Terminal {
+  const env = process.env;
+  const noColor = Boolean(env.NO_COLOR) || (!stream.isTTY && !env.FORCE_COLOR) || env.TERM === 'dumb';
+  const columns = env.COLUMNS ? parseInt(env.COLUMNS, 10) : NaN;
+  const width = options.width ?? (stream.isTTY && stream.columns ? stream.columns : Number.isNaN(columns) ? Infinity : columns);
+  return {
+    width: Math.max(20, width),
+    color: noColor ? new Chalk({ level: 0 }) : new Chalk(),
+    unicode: !options.ascii && env.TERM !== 'dumb' && env.TERM !== 'linux',
+  };
+}
+
+const ANSI = /\x1b\[[0-9;]*m/g;
+
+export function visibleLength(text: string): number {
+  return text.replace(ANSI, '').length;
+}
+
+/** Cut a (possibly colored) line to `width` visible characters, ending in an ellipsis */
+export function fitLine(text: string, width: number, unicode = true): string {
+  if (!Number.isFinite(width) || visibleLength(text) <= width) return text;
+  const ellipsis = unicode ? '…' : '~';
+  let visible = 0;
+  let out = '';
+  for (let i = 0; i < text.length;) {
+    ANSI.lastIndex = i;
+    const escape = text[i] === '\x1b' ? ANSI.exec(text) : null;
+    if (escape && escape.index === i) {
+      out += escape[0];
+      i += escape[0].length;
+      continue;
+    }
+    if (visible === width - 1) break;
+    out += text[i++];
+    visible++;
+  }
+  // Close any color left open by the cut
+  return `${out}${ellipsis}${text.includes('\x1b[') ? '\x1b[0m' : ''}`;
+}
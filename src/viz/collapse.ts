@@ -0,0 +1,120 @@
+import { findGoModules, GoModuleIndex } from '../golang/modules.js';
+import type { VizData, VizFile, VizArc } from './types.js';
+
+/**
+ * Hierarchical collapsing for the file-level viz graph: every file under a
+ * path prefix becomes one node, and the arcs into and out of it are merged
+ * with their edge counts summed. Arcs inside a collapsed prefix disappear.
+ */
+
+/** "internal/store/", "internal/store/..." and "./internal/store" all mean internal/store */
+export function normalizePrefix(prefix: string): string {
+  return prefix.trim().replace(/\\/g, '/').replace(/\/\.\.\.$/, '').replace(/^\.\//, '').replace(/\/+$/, '');
+}
+
+/** The collapsed prefix a path falls under, if any */
+export function prefixOf(path: string, prefixes: string[]): string | null {
+  // The outermost prefix wins, so a collapsed subtree inside another is absorbed
+  let best: string | null = null;
+  for (const prefix of prefixes) {
+    if (prefix === '.' || path === prefix || path.startsWith(`${prefix}/`)) {
+      if (best === null || prefix.length < best.length) best = prefix;
+    }
+  }
+  return best;
+}
+
+/**
+ * Map collapse prefixes given as Go import paths (github.com/org/x/...) to the
+ * project-relative directories the viz uses. Other prefixes pass through.
+ */
+export function resolveCollapsePrefixes(projectRoot: string, prefixes: string[]): string[] {
+  const normalized = prefixes.map(normalizePrefix).filter(Boolean);
+  // Import paths start with a domain; project directories don't
+  if (!normalized.some(p => p.split('/')[0].includes('.') && p !== '.')) return normalized;
+  const index = new GoModuleIndex(findGoModules(projectRoot));
+  return normalized.map(p => index.dirForImport(p) ?? p);
+}
+
+export function collapseVizData(data: VizData, prefixes: string[]): VizData {
+  const active = [...new Set(prefixes.map(normalizePrefix).filter(Boolean))];
+  if (active.length === 0) return { ...data, collapsed: [] };
+
+  const groups = new Map<string, VizFile>();
+  const nodeOf = new Map<string, string>();
+  const files: VizFile[] = [];
+
+  for (const file of data.files) {
+    const prefix = prefixOf(file.path, active);
+    if (!prefix) {
+      files.push(file);
+      nodeOf.set(file.path, file.path);
+      continue;
+    }
+    const id = prefix === '.' ? './…' : `${prefix}/…`;
+    nodeOf.set(file.path, id);
+    const group = groups.get(id);
+    if (group) {
+      group.symbolCount += file.symbolCount;
+      group.collapsed!.fileCount++;
+    } else {
+      const node: VizFile = {
+        path: id,
+        directory: prefix,
+        symbolCount: file.symbolCount,
+        incomingCount: 0,
+        outgoingCount: 0,
+        collapsed: { prefix, fileCount: 1, internalEdges: 0 },
+      };
+      groups.set(id, node);
+      files.push(node);
+    }
+  }
+
+  const arcMap = new Map<string, VizArc>();
+  for (const arc of data.arcs) {
+    const source = nodeOf.get(arc.sourceFile) ?? arc.sourceFile;
+    const target = nodeOf.get(arc.targetFile) ?? arc.targetFile;
+    if (source === target) {
+      const group = groups.get(source);
+      if (group) group.collapsed!.internalEdges += arc.edgeCount;
+      continue;
+    }
+    const key = `${source}::${target}`;
+    const merged = arcMap.get(key);
+    if (merged) {
+      merged.edgeCount += arc.edgeCount;
+      merged.aggregated = (merged.aggregated ?? 1) + 1;
+      for (const kind of arc.edgeKinds) {
+        if (!merged.edgeKinds.includes(kind)) merged.edgeKinds.push(kind);
+      }
+      if (arc.crossLanguage) {
+        merged.crossLanguage = true;
+        merged.edgeType = arc.edgeType || merged.edgeType;
+      }
+    } else {
+      arcMap.set(key, { ...arc, sourceFile: source, targetFile: target, edgeKinds: [...arc.edgeKinds] });
+    }
+  }
+  const arcs = Array.from(arcMap.values());
+
+  // Collapsed nodes count their connections like files do: edges crossing the boundary
+  for (const arc of arcs) {
+    const source = groups.get(arc.sourceFile);
+    if (source) source.outgoingCount += arc.edgeCount;
+    const target = groups.get(arc.targetFile);
+    if (target) target.incomingCount += arc.edgeCount;
+  }
+
+  files.sort((a, b) => {
+    if (a.directory !== b.directory) return a.directory.localeCompare(b.directory);
+    return a.path.localeCompare(b.path);
+  });
+
+  return {
+    ...data,
+    files,
+    arcs,
+    collapsed: active,
+  };
+}
@@ -178,19 +178,243 @@ let filePositions = new Map();
 let selectedFile = null;
 let selectedArc = null;
 let ws = null;
+// null until the first load, so the server's --collapse defaults apply
+let collapsedPrefixes = null;
+// Search, edge filters, pins and the focused package; mirrored in the URL
+// hash (#pkg=…&q=…&pin=…&hide=…&collapse=…) so a view can be shared
+let searchQuery = '';
+let focusedPackage = null;
+let hiddenKinds = new Set();
+let pinnedFiles = new Set();
+
+const TEST_FILE = /(_test\.go|\.(test|spec)\.[cm]?[jt]sx?|_test\.py|(^|\/)test_[^/]*\.py)$|(^|\/)(__tests__|tests?)\//;
+
+function graphUrl() {
+  if (collapsedPrefixes === null) return '/api/graph';
+  return '/api/graph?collapse=' + encodeURIComponent(collapsedPrefixes.join(','));
+}
+
+async function loadGraph() {
+  const response = await fetch(graphUrl());
+  graphData = await response.json();
+  collapsedPrefixes = graphData.collapsed || [];
+  updateStats();
+  renderCollapsed();
+}
+
+function updateStats() {
+  document.getElementById('stats').innerHTML = `
+    <div class="stat-item"><span class="stat-label">Files:</span> <span class="stat-value">${graphData.stats.totalFiles}</span></div>
+    <div class="stat-item"><span class="stat-label">Symbols:</span> <span class="stat-value">${graphData.stats.totalSymbols}</span></div>
+    <div class="stat-item"><span class="stat-label">Edges:</span> <span class="stat-value">${graphData.stats.totalCrossFileEdges}</span></div>
+    ${collapsedPrefixes.length ? `<div class="stat-item"><span class="stat-label">Nodes:</span> <span class="stat-value">${graphData.files.length}</span></div>` : ''}
+  `;
+}
+
+function parentPrefix(prefix) {
+  return prefix.includes('/') ? prefix.substring(0, prefix.lastIndexOf('/')) : null;
+}
+
+// Collapse a prefix (absorbing collapsed prefixes under it) or expand it again
+async function toggleCollapse(prefix) {
+  if (collapsedPrefixes.includes(prefix)) {
+    collapsedPrefixes = collapsedPrefixes.filter(p => p !== prefix);
+  } else {
+    collapsedPrefixes = collapsedPrefixes.filter(p => !p.startsWith(prefix + '/')).concat(prefix);
+  }
+  await loadGraph();
+  renderArcDiagram();
+  writeHash();
+}
+
+function renderCollapsed() {
+  const container = document.getElementById('collapsedList');
+  if (!container) return;
+  container.innerHTML = '';
+  collapsedPrefixes.forEach(prefix => {
+    const chip = document.createElement('button');
+    chip.className = 'collapse-chip';
+    chip.title = 'Expand';
+    chip.textContent = prefix + '/… ✕';
+    chip.addEventListener('click', (e) => {
+      e.stopPropagation();
+      toggleCollapse(prefix);
+    });
+    container.appendChild(chip);
+  });
+}
+
+function isTestPath(path) {
+  return TEST_FILE.test(path);
+}
+
+// The edge filter an edge kind falls under; other kinds are always shown
+function kindFilter(kind) {
+  if (kind === 'imports') return 'imports';
+  if (kind === 'implements' || kind === 'extends' || kind === 'inherits') return 'implements';
+  return null;
+}
+
+// An arc stays visible while any of its edge kinds is
+function arcVisible(arc) {
+  if (hiddenKinds.has('tests') && (isTestPath(arc.sourceFile) || isTestPath(arc.targetFile))) return false;
+  return arc.edgeKinds.length === 0 || arc.edgeKinds.some(kind => !hiddenKinds.has(kindFilter(kind)));
+}
+
+// "/pattern/flags" is a regular expression, anything else a case-insensitive substring
+function searchPattern(query) {
+  const match = query.match(/^\/(.+)\/([a-z]*)$/);
+  if (!match) {
+    const needle = query.toLowerCase();
+    return { test: (path) => path.toLowerCase().includes(needle) };
+  }
+  try {
+    // Stateful flags would make test() skip matches
+    return new RegExp(match[1], match[2].replace(/[gy]/g, '') || 'i');
+  } catch {
+    return null;
+  }
+}
+
+// A directory, a collapsed prefix containing it, or a Go import path ending in it
+function inPackage(file, pkg) {
+  const dir = file.collapsed ? file.collapsed.prefix : file.directory;
+  if (dir === pkg || (file.collapsed && pkg.startsWith(dir + '/'))) return true;
+  return dir !== '.' && pkg.split('/')[0].includes('.') && pkg.endsWith('/' + dir);
+}
+
+// The files the current search or focused package picks out, or null
+function viewMatches() {
+  if (searchQuery) {
+    const pattern = searchPattern(searchQuery);
+    if (!pattern) return null;
+    const files = graphData.files.filter(f => pattern.test(f.path));
+    return files.length ? { title: `Search Results: ${files.length} file(s)`, files } : null;
+  }
+  if (focusedPackage) {
+    const files = graphData.files.filter(f => inPackage(f, focusedPackage));
+    return files.length ? { title: `Package: ${focusedPackage} (${files.length} file(s))`, files } : null;
+  }
+  return null;
+}
+
+// Back to the search or package highlight (if any) once a hover or selection ends
+function restoreView() {
+  d3.selectAll('.arc').classed('highlighted', false).classed('dimmed', false);
+  d3.selectAll('.file-bar').classed('highlighted', false).classed('dimmed', false);
+  const matches = viewMatches();
+  if (!matches) {
+    resetDetailPanel();
+    return;
+  }
+  
+  const matchingPaths = new Set(matches.files.map(f => f.path));
+  d3.selectAll('.file-bar')
+    .classed('highlighted', f => matchingPaths.has(f.path))
+    .classed('dimmed', f => !matchingPaths.has(f.path));
+  d3.selectAll('.arc')
+    .classed('highlighted', arc => matchingPaths.has(arc.sourceFile) || matchingPaths.has(arc.targetFile))
+    .classed('dimmed', arc => !matchingPaths.has(arc.sourceFile) && !matchingPaths.has(arc.targetFile));
+  
+  updateDetailPanel(`
+    <p class="detail-title">${matches.title}</p>
+    ${matches.files.slice(0, 10).map(f => `<p class="detail-info">${f.path}</p>`).join('')}
+    ${matches.files.length > 10 ? '<p class="detail-info">...</p>' : ''}
+  `);
+}
+
+function applyFilters() {
+  d3.selectAll('.arc').classed('filtered', arc => !arcVisible(arc));
+  document.querySelectorAll('#edgeFilters input').forEach(input => {
+    input.checked = !hiddenKinds.has(input.value);
+  });
+}
+
+function applyPins() {
+  d3.selectAll('.file-bar').classed('pinned', f => pinnedFiles.has(f.path));
+  d3.selectAll('.arc').classed('pinned', arc => pinnedFiles.has(arc.sourceFile) || pinnedFiles.has(arc.targetFile));
+  renderPinned();
+}
+
+function togglePin(path) {
+  if (pinnedFiles.has(path)) pinnedFiles.delete(path);
+  else pinnedFiles.add(path);
+  applyPins();
+  writeHash();
+}
+
+function renderPinned() {
+  const container = document.getElementById('pinnedList');
+  if (!container) return;
+  container.innerHTML = '';
+  pinnedFiles.forEach(path => {
+    const chip = document.createElement('button');
+    chip.className = 'pin-chip';
+    chip.title = 'Unpin';
+    chip.textContent = '📌 ' + path.split('/').pop() + ' ✕';
+    chip.addEventListener('click', (e) => {
+      e.stopPropagation();
+      togglePin(path);
+    });
+    container.appendChild(chip);
+  });
+}
+
+function focusPackage(pkg) {
+  focusedPackage = pkg;
+  searchQuery = '';
+  const searchInput = document.getElementById('searchInput');
+  if (searchInput) searchInput.value = '';
+  selectedFile = null;
+  selectedArc = null;
+  restoreView();
+  writeHash();
+}
+
+// '/' and ',' stay readable in shared links
+function encodeHashValue(value) {
+  return encodeURIComponent(value).replace(/%2F/gi, '/').replace(/%2C/gi, ',');
+}
+
+function writeHash() {
+  const parts = [];
+  if (focusedPackage) parts.push('pkg=' + encodeHashValue(focusedPackage));
+  if (searchQuery) parts.push('q=' + encodeHashValue(searchQuery));
+  if (pinnedFiles.size) parts.push('pin=' + [...pinnedFiles].map(encodeHashValue).join(','));
+  if (hiddenKinds.size) parts.push('hide=' + [...hiddenKinds].join(','));
+  if (collapsedPrefixes && collapsedPrefixes.length) parts.push('collapse=' + collapsedPrefixes.map(encodeHashValue).join(','));
+  const hash = parts.length ? '#' + parts.join('&') : '';
+  if (hash !== window.location.hash) {
+    history.replaceState(null, '', window.location.pathname + window.location.search + hash);
+  }
+}
+
+// Returns true when the collapsed prefixes changed and the graph must be refetched
+function readHash() {
+  const params = new URLSearchParams(window.location.hash.replace(/^#/, ''));
+  const list = (name) => (params.get(name) || '').split(',').map(v => v.trim()).filter(Boolean);
+  focusedPackage = params.get('pkg') || null;
+  searchQuery = params.get('q') || '';
+  pinnedFiles = new Set(list('pin'));
+  hiddenKinds = new Set(list('hide'));
+  const searchInput = document.getElementById('searchInput');
+  if (searchInput) searchInput.value = searchQuery;
+  
+  if (!params.has('collapse')) return false;
+  const collapse = list('collapse');
+  const changed = collapsedPrefixes === null || collapse.join(',') !== collapsedPrefixes.join(',');
+  collapsedPrefixes = collapse;
+  return changed;
+}
 
 async function init() {
   try {
-    const response = await fetch('/api/graph');
-    graphData = await response.json();
+    // A shared link's collapsed prefixes replace the server's defaults
+    if (!window.__depwireWhatIf) readHash();
+    await loadGraph();
     
     // Update header
     document.getElementById('projectName').textContent = graphData.projectName;
-    document.getElementById('stats').innerHTML = `
-      <div class="stat-item"><span class="stat-label">Files:</span> <span class="stat-value">${graphData.stats.totalFiles}</span></div>
-      <div class="stat-item"><span class="stat-label">Symbols:</span> <span class="stat-value">${graphData.stats.totalSymbols}</span></div>
-      <div class="stat-item"><span class="stat-label">Edges:</span> <span class="stat-value">${graphData.stats.totalCrossFileEdges}</span></div>
-    `;
     
     // Render diagram
     renderArcDiagram();
@@ -198,6 +422,9 @@ async function init() {
     // Setup interactions (not in whatif mode)
     if (!window.__depwireWhatIf) {
       setupSearch();
+      setupCollapse();
+      setupFilters();
+      setupShare();
       setupExport();
     }
     
@@ -238,15 +465,8 @@ function setupWebSocket() {
       
       // Re-fetch graph data
       try {
-        const response = await fetch('/api/graph');
-        graphData = await response.json();
-        
-        // Update header stats
-        document.getElementById('stats').innerHTML = `
-          <div class="stat-item"><span class="stat-label">Files:</span> <span class="stat-value">${graphData.stats.totalFiles}</span></div>
-          <div class="stat-item"><span class="stat-label">Symbols:</span> <span class="stat-value">${graphData.stats.totalSymbols}</span></div>
-          <div class="stat-item"><span class="stat-label">Edges:</span> <span class="stat-value">${graphData.stats.totalCrossFileEdges}</span></div>
-        `;
+        // Re-fetch with the same prefixes collapsed
+        await loadGraph();
         
         // Re-render diagram
         renderArcDiagram();
@@ -402,8 +622,9 @@ function renderArcDiagram() {
     })
     .attr('y', baseline)
     .attr('width', d => filePositions.get(d.path).width)
-    .attr('height', 8)
+    .attr('height', d => d.collapsed ? 12 : 8)
     .attr('fill', d => colorScale(d.directory))
+    .classed('collapsed', d => Boolean(d.collapsed))
     .on('mouseover', handleBarHover)
     .on('mouseout', handleBarOut)
     .on('click', handleBarClick);
@@ -418,7 +639,7 @@ function renderArcDiagram() {
     .attr('y', baseline + 20)
     .attr('transform', d => `rotate(-45, ${filePositions.get(d.path).x}, ${baseline + 20})`)
     .attr('text-anchor', 'end')
-    .text(d => d.path.split('/').pop());
+    .text(d => d.collapsed ? d.path : d.path.split('/').pop());
   
   // Reset view button
   svg.append('text')
@@ -432,8 +653,15 @@ function renderArcDiagram() {
       svg.transition()
         .duration(750)
         .call(zoom.transform, d3.zoomIdentity);
+      focusedPackage = null;
       clearSelection();
+      writeHash();
     });
+  
+  // Filters, pins and highlights survive re-renders (resize, live refresh, collapse)
+  applyFilters();
+  applyPins();
+  restoreView();
 }
 
 function handleArcHover(event, d) {
@@ -457,7 +685,7 @@ function handleArcHover(event, d) {
   // Show tooltip
   showTooltip(event, `
     <div class="tooltip-line"><strong>${d.sourceFile}</strong> → <strong>${d.targetFile}</strong></div>
-    <div class="tooltip-line"><span class="tooltip-label">Edges:</span> ${d.edgeCount}</div>
+    <div class="tooltip-line"><span class="tooltip-label">Edges:</span> ${d.edgeCount}${d.aggregated ? ` (${d.aggregated} file pairs)` : ''}</div>
     <div class="tooltip-line"><span class="tooltip-label">Types:</span> ${d.edgeKinds.join(', ')}</div>
   `);
   
@@ -472,12 +700,8 @@ function handleArcHover(event, d) {
 function handleArcOut(event, d) {
   if (selectedArc) return;
   
-  d3.select(event.currentTarget).classed('highlighted', false);
-  d3.selectAll('.arc').classed('dimmed', false);
-  d3.selectAll('.file-bar').classed('highlighted', false).classed('dimmed', false);
-  
   hideTooltip();
-  resetDetailPanel();
+  restoreView();
 }
 
 function handleArcClick(event, d) {
@@ -526,12 +750,31 @@ function handleBarHover(event, d) {
   // Dim other bars
   d3.selectAll('.file-bar').filter(f => f !== d).classed('dimmed', true);
   
+  if (d.collapsed) {
+    showTooltip(event, `
+      <div class="tooltip-line"><strong>${d.path}</strong> (collapsed)</div>
+      <div class="tooltip-line"><span class="tooltip-label">Files:</span> ${d.collapsed.fileCount} | <span class="tooltip-label">Symbols:</span> ${d.symbolCount}</div>
+      <div class="tooltip-line"><span class="tooltip-label">Incoming:</span> ${d.incomingCount} | <span class="tooltip-label">Outgoing:</span> ${d.outgoingCount} | <span class="tooltip-label">Internal:</span> ${d.collapsed.internalEdges}</div>
+      <div class="tooltip-line"><span class="tooltip-label">Click to expand</span></div>
+    `);
+    updateDetailPanel(`
+      <p class="detail-title">Collapsed: ${d.collapsed.prefix}/</p>
+      <p class="detail-info"><span class="detail-label">Files:</span> ${d.collapsed.fileCount}</p>
+      <p class="detail-info"><span class="detail-label">Symbols:</span> ${d.symbolCount}</p>
+      <p class="detail-info"><span class="detail-label">Incoming edges:</span> ${d.incomingCount}</p>
+      <p class="detail-info"><span class="detail-label">Outgoing edges:</span> ${d.outgoingCount}</p>
+      <p class="detail-info"><span class="detail-label">Internal edges:</span> ${d.collapsed.internalEdges}</p>
+    `);
+    return;
+  }
+  
   // Show tooltip
   showTooltip(event, `
     <div class="tooltip-line"><strong>${d.path}</strong></div>
     <div class="tooltip-line"><span class="tooltip-label">Symbols:</span> ${d.symbolCount}</div>
     <div class="tooltip-line"><span class="tooltip-label">Incoming:</span> ${d.incomingCount} connections</div>
     <div class="tooltip-line"><span class="tooltip-label">Outgoing:</span> ${d.outgoingCount} connections</div>
+    <div class="tooltip-line"><span class="tooltip-label">Alt-click to ${pinnedFiles.has(d.path) ? 'unpin' : 'pin'}</span></div>
   `);
   
   // Update detail panel
@@ -547,16 +790,28 @@ function handleBarHover(event, d) {
 function handleBarOut(event, d) {
   if (selectedFile) return;
   
-  d3.select(event.currentTarget).classed('highlighted', false);
-  d3.selectAll('.arc').classed('highlighted', false).classed('dimmed', false);
-  d3.selectAll('.file-bar').classed('dimmed', false);
-  
   hideTooltip();
-  resetDetailPanel();
+  restoreView();
 }
 
 function handleBarClick(event, d) {
   event.stopPropagation();
+  hideTooltip();
+  
+  // Alt-click pins; click expands a collapsed node; shift-click collapses the directory (or one level up)
+  if (event.altKey) {
+    togglePin(d.path);
+    return;
+  }
+  if (d.collapsed && !event.shiftKey) {
+    toggleCollapse(d.collapsed.prefix);
+    return;
+  }
+  if (event.shiftKey) {
+    const prefix = d.collapsed ? parentPrefix(d.collapsed.prefix) : d.directory;
+    if (prefix && prefix !== '.') toggleCollapse(prefix);
+    return;
+  }
   
   if (selectedFile === d) {
     // Deselect
@@ -578,6 +833,21 @@ function handleBarClick(event, d) {
     d3.selectAll('.file-bar')
       .classed('highlighted', f => f === d)
       .classed('dimmed', f => f !== d);
+    
+    updateDetailPanel(`
+      <p class="detail-title">File: ${d.path}</p>
+      <p class="detail-info"><span class="detail-label">Directory:</span> ${d.directory}</p>
+      <p class="detail-info"><span class="detail-label">Symbols:</span> ${d.symbolCount} | <span class="detail-label">Incoming:</span> ${d.incomingCount} | <span class="detail-label">Outgoing:</span> ${d.outgoingCount}</p>
+      <p class="detail-info">
+        <button class="detail-action" id="focusPackageAction">Focus package</button>
+        <button class="detail-action" id="pinAction">${pinnedFiles.has(d.path) ? 'Unpin' : 'Pin'}</button>
+      </p>
+    `);
+    document.getElementById('focusPackageAction').addEventListener('click', () => focusPackage(d.directory));
+    document.getElementById('pinAction').addEventListener('click', (e) => {
+      togglePin(d.path);
+      e.target.textContent = pinnedFiles.has(d.path) ? 'Unpin' : 'Pin';
+    });
   }
 }
 
@@ -611,64 +881,83 @@ function resetDetailPanel() {
 function clearSelection() {
   selectedFile = null;
   selectedArc = null;
-  d3.selectAll('.arc').classed('highlighted', false).classed('dimmed', false);
-  d3.selectAll('.file-bar').classed('highlighted', false).classed('dimmed', false);
-  resetDetailPanel();
+  restoreView();
 }
 
 function setupSearch() {
   const searchInput = document.getElementById('searchInput');
   
   searchInput.addEventListener('input', (e) => {
-    const query = e.target.value.toLowerCase().trim();
-    
-    if (!query) {
-      clearSelection();
-      return;
-    }
-    
-    // Find matching files
-    const matchingFiles = graphData.files.filter(f => 
-      f.path.toLowerCase().includes(query)
-    );
-    
-    if (matchingFiles.length === 0) {
-      clearSelection();
-      return;
-    }
-    
-    // Highlight matching files and their arcs
-    const matchingPaths = new Set(matchingFiles.map(f => f.path));
-    
-    d3.selectAll('.file-bar')
-      .classed('highlighted', f => matchingPaths.has(f.path))
-      .classed('dimmed', f => !matchingPaths.has(f.path));
-    
-    const connectedArcs = graphData.arcs.filter(arc =>
-      matchingPaths.has(arc.sourceFile) || matchingPaths.has(arc.targetFile)
-    );
-    
-    d3.selectAll('.arc')
-      .classed('highlighted', arc => connectedArcs.includes(arc))
-      .classed('dimmed', arc => !connectedArcs.includes(arc));
-    
-    // Update detail panel
-    updateDetailPanel(`
-      <p class="detail-title">Search Results: ${matchingFiles.length} file(s)</p>
-      ${matchingFiles.slice(0, 10).map(f => `<p class="detail-info">${f.path}</p>`).join('')}
-      ${matchingFiles.length > 10 ? '<p class="detail-info">...</p>' : ''}
-    `);
+    searchQuery = e.target.value.trim();
+    if (searchQuery) focusedPackage = null;
+    searchInput.classList.toggle('invalid', Boolean(searchQuery) && !searchPattern(searchQuery));
+    clearSelection();
+    writeHash();
   });
   
   // Clear on Escape
   searchInput.addEventListener('keydown', (e) => {
     if (e.key === 'Escape') {
       searchInput.value = '';
+      searchQuery = '';
+      searchInput.classList.remove('invalid');
+      clearSelection();
+      writeHash();
+    }
+  });
+}
+
+function setupCollapse() {
+  const collapseInput = document.getElementById('collapseInput');
+  if (!collapseInput) return;
+  
+  collapseInput.addEventListener('keydown', (e) => {
+    if (e.key !== 'Enter') return;
+    // Accept "internal/store", "internal/store/" and "internal/store/..."
+    const prefix = collapseInput.value.trim().replace(/\/\.\.\.$/, '').replace(/^\.\//, '').replace(/\/+$/, '');
+    collapseInput.value = '';
+    if (prefix && !collapsedPrefixes.includes(prefix)) toggleCollapse(prefix);
+  });
+}
+
+function setupFilters() {
+  document.querySelectorAll('#edgeFilters input').forEach(input => {
+    input.addEventListener('change', () => {
+      if (input.checked) hiddenKinds.delete(input.value);
+      else hiddenKinds.add(input.value);
+      applyFilters();
+      writeHash();
+    });
+  });
+  
+  // Links pasted into the address bar, or back/forward between edited hashes
+  window.addEventListener('hashchange', async () => {
+    if (readHash()) {
+      await loadGraph();
+      renderArcDiagram();
+    } else {
+      applyFilters();
+      applyPins();
       clearSelection();
     }
   });
 }
 
+function setupShare() {
+  const shareButton = document.getElementById('shareButton');
+  if (!shareButton) return;
+  
+  shareButton.addEventListener('click', async () => {
+    writeHash();
+    try {
+      await navigator.clipboard.writeText(window.location.href);
+      showNotification('Link copied', 'success');
+    } catch {
+      showNotification('Copy the link from the address bar', 'info');
+    }
+  });
+}
+
 function setupExport() {
   const exportButton = document.getElementById('exportButton');
   const exportMenu = document.getElementById('exportMenu');
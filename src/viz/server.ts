@@ -7,6 +7,7 @@ import type { DirectedGraph } from 'graphology';
 import type { VizData } from './types.js';
 import { watchProject } from '../watcher.js';
 import { prepareVizData } from './data.js';
+import { collapseVizData, resolveCollapsePrefixes } from './collapse.js';
 import { parseProject } from '../parser/index.js';
 import { buildGraph } from '../graph/index.js';
 
@@ -54,7 +55,7 @@ export async function startVizServer(
   projectRoot: string,
   port: number = 3333,
   shouldOpen: boolean = true,
-  options?: { exclude?: string[]; verbose?: boolean }
+  options?: { exclude?: string[]; verbose?: boolean; collapse?: string[]; only?: Set<string>; runtimeEdges?: boolean }
 ): Promise<{ server: any; url: string; alreadyRunning: boolean }> {
   // If server is already running, return existing info
   if (activeServer) {
@@ -80,8 +81,13 @@ export async function startVizServer(
   app.use(express.static(publicDir));
   
   // API endpoint
+  // ?collapse=a,b merges every file under those prefixes; without it the --collapse defaults apply
   app.get('/api/graph', (req, res) => {
-    res.json(vizData);
+    const param = req.query.collapse;
+    const prefixes = typeof param === 'string'
+      ? param.split(',').filter(Boolean)
+      : options?.collapse ?? [];
+    res.json(collapseVizData(vizData, resolveCollapsePrefixes(projectRoot, prefixes)));
   });
   
   const server = app.listen(availablePort, '127.0.0.1', () => {
@@ -125,7 +131,7 @@ export async function startVizServer(
       try {
         // Re-parse entire project (simplest and most reliable approach)
         const parsedFiles = await parseProject(projectRoot, options);
-        const newGraph = buildGraph(parsedFiles, projectRoot);
+        const newGraph = buildGraph(parsedFiles, projectRoot, options);
         
         // Replace the graph reference (mutations affect the shared reference)
         // Copy nodes and edges to the existing graph object
@@ -153,7 +159,7 @@ export async function startVizServer(
       try {
         // Re-parse entire project
         const parsedFiles = await parseProject(projectRoot, options);
-        const newGraph = buildGraph(parsedFiles, projectRoot);
+        const newGraph = buildGraph(parsedFiles, projectRoot, options);
         
         // Replace graph contents
         graph.clear();
@@ -180,7 +186,7 @@ export async function startVizServer(
       try {
         // Re-parse entire project
         const parsedFiles = await parseProject(projectRoot, options);
-        const newGraph = buildGraph(parsedFiles, projectRoot);
+        const newGraph = buildGraph(parsedFiles, projectRoot, options);
         
         // Replace graph contents
         graph.clear();
@@ -8,6 +8,8 @@ export interface VizData {
     totalCrossFileEdges: number;
   };
   projectName: string;
+  /** Path prefixes currently collapsed into single nodes */
+  collapsed?: string[];
 }
 
 export interface VizFile {
@@ -16,6 +18,13 @@ export interface VizFile {
   symbolCount: number;
   incomingCount: number;
   outgoingCount: number;
+  /** Set on a node standing in for every file under a collapsed prefix */
+  collapsed?: {
+    prefix: string;
+    fileCount: number;
+    /** Edges between files inside the prefix, hidden while collapsed */
+    internalEdges: number;
+  };
 }
 
 export interface VizArc {
@@ -25,4 +34,6 @@ export interface VizArc {
   edgeKinds: string[];
   crossLanguage?: boolean;
   edgeType?: string; // 'rest-api' | 'subprocess' for cross-language edges
+  /** Number of file-level arcs merged into this one by collapsing */
+  aggregated?: number;
 }
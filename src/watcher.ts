@@ -1,5 +1,8 @@
 import chokidar, { FSWatcher } from 'chokidar';
 import { join } from 'path';
+import { logger } from './utils/log.js';
+
+const log = logger('watcher');
 
 export interface WatcherCallbacks {
   onFileChanged: (filePath: string) => void | Promise<void>;
@@ -8,7 +11,7 @@ export interface WatcherCallbacks {
 }
 
 export function watchProject(projectRoot: string, callbacks: WatcherCallbacks): FSWatcher {
-  console.error(`[Watcher] Creating watcher for: ${projectRoot}`);
+  log.debug(`Creating watcher for: ${projectRoot}`);
   
   // Watch the directory directly (glob patterns don't work reliably on all systems)
   // We'll filter by extension in the callbacks
@@ -43,7 +46,7 @@ export function watchProject(projectRoot: string, callbacks: WatcherCallbacks):
   
   const watcher = chokidar.watch(projectRoot, watcherOptions);
 
-  console.error('[Watcher] Attaching event listeners...');
+  log.debug('Attaching event listeners...');
 
   watcher.on('change', (absolutePath: string) => {
     // Only process TypeScript, JavaScript, Python, Go, Rust, C, and C# files
@@ -58,7 +61,7 @@ export function watchProject(projectRoot: string, callbacks: WatcherCallbacks):
     
     // Convert absolute path to relative path for consistency
     const relativePath = absolutePath.replace(projectRoot + '/', '');
-    console.error(`[Watcher] Change event: ${relativePath}`);
+    log.debug(`Change event: ${relativePath}`, { file: relativePath });
     callbacks.onFileChanged(relativePath);
   });
 
@@ -73,7 +76,7 @@ export function watchProject(projectRoot: string, callbacks: WatcherCallbacks):
     
     // Convert absolute path to relative path for consistency
     const relativePath = absolutePath.replace(projectRoot + '/', '');
-    console.error(`[Watcher] Add event: ${relativePath}`);
+    log.debug(`Add event: ${relativePath}`, { file: relativePath });
     callbacks.onFileAdded(relativePath);
   });
 
@@ -87,16 +90,16 @@ export function watchProject(projectRoot: string, callbacks: WatcherCallbacks):
     
     // Convert absolute path to relative path for consistency
     const relativePath = absolutePath.replace(projectRoot + '/', '');
-    console.error(`[Watcher] Unlink event: ${relativePath}`);
+    log.debug(`Unlink event: ${relativePath}`, { file: relativePath });
     callbacks.onFileDeleted(relativePath);
   });
 
   watcher.on('error', (error: Error) => {
-    console.error('[Watcher] Error:', error);
+    log.error('Watcher error', { error });
   });
 
   watcher.on('ready', () => {
-    console.error('[Watcher] Ready — watching for changes');
+    log.debug('Ready — watching for changes');
     // Log what we're actually watching
     const watched = watcher.getWatched();
     const dirs = Object.keys(watched);
@@ -123,7 +126,7 @@ export function watchProject(projectRoot: string, callbacks: WatcherCallbacks):
       ).length;
     }
     
-    console.error(`[Watcher] Watching ${fileCount} TypeScript/JavaScript/Python/Go/Rust/C/C++/C#/Java/Kotlin/PHP files in ${dirs.length} directories`);
+    log.info(`Watching ${fileCount} TypeScript/JavaScript/Python/Go/Rust/C/C++/C#/Java/Kotlin/PHP files in ${dirs.length} directories`, { files: fileCount, dirs: dirs.length });
   });
 
   return watcher;